@@ -76,6 +76,8 @@ func main() {
 	// Initialize repositories
 	apiKeyRepo := mongodb.NewAPIKeyRepository(mongoClient)
 	emissionRepo := mongodb.NewEmissionRepository(mongoClient)
+	eventRepo := mongodb.NewEventRepository(mongoClient)
+	distributionRepo := mongodb.NewDistributionRepository(mongoClient)
 
 	// Ensure indexes are created
 	if err := apiKeyRepo.EnsureIndexes(ctx); err != nil {
@@ -84,6 +86,12 @@ func main() {
 	if err := emissionRepo.EnsureIndexes(ctx); err != nil {
 		log.Printf("Warning: Failed to ensure emission indexes: %v", err)
 	}
+	if err := eventRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure event indexes: %v", err)
+	}
+	if err := distributionRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure distribution indexes: %v", err)
+	}
 
 	// Determine base URL for status URLs
 	baseURL := os.Getenv("BASE_URL")
@@ -93,13 +101,15 @@ func main() {
 
 	// Setup router with all dependencies
 	router := api.NewRouter(api.RouterConfig{
-		Config:       cfg,
-		MongoClient:  mongoClient,
-		RedisClient:  redisClient,
-		APIKeyRepo:   apiKeyRepo,
-		EmissionRepo: emissionRepo,
-		JobClient:    jobClient,
-		BaseURL:      baseURL,
+		Config:           cfg,
+		MongoClient:      mongoClient,
+		RedisClient:      redisClient,
+		APIKeyRepo:       apiKeyRepo,
+		EmissionRepo:     emissionRepo,
+		EventRepo:        eventRepo,
+		DistributionRepo: distributionRepo,
+		JobClient:        jobClient,
+		BaseURL:          baseURL,
 	})
 
 	// Create HTTP server
@@ -0,0 +1,107 @@
+// Package main provides the command that loads an approximate tax burden table
+// (totTrib) into MongoDB. Each load replaces the rates of the table's state; the
+// worker uses the newest rates from the next emission on.
+//
+// Usage:
+//
+//	taxburden -state SP -file TabelaIBPTaxSP25.2.A.csv
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/config"
+	"github.com/eduardo/nfse-nacional/internal/domain/taxburden"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+)
+
+const (
+	// loadTimeout is the maximum time to connect and load the table.
+	loadTimeout = 5 * time.Minute
+)
+
+func main() {
+	file := flag.String("file", "", "path of the IBPT table (CSV separated by semicolons)")
+	state := flag.String("state", "", "state abbreviation (UF) the table applies to, such as SP")
+	flag.Parse()
+
+	uf := strings.ToUpper(strings.TrimSpace(*state))
+	if *file == "" || !taxburden.IsState(uf) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	table, err := readTable(*file, uf)
+	if err != nil {
+		log.Fatalf("Failed to read tax burden table: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+	defer cancel()
+
+	mongoClient, err := mongodb.NewClient(ctx, mongodb.ClientOptions{
+		URI:          cfg.MongoDBURI,
+		DatabaseName: cfg.MongoDBDatabase,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	repo := mongodb.NewTaxBurdenRepository(mongoClient)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure tax burden indexes: %v", err)
+	}
+
+	if err := repo.ReplaceTable(ctx, table.State, table.Version, table.Source, newTaxBurdenRates(table.Rates)); err != nil {
+		log.Fatalf("Failed to load tax burden table: %v", err)
+	}
+
+	log.Printf("Loaded %d tax burden rates for %s (version %s, source %s)",
+		len(table.Rates), table.State, table.Version, table.Source)
+}
+
+// readTable parses the table file of a state.
+func readTable(path, state string) (*taxburden.Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	table, err := taxburden.ParseIBPT(f, state)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return table, nil
+}
+
+// newTaxBurdenRates converts the table rates to their stored form.
+func newTaxBurdenRates(rates []taxburden.Rate) []mongodb.TaxBurdenRate {
+	stored := make([]mongodb.TaxBurdenRate, len(rates))
+	for i, rate := range rates {
+		stored[i] = mongodb.TaxBurdenRate{
+			CodeType:      rate.CodeType,
+			Code:          rate.Code,
+			Description:   rate.Description,
+			FederalRate:   rate.FederalRate,
+			StateRate:     rate.StateRate,
+			MunicipalRate: rate.MunicipalRate,
+			ValidFrom:     rate.ValidFrom,
+			ValidUntil:    rate.ValidUntil,
+		}
+	}
+	return stored
+}
@@ -1,5 +1,6 @@
 // Package main provides the entry point for the NFS-e Nacional worker process.
-// It processes background jobs for emission processing and webhook delivery.
+// It processes background jobs for emission processing, event registration, DF-e
+// distribution sync and webhook delivery.
 package main
 
 import (
@@ -64,16 +65,28 @@ func main() {
 
 	// Initialize repositories
 	emissionRepo := mongodb.NewEmissionRepository(mongoClient)
+	eventRepo := mongodb.NewEventRepository(mongoClient)
 	webhookRepo := mongodb.NewWebhookRepository(mongoClient)
+	distributionRepo := mongodb.NewDistributionRepository(mongoClient)
 	apiKeyRepo := mongodb.NewAPIKeyRepository(mongoClient)
+	taxBurdenRepo := mongodb.NewTaxBurdenRepository(mongoClient)
 
 	// Ensure indexes are created
 	if err := emissionRepo.EnsureIndexes(ctx); err != nil {
 		log.Printf("Warning: Failed to ensure emission indexes: %v", err)
 	}
+	if err := eventRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure event indexes: %v", err)
+	}
 	if err := webhookRepo.EnsureIndexes(ctx); err != nil {
 		log.Printf("Warning: Failed to ensure webhook indexes: %v", err)
 	}
+	if err := distributionRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure distribution indexes: %v", err)
+	}
+	if err := taxBurdenRepo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure tax burden indexes: %v", err)
+	}
 
 	// Initialize SEFIN client (mock for development)
 	sefinClient := sefin.NewMockClient()
@@ -85,12 +98,64 @@ func main() {
 		MaxRetries: 3,
 	})
 
+	// Initialize job client for the distribution poller
+	jobClient, err := infraredis.NewJobClientFromURL(cfg.RedisURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize job client: %v", err)
+	}
+	defer jobClient.Close()
+
+	// Initialize Redis for the municipal parameters cache shared with the API
+	redisClient, err := initRedis(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize Redis: %v", err)
+	}
+	defer redisClient.Close()
+
+	parametersCache := sefin.NewParametersCache(sefin.ParametersCacheConfig{
+		Client:                   sefinClient,
+		Store:                    infraredis.NewCacheStore(infraredis.CacheStoreConfig{Client: redisClient}),
+		ConvenioTTL:              cfg.CacheConvenioTTL,
+		ServiceParametersTTL:     cfg.CacheServiceParametersTTL,
+		ContributorParametersTTL: cfg.CacheContributorParametersTTL,
+		StaleTTL:                 cfg.CacheStaleTTL,
+		NegativeTTL:              cfg.CacheNegativeTTL,
+	})
+
 	// Create emission processor
 	emissionProcessor := jobs.NewEmissionProcessor(jobs.EmissionProcessorConfig{
 		EmissionRepo:  emissionRepo,
 		WebhookRepo:   webhookRepo,
 		SefinClient:   sefinClient,
+		Parameters:    parametersCache,
+		TaxBurden:     taxBurdenRepo,
+		WebhookSender: webhookSender,
+	})
+
+	// Create event processor
+	eventProcessor := jobs.NewEventProcessor(jobs.EventProcessorConfig{
+		EventRepo:     eventRepo,
+		EmissionRepo:  emissionRepo,
+		WebhookRepo:   webhookRepo,
+		SefinClient:   sefinClient,
+		WebhookSender: webhookSender,
+	})
+
+	// Create distribution syncer
+	distributionSyncer := jobs.NewDistributionSyncer(jobs.DistributionSyncerConfig{
+		Repo:          distributionRepo,
+		Client:        sefinClient,
+		EmissionRepo:  emissionRepo,
+		WebhookRepo:   webhookRepo,
 		WebhookSender: webhookSender,
+		MaxBatches:    cfg.DistributionMaxBatches,
+		IdleBackoff:   cfg.DistributionIdleBackoff,
+	})
+
+	// Create distribution poller
+	distributionPoller := jobs.NewDistributionPoller(jobs.DistributionPollerConfig{
+		Repo:      distributionRepo,
+		JobClient: jobClient,
 	})
 
 	// Create webhook processor
@@ -106,14 +171,16 @@ func main() {
 		log.Fatalf("Failed to parse Redis URL: %v", err)
 	}
 
+	asynqRedisOpt := asynq.RedisClientOpt{
+		Addr:     redisOpts.Addr,
+		Password: redisOpts.Password,
+		DB:       redisOpts.DB,
+		Username: redisOpts.Username,
+	}
+
 	// Create Asynq server
 	srv := asynq.NewServer(
-		asynq.RedisClientOpt{
-			Addr:     redisOpts.Addr,
-			Password: redisOpts.Password,
-			DB:       redisOpts.DB,
-			Username: redisOpts.Username,
-		},
+		asynqRedisOpt,
 		asynq.Config{
 			Concurrency: cfg.WorkerConcurrency,
 			Queues: map[string]int{
@@ -138,8 +205,27 @@ func main() {
 
 	// Register handlers
 	mux.HandleFunc(jobs.TypeEmissionProcess, emissionProcessor.ProcessEmission)
+	mux.HandleFunc(jobs.TypeEventRegister, eventProcessor.ProcessEvent)
+	mux.HandleFunc(jobs.TypeDistributionSync, distributionSyncer.ProcessSync)
+	mux.HandleFunc(jobs.TypeDistributionPoll, distributionPoller.ProcessPoll)
 	mux.HandleFunc(jobs.TypeWebhookDelivery, webhookProcessor.ProcessWebhook)
 
+	// Create scheduler for recurring tasks
+	scheduler := asynq.NewScheduler(asynqRedisOpt, &asynq.SchedulerOpts{
+		Location: time.UTC,
+	})
+
+	// Poll ADN distribution. Unique keeps a single poll queued when several
+	// workers run the scheduler.
+	pollSpec := fmt.Sprintf("@every %s", cfg.DistributionPollInterval)
+	if _, err := scheduler.Register(pollSpec, jobs.NewDistributionPollTask(),
+		asynq.Queue(infraredis.QueueLow),
+		asynq.MaxRetry(0),
+		asynq.Unique(cfg.DistributionPollInterval),
+	); err != nil {
+		log.Fatalf("Failed to schedule distribution poll: %v", err)
+	}
+
 	// Initialize worker stats
 	stats := &workerStats{
 		startTime: time.Now(),
@@ -155,6 +241,12 @@ func main() {
 		close(serverDone)
 	}()
 
+	// Start scheduler
+	if err := scheduler.Start(); err != nil {
+		log.Fatalf("Scheduler failed to start: %v", err)
+	}
+	log.Printf("Distribution poll scheduled every %s", cfg.DistributionPollInterval)
+
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -174,7 +266,9 @@ func main() {
 	// Track shutdown start time
 	shutdownStart := time.Now()
 
-	// Phase 1: Stop accepting new jobs and wait for in-progress jobs
+	// Phase 1: Stop scheduling recurring tasks, then stop accepting new jobs
+	// and wait for in-progress jobs
+	scheduler.Shutdown()
 	logWorkerShutdownEvent("draining_jobs", nil)
 
 	// Create a channel to track shutdown completion
@@ -246,6 +340,17 @@ func logWorkerShutdownEvent(event string, data map[string]interface{}) {
 	log.Println(string(jsonBytes))
 }
 
+// initRedis initializes the Redis connection.
+func initRedis(ctx context.Context, cfg *config.Config) (*infraredis.Client, error) {
+	client, err := infraredis.NewClient(ctx, infraredis.ClientOptions{
+		URL: cfg.RedisURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis connection failed: %w", err)
+	}
+	return client, nil
+}
+
 // initMongoDB initializes the MongoDB connection.
 func initMongoDB(ctx context.Context, cfg *config.Config) (*mongodb.Client, error) {
 	client, err := mongodb.NewClient(ctx, mongodb.ClientOptions{
@@ -272,5 +377,6 @@ func logStartupInfo(cfg *config.Config) {
 	log.Printf("SEFIN Environment: %s", cfg.SEFINEnvironment)
 	log.Printf("Worker Concurrency: %d", cfg.WorkerConcurrency)
 	log.Printf("Max Retries: %d", cfg.WorkerMaxRetries)
+	log.Printf("Distribution Poll Interval: %s", cfg.DistributionPollInterval)
 	log.Println("=================================================")
 }
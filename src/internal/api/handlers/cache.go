@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/municipality"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+)
+
+// ParametersInvalidator removes cached municipal parameters.
+// This interface allows for easier testing by enabling mock implementations.
+type ParametersInvalidator interface {
+	Invalidate(ctx context.Context, resource, codigoMunicipio string) (int, error)
+}
+
+// CacheHandler handles the admin operations on the government reference data cache.
+type CacheHandler struct {
+	parameters ParametersInvalidator
+}
+
+// CacheHandlerConfig configures the cache handler.
+type CacheHandlerConfig struct {
+	// Parameters is the municipal parameters cache.
+	// Can be *sefin.ParametersCache or any type implementing ParametersInvalidator.
+	Parameters ParametersInvalidator
+}
+
+// NewCacheHandler creates a new cache handler.
+func NewCacheHandler(config CacheHandlerConfig) *CacheHandler {
+	return &CacheHandler{
+		parameters: config.Parameters,
+	}
+}
+
+// InvalidateParameters handles DELETE /admin/cache/parametros_municipais[/:codigo] requests.
+// It removes the cached municipal parameters of one municipality, or of every
+// municipality when no code is given, so the next lookup queries the government API.
+// The optional ?resource= query parameter limits the invalidation to one resource
+// (convenio, servico or contribuinte).
+func (h *CacheHandler) InvalidateParameters(c *gin.Context) {
+	codigo := c.Param("codigo")
+	if codigo != "" && !validation.IsValidMunicipalityCode(codigo) {
+		BadRequest(c, "Municipality code must be a 7-digit IBGE code")
+		return
+	}
+
+	resource := c.Query("resource")
+	deleted, err := h.parameters.Invalidate(c.Request.Context(), resource, codigo)
+	if err != nil {
+		if errors.Is(err, sefin.ErrUnknownCacheResource) {
+			BadRequest(c, fmt.Sprintf("Unknown cache resource '%s'. Valid values: %s, %s, %s",
+				resource, sefin.CacheResourceConvenio, sefin.CacheResourceServiceParameters, sefin.CacheResourceContributorParameters))
+			return
+		}
+		log.Printf("ERROR: Failed to invalidate municipal parameters cache: resource=%q municipality=%q error=%v", resource, codigo, err)
+		InternalError(c, "Failed to invalidate municipal parameters cache")
+		return
+	}
+
+	c.JSON(http.StatusOK, municipality.CacheInvalidationResponse{
+		Resource:        resource,
+		CodigoMunicipio: codigo,
+		Deleted:         deleted,
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/municipality"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+)
+
+// MockParametersInvalidator is a mock implementation of the ParametersInvalidator interface.
+type MockParametersInvalidator struct {
+	mock.Mock
+}
+
+// Invalidate mocks the Invalidate method.
+func (m *MockParametersInvalidator) Invalidate(ctx context.Context, resource, codigoMunicipio string) (int, error) {
+	args := m.Called(ctx, resource, codigoMunicipio)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCacheHandler_InvalidateParameters(t *testing.T) {
+	params := new(MockParametersInvalidator)
+	params.On("Invalidate", mock.Anything, "", "3550308").Return(3, nil)
+	params.On("Invalidate", mock.Anything, sefin.CacheResourceConvenio, "3550308").Return(1, nil)
+	params.On("Invalidate", mock.Anything, "", "").Return(42, nil)
+	params.On("Invalidate", mock.Anything, "aliquotas", "").Return(0, fmt.Errorf("%w: aliquotas", sefin.ErrUnknownCacheResource))
+	params.On("Invalidate", mock.Anything, "", "4106902").Return(0, errors.New("connection refused"))
+
+	handler := NewCacheHandler(CacheHandlerConfig{Parameters: params})
+	router := gin.New()
+	router.DELETE("/admin/cache/parametros_municipais", handler.InvalidateParameters)
+	router.DELETE("/admin/cache/parametros_municipais/:codigo", handler.InvalidateParameters)
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedDeleted int
+	}{
+		{name: "one municipality", path: "/3550308", expectedStatus: http.StatusOK, expectedDeleted: 3},
+		{name: "one resource of a municipality", path: "/3550308?resource=convenio", expectedStatus: http.StatusOK, expectedDeleted: 1},
+		{name: "every municipality", path: "", expectedStatus: http.StatusOK, expectedDeleted: 42},
+		{name: "unknown resource", path: "?resource=aliquotas", expectedStatus: http.StatusBadRequest},
+		{name: "invalid code", path: "/355030", expectedStatus: http.StatusBadRequest},
+		{name: "store failure", path: "/4106902", expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/admin/cache/parametros_municipais"+tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp municipality.CacheInvalidationResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedDeleted, resp.Deleted)
+		})
+	}
+
+	params.AssertNotCalled(t, "Invalidate", mock.Anything, mock.Anything, "355030")
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
+	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
+)
+
+// DistributionRepository defines the operations needed by DistributionHandler.
+// This interface allows for easier testing by enabling mock implementations.
+type DistributionRepository interface {
+	RegisterCursor(ctx context.Context, cursor *mongodb.DistributionCursor) error
+	FindCursorByCNPJ(ctx context.Context, cnpj string) (*mongodb.DistributionCursor, error)
+}
+
+// DistributionHandler handles the registration of CNPJs for ADN DF-e distribution.
+type DistributionHandler struct {
+	repo      DistributionRepository
+	jobClient TaskEnqueuer
+	validator *validation.DistributionValidator
+	baseURL   string
+}
+
+// DistributionHandlerConfig configures the distribution handler.
+type DistributionHandlerConfig struct {
+	// Repo is the repository for distribution cursors.
+	// Can be *mongodb.DistributionRepository or any type implementing DistributionRepository.
+	Repo DistributionRepository
+
+	// JobClient is the Asynq job client for enqueueing sync tasks.
+	JobClient TaskEnqueuer
+
+	// BaseURL is the base URL for constructing status URLs.
+	BaseURL string
+}
+
+// NewDistributionHandler creates a new distribution handler.
+func NewDistributionHandler(config DistributionHandlerConfig) *DistributionHandler {
+	return &DistributionHandler{
+		repo:      config.Repo,
+		jobClient: config.JobClient,
+		validator: validation.NewDistributionValidator(),
+		baseURL:   config.BaseURL,
+	}
+}
+
+// Register handles POST /v1/distribution/cnpjs requests.
+// It registers the CNPJ and its certificate for DF-e distribution and queues
+// the first sync. Registering the same CNPJ again replaces the certificate and
+// webhook while keeping the NSU cursor.
+func (h *DistributionHandler) Register(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	// Bind JSON request
+	var req distribution.RegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, fmt.Sprintf("Invalid JSON request body: %v", err))
+		return
+	}
+
+	// Validate request using domain validator
+	if validationErrors := h.validator.ValidateRegistration(&req); len(validationErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(validationErrors))
+		return
+	}
+
+	// Validate certificate (deep validation beyond basic format)
+	certValidationResult := validation.ValidateCertificateWithResult(req.Certificate)
+	if !certValidationResult.Valid {
+		ValidationFailed(c, convertDomainValidationErrors(certValidationResult.Errors))
+		return
+	}
+
+	// Determine webhook URL (request override or API key default)
+	webhookURL := req.WebhookURL
+	if webhookURL == "" {
+		webhookURL = apiKey.WebhookURL
+	}
+
+	cnpj := cnpjcpf.CleanCNPJ(req.CNPJ)
+	cursor := &mongodb.DistributionCursor{
+		CNPJ:        cnpj,
+		APIKeyID:    apiKey.ID,
+		Environment: apiKey.Environment,
+		Active:      true,
+		Certificate: &mongodb.CertificateData{
+			HasCertificate: true,
+			PFXBase64:      req.Certificate.PFXBase64,
+			Password:       req.Certificate.Password,
+		},
+		WebhookURL: webhookURL,
+	}
+
+	if err := h.repo.RegisterCursor(c.Request.Context(), cursor); err != nil {
+		if errors.Is(err, mongodb.ErrDistributionCursorExists) {
+			Conflict(c, "This CNPJ is already registered for distribution by another integrator")
+			return
+		}
+		InternalError(c, "Failed to register CNPJ for distribution")
+		return
+	}
+
+	h.enqueueSync(c.Request.Context(), cnpj)
+
+	c.JSON(http.StatusCreated, newCursorResponse(cursor))
+}
+
+// Get handles GET /v1/distribution/cnpjs/:cnpj requests.
+// It returns the distribution state (NSU cursor and last sync) of a registered CNPJ.
+func (h *DistributionHandler) Get(c *gin.Context) {
+	cursor, ok := h.findOwnedCursor(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newCursorResponse(cursor))
+}
+
+// Sync handles POST /v1/distribution/cnpjs/:cnpj/sync requests.
+// It queues a sync that downloads the documents after the stored NSU cursor.
+// The ADN requires waiting after it reports no more documents, so the sync is
+// refused until the next sync time of the CNPJ.
+func (h *DistributionHandler) Sync(c *gin.Context) {
+	cursor, ok := h.findOwnedCursor(c)
+	if !ok {
+		return
+	}
+
+	if !cursor.Active {
+		Conflict(c, "Distribution is not active for this CNPJ")
+		return
+	}
+
+	now := time.Now()
+	if cursor.IsBackingOff(now) {
+		retryAfter := int(cursor.NextSyncAt.Sub(now).Seconds()) + 1
+		TooManyRequests(c, fmt.Sprintf("No new documents in the ADN; the next sync is allowed at %s",
+			cursor.NextSyncAt.UTC().Format(time.RFC3339)), retryAfter)
+		return
+	}
+
+	h.enqueueSync(c.Request.Context(), cursor.CNPJ)
+
+	c.JSON(http.StatusAccepted, distribution.SyncAccepted{
+		CNPJ:      cursor.CNPJ,
+		Message:   "Distribution sync queued for processing",
+		StatusURL: h.buildStatusURL(cursor.CNPJ),
+	})
+}
+
+// findOwnedCursor loads the cursor of the CNPJ in the path, writing the error
+// response and returning false if it is invalid or not owned by the API key.
+func (h *DistributionHandler) findOwnedCursor(c *gin.Context) (*mongodb.DistributionCursor, bool) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return nil, false
+	}
+
+	cnpj := cnpjcpf.CleanCNPJ(c.Param("cnpj"))
+	if !cnpjcpf.ValidateCNPJ(cnpj) {
+		BadRequest(c, "Invalid CNPJ in the path")
+		return nil, false
+	}
+
+	cursor, err := h.repo.FindCursorByCNPJ(c.Request.Context(), cnpj)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrDistributionCursorNotFound) {
+			NotFound(c, "CNPJ is not registered for distribution")
+			return nil, false
+		}
+		InternalError(c, "Failed to retrieve distribution state")
+		return nil, false
+	}
+
+	// Verify ownership - return 404 instead of 403 to prevent information leakage
+	if cursor.APIKeyID != apiKey.ID {
+		NotFound(c, "CNPJ is not registered for distribution")
+		return nil, false
+	}
+
+	return cursor, true
+}
+
+// enqueueSync queues a distribution sync for the CNPJ. Failures are only logged,
+// since the registration is saved and the sync can be requested again. A sync
+// already queued for the CNPJ is kept.
+func (h *DistributionHandler) enqueueSync(ctx context.Context, cnpj string) {
+	task, err := jobs.NewDistributionSyncTask(cnpj)
+	if err != nil {
+		log.Printf("ERROR: Failed to create distribution sync task: cnpj=%s error=%v", cnpj, err)
+		return
+	}
+
+	_, err = h.jobClient.Enqueue(ctx, task, &infraredis.EnqueueOptions{
+		Queue:    infraredis.QueueLow,
+		MaxRetry: 3,
+		TaskID:   jobs.DistributionSyncTaskID(cnpj),
+	})
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		log.Printf("ERROR: Failed to enqueue distribution sync task: cnpj=%s error=%v", cnpj, err)
+	}
+}
+
+// buildStatusURL constructs the distribution state URL for a CNPJ.
+func (h *DistributionHandler) buildStatusURL(cnpj string) string {
+	return fmt.Sprintf("%s/v1/distribution/cnpjs/%s", h.baseURL, cnpj)
+}
+
+// newCursorResponse converts a stored cursor into its API representation.
+func newCursorResponse(cursor *mongodb.DistributionCursor) distribution.CursorResponse {
+	return distribution.CursorResponse{
+		CNPJ:       cursor.CNPJ,
+		Active:     cursor.Active,
+		LastNSU:    cursor.LastNSU,
+		LastSyncAt: cursor.LastSyncAt,
+		LastError:  cursor.LastError,
+		NextSyncAt: cursor.NextSyncAt,
+		CreatedAt:  cursor.CreatedAt,
+		UpdatedAt:  cursor.UpdatedAt,
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
+)
+
+// MockDistributionRepository is a mock implementation of the DistributionRepository interface.
+type MockDistributionRepository struct {
+	mock.Mock
+}
+
+// RegisterCursor mocks the RegisterCursor method.
+func (m *MockDistributionRepository) RegisterCursor(ctx context.Context, cursor *mongodb.DistributionCursor) error {
+	args := m.Called(ctx, cursor)
+	return args.Error(0)
+}
+
+// FindCursorByCNPJ mocks the FindCursorByCNPJ method.
+func (m *MockDistributionRepository) FindCursorByCNPJ(ctx context.Context, cnpj string) (*mongodb.DistributionCursor, error) {
+	args := m.Called(ctx, cnpj)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.DistributionCursor), args.Error(1)
+}
+
+// setupDistributionTestRouter creates a test router with the distribution handler and API key.
+func setupDistributionTestRouter(handler *DistributionHandler, apiKey *mongodb.APIKey) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if apiKey != nil {
+			setAPIKeyInContext(c, apiKey)
+		}
+		c.Next()
+	})
+
+	r.POST("/v1/distribution/cnpjs", handler.Register)
+	r.GET("/v1/distribution/cnpjs/:cnpj", handler.Get)
+	r.POST("/v1/distribution/cnpjs/:cnpj/sync", handler.Sync)
+
+	return r
+}
+
+// validDistributionBody returns a registration request body that passes request validation.
+func validDistributionBody() distribution.RegistrationRequest {
+	return distribution.RegistrationRequest{
+		CNPJ: "11222333000181",
+		Certificate: &emission.CertificateRequest{
+			PFXBase64: "dGVzdA==",
+			Password:  "secret",
+		},
+	}
+}
+
+func TestDistributionHandler_Register_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(req *distribution.RegistrationRequest)
+		expectedField string
+	}{
+		{
+			name: "invalid CNPJ",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.CNPJ = "11111111111111"
+			},
+			expectedField: "cnpj",
+		},
+		{
+			name: "missing certificate",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.Certificate = nil
+			},
+			expectedField: "certificate",
+		},
+		{
+			name:          "unparseable certificate",
+			modify:        func(req *distribution.RegistrationRequest) {},
+			expectedField: "certificate.pfx_base64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockDistributionRepository)
+			handler := NewDistributionHandler(DistributionHandlerConfig{Repo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+			router := setupDistributionTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+			reqBody := validDistributionBody()
+			tt.modify(&reqBody)
+			body, _ := json.Marshal(reqBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/distribution/cnpjs", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var problem ProblemDetails
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			fields := make([]string, 0, len(problem.Errors))
+			for _, e := range problem.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.Contains(t, fields, tt.expectedField)
+
+			mockRepo.AssertNotCalled(t, "RegisterCursor", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDistributionHandler_Get(t *testing.T) {
+	apiKeyID := primitive.NewObjectID()
+	syncedAt := time.Now().UTC()
+
+	mockRepo := new(MockDistributionRepository)
+	mockRepo.On("FindCursorByCNPJ", mock.Anything, "11222333000181").Return(&mongodb.DistributionCursor{
+		CNPJ:       "11222333000181",
+		APIKeyID:   apiKeyID,
+		Active:     true,
+		LastNSU:    42,
+		LastSyncAt: &syncedAt,
+	}, nil)
+	mockRepo.On("FindCursorByCNPJ", mock.Anything, "11444777000161").Return(&mongodb.DistributionCursor{
+		CNPJ:     "11444777000161",
+		APIKeyID: primitive.NewObjectID(),
+	}, nil)
+	mockRepo.On("FindCursorByCNPJ", mock.Anything, "45723174000110").Return(nil, mongodb.ErrDistributionCursorNotFound)
+
+	handler := NewDistributionHandler(DistributionHandlerConfig{Repo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+	router := setupDistributionTestRouter(handler, createTestAPIKey(apiKeyID))
+
+	tests := []struct {
+		name           string
+		cnpj           string
+		expectedStatus int
+	}{
+		{name: "registered CNPJ", cnpj: "11222333000181", expectedStatus: http.StatusOK},
+		{name: "CNPJ of another API key", cnpj: "11444777000161", expectedStatus: http.StatusNotFound},
+		{name: "unregistered CNPJ", cnpj: "45723174000110", expectedStatus: http.StatusNotFound},
+		{name: "invalid CNPJ", cnpj: "11111111111111", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/distribution/cnpjs/"+tt.cnpj, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp distribution.CursorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "11222333000181", resp.CNPJ)
+			assert.Equal(t, int64(42), resp.LastNSU)
+			assert.True(t, resp.Active)
+			assert.NotNil(t, resp.LastSyncAt)
+		})
+	}
+}
+
+func TestDistributionHandler_Sync(t *testing.T) {
+	apiKeyID := primitive.NewObjectID()
+
+	mockRepo := new(MockDistributionRepository)
+	mockRepo.On("FindCursorByCNPJ", mock.Anything, "11222333000181").Return(&mongodb.DistributionCursor{
+		CNPJ:     "11222333000181",
+		APIKeyID: apiKeyID,
+		Active:   true,
+	}, nil)
+	mockRepo.On("FindCursorByCNPJ", mock.Anything, "11444777000161").Return(&mongodb.DistributionCursor{
+		CNPJ:     "11444777000161",
+		APIKeyID: apiKeyID,
+		Active:   false,
+	}, nil)
+	nextSyncAt := time.Now().Add(30 * time.Minute)
+	mockRepo.On("FindCursorByCNPJ", mock.Anything, "45723174000110").Return(&mongodb.DistributionCursor{
+		CNPJ:       "45723174000110",
+		APIKeyID:   apiKeyID,
+		Active:     true,
+		NextSyncAt: &nextSyncAt,
+	}, nil)
+
+	mockJobs := new(MockTaskEnqueuer)
+	mockJobs.On("Enqueue", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	handler := NewDistributionHandler(DistributionHandlerConfig{
+		Repo:      mockRepo,
+		JobClient: mockJobs,
+		BaseURL:   "http://localhost:8080",
+	})
+	router := setupDistributionTestRouter(handler, createTestAPIKey(apiKeyID))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/distribution/cnpjs/11222333000181/sync", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var resp distribution.SyncAccepted
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "http://localhost:8080/v1/distribution/cnpjs/11222333000181", resp.StatusURL)
+
+	mockJobs.AssertNumberOfCalls(t, "Enqueue", 1)
+	task := mockJobs.Calls[0].Arguments.Get(1).(*asynq.Task)
+	payload, err := jobs.ParseDistributionSyncTask(task)
+	require.NoError(t, err)
+	assert.Equal(t, "11222333000181", payload.CNPJ)
+	opts := mockJobs.Calls[0].Arguments.Get(2).(*infraredis.EnqueueOptions)
+	assert.Equal(t, jobs.DistributionSyncTaskID("11222333000181"), opts.TaskID)
+
+	// Inactive registrations are not synced
+	req = httptest.NewRequest(http.MethodPost, "/v1/distribution/cnpjs/11444777000161/sync", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockJobs.AssertNumberOfCalls(t, "Enqueue", 1)
+
+	// CNPJs without new documents wait for the next sync time
+	req = httptest.NewRequest(http.MethodPost, "/v1/distribution/cnpjs/45723174000110/sync", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	mockJobs.AssertNumberOfCalls(t, "Enqueue", 1)
+}
@@ -54,6 +54,30 @@ func (m *mockSefinClient) QueryEvents(ctx context.Context, chaveAcesso string, c
 	return nil, nil
 }
 
+func (m *mockSefinClient) QueryEventsByType(ctx context.Context, chaveAcesso, tipoEvento string, cert *tls.Certificate) (*sefin.EventsQueryResult, error) {
+	return nil, nil
+}
+
+func (m *mockSefinClient) QueryEvent(ctx context.Context, chaveAcesso, tipoEvento string, numSeqEvento int, cert *tls.Certificate) (*sefin.EventData, error) {
+	return nil, nil
+}
+
+func (m *mockSefinClient) RegisterEvent(ctx context.Context, chaveAcesso, pedRegEventoXML string, cert *tls.Certificate) (*sefin.EventRegistrationResult, error) {
+	return nil, nil
+}
+
+func (m *mockSefinClient) QueryConvenio(ctx context.Context, codigoMunicipio string, cert *tls.Certificate) (*sefin.ConvenioResult, error) {
+	return nil, nil
+}
+
+func (m *mockSefinClient) QueryServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string, cert *tls.Certificate) (*sefin.ServiceParametersResult, error) {
+	return nil, nil
+}
+
+func (m *mockSefinClient) QueryContributorParameters(ctx context.Context, codigoMunicipio, documento string, cert *tls.Certificate) (*sefin.ContributorParametersResult, error) {
+	return nil, nil
+}
+
 func TestNewDPSHandler(t *testing.T) {
 	mockClient := &mockSefinClient{}
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,8 +15,10 @@ import (
 	"github.com/eduardo/nfse-nacional/internal/domain/validation"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
 	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
 	"github.com/eduardo/nfse-nacional/internal/jobs"
 	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
+	"github.com/eduardo/nfse-nacional/pkg/nbs"
 )
 
 // Context key for storing the authenticated API key.
@@ -41,6 +45,7 @@ type EmissionHandler struct {
 	emissionRepo *mongodb.EmissionRepository
 	jobClient    *infraredis.JobClient
 	validator    *validation.EmissionValidator
+	parameters   MunicipalParameters
 	baseURL      string
 }
 
@@ -54,6 +59,11 @@ type EmissionHandlerConfig struct {
 
 	// BaseURL is the base URL for constructing status URLs.
 	BaseURL string
+
+	// Parameters retrieves the municipal parameters used to reject emissions in
+	// municipalities not adhered to the national emitter.
+	// Optional: when nil, the municipality is only checked by SEFIN.
+	Parameters MunicipalParameters
 }
 
 // NewEmissionHandler creates a new emission handler.
@@ -62,6 +72,7 @@ func NewEmissionHandler(config EmissionHandlerConfig) *EmissionHandler {
 		emissionRepo: config.EmissionRepo,
 		jobClient:    config.JobClient,
 		validator:    validation.NewEmissionValidator(),
+		parameters:   config.Parameters,
 		baseURL:      config.BaseURL,
 	}
 }
@@ -101,6 +112,7 @@ func (h *EmissionHandler) Create(c *gin.Context) {
 
 	// Validate certificate if provided (deep validation beyond basic format)
 	var certValidationResult *validation.CertificateValidationResult
+	var certInfo *xmlsigner.CertificateInfo
 	if req.Certificate != nil {
 		certValidationResult = validation.ValidateCertificateWithResult(req.Certificate)
 		if !certValidationResult.Valid {
@@ -116,18 +128,76 @@ func (h *EmissionHandler) Create(c *gin.Context) {
 			ValidationFailed(c, handlerErrors)
 			return
 		}
+		certInfo = certValidationResult.CertificateInfo
+	}
+
+	// Check the certificate holder, the municipal agreement and the contributor parameters
+	if !checkBeforeQueue(c, h.parameters, h.validator, &req, certInfo) {
+		return
 	}
 
 	// Generate unique request ID
 	requestID := uuid.New().String()
 
+	// Create emission request record
+	emissionReq := newEmissionRecord(&req, apiKey, requestID)
+
+	// Add certificate if provided and validated
+	if req.Certificate != nil && certValidationResult != nil && certValidationResult.Valid {
+		emissionReq.Certificate = &mongodb.CertificateData{
+			HasCertificate: true,
+			PFXBase64:      req.Certificate.PFXBase64,
+			Password:       req.Certificate.Password,
+			// Note: SubjectCN, IssuerCN, and SerialNumber will be populated
+			// by the processor after signing is complete
+		}
+	}
+
+	// Save to database
+	if err := h.emissionRepo.Create(c.Request.Context(), emissionReq); err != nil {
+		InternalError(c, "Failed to create emission request")
+		return
+	}
+
+	// Enqueue processing job
+	task, err := jobs.NewEmissionTask(requestID)
+	if err != nil {
+		// Log error but don't fail - request is saved and can be retried
+		log.Printf("ERROR: Failed to create emission task: requestID=%s error=%v", requestID, err)
+	} else {
+		_, err = h.jobClient.Enqueue(c.Request.Context(), task, &infraredis.EnqueueOptions{
+			Queue:    infraredis.QueueDefault,
+			MaxRetry: 3,
+		})
+		if err != nil {
+			// Log error but don't fail - request is saved and can be processed later
+			log.Printf("ERROR: Failed to enqueue emission task: requestID=%s error=%v", requestID, err)
+		}
+	}
+
+	// Build status URL
+	statusURL := h.buildStatusURL(requestID)
+
+	// Return 202 Accepted
+	response := emission.EmissionAccepted{
+		RequestID: requestID,
+		Status:    emission.StatusPending,
+		Message:   "Request queued for processing",
+		StatusURL: statusURL,
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// newEmissionRecord maps a validated emission request to its database record.
+// The certificate is left for the caller, which decides whether it is required.
+func newEmissionRecord(req *emission.EmissionRequest, apiKey *mongodb.APIKey, requestID string) *mongodb.EmissionRequest {
 	// Determine webhook URL (request override or API key default)
 	webhookURL := req.WebhookURL
 	if webhookURL == "" {
 		webhookURL = apiKey.WebhookURL
 	}
 
-	// Create emission request record
 	emissionReq := &mongodb.EmissionRequest{
 		RequestID:   requestID,
 		APIKeyID:    apiKey.ID,
@@ -135,84 +205,199 @@ func (h *EmissionHandler) Create(c *gin.Context) {
 		Environment: apiKey.Environment,
 		Provider: mongodb.ProviderData{
 			CNPJ:                  cnpjcpf.CleanCNPJ(req.Provider.CNPJ),
+			CPF:                   cnpjcpf.CleanCPF(req.Provider.CPF),
 			TaxRegime:             req.Provider.TaxRegime,
 			Name:                  req.Provider.Name,
 			MunicipalRegistration: req.Provider.MunicipalRegistration,
+
+			SimplesCalculationRegime: req.Provider.SimplesCalculationRegime,
+			SpecialRegime:            req.Provider.SpecialRegime,
+			Address:                  newAddressData(req.Provider.Address),
 		},
 		Service: mongodb.ServiceData{
 			NationalCode:     req.Service.NationalCode,
 			Description:      req.Service.Description,
 			MunicipalityCode: req.Service.MunicipalityCode,
+			NBSCode:          nbs.Clean(req.Service.NBSCode),
+			MunicipalTaxCode: req.Service.MunicipalTaxCode,
+			CountryCode:      req.Service.CountryCode,
 		},
 		Values: mongodb.ValuesData{
 			ServiceValue:          req.Values.ServiceValue,
 			UnconditionalDiscount: req.Values.UnconditionalDiscount,
 			ConditionalDiscount:   req.Values.ConditionalDiscount,
 			Deductions:            req.Values.Deductions,
+			SimplesTaxRate:        req.Values.SimplesTaxRate,
 		},
 		DPS: mongodb.DPSData{
 			Series: req.DPS.Series,
 			Number: req.DPS.Number,
 		},
-		WebhookURL: webhookURL,
-		RetryCount: 0,
+		EmitterType:       req.EmitterType,
+		EmissionReason:    req.EmissionReason,
+		RejectedAccessKey: req.RejectedAccessKey,
+		WebhookURL:        webhookURL,
+		RetryCount:        0,
 	}
 
-	// Add taker if provided
-	if req.Taker != nil {
-		emissionReq.Taker = &mongodb.TakerData{
-			CNPJ: cnpjcpf.CleanCNPJ(req.Taker.CNPJ),
-			CPF:  cnpjcpf.CleanCPF(req.Taker.CPF),
-			NIF:  req.Taker.NIF,
-			Name: req.Taker.Name,
+	// Keep the claimed municipal benefit for audit
+	if req.Values.MunicipalBenefit != nil {
+		emissionReq.Values.MunicipalBenefitNumber = req.Values.MunicipalBenefit.Number
+		emissionReq.Values.MunicipalBenefitReductionPercentage = req.Values.MunicipalBenefit.ReductionPercentage
+		emissionReq.Values.MunicipalBenefitReductionValue = req.Values.MunicipalBenefit.ReductionValue
+	}
+
+	// Add the ISS treatment if provided
+	if iss := req.Values.ISS; iss != nil {
+		emissionReq.Values.ISS = &mongodb.ISSData{
+			Taxation:          iss.Taxation,
+			ResultCountryCode: iss.ResultCountryCode,
+			ImmunityType:      iss.ImmunityType,
+			Withholding:       iss.Withholding,
+		}
+		if susp := iss.Suspension; susp != nil {
+			emissionReq.Values.ISS.Suspension = &mongodb.ISSSuspensionData{
+				Type:          susp.Type,
+				ProcessNumber: susp.ProcessNumber,
+			}
 		}
 	}
 
-	// Add certificate if provided and validated
-	if req.Certificate != nil && certValidationResult != nil && certValidationResult.Valid {
-		emissionReq.Certificate = &mongodb.CertificateData{
-			HasCertificate: true,
-			PFXBase64:      req.Certificate.PFXBase64,
-			Password:       req.Certificate.Password,
-			// Note: SubjectCN, IssuerCN, and SerialNumber will be populated
-			// by the processor after signing is complete
+	// Add federal taxes if provided
+	if taxes := req.Values.FederalTaxes; taxes != nil {
+		emissionReq.Values.FederalTaxes = &mongodb.FederalTaxesData{
+			CPRate:   taxes.CPRate,
+			IRRFRate: taxes.IRRFRate,
+			CSLLRate: taxes.CSLLRate,
+		}
+		if pc := taxes.PisCofins; pc != nil {
+			emissionReq.Values.FederalTaxes.PisCofins = &mongodb.PisCofinsData{
+				CST:        pc.CST,
+				TaxBase:    pc.TaxBase,
+				PISRate:    pc.PISRate,
+				COFINSRate: pc.COFINSRate,
+				Withheld:   pc.Withheld,
+			}
 		}
 	}
 
-	// Save to database
-	if err := h.emissionRepo.Create(c.Request.Context(), emissionReq); err != nil {
-		InternalError(c, "Failed to create emission request")
-		return
+	// Add deduction documents if provided (issue dates were checked by the validator)
+	for _, doc := range req.Values.DeductionDocuments {
+		issueDate, _ := time.Parse("2006-01-02", doc.IssueDate)
+		data := mongodb.DeductionDocumentData{
+			NFSeKey:              doc.NFSeKey,
+			NFeKey:               doc.NFeKey,
+			FiscalDocumentNumber: strings.TrimSpace(doc.FiscalDocumentNumber),
+			DocumentNumber:       strings.TrimSpace(doc.DocumentNumber),
+			Type:                 doc.Type,
+			TypeDescription:      strings.TrimSpace(doc.TypeDescription),
+			IssueDate:            issueDate,
+			DeductibleValue:      doc.DeductibleValue,
+			DeductionValue:       doc.DeductionValue,
+		}
+		if nfse := doc.MunicipalNFSe; nfse != nil {
+			data.MunicipalNFSe = &mongodb.MunicipalNFSeData{
+				MunicipalityCode: nfse.MunicipalityCode,
+				Number:           nfse.Number,
+				VerificationCode: nfse.VerificationCode,
+			}
+		}
+		if invoice := doc.PaperInvoice; invoice != nil {
+			data.PaperInvoice = &mongodb.PaperInvoiceData{
+				Number: invoice.Number,
+				Model:  invoice.Model,
+				Series: invoice.Series,
+			}
+		}
+		emissionReq.Values.DeductionDocuments = append(emissionReq.Values.DeductionDocuments, data)
 	}
 
-	// Enqueue processing job
-	task, err := jobs.NewEmissionTask(requestID)
-	if err != nil {
-		// Log error but don't fail - request is saved and can be retried
-		log.Printf("ERROR: Failed to create emission task: requestID=%s error=%v", requestID, err)
-	} else {
-		_, err = h.jobClient.Enqueue(c.Request.Context(), task, &infraredis.EnqueueOptions{
-			Queue:    infraredis.QueueDefault,
-			MaxRetry: 3,
-		})
-		if err != nil {
-			// Log error but don't fail - request is saved and can be processed later
-			log.Printf("ERROR: Failed to enqueue emission task: requestID=%s error=%v", requestID, err)
+	// Add foreign trade information if provided
+	if trade := req.Service.ForeignTrade; trade != nil {
+		emissionReq.Service.ForeignTrade = &mongodb.ForeignTradeData{
+			ServiceMode:              trade.ServiceMode,
+			Relationship:             trade.Relationship,
+			CurrencyCode:             trade.CurrencyCode,
+			ForeignValue:             trade.ForeignValue,
+			ProviderSupportMechanism: trade.ProviderSupportMechanism,
+			TakerSupportMechanism:    trade.TakerSupportMechanism,
+			TemporaryGoods:           trade.TemporaryGoods,
+			ImportDeclaration:        strings.TrimSpace(trade.ImportDeclaration),
+			ExportRegistration:       strings.TrimSpace(trade.ExportRegistration),
+			ShareWithMDIC:            trade.ShareWithMDIC,
 		}
 	}
 
-	// Build status URL
-	statusURL := h.buildStatusURL(requestID)
+	// Add construction site if provided
+	if construction := req.Service.Construction; construction != nil {
+		emissionReq.Service.Construction = &mongodb.ConstructionData{
+			Code:                 strings.TrimSpace(construction.Code),
+			PropertyRegistration: strings.TrimSpace(construction.PropertyRegistration),
+			Address:              newAddressData(construction.Address),
+		}
+	}
 
-	// Return 202 Accepted
-	response := emission.EmissionAccepted{
-		RequestID: requestID,
-		Status:    emission.StatusPending,
-		Message:   "Request queued for processing",
-		StatusURL: statusURL,
+	// Add event if provided (dates were checked by the validator)
+	if event := req.Service.Event; event != nil {
+		startDate, _ := time.Parse("2006-01-02", event.StartDate)
+		endDate, _ := time.Parse("2006-01-02", event.EndDate)
+		emissionReq.Service.Event = &mongodb.EventData{
+			Name:      strings.TrimSpace(event.Name),
+			StartDate: startDate,
+			EndDate:   endDate,
+			ID:        strings.TrimSpace(event.ID),
+			Address:   newAddressData(event.Address),
+		}
 	}
 
-	c.JSON(http.StatusAccepted, response)
+	// Add taker if provided
+	if req.Taker != nil {
+		emissionReq.Taker = &mongodb.TakerData{
+			CNPJ:    cnpjcpf.CleanCNPJ(req.Taker.CNPJ),
+			CPF:     cnpjcpf.CleanCPF(req.Taker.CPF),
+			NIF:     req.Taker.NIF,
+			Name:    req.Taker.Name,
+			Phone:   req.Taker.Phone,
+			Email:   strings.TrimSpace(req.Taker.Email),
+			Address: newAddressData(req.Taker.Address),
+		}
+	}
+
+	// Add intermediary if provided
+	if req.Intermediary != nil {
+		emissionReq.Intermediary = &mongodb.IntermediaryData{
+			CNPJ:    cnpjcpf.CleanCNPJ(req.Intermediary.CNPJ),
+			CPF:     cnpjcpf.CleanCPF(req.Intermediary.CPF),
+			NIF:     req.Intermediary.NIF,
+			Name:    req.Intermediary.Name,
+			Phone:   req.Intermediary.Phone,
+			Email:   strings.TrimSpace(req.Intermediary.Email),
+			Address: newAddressData(req.Intermediary.Address),
+		}
+	}
+
+	return emissionReq
+}
+
+// newAddressData converts a provider, taker, intermediary, construction site or event address for storage.
+// Returns nil when no address was provided.
+func newAddressData(addr *emission.AddressRequest) *mongodb.AddressData {
+	if addr == nil {
+		return nil
+	}
+
+	return &mongodb.AddressData{
+		Street:           addr.Street,
+		Number:           addr.Number,
+		Complement:       addr.Complement,
+		Neighborhood:     addr.Neighborhood,
+		MunicipalityCode: addr.MunicipalityCode,
+		State:            strings.ToUpper(addr.State),
+		PostalCode:       addr.PostalCode,
+		City:             addr.City,
+		Region:           addr.Region,
+		CountryCode:      strings.ToUpper(addr.CountryCode),
+	}
 }
 
 // buildStatusURL constructs the status URL for a request.
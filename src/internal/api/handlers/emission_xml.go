@@ -24,6 +24,7 @@ type EmissionXMLHandler struct {
 	jobClient    *infraredis.JobClient
 	verifier     *xmlsigner.XMLVerifier
 	xsdValidator *validation.XSDValidator
+	parameters   MunicipalParameters
 	baseURL      string
 }
 
@@ -43,6 +44,11 @@ type EmissionXMLHandlerConfig struct {
 
 	// ValidateCertificate controls whether to validate signer certificate dates.
 	ValidateCertificate bool
+
+	// Parameters retrieves the municipal parameters used to reject emissions in
+	// municipalities not adhered to the national emitter.
+	// Optional: when nil, the municipality is only checked by SEFIN.
+	Parameters MunicipalParameters
 }
 
 // NewEmissionXMLHandler creates a new emission XML handler.
@@ -62,6 +68,7 @@ func NewEmissionXMLHandler(config EmissionXMLHandlerConfig) (*EmissionXMLHandler
 		jobClient:    config.JobClient,
 		verifier:     verifier,
 		xsdValidator: xsdValidator,
+		parameters:   config.Parameters,
 		baseURL:      config.BaseURL,
 	}, nil
 }
@@ -78,9 +85,10 @@ func NewEmissionXMLHandler(config EmissionXMLHandlerConfig) (*EmissionXMLHandler
 //  2. Verify the XML signature
 //  3. Validate the XML against XSD schema
 //  4. Extract information from the XML
-//  5. Create an emission request record with is_presigned=true
-//  6. Enqueue the emission job
-//  7. Return 202 Accepted with request details
+//  5. Check the issuing municipality adhered to the national emitter
+//  6. Create an emission request record with is_presigned=true
+//  7. Enqueue the emission job
+//  8. Return 202 Accepted with request details
 func (h *EmissionXMLHandler) Create(c *gin.Context) {
 	// Get API key from context (set by auth middleware)
 	apiKey := getAPIKeyFromContext(c)
@@ -233,10 +241,15 @@ func (h *EmissionXMLHandler) processPreSignedXML(
 		).WithDetail("Pre-signed XML validation failed").WithInstance(c.Request.URL.Path).WithErrors(errors)
 	}
 
-	// Step 5: Generate unique request ID
+	// Step 5: Check the issuing municipality (cLocEmi) accepts the national emitter
+	if problem := checkConvenio(c, h.parameters, preSignedInfo.MunicipalityCode); problem != nil {
+		return nil, problem
+	}
+
+	// Step 6: Generate unique request ID
 	requestID := uuid.New().String()
 
-	// Step 6: Determine environment
+	// Step 7: Determine environment
 	// Use the environment from the XML if valid, otherwise fall back to API key environment
 	environment := preSignedInfo.GetEnvironmentString()
 	if apiKey.Environment != "" && apiKey.Environment != environment {
@@ -245,7 +258,7 @@ func (h *EmissionXMLHandler) processPreSignedXML(
 			apiKey.Environment, environment, requestID)
 	}
 
-	// Step 7: Create emission request record
+	// Step 8: Create emission request record
 	emissionReq := &mongodb.EmissionRequest{
 		RequestID:    requestID,
 		APIKeyID:     apiKey.ID,
@@ -255,6 +268,7 @@ func (h *EmissionXMLHandler) processPreSignedXML(
 		PreSignedXML: xmlContent,
 		Provider: mongodb.ProviderData{
 			CNPJ: preSignedInfo.ProviderCNPJ,
+			CPF:  preSignedInfo.ProviderCPF,
 			Name: preSignedInfo.ProviderName,
 		},
 		Service: mongodb.ServiceData{
@@ -280,15 +294,7 @@ func (h *EmissionXMLHandler) processPreSignedXML(
 		},
 	}
 
-	// Handle CPF if CNPJ is not present
-	if preSignedInfo.ProviderCNPJ == "" && preSignedInfo.ProviderCPF != "" {
-		// Store CPF in a way that's compatible with the existing model
-		// For now, we'll store it in the CNPJ field with a prefix indicator
-		// In a real implementation, you might want to add a separate field
-		emissionReq.Provider.CNPJ = preSignedInfo.ProviderCPF
-	}
-
-	// Step 8: Save to database
+	// Step 9: Save to database
 	if err := h.emissionRepo.Create(c.Request.Context(), emissionReq); err != nil {
 		return nil, NewProblemDetails(
 			ProblemTypeInternalError,
@@ -297,7 +303,7 @@ func (h *EmissionXMLHandler) processPreSignedXML(
 		).WithDetail("Failed to create emission request").WithInstance(c.Request.URL.Path)
 	}
 
-	// Step 9: Enqueue processing job
+	// Step 10: Enqueue processing job
 	task, err := jobs.NewEmissionTask(requestID)
 	if err != nil {
 		// Log error but don't fail - request is saved and can be retried
@@ -313,7 +319,7 @@ func (h *EmissionXMLHandler) processPreSignedXML(
 		}
 	}
 
-	// Step 10: Build and return response
+	// Step 11: Build and return response
 	statusURL := h.buildStatusURL(requestID)
 
 	return &emission.PreSignedXMLResponse{
@@ -0,0 +1,588 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/domain/query"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
+	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
+)
+
+// EventRepository defines the operations needed by EventHandler.
+// This interface allows for easier testing by enabling mock implementations.
+type EventRepository interface {
+	Create(ctx context.Context, req *mongodb.EventRequest) error
+	FindByRequestID(ctx context.Context, requestID string) (*mongodb.EventRequest, error)
+	FindActiveByAccessKey(ctx context.Context, chaveAcesso, eventType string) (*mongodb.EventRequest, error)
+}
+
+// ReplacementRepository defines the emission operations needed by EventHandler
+// to emit a substitute NFS-e.
+type ReplacementRepository interface {
+	Create(ctx context.Context, req *mongodb.EmissionRequest) error
+	FindActiveReplacement(ctx context.Context, replacedAccessKey string) (*mongodb.EmissionRequest, error)
+}
+
+// TaskEnqueuer defines the job client operation needed to enqueue background tasks.
+type TaskEnqueuer interface {
+	Enqueue(ctx context.Context, task *asynq.Task, opts *infraredis.EnqueueOptions) (*asynq.TaskInfo, error)
+}
+
+// EventHandler handles NFS-e event requests (cancellation, replacement and manifestation).
+type EventHandler struct {
+	eventRepo         EventRepository
+	emissionRepo      ReplacementRepository
+	jobClient         TaskEnqueuer
+	validator         *validation.EventValidator
+	emissionValidator *validation.EmissionValidator
+	parameters        MunicipalParameters
+	baseURL           string
+}
+
+// EventHandlerConfig configures the event handler.
+type EventHandlerConfig struct {
+	// EventRepo is the repository for event requests.
+	// Can be *mongodb.EventRepository or any type implementing EventRepository.
+	EventRepo EventRepository
+
+	// EmissionRepo is the repository for emission requests, used by the replacement flow.
+	// Can be *mongodb.EmissionRepository or any type implementing ReplacementRepository.
+	EmissionRepo ReplacementRepository
+
+	// JobClient is the Asynq job client for enqueueing tasks.
+	JobClient TaskEnqueuer
+
+	// BaseURL is the base URL for constructing status URLs.
+	BaseURL string
+
+	// Parameters retrieves the municipal parameters used to reject substitute
+	// emissions in municipalities not adhered to the national emitter, or whose
+	// tax regime the municipality has no record of.
+	// Optional: when nil, the municipality is only checked by SEFIN.
+	Parameters MunicipalParameters
+}
+
+// NewEventHandler creates a new event handler.
+func NewEventHandler(config EventHandlerConfig) *EventHandler {
+	return &EventHandler{
+		eventRepo:         config.EventRepo,
+		emissionRepo:      config.EmissionRepo,
+		jobClient:         config.JobClient,
+		validator:         validation.NewEventValidator(),
+		emissionValidator: validation.NewEmissionValidator(),
+		parameters:        config.Parameters,
+		baseURL:           config.BaseURL,
+	}
+}
+
+// Cancel handles POST /v1/nfse/:chaveAcesso/cancel requests.
+// It validates the request, creates an event record for the e101101 event,
+// and enqueues a job that signs and registers it with the government API.
+func (h *EventHandler) Cancel(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	// Validate access key format
+	chaveAcesso := strings.TrimSpace(c.Param("chaveAcesso"))
+	if err := query.ValidateAccessKey(chaveAcesso); err != nil {
+		BadRequest(c, formatAccessKeyError(err))
+		return
+	}
+
+	// Bind JSON request
+	var req event.CancellationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, fmt.Sprintf("Invalid JSON request body: %v", err))
+		return
+	}
+
+	// Validate request using domain validator
+	if validationErrors := h.validator.ValidateCancellation(&req); len(validationErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(validationErrors))
+		return
+	}
+
+	// Validate certificate (deep validation beyond basic format)
+	certValidationResult := validation.ValidateCertificateWithResult(req.Certificate)
+	if !certValidationResult.Valid {
+		ValidationFailed(c, convertDomainValidationErrors(certValidationResult.Errors))
+		return
+	}
+
+	// The certificate must belong to the event author, as SEFIN would reject it otherwise
+	if holderErrors := validation.ValidateEventCertificateHolder(req.Author, certValidationResult.CertificateInfo); len(holderErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(holderErrors))
+		return
+	}
+
+	// Reject duplicate cancellations for the same NFS-e
+	existing, err := h.findActiveCancellation(c.Request.Context(), chaveAcesso)
+	if err != nil {
+		InternalError(c, "Failed to check existing event requests")
+		return
+	}
+	if existing != nil {
+		Conflict(c, fmt.Sprintf("A cancellation request for this NFS-e already exists (request_id: %s, status: %s)", existing.RequestID, existing.Status))
+		return
+	}
+
+	// A substitute NFS-e cancels the original when it is emitted
+	replacement, err := h.findActiveReplacement(c.Request.Context(), chaveAcesso)
+	if err != nil {
+		InternalError(c, "Failed to check existing replacement requests")
+		return
+	}
+	if replacement != nil {
+		Conflict(c, fmt.Sprintf("A replacement request for this NFS-e already exists (request_id: %s, status: %s)", replacement.RequestID, replacement.Status))
+		return
+	}
+
+	// Generate unique request ID
+	requestID := uuid.New().String()
+
+	// Determine webhook URL (request override or API key default)
+	webhookURL := req.WebhookURL
+	if webhookURL == "" {
+		webhookURL = apiKey.WebhookURL
+	}
+
+	// Create event request record
+	eventReq := &mongodb.EventRequest{
+		RequestID:   requestID,
+		APIKeyID:    apiKey.ID,
+		Status:      emission.StatusPending,
+		Environment: apiKey.Environment,
+		ChaveAcesso: chaveAcesso,
+		EventType:   event.TypeCancellation,
+		Author: mongodb.EventAuthorData{
+			CNPJ: cnpjcpf.CleanCNPJ(req.Author.CNPJ),
+			CPF:  cnpjcpf.CleanCPF(req.Author.CPF),
+		},
+		Cancellation: &mongodb.CancellationData{
+			ReasonCode: req.ReasonCode,
+			Reason:     strings.TrimSpace(req.Reason),
+		},
+		Certificate: &mongodb.CertificateData{
+			HasCertificate: true,
+			PFXBase64:      req.Certificate.PFXBase64,
+			Password:       req.Certificate.Password,
+		},
+		WebhookURL: webhookURL,
+		RetryCount: 0,
+	}
+
+	// Save to database
+	if err := h.eventRepo.Create(c.Request.Context(), eventReq); err != nil {
+		InternalError(c, "Failed to create event request")
+		return
+	}
+
+	// Enqueue processing job
+	task, err := jobs.NewEventTask(requestID)
+	if err != nil {
+		// Log error but don't fail - request is saved and can be retried
+		log.Printf("ERROR: Failed to create event task: requestID=%s error=%v", requestID, err)
+	} else {
+		_, err = h.jobClient.Enqueue(c.Request.Context(), task, &infraredis.EnqueueOptions{
+			Queue:    infraredis.QueueDefault,
+			MaxRetry: 3,
+		})
+		if err != nil {
+			// Log error but don't fail - request is saved and can be processed later
+			log.Printf("ERROR: Failed to enqueue event task: requestID=%s error=%v", requestID, err)
+		}
+	}
+
+	// Return 202 Accepted
+	response := event.EventAccepted{
+		RequestID: requestID,
+		Status:    emission.StatusPending,
+		Message:   "Cancellation request queued for processing",
+		StatusURL: h.buildStatusURL(requestID),
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// Replace handles POST /v1/nfse/:chaveAcesso/replace requests.
+// It validates the corrected emission payload and enqueues the emission of a
+// substitute NFS-e carrying the subst group. When the government API authorizes the
+// substitute, it registers the cancellation by substitution event (e105102) of the
+// original NFS-e itself. The returned status URL tracks the whole chain.
+func (h *EventHandler) Replace(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	// Validate access key format
+	chaveAcesso := strings.TrimSpace(c.Param("chaveAcesso"))
+	if err := query.ValidateAccessKey(chaveAcesso); err != nil {
+		BadRequest(c, formatAccessKeyError(err))
+		return
+	}
+
+	// Bind JSON request
+	var req event.ReplacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, fmt.Sprintf("Invalid JSON request body: %v", err))
+		return
+	}
+
+	// Validate request using domain validator
+	if validationErrors := h.validator.ValidateReplacement(&req); len(validationErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(validationErrors))
+		return
+	}
+
+	// Validate certificate (deep validation beyond basic format)
+	certValidationResult := validation.ValidateCertificateWithResult(req.Certificate)
+	if !certValidationResult.Valid {
+		ValidationFailed(c, convertDomainValidationErrors(certValidationResult.Errors))
+		return
+	}
+
+	// The substitute must pass the same checks as any emission before being queued
+	if !checkBeforeQueue(c, h.parameters, h.emissionValidator, &req.EmissionRequest, certValidationResult.CertificateInfo) {
+		return
+	}
+
+	// The original NFS-e must not be already cancelled or being cancelled
+	existing, err := h.findActiveCancellation(c.Request.Context(), chaveAcesso)
+	if err != nil {
+		InternalError(c, "Failed to check existing event requests")
+		return
+	}
+	if existing != nil {
+		Conflict(c, fmt.Sprintf("A cancellation request for this NFS-e already exists (request_id: %s, status: %s)", existing.RequestID, existing.Status))
+		return
+	}
+
+	// Only one substitute may be in flight for the same NFS-e
+	replacement, err := h.findActiveReplacement(c.Request.Context(), chaveAcesso)
+	if err != nil {
+		InternalError(c, "Failed to check existing replacement requests")
+		return
+	}
+	if replacement != nil {
+		Conflict(c, fmt.Sprintf("A replacement request for this NFS-e already exists (request_id: %s, status: %s)", replacement.RequestID, replacement.Status))
+		return
+	}
+
+	// Generate unique request ID
+	requestID := uuid.New().String()
+
+	// Create emission request record for the substitute NFS-e
+	emissionReq := newEmissionRecord(&req.EmissionRequest, apiKey, requestID)
+	emissionReq.Substitution = &mongodb.SubstitutionData{
+		ReplacedAccessKey: chaveAcesso,
+		ReasonCode:        req.Substitution.ReasonCode,
+		Reason:            strings.TrimSpace(req.Substitution.Reason),
+	}
+	emissionReq.Certificate = &mongodb.CertificateData{
+		HasCertificate: true,
+		PFXBase64:      req.Certificate.PFXBase64,
+		Password:       req.Certificate.Password,
+	}
+
+	// Save to database
+	if err := h.emissionRepo.Create(c.Request.Context(), emissionReq); err != nil {
+		InternalError(c, "Failed to create emission request")
+		return
+	}
+
+	// Enqueue processing job
+	task, err := jobs.NewEmissionTask(requestID)
+	if err != nil {
+		// Log error but don't fail - request is saved and can be retried
+		log.Printf("ERROR: Failed to create emission task: requestID=%s error=%v", requestID, err)
+	} else {
+		_, err = h.jobClient.Enqueue(c.Request.Context(), task, &infraredis.EnqueueOptions{
+			Queue:    infraredis.QueueDefault,
+			MaxRetry: 3,
+		})
+		if err != nil {
+			// Log error but don't fail - request is saved and can be processed later
+			log.Printf("ERROR: Failed to enqueue emission task: requestID=%s error=%v", requestID, err)
+		}
+	}
+
+	// Return 202 Accepted
+	response := emission.EmissionAccepted{
+		RequestID: requestID,
+		Status:    emission.StatusPending,
+		Message:   "Replacement request queued for processing",
+		StatusURL: h.buildEmissionStatusURL(requestID),
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// Manifest handles POST /v1/nfse/:chaveAcesso/manifest requests.
+// It registers a confirmation, rejection or rejection annulment for the NFS-e.
+// The event type is derived from the author's role and the requested action;
+// combinations a role is not allowed to register are rejected.
+func (h *EventHandler) Manifest(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	// Validate access key format
+	chaveAcesso := strings.TrimSpace(c.Param("chaveAcesso"))
+	if err := query.ValidateAccessKey(chaveAcesso); err != nil {
+		BadRequest(c, formatAccessKeyError(err))
+		return
+	}
+
+	// Bind JSON request
+	var req event.ManifestationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, fmt.Sprintf("Invalid JSON request body: %v", err))
+		return
+	}
+
+	// Validate request using domain validator
+	if validationErrors := h.validator.ValidateManifestation(&req); len(validationErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(validationErrors))
+		return
+	}
+
+	// Validate certificate (deep validation beyond basic format)
+	certValidationResult := validation.ValidateCertificateWithResult(req.Certificate)
+	if !certValidationResult.Valid {
+		ValidationFailed(c, convertDomainValidationErrors(certValidationResult.Errors))
+		return
+	}
+
+	// The certificate must belong to the event author, as SEFIN would reject it otherwise
+	if holderErrors := validation.ValidateEventCertificateHolder(req.Author, certValidationResult.CertificateInfo); len(holderErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(holderErrors))
+		return
+	}
+
+	eventType, _ := event.ManifestationEventType(req.Role, req.Action)
+
+	// Reject duplicate manifestations of the same type for the same NFS-e
+	existing, err := h.eventRepo.FindActiveByAccessKey(c.Request.Context(), chaveAcesso, eventType)
+	if err != nil && !errors.Is(err, mongodb.ErrEventRequestNotFound) {
+		InternalError(c, "Failed to check existing event requests")
+		return
+	}
+	if existing != nil {
+		Conflict(c, fmt.Sprintf("A %s request for this NFS-e already exists (request_id: %s, status: %s)", eventType, existing.RequestID, existing.Status))
+		return
+	}
+
+	// Generate unique request ID
+	requestID := uuid.New().String()
+
+	// Determine webhook URL (request override or API key default)
+	webhookURL := req.WebhookURL
+	if webhookURL == "" {
+		webhookURL = apiKey.WebhookURL
+	}
+
+	// Create event request record
+	eventReq := &mongodb.EventRequest{
+		RequestID:   requestID,
+		APIKeyID:    apiKey.ID,
+		Status:      emission.StatusPending,
+		Environment: apiKey.Environment,
+		ChaveAcesso: chaveAcesso,
+		EventType:   eventType,
+		Author: mongodb.EventAuthorData{
+			CNPJ: cnpjcpf.CleanCNPJ(req.Author.CNPJ),
+			CPF:  cnpjcpf.CleanCPF(req.Author.CPF),
+		},
+		Manifestation: &mongodb.ManifestationData{
+			Role:             req.Role,
+			Action:           req.Action,
+			ReasonCode:       req.ReasonCode,
+			Reason:           strings.TrimSpace(req.Reason),
+			TaxAgentCPF:      cnpjcpf.CleanCPF(req.TaxAgentCPF),
+			RejectionEventID: strings.TrimSpace(req.RejectionEventID),
+		},
+		Certificate: &mongodb.CertificateData{
+			HasCertificate: true,
+			PFXBase64:      req.Certificate.PFXBase64,
+			Password:       req.Certificate.Password,
+		},
+		WebhookURL: webhookURL,
+		RetryCount: 0,
+	}
+
+	// Save to database
+	if err := h.eventRepo.Create(c.Request.Context(), eventReq); err != nil {
+		InternalError(c, "Failed to create event request")
+		return
+	}
+
+	// Enqueue processing job
+	task, err := jobs.NewEventTask(requestID)
+	if err != nil {
+		// Log error but don't fail - request is saved and can be retried
+		log.Printf("ERROR: Failed to create event task: requestID=%s error=%v", requestID, err)
+	} else {
+		_, err = h.jobClient.Enqueue(c.Request.Context(), task, &infraredis.EnqueueOptions{
+			Queue:    infraredis.QueueDefault,
+			MaxRetry: 3,
+		})
+		if err != nil {
+			// Log error but don't fail - request is saved and can be processed later
+			log.Printf("ERROR: Failed to enqueue event task: requestID=%s error=%v", requestID, err)
+		}
+	}
+
+	// Return 202 Accepted
+	response := event.EventAccepted{
+		RequestID: requestID,
+		Status:    emission.StatusPending,
+		Message:   fmt.Sprintf("Manifestation request (%s) queued for processing", eventType),
+		StatusURL: h.buildStatusURL(requestID),
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// GetStatus handles GET /v1/events/:requestId requests.
+// It returns the current status of an event request.
+func (h *EventHandler) GetStatus(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	requestID := c.Param("requestId")
+	if requestID == "" {
+		BadRequest(c, "Request ID is required in the path")
+		return
+	}
+
+	eventReq, err := h.eventRepo.FindByRequestID(c.Request.Context(), requestID)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrEventRequestNotFound) {
+			NotFound(c, "Event request not found")
+			return
+		}
+		InternalError(c, "Failed to retrieve event request")
+		return
+	}
+
+	// Verify ownership - return 404 instead of 403 to prevent information leakage
+	if eventReq.APIKeyID != apiKey.ID {
+		NotFound(c, "Event request not found")
+		return
+	}
+
+	response := event.StatusResponse{
+		RequestID:   eventReq.RequestID,
+		Status:      eventReq.Status,
+		ChaveAcesso: eventReq.ChaveAcesso,
+		EventType:   eventReq.EventType,
+		CreatedAt:   eventReq.CreatedAt,
+		UpdatedAt:   eventReq.UpdatedAt,
+		ProcessedAt: eventReq.ProcessedAt,
+	}
+
+	// Add manifestation details for confirmation, rejection and annulment events
+	if eventReq.Manifestation != nil {
+		response.Manifestation = jobs.NewManifestationDTO(eventReq.Manifestation)
+	}
+
+	// Add result if successful
+	if eventReq.Status == emission.StatusSuccess && eventReq.Result != nil {
+		response.Result = jobs.NewEventResultDTO(eventReq.Result)
+	}
+
+	// Add error if failed
+	if eventReq.Status == emission.StatusFailed && eventReq.Rejection != nil {
+		response.Error = &emission.EmissionErrorDTO{
+			Code:           eventReq.Rejection.Code,
+			Message:        eventReq.Rejection.Message,
+			GovernmentCode: eventReq.Rejection.GovernmentCode,
+			Details:        eventReq.Rejection.Details,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// buildStatusURL constructs the status URL for an event request.
+func (h *EventHandler) buildStatusURL(requestID string) string {
+	if h.baseURL != "" {
+		return fmt.Sprintf("%s/v1/events/%s", h.baseURL, requestID)
+	}
+	return fmt.Sprintf("/v1/events/%s", requestID)
+}
+
+// buildEmissionStatusURL constructs the status URL for a substitute emission request.
+func (h *EventHandler) buildEmissionStatusURL(requestID string) string {
+	if h.baseURL != "" {
+		return fmt.Sprintf("%s/v1/nfse/status/%s", h.baseURL, requestID)
+	}
+	return fmt.Sprintf("/v1/nfse/status/%s", requestID)
+}
+
+// findActiveCancellation returns the pending or successful cancellation request
+// (e101101) for an NFS-e, or nil if there is none.
+func (h *EventHandler) findActiveCancellation(ctx context.Context, chaveAcesso string) (*mongodb.EventRequest, error) {
+	existing, err := h.eventRepo.FindActiveByAccessKey(ctx, chaveAcesso, event.TypeCancellation)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrEventRequestNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return existing, nil
+}
+
+// findActiveReplacement returns the pending, processing or successful substitute
+// emission for an NFS-e, or nil if there is none.
+func (h *EventHandler) findActiveReplacement(ctx context.Context, chaveAcesso string) (*mongodb.EmissionRequest, error) {
+	replacement, err := h.emissionRepo.FindActiveReplacement(ctx, chaveAcesso)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrEmissionRequestNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return replacement, nil
+}
+
+// convertDomainValidationErrors converts domain validation errors to handler errors.
+func convertDomainValidationErrors(errs []validation.ValidationError) []ValidationError {
+	handlerErrors := make([]ValidationError, len(errs))
+	for i, err := range errs {
+		handlerErrors[i] = ValidationError{
+			Field:   err.Field,
+			Code:    err.Code,
+			Message: err.Message,
+		}
+	}
+	return handlerErrors
+}
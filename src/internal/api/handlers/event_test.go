@@ -0,0 +1,571 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
+)
+
+// MockEventRepository is a mock implementation of the EventRepository interface.
+type MockEventRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method.
+func (m *MockEventRepository) Create(ctx context.Context, req *mongodb.EventRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+// FindByRequestID mocks the FindByRequestID method.
+func (m *MockEventRepository) FindByRequestID(ctx context.Context, requestID string) (*mongodb.EventRequest, error) {
+	args := m.Called(ctx, requestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.EventRequest), args.Error(1)
+}
+
+// FindActiveByAccessKey mocks the FindActiveByAccessKey method.
+func (m *MockEventRepository) FindActiveByAccessKey(ctx context.Context, chaveAcesso, eventType string) (*mongodb.EventRequest, error) {
+	args := m.Called(ctx, chaveAcesso, eventType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.EventRequest), args.Error(1)
+}
+
+// MockReplacementRepository is a mock implementation of the ReplacementRepository interface.
+type MockReplacementRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method.
+func (m *MockReplacementRepository) Create(ctx context.Context, req *mongodb.EmissionRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+// FindActiveReplacement mocks the FindActiveReplacement method.
+func (m *MockReplacementRepository) FindActiveReplacement(ctx context.Context, replacedAccessKey string) (*mongodb.EmissionRequest, error) {
+	args := m.Called(ctx, replacedAccessKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.EmissionRequest), args.Error(1)
+}
+
+// MockTaskEnqueuer is a mock implementation of the TaskEnqueuer interface.
+type MockTaskEnqueuer struct {
+	mock.Mock
+}
+
+// Enqueue mocks the Enqueue method.
+func (m *MockTaskEnqueuer) Enqueue(ctx context.Context, task *asynq.Task, opts *infraredis.EnqueueOptions) (*asynq.TaskInfo, error) {
+	args := m.Called(ctx, task, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*asynq.TaskInfo), args.Error(1)
+}
+
+// setupEventTestRouter creates a test router with the event handler and API key.
+func setupEventTestRouter(handler *EventHandler, apiKey *mongodb.APIKey) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if apiKey != nil {
+			setAPIKeyInContext(c, apiKey)
+		}
+		c.Next()
+	})
+
+	r.POST("/v1/nfse/:chaveAcesso/cancel", handler.Cancel)
+	r.POST("/v1/nfse/:chaveAcesso/replace", handler.Replace)
+	r.POST("/v1/nfse/:chaveAcesso/manifest", handler.Manifest)
+	r.GET("/v1/events/:requestId", handler.GetStatus)
+
+	return r
+}
+
+// validCancellationBody returns a cancellation request body that passes request validation.
+func validCancellationBody() event.CancellationRequest {
+	return event.CancellationRequest{
+		Author:     event.AuthorRequest{CNPJ: "11222333000181"},
+		ReasonCode: event.CancellationReasonEmissionError,
+		Reason:     "Erro no valor do servico informado",
+		Certificate: &emission.CertificateRequest{
+			PFXBase64: "dGVzdA==",
+			Password:  "secret",
+		},
+	}
+}
+
+// validReplacementBody returns a replacement request body that passes request validation.
+func validReplacementBody() event.ReplacementRequest {
+	return event.ReplacementRequest{
+		EmissionRequest: emission.EmissionRequest{
+			Provider: emission.ProviderRequest{
+				CNPJ:      "11222333000181",
+				TaxRegime: "me_epp",
+				Name:      "Empresa Teste LTDA",
+			},
+			Service: emission.ServiceRequest{
+				NationalCode:     "010101",
+				Description:      "Servico de desenvolvimento de software",
+				MunicipalityCode: "3550308",
+			},
+			Values: emission.ValuesRequest{ServiceValue: 1000.00},
+			DPS:    emission.DPSRequest{Series: "00001", Number: "2"},
+			Certificate: &emission.CertificateRequest{
+				PFXBase64: "dGVzdA==",
+				Password:  "secret",
+			},
+		},
+		Substitution: event.SubstitutionRequest{
+			ReasonCode: event.SubstitutionReasonOther,
+			Reason:     "Correcao da descricao do servico",
+		},
+	}
+}
+
+func TestEventHandler_Cancel_InvalidAccessKey(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+	router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+	body, _ := json.Marshal(validCancellationBody())
+	req := httptest.NewRequest(http.MethodPost, "/v1/nfse/INVALID/cancel", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEventHandler_Cancel_InvalidJSON(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+	router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/nfse/"+validAccessKey()+"/cancel", bytes.NewBufferString("{invalid"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEventHandler_Cancel_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(req *event.CancellationRequest)
+		expectedField string
+	}{
+		{
+			name: "missing author",
+			modify: func(req *event.CancellationRequest) {
+				req.Author = event.AuthorRequest{}
+			},
+			expectedField: "author",
+		},
+		{
+			name: "invalid reason code",
+			modify: func(req *event.CancellationRequest) {
+				req.ReasonCode = 5
+			},
+			expectedField: "reason_code",
+		},
+		{
+			name: "reason too short",
+			modify: func(req *event.CancellationRequest) {
+				req.Reason = "curto"
+			},
+			expectedField: "reason",
+		},
+		{
+			name: "missing certificate",
+			modify: func(req *event.CancellationRequest) {
+				req.Certificate = nil
+			},
+			expectedField: "certificate",
+		},
+		{
+			name:          "unparseable certificate",
+			modify:        func(req *event.CancellationRequest) {},
+			expectedField: "certificate.pfx_base64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockEventRepository)
+			handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+			router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+			reqBody := validCancellationBody()
+			tt.modify(&reqBody)
+			body, _ := json.Marshal(reqBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/nfse/"+validAccessKey()+"/cancel", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var problem ProblemDetails
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.NotEmpty(t, problem.Errors)
+			fields := make([]string, 0, len(problem.Errors))
+			for _, e := range problem.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.Contains(t, fields, tt.expectedField)
+
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEventHandler_Replace_InvalidAccessKey(t *testing.T) {
+	mockEmissionRepo := new(MockReplacementRepository)
+	handler := NewEventHandler(EventHandlerConfig{
+		EventRepo:    new(MockEventRepository),
+		EmissionRepo: mockEmissionRepo,
+		JobClient:    new(MockTaskEnqueuer),
+	})
+	router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+	body, _ := json.Marshal(validReplacementBody())
+	req := httptest.NewRequest(http.MethodPost, "/v1/nfse/INVALID/replace", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockEmissionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEventHandler_Replace_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(req *event.ReplacementRequest)
+		expectedField string
+	}{
+		{
+			name: "missing certificate",
+			modify: func(req *event.ReplacementRequest) {
+				req.Certificate = nil
+			},
+			expectedField: "certificate",
+		},
+		{
+			name: "invalid reason code",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution.ReasonCode = "06"
+			},
+			expectedField: "substitution.reason_code",
+		},
+		{
+			name: "missing reason for other motive",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution.Reason = ""
+			},
+			expectedField: "substitution.reason",
+		},
+		{
+			name: "invalid emission payload",
+			modify: func(req *event.ReplacementRequest) {
+				req.Provider.CNPJ = "11111111111111"
+			},
+			expectedField: "provider.cnpj",
+		},
+		{
+			name:          "unparseable certificate",
+			modify:        func(req *event.ReplacementRequest) {},
+			expectedField: "certificate.pfx_base64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEmissionRepo := new(MockReplacementRepository)
+			handler := NewEventHandler(EventHandlerConfig{
+				EventRepo:    new(MockEventRepository),
+				EmissionRepo: mockEmissionRepo,
+				JobClient:    new(MockTaskEnqueuer),
+			})
+			router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+			reqBody := validReplacementBody()
+			tt.modify(&reqBody)
+			body, _ := json.Marshal(reqBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/nfse/"+validAccessKey()+"/replace", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var problem ProblemDetails
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.NotEmpty(t, problem.Errors)
+			fields := make([]string, 0, len(problem.Errors))
+			for _, e := range problem.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.Contains(t, fields, tt.expectedField)
+
+			mockEmissionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEventHandler_Manifest_InvalidAccessKey(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+	router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+	body, _ := json.Marshal(event.ManifestationRequest{Role: event.RoleTaker, Action: event.ActionConfirm})
+	req := httptest.NewRequest(http.MethodPost, "/v1/nfse/INVALID/manifest", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEventHandler_Manifest_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(req *event.ManifestationRequest)
+		expectedField string
+	}{
+		{
+			name: "role not allowed to perform action",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = event.RoleMunicipality
+			},
+			expectedField: "action",
+		},
+		{
+			name: "rejection without reason code",
+			modify: func(req *event.ManifestationRequest) {
+				req.Action = event.ActionReject
+			},
+			expectedField: "reason_code",
+		},
+		{
+			name: "missing author",
+			modify: func(req *event.ManifestationRequest) {
+				req.Author = event.AuthorRequest{}
+			},
+			expectedField: "author",
+		},
+		{
+			name:          "unparseable certificate",
+			modify:        func(req *event.ManifestationRequest) {},
+			expectedField: "certificate.pfx_base64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockEventRepository)
+			handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+			router := setupEventTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+			reqBody := event.ManifestationRequest{
+				Role:   event.RoleTaker,
+				Action: event.ActionConfirm,
+				Author: event.AuthorRequest{CNPJ: "11222333000181"},
+				Certificate: &emission.CertificateRequest{
+					PFXBase64: "dGVzdA==",
+					Password:  "secret",
+				},
+			}
+			tt.modify(&reqBody)
+			body, _ := json.Marshal(reqBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/nfse/"+validAccessKey()+"/manifest", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var problem ProblemDetails
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.NotEmpty(t, problem.Errors)
+			fields := make([]string, 0, len(problem.Errors))
+			for _, e := range problem.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.Contains(t, fields, tt.expectedField)
+
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEventHandler_FindActiveCancellation(t *testing.T) {
+	active := &mongodb.EventRequest{RequestID: "evt-cancel", Status: emission.StatusPending}
+
+	mockRepo := new(MockEventRepository)
+	mockRepo.On("FindActiveByAccessKey", mock.Anything, validAccessKey(), event.TypeCancellation).
+		Return(active, nil).Once()
+	mockRepo.On("FindActiveByAccessKey", mock.Anything, validAccessKey(), event.TypeCancellation).
+		Return(nil, mongodb.ErrEventRequestNotFound).Once()
+
+	handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo})
+
+	existing, err := handler.findActiveCancellation(context.Background(), validAccessKey())
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "evt-cancel", existing.RequestID)
+
+	existing, err = handler.findActiveCancellation(context.Background(), validAccessKey())
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestEventHandler_GetStatus(t *testing.T) {
+	apiKeyID := primitive.NewObjectID()
+	now := time.Now().UTC()
+	registeredAt := now.Add(-time.Minute)
+
+	successReq := &mongodb.EventRequest{
+		RequestID:   "req-success",
+		APIKeyID:    apiKeyID,
+		Status:      emission.StatusSuccess,
+		ChaveAcesso: validAccessKey(),
+		EventType:   event.TypeCancellation,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ProcessedAt: &now,
+		Result: &mongodb.EventResult{
+			EventType:    event.TypeCancellation,
+			Sequence:     1,
+			RegisteredAt: registeredAt,
+			EventXML:     "<evento/>",
+		},
+	}
+	failedReq := &mongodb.EventRequest{
+		RequestID:   "req-failed",
+		APIKeyID:    apiKeyID,
+		Status:      emission.StatusFailed,
+		ChaveAcesso: validAccessKey(),
+		EventType:   event.TypeCancellation,
+		Rejection: &mongodb.RejectionInfo{
+			Code:           "SEFIN_REJECTION",
+			Message:        "NFS-e already cancelled",
+			GovernmentCode: "E002",
+		},
+	}
+	manifestReq := &mongodb.EventRequest{
+		RequestID:   "req-manifest",
+		APIKeyID:    apiKeyID,
+		Status:      emission.StatusPending,
+		ChaveAcesso: validAccessKey(),
+		EventType:   event.TypeTakerRejection,
+		Manifestation: &mongodb.ManifestationData{
+			Role:       event.RoleTaker,
+			Action:     event.ActionReject,
+			ReasonCode: event.RejectionReasonDuplicate,
+		},
+	}
+	otherOwnerReq := &mongodb.EventRequest{
+		RequestID: "req-other",
+		APIKeyID:  primitive.NewObjectID(),
+		Status:    emission.StatusPending,
+	}
+
+	mockRepo := new(MockEventRepository)
+	mockRepo.On("FindByRequestID", mock.Anything, "req-success").Return(successReq, nil)
+	mockRepo.On("FindByRequestID", mock.Anything, "req-failed").Return(failedReq, nil)
+	mockRepo.On("FindByRequestID", mock.Anything, "req-manifest").Return(manifestReq, nil)
+	mockRepo.On("FindByRequestID", mock.Anything, "req-other").Return(otherOwnerReq, nil)
+	mockRepo.On("FindByRequestID", mock.Anything, "req-missing").Return(nil, mongodb.ErrEventRequestNotFound)
+
+	handler := NewEventHandler(EventHandlerConfig{EventRepo: mockRepo, JobClient: new(MockTaskEnqueuer)})
+	router := setupEventTestRouter(handler, createTestAPIKey(apiKeyID))
+
+	t.Run("success includes result", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/events/req-success", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp event.StatusResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, emission.StatusSuccess, resp.Status)
+		require.NotNil(t, resp.Result)
+		assert.Equal(t, event.TypeCancellation, resp.Result.EventType)
+		assert.Equal(t, 1, resp.Result.Sequence)
+		assert.Nil(t, resp.Error)
+	})
+
+	t.Run("failed includes error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/events/req-failed", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp event.StatusResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, "E002", resp.Error.GovernmentCode)
+		assert.Nil(t, resp.Result)
+	})
+
+	t.Run("manifestation includes role and action", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/events/req-manifest", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp event.StatusResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, event.TypeTakerRejection, resp.EventType)
+		require.NotNil(t, resp.Manifestation)
+		assert.Equal(t, event.RoleTaker, resp.Manifestation.Role)
+		assert.Equal(t, event.ActionReject, resp.Manifestation.Action)
+		assert.Equal(t, event.RejectionReasonDuplicate, resp.Manifestation.ReasonCode)
+	})
+
+	t.Run("other owner returns not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/events/req-other", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("missing returns not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/events/req-missing", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestEventHandler_BuildStatusURL(t *testing.T) {
+	handler := NewEventHandler(EventHandlerConfig{BaseURL: "https://api.example.com"})
+	assert.Equal(t, "https://api.example.com/v1/events/abc", handler.buildStatusURL("abc"))
+
+	handler = NewEventHandler(EventHandlerConfig{})
+	assert.Equal(t, "/v1/events/abc", handler.buildStatusURL("abc"))
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/domain/query"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+)
+
+// InboxRepository defines the operations needed by InboxHandler.
+// This interface allows for easier testing by enabling mock implementations.
+type InboxRepository interface {
+	FindInbox(ctx context.Context, filter mongodb.InboxFilter, params mongodb.PaginationParams) (*mongodb.InboxResult, error)
+	FindInboxNFSe(ctx context.Context, apiKeyID primitive.ObjectID, accessKey string) (*mongodb.ReceivedDocument, error)
+}
+
+// InboxHandler handles queries of the NFS-e received through ADN DF-e distribution
+// by the CNPJs registered by the API key.
+type InboxHandler struct {
+	repo      InboxRepository
+	validator *validation.DistributionValidator
+}
+
+// InboxHandlerConfig configures the inbox handler.
+type InboxHandlerConfig struct {
+	// Repo is the repository for received documents.
+	// Can be *mongodb.DistributionRepository or any type implementing InboxRepository.
+	Repo InboxRepository
+}
+
+// NewInboxHandler creates a new inbox handler.
+func NewInboxHandler(config InboxHandlerConfig) *InboxHandler {
+	return &InboxHandler{
+		repo:      config.Repo,
+		validator: validation.NewDistributionValidator(),
+	}
+}
+
+// List handles GET /v1/inbox/nfse requests.
+// It returns a paginated list of the received NFS-e, latest competence first,
+// filtered by issuer, competence period, value range, manifestation status and
+// municipality of the service.
+func (h *InboxHandler) List(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	var q distribution.InboxQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		BadRequest(c, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+
+	parsed, validationErrors := h.validator.ParseInboxQuery(&q)
+	if len(validationErrors) > 0 {
+		ValidationFailed(c, convertDomainValidationErrors(validationErrors))
+		return
+	}
+
+	filter := mongodb.InboxFilter{
+		APIKeyID:         apiKey.ID,
+		CNPJ:             parsed.CNPJ,
+		IssuerCNPJ:       parsed.IssuerCNPJ,
+		CompetenceFrom:   parsed.CompetenceFrom,
+		CompetenceTo:     parsed.CompetenceTo,
+		MinValue:         parsed.MinValue,
+		MaxValue:         parsed.MaxValue,
+		Status:           parsed.ManifestationStatus,
+		MunicipalityCode: parsed.Municipality,
+	}
+	params := mongodb.PaginationParams{
+		Page:     parseIntQuery(c, "page", 1),
+		PageSize: parseIntQuery(c, "page_size", 20),
+	}
+
+	result, err := h.repo.FindInbox(c.Request.Context(), filter, params)
+	if err != nil {
+		InternalError(c, "Failed to retrieve received NFS-e")
+		return
+	}
+
+	items := make([]distribution.InboxNFSeResponse, 0, len(result.Items))
+	for _, doc := range result.Items {
+		items = append(items, newInboxNFSeResponse(doc))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"pagination": gin.H{
+			"page":        result.Page,
+			"page_size":   result.PageSize,
+			"total_count": result.TotalCount,
+			"total_pages": result.TotalPages,
+		},
+	})
+}
+
+// Get handles GET /v1/inbox/nfse/:chaveAcesso requests.
+// It returns a received NFS-e with its stored XML and parsed data.
+func (h *InboxHandler) Get(c *gin.Context) {
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	chaveAcesso := c.Param("chaveAcesso")
+	if err := query.ValidateAccessKey(chaveAcesso); err != nil {
+		BadRequest(c, formatAccessKeyError(err))
+		return
+	}
+
+	// Only NFS-e received by the API key's CNPJs are found
+	doc, err := h.repo.FindInboxNFSe(c.Request.Context(), apiKey.ID, chaveAcesso)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrReceivedDocumentNotFound) {
+			NotFound(c, "NFS-e was not received by any registered CNPJ")
+			return
+		}
+		InternalError(c, "Failed to retrieve received NFS-e")
+		return
+	}
+
+	c.JSON(http.StatusOK, newInboxNFSeResponse(doc))
+}
+
+// newInboxNFSeResponse converts a received NFS-e into its API representation,
+// parsing the stored XML as the NFS-e query endpoint does.
+func newInboxNFSeResponse(doc *mongodb.ReceivedDocument) distribution.InboxNFSeResponse {
+	nfse := &query.NFSeQueryResponse{
+		ChaveAcesso: doc.AccessKey,
+		XML:         doc.XML,
+	}
+	if data, err := query.ParseNFSeXML(doc.XML); err != nil {
+		// Still return the stored XML, which is the authoritative document
+		log.Printf("WARN: Failed to parse received NFS-e %s: %v", doc.AccessKey, err)
+	} else {
+		nfse = data.ToQueryResponse(doc.XML)
+	}
+
+	resp := distribution.InboxNFSeResponse{
+		NFSeQueryResponse: nfse,
+		Inbox: distribution.InboxInfo{
+			CNPJ:           doc.CNPJ,
+			NSU:            doc.NSU,
+			ReceivedAt:     doc.ReceivedAt,
+			SignatureValid: doc.Signature.Valid,
+		},
+	}
+	if doc.NFSe != nil {
+		resp.Inbox.ManifestationStatus = doc.NFSe.Status
+	}
+
+	return resp
+}
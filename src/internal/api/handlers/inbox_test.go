@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+)
+
+// MockInboxRepository is a mock implementation of the InboxRepository interface.
+type MockInboxRepository struct {
+	mock.Mock
+}
+
+// FindInbox mocks the FindInbox method.
+func (m *MockInboxRepository) FindInbox(ctx context.Context, filter mongodb.InboxFilter, params mongodb.PaginationParams) (*mongodb.InboxResult, error) {
+	args := m.Called(ctx, filter, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.InboxResult), args.Error(1)
+}
+
+// FindInboxNFSe mocks the FindInboxNFSe method.
+func (m *MockInboxRepository) FindInboxNFSe(ctx context.Context, apiKeyID primitive.ObjectID, accessKey string) (*mongodb.ReceivedDocument, error) {
+	args := m.Called(ctx, apiKeyID, accessKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.ReceivedDocument), args.Error(1)
+}
+
+// testInboxAccessKey is a valid access key of a received NFS-e.
+const testInboxAccessKey = "NFSe3550308260111222000000000000000000000000000010"
+
+// testInboxNFSeXML is the XML of a received NFS-e.
+const testInboxNFSeXML = `<?xml version="1.0" encoding="UTF-8"?>
+<NFSe xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00">
+  <infNFSe>
+    <nNFSe>000000042</nNFSe>
+    <dhEmi>2026-01-15T10:00:00-03:00</dhEmi>
+    <chNFSe>NFSe3550308260111222000000000000000000000000000010</chNFSe>
+    <sit>1</sit>
+    <emit>
+      <CNPJ>11444777000161</CNPJ>
+      <xNome>Prestador Ltda</xNome>
+      <ender>
+        <cMun>3550308</cMun>
+        <xMun>Sao Paulo</xMun>
+        <UF>SP</UF>
+      </ender>
+    </emit>
+    <toma>
+      <CNPJ>11222333000181</CNPJ>
+      <xNome>Tomador S.A.</xNome>
+    </toma>
+    <serv>
+      <cTribNac>010101</cTribNac>
+      <xDescServ>Consultoria</xDescServ>
+      <localPrest>
+        <cMun>3550308</cMun>
+        <xMun>Sao Paulo</xMun>
+        <UF>SP</UF>
+      </localPrest>
+    </serv>
+    <valores>
+      <vServico>1500.00</vServico>
+      <vBC>1500.00</vBC>
+      <pAliq>2.00</pAliq>
+      <vISS>30.00</vISS>
+      <vLiq>1470.00</vLiq>
+    </valores>
+    <DPS>
+      <infDPS>
+        <dCompet>2025-12-31</dCompet>
+      </infDPS>
+    </DPS>
+  </infNFSe>
+</NFSe>`
+
+// setupInboxTestRouter creates a test router with the inbox handler and API key.
+func setupInboxTestRouter(handler *InboxHandler, apiKey *mongodb.APIKey) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if apiKey != nil {
+			setAPIKeyInContext(c, apiKey)
+		}
+		c.Next()
+	})
+
+	r.GET("/v1/inbox/nfse", handler.List)
+	r.GET("/v1/inbox/nfse/:chaveAcesso", handler.Get)
+
+	return r
+}
+
+// newTestReceivedNFSe returns a received NFS-e owned by the API key.
+func newTestReceivedNFSe(apiKeyID primitive.ObjectID) *mongodb.ReceivedDocument {
+	return &mongodb.ReceivedDocument{
+		CNPJ:         "11222333000181",
+		NSU:          7,
+		APIKeyID:     apiKeyID,
+		ReceivedAt:   time.Now().UTC(),
+		AccessKey:    testInboxAccessKey,
+		DocumentType: "NFSE",
+		XML:          testInboxNFSeXML,
+		Signature:    mongodb.SignatureCheck{Valid: true},
+		NFSe: &mongodb.ReceivedNFSe{
+			IssuerCNPJ:   "11444777000161",
+			ServiceValue: 1500,
+			Status:       "confirmed",
+		},
+	}
+}
+
+func TestInboxHandler_List(t *testing.T) {
+	apiKeyID := primitive.NewObjectID()
+
+	mockRepo := new(MockInboxRepository)
+	mockRepo.On("FindInbox", mock.Anything, mock.Anything, mock.Anything).Return(&mongodb.InboxResult{
+		Items:      []*mongodb.ReceivedDocument{newTestReceivedNFSe(apiKeyID)},
+		TotalCount: 1,
+		Page:       1,
+		PageSize:   20,
+		TotalPages: 1,
+	}, nil)
+
+	handler := NewInboxHandler(InboxHandlerConfig{Repo: mockRepo})
+	router := setupInboxTestRouter(handler, createTestAPIKey(apiKeyID))
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/v1/inbox/nfse?issuer_cnpj=11.444.777/0001-61&competence=2025-12&min_value=1000&manifestation_status=confirmed&municipality=3550308", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Items      []json.RawMessage `json:"items"`
+		Pagination map[string]int64  `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, int64(1), resp.Pagination["total_count"])
+
+	var item distribution.InboxNFSeResponse
+	require.NoError(t, json.Unmarshal(resp.Items[0], &item))
+	assert.Equal(t, testInboxAccessKey, item.ChaveAcesso)
+	assert.Equal(t, "2025-12-31", item.DataCompetencia)
+	assert.Equal(t, "11444777000161", item.Prestador.Documento)
+	assert.Equal(t, 1500.0, item.Valores.ValorServico)
+	assert.Equal(t, "confirmed", item.Inbox.ManifestationStatus)
+	assert.Equal(t, int64(7), item.Inbox.NSU)
+
+	// The query parameters are passed to the repository parsed
+	filter := mockRepo.Calls[0].Arguments.Get(1).(mongodb.InboxFilter)
+	assert.Equal(t, apiKeyID, filter.APIKeyID)
+	assert.Equal(t, "11444777000161", filter.IssuerCNPJ)
+	require.NotNil(t, filter.CompetenceFrom)
+	require.NotNil(t, filter.CompetenceTo)
+	assert.Equal(t, "2025-12-01", filter.CompetenceFrom.Format("2006-01-02"))
+	assert.Equal(t, "2025-12-31", filter.CompetenceTo.Format("2006-01-02"))
+	require.NotNil(t, filter.MinValue)
+	assert.Equal(t, 1000.0, *filter.MinValue)
+	assert.Nil(t, filter.MaxValue)
+	assert.Equal(t, "confirmed", filter.Status)
+	assert.Equal(t, "3550308", filter.MunicipalityCode)
+}
+
+func TestInboxHandler_List_InvalidFilters(t *testing.T) {
+	mockRepo := new(MockInboxRepository)
+	handler := NewInboxHandler(InboxHandlerConfig{Repo: mockRepo})
+	router := setupInboxTestRouter(handler, createTestAPIKey(primitive.NewObjectID()))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/inbox/nfse?competence=12-2025&min_value=abc&manifestation_status=pending", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var problem ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	fields := make([]string, 0, len(problem.Errors))
+	for _, e := range problem.Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{"competence", "min_value", "manifestation_status"}, fields)
+
+	mockRepo.AssertNotCalled(t, "FindInbox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInboxHandler_Get(t *testing.T) {
+	apiKeyID := primitive.NewObjectID()
+	missingKey := "NFSe3550308260111222000000000000000000000000000099"
+
+	mockRepo := new(MockInboxRepository)
+	mockRepo.On("FindInboxNFSe", mock.Anything, apiKeyID, testInboxAccessKey).Return(newTestReceivedNFSe(apiKeyID), nil)
+	mockRepo.On("FindInboxNFSe", mock.Anything, apiKeyID, missingKey).Return(nil, mongodb.ErrReceivedDocumentNotFound)
+
+	handler := NewInboxHandler(InboxHandlerConfig{Repo: mockRepo})
+	router := setupInboxTestRouter(handler, createTestAPIKey(apiKeyID))
+
+	tests := []struct {
+		name           string
+		accessKey      string
+		expectedStatus int
+	}{
+		{name: "received NFS-e", accessKey: testInboxAccessKey, expectedStatus: http.StatusOK},
+		{name: "NFS-e not received", accessKey: missingKey, expectedStatus: http.StatusNotFound},
+		{name: "invalid access key", accessKey: "invalid", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/inbox/nfse/"+tt.accessKey, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp distribution.InboxNFSeResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, testInboxAccessKey, resp.ChaveAcesso)
+			assert.Equal(t, "000000042", resp.Numero)
+			assert.Equal(t, testInboxNFSeXML, resp.XML)
+			require.NotNil(t, resp.Tomador)
+			assert.Equal(t, "11222333000181", *resp.Tomador.Documento)
+			assert.Equal(t, "11222333000181", resp.Inbox.CNPJ)
+			assert.True(t, resp.Inbox.SignatureValid)
+		})
+	}
+}
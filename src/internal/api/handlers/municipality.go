@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/municipality"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
+	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
+)
+
+// MunicipalParameters defines the municipal parameters lookups needed by the handlers.
+// This interface allows for easier testing by enabling mock implementations.
+type MunicipalParameters interface {
+	GetConvenio(ctx context.Context, codigoMunicipio string) (*sefin.ConvenioResult, error)
+	GetContributorParameters(ctx context.Context, codigoMunicipio, documento string) (*sefin.ContributorParametersResult, error)
+}
+
+// MunicipalityHandler handles queries of the municipal parameters of the
+// Sistema Nacional NFS-e.
+type MunicipalityHandler struct {
+	parameters MunicipalParameters
+}
+
+// MunicipalityHandlerConfig configures the municipality handler.
+type MunicipalityHandlerConfig struct {
+	// Parameters retrieves the municipal parameters.
+	// Can be *sefin.ParametersCache or any type implementing MunicipalParameters.
+	Parameters MunicipalParameters
+}
+
+// NewMunicipalityHandler creates a new municipality handler.
+func NewMunicipalityHandler(config MunicipalityHandlerConfig) *MunicipalityHandler {
+	return &MunicipalityHandler{
+		parameters: config.Parameters,
+	}
+}
+
+// GetConvenio handles GET /v1/municipios/:codigo/convenio requests.
+// It returns the agreement of the municipality with the Sistema Nacional NFS-e,
+// so integrators can check whether NFS-e can be emitted there before onboarding.
+func (h *MunicipalityHandler) GetConvenio(c *gin.Context) {
+	codigo := c.Param("codigo")
+	if !validation.IsValidMunicipalityCode(codigo) {
+		BadRequest(c, "Municipality code must be a 7-digit IBGE code")
+		return
+	}
+
+	result, err := h.parameters.GetConvenio(c.Request.Context(), codigo)
+	if err != nil {
+		switch {
+		case errors.Is(err, sefin.ErrServiceUnavailable):
+			ServiceUnavailable(c, "Government service is temporarily unavailable. Please try again later.")
+		case errors.Is(err, sefin.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+			GatewayTimeout(c, "Government service request timed out. Please try again later.")
+		default:
+			InternalError(c, "Failed to retrieve municipal agreement")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, municipality.ConvenioResponse{
+		CodigoMunicipio:               result.CodigoMunicipio,
+		Conveniado:                    result.Conveniado,
+		AderenteEmissorNacional:       result.AderenteEmissorNacional,
+		AderenteAmbienteNacional:      result.AderenteAmbienteNacional,
+		AderenteMAN:                   result.AderenteMAN,
+		PermiteAproveitamentoCreditos: result.PermiteAproveitamentoCreditos,
+		CanEmit:                       result.CanEmit(),
+		ConsultedAt:                   result.ConsultedAt,
+	})
+}
+
+// checkConvenio verifies the issuing municipality adhered to the national emitter,
+// so an emission that SEFIN would reject is refused before being queued.
+// If the agreement cannot be retrieved the emission is allowed, leaving the
+// decision to SEFIN.
+func checkConvenio(c *gin.Context, parameters MunicipalParameters, codigoMunicipio string) *ProblemDetails {
+	if parameters == nil || codigoMunicipio == "" {
+		return nil
+	}
+
+	result, err := parameters.GetConvenio(c.Request.Context(), codigoMunicipio)
+	if err != nil {
+		log.Printf("WARN: Failed to check agreement of municipality %s: %v", codigoMunicipio, err)
+		return nil
+	}
+
+	if result.CanEmit() {
+		return nil
+	}
+
+	detail := fmt.Sprintf("Municipality %s has no agreement with the Sistema Nacional NFS-e", codigoMunicipio)
+	if result.Conveniado {
+		detail = fmt.Sprintf("Municipality %s has not adhered to the national NFS-e emitter", codigoMunicipio)
+	}
+
+	return NewProblemDetails(
+		ProblemTypeUnprocessableEntity,
+		"Municipality Not Adhered",
+		http.StatusUnprocessableEntity,
+	).WithDetail(detail).WithInstance(c.Request.URL.Path)
+}
+
+// checkContributorParameters verifies the provider's tax regime and claimed municipal
+// benefit match what the issuing municipality has on record for the provider.
+// If the parameters cannot be retrieved the emission is allowed, leaving the
+// decision to SEFIN.
+func checkContributorParameters(c *gin.Context, parameters MunicipalParameters, validator *validation.EmissionValidator, req *emission.EmissionRequest) []ValidationError {
+	codigoMunicipio := req.Service.MunicipalityCode
+	if parameters == nil || codigoMunicipio == "" {
+		return nil
+	}
+
+	documento := cnpjcpf.CleanCNPJ(req.Provider.Registration())
+	result, err := parameters.GetContributorParameters(c.Request.Context(), codigoMunicipio, documento)
+	if err != nil {
+		log.Printf("WARN: Failed to check parameters of contributor %s in municipality %s: %v", documento, codigoMunicipio, err)
+		return nil
+	}
+
+	errs := validator.ValidateContributorParameters(req, result, time.Now())
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return convertDomainValidationErrors(errs)
+}
+
+// checkBeforeQueue runs the checks every emission, original or substitute, must
+// pass before it is queued: the certificate, when given, must belong to the party
+// emitting the DPS, the issuing municipality must accept the national emitter, and
+// the provider's tax regime and benefits must match its contributor parameters.
+// It responds with the failure and returns false when a check rejects the emission.
+func checkBeforeQueue(c *gin.Context, parameters MunicipalParameters, validator *validation.EmissionValidator, req *emission.EmissionRequest, certInfo *xmlsigner.CertificateInfo) bool {
+	// The certificate must belong to the party emitting the DPS
+	if certInfo != nil {
+		if holderErrors := validation.ValidateCertificateHolder(req, certInfo); len(holderErrors) > 0 {
+			ValidationFailed(c, convertDomainValidationErrors(holderErrors))
+			return false
+		}
+	}
+
+	// Reject emissions the issuing municipality does not accept through the national emitter
+	if problem := checkConvenio(c, parameters, req.Service.MunicipalityCode); problem != nil {
+		problem.Respond(c)
+		return false
+	}
+
+	// Reject tax regimes and municipal benefits the municipality has no record of
+	if errs := checkContributorParameters(c, parameters, validator, req); len(errs) > 0 {
+		ValidationFailed(c, errs)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/municipality"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
+)
+
+// MockMunicipalParameters is a mock implementation of the MunicipalParameters interface.
+type MockMunicipalParameters struct {
+	mock.Mock
+}
+
+// GetConvenio mocks the GetConvenio method.
+func (m *MockMunicipalParameters) GetConvenio(ctx context.Context, codigoMunicipio string) (*sefin.ConvenioResult, error) {
+	args := m.Called(ctx, codigoMunicipio)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.ConvenioResult), args.Error(1)
+}
+
+// GetContributorParameters mocks the GetContributorParameters method.
+func (m *MockMunicipalParameters) GetContributorParameters(ctx context.Context, codigoMunicipio, documento string) (*sefin.ContributorParametersResult, error) {
+	args := m.Called(ctx, codigoMunicipio, documento)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.ContributorParametersResult), args.Error(1)
+}
+
+// newTestConvenio returns the agreement of a municipality with the given adherence.
+func newTestConvenio(codigo string, conveniado, emissorNacional bool) *sefin.ConvenioResult {
+	return &sefin.ConvenioResult{
+		CodigoMunicipio:          codigo,
+		Conveniado:               conveniado,
+		AderenteAmbienteNacional: conveniado,
+		AderenteEmissorNacional:  emissorNacional,
+		ConsultedAt:              time.Now().UTC(),
+	}
+}
+
+func TestMunicipalityHandler_GetConvenio(t *testing.T) {
+	params := new(MockMunicipalParameters)
+	params.On("GetConvenio", mock.Anything, "3550308").Return(newTestConvenio("3550308", true, true), nil)
+	params.On("GetConvenio", mock.Anything, "4106902").Return(newTestConvenio("4106902", true, false), nil)
+	params.On("GetConvenio", mock.Anything, "5300108").Return(nil, sefin.ErrServiceUnavailable)
+
+	handler := NewMunicipalityHandler(MunicipalityHandlerConfig{Parameters: params})
+	router := gin.New()
+	router.GET("/v1/municipios/:codigo/convenio", handler.GetConvenio)
+
+	tests := []struct {
+		name           string
+		codigo         string
+		expectedStatus int
+		expectedEmit   bool
+	}{
+		{name: "adhered municipality", codigo: "3550308", expectedStatus: http.StatusOK, expectedEmit: true},
+		{name: "own emitter municipality", codigo: "4106902", expectedStatus: http.StatusOK, expectedEmit: false},
+		{name: "service unavailable", codigo: "5300108", expectedStatus: http.StatusServiceUnavailable},
+		{name: "invalid code", codigo: "355030", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/municipios/"+tt.codigo+"/convenio", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp municipality.ConvenioResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.codigo, resp.CodigoMunicipio)
+			assert.True(t, resp.Conveniado)
+			assert.Equal(t, tt.expectedEmit, resp.CanEmit)
+			assert.False(t, resp.ConsultedAt.IsZero())
+		})
+	}
+
+	params.AssertNotCalled(t, "GetConvenio", mock.Anything, "355030")
+}
+
+func TestCheckConvenio(t *testing.T) {
+	params := new(MockMunicipalParameters)
+	params.On("GetConvenio", mock.Anything, "3550308").Return(newTestConvenio("3550308", true, true), nil)
+	params.On("GetConvenio", mock.Anything, "4106902").Return(newTestConvenio("4106902", true, false), nil)
+	params.On("GetConvenio", mock.Anything, "9999999").Return(newTestConvenio("9999999", false, false), nil)
+	params.On("GetConvenio", mock.Anything, "5300108").Return(nil, errors.New("connection refused"))
+
+	tests := []struct {
+		name       string
+		params     MunicipalParameters
+		codigo     string
+		wantReject bool
+	}{
+		{name: "adhered municipality", params: params, codigo: "3550308"},
+		{name: "not adhered to the national emitter", params: params, codigo: "4106902", wantReject: true},
+		{name: "no agreement", params: params, codigo: "9999999", wantReject: true},
+		{name: "lookup failure allows emission", params: params, codigo: "5300108"},
+		{name: "no parameters configured", params: nil, codigo: "9999999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/nfse", nil)
+
+			problem := checkConvenio(c, tt.params, tt.codigo)
+			if !tt.wantReject {
+				assert.Nil(t, problem)
+				return
+			}
+
+			require.NotNil(t, problem)
+			assert.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+			assert.Contains(t, problem.Detail, tt.codigo)
+		})
+	}
+}
+
+func TestCheckContributorParameters(t *testing.T) {
+	const benefit = "35503080400001"
+	granted := &sefin.ContributorParametersResult{
+		CodigoMunicipio:      "3550308",
+		Documento:            "11222333000181",
+		Cadastrado:           true,
+		OpcaoSimplesNacional: sefin.OpcaoSimplesNacionalMEEPP,
+		Beneficios:           []sefin.BeneficioMunicipal{{Numero: benefit}},
+	}
+
+	params := new(MockMunicipalParameters)
+	params.On("GetContributorParameters", mock.Anything, "3550308", "11222333000181").Return(granted, nil)
+	params.On("GetContributorParameters", mock.Anything, "5300108", "11222333000181").Return(nil, errors.New("connection refused"))
+
+	tests := []struct {
+		name        string
+		params      MunicipalParameters
+		codigo      string
+		taxRegime   string
+		benefit     string
+		wantRejects []string
+	}{
+		{name: "matching record", params: params, codigo: "3550308", taxRegime: validation.TaxRegimeMEEPP, benefit: benefit},
+		{name: "tax regime not on record", params: params, codigo: "3550308", taxRegime: validation.TaxRegimeMEI, wantRejects: []string{"provider.tax_regime"}},
+		{name: "benefit not on record", params: params, codigo: "3550308", taxRegime: validation.TaxRegimeMEEPP, benefit: "35503080400009", wantRejects: []string{"values.municipal_benefit.number"}},
+		{name: "lookup failure allows emission", params: params, codigo: "5300108", taxRegime: validation.TaxRegimeMEI, benefit: benefit},
+		{name: "no parameters configured", params: nil, codigo: "3550308", taxRegime: validation.TaxRegimeMEI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/nfse", nil)
+
+			req := &emission.EmissionRequest{
+				Provider: emission.ProviderRequest{CNPJ: "11.222.333/0001-81", TaxRegime: tt.taxRegime},
+				Service:  emission.ServiceRequest{MunicipalityCode: tt.codigo},
+			}
+			if tt.benefit != "" {
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{Number: tt.benefit}
+			}
+
+			errs := checkContributorParameters(c, tt.params, validation.NewEmissionValidator(), req)
+			require.Len(t, errs, len(tt.wantRejects))
+			for i, field := range tt.wantRejects {
+				assert.Equal(t, field, errs[i].Field)
+				assert.Equal(t, validation.ValidationCodeNotOnRecord, errs[i].Code)
+			}
+		})
+	}
+}
+
+// newTestHolderCertificate creates a self-signed certificate identifying its holder
+// in the common name, as ICP-Brasil certificates do ("NAME:registration").
+func newTestHolderCertificate(t *testing.T, commonName string) *xmlsigner.CertificateInfo {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	return &xmlsigner.CertificateInfo{PrivateKey: privateKey, Certificate: cert}
+}
+
+func TestCheckBeforeQueue(t *testing.T) {
+	registered := &sefin.ContributorParametersResult{
+		CodigoMunicipio:      "3550308",
+		Documento:            "11222333000181",
+		Cadastrado:           true,
+		OpcaoSimplesNacional: sefin.OpcaoSimplesNacionalMEEPP,
+	}
+
+	params := new(MockMunicipalParameters)
+	params.On("GetConvenio", mock.Anything, "3550308").Return(newTestConvenio("3550308", true, true), nil)
+	params.On("GetConvenio", mock.Anything, "4106902").Return(newTestConvenio("4106902", true, false), nil)
+	params.On("GetContributorParameters", mock.Anything, "3550308", "11222333000181").Return(registered, nil)
+
+	tests := []struct {
+		name           string
+		codigo         string
+		taxRegime      string
+		commonName     string
+		expectedStatus int
+		expectedField  string
+	}{
+		{name: "all checks pass", codigo: "3550308", taxRegime: validation.TaxRegimeMEEPP, commonName: "PRESTADOR LTDA:11222333000181"},
+		{name: "without certificate", codigo: "3550308", taxRegime: validation.TaxRegimeMEEPP},
+		{
+			name: "certificate of another party", codigo: "3550308", taxRegime: validation.TaxRegimeMEEPP,
+			commonName: "OUTRA EMPRESA SA:11444777000161", expectedStatus: http.StatusBadRequest, expectedField: "certificate.pfx_base64",
+		},
+		{
+			name: "municipality not adhered", codigo: "4106902", taxRegime: validation.TaxRegimeMEEPP,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "tax regime not on record", codigo: "3550308", taxRegime: validation.TaxRegimeMEI,
+			expectedStatus: http.StatusBadRequest, expectedField: "provider.tax_regime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/nfse", nil)
+
+			req := &emission.EmissionRequest{
+				Provider: emission.ProviderRequest{CNPJ: "11222333000181", TaxRegime: tt.taxRegime},
+				Service:  emission.ServiceRequest{MunicipalityCode: tt.codigo},
+			}
+			var certInfo *xmlsigner.CertificateInfo
+			if tt.commonName != "" {
+				certInfo = newTestHolderCertificate(t, tt.commonName)
+			}
+
+			ok := checkBeforeQueue(c, params, validation.NewEmissionValidator(), req, certInfo)
+			if tt.expectedStatus == 0 {
+				assert.True(t, ok)
+				return
+			}
+
+			assert.False(t, ok)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedField != "" {
+				var problem ProblemDetails
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				require.Len(t, problem.Errors, 1)
+				assert.Equal(t, tt.expectedField, problem.Errors[0].Field)
+			}
+		})
+	}
+}
@@ -12,12 +12,21 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/eduardo/nfse-nacional/internal/domain/query"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
 )
 
+// LifecycleTracker defines the operation needed by QueryHandler to update the
+// lifecycle of NFS-e emitted through this API with the events it discovers.
+type LifecycleTracker interface {
+	Apply(ctx context.Context, accessKey string, events ...jobs.LifecycleEvent) (*mongodb.LifecycleData, error)
+}
+
 // QueryHandler handles NFS-e query requests.
 type QueryHandler struct {
 	sefinClient sefin.SefinClient
+	lifecycle   LifecycleTracker
 	baseURL     string
 	logger      *log.Logger
 }
@@ -27,6 +36,10 @@ type QueryHandlerConfig struct {
 	// SefinClient is the client for communicating with the government API.
 	SefinClient sefin.SefinClient
 
+	// Lifecycle is an optional lifecycle tracker (can be nil). When set, the
+	// events returned by the event queries update the local NFS-e lifecycle.
+	Lifecycle LifecycleTracker
+
 	// BaseURL is the base URL for constructing resource URLs.
 	BaseURL string
 
@@ -38,6 +51,7 @@ type QueryHandlerConfig struct {
 func NewQueryHandler(config QueryHandlerConfig) *QueryHandler {
 	return &QueryHandler{
 		sefinClient: config.SefinClient,
+		lifecycle:   config.Lifecycle,
 		baseURL:     config.BaseURL,
 		logger:      config.Logger,
 	}
@@ -338,6 +352,8 @@ func (h *QueryHandler) GetEvents(c *gin.Context) {
 		return
 	}
 
+	h.trackLifecycle(c, chaveAcesso, result.Events...)
+
 	// Map SEFIN response to API response DTO (T039)
 	response := h.mapToEventsQueryResponse(result, eventType)
 
@@ -353,6 +369,171 @@ func (h *QueryHandler) GetEvents(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetEventsByType handles GET /v1/nfse/:chaveAcesso/eventos/:tipoEvento requests.
+// It retrieves only the events of the given type from the government API, instead of
+// downloading every event of the NFS-e and filtering them locally.
+//
+// Responses:
+//   - 200 OK: Events returned successfully (empty list if the NFS-e has no events of that type)
+//   - 400 Bad Request: Invalid access key or event type format
+//   - 404 Not Found: NFS-e not found
+//   - 503 Service Unavailable: Government API unavailable
+//   - 504 Gateway Timeout: Government API timeout
+func (h *QueryHandler) GetEventsByType(c *gin.Context) {
+	start := time.Now()
+
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	chaveAcesso := c.Param("chaveAcesso")
+	eventType := c.Param("tipoEvento")
+
+	h.logQuery(c, "events_query_start", map[string]interface{}{
+		"chave_acesso": maskAccessKey(chaveAcesso),
+		"api_key_id":   apiKey.KeyPrefix,
+		"event_type":   eventType,
+	})
+
+	if !h.validateEventPath(c, chaveAcesso, eventType) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := h.sefinClient.QueryEventsByType(ctx, chaveAcesso, eventType, nil)
+	if err != nil {
+		h.handleEventsQueryError(c, err, chaveAcesso, start)
+		return
+	}
+
+	h.trackLifecycle(c, chaveAcesso, result.Events...)
+
+	response := h.mapToEventsQueryResponse(result, eventType)
+
+	h.logQuery(c, "events_query_success", map[string]interface{}{
+		"chave_acesso": maskAccessKey(chaveAcesso),
+		"total_events": response.Total,
+		"event_type":   eventType,
+		"latency_ms":   time.Since(start).Milliseconds(),
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEvent handles GET /v1/nfse/:chaveAcesso/eventos/:tipoEvento/:numSeqEvento requests.
+// It retrieves a single event with its XML and the fields parsed from it
+// (sequence number, processing date and author).
+//
+// Responses:
+//   - 200 OK: Event found and returned successfully
+//   - 400 Bad Request: Invalid access key, event type or sequence number
+//   - 404 Not Found: NFS-e or event not found
+//   - 503 Service Unavailable: Government API unavailable
+//   - 504 Gateway Timeout: Government API timeout
+func (h *QueryHandler) GetEvent(c *gin.Context) {
+	start := time.Now()
+
+	// Get API key from context (set by auth middleware)
+	apiKey := getAPIKeyFromContext(c)
+	if apiKey == nil {
+		InternalError(c, "Failed to retrieve API key from context")
+		return
+	}
+
+	chaveAcesso := c.Param("chaveAcesso")
+	eventType := c.Param("tipoEvento")
+
+	h.logQuery(c, "event_query_start", map[string]interface{}{
+		"chave_acesso": maskAccessKey(chaveAcesso),
+		"api_key_id":   apiKey.KeyPrefix,
+		"event_type":   eventType,
+		"sequence":     c.Param("numSeqEvento"),
+	})
+
+	if !h.validateEventPath(c, chaveAcesso, eventType) {
+		return
+	}
+
+	sequence, err := query.ParseEventSequence(c.Param("numSeqEvento"))
+	if err != nil {
+		BadRequest(c, "Event sequence number must be an integer between 1 and 999")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	evt, err := h.sefinClient.QueryEvent(ctx, chaveAcesso, eventType, sequence, nil)
+	if err != nil {
+		h.handleEventsQueryError(c, err, chaveAcesso, start)
+		return
+	}
+
+	h.trackLifecycle(c, chaveAcesso, *evt)
+
+	response := &query.EventQueryResponse{
+		ChaveAcesso: chaveAcesso,
+		EventInfo:   mapToEventInfo(*evt),
+	}
+
+	h.logQuery(c, "event_query_success", map[string]interface{}{
+		"chave_acesso": maskAccessKey(chaveAcesso),
+		"event_type":   eventType,
+		"sequence":     sequence,
+		"latency_ms":   time.Since(start).Milliseconds(),
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// trackLifecycle applies the events found in the government API to the local
+// lifecycle of the NFS-e. Failures are only logged and never affect the response.
+func (h *QueryHandler) trackLifecycle(c *gin.Context, chaveAcesso string, events ...sefin.EventData) {
+	if h.lifecycle == nil || len(events) == 0 {
+		return
+	}
+
+	lifecycleEvents := make([]jobs.LifecycleEvent, 0, len(events))
+	for _, evt := range events {
+		lifecycleEvents = append(lifecycleEvents, jobs.NewDiscoveredLifecycleEvent(evt))
+	}
+
+	if _, err := h.lifecycle.Apply(c.Request.Context(), chaveAcesso, lifecycleEvents...); err != nil {
+		h.logQuery(c, "lifecycle_update_error", map[string]interface{}{
+			"chave_acesso": maskAccessKey(chaveAcesso),
+			"error":        err.Error(),
+		})
+	}
+}
+
+// validateEventPath validates the access key and event type path parameters.
+// Writes a 400 response and returns false if either is invalid.
+func (h *QueryHandler) validateEventPath(c *gin.Context, chaveAcesso, eventType string) bool {
+	if err := query.ValidateAccessKey(chaveAcesso); err != nil {
+		h.logQuery(c, "events_query_invalid_key", map[string]interface{}{
+			"error":  err.Error(),
+			"length": len(chaveAcesso),
+		})
+		BadRequest(c, formatAccessKeyError(err))
+		return false
+	}
+
+	if err := query.ValidateEventType(eventType); err != nil {
+		h.logQuery(c, "events_query_invalid_type", map[string]interface{}{
+			"error": err.Error(),
+		})
+		BadRequest(c, "Event type must be 'e' followed by 6 digits (e.g. e101101)")
+		return false
+	}
+
+	return true
+}
+
 // handleEventsQueryError processes errors from the SEFIN API for events queries.
 func (h *QueryHandler) handleEventsQueryError(c *gin.Context, err error, chaveAcesso string, start time.Time) {
 	latencyMs := time.Since(start).Milliseconds()
@@ -368,6 +549,24 @@ func (h *QueryHandler) handleEventsQueryError(c *gin.Context, err error, chaveAc
 		NotFound(c, "NFS-e not found with the specified access key")
 		return
 
+	case errors.Is(err, sefin.ErrEventNotFound):
+		// 404 Not Found - NFS-e exists but has no such event
+		h.logQuery(c, "events_query_event_not_found", map[string]interface{}{
+			"chave_acesso": maskAccessKey(chaveAcesso),
+			"latency_ms":   latencyMs,
+		})
+		NotFound(c, "Event not found for the specified NFS-e")
+		return
+
+	case errors.Is(err, sefin.ErrForbidden):
+		// 403 Forbidden - actor restriction
+		h.logQuery(c, "events_query_forbidden", map[string]interface{}{
+			"chave_acesso": maskAccessKey(chaveAcesso),
+			"latency_ms":   latencyMs,
+		})
+		Forbidden(c, "Access to the NFS-e events was denied by the government API")
+		return
+
 	case errors.Is(err, sefin.ErrServiceUnavailable):
 		// 503 Service Unavailable
 		h.logQuery(c, "events_query_service_unavailable", map[string]interface{}{
@@ -422,25 +621,7 @@ func (h *QueryHandler) mapToEventsQueryResponse(result *sefin.EventsQueryResult,
 			continue
 		}
 
-		// Map event data to DTO
-		eventInfo := query.EventInfo{
-			Tipo:      evt.Tipo,
-			Descricao: evt.Descricao,
-			Sequencia: evt.Sequencia,
-			Data:      formatDateTime(evt.Data),
-			XML:       evt.XML,
-		}
-
-		// Use description from EventTypeDescriptions if available and not already set
-		if eventInfo.Descricao == "" {
-			if desc, ok := query.EventTypeDescriptions[evt.Tipo]; ok {
-				eventInfo.Descricao = desc
-			} else {
-				eventInfo.Descricao = evt.Tipo
-			}
-		}
-
-		eventos = append(eventos, eventInfo)
+		eventos = append(eventos, mapToEventInfo(evt))
 	}
 
 	// Return response with empty list if no events (T040)
@@ -451,6 +632,39 @@ func (h *QueryHandler) mapToEventsQueryResponse(result *sefin.EventsQueryResult,
 	}
 }
 
+// mapToEventInfo maps a single SEFIN EventData to the API event DTO.
+func mapToEventInfo(evt sefin.EventData) query.EventInfo {
+	eventInfo := query.EventInfo{
+		Tipo:      evt.Tipo,
+		Descricao: evt.Descricao,
+		Sequencia: evt.Sequencia,
+		Data:      formatDateTime(evt.Data),
+		XML:       evt.XML,
+	}
+
+	// Use description from EventTypeDescriptions if available and not already set
+	if eventInfo.Descricao == "" {
+		if desc, ok := query.EventTypeDescriptions[evt.Tipo]; ok {
+			eventInfo.Descricao = desc
+		} else {
+			eventInfo.Descricao = evt.Tipo
+		}
+	}
+
+	if !evt.DataProcessamento.IsZero() {
+		eventInfo.DataProcessamento = formatDateTime(evt.DataProcessamento)
+	}
+
+	if evt.AutorCNPJ != "" || evt.AutorCPF != "" {
+		eventInfo.Autor = &query.EventAuthor{
+			CNPJ: evt.AutorCNPJ,
+			CPF:  evt.AutorCPF,
+		}
+	}
+
+	return eventInfo
+}
+
 // GatewayTimeout responds with a 504 Gateway Timeout error.
 func GatewayTimeout(c *gin.Context, detail string) {
 	problem := NewProblemDetails(
@@ -18,8 +18,10 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
 )
 
 // ================================================================================
@@ -73,6 +75,60 @@ func (m *MockSefinClient) QueryEvents(ctx context.Context, chaveAcesso string, c
 	return args.Get(0).(*sefin.EventsQueryResult), args.Error(1)
 }
 
+// QueryEventsByType mocks the events query filtered by type.
+func (m *MockSefinClient) QueryEventsByType(ctx context.Context, chaveAcesso, tipoEvento string, cert *tls.Certificate) (*sefin.EventsQueryResult, error) {
+	args := m.Called(ctx, chaveAcesso, tipoEvento, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.EventsQueryResult), args.Error(1)
+}
+
+// QueryEvent mocks the single event query.
+func (m *MockSefinClient) QueryEvent(ctx context.Context, chaveAcesso, tipoEvento string, numSeqEvento int, cert *tls.Certificate) (*sefin.EventData, error) {
+	args := m.Called(ctx, chaveAcesso, tipoEvento, numSeqEvento, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.EventData), args.Error(1)
+}
+
+// RegisterEvent mocks the event registration operation.
+func (m *MockSefinClient) RegisterEvent(ctx context.Context, chaveAcesso, pedRegEventoXML string, cert *tls.Certificate) (*sefin.EventRegistrationResult, error) {
+	args := m.Called(ctx, chaveAcesso, pedRegEventoXML, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.EventRegistrationResult), args.Error(1)
+}
+
+// QueryConvenio mocks the QueryConvenio method.
+func (m *MockSefinClient) QueryConvenio(ctx context.Context, codigoMunicipio string, cert *tls.Certificate) (*sefin.ConvenioResult, error) {
+	args := m.Called(ctx, codigoMunicipio, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.ConvenioResult), args.Error(1)
+}
+
+// QueryServiceParameters mocks the QueryServiceParameters method.
+func (m *MockSefinClient) QueryServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string, cert *tls.Certificate) (*sefin.ServiceParametersResult, error) {
+	args := m.Called(ctx, codigoMunicipio, codigoServico, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.ServiceParametersResult), args.Error(1)
+}
+
+// QueryContributorParameters mocks the QueryContributorParameters method.
+func (m *MockSefinClient) QueryContributorParameters(ctx context.Context, codigoMunicipio, documento string, cert *tls.Certificate) (*sefin.ContributorParametersResult, error) {
+	args := m.Called(ctx, codigoMunicipio, documento, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sefin.ContributorParametersResult), args.Error(1)
+}
+
 // ================================================================================
 // Test Helpers
 // ================================================================================
@@ -111,6 +167,8 @@ func setupTestRouter(handler *QueryHandler, apiKey *mongodb.APIKey) *gin.Engine
 
 	r.GET("/v1/nfse/:chaveAcesso", handler.GetNFSe)
 	r.GET("/v1/nfse/:chaveAcesso/eventos", handler.GetEvents)
+	r.GET("/v1/nfse/:chaveAcesso/eventos/:tipoEvento", handler.GetEventsByType)
+	r.GET("/v1/nfse/:chaveAcesso/eventos/:tipoEvento/:numSeqEvento", handler.GetEvent)
 
 	return r
 }
@@ -1166,6 +1224,182 @@ func TestGetEvents_PreservesExistingDescription(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+// ================================================================================
+// GetEventsByType / GetEvent Tests
+// ================================================================================
+
+func TestGetEventsByType_Success(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	handler := createTestHandler(mockClient)
+	apiKey := testAPIKey()
+
+	result := &sefin.EventsQueryResult{
+		ChaveAcesso: validAccessKey(),
+		Events: []sefin.EventData{
+			{
+				Tipo:      "e101101",
+				Sequencia: 1,
+				Data:      time.Date(2024, 1, 16, 14, 0, 0, 0, time.UTC),
+				AutorCNPJ: "12345678000199",
+				XML:       "<evento>cancellation</evento>",
+			},
+		},
+	}
+
+	mockClient.On("QueryEventsByType", mock.Anything, validAccessKey(), "e101101", (*tls.Certificate)(nil)).
+		Return(result, nil)
+
+	router := setupTestRouter(handler, apiKey)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/e101101", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(1), response["total"])
+	eventos := response["eventos"].([]interface{})
+	require.Len(t, eventos, 1)
+
+	event := eventos[0].(map[string]interface{})
+	assert.Equal(t, "e101101", event["tipo"])
+	assert.Equal(t, "Cancelamento de NFS-e", event["descricao"])
+	assert.Equal(t, "12345678000199", event["autor"].(map[string]interface{})["cnpj"])
+
+	mockClient.AssertNotCalled(t, "QueryEvents", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetEventsByType_InvalidEventType(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	handler := createTestHandler(mockClient)
+	apiKey := testAPIKey()
+
+	router := setupTestRouter(handler, apiKey)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/CANCELAMENTO", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockClient.AssertNotCalled(t, "QueryEventsByType", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetEventsByType_NFSeNotFound(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	handler := createTestHandler(mockClient)
+	apiKey := testAPIKey()
+
+	mockClient.On("QueryEventsByType", mock.Anything, validAccessKey(), "e101101", (*tls.Certificate)(nil)).
+		Return(nil, sefin.ErrNFSeNotFound)
+
+	router := setupTestRouter(handler, apiKey)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/e101101", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetEvent_Success(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	handler := createTestHandler(mockClient)
+	apiKey := testAPIKey()
+
+	event := &sefin.EventData{
+		Tipo:              "e105102",
+		Sequencia:         2,
+		Data:              time.Date(2024, 1, 16, 14, 0, 0, 0, time.UTC),
+		DataProcessamento: time.Date(2024, 1, 16, 14, 0, 5, 0, time.UTC),
+		AutorCPF:          "12345678909",
+		XML:               "<evento>substitution</evento>",
+	}
+
+	mockClient.On("QueryEvent", mock.Anything, validAccessKey(), "e105102", 2, (*tls.Certificate)(nil)).
+		Return(event, nil)
+
+	router := setupTestRouter(handler, apiKey)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/e105102/2", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, validAccessKey(), response["chave_acesso"])
+	assert.Equal(t, "e105102", response["tipo"])
+	assert.Equal(t, float64(2), response["sequencia"])
+	assert.NotEmpty(t, response["data_processamento"])
+	assert.Equal(t, "<evento>substitution</evento>", response["xml"])
+
+	autor := response["autor"].(map[string]interface{})
+	assert.Equal(t, "12345678909", autor["cpf"])
+	assert.NotContains(t, autor, "cnpj")
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetEvent_InvalidSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence string
+	}{
+		{"not a number", "abc"},
+		{"zero", "0"},
+		{"above maximum", "1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockSefinClient)
+			handler := createTestHandler(mockClient)
+
+			router := setupTestRouter(handler, testAPIKey())
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/e101101/"+tt.sequence, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockClient.AssertNotCalled(t, "QueryEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetEvent_EventNotFound(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	handler := createTestHandler(mockClient)
+	apiKey := testAPIKey()
+
+	mockClient.On("QueryEvent", mock.Anything, validAccessKey(), "e101101", 1, (*tls.Certificate)(nil)).
+		Return(nil, sefin.ErrEventNotFound)
+
+	router := setupTestRouter(handler, apiKey)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/e101101/1", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Contains(t, response["detail"], "Event not found")
+
+	mockClient.AssertExpectations(t)
+}
+
 // ================================================================================
 // Table-Driven Tests for Access Key Validation
 // ================================================================================
@@ -1997,6 +2231,136 @@ func TestGetNFSe_LoggingOnInternalError(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+// ================================================================================
+// Lifecycle Tracking Tests
+// ================================================================================
+
+// MockLifecycleTracker implements LifecycleTracker for testing.
+type MockLifecycleTracker struct {
+	mock.Mock
+}
+
+func (m *MockLifecycleTracker) Apply(ctx context.Context, accessKey string, events ...jobs.LifecycleEvent) (*mongodb.LifecycleData, error) {
+	args := m.Called(ctx, accessKey, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.LifecycleData), args.Error(1)
+}
+
+func TestGetEvents_TracksDiscoveredEvents(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	tracker := new(MockLifecycleTracker)
+	handler := NewQueryHandler(QueryHandlerConfig{
+		SefinClient: mockClient,
+		Lifecycle:   tracker,
+		BaseURL:     "http://localhost:8080",
+	})
+
+	mockClient.On("QueryEvents", mock.Anything, validAccessKey(), (*tls.Certificate)(nil)).
+		Return(createSampleEventsResult(), nil)
+	tracker.On("Apply", mock.Anything, validAccessKey(), mock.MatchedBy(func(events []jobs.LifecycleEvent) bool {
+		return len(events) == 2 &&
+			events[1].EventType == "e101101" &&
+			events[1].Sequence == 2 &&
+			events[1].Source == event.LifecycleSourceDiscovered &&
+			!events[1].OccurredAt.IsZero()
+	})).Return(&mongodb.LifecycleData{Status: event.LifecycleCancelled}, nil)
+
+	router := setupTestRouter(handler, testAPIKey())
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockClient.AssertExpectations(t)
+	tracker.AssertExpectations(t)
+}
+
+func TestGetEvents_LifecycleErrorDoesNotAffectResponse(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	tracker := new(MockLifecycleTracker)
+	handler := NewQueryHandler(QueryHandlerConfig{
+		SefinClient: mockClient,
+		Lifecycle:   tracker,
+		BaseURL:     "http://localhost:8080",
+	})
+
+	mockClient.On("QueryEvents", mock.Anything, validAccessKey(), (*tls.Certificate)(nil)).
+		Return(createSampleEventsResult(), nil)
+	tracker.On("Apply", mock.Anything, validAccessKey(), mock.Anything).
+		Return(nil, errors.New("database unavailable"))
+
+	router := setupTestRouter(handler, testAPIKey())
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(2), response["total"])
+
+	tracker.AssertExpectations(t)
+}
+
+func TestGetEvents_NoEventsSkipsLifecycle(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	tracker := new(MockLifecycleTracker)
+	handler := NewQueryHandler(QueryHandlerConfig{
+		SefinClient: mockClient,
+		Lifecycle:   tracker,
+		BaseURL:     "http://localhost:8080",
+	})
+
+	mockClient.On("QueryEvents", mock.Anything, validAccessKey(), (*tls.Certificate)(nil)).
+		Return(&sefin.EventsQueryResult{ChaveAcesso: validAccessKey(), Events: []sefin.EventData{}}, nil)
+
+	router := setupTestRouter(handler, testAPIKey())
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	tracker.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetEvent_TracksDiscoveredEvent(t *testing.T) {
+	mockClient := new(MockSefinClient)
+	tracker := new(MockLifecycleTracker)
+	handler := NewQueryHandler(QueryHandlerConfig{
+		SefinClient: mockClient,
+		Lifecycle:   tracker,
+		BaseURL:     "http://localhost:8080",
+	})
+
+	processedAt := time.Date(2024, 1, 16, 14, 0, 5, 0, time.UTC)
+	mockClient.On("QueryEvent", mock.Anything, validAccessKey(), "e305102", 1, (*tls.Certificate)(nil)).
+		Return(&sefin.EventData{
+			Tipo:              "e305102",
+			Sequencia:         1,
+			Data:              time.Date(2024, 1, 16, 14, 0, 0, 0, time.UTC),
+			DataProcessamento: processedAt,
+			XML:               "<evento>block</evento>",
+		}, nil)
+	tracker.On("Apply", mock.Anything, validAccessKey(), []jobs.LifecycleEvent{{
+		EventType:  "e305102",
+		Sequence:   1,
+		OccurredAt: processedAt,
+		Source:     event.LifecycleSourceDiscovered,
+	}}).Return(&mongodb.LifecycleData{Status: event.LifecycleBlocked}, nil)
+
+	router := setupTestRouter(handler, testAPIKey())
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/nfse/"+validAccessKey()+"/eventos/e305102/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockClient.AssertExpectations(t)
+	tracker.AssertExpectations(t)
+}
+
 // ================================================================================
 // GatewayTimeout Function Test
 // ================================================================================
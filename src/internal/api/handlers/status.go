@@ -15,6 +15,7 @@ import (
 
 	"github.com/eduardo/nfse-nacional/internal/domain/emission"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
 )
 
 // EmissionRepositoryReader defines the read operations needed by StatusHandler.
@@ -129,8 +130,13 @@ func (h *StatusHandler) Get(c *gin.Context) {
 			NFSeNumber:    emissionReq.Result.NFSeNumber,
 			NFSeXMLURL:    h.buildNFSeQueryURL(emissionReq.Result.NFSeAccessKey),
 		}
+		response.Lifecycle = jobs.NewLifecycleDTO(emissionReq.Lifecycle)
 	}
 
+	response.Taker = newTakerDTO(emissionReq.Taker)
+	response.ISSRate = newISSRateDTO(emissionReq.ISSRate)
+	response.TotalTaxes = newTotalTaxesDTO(emissionReq.TotalTaxes)
+
 	// Add error if failed
 	if emissionReq.Status == emission.StatusFailed && emissionReq.Rejection != nil {
 		response.Error = &emission.EmissionErrorDTO{
@@ -141,6 +147,11 @@ func (h *StatusHandler) Get(c *gin.Context) {
 		}
 	}
 
+	// Add the replaced NFS-e and its cancellation if this is a substitute
+	if emissionReq.Substitution != nil {
+		response.Substitution = jobs.NewSubstitutionDTO(emissionReq.Substitution)
+	}
+
 	// Log successful status query
 	h.logStatus(c, "status_query_success", map[string]interface{}{
 		"request_id":     requestID,
@@ -206,8 +217,13 @@ func (h *StatusHandler) List(c *gin.Context) {
 				NFSeNumber:    req.Result.NFSeNumber,
 				NFSeXMLURL:    h.buildNFSeQueryURL(req.Result.NFSeAccessKey),
 			}
+			item.Lifecycle = jobs.NewLifecycleDTO(req.Lifecycle)
 		}
 
+		item.Taker = newTakerDTO(req.Taker)
+		item.ISSRate = newISSRateDTO(req.ISSRate)
+		item.TotalTaxes = newTotalTaxesDTO(req.TotalTaxes)
+
 		// Add error if failed
 		if req.Status == emission.StatusFailed && req.Rejection != nil {
 			item.Error = &emission.EmissionErrorDTO{
@@ -274,6 +290,74 @@ func parseIntValue(s string, result *int64) (bool, error) {
 	return true, nil
 }
 
+// newISSRateDTO converts the ISS rate recorded for an emission into its API representation.
+func newISSRateDTO(issRate *mongodb.ISSRateData) *emission.ISSRateDTO {
+	if issRate == nil {
+		return nil
+	}
+
+	return &emission.ISSRateDTO{
+		Rate:             issRate.Rate,
+		Source:           issRate.Source,
+		MunicipalTaxCode: issRate.MunicipalTaxCode,
+		ConsultedAt:      issRate.ConsultedAt,
+	}
+}
+
+// newTotalTaxesDTO converts the approximate tax burden recorded for an emission into its API representation.
+func newTotalTaxesDTO(totalTaxes *mongodb.TotalTaxesData) *emission.TotalTaxesDTO {
+	if totalTaxes == nil {
+		return nil
+	}
+
+	return &emission.TotalTaxesDTO{
+		Source:          totalTaxes.Source,
+		State:           totalTaxes.State,
+		CodeType:        totalTaxes.CodeType,
+		Code:            totalTaxes.Code,
+		TableVersion:    totalTaxes.TableVersion,
+		TableSource:     totalTaxes.TableSource,
+		FederalRate:     totalTaxes.FederalRate,
+		StateRate:       totalTaxes.StateRate,
+		MunicipalRate:   totalTaxes.MunicipalRate,
+		FederalAmount:   totalTaxes.FederalAmount,
+		StateAmount:     totalTaxes.StateAmount,
+		MunicipalAmount: totalTaxes.MunicipalAmount,
+		SimplesRate:     totalTaxes.SimplesRate,
+	}
+}
+
+// newTakerDTO converts the taker recorded for an emission into its API representation.
+func newTakerDTO(taker *mongodb.TakerData) *emission.TakerDTO {
+	if taker == nil {
+		return nil
+	}
+
+	dto := &emission.TakerDTO{
+		CNPJ:  taker.CNPJ,
+		CPF:   taker.CPF,
+		NIF:   taker.NIF,
+		Name:  taker.Name,
+		Phone: taker.Phone,
+		Email: taker.Email,
+	}
+	if addr := taker.Address; addr != nil {
+		dto.Address = &emission.AddressDTO{
+			Street:           addr.Street,
+			Number:           addr.Number,
+			Complement:       addr.Complement,
+			Neighborhood:     addr.Neighborhood,
+			MunicipalityCode: addr.MunicipalityCode,
+			State:            addr.State,
+			PostalCode:       addr.PostalCode,
+			City:             addr.City,
+			Region:           addr.Region,
+			CountryCode:      addr.CountryCode,
+		}
+	}
+	return dto
+}
+
 // buildNFSeQueryURL constructs the URL to retrieve an NFS-e by its access key.
 // The URL points to GET /v1/nfse/{chaveAcesso} endpoint.
 func (h *StatusHandler) buildNFSeQueryURL(chaveAcesso string) string {
@@ -1362,3 +1362,239 @@ func TestStatusHandler_List_MixedStatuses(t *testing.T) {
 		}
 	}
 }
+
+// TestStatusHandler_Get_Substitution tests that a substitute emission reports the replaced NFS-e
+// and its cancellation by substitution.
+func TestStatusHandler_Get_Substitution(t *testing.T) {
+	testAPIKeyID := primitive.NewObjectID()
+	cancelledAt := time.Date(2026, 1, 8, 14, 30, 0, 0, time.UTC)
+
+	req := createTestEmissionRequest("req-subst", testAPIKeyID, emission.StatusSuccess)
+	req.Substitution = &mongodb.SubstitutionData{
+		ReplacedAccessKey:    "NFSe3550308202601081123456789012300000000000012310",
+		ReasonCode:           "99",
+		Reason:               "Correcao da descricao do servico",
+		CancellationSequence: 1,
+		CancelledAt:          cancelledAt,
+	}
+
+	mockRepo := &MockEmissionRepository{}
+	mockRepo.On("FindByRequestID", mock.Anything, "req-subst").Return(req, nil)
+
+	handler := NewStatusHandler(StatusHandlerConfig{
+		EmissionRepo: mockRepo,
+		BaseURL:      "https://api.example.com",
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/nfse/status/req-subst", nil)
+	c.Params = gin.Params{{Key: "requestId", Value: "req-subst"}}
+	setAPIKeyInContext(c, createTestAPIKey(testAPIKeyID))
+
+	handler.Get(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp emission.StatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Substitution)
+	assert.Equal(t, "NFSe3550308202601081123456789012300000000000012310", resp.Substitution.ReplacedAccessKey)
+	assert.Equal(t, "99", resp.Substitution.ReasonCode)
+	assert.Equal(t, emission.StatusSuccess, resp.Substitution.CancellationStatus)
+	assert.Equal(t, 1, resp.Substitution.CancellationSequence)
+	require.NotNil(t, resp.Substitution.CancelledAt)
+	assert.True(t, cancelledAt.Equal(*resp.Substitution.CancelledAt))
+}
+
+// TestStatusHandler_Get_Lifecycle tests that the NFS-e lifecycle is reported for successful emissions.
+func TestStatusHandler_Get_Lifecycle(t *testing.T) {
+	testAPIKeyID := primitive.NewObjectID()
+
+	updatedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	req := createTestEmissionRequest("req-lifecycle", testAPIKeyID, emission.StatusSuccess)
+	req.Lifecycle = &mongodb.LifecycleData{
+		Status:        "cancelled",
+		LastEventType: "e101101",
+		UpdatedAt:     updatedAt,
+		History: []mongodb.LifecycleTransition{
+			{
+				EventType:  "e101101",
+				Sequence:   1,
+				FromStatus: "active",
+				ToStatus:   "cancelled",
+				OccurredAt: updatedAt,
+				RecordedAt: updatedAt,
+				Source:     "registered",
+			},
+		},
+	}
+
+	mockRepo := &MockEmissionRepository{}
+	mockRepo.On("FindByRequestID", mock.Anything, "req-lifecycle").Return(req, nil)
+
+	handler := NewStatusHandler(StatusHandlerConfig{
+		EmissionRepo: mockRepo,
+		BaseURL:      "https://api.example.com",
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/nfse/status/req-lifecycle", nil)
+	c.Params = gin.Params{{Key: "requestId", Value: "req-lifecycle"}}
+	setAPIKeyInContext(c, createTestAPIKey(testAPIKeyID))
+
+	handler.Get(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp emission.StatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Lifecycle)
+	assert.Equal(t, "cancelled", resp.Lifecycle.Status)
+	assert.Equal(t, "e101101", resp.Lifecycle.LastEventType)
+	require.Len(t, resp.Lifecycle.History, 1)
+	assert.Equal(t, "active", resp.Lifecycle.History[0].FromStatus)
+	assert.Equal(t, "Cancelamento de NFS-e", resp.Lifecycle.History[0].Description)
+	assert.Equal(t, "registered", resp.Lifecycle.History[0].Source)
+}
+
+// TestStatusHandler_Get_ISSRate tests that the ISS rate used in the DPS is reported with its source.
+func TestStatusHandler_Get_ISSRate(t *testing.T) {
+	testAPIKeyID := primitive.NewObjectID()
+
+	consultedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	req := createTestEmissionRequest("req-iss-rate", testAPIKeyID, emission.StatusProcessing)
+	req.ISSRate = &mongodb.ISSRateData{
+		Rate:             2.5,
+		Source:           emission.ISSRateSourceMunicipalParameters,
+		MunicipalTaxCode: "001",
+		ConsultedAt:      &consultedAt,
+	}
+
+	mockRepo := &MockEmissionRepository{}
+	mockRepo.On("FindByRequestID", mock.Anything, "req-iss-rate").Return(req, nil)
+
+	handler := NewStatusHandler(StatusHandlerConfig{
+		EmissionRepo: mockRepo,
+		BaseURL:      "https://api.example.com",
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/nfse/status/req-iss-rate", nil)
+	c.Params = gin.Params{{Key: "requestId", Value: "req-iss-rate"}}
+	setAPIKeyInContext(c, createTestAPIKey(testAPIKeyID))
+
+	handler.Get(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp emission.StatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.ISSRate)
+	assert.Equal(t, 2.5, resp.ISSRate.Rate)
+	assert.Equal(t, emission.ISSRateSourceMunicipalParameters, resp.ISSRate.Source)
+	assert.Equal(t, "001", resp.ISSRate.MunicipalTaxCode)
+	require.NotNil(t, resp.ISSRate.ConsultedAt)
+	assert.True(t, consultedAt.Equal(*resp.ISSRate.ConsultedAt))
+}
+
+// TestStatusHandler_Get_TotalTaxes tests that the approximate tax burden is reported with its table version.
+func TestStatusHandler_Get_TotalTaxes(t *testing.T) {
+	testAPIKeyID := primitive.NewObjectID()
+
+	req := createTestEmissionRequest("req-total-taxes", testAPIKeyID, emission.StatusProcessing)
+	req.TotalTaxes = &mongodb.TotalTaxesData{
+		Source:          emission.TotalTaxesSourceTable,
+		State:           "SP",
+		CodeType:        "lc116",
+		Code:            "0101",
+		TableVersion:    "25.2.A",
+		TableSource:     "IBPT",
+		FederalRate:     13.45,
+		MunicipalRate:   2.90,
+		FederalAmount:   134.50,
+		MunicipalAmount: 29.00,
+	}
+
+	mockRepo := &MockEmissionRepository{}
+	mockRepo.On("FindByRequestID", mock.Anything, "req-total-taxes").Return(req, nil)
+
+	handler := NewStatusHandler(StatusHandlerConfig{
+		EmissionRepo: mockRepo,
+		BaseURL:      "https://api.example.com",
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/nfse/status/req-total-taxes", nil)
+	c.Params = gin.Params{{Key: "requestId", Value: "req-total-taxes"}}
+	setAPIKeyInContext(c, createTestAPIKey(testAPIKeyID))
+
+	handler.Get(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp emission.StatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.TotalTaxes)
+	assert.Equal(t, emission.TotalTaxesSourceTable, resp.TotalTaxes.Source)
+	assert.Equal(t, "25.2.A", resp.TotalTaxes.TableVersion)
+	assert.Equal(t, "IBPT", resp.TotalTaxes.TableSource)
+	assert.Equal(t, "0101", resp.TotalTaxes.Code)
+	assert.Equal(t, 134.50, resp.TotalTaxes.FederalAmount)
+	assert.Equal(t, 29.00, resp.TotalTaxes.MunicipalAmount)
+}
+
+// TestStatusHandler_Get_Taker tests that the full taker, including a foreign address, is reported.
+func TestStatusHandler_Get_Taker(t *testing.T) {
+	testAPIKeyID := primitive.NewObjectID()
+
+	req := createTestEmissionRequest("req-taker", testAPIKeyID, emission.StatusProcessing)
+	req.Taker = &mongodb.TakerData{
+		NIF:   "ES12345678A",
+		Name:  "Foreign Company",
+		Phone: "+34123456789",
+		Email: "billing@example.es",
+		Address: &mongodb.AddressData{
+			Street:       "Calle Mayor",
+			Number:       "10",
+			Neighborhood: "Centro",
+			PostalCode:   "28013",
+			City:         "Madrid",
+			Region:       "Comunidad de Madrid",
+			CountryCode:  "ES",
+		},
+	}
+
+	mockRepo := &MockEmissionRepository{}
+	mockRepo.On("FindByRequestID", mock.Anything, "req-taker").Return(req, nil)
+
+	handler := NewStatusHandler(StatusHandlerConfig{
+		EmissionRepo: mockRepo,
+		BaseURL:      "https://api.example.com",
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/nfse/status/req-taker", nil)
+	c.Params = gin.Params{{Key: "requestId", Value: "req-taker"}}
+	setAPIKeyInContext(c, createTestAPIKey(testAPIKeyID))
+
+	handler.Get(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp emission.StatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Taker)
+	assert.Equal(t, "ES12345678A", resp.Taker.NIF)
+	assert.Equal(t, "+34123456789", resp.Taker.Phone)
+	assert.Equal(t, "billing@example.es", resp.Taker.Email)
+	require.NotNil(t, resp.Taker.Address)
+	assert.Equal(t, "Madrid", resp.Taker.Address.City)
+	assert.Equal(t, "Comunidad de Madrid", resp.Taker.Address.Region)
+	assert.Equal(t, "28013", resp.Taker.Address.PostalCode)
+	assert.Equal(t, "ES", resp.Taker.Address.CountryCode)
+}
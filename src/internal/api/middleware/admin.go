@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/eduardo/nfse-nacional/internal/api/handlers"
+)
+
+// AdminKeyHeaderName is the HTTP header name for the admin key.
+const AdminKeyHeaderName = "X-Admin-Key"
+
+// AdminAuth returns a Gin middleware handler that authenticates operator requests
+// to the admin endpoints. It compares the X-Admin-Key header with the configured
+// key in constant time. Integrator API keys are not accepted.
+func AdminAuth(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(AdminKeyHeaderName))
+		if key == "" {
+			handlers.Unauthorized(c, "Missing admin key. Include the X-Admin-Key header in your request.")
+			c.Abort()
+			return
+		}
+
+		if adminKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) != 1 {
+			handlers.Unauthorized(c, "Invalid admin key.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
 	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/jobs"
 )
 
 // RouterConfig contains dependencies needed to configure the router.
@@ -31,6 +32,12 @@ type RouterConfig struct {
 	// EmissionRepo is the repository for emission requests.
 	EmissionRepo *mongodb.EmissionRepository
 
+	// EventRepo is the repository for event requests (cancellation, etc.).
+	EventRepo *mongodb.EventRepository
+
+	// DistributionRepo is the repository for ADN DF-e distribution cursors.
+	DistributionRepo *mongodb.DistributionRepository
+
 	// JobClient is the Asynq job client for enqueueing tasks.
 	JobClient *infraredis.JobClient
 
@@ -104,23 +111,63 @@ func NewRouter(cfg RouterConfig) *gin.Engine {
 	var statusHandler *handlers.StatusHandler
 	var queryHandler *handlers.QueryHandler
 	var dpsHandler *handlers.DPSHandler
+	var eventHandler *handlers.EventHandler
+	var distributionHandler *handlers.DistributionHandler
+	var inboxHandler *handlers.InboxHandler
+	var municipalityHandler *handlers.MunicipalityHandler
+
+	var cacheHandler *handlers.CacheHandler
+
+	// Cache municipal parameters shared by the emission checks and the municipality endpoints.
+	// With Redis the cache is shared with the worker and the other API instances.
+	var parametersCache *sefin.ParametersCache
+	if cfg.SefinClient != nil {
+		parametersConfig := sefin.ParametersCacheConfig{
+			Client:                   cfg.SefinClient,
+			ConvenioTTL:              cfg.Config.CacheConvenioTTL,
+			ServiceParametersTTL:     cfg.Config.CacheServiceParametersTTL,
+			ContributorParametersTTL: cfg.Config.CacheContributorParametersTTL,
+			StaleTTL:                 cfg.Config.CacheStaleTTL,
+			NegativeTTL:              cfg.Config.CacheNegativeTTL,
+		}
+		// Only set when available to avoid a non-nil interface holding a nil pointer
+		if cfg.RedisClient != nil {
+			parametersConfig.Store = infraredis.NewCacheStore(infraredis.CacheStoreConfig{
+				Client: cfg.RedisClient,
+			})
+		}
+		parametersCache = sefin.NewParametersCache(parametersConfig)
+		municipalityHandler = handlers.NewMunicipalityHandler(handlers.MunicipalityHandlerConfig{
+			Parameters: parametersCache,
+		})
+		cacheHandler = handlers.NewCacheHandler(handlers.CacheHandlerConfig{
+			Parameters: parametersCache,
+		})
+	}
 
 	if cfg.EmissionRepo != nil && cfg.JobClient != nil {
-		emissionHandler = handlers.NewEmissionHandler(handlers.EmissionHandlerConfig{
+		emissionConfig := handlers.EmissionHandlerConfig{
 			EmissionRepo: cfg.EmissionRepo,
 			JobClient:    cfg.JobClient,
 			BaseURL:      baseURL,
-		})
-
-		// Create emission XML handler for pre-signed XML submissions (Phase 5)
-		var err error
-		emissionXMLHandler, err = handlers.NewEmissionXMLHandler(handlers.EmissionXMLHandlerConfig{
+		}
+		emissionXMLConfig := handlers.EmissionXMLHandlerConfig{
 			EmissionRepo:        cfg.EmissionRepo,
 			JobClient:           cfg.JobClient,
 			BaseURL:             baseURL,
 			SchemaDir:           cfg.SchemaDir,
 			ValidateCertificate: cfg.ValidateCertificate,
-		})
+		}
+		// Only set when available to avoid a non-nil interface holding a nil pointer
+		if parametersCache != nil {
+			emissionConfig.Parameters = parametersCache
+			emissionXMLConfig.Parameters = parametersCache
+		}
+		emissionHandler = handlers.NewEmissionHandler(emissionConfig)
+
+		// Create emission XML handler for pre-signed XML submissions (Phase 5)
+		var err error
+		emissionXMLHandler, err = handlers.NewEmissionXMLHandler(emissionXMLConfig)
 		if err != nil {
 			// Log error but continue - pre-signed XML endpoint will not be available
 			fmt.Printf("Warning: Failed to create EmissionXMLHandler: %v\n", err)
@@ -134,12 +181,48 @@ func NewRouter(cfg RouterConfig) *gin.Engine {
 		})
 	}
 
+	// Create event handler for NFS-e events (cancellation, replacement and manifestation)
+	if cfg.EventRepo != nil && cfg.EmissionRepo != nil && cfg.JobClient != nil {
+		eventConfig := handlers.EventHandlerConfig{
+			EventRepo:    cfg.EventRepo,
+			EmissionRepo: cfg.EmissionRepo,
+			JobClient:    cfg.JobClient,
+			BaseURL:      baseURL,
+		}
+		// Only set when available to avoid a non-nil interface holding a nil pointer
+		if parametersCache != nil {
+			eventConfig.Parameters = parametersCache
+		}
+		eventHandler = handlers.NewEventHandler(eventConfig)
+	}
+
+	// Create distribution handler for registering CNPJs for ADN DF-e distribution
+	if cfg.DistributionRepo != nil && cfg.JobClient != nil {
+		distributionHandler = handlers.NewDistributionHandler(handlers.DistributionHandlerConfig{
+			Repo:      cfg.DistributionRepo,
+			JobClient: cfg.JobClient,
+			BaseURL:   baseURL,
+		})
+	}
+
+	// Create inbox handler for the NFS-e received through ADN DF-e distribution
+	if cfg.DistributionRepo != nil {
+		inboxHandler = handlers.NewInboxHandler(handlers.InboxHandlerConfig{
+			Repo: cfg.DistributionRepo,
+		})
+	}
+
 	// Create query and DPS handlers for NFS-e query operations (Phase 4 - Query API)
 	if cfg.SefinClient != nil {
-		queryHandler = handlers.NewQueryHandler(handlers.QueryHandlerConfig{
+		queryConfig := handlers.QueryHandlerConfig{
 			SefinClient: cfg.SefinClient,
 			BaseURL:     baseURL,
-		})
+		}
+		// Only set when available to avoid a non-nil interface holding a nil pointer
+		if cfg.EmissionRepo != nil {
+			queryConfig.Lifecycle = jobs.NewLifecycleTracker(cfg.EmissionRepo)
+		}
+		queryHandler = handlers.NewQueryHandler(queryConfig)
 
 		// Create DPS handler for DPS lookup operations (Phase 4 - User Story 2)
 		dpsHandler = handlers.NewDPSHandler(handlers.DPSHandlerConfig{
@@ -168,7 +251,14 @@ func NewRouter(cfg RouterConfig) *gin.Engine {
 		}
 
 		// Register v1 routes
-		registerV1Routes(v1, emissionHandler, emissionXMLHandler, statusHandler, queryHandler, dpsHandler)
+		registerV1Routes(v1, emissionHandler, emissionXMLHandler, statusHandler, queryHandler, dpsHandler, eventHandler, distributionHandler, inboxHandler, municipalityHandler)
+	}
+
+	// Admin routes (protected by the admin key, disabled when it is not configured)
+	if cfg.Config.AdminAPIKey != "" {
+		admin := router.Group("/admin")
+		admin.Use(middleware.AdminAuth(cfg.Config.AdminAPIKey))
+		registerAdminRoutes(admin, cacheHandler)
 	}
 
 	// Handle 404 for undefined routes
@@ -186,7 +276,7 @@ func NewRouter(cfg RouterConfig) *gin.Engine {
 
 // registerV1Routes registers all v1 API routes.
 // These routes are protected by authentication and rate limiting.
-func registerV1Routes(v1 *gin.RouterGroup, emissionHandler *handlers.EmissionHandler, emissionXMLHandler *handlers.EmissionXMLHandler, statusHandler *handlers.StatusHandler, queryHandler *handlers.QueryHandler, dpsHandler *handlers.DPSHandler) {
+func registerV1Routes(v1 *gin.RouterGroup, emissionHandler *handlers.EmissionHandler, emissionXMLHandler *handlers.EmissionXMLHandler, statusHandler *handlers.StatusHandler, queryHandler *handlers.QueryHandler, dpsHandler *handlers.DPSHandler, eventHandler *handlers.EventHandler, distributionHandler *handlers.DistributionHandler, inboxHandler *handlers.InboxHandler, municipalityHandler *handlers.MunicipalityHandler) {
 	// API info endpoint
 	v1.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -222,6 +312,10 @@ func registerV1Routes(v1 *gin.RouterGroup, emissionHandler *handlers.EmissionHan
 		// Allows integrators to retrieve events (cancellations, substitutions, etc.) for an NFS-e
 		// Supports optional filtering by event type via ?tipo=e101101 query parameter
 		v1.GET("/nfse/:chaveAcesso/eventos", queryHandler.GetEvents)
+
+		// Event-specific queries, forwarded to the national API without listing every event
+		v1.GET("/nfse/:chaveAcesso/eventos/:tipoEvento", queryHandler.GetEventsByType)
+		v1.GET("/nfse/:chaveAcesso/eventos/:tipoEvento/:numSeqEvento", queryHandler.GetEvent)
 	}
 
 	// DPS Lookup endpoints (Phase 4 - User Story 2: Lookup Access Key by DPS Identifier)
@@ -232,8 +326,52 @@ func registerV1Routes(v1 *gin.RouterGroup, emissionHandler *handlers.EmissionHan
 	}
 
 	// Event endpoints (Phase 5)
-	// v1.POST("/nfse/:key/cancel", eventHandler.Cancel)
-	// v1.POST("/nfse/:key/replace", eventHandler.Replace)
+	// Cancellation is registered asynchronously; the status of the event request
+	// is available at /v1/events/:requestId. Replacement emits a substitute NFS-e
+	// (tracked at /v1/nfse/status/:requestId) and then cancels the original (e105102).
+	// Manifest registers confirmations, rejections and rejection annulments
+	if eventHandler != nil {
+		v1.POST("/nfse/:chaveAcesso/cancel", eventHandler.Cancel)
+		v1.POST("/nfse/:chaveAcesso/replace", eventHandler.Replace)
+		v1.POST("/nfse/:chaveAcesso/manifest", eventHandler.Manifest)
+		v1.GET("/events/:requestId", eventHandler.GetStatus)
+	}
+
+	// Distribution endpoints
+	// Registers CNPJs whose NFS-e and events are downloaded from the ADN.
+	// Documents are synced asynchronously from the stored NSU cursor
+	if distributionHandler != nil {
+		v1.POST("/distribution/cnpjs", distributionHandler.Register)
+		v1.GET("/distribution/cnpjs/:cnpj", distributionHandler.Get)
+		v1.POST("/distribution/cnpjs/:cnpj/sync", distributionHandler.Sync)
+	}
+
+	// Inbox endpoints
+	// Lists the NFS-e received by the registered CNPJs from the locally synced
+	// documents, in the same format as the NFS-e query endpoint
+	if inboxHandler != nil {
+		v1.GET("/inbox/nfse", inboxHandler.List)
+		v1.GET("/inbox/nfse/:chaveAcesso", inboxHandler.Get)
+	}
+
+	// Municipality endpoints
+	// Exposes the municipal parameters so integrators can check whether NFS-e
+	// can be emitted in a municipality before onboarding
+	if municipalityHandler != nil {
+		v1.GET("/municipios/:codigo/convenio", municipalityHandler.GetConvenio)
+	}
+}
+
+// registerAdminRoutes registers the operator routes.
+// These routes are protected by the admin key instead of integrator API keys.
+func registerAdminRoutes(admin *gin.RouterGroup, cacheHandler *handlers.CacheHandler) {
+	// Cache endpoints
+	// Invalidates cached government reference data, so changes published by a
+	// municipality are picked up before the cached entries expire
+	if cacheHandler != nil {
+		admin.DELETE("/cache/parametros_municipais", cacheHandler.InvalidateParameters)
+		admin.DELETE("/cache/parametros_municipais/:codigo", cacheHandler.InvalidateParameters)
+	}
 }
 
 // NewRouterSimple creates a minimal router for testing or simple deployments.
@@ -36,10 +36,25 @@ type Config struct {
 	WorkerConcurrency int
 	WorkerMaxRetries  int
 
+	// DF-e distribution polling configuration
+	DistributionPollInterval time.Duration
+	DistributionIdleBackoff  time.Duration
+	DistributionMaxBatches   int
+
+	// Municipal parameters cache configuration
+	CacheConvenioTTL              time.Duration
+	CacheServiceParametersTTL     time.Duration
+	CacheContributorParametersTTL time.Duration
+	CacheStaleTTL                 time.Duration
+	CacheNegativeTTL              time.Duration
+
 	// Rate limiting configuration
 	RateLimitDefaultRPM int
 	RateLimitBurst      int
 
+	// Admin configuration (admin endpoints are disabled when the key is empty)
+	AdminAPIKey string
+
 	// Certificate configuration
 	CertPath     string
 	CertPassword string
@@ -76,10 +91,25 @@ func Load() (*Config, error) {
 		WorkerConcurrency: getEnvOrDefaultInt("WORKER_CONCURRENCY", 10),
 		WorkerMaxRetries:  getEnvOrDefaultInt("WORKER_MAX_RETRIES", 3),
 
+		// DF-e distribution polling defaults (intervals in minutes)
+		DistributionPollInterval: time.Duration(getEnvOrDefaultInt("DISTRIBUTION_POLL_INTERVAL", 15)) * time.Minute,
+		DistributionIdleBackoff:  time.Duration(getEnvOrDefaultInt("DISTRIBUTION_IDLE_BACKOFF", 60)) * time.Minute,
+		DistributionMaxBatches:   getEnvOrDefaultInt("DISTRIBUTION_MAX_BATCHES", 20),
+
+		// Municipal parameters cache defaults (TTLs in minutes)
+		CacheConvenioTTL:              time.Duration(getEnvOrDefaultInt("CACHE_CONVENIO_TTL", 1440)) * time.Minute,
+		CacheServiceParametersTTL:     time.Duration(getEnvOrDefaultInt("CACHE_SERVICE_PARAMETERS_TTL", 1440)) * time.Minute,
+		CacheContributorParametersTTL: time.Duration(getEnvOrDefaultInt("CACHE_CONTRIBUTOR_PARAMETERS_TTL", 360)) * time.Minute,
+		CacheStaleTTL:                 time.Duration(getEnvOrDefaultInt("CACHE_STALE_TTL", 60)) * time.Minute,
+		CacheNegativeTTL:              time.Duration(getEnvOrDefaultInt("CACHE_NEGATIVE_TTL", 30)) * time.Minute,
+
 		// Rate limiting defaults
 		RateLimitDefaultRPM: getEnvOrDefaultInt("RATE_LIMIT_DEFAULT_RPM", 100),
 		RateLimitBurst:      getEnvOrDefaultInt("RATE_LIMIT_BURST", 20),
 
+		// Admin configuration
+		AdminAPIKey: getEnvOrDefault("ADMIN_API_KEY", ""),
+
 		// Certificate configuration
 		CertPath:     getEnvOrDefault("CERT_PATH", ""),
 		CertPassword: getEnvOrDefault("CERT_PASSWORD", ""),
@@ -126,6 +156,32 @@ func (c *Config) validate() error {
 		return fmt.Errorf("WORKER_CONCURRENCY must be at least 1")
 	}
 
+	if c.DistributionPollInterval < time.Minute {
+		return fmt.Errorf("DISTRIBUTION_POLL_INTERVAL must be at least 1")
+	}
+
+	// The ADN requires waiting at least one hour after no more documents are available
+	if c.DistributionIdleBackoff < time.Hour {
+		return fmt.Errorf("DISTRIBUTION_IDLE_BACKOFF must be at least 60")
+	}
+
+	if c.DistributionMaxBatches < 1 {
+		return fmt.Errorf("DISTRIBUTION_MAX_BATCHES must be at least 1")
+	}
+
+	cacheTTLs := map[string]time.Duration{
+		"CACHE_CONVENIO_TTL":               c.CacheConvenioTTL,
+		"CACHE_SERVICE_PARAMETERS_TTL":     c.CacheServiceParametersTTL,
+		"CACHE_CONTRIBUTOR_PARAMETERS_TTL": c.CacheContributorParametersTTL,
+		"CACHE_STALE_TTL":                  c.CacheStaleTTL,
+		"CACHE_NEGATIVE_TTL":               c.CacheNegativeTTL,
+	}
+	for name, ttl := range cacheTTLs {
+		if ttl < time.Minute {
+			return fmt.Errorf("%s must be at least 1", name)
+		}
+	}
+
 	if c.RateLimitDefaultRPM < 1 {
 		return fmt.Errorf("RATE_LIMIT_DEFAULT_RPM must be at least 1")
 	}
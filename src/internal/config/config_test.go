@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -18,6 +19,9 @@ func TestLoad(t *testing.T) {
 		"SEFIN_ENVIRONMENT":      os.Getenv("SEFIN_ENVIRONMENT"),
 		"WORKER_CONCURRENCY":     os.Getenv("WORKER_CONCURRENCY"),
 		"RATE_LIMIT_DEFAULT_RPM": os.Getenv("RATE_LIMIT_DEFAULT_RPM"),
+
+		"DISTRIBUTION_IDLE_BACKOFF": os.Getenv("DISTRIBUTION_IDLE_BACKOFF"),
+		"CACHE_NEGATIVE_TTL":        os.Getenv("CACHE_NEGATIVE_TTL"),
 	}
 
 	// Restore environment after test
@@ -61,6 +65,18 @@ func TestLoad(t *testing.T) {
 		if cfg.RateLimitDefaultRPM != 100 {
 			t.Errorf("RateLimitDefaultRPM = %d, want %d", cfg.RateLimitDefaultRPM, 100)
 		}
+		if cfg.DistributionPollInterval != 15*time.Minute {
+			t.Errorf("DistributionPollInterval = %v, want %v", cfg.DistributionPollInterval, 15*time.Minute)
+		}
+		if cfg.DistributionIdleBackoff != time.Hour {
+			t.Errorf("DistributionIdleBackoff = %v, want %v", cfg.DistributionIdleBackoff, time.Hour)
+		}
+		if cfg.CacheContributorParametersTTL != 6*time.Hour {
+			t.Errorf("CacheContributorParametersTTL = %v, want %v", cfg.CacheContributorParametersTTL, 6*time.Hour)
+		}
+		if cfg.CacheNegativeTTL != 30*time.Minute {
+			t.Errorf("CacheNegativeTTL = %v, want %v", cfg.CacheNegativeTTL, 30*time.Minute)
+		}
 	})
 
 	t.Run("loads from environment", func(t *testing.T) {
@@ -107,6 +123,30 @@ func TestLoad(t *testing.T) {
 		}
 	})
 
+	t.Run("validates distribution idle backoff", func(t *testing.T) {
+		os.Setenv("ENV", "development")
+		os.Setenv("LOG_LEVEL", "info")
+		os.Setenv("DISTRIBUTION_IDLE_BACKOFF", "30")
+		defer os.Unsetenv("DISTRIBUTION_IDLE_BACKOFF")
+
+		_, err := Load()
+		if err == nil {
+			t.Error("Load() expected error for DISTRIBUTION_IDLE_BACKOFF below one hour")
+		}
+	})
+
+	t.Run("validates cache TTLs", func(t *testing.T) {
+		os.Setenv("ENV", "development")
+		os.Setenv("LOG_LEVEL", "info")
+		os.Setenv("CACHE_NEGATIVE_TTL", "0")
+		defer os.Unsetenv("CACHE_NEGATIVE_TTL")
+
+		_, err := Load()
+		if err == nil {
+			t.Error("Load() expected error for CACHE_NEGATIVE_TTL below one minute")
+		}
+	})
+
 	t.Run("validates SEFIN environment", func(t *testing.T) {
 		os.Setenv("ENV", "development")
 		os.Setenv("LOG_LEVEL", "info")
@@ -0,0 +1,67 @@
+// Package distribution provides DTOs for the ADN DF-e distribution, which
+// delivers every NFS-e and event in which a registered CNPJ is an actor.
+package distribution
+
+import (
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// RegistrationRequest represents a request to register a CNPJ for DF-e distribution.
+type RegistrationRequest struct {
+	// CNPJ is the CNPJ whose documents will be downloaded.
+	CNPJ string `json:"cnpj" binding:"required"`
+
+	// Certificate is the CNPJ's certificate, used for mTLS with the ADN.
+	// It is kept while the registration is active.
+	Certificate *emission.CertificateRequest `json:"certificate,omitempty"`
+
+	// WebhookURL is an optional override for the webhook URL configured in the API key.
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// InboxQuery contains the query parameters for listing the NFS-e received
+// through DF-e distribution. All filters are optional.
+type InboxQuery struct {
+	// CNPJ restricts the list to the NFS-e received by one registered CNPJ.
+	CNPJ string `form:"cnpj"`
+
+	// IssuerCNPJ is the CNPJ of the service provider that issued the NFS-e.
+	IssuerCNPJ string `form:"issuer_cnpj"`
+
+	// Competence is a competence month (YYYY-MM). It cannot be combined with
+	// CompetenceFrom and CompetenceTo.
+	Competence string `form:"competence"`
+
+	// CompetenceFrom is the first competence date of the period (YYYY-MM-DD).
+	CompetenceFrom string `form:"competence_from"`
+
+	// CompetenceTo is the last competence date of the period (YYYY-MM-DD).
+	CompetenceTo string `form:"competence_to"`
+
+	// MinValue is the minimum gross service value.
+	MinValue string `form:"min_value"`
+
+	// MaxValue is the maximum gross service value.
+	MaxValue string `form:"max_value"`
+
+	// ManifestationStatus is the status of the NFS-e derived from the received
+	// events (active, confirmed, rejected, cancelled, ...).
+	ManifestationStatus string `form:"manifestation_status"`
+
+	// Municipality is the 7-digit IBGE code of the service location.
+	Municipality string `form:"municipality"`
+}
+
+// InboxFilter contains the parsed filters of an InboxQuery.
+type InboxFilter struct {
+	CNPJ                string
+	IssuerCNPJ          string
+	CompetenceFrom      *time.Time
+	CompetenceTo        *time.Time
+	MinValue            *float64
+	MaxValue            *float64
+	ManifestationStatus string
+	Municipality        string
+}
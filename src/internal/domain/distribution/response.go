@@ -0,0 +1,116 @@
+package distribution
+
+import (
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/query"
+)
+
+// CursorResponse represents the distribution state of a registered CNPJ.
+type CursorResponse struct {
+	// CNPJ is the registered CNPJ.
+	CNPJ string `json:"cnpj"`
+
+	// Active indicates whether the CNPJ is being synced.
+	Active bool `json:"active"`
+
+	// LastNSU is the greatest NSU already downloaded (ultNSU).
+	LastNSU int64 `json:"last_nsu"`
+
+	// LastSyncAt is when the last sync finished, if any.
+	LastSyncAt *time.Time `json:"last_sync_at,omitempty"`
+
+	// LastError is the error of the last sync, if it failed.
+	LastError string `json:"last_error,omitempty"`
+
+	// NextSyncAt is the earliest time the CNPJ will be synced again, if the
+	// ADN reported no more documents.
+	NextSyncAt *time.Time `json:"next_sync_at,omitempty"`
+
+	// CreatedAt is when the CNPJ was registered.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the registration was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SyncAccepted represents the response when a sync is queued (202).
+type SyncAccepted struct {
+	// CNPJ is the CNPJ being synced.
+	CNPJ string `json:"cnpj"`
+
+	// Message provides additional context about the request.
+	Message string `json:"message"`
+
+	// StatusURL is the URL to poll for the distribution state.
+	StatusURL string `json:"status_url"`
+}
+
+// WebhookPayload represents the payload sent to webhook endpoints when a
+// document is received through DF-e distribution.
+type WebhookPayload struct {
+	// Event indicates the type of webhook event.
+	Event string `json:"event"`
+
+	// Timestamp is when this webhook was generated.
+	Timestamp time.Time `json:"timestamp"`
+
+	// CNPJ is the registered CNPJ that received the document.
+	CNPJ string `json:"cnpj"`
+
+	// NSU is the sequential number of the document for the CNPJ.
+	NSU int64 `json:"nsu"`
+
+	// ChaveAcesso is the access key of the NFS-e the document refers to.
+	ChaveAcesso string `json:"chave_acesso"`
+
+	// EventType is the event type code (only for event.received).
+	EventType string `json:"event_type,omitempty"`
+
+	// GeneratedAt is when the document was generated in the ADN.
+	GeneratedAt *time.Time `json:"generated_at,omitempty"`
+
+	// SignatureValid indicates whether the document signature was verified.
+	SignatureValid bool `json:"signature_valid"`
+
+	// XML is the document XML.
+	XML string `json:"xml"`
+}
+
+// WebhookEvent constants define the types of webhook events for received documents.
+const (
+	// WebhookEventNFSeReceived indicates an NFS-e was received through distribution.
+	WebhookEventNFSeReceived = "nfse.received"
+
+	// WebhookEventEventReceived indicates an NFS-e event was received through distribution.
+	WebhookEventEventReceived = "event.received"
+)
+
+// InboxNFSeResponse represents an NFS-e received through DF-e distribution.
+// It has the same fields as the NFS-e query response (GET /v1/nfse/{chaveAcesso}),
+// plus the inbox data of the received document.
+type InboxNFSeResponse struct {
+	*query.NFSeQueryResponse
+
+	// Inbox contains how and when the NFS-e was received.
+	Inbox InboxInfo `json:"inbox"`
+}
+
+// InboxInfo contains the distribution data of a received NFS-e.
+type InboxInfo struct {
+	// CNPJ is the registered CNPJ that received the NFS-e.
+	CNPJ string `json:"cnpj"`
+
+	// NSU is the sequential number of the NFS-e for the CNPJ.
+	NSU int64 `json:"nsu"`
+
+	// ReceivedAt is when the NFS-e was downloaded from the ADN.
+	ReceivedAt time.Time `json:"received_at"`
+
+	// ManifestationStatus is the status derived from the events received for
+	// the NFS-e (active, confirmed, rejected, cancelled, ...).
+	ManifestationStatus string `json:"manifestation_status,omitempty"`
+
+	// SignatureValid indicates whether the NFS-e signature was verified.
+	SignatureValid bool `json:"signature_valid"`
+}
@@ -15,6 +15,9 @@ const (
 	ErrCodeInvalidDeduction    = "INVALID_DEDUCTION"
 	ErrCodeDeductionExceedsValue = "DEDUCTION_EXCEEDS_VALUE"
 	ErrCodeNegativeTaxBase     = "NEGATIVE_TAX_BASE"
+	ErrCodeInvalidFederalTax   = "INVALID_FEDERAL_TAX"
+	ErrCodeWithholdingExceedsValue = "WITHHOLDING_EXCEEDS_VALUE"
+	ErrCodeInvalidBenefitReduction = "INVALID_BENEFIT_REDUCTION"
 )
 
 // CalculationError represents an error that occurred during value calculation.
@@ -64,6 +67,47 @@ type CalculationInput struct {
 	// ISSRate is the ISS tax rate as a percentage (e.g., 2.0 for 2%).
 	// Can be 0 for SIMPLES NACIONAL MEI providers.
 	ISSRate float64
+
+	// BenefitReductionPercentage is the tax base reduction percentage of a municipal benefit (pRedBCBM).
+	// Mutually exclusive with BenefitReductionValue.
+	BenefitReductionPercentage float64
+
+	// BenefitReductionValue is the tax base reduction value of a municipal benefit (vRedBCBM).
+	// Mutually exclusive with BenefitReductionPercentage.
+	BenefitReductionValue float64
+
+	// ISSWithheld indicates that the taker or the intermediary withholds the ISS (tpRetISSQN 2 or 3).
+	ISSWithheld bool
+
+	// FederalTaxes are the federal tax rates (tribFed). Optional.
+	FederalTaxes *FederalTaxInput
+}
+
+// FederalTaxInput contains the federal tax rates as percentages.
+// CP, IRRF and CSLL are always withheld by the taker; PIS and COFINS only when
+// PisCofinsWithheld is set.
+type FederalTaxInput struct {
+	// PisCofinsTaxBase is the PIS/COFINS tax base (vBCPisCofins).
+	// If set to 0, the withholding base is used.
+	PisCofinsTaxBase float64
+
+	// PISRate is the PIS rate percentage (pAliqPis).
+	PISRate float64
+
+	// COFINSRate is the COFINS rate percentage (pAliqCofins).
+	COFINSRate float64
+
+	// PisCofinsWithheld indicates that the taker withholds PIS and COFINS.
+	PisCofinsWithheld bool
+
+	// CPRate is the withheld social security contribution rate (vRetCP).
+	CPRate float64
+
+	// IRRFRate is the withheld income tax rate (vRetIRRF).
+	IRRFRate float64
+
+	// CSLLRate is the withheld social contribution on net profit rate (vRetCSLL).
+	CSLLRate float64
 }
 
 // CalculationResult contains the results of the tax base calculation.
@@ -89,15 +133,50 @@ type CalculationResult struct {
 	// Note: ConditionalDiscount does NOT reduce the tax base.
 	TaxBase float64
 
+	// BenefitReduction is the tax base reduction of the municipal benefit (vCalcBM).
+	BenefitReduction float64
+
+	// ISSTaxBase is the ISS tax base after the municipal benefit (vBC).
+	// Calculated as TaxBase - BenefitReduction.
+	ISSTaxBase float64
+
 	// ISSRate is the ISS tax rate percentage (pAliq).
 	ISSRate float64
 
 	// ISSAmount is the calculated ISS tax amount (vISS).
-	// Calculated as TaxBase * ISSRate / 100.
+	// Calculated as ISSTaxBase * ISSRate / 100.
 	ISSAmount float64
 
-	// NetValue is the final value after all discounts (for reference only).
-	// Calculated as ServiceValue - UnconditionalDiscount - ConditionalDiscount - Deductions.
+	// ISSWithheld indicates that the ISS amount is withheld and included in TotalWithheld.
+	ISSWithheld bool
+
+	// WithholdingBase is the base of the federal taxes (vServ - vDescIncond).
+	WithholdingBase float64
+
+	// PisCofinsTaxBase is the PIS/COFINS tax base (vBCPisCofins).
+	PisCofinsTaxBase float64
+
+	// PISAmount is the calculated PIS amount (vPis).
+	PISAmount float64
+
+	// COFINSAmount is the calculated COFINS amount (vCofins).
+	COFINSAmount float64
+
+	// CPWithheld is the withheld social security contribution (vRetCP).
+	CPWithheld float64
+
+	// IRRFWithheld is the withheld income tax (vRetIRRF).
+	IRRFWithheld float64
+
+	// CSLLWithheld is the withheld social contribution on net profit (vRetCSLL).
+	CSLLWithheld float64
+
+	// TotalWithheld is the total withheld by the taker (vTotalRet):
+	// CPWithheld + IRRFWithheld + CSLLWithheld, plus ISSAmount and PISAmount + COFINSAmount when withheld.
+	TotalWithheld float64
+
+	// NetValue is the net value paid by the taker (vLiq).
+	// Calculated as ServiceValue - UnconditionalDiscount - ConditionalDiscount - TotalWithheld.
 	NetValue float64
 }
 
@@ -105,7 +184,8 @@ type CalculationResult struct {
 // It implements the Brazilian NFS-e calculation rules where:
 // - Tax Base (vBCCalc) = Service Value - Unconditional Discount - Deductions
 // - Conditional Discount does NOT affect the tax base
-// - ISS Amount (vISS) = Tax Base * ISS Rate / 100
+// - ISS Amount (vISS) = (Tax Base - Municipal Benefit Reduction) * ISS Rate / 100
+// - Net Value (vLiq) = Service Value - Discounts - Total Withheld (vTotalRet)
 type ValueCalculator struct{}
 
 // NewValueCalculator creates a new ValueCalculator.
@@ -147,6 +227,11 @@ func (c *ValueCalculator) Calculate(input *CalculationInput) (*CalculationResult
 		return nil, err
 	}
 
+	// Validate federal tax rates
+	if err := c.validateFederalTaxes(input.FederalTaxes); err != nil {
+		return nil, err
+	}
+
 	// Validate that unconditional discount + deductions don't exceed service value
 	// (this would result in a negative tax base)
 	taxBaseDeductions := input.UnconditionalDiscount + input.Deductions
@@ -177,14 +262,39 @@ func (c *ValueCalculator) Calculate(input *CalculationInput) (*CalculationResult
 		deductionPercentage = roundToTwoDecimals((input.Deductions / input.ServiceValue) * 100)
 	}
 
-	// Calculate ISS amount: vISS = vBCCalc * pAliq / 100
+	// Calculate municipal benefit reduction: vCalcBM = vRedBCBM or vBCCalc * pRedBCBM / 100
+	benefitReduction, err := c.calculateBenefitReduction(taxBase, input)
+	if err != nil {
+		return nil, err
+	}
+	issTaxBase := roundToTwoDecimals(taxBase - benefitReduction)
+
+	// Calculate ISS amount: vISS = vBC * pAliq / 100
 	var issAmount float64
 	if input.ISSRate > 0 {
-		issAmount = roundToTwoDecimals(taxBase * input.ISSRate / 100)
+		issAmount = roundToTwoDecimals(issTaxBase * input.ISSRate / 100)
 	}
 
-	// Calculate net value (for reference): vServ - vDescIncond - vDescCond - vDedRed
-	netValue := roundToTwoDecimals(input.ServiceValue - input.UnconditionalDiscount - input.ConditionalDiscount - input.Deductions)
+	// Calculate federal taxes on the withholding base: vServ - vDescIncond
+	withholdingBase := roundToTwoDecimals(input.ServiceValue - input.UnconditionalDiscount)
+	federal := calculateFederalTaxes(withholdingBase, input.FederalTaxes)
+
+	// Withheld ISS is part of the total withheld
+	totalWithheld := federal.totalWithheld
+	if input.ISSWithheld {
+		totalWithheld = roundToTwoDecimals(totalWithheld + issAmount)
+	}
+
+	// Calculate net value: vLiq = vServ - vDescIncond - vDescCond - vTotalRet
+	netValue := roundToTwoDecimals(input.ServiceValue - input.UnconditionalDiscount - input.ConditionalDiscount - totalWithheld)
+	if netValue < 0 {
+		return nil, NewCalculationError(
+			ErrCodeWithholdingExceedsValue,
+			"federal_taxes",
+			fmt.Sprintf("withheld taxes (%.2f) cannot exceed the service value after discounts (%.2f)",
+				totalWithheld, input.ServiceValue-input.UnconditionalDiscount-input.ConditionalDiscount),
+		)
+	}
 
 	return &CalculationResult{
 		ServiceValue:          roundToTwoDecimals(input.ServiceValue),
@@ -193,12 +303,128 @@ func (c *ValueCalculator) Calculate(input *CalculationInput) (*CalculationResult
 		Deductions:            roundToTwoDecimals(input.Deductions),
 		DeductionPercentage:   deductionPercentage,
 		TaxBase:               taxBase,
+		BenefitReduction:      benefitReduction,
+		ISSTaxBase:            issTaxBase,
 		ISSRate:               roundToTwoDecimals(input.ISSRate),
 		ISSAmount:             issAmount,
+		ISSWithheld:           input.ISSWithheld,
+		WithholdingBase:       withholdingBase,
+		PisCofinsTaxBase:      federal.pisCofinsTaxBase,
+		PISAmount:             federal.pisAmount,
+		COFINSAmount:          federal.cofinsAmount,
+		CPWithheld:            federal.cpWithheld,
+		IRRFWithheld:          federal.irrfWithheld,
+		CSLLWithheld:          federal.csllWithheld,
+		TotalWithheld:         totalWithheld,
 		NetValue:              netValue,
 	}, nil
 }
 
+// calculateBenefitReduction calculates the tax base reduction of a municipal benefit (vCalcBM).
+func (c *ValueCalculator) calculateBenefitReduction(taxBase float64, input *CalculationInput) (float64, error) {
+	percentage, value := input.BenefitReductionPercentage, input.BenefitReductionValue
+
+	if percentage != 0 && value != 0 {
+		return 0, NewCalculationError(
+			ErrCodeInvalidBenefitReduction,
+			"municipal_benefit",
+			"benefit reduction percentage and value are mutually exclusive",
+		)
+	}
+
+	if percentage < 0 || percentage > 100 || !hasValidDecimalPrecision(percentage) {
+		return 0, NewCalculationError(
+			ErrCodeInvalidBenefitReduction,
+			"municipal_benefit.reduction_percentage",
+			"benefit reduction percentage must be between 0 and 100 with at most 2 decimal places",
+		)
+	}
+
+	if value < 0 || value > taxBase || !hasValidDecimalPrecision(value) {
+		return 0, NewCalculationError(
+			ErrCodeInvalidBenefitReduction,
+			"municipal_benefit.reduction_value",
+			fmt.Sprintf("benefit reduction value must be between 0 and the tax base (%.2f) with at most 2 decimal places", taxBase),
+		)
+	}
+
+	if value > 0 {
+		return roundToTwoDecimals(value), nil
+	}
+	return roundToTwoDecimals(taxBase * percentage / 100), nil
+}
+
+// federalTaxAmounts contains the federal tax amounts calculated from a FederalTaxInput.
+type federalTaxAmounts struct {
+	pisCofinsTaxBase float64
+	pisAmount        float64
+	cofinsAmount     float64
+	cpWithheld       float64
+	irrfWithheld     float64
+	csllWithheld     float64
+	totalWithheld    float64
+}
+
+// calculateFederalTaxes calculates the federal tax amounts on the withholding base.
+// Each amount is rounded before being added to the total, as in the NFS-e.
+func calculateFederalTaxes(withholdingBase float64, input *FederalTaxInput) federalTaxAmounts {
+	var amounts federalTaxAmounts
+	if input == nil {
+		return amounts
+	}
+
+	amounts.pisCofinsTaxBase = roundToTwoDecimals(withholdingBase)
+	if input.PisCofinsTaxBase > 0 {
+		amounts.pisCofinsTaxBase = roundToTwoDecimals(input.PisCofinsTaxBase)
+	}
+	amounts.pisAmount = roundToTwoDecimals(amounts.pisCofinsTaxBase * input.PISRate / 100)
+	amounts.cofinsAmount = roundToTwoDecimals(amounts.pisCofinsTaxBase * input.COFINSRate / 100)
+
+	amounts.cpWithheld = roundToTwoDecimals(withholdingBase * input.CPRate / 100)
+	amounts.irrfWithheld = roundToTwoDecimals(withholdingBase * input.IRRFRate / 100)
+	amounts.csllWithheld = roundToTwoDecimals(withholdingBase * input.CSLLRate / 100)
+
+	// vTotalRet = (vRetCP + vRetIRRF + vRetCSLL) + (vPis + vCofins, if withheld)
+	total := amounts.cpWithheld + amounts.irrfWithheld + amounts.csllWithheld
+	if input.PisCofinsWithheld {
+		total += amounts.pisAmount + amounts.cofinsAmount
+	}
+	amounts.totalWithheld = roundToTwoDecimals(total)
+
+	return amounts
+}
+
+// applyMunicipalTaxInput fills the municipal benefit reduction and the ISS withholding of a request.
+func applyMunicipalTaxInput(input *CalculationInput, values *ValuesRequest) {
+	if values.MunicipalBenefit != nil {
+		input.BenefitReductionPercentage = values.MunicipalBenefit.ReductionPercentage
+		input.BenefitReductionValue = values.MunicipalBenefit.ReductionValue
+	}
+	input.ISSWithheld = values.ISS.WithholdingOrDefault() != ISSWithholdingNone
+}
+
+// newFederalTaxInput converts the federal taxes of a request into calculator input.
+// Returns nil when no federal taxes were provided.
+func newFederalTaxInput(taxes *FederalTaxesRequest) *FederalTaxInput {
+	if taxes == nil {
+		return nil
+	}
+
+	input := &FederalTaxInput{
+		CPRate:   taxes.CPRate,
+		IRRFRate: taxes.IRRFRate,
+		CSLLRate: taxes.CSLLRate,
+	}
+	if taxes.PisCofins != nil {
+		input.PisCofinsTaxBase = taxes.PisCofins.TaxBase
+		input.PISRate = taxes.PisCofins.PISRate
+		input.COFINSRate = taxes.PisCofins.COFINSRate
+		input.PisCofinsWithheld = taxes.PisCofins.Withheld
+	}
+
+	return input
+}
+
 // validateServiceValue validates that the service value is positive and has valid precision.
 func (c *ValueCalculator) validateServiceValue(value float64) error {
 	if value <= 0 {
@@ -318,6 +544,46 @@ func (c *ValueCalculator) validateDeductions(serviceValue, deductions float64) e
 	return nil
 }
 
+// validateFederalTaxes validates the federal tax rates and the PIS/COFINS tax base.
+func (c *ValueCalculator) validateFederalTaxes(input *FederalTaxInput) error {
+	if input == nil {
+		return nil
+	}
+
+	rates := []struct {
+		field string
+		rate  float64
+	}{
+		{"federal_taxes.pis_cofins.pis_rate", input.PISRate},
+		{"federal_taxes.pis_cofins.cofins_rate", input.COFINSRate},
+		{"federal_taxes.cp_rate", input.CPRate},
+		{"federal_taxes.irrf_rate", input.IRRFRate},
+		{"federal_taxes.csll_rate", input.CSLLRate},
+	}
+	for _, r := range rates {
+		if r.rate < 0 || r.rate > MaxFederalTaxRate || !hasValidDecimalPrecision(r.rate) {
+			return NewCalculationError(
+				ErrCodeInvalidFederalTax,
+				r.field,
+				fmt.Sprintf("rate must be between 0 and %.2f with at most 2 decimal places", MaxFederalTaxRate),
+			)
+		}
+	}
+
+	if input.PisCofinsTaxBase < 0 || !hasValidDecimalPrecision(input.PisCofinsTaxBase) {
+		return NewCalculationError(
+			ErrCodeInvalidFederalTax,
+			"federal_taxes.pis_cofins.tax_base",
+			"PIS/COFINS tax base cannot be negative and must have at most 2 decimal places",
+		)
+	}
+
+	return nil
+}
+
+// MaxFederalTaxRate is the maximum federal tax rate percentage (99.99, as in pAliqPis and pAliqCofins).
+const MaxFederalTaxRate = 99.99
+
 // MaxServiceValue is the maximum allowed service value (999,999,999.99).
 const MaxServiceValue = 999999999.99
 
@@ -350,7 +616,9 @@ func (c *ValueCalculator) CalculateFromRequest(values *ValuesRequest) (*Calculat
 		ConditionalDiscount:   values.ConditionalDiscount,
 		Deductions:            values.Deductions,
 		ISSRate:               0, // Default for MEI
+		FederalTaxes:          newFederalTaxInput(values.FederalTaxes),
 	}
+	applyMunicipalTaxInput(input, values)
 
 	return c.Calculate(input)
 }
@@ -368,7 +636,9 @@ func (c *ValueCalculator) CalculateFromRequestWithRate(values *ValuesRequest, is
 		ConditionalDiscount:   values.ConditionalDiscount,
 		Deductions:            values.Deductions,
 		ISSRate:               issRate,
+		FederalTaxes:          newFederalTaxInput(values.FederalTaxes),
 	}
+	applyMunicipalTaxInput(input, values)
 
 	return c.Calculate(input)
 }
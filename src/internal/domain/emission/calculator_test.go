@@ -256,6 +256,225 @@ func TestValueCalculator_Calculate(t *testing.T) {
 	}
 }
 
+// TestValueCalculator_Calculate_FederalTaxes tests the federal tax amounts, vTotalRet and vLiq.
+func TestValueCalculator_Calculate_FederalTaxes(t *testing.T) {
+	calculator := NewValueCalculator()
+
+	tests := []struct {
+		name              string
+		input             *CalculationInput
+		errCode           string // empty when no error is expected
+		wantPIS           float64
+		wantCOFINS        float64
+		wantIRRF          float64
+		wantTotalWithheld float64
+		wantNetValue      float64
+	}{
+		{
+			name: "no federal taxes",
+			input: &CalculationInput{
+				ServiceValue:          1500.00,
+				UnconditionalDiscount: 100.00,
+				ConditionalDiscount:   50.00,
+				Deductions:            200.00,
+			},
+			wantNetValue: 1350.00, // deductions do not reduce the amount paid
+		},
+		{
+			name: "withheld PIS/COFINS, IRRF and CSLL",
+			input: &CalculationInput{
+				ServiceValue:          10000.00,
+				UnconditionalDiscount: 1000.00,
+				ISSRate:               2.00,
+				FederalTaxes: &FederalTaxInput{
+					PISRate:           0.65,
+					COFINSRate:        3.00,
+					PisCofinsWithheld: true,
+					IRRFRate:          1.50,
+					CSLLRate:          1.00,
+				},
+			},
+			wantPIS:           58.50,  // 9000 * 0.65%
+			wantCOFINS:        270.00, // 9000 * 3%
+			wantIRRF:          135.00, // 9000 * 1.5%
+			wantTotalWithheld: 553.50, // 58.50 + 270 + 135 + 90
+			wantNetValue:      8446.50,
+		},
+		{
+			name: "PIS/COFINS not withheld",
+			input: &CalculationInput{
+				ServiceValue: 10000.00,
+				FederalTaxes: &FederalTaxInput{
+					PisCofinsTaxBase: 5000.00,
+					PISRate:          0.65,
+					COFINSRate:       3.00,
+					CPRate:           11.00,
+				},
+			},
+			wantPIS:           32.50,
+			wantCOFINS:        150.00,
+			wantTotalWithheld: 1100.00, // only CP
+			wantNetValue:      8900.00,
+		},
+		{
+			name: "invalid rate",
+			input: &CalculationInput{
+				ServiceValue: 1000.00,
+				FederalTaxes: &FederalTaxInput{IRRFRate: 1.505},
+			},
+			errCode: ErrCodeInvalidFederalTax,
+		},
+		{
+			name: "withholdings exceed value after discounts",
+			input: &CalculationInput{
+				ServiceValue:        1000.00,
+				ConditionalDiscount: 500.00,
+				FederalTaxes:        &FederalTaxInput{CPRate: 60.00},
+			},
+			errCode: ErrCodeWithholdingExceedsValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calculator.Calculate(tt.input)
+
+			if tt.errCode != "" {
+				calcErr, ok := err.(*CalculationError)
+				if !ok || calcErr.Code != tt.errCode {
+					t.Errorf("expected error code %s, got %v", tt.errCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !floatEquals(result.PISAmount, tt.wantPIS) {
+				t.Errorf("PISAmount = %.2f, want %.2f", result.PISAmount, tt.wantPIS)
+			}
+			if !floatEquals(result.COFINSAmount, tt.wantCOFINS) {
+				t.Errorf("COFINSAmount = %.2f, want %.2f", result.COFINSAmount, tt.wantCOFINS)
+			}
+			if !floatEquals(result.IRRFWithheld, tt.wantIRRF) {
+				t.Errorf("IRRFWithheld = %.2f, want %.2f", result.IRRFWithheld, tt.wantIRRF)
+			}
+			if !floatEquals(result.TotalWithheld, tt.wantTotalWithheld) {
+				t.Errorf("TotalWithheld = %.2f, want %.2f", result.TotalWithheld, tt.wantTotalWithheld)
+			}
+			if !floatEquals(result.NetValue, tt.wantNetValue) {
+				t.Errorf("NetValue = %.2f, want %.2f", result.NetValue, tt.wantNetValue)
+			}
+		})
+	}
+}
+
+// TestValueCalculator_Calculate_MunicipalTax tests the municipal benefit reduction and the ISS withholding.
+func TestValueCalculator_Calculate_MunicipalTax(t *testing.T) {
+	calculator := NewValueCalculator()
+
+	tests := []struct {
+		name              string
+		input             *CalculationInput
+		errCode           string // empty when no error is expected
+		wantReduction     float64
+		wantISSTaxBase    float64
+		wantISS           float64
+		wantTotalWithheld float64
+		wantNetValue      float64
+	}{
+		{
+			name: "benefit reduction percentage",
+			input: &CalculationInput{
+				ServiceValue:               1000.00,
+				Deductions:                 200.00,
+				ISSRate:                    5.00,
+				BenefitReductionPercentage: 40.00,
+			},
+			wantReduction:  320.00, // 800 * 40%
+			wantISSTaxBase: 480.00,
+			wantISS:        24.00,
+			wantNetValue:   1000.00,
+		},
+		{
+			name: "benefit reduction value",
+			input: &CalculationInput{
+				ServiceValue:          1000.00,
+				ISSRate:               5.00,
+				BenefitReductionValue: 250.00,
+			},
+			wantReduction:  250.00,
+			wantISSTaxBase: 750.00,
+			wantISS:        37.50,
+			wantNetValue:   1000.00,
+		},
+		{
+			name: "ISS withheld by the taker",
+			input: &CalculationInput{
+				ServiceValue: 1000.00,
+				ISSRate:      5.00,
+				ISSWithheld:  true,
+				FederalTaxes: &FederalTaxInput{IRRFRate: 1.50},
+			},
+			wantISSTaxBase:    1000.00,
+			wantISS:           50.00,
+			wantTotalWithheld: 65.00, // 50 ISS + 15 IRRF
+			wantNetValue:      935.00,
+		},
+		{
+			name: "both benefit reductions",
+			input: &CalculationInput{
+				ServiceValue:               1000.00,
+				BenefitReductionPercentage: 10.00,
+				BenefitReductionValue:      100.00,
+			},
+			errCode: ErrCodeInvalidBenefitReduction,
+		},
+		{
+			name: "benefit reduction value exceeds tax base",
+			input: &CalculationInput{
+				ServiceValue:          1000.00,
+				Deductions:            500.00,
+				BenefitReductionValue: 600.00,
+			},
+			errCode: ErrCodeInvalidBenefitReduction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calculator.Calculate(tt.input)
+
+			if tt.errCode != "" {
+				calcErr, ok := err.(*CalculationError)
+				if !ok || calcErr.Code != tt.errCode {
+					t.Errorf("expected error code %s, got %v", tt.errCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !floatEquals(result.BenefitReduction, tt.wantReduction) {
+				t.Errorf("BenefitReduction = %.2f, want %.2f", result.BenefitReduction, tt.wantReduction)
+			}
+			if !floatEquals(result.ISSTaxBase, tt.wantISSTaxBase) {
+				t.Errorf("ISSTaxBase = %.2f, want %.2f", result.ISSTaxBase, tt.wantISSTaxBase)
+			}
+			if !floatEquals(result.ISSAmount, tt.wantISS) {
+				t.Errorf("ISSAmount = %.2f, want %.2f", result.ISSAmount, tt.wantISS)
+			}
+			if !floatEquals(result.TotalWithheld, tt.wantTotalWithheld) {
+				t.Errorf("TotalWithheld = %.2f, want %.2f", result.TotalWithheld, tt.wantTotalWithheld)
+			}
+			if !floatEquals(result.NetValue, tt.wantNetValue) {
+				t.Errorf("NetValue = %.2f, want %.2f", result.NetValue, tt.wantNetValue)
+			}
+		})
+	}
+}
+
 // TestValueCalculator_Calculate_NilInput tests nil input handling.
 func TestValueCalculator_Calculate_NilInput(t *testing.T) {
 	calculator := NewValueCalculator()
@@ -20,7 +20,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000190</CNPJ>
       <xNome>Provider Company Ltd</xNome>
@@ -55,7 +54,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>98765432000121</CNPJ>
       <xNome>Another Company</xNome>
@@ -82,7 +80,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CPF>12345678901</CPF>
       <xNome>Individual Provider</xNome>
@@ -10,6 +10,10 @@ type EmissionRequest struct {
 	// Taker contains the service taker (tomador) information. Optional.
 	Taker *TakerRequest `json:"taker,omitempty"`
 
+	// Intermediary contains the service intermediary (intermediario) information,
+	// such as the marketplace that brokered the service. Optional.
+	Intermediary *IntermediaryRequest `json:"intermediary,omitempty"`
+
 	// Service contains the service details being invoiced.
 	Service ServiceRequest `json:"service" binding:"required"`
 
@@ -19,6 +23,22 @@ type EmissionRequest struct {
 	// DPS contains the source document (DPS) information.
 	DPS DPSRequest `json:"dps" binding:"required"`
 
+	// EmitterType identifies who emits the DPS (tpEmit): 1 = provider (default),
+	// 2 = taker or 3 = intermediary. The DPS ID uses the emitter's CNPJ or CPF, and
+	// the certificate must belong to the emitter. SEFIN currently rejects DPS emitted
+	// by the taker or intermediary (E9996).
+	EmitterType int `json:"emitter_type,omitempty"`
+
+	// EmissionReason is why the taker or intermediary emits the DPS (cMotivoEmisTI):
+	// 1 = service import, 2 = required by municipal law, 3 = refusal of the provider
+	// to emit, 4 = rejection of the NFS-e emitted by the provider. Optional; not
+	// allowed when the provider emits the DPS.
+	EmissionReason int `json:"emission_reason,omitempty"`
+
+	// RejectedAccessKey is the 50-digit access key of the NFS-e rejected by the taker
+	// or intermediary (chNFSeRej). Required when EmissionReason is 4, and only allowed then.
+	RejectedAccessKey string `json:"rejected_access_key,omitempty"`
+
 	// Certificate contains the digital certificate for signing. Optional for Phase 3.
 	Certificate *CertificateRequest `json:"certificate,omitempty"`
 
@@ -26,19 +46,106 @@ type EmissionRequest struct {
 	WebhookURL string `json:"webhook_url,omitempty"`
 }
 
+// DPS emitter types (tpEmit).
+const (
+	// EmitterProvider means the provider emits the DPS (1).
+	EmitterProvider = 1
+
+	// EmitterTaker means the taker emits the DPS (2).
+	EmitterTaker = 2
+
+	// EmitterIntermediary means the intermediary emits the DPS (3).
+	EmitterIntermediary = 3
+)
+
+// Reasons for the taker or intermediary to emit the DPS (cMotivoEmisTI).
+const (
+	// EmissionReasonImport is a service import (1).
+	EmissionReasonImport = 1
+
+	// EmissionReasonMunicipalLaw means the municipal law requires the taker or intermediary to emit (2).
+	EmissionReasonMunicipalLaw = 2
+
+	// EmissionReasonProviderRefusal means the provider refused to emit the NFS-e (3).
+	EmissionReasonProviderRefusal = 3
+
+	// EmissionReasonRejection means the taker or intermediary rejected the NFS-e emitted by the provider (4).
+	EmissionReasonRejection = 4
+)
+
+// EmitterTypeOrDefault returns the DPS emitter type, defaulting to the provider.
+func (r *EmissionRequest) EmitterTypeOrDefault() int {
+	if r.EmitterType == 0 {
+		return EmitterProvider
+	}
+	return r.EmitterType
+}
+
+// EmitterRegistration returns the CNPJ or CPF of the party emitting the DPS,
+// or an empty string when that party is missing or identified only by NIF.
+func (r *EmissionRequest) EmitterRegistration() string {
+	switch r.EmitterTypeOrDefault() {
+	case EmitterTaker:
+		if r.Taker == nil {
+			return ""
+		}
+		if r.Taker.CNPJ != "" {
+			return r.Taker.CNPJ
+		}
+		return r.Taker.CPF
+	case EmitterIntermediary:
+		if r.Intermediary == nil {
+			return ""
+		}
+		if r.Intermediary.CNPJ != "" {
+			return r.Intermediary.CNPJ
+		}
+		return r.Intermediary.CPF
+	default:
+		return r.Provider.Registration()
+	}
+}
+
+// Registration returns the CNPJ or CPF of the provider.
+func (p *ProviderRequest) Registration() string {
+	if p.CNPJ != "" {
+		return p.CNPJ
+	}
+	return p.CPF
+}
+
 // ProviderRequest contains the service provider information in the emission request.
 type ProviderRequest struct {
-	// CNPJ is the 14-digit tax ID of the service provider (without formatting).
-	CNPJ string `json:"cnpj" binding:"required"`
+	// CNPJ is the 14-digit tax ID of a company provider (without formatting).
+	// Mutually exclusive with CPF.
+	CNPJ string `json:"cnpj,omitempty"`
 
-	// TaxRegime indicates the tax regime: "mei" or "me_epp".
+	// CPF is the 11-digit tax ID of an individual provider, such as an autonomous
+	// professional (without formatting). Mutually exclusive with CNPJ.
+	CPF string `json:"cpf,omitempty"`
+
+	// TaxRegime indicates the tax regime: "mei" or "me_epp" (Simples Nacional optants),
+	// "lucro_presumido" or "lucro_real" (companies), or "autonomo" (CPF providers).
 	TaxRegime string `json:"tax_regime" binding:"required"`
 
+	// SimplesCalculationRegime is how an ME/EPP provider that exceeded a Simples Nacional
+	// sublimit calculates its taxes (regApTribSN): 1 = federal taxes and ISS within the
+	// Simples Nacional, 2 = ISS outside, 3 = federal taxes and ISS outside. Optional.
+	SimplesCalculationRegime int `json:"simples_calculation_regime,omitempty"`
+
+	// SpecialRegime is the special taxation regime (regEspTrib), 0 (none) to 6. Optional.
+	SpecialRegime int `json:"special_regime,omitempty"`
+
 	// Name is the legal name (razao social) of the provider.
 	Name string `json:"name" binding:"required"`
 
 	// MunicipalRegistration is the optional municipal service provider registration number.
 	MunicipalRegistration string `json:"municipal_registration,omitempty"`
+
+	// Address is the national address of the provider (prest/end). Required when the
+	// taker or intermediary emits the DPS, and not allowed when the provider does, since
+	// SEFIN then takes the address from its own registry.
+	Address *AddressRequest `json:"address,omitempty"`
 }
 
 // TakerRequest contains the service taker information in the emission request.
@@ -73,6 +180,37 @@ type TakerRequest struct {
 	Address *AddressRequest `json:"address,omitempty"`
 }
 
+// IntermediaryRequest contains the service intermediary information in the emission request.
+type IntermediaryRequest struct {
+	// CNPJ is the 14-digit tax ID for company intermediaries (without formatting).
+	// Mutually exclusive with CPF and NIF.
+	CNPJ string `json:"cnpj,omitempty"`
+
+	// CPF is the 11-digit tax ID for individual intermediaries (without formatting).
+	// Mutually exclusive with CNPJ and NIF.
+	CPF string `json:"cpf,omitempty"`
+
+	// NIF is the foreign tax identification number for non-Brazilian intermediaries.
+	// Valid NIF: 1-40 alphanumeric characters.
+	// Mutually exclusive with CNPJ and CPF.
+	NIF string `json:"nif,omitempty"`
+
+	// Name is the legal name or full name of the intermediary (max 300 chars).
+	Name string `json:"name" binding:"required"`
+
+	// Phone is the intermediary's phone number.
+	// For Brazilian numbers: 10-11 digits (DDD + number).
+	// For international: include country code.
+	Phone string `json:"phone,omitempty"`
+
+	// Email is the intermediary's email address.
+	Email string `json:"email,omitempty"`
+
+	// Address is the intermediary's address. Optional.
+	// For foreign intermediaries (NIF), requires foreign address format.
+	Address *AddressRequest `json:"address,omitempty"`
+}
+
 // AddressRequest contains address information in the emission request.
 type AddressRequest struct {
 	// Street is the street name (xLgr). Required.
@@ -95,16 +233,34 @@ type AddressRequest struct {
 	// Required for national (Brazilian) addresses.
 	State string `json:"state,omitempty"`
 
-	// PostalCode is the 8-digit CEP without formatting.
-	// Required for national (Brazilian) addresses.
+	// PostalCode is the 8-digit CEP without formatting for national addresses,
+	// or the foreign postal code (cEndPost, up to 11 characters).
+	// Required for both national and foreign addresses.
 	PostalCode string `json:"postal_code,omitempty"`
 
+	// City is the city name (xCidade).
+	// Required for foreign addresses.
+	City string `json:"city,omitempty"`
+
+	// Region is the state, province or region (xEstProvReg).
+	// Required for foreign addresses.
+	Region string `json:"region,omitempty"`
+
 	// CountryCode is the ISO 3166-1 alpha-2 country code (cPais).
 	// Defaults to "BR" if not provided.
 	// For foreign addresses, must be a non-BR code.
 	CountryCode string `json:"country_code,omitempty"`
 }
 
+// IsForeign returns true if the address is outside Brazil.
+// An address is considered foreign if CountryCode is set and is not "BR".
+func (a *AddressRequest) IsForeign() bool {
+	if a == nil {
+		return false
+	}
+	return a.CountryCode != "" && a.CountryCode != "BR"
+}
+
 // ServiceRequest contains the service details in the emission request.
 type ServiceRequest struct {
 	// NationalCode is the 6-digit cTribNac (national service code).
@@ -116,6 +272,118 @@ type ServiceRequest struct {
 	// MunicipalityCode is the 7-digit IBGE code of the municipality where
 	// the service was provided (local de prestacao).
 	MunicipalityCode string `json:"municipality_code" binding:"required"`
+
+	// NBSCode is the 9-digit NBS code of the service (cNBS), from the national
+	// NBS list (ANEXO B). Optional; formatted codes such as "1.0101.11.00" are accepted.
+	NBSCode string `json:"nbs_code,omitempty"`
+
+	// MunicipalTaxCode is the 3-digit municipal tax code of the service (cTribMun),
+	// defined by the municipality where the service is taxed. Optional; when set,
+	// it replaces the code informed by the municipal parameters.
+	MunicipalTaxCode string `json:"municipal_tax_code,omitempty"`
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country where the service
+	// was performed (cPaisPrestacao), for services performed abroad. Optional.
+	// When set, it replaces the municipality as the place of service in the DPS;
+	// MunicipalityCode is still required as the issuing municipality.
+	CountryCode string `json:"country_code,omitempty"`
+
+	// ForeignTrade contains the foreign trade information (comExt) of service
+	// exports and imports. Optional.
+	ForeignTrade *ForeignTradeRequest `json:"foreign_trade,omitempty"`
+
+	// Construction identifies the construction site (obra) of civil construction services.
+	// Required for the construction service codes (subitems 07.02, 07.04 to 07.08,
+	// 07.17 and 07.19). Optional for other services.
+	Construction *ConstructionRequest `json:"construction,omitempty"`
+
+	// Event identifies the event (atvEvento) of artistic, cultural, sports and
+	// similar services. Required for the event service codes (item 12 and
+	// subitem 17.10). Optional for other services.
+	Event *EventRequest `json:"event,omitempty"`
+}
+
+// ForeignTradeRequest contains the foreign trade information (comExt group).
+type ForeignTradeRequest struct {
+	// ServiceMode is the mode of supply (mdPrestacao): 0 = unknown, 1 = cross-border,
+	// 2 = consumption in Brazil, 3 = temporary movement of individuals,
+	// 4 = consumption abroad.
+	ServiceMode int `json:"service_mode"`
+
+	// Relationship is the relationship between the parties (vincPrest): 0 = none,
+	// 1 = controlled, 2 = controlling, 3 = affiliate, 4 = head office,
+	// 5 = branch, 6 = other.
+	Relationship int `json:"relationship"`
+
+	// CurrencyCode is the 3-digit BACEN code of the transaction currency (tpMoeda),
+	// e.g. "220" for the US dollar. Required.
+	CurrencyCode string `json:"currency_code" binding:"required"`
+
+	// ForeignValue is the service value in the transaction currency (vServMoeda).
+	// Required, must be greater than 0.
+	ForeignValue float64 `json:"foreign_value" binding:"required"`
+
+	// ProviderSupportMechanism is the foreign trade support mechanism used by the
+	// provider (mecAFComexP): "00" to "08", "01" meaning none. Required.
+	ProviderSupportMechanism string `json:"provider_support_mechanism" binding:"required"`
+
+	// TakerSupportMechanism is the foreign trade support mechanism used by the
+	// taker (mecAFComexT): "00" to "26", "01" meaning none. Required.
+	TakerSupportMechanism string `json:"taker_support_mechanism" binding:"required"`
+
+	// TemporaryGoods links the operation to a temporary movement of goods (movTempBens):
+	// 0 = unknown, 1 = no, 2 = import declaration, 3 = export declaration.
+	TemporaryGoods int `json:"temporary_goods"`
+
+	// ImportDeclaration is the import declaration number (nDI, max 12 chars).
+	// Required when TemporaryGoods is 2.
+	ImportDeclaration string `json:"import_declaration,omitempty"`
+
+	// ExportRegistration is the export registration number (nRE, max 12 chars).
+	// Required when TemporaryGoods is 3.
+	ExportRegistration string `json:"export_registration,omitempty"`
+
+	// ShareWithMDIC shares the NFS-e with the Secretaria de Comercio Exterior (mdic).
+	ShareWithMDIC bool `json:"share_with_mdic,omitempty"`
+}
+
+// ConstructionRequest contains the construction site information (obra group).
+// The site is identified by its Code or by its Address.
+type ConstructionRequest struct {
+	// Code is the construction identification (cObra): the CNO or CEI number (max 30 chars).
+	// Mutually exclusive with Address.
+	Code string `json:"code,omitempty"`
+
+	// PropertyRegistration is the municipal property registration (inscImobFisc, max 30 chars).
+	// Optional.
+	PropertyRegistration string `json:"property_registration,omitempty"`
+
+	// Address is the construction site address, used when there is no Code.
+	// National sites need the CEP; foreign sites need a non-BR country code,
+	// postal code, city and region. Municipality code and state are not used.
+	Address *AddressRequest `json:"address,omitempty"`
+}
+
+// EventRequest contains the event information (atvEvento group).
+// The event is identified by its ID or by its Address.
+type EventRequest struct {
+	// Name is the description of the event (xNome, max 255 chars). Required.
+	Name string `json:"name" binding:"required"`
+
+	// StartDate is the first day of the event (dtIni) in YYYY-MM-DD format. Required.
+	StartDate string `json:"start_date" binding:"required"`
+
+	// EndDate is the last day of the event (dtFim) in YYYY-MM-DD format. Required.
+	EndDate string `json:"end_date" binding:"required"`
+
+	// ID is the event identification assigned by the municipality (idAtvEvt, max 30 chars).
+	// Mutually exclusive with Address.
+	ID string `json:"id,omitempty"`
+
+	// Address is the event address, used when there is no ID.
+	// National events need the CEP; foreign events need a non-BR country code,
+	// postal code, city and region. Municipality code and state are not used.
+	Address *AddressRequest `json:"address,omitempty"`
 }
 
 // ValuesRequest contains the monetary values in the emission request.
@@ -138,6 +406,290 @@ type ValuesRequest struct {
 	// Deductions are legally permitted deductions from the service value (vDedRed / vDR).
 	// Reduces the tax base. Optional, must be >= 0.
 	Deductions float64 `json:"deductions,omitempty"`
+
+	// DeductionDocuments itemizes the documents supporting the deductions (documentos/docDedRed).
+	// Optional; when present, their deduction values must add up to Deductions and the
+	// DPS lists the documents instead of the deduction total. Up to 1000 documents.
+	DeductionDocuments []DeductionDocumentRequest `json:"deduction_documents,omitempty"`
+
+	// MunicipalBenefit is the municipal benefit (BM) claimed for the service. Optional.
+	// It must be on record for the provider in the issuing municipality.
+	MunicipalBenefit *MunicipalBenefitRequest `json:"municipal_benefit,omitempty"`
+
+	// ISS contains the municipal tax (ISSQN) treatment of the service (tribMun). Optional;
+	// by default the service is taxable and the ISS is not withheld.
+	ISS *ISSRequest `json:"iss,omitempty"`
+
+	// FederalTaxes are the federal taxes of the service (tribFed). Optional.
+	// Withheld taxes reduce the net value (vLiq) paid by the taker.
+	FederalTaxes *FederalTaxesRequest `json:"federal_taxes,omitempty"`
+
+	// SimplesTaxRate is the approximate tax percentage of the provider's Simples Nacional
+	// rate (pTotTribSN), informed to the taker instead of the tax burden table. Optional;
+	// only for Simples Nacional optants.
+	SimplesTaxRate float64 `json:"simples_tax_rate,omitempty"`
+}
+
+// Deduction/reduction types (tpDedRed).
+const (
+	// DeductionTypeFood is food and beverages/minibar (1).
+	DeductionTypeFood = 1
+
+	// DeductionTypeMaterials is materials (2).
+	DeductionTypeMaterials = 2
+
+	// DeductionTypeExternalProduction is external production (3).
+	DeductionTypeExternalProduction = 3
+
+	// DeductionTypeExpenseRefund is the refund of expenses (4).
+	DeductionTypeExpenseRefund = 4
+
+	// DeductionTypeConsortiumTransfer is a transfer to consortium members (5).
+	DeductionTypeConsortiumTransfer = 5
+
+	// DeductionTypeHealthPlanTransfer is a health plan transfer (6).
+	DeductionTypeHealthPlanTransfer = 6
+
+	// DeductionTypeServices is services (7).
+	DeductionTypeServices = 7
+
+	// DeductionTypeSubcontracting is labor subcontracting (8).
+	DeductionTypeSubcontracting = 8
+
+	// DeductionTypeOther is any other deduction, which must be described (99).
+	DeductionTypeOther = 99
+)
+
+// DeductionDocumentRequest contains a document supporting a deduction (docDedRed).
+// The document is identified by exactly one of NFSeKey, NFeKey, MunicipalNFSe,
+// PaperInvoice, FiscalDocumentNumber or DocumentNumber.
+type DeductionDocumentRequest struct {
+	// NFSeKey is the 50-digit access key of a national NFS-e (chNFSe).
+	NFSeKey string `json:"nfse_key,omitempty"`
+
+	// NFeKey is the 44-digit access key of an NF-e (chNFe).
+	NFeKey string `json:"nfe_key,omitempty"`
+
+	// MunicipalNFSe identifies an NFS-e issued in a municipal standard (NFSeMun).
+	MunicipalNFSe *MunicipalNFSeRequest `json:"municipal_nfse,omitempty"`
+
+	// PaperInvoice identifies a non-electronic NF or NFS (NFNFS).
+	PaperInvoice *PaperInvoiceRequest `json:"paper_invoice,omitempty"`
+
+	// FiscalDocumentNumber is the number of another fiscal document (nDocFisc, max 255 chars).
+	FiscalDocumentNumber string `json:"fiscal_document_number,omitempty"`
+
+	// DocumentNumber is the number of a non-fiscal document (nDoc, max 255 chars).
+	DocumentNumber string `json:"document_number,omitempty"`
+
+	// Type is the deduction/reduction type (tpDedRed): 1-8 or 99 (other). Required.
+	Type int `json:"type" binding:"required"`
+
+	// TypeDescription describes the deduction (xDescOutDed, max 150 chars).
+	// Required when Type is 99.
+	TypeDescription string `json:"type_description,omitempty"`
+
+	// IssueDate is the issue date of the document (dtEmiDoc) in YYYY-MM-DD format. Required.
+	IssueDate string `json:"issue_date" binding:"required"`
+
+	// DeductibleValue is the total deductible value of the document (vDedutivelRedutivel).
+	DeductibleValue float64 `json:"deductible_value" binding:"required"`
+
+	// DeductionValue is the value deducted in this NFS-e (vDeducaoReducao).
+	// Must not exceed DeductibleValue.
+	DeductionValue float64 `json:"deduction_value" binding:"required"`
+}
+
+// MunicipalNFSeRequest identifies an NFS-e issued in a municipal standard (NFSeMun).
+type MunicipalNFSeRequest struct {
+	// MunicipalityCode is the 7-digit IBGE code of the issuing municipality (cMunNFSeMun).
+	MunicipalityCode string `json:"municipality_code" binding:"required"`
+
+	// Number is the municipal NFS-e number (nNFSeMun, up to 15 digits).
+	Number string `json:"number" binding:"required"`
+
+	// VerificationCode is the verification code of the municipal NFS-e (cVerifNFSeMun, up to 9 alphanumeric chars).
+	VerificationCode string `json:"verification_code" binding:"required"`
+}
+
+// PaperInvoiceRequest identifies a non-electronic NF or NFS (NFNFS).
+type PaperInvoiceRequest struct {
+	// Number is the invoice number (nNFS, up to 7 digits).
+	Number string `json:"number" binding:"required"`
+
+	// Model is the invoice model (modNFS, up to 15 digits).
+	Model string `json:"model" binding:"required"`
+
+	// Series is the invoice series (serieNFS, up to 15 alphanumeric chars).
+	Series string `json:"series" binding:"required"`
+}
+
+// MunicipalBenefitRequest identifies a municipal benefit (BM) claimed in the emission request.
+type MunicipalBenefitRequest struct {
+	// Number is the 14-digit benefit identifier (nBM) assigned by the Sistema Nacional:
+	// the IBGE municipality code, the parameter type and a sequence.
+	Number string `json:"number" binding:"required"`
+
+	// ReductionPercentage is the tax base reduction percentage granted by the benefit (pRedBCBM).
+	// Optional; mutually exclusive with ReductionValue.
+	ReductionPercentage float64 `json:"reduction_percentage,omitempty"`
+
+	// ReductionValue is the tax base reduction value granted by the benefit (vRedBCBM).
+	// Optional; mutually exclusive with ReductionPercentage.
+	ReductionValue float64 `json:"reduction_value,omitempty"`
+}
+
+// Simples Nacional calculation regimes (regApTribSN).
+const (
+	// SimplesCalculationAll calculates the federal taxes and the ISS within the Simples Nacional (1).
+	SimplesCalculationAll = 1
+
+	// SimplesCalculationISSOutside calculates the ISS outside the Simples Nacional (2).
+	SimplesCalculationISSOutside = 2
+
+	// SimplesCalculationNone calculates the federal taxes and the ISS outside the Simples Nacional (3).
+	SimplesCalculationNone = 3
+)
+
+// Special taxation regimes (regEspTrib).
+const (
+	SpecialRegimeNone                     = 0 // No special regime
+	SpecialRegimeCooperative              = 1 // Cooperative act
+	SpecialRegimeEstimate                 = 2 // ISS by estimate
+	SpecialRegimeMunicipalMicroenterprise = 3 // Municipal microenterprise
+	SpecialRegimeNotary                   = 4 // Notary or registrar
+	SpecialRegimeAutonomousProfessional   = 5 // Autonomous professional
+	SpecialRegimeProfessionalSociety      = 6 // Professional society
+)
+
+// SpecialRegimeHasFixedISS reports whether a special regime pays the ISS as a fixed
+// amount per period or per professional, instead of a rate on each service.
+func SpecialRegimeHasFixedISS(regime int) bool {
+	switch regime {
+	case SpecialRegimeEstimate, SpecialRegimeAutonomousProfessional, SpecialRegimeProfessionalSociety:
+		return true
+	default:
+		return false
+	}
+}
+
+// ISS taxation types (tribISSQN).
+const (
+	// ISSTaxationTaxable is a taxable operation (1).
+	ISSTaxationTaxable = 1
+
+	// ISSTaxationImmunity is an operation with constitutional immunity (2).
+	ISSTaxationImmunity = 2
+
+	// ISSTaxationExport is a service export (3).
+	ISSTaxationExport = 3
+
+	// ISSTaxationNonIncidence is an operation outside the scope of the ISS (4).
+	ISSTaxationNonIncidence = 4
+)
+
+// ISS withholding types (tpRetISSQN).
+const (
+	// ISSWithholdingNone means the provider collects the ISS (1).
+	ISSWithholdingNone = 1
+
+	// ISSWithholdingTaker means the taker withholds the ISS (2).
+	ISSWithholdingTaker = 2
+
+	// ISSWithholdingIntermediary means the intermediary withholds the ISS (3).
+	ISSWithholdingIntermediary = 3
+)
+
+// ISS suspension types (tpSusp).
+const (
+	// ISSSuspensionJudicial is a suspension by court decision (1).
+	ISSSuspensionJudicial = 1
+
+	// ISSSuspensionAdministrative is a suspension by administrative proceeding (2).
+	ISSSuspensionAdministrative = 2
+)
+
+// ISSRequest contains the municipal tax (ISSQN) treatment of the service (tribMun).
+type ISSRequest struct {
+	// Taxation is the ISS taxation (tribISSQN): 1 = taxable, 2 = immunity,
+	// 3 = export, 4 = non-incidence. Optional, defaults to 1.
+	Taxation int `json:"taxation,omitempty"`
+
+	// ResultCountryCode is the 2-letter ISO code of the country where the result
+	// of an exported service occurs (cPaisResult). Required for exports.
+	ResultCountryCode string `json:"result_country_code,omitempty"`
+
+	// ImmunityType is the constitutional immunity (tpImunidade), 1 to 5.
+	// Required for immunity.
+	ImmunityType int `json:"immunity_type,omitempty"`
+
+	// Suspension describes the suspended exigibility of the ISS (exigSusp). Optional.
+	Suspension *ISSSuspensionRequest `json:"suspension,omitempty"`
+
+	// Withholding is the ISS withholding (tpRetISSQN): 1 = not withheld,
+	// 2 = withheld by the taker, 3 = withheld by the intermediary. Optional, defaults to 1.
+	Withholding int `json:"withholding,omitempty"`
+}
+
+// ISSSuspensionRequest describes the suspended exigibility of the ISS (exigSusp).
+type ISSSuspensionRequest struct {
+	// Type is the suspension type (tpSusp): 1 = court decision, 2 = administrative proceeding.
+	Type int `json:"type" binding:"required"`
+
+	// ProcessNumber is the 30-digit number of the proceeding (nProcesso).
+	ProcessNumber string `json:"process_number" binding:"required"`
+}
+
+// TaxationOrDefault returns the ISS taxation, defaulting to taxable.
+func (r *ISSRequest) TaxationOrDefault() int {
+	if r == nil || r.Taxation == 0 {
+		return ISSTaxationTaxable
+	}
+	return r.Taxation
+}
+
+// WithholdingOrDefault returns the ISS withholding, defaulting to not withheld.
+func (r *ISSRequest) WithholdingOrDefault() int {
+	if r == nil || r.Withholding == 0 {
+		return ISSWithholdingNone
+	}
+	return r.Withholding
+}
+
+// FederalTaxesRequest contains the federal taxes of the service (tribFed).
+// Rates are percentages (e.g., 1.5 for 1.5%); the amounts are calculated by the
+// ValueCalculator on the withholding base (ServiceValue - UnconditionalDiscount).
+type FederalTaxesRequest struct {
+	// PisCofins contains the PIS/COFINS taxes (piscofins). Optional.
+	PisCofins *PisCofinsRequest `json:"pis_cofins,omitempty"`
+
+	// CPRate is the withheld social security contribution rate (vRetCP). Optional.
+	CPRate float64 `json:"cp_rate,omitempty"`
+
+	// IRRFRate is the withheld income tax rate (vRetIRRF). Optional.
+	IRRFRate float64 `json:"irrf_rate,omitempty"`
+
+	// CSLLRate is the withheld social contribution on net profit rate (vRetCSLL). Optional.
+	CSLLRate float64 `json:"csll_rate,omitempty"`
+}
+
+// PisCofinsRequest contains the PIS/COFINS taxes of the service (piscofins).
+type PisCofinsRequest struct {
+	// CST is the 2-digit tax situation code (CST), "00" to "09". Required.
+	CST string `json:"cst" binding:"required"`
+
+	// TaxBase is the PIS/COFINS tax base (vBCPisCofins).
+	// Optional; defaults to the withholding base.
+	TaxBase float64 `json:"tax_base,omitempty"`
+
+	// PISRate is the PIS rate percentage (pAliqPis). Optional.
+	PISRate float64 `json:"pis_rate,omitempty"`
+
+	// COFINSRate is the COFINS rate percentage (pAliqCofins). Optional.
+	COFINSRate float64 `json:"cofins_rate,omitempty"`
+
+	// Withheld indicates that the taker withholds PIS/COFINS (tpRetPisCofins = 1).
+	Withheld bool `json:"withheld,omitempty"`
 }
 
 // HasUnconditionalDiscount returns true if an unconditional discount is present.
@@ -190,16 +742,16 @@ func (v *ValuesRequest) CalculateDeductionPercentage() float64 {
 	return (v.Deductions / v.ServiceValue) * 100
 }
 
-// CalculateNetValue calculates the net value after all discounts and deductions.
-// NetValue = ServiceValue - UnconditionalDiscount - ConditionalDiscount - Deductions
-// Note: This is different from tax base (which excludes conditional discount).
+// CalculateNetValue calculates the net value (vLiq) paid by the taker.
+// NetValue = ServiceValue - UnconditionalDiscount - ConditionalDiscount - TotalWithheld
+// Note: Deductions reduce the tax base but not the amount paid.
 // Returns 0 if the result would be negative (invalid state).
 func (v *ValuesRequest) CalculateNetValue() float64 {
 	if v == nil {
 		return 0
 	}
 
-	netValue := v.ServiceValue - v.UnconditionalDiscount - v.ConditionalDiscount - v.Deductions
+	netValue := v.ServiceValue - v.UnconditionalDiscount - v.ConditionalDiscount - v.CalculateTotalWithheld()
 
 	// Net value cannot be negative
 	if netValue < 0 {
@@ -209,6 +761,17 @@ func (v *ValuesRequest) CalculateNetValue() float64 {
 	return netValue
 }
 
+// CalculateTotalWithheld calculates the total of the federal taxes withheld by the taker (vTotalRet):
+// CP, IRRF and CSLL, plus PIS and COFINS when they are withheld.
+func (v *ValuesRequest) CalculateTotalWithheld() float64 {
+	if v == nil || v.FederalTaxes == nil {
+		return 0
+	}
+
+	base := v.ServiceValue - v.UnconditionalDiscount
+	return calculateFederalTaxes(base, newFederalTaxInput(v.FederalTaxes)).totalWithheld
+}
+
 // TotalTaxBaseDeductions returns the total amount that reduces the tax base.
 // This is UnconditionalDiscount + Deductions (not ConditionalDiscount).
 func (v *ValuesRequest) TotalTaxBaseDeductions() float64 {
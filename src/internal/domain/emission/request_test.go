@@ -346,7 +346,20 @@ func TestValuesRequest_CalculateNetValue(t *testing.T) {
 				ConditionalDiscount:   50.00,
 				Deductions:            200.00,
 			},
-			want: 1150.00, // 1500 - 100 - 50 - 200 = 1150
+			want: 1350.00, // 1500 - 100 - 50 = 1350 (deductions do not reduce the amount paid)
+		},
+		{
+			name: "federal withholdings",
+			values: &ValuesRequest{
+				ServiceValue:          10000.00,
+				UnconditionalDiscount: 1000.00,
+				FederalTaxes: &FederalTaxesRequest{
+					PisCofins: &PisCofinsRequest{CST: "01", PISRate: 0.65, COFINSRate: 3.00, Withheld: true},
+					IRRFRate:  1.50,
+					CSLLRate:  1.00,
+				},
+			},
+			want: 8446.50, // 9000 - (58.50 + 270.00 + 135.00 + 90.00) = 8446.50
 		},
 		{
 			name: "would be negative - clamp to 0",
@@ -354,7 +367,7 @@ func TestValuesRequest_CalculateNetValue(t *testing.T) {
 				ServiceValue:          1000.00,
 				UnconditionalDiscount: 400.00,
 				ConditionalDiscount:   400.00,
-				Deductions:            300.00,
+				FederalTaxes:          &FederalTaxesRequest{IRRFRate: 50.00},
 			},
 			want: 0,
 		},
@@ -482,3 +495,60 @@ func BenchmarkValuesRequest_CalculateTaxBase(b *testing.B) {
 		_ = values.CalculateTaxBase()
 	}
 }
+
+// TestEmissionRequest_EmitterRegistration tests the EmitterRegistration method.
+func TestEmissionRequest_EmitterRegistration(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *EmissionRequest
+		want string
+	}{
+		{
+			name: "provider by default",
+			req:  &EmissionRequest{Provider: ProviderRequest{CNPJ: "11222333000181"}},
+			want: "11222333000181",
+		},
+		{
+			name: "taker with CPF",
+			req: &EmissionRequest{
+				EmitterType: EmitterTaker,
+				Provider:    ProviderRequest{CNPJ: "11222333000181"},
+				Taker:       &TakerRequest{CPF: "52998224725"},
+			},
+			want: "52998224725",
+		},
+		{
+			name: "intermediary with CNPJ",
+			req: &EmissionRequest{
+				EmitterType:  EmitterIntermediary,
+				Provider:     ProviderRequest{CNPJ: "11222333000181"},
+				Intermediary: &IntermediaryRequest{CNPJ: "11444777000161"},
+			},
+			want: "11444777000161",
+		},
+		{
+			name: "taker missing",
+			req: &EmissionRequest{
+				EmitterType: EmitterTaker,
+				Provider:    ProviderRequest{CNPJ: "11222333000181"},
+			},
+			want: "",
+		},
+		{
+			name: "foreign intermediary",
+			req: &EmissionRequest{
+				EmitterType:  EmitterIntermediary,
+				Intermediary: &IntermediaryRequest{NIF: "123456789"},
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.EmitterRegistration(); got != tt.want {
+				t.Errorf("EmitterRegistration() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -37,13 +37,162 @@ type StatusResponse struct {
 	// ProcessedAt is when the request was processed (only if completed).
 	ProcessedAt *time.Time `json:"processed_at,omitempty"`
 
+	// Substitution describes the replaced NFS-e (only for replacement emissions).
+	Substitution *SubstitutionDTO `json:"substitution,omitempty"`
+
 	// Result contains the successful emission result (only on success).
 	Result *EmissionResultDTO `json:"result,omitempty"`
 
+	// Lifecycle contains the current fiscal state of the emitted NFS-e (only on success).
+	Lifecycle *LifecycleDTO `json:"lifecycle,omitempty"`
+
+	// Taker contains the taker sent in the DPS (only when the NFS-e has a taker).
+	Taker *TakerDTO `json:"taker,omitempty"`
+
+	// ISSRate describes the ISS rate used in the DPS (only once the DPS was built).
+	ISSRate *ISSRateDTO `json:"iss_rate,omitempty"`
+
+	// TotalTaxes describes the approximate tax burden used in the DPS (only once the DPS was built).
+	TotalTaxes *TotalTaxesDTO `json:"total_taxes,omitempty"`
+
 	// Error contains the error details (only on failure).
 	Error *EmissionErrorDTO `json:"error,omitempty"`
 }
 
+// TakerDTO contains the taker (tomador) of the NFS-e.
+type TakerDTO struct {
+	// CNPJ is the tax ID of company takers.
+	CNPJ string `json:"cnpj,omitempty"`
+
+	// CPF is the tax ID of individual takers.
+	CPF string `json:"cpf,omitempty"`
+
+	// NIF is the foreign tax identification number.
+	NIF string `json:"nif,omitempty"`
+
+	// Name is the legal name or full name of the taker.
+	Name string `json:"name"`
+
+	// Phone is the taker's phone number.
+	Phone string `json:"phone,omitempty"`
+
+	// Email is the taker's email address.
+	Email string `json:"email,omitempty"`
+
+	// Address is the taker's national or foreign address.
+	Address *AddressDTO `json:"address,omitempty"`
+}
+
+// AddressDTO contains a national or foreign address, in the same format as the request.
+type AddressDTO struct {
+	Street           string `json:"street"`
+	Number           string `json:"number"`
+	Complement       string `json:"complement,omitempty"`
+	Neighborhood     string `json:"neighborhood"`
+	MunicipalityCode string `json:"municipality_code,omitempty"`
+	State            string `json:"state,omitempty"`
+	PostalCode       string `json:"postal_code,omitempty"`
+	City             string `json:"city,omitempty"`
+	Region           string `json:"region,omitempty"`
+	CountryCode      string `json:"country_code,omitempty"`
+}
+
+// ISSRateDTO describes the ISS rate used in the DPS and where it came from.
+type ISSRateDTO struct {
+	// Rate is the ISS rate percentage (pAliq).
+	Rate float64 `json:"rate"`
+
+	// Source indicates where the rate came from (see ISSRateSource* constants).
+	Source string `json:"source"`
+
+	// MunicipalTaxCode is the municipal tax code of the service (cTribMun), if any.
+	MunicipalTaxCode string `json:"municipal_tax_code,omitempty"`
+
+	// ConsultedAt is when the municipal parameters were retrieved, if they were.
+	ConsultedAt *time.Time `json:"consulted_at,omitempty"`
+}
+
+// ISSRateSource constants define where the ISS rate of a DPS came from.
+const (
+	// ISSRateSourceMunicipalParameters indicates the rate was retrieved from the
+	// municipal parameters of the service.
+	ISSRateSourceMunicipalParameters = "municipal_parameters"
+
+	// ISSRateSourceNotApplicable indicates the DPS does not inform a rate, which is only
+	// informed by ME/EPP providers within the Simples Nacional when the ISS is withheld.
+	ISSRateSourceNotApplicable = "not_applicable"
+
+	// ISSRateSourceNotFound indicates the municipality has no parameters for the
+	// service, so the DPS was sent without a rate.
+	ISSRateSourceNotFound = "not_found"
+)
+
+// TotalTaxesDTO describes the approximate tax burden (totTrib) used in the DPS and
+// the table version it came from.
+type TotalTaxesDTO struct {
+	// Source indicates where the tax burden came from (see TotalTaxesSource* constants).
+	Source string `json:"source"`
+
+	// State, CodeType and Code identify the table entry used.
+	State    string `json:"state,omitempty"`
+	CodeType string `json:"code_type,omitempty"`
+	Code     string `json:"code,omitempty"`
+
+	// TableVersion and TableSource identify the tax burden table, such as "25.2.A" by "IBPT".
+	TableVersion string `json:"table_version,omitempty"`
+	TableSource  string `json:"table_source,omitempty"`
+
+	// Approximate tax percentages by jurisdiction.
+	FederalRate   float64 `json:"federal_rate"`
+	StateRate     float64 `json:"state_rate"`
+	MunicipalRate float64 `json:"municipal_rate"`
+
+	// Approximate tax amounts by jurisdiction (vTotTrib).
+	FederalAmount   float64 `json:"federal_amount"`
+	StateAmount     float64 `json:"state_amount"`
+	MunicipalAmount float64 `json:"municipal_amount"`
+
+	// SimplesRate is the approximate Simples Nacional percentage (pTotTribSN), if informed.
+	SimplesRate float64 `json:"simples_rate,omitempty"`
+}
+
+// TotalTaxesSource constants define where the approximate tax burden of a DPS came from.
+const (
+	// TotalTaxesSourceTable indicates the amounts were calculated from the tax burden table.
+	TotalTaxesSourceTable = "table"
+
+	// TotalTaxesSourceSimplesRate indicates the Simples Nacional percentage sent in the request was used.
+	TotalTaxesSourceSimplesRate = "simples_rate"
+
+	// TotalTaxesSourceNotFound indicates the table has no rate for the service, so the
+	// DPS declares the tax burden as not informed.
+	TotalTaxesSourceNotFound = "not_found"
+)
+
+// SubstitutionDTO describes the NFS-e replaced by a substitute emission and its
+// cancellation by substitution (e105102), registered by the government API when
+// it accepts the substitute.
+type SubstitutionDTO struct {
+	// ReplacedAccessKey is the access key of the replaced NFS-e (chSubstda).
+	ReplacedAccessKey string `json:"replaced_access_key"`
+
+	// ReasonCode is the substitution reason (cMotivo).
+	ReasonCode string `json:"reason_code"`
+
+	// Reason is the free-text justification (xMotivo), if any.
+	Reason string `json:"reason,omitempty"`
+
+	// CancellationStatus is "success" once the substitute NFS-e has been emitted
+	// and the replaced NFS-e cancelled.
+	CancellationStatus string `json:"cancellation_status,omitempty"`
+
+	// CancellationSequence is the sequence number of the e105102 event, if found.
+	CancellationSequence int `json:"cancellation_sequence,omitempty"`
+
+	// CancelledAt is when the replaced NFS-e was cancelled.
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
 // EmissionResultDTO contains the successful emission result.
 type EmissionResultDTO struct {
 	// NFSeAccessKey is the 66-character access key for the emitted NFS-e.
@@ -56,6 +205,58 @@ type EmissionResultDTO struct {
 	NFSeXMLURL string `json:"nfse_xml_url,omitempty"`
 }
 
+// LifecycleDTO describes the current fiscal state of an emitted NFS-e, derived
+// from the events linked to it.
+type LifecycleDTO struct {
+	// Status is the lifecycle status: active, cancelled, substituted,
+	// cancellation_under_review, blocked, confirmed or rejected.
+	Status string `json:"status"`
+
+	// LastEventType is the type of the last event applied to the lifecycle.
+	LastEventType string `json:"last_event_type,omitempty"`
+
+	// ReplacedBy is the access key of the substitute NFS-e, once the NFS-e was
+	// cancelled by substitution.
+	ReplacedBy string `json:"replaced_by,omitempty"`
+
+	// CancellationUnderReview reports whether a cancellation awaits fiscal review,
+	// also while the NFS-e is blocked.
+	CancellationUnderReview bool `json:"cancellation_under_review,omitempty"`
+
+	// BlockedEvents lists the event types blocked ex officio by the municipality.
+	BlockedEvents []string `json:"blocked_events,omitempty"`
+
+	// UpdatedAt is when the lifecycle was last updated.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// History lists the events applied to the lifecycle, oldest first.
+	History []LifecycleTransitionDTO `json:"history,omitempty"`
+}
+
+// LifecycleTransitionDTO describes an event applied to the lifecycle of an NFS-e.
+type LifecycleTransitionDTO struct {
+	// EventType is the event type code (e.g., "e101101").
+	EventType string `json:"event_type"`
+
+	// Description is the human-readable event type description.
+	Description string `json:"description,omitempty"`
+
+	// Sequence is the event sequence number, if known.
+	Sequence int `json:"sequence,omitempty"`
+
+	// FromStatus is the lifecycle status before the event.
+	FromStatus string `json:"from_status"`
+
+	// ToStatus is the lifecycle status after the event.
+	ToStatus string `json:"to_status"`
+
+	// OccurredAt is when the event was registered by the government API, if known.
+	OccurredAt *time.Time `json:"occurred_at,omitempty"`
+
+	// Source indicates whether the event was registered through this API or discovered.
+	Source string `json:"source"`
+}
+
 // EmissionErrorDTO contains error details when emission fails.
 type EmissionErrorDTO struct {
 	// Code is the error classification code (e.g., GOVERNMENT_REJECTION, VALIDATION_ERROR).
@@ -85,9 +286,15 @@ type WebhookPayload struct {
 	// Status indicates the final status: success or failed.
 	Status string `json:"status"`
 
+	// Substitution describes the replaced NFS-e (only for replacement emissions).
+	Substitution *SubstitutionDTO `json:"substitution,omitempty"`
+
 	// Result contains the successful emission result (only on success).
 	Result *EmissionResultDTO `json:"result,omitempty"`
 
+	// Lifecycle contains the current fiscal state of the emitted NFS-e (only on success).
+	Lifecycle *LifecycleDTO `json:"lifecycle,omitempty"`
+
 	// Error contains the error details (only on failure).
 	Error *EmissionErrorDTO `json:"error,omitempty"`
 }
@@ -32,10 +32,18 @@ type Address struct {
 	// Required for national addresses, empty for foreign addresses.
 	State string `json:"state,omitempty" bson:"state,omitempty"`
 
-	// PostalCode is the 8-digit CEP (postal code) without formatting.
-	// Required for national addresses, empty for foreign addresses.
+	// PostalCode is the 8-digit CEP (postal code) without formatting for national
+	// addresses, or the foreign postal code (cEndPost) for foreign addresses.
 	PostalCode string `json:"postal_code,omitempty" bson:"postal_code,omitempty"`
 
+	// City is the city name (xCidade).
+	// Required for foreign addresses, empty for national addresses.
+	City string `json:"city,omitempty" bson:"city,omitempty"`
+
+	// Region is the state, province or region (xEstProvReg).
+	// Required for foreign addresses, empty for national addresses.
+	Region string `json:"region,omitempty" bson:"region,omitempty"`
+
 	// CountryCode is the ISO 3166-1 alpha-2 country code (cPais).
 	// Defaults to "BR" for national addresses.
 	CountryCode string `json:"country_code,omitempty" bson:"country_code,omitempty"`
@@ -59,13 +67,17 @@ func (a *Address) GetCountryCode() string {
 }
 
 // Provider represents a service provider (prestador) in an NFS-e transaction.
-// Providers must be registered with a valid CNPJ and municipal registration.
+// Providers must be registered with a valid CNPJ or CPF and municipal registration.
 type Provider struct {
-	// CNPJ is the 14-digit tax ID of the service provider (without formatting).
-	CNPJ string `json:"cnpj" bson:"cnpj"`
+	// CNPJ is the 14-digit tax ID of a company provider (without formatting).
+	CNPJ string `json:"cnpj,omitempty" bson:"cnpj,omitempty"`
 
-	// TaxRegime indicates the tax regime: "mei" (Microempreendedor Individual)
-	// or "me_epp" (Microempresa ou Empresa de Pequeno Porte).
+	// CPF is the 11-digit tax ID of an individual provider (without formatting).
+	CPF string `json:"cpf,omitempty" bson:"cpf,omitempty"`
+
+	// TaxRegime indicates the tax regime: "mei" (Microempreendedor Individual),
+	// "me_epp" (Microempresa ou Empresa de Pequeno Porte), "lucro_presumido", "lucro_real"
+	// or "autonomo" (individual provider).
 	TaxRegime string `json:"tax_regime" bson:"tax_regime"`
 
 	// Name is the legal name (razao social) of the provider.
@@ -272,6 +284,16 @@ const (
 
 	// TaxRegimeMEEPP represents Microempresa ou Empresa de Pequeno Porte regime.
 	TaxRegimeMEEPP = "me_epp"
+
+	// TaxRegimeLucroPresumido represents the presumed profit regime, outside the Simples Nacional.
+	TaxRegimeLucroPresumido = "lucro_presumido"
+
+	// TaxRegimeLucroReal represents the actual profit regime, outside the Simples Nacional.
+	TaxRegimeLucroReal = "lucro_real"
+
+	// TaxRegimeAutonomo represents an individual (CPF) provider, such as an autonomous
+	// professional, outside the Simples Nacional.
+	TaxRegimeAutonomo = "autonomo"
 )
 
 // EmissionStatus represents the status of an NFS-e emission.
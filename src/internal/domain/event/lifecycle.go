@@ -0,0 +1,192 @@
+package event
+
+// NFS-e lifecycle statuses. The lifecycle is the current fiscal state of an
+// emitted NFS-e, derived from the events linked to it; it is independent of the
+// status of the emission request that produced the note.
+const (
+	// LifecycleActive indicates the NFS-e is valid and has no pending manifestation.
+	LifecycleActive = "active"
+
+	// LifecycleCancelled indicates the NFS-e was cancelled (by the provider,
+	// after fiscal review or ex officio).
+	LifecycleCancelled = "cancelled"
+
+	// LifecycleSubstituted indicates the NFS-e was cancelled by a substitute NFS-e.
+	LifecycleSubstituted = "substituted"
+
+	// LifecycleCancellationUnderReview indicates a cancellation awaits fiscal review.
+	LifecycleCancellationUnderReview = "cancellation_under_review"
+
+	// LifecycleBlocked indicates the NFS-e was blocked ex officio by the municipality.
+	LifecycleBlocked = "blocked"
+
+	// LifecycleConfirmed indicates the NFS-e was confirmed by a party or tacitly.
+	LifecycleConfirmed = "confirmed"
+
+	// LifecycleRejected indicates the NFS-e was rejected by a party.
+	LifecycleRejected = "rejected"
+)
+
+// Lifecycle event sources.
+const (
+	// LifecycleSourceRegistered indicates the event was registered through this API.
+	LifecycleSourceRegistered = "registered"
+
+	// LifecycleSourceDiscovered indicates the event was found when querying the government API.
+	LifecycleSourceDiscovered = "discovered"
+)
+
+// LifecycleState is the lifecycle status of an NFS-e together with the temporary
+// conditions that override it: pending ex officio blocks and a cancellation
+// under fiscal review.
+type LifecycleState struct {
+	// Status is the current lifecycle status.
+	Status string
+
+	// PreviousStatus is the status to restore once the NFS-e is no longer blocked
+	// nor under cancellation review. Empty otherwise.
+	PreviousStatus string
+
+	// UnderReview reports whether a cancellation of the NFS-e awaits fiscal review.
+	UnderReview bool
+
+	// BlockedEvents lists the event types blocked ex officio by the municipality
+	// (e305102) and not unblocked yet. Each event type is blocked at most once.
+	BlockedEvents []string
+}
+
+// IsTerminal reports whether no further event can change the lifecycle.
+func (s LifecycleState) IsTerminal() bool {
+	return s.Status == LifecycleCancelled || s.Status == LifecycleSubstituted
+}
+
+// isSuspended reports whether the NFS-e is in a temporary state that keeps
+// track of the status to restore.
+func (s LifecycleState) isSuspended() bool {
+	return s.Status == LifecycleBlocked || s.Status == LifecycleCancellationUnderReview
+}
+
+// Equal reports whether both states are the same.
+func (s LifecycleState) Equal(other LifecycleState) bool {
+	if s.Status != other.Status || s.PreviousStatus != other.PreviousStatus || s.UnderReview != other.UnderReview {
+		return false
+	}
+	if len(s.BlockedEvents) != len(other.BlockedEvents) {
+		return false
+	}
+	for i := range s.BlockedEvents {
+		if s.BlockedEvents[i] != other.BlockedEvents[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the lifecycle state that results from linking an event of the
+// given type to the NFS-e, and whether the state changed. For a block (e305102)
+// or an unblock (e305103), target is the event type blocked or unblocked; it is
+// ignored for other event types. Unknown event types and events received after a
+// terminal state leave the state unchanged.
+func (s LifecycleState) Apply(eventType, target string) (LifecycleState, bool) {
+	if s.Status == "" {
+		s.Status = LifecycleActive
+	}
+	if s.IsTerminal() {
+		return s, false
+	}
+
+	next := s
+	switch eventType {
+	case TypeCancellation, TypeCancellationReviewApproved, TypeOfficialCancellation:
+		next = LifecycleState{Status: LifecycleCancelled}
+
+	case TypeCancellationBySubstitution:
+		next = LifecycleState{Status: LifecycleSubstituted}
+
+	case TypeCancellationReviewRequest:
+		next = s.with(s.settled(), true, s.BlockedEvents)
+
+	case TypeCancellationReviewDenied:
+		if s.underReview() {
+			next = s.with(s.settled(), false, s.BlockedEvents)
+		}
+
+	case TypeOfficialBlock:
+		next = s.with(s.settled(), s.underReview(), s.block(target))
+
+	case TypeOfficialUnblock:
+		next = s.with(s.settled(), s.underReview(), s.unblock(target))
+
+	case TypeProviderConfirmation, TypeTakerConfirmation, TypeIntermediaryConfirmation, TypeTacitConfirmation:
+		next = s.with(LifecycleConfirmed, s.underReview(), s.BlockedEvents)
+
+	case TypeProviderRejection, TypeTakerRejection, TypeIntermediaryRejection:
+		next = s.with(LifecycleRejected, s.underReview(), s.BlockedEvents)
+
+	case TypeRejectionAnnulment:
+		next = s.with(LifecycleActive, s.underReview(), s.BlockedEvents)
+
+	default:
+		return s, false
+	}
+
+	return next, !next.Equal(s)
+}
+
+// settled returns the status the NFS-e holds apart from blocks and cancellation review.
+func (s LifecycleState) settled() string {
+	if !s.isSuspended() {
+		return s.Status
+	}
+	if s.PreviousStatus == "" {
+		return LifecycleActive
+	}
+	return s.PreviousStatus
+}
+
+// underReview reports whether a cancellation awaits fiscal review. States stored
+// before the flag existed only carry the review in their status.
+func (s LifecycleState) underReview() bool {
+	return s.UnderReview || s.Status == LifecycleCancellationUnderReview
+}
+
+// block returns the blocked event types with the target added.
+func (s LifecycleState) block(target string) []string {
+	for _, blocked := range s.BlockedEvents {
+		if blocked == target {
+			return s.BlockedEvents
+		}
+	}
+	return append(append([]string(nil), s.BlockedEvents...), target)
+}
+
+// unblock returns the blocked event types without the target. An unblock whose
+// target is unknown lifts the block only when a single one is pending.
+func (s LifecycleState) unblock(target string) []string {
+	if target == "" && len(s.BlockedEvents) == 1 {
+		return nil
+	}
+	blocked := make([]string, 0, len(s.BlockedEvents))
+	for _, eventType := range s.BlockedEvents {
+		if eventType != target {
+			blocked = append(blocked, eventType)
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	return blocked
+}
+
+// with builds the state for the given settled status and temporary conditions.
+// Blocks take precedence over the cancellation review in the reported status.
+func (s LifecycleState) with(settled string, underReview bool, blocked []string) LifecycleState {
+	switch {
+	case len(blocked) > 0:
+		return LifecycleState{Status: LifecycleBlocked, PreviousStatus: settled, UnderReview: underReview, BlockedEvents: blocked}
+	case underReview:
+		return LifecycleState{Status: LifecycleCancellationUnderReview, PreviousStatus: settled, UnderReview: true}
+	default:
+		return LifecycleState{Status: settled}
+	}
+}
@@ -0,0 +1,239 @@
+package event
+
+import "testing"
+
+func TestLifecycleState_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       LifecycleState
+		eventType   string
+		target      string
+		want        LifecycleState
+		wantChanged bool
+	}{
+		{
+			name:        "empty state is treated as active",
+			state:       LifecycleState{},
+			eventType:   TypeCancellation,
+			want:        LifecycleState{Status: LifecycleCancelled},
+			wantChanged: true,
+		},
+		{
+			name:        "cancellation by substitution",
+			state:       LifecycleState{Status: LifecycleConfirmed},
+			eventType:   TypeCancellationBySubstitution,
+			want:        LifecycleState{Status: LifecycleSubstituted},
+			wantChanged: true,
+		},
+		{
+			name:        "official cancellation",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive},
+			eventType:   TypeOfficialCancellation,
+			want:        LifecycleState{Status: LifecycleCancelled},
+			wantChanged: true,
+		},
+		{
+			name:        "terminal state ignores events",
+			state:       LifecycleState{Status: LifecycleCancelled},
+			eventType:   TypeTakerConfirmation,
+			want:        LifecycleState{Status: LifecycleCancelled},
+			wantChanged: false,
+		},
+		{
+			name:        "cancellation sent to fiscal review",
+			state:       LifecycleState{Status: LifecycleConfirmed},
+			eventType:   TypeCancellationReviewRequest,
+			want:        LifecycleState{Status: LifecycleCancellationUnderReview, PreviousStatus: LifecycleConfirmed, UnderReview: true},
+			wantChanged: true,
+		},
+		{
+			name:        "cancellation approved by fiscal review",
+			state:       LifecycleState{Status: LifecycleCancellationUnderReview, PreviousStatus: LifecycleActive},
+			eventType:   TypeCancellationReviewApproved,
+			want:        LifecycleState{Status: LifecycleCancelled},
+			wantChanged: true,
+		},
+		{
+			name:        "cancellation denied restores previous status",
+			state:       LifecycleState{Status: LifecycleCancellationUnderReview, PreviousStatus: LifecycleConfirmed, UnderReview: true},
+			eventType:   TypeCancellationReviewDenied,
+			want:        LifecycleState{Status: LifecycleConfirmed},
+			wantChanged: true,
+		},
+		{
+			name:        "denial without review is ignored",
+			state:       LifecycleState{Status: LifecycleActive},
+			eventType:   TypeCancellationReviewDenied,
+			want:        LifecycleState{Status: LifecycleActive},
+			wantChanged: false,
+		},
+		{
+			name:        "block keeps status to restore",
+			state:       LifecycleState{Status: LifecycleRejected},
+			eventType:   TypeOfficialBlock,
+			target:      TypeCancellation,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleRejected, BlockedEvents: []string{TypeCancellation}},
+			wantChanged: true,
+		},
+		{
+			name:        "block during review keeps the review pending",
+			state:       LifecycleState{Status: LifecycleCancellationUnderReview, PreviousStatus: LifecycleConfirmed, UnderReview: true},
+			eventType:   TypeOfficialBlock,
+			target:      TypeCancellationReviewApproved,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleConfirmed, UnderReview: true, BlockedEvents: []string{TypeCancellationReviewApproved}},
+			wantChanged: true,
+		},
+		{
+			name:        "unblock restores previous status",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleRejected, BlockedEvents: []string{TypeCancellation}},
+			eventType:   TypeOfficialUnblock,
+			target:      TypeCancellation,
+			want:        LifecycleState{Status: LifecycleRejected},
+			wantChanged: true,
+		},
+		{
+			name:        "unblock without previous status returns to active",
+			state:       LifecycleState{Status: LifecycleBlocked, BlockedEvents: []string{TypeCancellation}},
+			eventType:   TypeOfficialUnblock,
+			target:      TypeCancellation,
+			want:        LifecycleState{Status: LifecycleActive},
+			wantChanged: true,
+		},
+		{
+			name:        "block of another event type stacks",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation}},
+			eventType:   TypeOfficialBlock,
+			target:      TypeOfficialCancellation,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation, TypeOfficialCancellation}},
+			wantChanged: true,
+		},
+		{
+			name:        "repeated block of the same event type is ignored",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation}},
+			eventType:   TypeOfficialBlock,
+			target:      TypeCancellation,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation}},
+			wantChanged: false,
+		},
+		{
+			name:        "unblock keeps the other blocks",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleConfirmed, BlockedEvents: []string{TypeCancellation, TypeOfficialCancellation}},
+			eventType:   TypeOfficialUnblock,
+			target:      TypeCancellation,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleConfirmed, BlockedEvents: []string{TypeOfficialCancellation}},
+			wantChanged: true,
+		},
+		{
+			name:        "unblock of an unknown block is ignored",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation, TypeOfficialCancellation}},
+			eventType:   TypeOfficialUnblock,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation, TypeOfficialCancellation}},
+			wantChanged: false,
+		},
+		{
+			name:        "last unblock during review returns to review",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleConfirmed, UnderReview: true, BlockedEvents: []string{TypeCancellationReviewApproved}},
+			eventType:   TypeOfficialUnblock,
+			target:      TypeCancellationReviewApproved,
+			want:        LifecycleState{Status: LifecycleCancellationUnderReview, PreviousStatus: LifecycleConfirmed, UnderReview: true},
+			wantChanged: true,
+		},
+		{
+			name:        "denial while blocked ends the review and keeps the block",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleConfirmed, UnderReview: true, BlockedEvents: []string{TypeCancellation}},
+			eventType:   TypeCancellationReviewDenied,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleConfirmed, BlockedEvents: []string{TypeCancellation}},
+			wantChanged: true,
+		},
+		{
+			name:        "taker confirmation",
+			state:       LifecycleState{Status: LifecycleActive},
+			eventType:   TypeTakerConfirmation,
+			want:        LifecycleState{Status: LifecycleConfirmed},
+			wantChanged: true,
+		},
+		{
+			name:        "tacit confirmation",
+			state:       LifecycleState{Status: LifecycleActive},
+			eventType:   TypeTacitConfirmation,
+			want:        LifecycleState{Status: LifecycleConfirmed},
+			wantChanged: true,
+		},
+		{
+			name:        "repeated confirmation does not change state",
+			state:       LifecycleState{Status: LifecycleConfirmed},
+			eventType:   TypeProviderConfirmation,
+			want:        LifecycleState{Status: LifecycleConfirmed},
+			wantChanged: false,
+		},
+		{
+			name:        "rejection",
+			state:       LifecycleState{Status: LifecycleConfirmed},
+			eventType:   TypeIntermediaryRejection,
+			want:        LifecycleState{Status: LifecycleRejected},
+			wantChanged: true,
+		},
+		{
+			name:        "rejection annulment",
+			state:       LifecycleState{Status: LifecycleRejected},
+			eventType:   TypeRejectionAnnulment,
+			want:        LifecycleState{Status: LifecycleActive},
+			wantChanged: true,
+		},
+		{
+			name:        "manifestation while blocked only updates status to restore",
+			state:       LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleActive, BlockedEvents: []string{TypeCancellation}},
+			eventType:   TypeTakerRejection,
+			want:        LifecycleState{Status: LifecycleBlocked, PreviousStatus: LifecycleRejected, BlockedEvents: []string{TypeCancellation}},
+			wantChanged: true,
+		},
+		{
+			name:        "unknown event type",
+			state:       LifecycleState{Status: LifecycleActive},
+			eventType:   "EMISSAO",
+			want:        LifecycleState{Status: LifecycleActive},
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := tt.state.Apply(tt.eventType, tt.target)
+			if !got.Equal(tt.want) {
+				t.Errorf("Apply(%s) = %+v, want %+v", tt.eventType, got, tt.want)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("Apply(%s) changed = %v, want %v", tt.eventType, changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestLifecycleState_ApplyStackedBlocks(t *testing.T) {
+	blocked := []string{TypeCancellation, TypeCancellationReviewApproved, TypeCancellationReviewDenied, TypeOfficialCancellation}
+
+	state := LifecycleState{Status: LifecycleConfirmed}
+	state, _ = state.Apply(TypeCancellationReviewRequest, "")
+	for _, target := range blocked {
+		state, _ = state.Apply(TypeOfficialBlock, target)
+	}
+	if state.Status != LifecycleBlocked || len(state.BlockedEvents) != len(blocked) {
+		t.Fatalf("expected %d blocks, got %+v", len(blocked), state)
+	}
+
+	for i, target := range blocked {
+		state, _ = state.Apply(TypeOfficialUnblock, target)
+		if i < len(blocked)-1 && state.Status != LifecycleBlocked {
+			t.Fatalf("expected NFS-e to stay blocked after unblocking %s, got %+v", target, state)
+		}
+	}
+	if state.Status != LifecycleCancellationUnderReview {
+		t.Fatalf("expected cancellation review to be restored, got %+v", state)
+	}
+
+	state, _ = state.Apply(TypeCancellationReviewDenied, "")
+	want := LifecycleState{Status: LifecycleConfirmed}
+	if !state.Equal(want) {
+		t.Errorf("expected %+v after denial, got %+v", want, state)
+	}
+}
@@ -0,0 +1,140 @@
+package event
+
+import "github.com/eduardo/nfse-nacional/internal/domain/emission"
+
+// Actor roles that may register manifestation events for an NFS-e.
+const (
+	// RoleProvider is the service provider (prestador) of the NFS-e.
+	RoleProvider = "provider"
+
+	// RoleTaker is the service taker (tomador) of the NFS-e.
+	RoleTaker = "taker"
+
+	// RoleIntermediary is the service intermediary (intermediário) of the NFS-e.
+	RoleIntermediary = "intermediary"
+
+	// RoleMunicipality is the municipal tax administration, represented by a tax agent.
+	RoleMunicipality = "municipality"
+)
+
+// Manifestation actions.
+const (
+	// ActionConfirm confirms the NFS-e.
+	ActionConfirm = "confirm"
+
+	// ActionReject rejects the NFS-e.
+	ActionReject = "reject"
+
+	// ActionAnnulRejection annuls a previously registered rejection.
+	ActionAnnulRejection = "annul_rejection"
+)
+
+// Rejection reason codes (cMotivo) accepted by the rejection events.
+const (
+	// RejectionReasonDuplicate indicates a duplicated NFS-e.
+	RejectionReasonDuplicate = 1
+
+	// RejectionReasonIssuedByTaker indicates the NFS-e was already issued by the taker.
+	RejectionReasonIssuedByTaker = 2
+
+	// RejectionReasonNoTaxableEvent indicates the taxable event did not occur.
+	RejectionReasonNoTaxableEvent = 3
+
+	// RejectionReasonTaxLiability indicates an error in the tax liability.
+	RejectionReasonTaxLiability = 4
+
+	// RejectionReasonValues indicates an error in the service, its values or the taxable event date.
+	RejectionReasonValues = 5
+
+	// RejectionReasonOther indicates any other reason.
+	RejectionReasonOther = 9
+)
+
+// manifestationEvents lists the events each role may register, by action.
+// The tacit confirmation (e205204) is registered by the Sistema Nacional itself.
+var manifestationEvents = map[string]map[string]string{
+	RoleProvider: {
+		ActionConfirm: TypeProviderConfirmation,
+		ActionReject:  TypeProviderRejection,
+	},
+	RoleTaker: {
+		ActionConfirm: TypeTakerConfirmation,
+		ActionReject:  TypeTakerRejection,
+	},
+	RoleIntermediary: {
+		ActionConfirm: TypeIntermediaryConfirmation,
+		ActionReject:  TypeIntermediaryRejection,
+	},
+	RoleMunicipality: {
+		ActionAnnulRejection: TypeRejectionAnnulment,
+	},
+}
+
+// ManifestationEventType returns the event type a role registers for an action.
+// The boolean is false when the role may not perform the action.
+func ManifestationEventType(role, action string) (string, bool) {
+	eventType, ok := manifestationEvents[role][action]
+	return eventType, ok
+}
+
+// IsValidRole reports whether role is a known manifestation role.
+func IsValidRole(role string) bool {
+	_, ok := manifestationEvents[role]
+	return ok
+}
+
+// IsValidAction reports whether action is a known manifestation action.
+func IsValidAction(action string) bool {
+	switch action {
+	case ActionConfirm, ActionReject, ActionAnnulRejection:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRejectionType reports whether eventType is a rejection manifestation
+// (e202205, e203206 or e204207).
+func IsRejectionType(eventType string) bool {
+	switch eventType {
+	case TypeProviderRejection, TypeTakerRejection, TypeIntermediaryRejection:
+		return true
+	default:
+		return false
+	}
+}
+
+// ManifestationRequest represents the incoming request to register a manifestation
+// (confirmation, rejection or rejection annulment) for an NFS-e.
+// This DTO matches POST /v1/nfse/{chaveAcesso}/manifest.
+type ManifestationRequest struct {
+	// Role is the author's role in the NFS-e: provider, taker, intermediary or municipality.
+	Role string `json:"role"`
+
+	// Action is the manifestation: confirm, reject or annul_rejection.
+	Action string `json:"action"`
+
+	// Author identifies who is registering the manifestation.
+	Author AuthorRequest `json:"author"`
+
+	// ReasonCode is the rejection reason (cMotivo): 1-5 or 9. Required for rejections.
+	ReasonCode int `json:"reason_code,omitempty"`
+
+	// Reason is the free-text justification (xMotivo), 15-255 characters.
+	// Optional for rejections (required for reason 9), required for annulments.
+	Reason string `json:"reason,omitempty"`
+
+	// TaxAgentCPF is the CPF of the municipal tax agent (CPFAgTrib). Required for annulments.
+	TaxAgentCPF string `json:"tax_agent_cpf,omitempty"`
+
+	// RejectionEventID is the Id of the rejection event being annulled (idEvManifRej).
+	// Required for annulments.
+	RejectionEventID string `json:"rejection_event_id,omitempty"`
+
+	// Certificate contains the author's digital certificate, used to sign the
+	// event and to authenticate with the government API.
+	Certificate *emission.CertificateRequest `json:"certificate"`
+
+	// WebhookURL is an optional override for the webhook URL configured in the API key.
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
@@ -0,0 +1,165 @@
+// Package event provides DTOs and business logic for NFS-e event operations
+// (pedidos de registro de evento), such as cancellation and manifestation.
+package event
+
+import "github.com/eduardo/nfse-nacional/internal/domain/emission"
+
+// Event type codes (tipo de evento) as defined by the Sistema Nacional NFS-e.
+const (
+	// TypeCancellation is the NFS-e cancellation event requested by the provider.
+	TypeCancellation = "e101101"
+
+	// TypeCancellationBySubstitution is the cancellation of an NFS-e that was
+	// replaced by a substitute NFS-e.
+	TypeCancellationBySubstitution = "e105102"
+
+	// TypeCancellationReviewRequest is the request for a fiscal review of a
+	// cancellation that cannot be registered directly (e101103).
+	TypeCancellationReviewRequest = "e101103"
+
+	// TypeCancellationReviewApproved is the cancellation granted by fiscal review (e105104).
+	TypeCancellationReviewApproved = "e105104"
+
+	// TypeCancellationReviewDenied is the cancellation denied by fiscal review (e105105).
+	TypeCancellationReviewDenied = "e105105"
+
+	// TypeProviderConfirmation is the confirmation of the NFS-e by its provider.
+	TypeProviderConfirmation = "e202201"
+
+	// TypeTakerConfirmation is the confirmation of the NFS-e by its taker.
+	TypeTakerConfirmation = "e203202"
+
+	// TypeIntermediaryConfirmation is the confirmation of the NFS-e by its intermediary.
+	TypeIntermediaryConfirmation = "e204203"
+
+	// TypeTacitConfirmation is the tacit confirmation registered by the Sistema Nacional
+	// when no manifestation is made in time. It cannot be requested through the API.
+	TypeTacitConfirmation = "e205204"
+
+	// TypeProviderRejection is the rejection of the NFS-e by its provider.
+	TypeProviderRejection = "e202205"
+
+	// TypeTakerRejection is the rejection of the NFS-e by its taker.
+	TypeTakerRejection = "e203206"
+
+	// TypeIntermediaryRejection is the rejection of the NFS-e by its intermediary.
+	TypeIntermediaryRejection = "e204207"
+
+	// TypeRejectionAnnulment is the annulment of a rejection by the municipal tax administration.
+	TypeRejectionAnnulment = "e205208"
+
+	// TypeOfficialCancellation is the cancellation ex officio by the municipality (e305101).
+	TypeOfficialCancellation = "e305101"
+
+	// TypeOfficialBlock is the blocking ex officio of the NFS-e by the municipality (e305102).
+	TypeOfficialBlock = "e305102"
+
+	// TypeOfficialUnblock is the unblocking ex officio of the NFS-e by the municipality (e305103).
+	TypeOfficialUnblock = "e305103"
+)
+
+// TypeDescriptions maps event type codes to their official descriptions (xDesc).
+var TypeDescriptions = map[string]string{
+	TypeCancellation:               "Cancelamento de NFS-e",
+	TypeCancellationBySubstitution: "Cancelamento de NFS-e por Substituição",
+	TypeCancellationReviewRequest:  "Solicitação de Análise Fiscal para Cancelamento de NFS-e",
+	TypeCancellationReviewApproved: "Cancelamento de NFS-e Deferido por Análise Fiscal",
+	TypeCancellationReviewDenied:   "Cancelamento de NFS-e Indeferido por Análise Fiscal",
+	TypeProviderConfirmation:       "Manifestação de NFS-e - Confirmação do Prestador",
+	TypeTakerConfirmation:          "Manifestação de NFS-e - Confirmação do Tomador",
+	TypeIntermediaryConfirmation:   "Manifestação de NFS-e - Confirmação do Intermediário",
+	TypeTacitConfirmation:          "Manifestação de NFS-e - Confirmação Tácita",
+	TypeProviderRejection:          "Manifestação de NFS-e - Rejeição do Prestador",
+	TypeTakerRejection:             "Manifestação de NFS-e - Rejeição do Tomador",
+	TypeIntermediaryRejection:      "Manifestação de NFS-e - Rejeição do Intermediário",
+	TypeRejectionAnnulment:         "Manifestação de NFS-e - Anulação da Rejeição",
+	TypeOfficialCancellation:       "Cancelamento de NFS-e por Ofício",
+	TypeOfficialBlock:              "Bloqueio de NFS-e por Ofício",
+	TypeOfficialUnblock:            "Desbloqueio de NFS-e por Ofício",
+}
+
+// Cancellation reason codes (cMotivo) accepted by the e101101 event.
+const (
+	// CancellationReasonEmissionError indicates an error in the emission.
+	CancellationReasonEmissionError = 1
+
+	// CancellationReasonServiceNotProvided indicates the service was not provided.
+	CancellationReasonServiceNotProvided = 2
+
+	// CancellationReasonOther indicates any other reason.
+	CancellationReasonOther = 9
+)
+
+// Substitution reason codes (cMotivo) accepted by the subst group of the DPS
+// and by the e105102 event.
+const (
+	// SubstitutionReasonSimplesExclusion indicates the NFS-e left the Simples Nacional.
+	SubstitutionReasonSimplesExclusion = "01"
+
+	// SubstitutionReasonSimplesInclusion indicates the NFS-e entered the Simples Nacional.
+	SubstitutionReasonSimplesInclusion = "02"
+
+	// SubstitutionReasonExemptionInclusion indicates a retroactive immunity/exemption inclusion.
+	SubstitutionReasonExemptionInclusion = "03"
+
+	// SubstitutionReasonExemptionExclusion indicates a retroactive immunity/exemption exclusion.
+	SubstitutionReasonExemptionExclusion = "04"
+
+	// SubstitutionReasonTakerRejection indicates the NFS-e was rejected by the taker or intermediary.
+	SubstitutionReasonTakerRejection = "05"
+
+	// SubstitutionReasonOther indicates any other reason.
+	SubstitutionReasonOther = "99"
+)
+
+// AuthorRequest identifies the author of an event (CNPJAutor/CPFAutor).
+type AuthorRequest struct {
+	// CNPJ is the 14-digit tax ID of the author (without formatting).
+	// Mutually exclusive with CPF.
+	CNPJ string `json:"cnpj,omitempty"`
+
+	// CPF is the 11-digit tax ID of the author (without formatting).
+	// Mutually exclusive with CNPJ.
+	CPF string `json:"cpf,omitempty"`
+}
+
+// CancellationRequest represents the incoming request to cancel an NFS-e.
+// This DTO matches POST /v1/nfse/{chaveAcesso}/cancel.
+type CancellationRequest struct {
+	// Author identifies who is requesting the cancellation (the NFS-e provider).
+	Author AuthorRequest `json:"author"`
+
+	// ReasonCode is the cancellation reason (cMotivo): 1, 2 or 9.
+	ReasonCode int `json:"reason_code"`
+
+	// Reason is the free-text justification (xMotivo), 15-255 characters.
+	Reason string `json:"reason"`
+
+	// Certificate contains the author's digital certificate, used to sign the
+	// event and to authenticate with the government API.
+	Certificate *emission.CertificateRequest `json:"certificate"`
+
+	// WebhookURL is an optional override for the webhook URL configured in the API key.
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// ReplacementRequest represents the incoming request to replace an NFS-e.
+// This DTO matches POST /v1/nfse/{chaveAcesso}/replace: the corrected emission
+// payload plus the substitution reason. The substitute NFS-e is emitted first
+// and the replaced one is then cancelled by substitution (e105102).
+type ReplacementRequest struct {
+	emission.EmissionRequest
+
+	// Substitution contains the reason for replacing the NFS-e.
+	Substitution SubstitutionRequest `json:"substitution"`
+}
+
+// SubstitutionRequest contains the substitution reason (subst group).
+type SubstitutionRequest struct {
+	// ReasonCode is the substitution reason (cMotivo): "01"-"05" or "99".
+	ReasonCode string `json:"reason_code"`
+
+	// Reason is the free-text justification (xMotivo), 15-255 characters.
+	// Required when ReasonCode is "99" (other).
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,137 @@
+package event
+
+import (
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// EventAccepted represents the response when an event request is accepted (202).
+type EventAccepted struct {
+	// RequestID is the unique identifier for tracking this event request.
+	RequestID string `json:"request_id"`
+
+	// Status indicates the current status of the request (always "pending" on creation).
+	Status string `json:"status"`
+
+	// Message provides additional context about the request.
+	Message string `json:"message"`
+
+	// StatusURL is the URL to poll for status updates.
+	StatusURL string `json:"status_url"`
+}
+
+// StatusResponse represents the response from GET /v1/events/{requestId}.
+type StatusResponse struct {
+	// RequestID is the unique identifier for this event request.
+	RequestID string `json:"request_id"`
+
+	// Status indicates the current status: pending, processing, success, or failed.
+	Status string `json:"status"`
+
+	// ChaveAcesso is the access key of the NFS-e the event refers to.
+	ChaveAcesso string `json:"chave_acesso"`
+
+	// EventType is the event type code (e.g., "e101101").
+	EventType string `json:"event_type"`
+
+	// CreatedAt is when the request was first submitted.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the request was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// ProcessedAt is when the request was processed (only if completed).
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+
+	// Manifestation contains the manifestation data (only for manifestation events).
+	Manifestation *ManifestationDTO `json:"manifestation,omitempty"`
+
+	// Result contains the registered event (only on success).
+	Result *ResultDTO `json:"result,omitempty"`
+
+	// Error contains the error details (only on failure).
+	Error *emission.EmissionErrorDTO `json:"error,omitempty"`
+}
+
+// ManifestationDTO describes a confirmation, rejection or rejection annulment.
+type ManifestationDTO struct {
+	// Role is the author's role in the NFS-e.
+	Role string `json:"role"`
+
+	// Action is the manifestation: confirm, reject or annul_rejection.
+	Action string `json:"action"`
+
+	// ReasonCode is the rejection reason (cMotivo), if any.
+	ReasonCode int `json:"reason_code,omitempty"`
+
+	// Reason is the free-text justification (xMotivo), if any.
+	Reason string `json:"reason,omitempty"`
+
+	// TaxAgentCPF is the CPF of the municipal tax agent (annulments only).
+	TaxAgentCPF string `json:"tax_agent_cpf,omitempty"`
+
+	// RejectionEventID is the Id of the annulled rejection event (annulments only).
+	RejectionEventID string `json:"rejection_event_id,omitempty"`
+}
+
+// ResultDTO contains the event registered by the government API.
+type ResultDTO struct {
+	// EventType is the event type code (e.g., "e101101").
+	EventType string `json:"event_type"`
+
+	// Description is the human-readable event description.
+	Description string `json:"description"`
+
+	// Sequence is the sequential event number assigned by SEFIN (nSeqEvento).
+	Sequence int `json:"sequence"`
+
+	// RegisteredAt is the government processing timestamp (dhProc).
+	RegisteredAt *time.Time `json:"registered_at,omitempty"`
+
+	// XML is the registered event XML document.
+	XML string `json:"xml,omitempty"`
+}
+
+// WebhookPayload represents the payload sent to webhook endpoints for event requests.
+type WebhookPayload struct {
+	// Event indicates the type of webhook event.
+	Event string `json:"event"`
+
+	// RequestID is the unique identifier for this event request.
+	RequestID string `json:"request_id"`
+
+	// Timestamp is when this webhook was generated.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Status indicates the final status: success or failed.
+	Status string `json:"status"`
+
+	// ChaveAcesso is the access key of the NFS-e the event refers to.
+	ChaveAcesso string `json:"chave_acesso"`
+
+	// EventType is the event type code (e.g., "e101101").
+	EventType string `json:"event_type"`
+
+	// Manifestation contains the manifestation data (only for manifestation events).
+	Manifestation *ManifestationDTO `json:"manifestation,omitempty"`
+
+	// Result contains the registered event (only on success).
+	Result *ResultDTO `json:"result,omitempty"`
+
+	// Lifecycle contains the fiscal state of the NFS-e after the event (only on
+	// success, and only for NFS-e emitted through this API).
+	Lifecycle *emission.LifecycleDTO `json:"lifecycle,omitempty"`
+
+	// Error contains the error details (only on failure).
+	Error *emission.EmissionErrorDTO `json:"error,omitempty"`
+}
+
+// WebhookEvent constants define the types of webhook events for event requests.
+const (
+	// WebhookEventRegistered indicates the event was registered by the government API.
+	WebhookEventRegistered = "event.registered"
+
+	// WebhookEventFailed indicates the event registration failed.
+	WebhookEventFailed = "event.failed"
+)
@@ -0,0 +1,47 @@
+// Package municipality provides DTOs for the municipal parameters of the
+// Sistema Nacional NFS-e, which define how each municipality takes part in it.
+package municipality
+
+import "time"
+
+// ConvenioResponse represents the agreement (convênio) of a municipality with
+// the Sistema Nacional NFS-e.
+type ConvenioResponse struct {
+	// CodigoMunicipio is the 7-digit IBGE code of the municipality.
+	CodigoMunicipio string `json:"codigo_municipio"`
+
+	// Conveniado indicates the municipality has an agreement with the Sistema Nacional NFS-e.
+	Conveniado bool `json:"conveniado"`
+
+	// AderenteEmissorNacional indicates the municipality adhered to the national emitter.
+	AderenteEmissorNacional bool `json:"aderente_emissor_nacional"`
+
+	// AderenteAmbienteNacional indicates the municipality adhered to the national
+	// data environment (ADN).
+	AderenteAmbienteNacional bool `json:"aderente_ambiente_nacional"`
+
+	// AderenteMAN indicates the municipality adhered to the national tax
+	// assessment module (MAN).
+	AderenteMAN bool `json:"aderente_man"`
+
+	// PermiteAproveitamentoCreditos indicates the municipality allows the use of tax credits.
+	PermiteAproveitamentoCreditos bool `json:"permite_aproveitamento_creditos"`
+
+	// CanEmit indicates NFS-e issued in the municipality can be emitted through this API.
+	CanEmit bool `json:"can_emit"`
+
+	// ConsultedAt is when the parameters were retrieved from the government API.
+	ConsultedAt time.Time `json:"consulted_at"`
+}
+
+// CacheInvalidationResponse reports the municipal parameters removed from the cache.
+type CacheInvalidationResponse struct {
+	// Resource is the invalidated resource, or empty when every resource was invalidated.
+	Resource string `json:"resource,omitempty"`
+
+	// CodigoMunicipio is the invalidated municipality, or empty when every municipality was invalidated.
+	CodigoMunicipio string `json:"codigo_municipio,omitempty"`
+
+	// Deleted is the number of removed cache entries.
+	Deleted int `json:"deleted"`
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Event query constants.
+const (
+	// MaxEventSequence is the highest event sequence number (nSeqEvento) accepted by the national API.
+	MaxEventSequence = 999
+)
+
+// Error definitions for event query validation.
+var (
+	// ErrEventTypeEmpty indicates an empty event type was provided.
+	ErrEventTypeEmpty = errors.New("event type cannot be empty")
+
+	// ErrEventTypeInvalid indicates the event type is not in the "e" + 6 digits format.
+	ErrEventTypeInvalid = errors.New("event type must be 'e' followed by 6 digits")
+
+	// ErrEventSequenceInvalid indicates the event sequence number is not an integer between 1 and 999.
+	ErrEventSequenceInvalid = errors.New("event sequence number must be an integer between 1 and 999")
+)
+
+// eventTypeRegex validates an event type code (e.g., "e101101").
+var eventTypeRegex = regexp.MustCompile(`^e\d{6}$`)
+
+// ValidateEventType validates an NFS-e event type code (tipoEvento).
+//
+// Example valid event type: "e101101" (cancellation)
+func ValidateEventType(tipo string) error {
+	tipo = strings.TrimSpace(tipo)
+
+	if tipo == "" {
+		return ErrEventTypeEmpty
+	}
+
+	if !eventTypeRegex.MatchString(tipo) {
+		return fmt.Errorf("%w: got '%s'", ErrEventTypeInvalid, tipo)
+	}
+
+	return nil
+}
+
+// ParseEventSequence parses and validates an event sequence number (numSeqEvento).
+// Returns the sequence number, or ErrEventSequenceInvalid if it is not between 1 and 999.
+func ParseEventSequence(value string) (int, error) {
+	seq, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seq < 1 || seq > MaxEventSequence {
+		return 0, ErrEventSequenceInvalid
+	}
+	return seq, nil
+}
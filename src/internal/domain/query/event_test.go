@@ -0,0 +1,100 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateEventType(t *testing.T) {
+	tests := []struct {
+		name    string
+		tipo    string
+		wantErr error
+	}{
+		{
+			name:    "cancellation",
+			tipo:    "e101101",
+			wantErr: nil,
+		},
+		{
+			name:    "valid with whitespace trimmed",
+			tipo:    " e105102 ",
+			wantErr: nil,
+		},
+		{
+			name:    "empty string",
+			tipo:    "",
+			wantErr: ErrEventTypeEmpty,
+		},
+		{
+			name:    "missing prefix",
+			tipo:    "101101",
+			wantErr: ErrEventTypeInvalid,
+		},
+		{
+			name:    "uppercase prefix",
+			tipo:    "E101101",
+			wantErr: ErrEventTypeInvalid,
+		},
+		{
+			name:    "too short",
+			tipo:    "e10110",
+			wantErr: ErrEventTypeInvalid,
+		},
+		{
+			name:    "non-digit characters",
+			tipo:    "e10110a",
+			wantErr: ErrEventTypeInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEventType(tt.tipo)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ValidateEventType(%q) unexpected error: %v", tt.tipo, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateEventType(%q) error = %v, want %v", tt.tipo, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseEventSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "first event", value: "1", want: 1},
+		{name: "maximum", value: "999", want: 999},
+		{name: "zero", value: "0", wantErr: true},
+		{name: "negative", value: "-1", wantErr: true},
+		{name: "above maximum", value: "1000", wantErr: true},
+		{name: "not a number", value: "abc", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEventSequence(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrEventSequenceInvalid) {
+					t.Errorf("ParseEventSequence(%q) error = %v, want %v", tt.value, err, ErrEventSequenceInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEventSequence(%q) unexpected error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEventSequence(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,10 @@ type NFSeData struct {
 	// DataEmissao is the emission date/time.
 	DataEmissao time.Time
 
+	// DataCompetencia is the competence date of the service (dCompet of the DPS).
+	// Falls back to the emission date when the DPS is not embedded in the XML.
+	DataCompetencia time.Time
+
 	// Status indicates the NFS-e status (e.g., "100" for normal/active).
 	Status string
 
@@ -137,6 +141,22 @@ type infNFSeXML struct {
 
 	// Valores contains the monetary values.
 	Valores valoresXML `xml:"valores"`
+
+	// DPS is the DPS that originated the NFS-e, when embedded in the XML.
+	DPS *dpsXML `xml:"DPS,omitempty"`
+}
+
+// dpsXML represents the DPS element embedded in the NFS-e.
+type dpsXML struct {
+	// InfDPS contains the DPS data.
+	InfDPS infDPSXML `xml:"infDPS"`
+}
+
+// infDPSXML represents the infDPS element, of which only the fields not
+// repeated in infNFSe are parsed.
+type infDPSXML struct {
+	// DataCompetencia is the competence date (YYYY-MM-DD).
+	DCompet string `xml:"dCompet"`
 }
 
 // emitXML represents the provider (emitente) element.
@@ -262,6 +282,16 @@ func ParseNFSeXML(xmlContent string) (*NFSeData, error) {
 		dataEmissao = time.Time{}
 	}
 
+	// Parse competence date, defaulting to the emission date
+	dataCompetencia := dataEmissao
+	if info.DPS != nil && info.DPS.InfDPS.DCompet != "" {
+		if compet, err := time.Parse("2006-01-02", info.DPS.InfDPS.DCompet); err == nil {
+			dataCompetencia = compet
+		} else {
+			log.Printf("WARN: Failed to parse competence date '%s': %v", info.DPS.InfDPS.DCompet, err)
+		}
+	}
+
 	// Extract access key from ID attribute or chNFSe element
 	chaveAcesso := info.ChNFSe
 	if chaveAcesso == "" && info.ID != "" {
@@ -274,10 +304,11 @@ func ParseNFSeXML(xmlContent string) (*NFSeData, error) {
 
 	// Build result
 	result := &NFSeData{
-		ChaveAcesso: chaveAcesso,
-		Numero:      info.NNFSe,
-		DataEmissao: dataEmissao,
-		Status:      mapStatus(info.Sit),
+		ChaveAcesso:     chaveAcesso,
+		Numero:          info.NNFSe,
+		DataEmissao:     dataEmissao,
+		DataCompetencia: dataCompetencia,
+		Status:          mapStatus(info.Sit),
 		Prestador: PrestadorData{
 			Documento:       info.Emit.CNPJ,
 			Nome:            info.Emit.XNome,
@@ -404,6 +435,10 @@ func (n *NFSeData) ToQueryResponse(xml string) *NFSeQueryResponse {
 		XML: xml,
 	}
 
+	if !n.DataCompetencia.IsZero() {
+		response.DataCompetencia = FormatDate(n.DataCompetencia)
+	}
+
 	// Set optional tax values
 	if n.Valores.Aliquota > 0 {
 		response.Valores.SetAliquota(n.Valores.Aliquota)
@@ -92,6 +92,9 @@ func TestParseNFSeXML(t *testing.T) {
 				if data.Valores.ValorISSQN != 50.00 {
 					t.Errorf("Valores.ValorISSQN = %f, want 50.00", data.Valores.ValorISSQN)
 				}
+				if !data.DataCompetencia.Equal(data.DataEmissao) {
+					t.Errorf("DataCompetencia = %v, want emission date %v", data.DataCompetencia, data.DataEmissao)
+				}
 			},
 		},
 		{
@@ -234,6 +237,46 @@ func TestParseNFSeXML(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "NFS-e with embedded DPS competence date",
+			xml: `<?xml version="1.0" encoding="UTF-8"?>
+<NFSe xmlns="http://www.sped.fazenda.gov.br/nfse">
+  <infNFSe>
+    <nNFSe>000000321</nNFSe>
+    <dhEmi>2026-02-03T09:00:00-03:00</dhEmi>
+    <chNFSe>NFSe3550308202602031123456789012300000000000032100</chNFSe>
+    <sit>1</sit>
+    <emit>
+      <CNPJ>12345678000199</CNPJ>
+      <xNome>Empresa Teste</xNome>
+    </emit>
+    <serv>
+      <cTribNac>010101</cTribNac>
+      <xDescServ>Servico de janeiro</xDescServ>
+    </serv>
+    <valores>
+      <vServico>300.00</vServico>
+      <vBC>300.00</vBC>
+      <vLiq>300.00</vLiq>
+    </valores>
+    <DPS>
+      <infDPS>
+        <dCompet>2026-01-31</dCompet>
+      </infDPS>
+    </DPS>
+  </infNFSe>
+</NFSe>`,
+			wantErr: false,
+			checkResult: func(t *testing.T, data *NFSeData) {
+				want := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+				if !data.DataCompetencia.Equal(want) {
+					t.Errorf("DataCompetencia = %v, want %v", data.DataCompetencia, want)
+				}
+				if got := data.ToQueryResponse("").DataCompetencia; got != "2026-01-31" {
+					t.Errorf("ToQueryResponse().DataCompetencia = %q, want 2026-01-31", got)
+				}
+			},
+		},
 		{
 			name:    "empty XML",
 			xml:     "",
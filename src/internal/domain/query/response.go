@@ -32,6 +32,9 @@ type NFSeQueryResponse struct {
 	// DataEmissao is the emission date in ISO 8601 format (YYYY-MM-DDTHH:MM:SS-03:00).
 	DataEmissao string `json:"data_emissao"`
 
+	// DataCompetencia is the competence date of the service (YYYY-MM-DD).
+	DataCompetencia string `json:"data_competencia,omitempty"`
+
 	// Status indicates the current NFS-e status code (e.g., "100" for normal).
 	Status string `json:"status"`
 
@@ -217,10 +220,41 @@ type EventInfo struct {
 	// Data is the event timestamp in ISO 8601 format.
 	Data string `json:"data"`
 
+	// DataProcessamento is the event processing timestamp (dhProc) in ISO 8601 format.
+	// Optional: only present when it could be parsed from the event XML.
+	DataProcessamento string `json:"data_processamento,omitempty"`
+
+	// Autor identifies who registered the event.
+	// Optional: only present when it could be parsed from the event XML.
+	Autor *EventAuthor `json:"autor,omitempty"`
+
 	// XML contains the complete signed event XML document.
 	XML string `json:"xml"`
 }
 
+// EventAuthor identifies the author of an NFS-e event.
+type EventAuthor struct {
+	// CNPJ is the author's CNPJ (CNPJAutor), when the author is a company.
+	CNPJ string `json:"cnpj,omitempty"`
+
+	// CPF is the author's CPF (CPFAutor), when the author is an individual.
+	CPF string `json:"cpf,omitempty"`
+}
+
+// ================================================================================
+// Event Query Response (GET /v1/nfse/{chaveAcesso}/eventos/{tipoEvento}/{numSeqEvento})
+// ================================================================================
+
+// EventQueryResponse represents the response for querying a single NFS-e event.
+//
+// Required fields per OpenAPI spec: chave_acesso, tipo, descricao, sequencia, data, xml.
+type EventQueryResponse struct {
+	// ChaveAcesso is the 50-character access key of the NFS-e.
+	ChaveAcesso string `json:"chave_acesso"`
+
+	EventInfo
+}
+
 // EventType constants define the possible event type codes.
 const (
 	// EventTypeEmission indicates the NFS-e was emitted.
@@ -0,0 +1,178 @@
+package taxburden
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// IBPT item types (tipo column).
+const (
+	ibptTypeNCM         = "0"
+	ibptTypeNBS         = "1"
+	ibptTypeServiceItem = "2"
+)
+
+// ibptColumns are the columns of the IBPT table used by the parser.
+var ibptColumns = []string{
+	"codigo", "tipo", "descricao", "nacionalfederal", "estadual", "municipal",
+	"vigenciainicio", "vigenciafim", "versao", "fonte",
+}
+
+// ErrEmptyTable is returned when a table has no service rates.
+var ErrEmptyTable = errors.New("table has no service rates")
+
+// ParseIBPT parses the IBPT "De Olho no Imposto" table of a state: a CSV file
+// separated by semicolons, usually encoded in ISO-8859-1. Only the service rates
+// (NBS codes and LC 116 items) are kept; goods (NCM) are ignored. All rows must
+// belong to the same table version.
+func ParseIBPT(r io.Reader, state string) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range ibptColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	table := &Table{State: strings.ToUpper(state)}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var codeType, code string
+		switch field("tipo") {
+		case ibptTypeNBS:
+			codeType, code = CodeTypeNBS, field("codigo")
+		case ibptTypeServiceItem:
+			codeType, code = CodeTypeServiceItem, leftPad(field("codigo"), 4)
+		case ibptTypeNCM:
+			continue
+		default:
+			return nil, fmt.Errorf("line %d: unknown type %q", line, field("tipo"))
+		}
+
+		version := field("versao")
+		if table.Version == "" {
+			table.Version = version
+			table.Source = decodeLatin1(field("fonte"))
+		} else if version != table.Version {
+			return nil, fmt.Errorf("line %d: version %q differs from table version %q", line, version, table.Version)
+		}
+
+		rate := Rate{
+			CodeType:    codeType,
+			Code:        code,
+			Description: decodeLatin1(field("descricao")),
+		}
+		if rate.FederalRate, err = parseRate(field("nacionalfederal")); err != nil {
+			return nil, fmt.Errorf("line %d: federal rate: %w", line, err)
+		}
+		if rate.StateRate, err = parseRate(field("estadual")); err != nil {
+			return nil, fmt.Errorf("line %d: state rate: %w", line, err)
+		}
+		if rate.MunicipalRate, err = parseRate(field("municipal")); err != nil {
+			return nil, fmt.Errorf("line %d: municipal rate: %w", line, err)
+		}
+		if rate.ValidFrom, err = parseDate(field("vigenciainicio")); err != nil {
+			return nil, fmt.Errorf("line %d: validity start: %w", line, err)
+		}
+		if rate.ValidUntil, err = parseDate(field("vigenciafim")); err != nil {
+			return nil, fmt.Errorf("line %d: validity end: %w", line, err)
+		}
+		if rate.ValidUntil != nil {
+			// The table is valid until the end of its last day
+			end := rate.ValidUntil.Add(24*time.Hour - time.Nanosecond)
+			rate.ValidUntil = &end
+		}
+
+		table.Rates = append(table.Rates, rate)
+	}
+
+	if len(table.Rates) == 0 {
+		return nil, ErrEmptyTable
+	}
+	if table.Version == "" {
+		return nil, fmt.Errorf("table version is empty")
+	}
+
+	return table, nil
+}
+
+// parseRate parses a percentage, accepting a decimal point or comma.
+func parseRate(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	rate, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 100 {
+		return 0, fmt.Errorf("rate %s out of range", value)
+	}
+	return rate, nil
+}
+
+// parseDate parses a date in the dd/mm/yyyy format; empty dates are nil.
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.ParseInLocation("02/01/2006", value, brazilLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// leftPad pads a code with leading zeros up to the given length.
+func leftPad(code string, length int) string {
+	if len(code) >= length {
+		return code
+	}
+	return strings.Repeat("0", length-len(code)) + code
+}
+
+// decodeLatin1 converts an ISO-8859-1 string to UTF-8. Valid UTF-8 is returned unchanged.
+func decodeLatin1(value string) string {
+	if utf8.ValidString(value) {
+		return value
+	}
+	runes := make([]rune, len(value))
+	for i := 0; i < len(value); i++ {
+		runes[i] = rune(value[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,97 @@
+package taxburden
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testIBPTHeader = "codigo;ex;tipo;descricao;nacionalfederal;importadosfederal;estadual;municipal;vigenciainicio;vigenciafim;chave;versao;fonte\n"
+
+func TestParseIBPT(t *testing.T) {
+	csv := testIBPTHeader +
+		"01012100;;0;Cavalos reprodutores;4.20;6.20;18.00;0.00;01/07/2025;31/12/2025;A1B2C3;25.2.A;IBPT/empresometro.com.br\n" +
+		"101011100;;1;Servi\xe7os de constru\xe7\xe3o;13.45;0.00;0.00;2.00;01/07/2025;31/12/2025;A1B2C3;25.2.A;IBPT/empresometro.com.br\n" +
+		"101;;2;An\xe1lise e desenvolvimento de sistemas;13,45;;0;2,90;01/07/2025;31/12/2025;A1B2C3;25.2.A;IBPT/empresometro.com.br\n"
+
+	table, err := ParseIBPT(strings.NewReader(csv), "sp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if table.State != "SP" || table.Version != "25.2.A" || table.Source != "IBPT/empresometro.com.br" {
+		t.Errorf("unexpected table header: %+v", table)
+	}
+	if len(table.Rates) != 2 {
+		t.Fatalf("expected 2 service rates, got %d", len(table.Rates))
+	}
+
+	nbs := table.Rates[0]
+	if nbs.CodeType != CodeTypeNBS || nbs.Code != "101011100" || nbs.FederalRate != 13.45 || nbs.MunicipalRate != 2.00 {
+		t.Errorf("unexpected NBS rate: %+v", nbs)
+	}
+	if nbs.Description != "Serviços de construção" {
+		t.Errorf("expected description decoded from ISO-8859-1, got %q", nbs.Description)
+	}
+
+	item := table.Rates[1]
+	if item.CodeType != CodeTypeServiceItem || item.Code != "0101" || item.FederalRate != 13.45 || item.MunicipalRate != 2.90 {
+		t.Errorf("unexpected service item rate: %+v", item)
+	}
+
+	// The table is valid until the end of its last day
+	if !item.IsValid(time.Date(2025, 12, 31, 23, 0, 0, 0, brazilLocation)) {
+		t.Error("expected rate to be valid on its last day")
+	}
+	if item.IsValid(time.Date(2026, 1, 1, 0, 0, 0, 0, brazilLocation)) {
+		t.Error("expected rate to be expired after its last day")
+	}
+	if item.IsValid(time.Date(2025, 6, 30, 12, 0, 0, 0, brazilLocation)) {
+		t.Error("expected rate not to be valid before its first day")
+	}
+}
+
+func TestParseIBPT_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr error
+	}{
+		{
+			name: "missing column",
+			csv:  "codigo;tipo;descricao\n101;2;Sistemas\n",
+		},
+		{
+			name:    "only goods",
+			csv:     testIBPTHeader + "01012100;;0;Cavalos;4.20;6.20;18.00;0.00;01/07/2025;31/12/2025;A1;25.2.A;IBPT\n",
+			wantErr: ErrEmptyTable,
+		},
+		{
+			name: "mixed versions",
+			csv: testIBPTHeader +
+				"101;;2;Sistemas;13.45;0;0;2.90;01/07/2025;31/12/2025;A1;25.2.A;IBPT\n" +
+				"102;;2;Programas;13.45;0;0;2.90;01/07/2025;31/12/2025;A1;25.1.A;IBPT\n",
+		},
+		{
+			name: "invalid rate",
+			csv:  testIBPTHeader + "101;;2;Sistemas;abc;0;0;2.90;01/07/2025;31/12/2025;A1;25.2.A;IBPT\n",
+		},
+		{
+			name: "invalid date",
+			csv:  testIBPTHeader + "101;;2;Sistemas;13.45;0;0;2.90;2025-07-01;31/12/2025;A1;25.2.A;IBPT\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseIBPT(strings.NewReader(tt.csv), "SP")
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package taxburden provides the approximate tax burden of services (totTrib),
+// informed to takers as required by the transparency law (Lei 12.741/2012).
+// The rates come from an offline table, such as the IBPT "De Olho no Imposto"
+// table, loaded per state.
+package taxburden
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Code types identify the classification of a rate in the table.
+const (
+	// CodeTypeNBS is a rate by NBS code (Nomenclatura Brasileira de Serviços), 9 digits.
+	CodeTypeNBS = "nbs"
+
+	// CodeTypeServiceItem is a rate by LC 116/2003 service item, 4 digits.
+	CodeTypeServiceItem = "lc116"
+)
+
+// brazilLocation is the time zone of the table validity dates (Brasília time).
+var brazilLocation = time.FixedZone("BRT", -3*60*60)
+
+// stateCodes maps the 2-digit IBGE state code, the prefix of municipality codes,
+// to the state abbreviation.
+var stateCodes = map[string]string{
+	"11": "RO", "12": "AC", "13": "AM", "14": "RR", "15": "PA", "16": "AP", "17": "TO",
+	"21": "MA", "22": "PI", "23": "CE", "24": "RN", "25": "PB", "26": "PE", "27": "AL", "28": "SE", "29": "BA",
+	"31": "MG", "32": "ES", "33": "RJ", "35": "SP",
+	"41": "PR", "42": "SC", "43": "RS",
+	"50": "MS", "51": "MT", "52": "GO", "53": "DF",
+}
+
+// Rate is the approximate tax burden of a service code in a state.
+type Rate struct {
+	// CodeType is the classification of Code (see CodeType* constants).
+	CodeType string
+
+	// Code is the NBS code or the LC 116 service item.
+	Code string
+
+	// Description describes the service.
+	Description string
+
+	// FederalRate is the approximate federal tax percentage.
+	FederalRate float64
+
+	// StateRate is the approximate state tax percentage.
+	StateRate float64
+
+	// MunicipalRate is the approximate municipal tax percentage.
+	MunicipalRate float64
+
+	// ValidFrom and ValidUntil delimit the validity of the rate, if informed.
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+}
+
+// Table is a table of approximate tax burden rates of a state.
+type Table struct {
+	// State is the state abbreviation (UF) the rates apply to.
+	State string
+
+	// Version identifies the table version, such as "24.2.A".
+	Version string
+
+	// Source is the entity that published the table, such as "IBPT".
+	Source string
+
+	// Rates are the rates of the service codes.
+	Rates []Rate
+}
+
+// Amounts are the approximate tax amounts of a service (vTotTrib).
+type Amounts struct {
+	Federal   float64
+	State     float64
+	Municipal float64
+}
+
+// StateFromMunicipality returns the state abbreviation of a 7-digit IBGE
+// municipality code, or an empty string if the code is unknown.
+func StateFromMunicipality(municipalityCode string) string {
+	if len(municipalityCode) < 2 {
+		return ""
+	}
+	return stateCodes[municipalityCode[:2]]
+}
+
+// IsState reports whether the abbreviation is a Brazilian state (UF).
+func IsState(state string) bool {
+	for _, abbreviation := range stateCodes {
+		if abbreviation == state {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceItemFromNationalCode returns the LC 116 service item of a 6-digit
+// national tax code (cTribNac): its item and subitem, without the breakdown.
+func ServiceItemFromNationalCode(nationalCode string) (string, error) {
+	if len(nationalCode) != 6 {
+		return "", fmt.Errorf("national tax code must have 6 digits, got %q", nationalCode)
+	}
+	return nationalCode[:4], nil
+}
+
+// Approximate calculates the approximate tax amounts on the given base.
+func (r *Rate) Approximate(base float64) Amounts {
+	return Amounts{
+		Federal:   roundToTwoDecimals(base * r.FederalRate / 100),
+		State:     roundToTwoDecimals(base * r.StateRate / 100),
+		Municipal: roundToTwoDecimals(base * r.MunicipalRate / 100),
+	}
+}
+
+// IsValid reports whether the rate is valid at the given time.
+func (r *Rate) IsValid(at time.Time) bool {
+	if r.ValidFrom != nil && at.Before(*r.ValidFrom) {
+		return false
+	}
+	if r.ValidUntil != nil && at.After(*r.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// roundToTwoDecimals rounds a value to 2 decimal places.
+func roundToTwoDecimals(value float64) float64 {
+	return math.Round(value*100) / 100
+}
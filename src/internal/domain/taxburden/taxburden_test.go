@@ -0,0 +1,52 @@
+package taxburden
+
+import "testing"
+
+func TestStateFromMunicipality(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"3550308", "SP"},
+		{"5300108", "DF"},
+		{"4106902", "PR"},
+		{"9999999", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := StateFromMunicipality(tt.code); got != tt.expected {
+			t.Errorf("StateFromMunicipality(%q) = %q, want %q", tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestIsState(t *testing.T) {
+	if !IsState("SP") || !IsState("DF") {
+		t.Error("expected SP and DF to be states")
+	}
+	if IsState("XX") || IsState("sp") {
+		t.Error("expected XX and lowercase abbreviations not to be states")
+	}
+}
+
+func TestServiceItemFromNationalCode(t *testing.T) {
+	item, err := ServiceItemFromNationalCode("010101")
+	if err != nil || item != "0101" {
+		t.Errorf("expected item 0101, got %q (%v)", item, err)
+	}
+
+	if _, err := ServiceItemFromNationalCode("0101"); err == nil {
+		t.Error("expected error for a code that is not 6 digits")
+	}
+}
+
+func TestRate_Approximate(t *testing.T) {
+	rate := Rate{FederalRate: 13.45, StateRate: 0, MunicipalRate: 2.90}
+
+	amounts := rate.Approximate(1234.56)
+
+	if amounts.Federal != 166.05 || amounts.State != 0 || amounts.Municipal != 35.80 {
+		t.Errorf("unexpected amounts: %+v", amounts)
+	}
+}
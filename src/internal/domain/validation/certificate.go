@@ -4,10 +4,13 @@ package validation
 import (
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
+	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
 )
 
 // Certificate validation error codes.
@@ -32,6 +35,9 @@ const (
 
 	// CertificateCodeInvalidKeyUsage indicates the certificate cannot be used for signing.
 	CertificateCodeInvalidKeyUsage = "CERTIFICATE_INVALID_KEY_USAGE"
+
+	// CertificateCodeHolderMismatch indicates the certificate does not belong to the DPS emitter.
+	CertificateCodeHolderMismatch = "CERTIFICATE_HOLDER_MISMATCH"
 )
 
 // CertificateValidationResult contains the result of certificate validation.
@@ -283,6 +289,66 @@ func ValidateCertificateWithResult(cert *emission.CertificateRequest) *Certifica
 	return result
 }
 
+// ValidateCertificateHolder checks that the signing certificate belongs to the party
+// emitting the DPS (provider, taker or intermediary). An e-CNPJ matches the emitter's
+// CNPJ or another establishment of the same company (same 8-digit root). Certificates
+// that do not identify their holder, unlike ICP-Brasil ones, are left for SEFIN to reject.
+//
+// Parameters:
+//   - req: The emission request, defining the emitter
+//   - certInfo: The parsed signing certificate
+//
+// Returns:
+//   - []ValidationError: A slice of validation errors (empty if the certificate matches)
+func ValidateCertificateHolder(req *emission.EmissionRequest, certInfo *xmlsigner.CertificateInfo) []ValidationError {
+	if certInfo == nil {
+		return nil
+	}
+
+	return validateHolder(certInfo, req.EmitterRegistration(), "the DPS emitter")
+}
+
+// ValidateEventCertificateHolder checks that the certificate signing an event belongs to
+// its author (CNPJAutor/CPFAutor), following the same rules as ValidateCertificateHolder.
+//
+// Parameters:
+//   - author: The event author
+//   - certInfo: The parsed signing certificate
+//
+// Returns:
+//   - []ValidationError: A slice of validation errors (empty if the certificate matches)
+func ValidateEventCertificateHolder(author event.AuthorRequest, certInfo *xmlsigner.CertificateInfo) []ValidationError {
+	if certInfo == nil {
+		return nil
+	}
+
+	registration := author.CNPJ
+	if registration == "" {
+		registration = author.CPF
+	}
+
+	return validateHolder(certInfo, registration, "the event author")
+}
+
+// validateHolder compares the certificate holder with the expected registration.
+func validateHolder(certInfo *xmlsigner.CertificateInfo, registration, party string) []ValidationError {
+	holder := certInfo.GetFederalRegistration()
+	if holder == "" {
+		return nil
+	}
+
+	expected := cnpjcpf.CleanCNPJ(registration)
+	if holder == expected || (len(holder) == 14 && len(expected) == 14 && holder[:8] == expected[:8]) {
+		return nil
+	}
+
+	return []ValidationError{NewValidationError(
+		"certificate.pfx_base64",
+		CertificateCodeHolderMismatch,
+		fmt.Sprintf("Certificate belongs to %s, not to %s %s", holder, party, expected),
+	)}
+}
+
 // isValidBase64 checks if a string is valid base64 encoding.
 func isValidBase64(s string) bool {
 	if s == "" {
@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+)
+
+// taxRegimeSimplesOptions maps each tax regime to the Simples Nacional option
+// (opSimpNac) the municipality must have on record for the provider.
+var taxRegimeSimplesOptions = map[string]int{
+	TaxRegimeMEI:            sefin.OpcaoSimplesNacionalMEI,
+	TaxRegimeMEEPP:          sefin.OpcaoSimplesNacionalMEEPP,
+	TaxRegimeLucroPresumido: sefin.OpcaoSimplesNacionalNaoOptante,
+	TaxRegimeLucroReal:      sefin.OpcaoSimplesNacionalNaoOptante,
+	TaxRegimeAutonomo:       sefin.OpcaoSimplesNacionalNaoOptante,
+}
+
+// simplesOptionDescriptions describes the Simples Nacional options for error messages.
+var simplesOptionDescriptions = map[int]string{
+	sefin.OpcaoSimplesNacionalNaoOptante: "not a Simples Nacional optant",
+	sefin.OpcaoSimplesNacionalMEI:        "MEI",
+	sefin.OpcaoSimplesNacionalMEEPP:      "ME/EPP",
+}
+
+// ValidateContributorParameters checks the provider's tax regime, special taxation regime
+// and the claimed municipal benefit against the parameters the issuing municipality has
+// on record for the provider. The benefit must be in force at the given date.
+// Returns a slice of ValidationErrors if the request disagrees with the record.
+func (v *EmissionValidator) ValidateContributorParameters(req *emission.EmissionRequest, params *sefin.ContributorParametersResult, at time.Time) []ValidationError {
+	var errors []ValidationError
+
+	if params == nil {
+		return errors
+	}
+
+	// Only check the tax regime when the municipality informs it
+	expected, known := taxRegimeSimplesOptions[req.Provider.TaxRegime]
+	if known && params.OpcaoSimplesNacional != 0 && params.OpcaoSimplesNacional != expected {
+		recorded, ok := simplesOptionDescriptions[params.OpcaoSimplesNacional]
+		if !ok {
+			recorded = fmt.Sprintf("option %d", params.OpcaoSimplesNacional)
+		}
+		errors = append(errors, NewValidationError(
+			"provider.tax_regime",
+			ValidationCodeNotOnRecord,
+			fmt.Sprintf("Provider tax regime '%s' does not match the municipal record (%s)", req.Provider.TaxRegime, recorded),
+		))
+	}
+
+	// Special regimes are granted by the municipality, so they must be on record
+	if regime := req.Provider.SpecialRegime; regime != emission.SpecialRegimeNone && params.Cadastrado && !params.HasRegimeEspecial(regime) {
+		errors = append(errors, NewValidationError(
+			"provider.special_regime",
+			ValidationCodeNotOnRecord,
+			fmt.Sprintf("Special taxation regime %d is not granted to the provider in municipality %s", regime, params.CodigoMunicipio),
+		))
+	}
+
+	if req.Values.MunicipalBenefit != nil {
+		number := req.Values.MunicipalBenefit.Number
+		benefit := params.FindBeneficio(number)
+		if benefit == nil {
+			errors = append(errors, NewValidationError(
+				"values.municipal_benefit.number",
+				ValidationCodeNotOnRecord,
+				fmt.Sprintf("Municipal benefit %s is not granted to the provider in municipality %s", number, params.CodigoMunicipio),
+			))
+		} else if !benefit.IsActive(at) {
+			errors = append(errors, NewValidationError(
+				"values.municipal_benefit.number",
+				ValidationCodeNotOnRecord,
+				fmt.Sprintf("Municipal benefit %s is not in force on %s", number, at.Format("2006-01-02")),
+			))
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,182 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+)
+
+// testBenefitNumber is a municipal benefit granted in São Paulo.
+const testBenefitNumber = "35503080400001"
+
+// testContributorParameters returns the parameters of a ME/EPP provider with one special regime and one benefit.
+func testContributorParameters() *sefin.ContributorParametersResult {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	return &sefin.ContributorParametersResult{
+		CodigoMunicipio:      "3550308",
+		Documento:            "11222333000181",
+		Cadastrado:           true,
+		OpcaoSimplesNacional: sefin.OpcaoSimplesNacionalMEEPP,
+		RegimesEspeciais: []sefin.RegimeEspecial{
+			{Codigo: emission.SpecialRegimeProfessionalSociety, Descricao: "Sociedade de Profissionais"},
+		},
+		Beneficios: []sefin.BeneficioMunicipal{
+			{Numero: testBenefitNumber, InicioVigencia: &start, FimVigencia: &end},
+		},
+	}
+}
+
+func TestEmissionValidator_ValidateContributorParameters(t *testing.T) {
+	validator := NewEmissionValidator()
+	inForce := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		taxRegime     string
+		specialRegime int
+		benefit       string
+		modify        func(params *sefin.ContributorParametersResult)
+		at            time.Time
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "matching tax regime without benefit",
+			taxRegime:     TaxRegimeMEEPP,
+			at:            inForce,
+			expectedCount: 0,
+		},
+		{
+			name:          "matching tax regime with benefit in force",
+			taxRegime:     TaxRegimeMEEPP,
+			benefit:       testBenefitNumber,
+			at:            inForce,
+			expectedCount: 0,
+		},
+		{
+			name:          "tax regime not on record",
+			taxRegime:     TaxRegimeMEI,
+			at:            inForce,
+			expectedCount: 1,
+			checkFields:   []string{"provider.tax_regime"},
+		},
+		{
+			name:      "provider not a Simples Nacional optant",
+			taxRegime: TaxRegimeMEEPP,
+			modify: func(params *sefin.ContributorParametersResult) {
+				params.OpcaoSimplesNacional = sefin.OpcaoSimplesNacionalNaoOptante
+			},
+			at:            inForce,
+			expectedCount: 1,
+			checkFields:   []string{"provider.tax_regime"},
+		},
+		{
+			name:      "tax regime not informed by the municipality",
+			taxRegime: TaxRegimeMEI,
+			modify: func(params *sefin.ContributorParametersResult) {
+				params.OpcaoSimplesNacional = 0
+			},
+			at:            inForce,
+			expectedCount: 0,
+		},
+		{
+			name:      "not optant on record",
+			taxRegime: TaxRegimeLucroPresumido,
+			modify: func(params *sefin.ContributorParametersResult) {
+				params.OpcaoSimplesNacional = sefin.OpcaoSimplesNacionalNaoOptante
+			},
+			at:            inForce,
+			expectedCount: 0,
+		},
+		{
+			name:          "lucro real provider on record as ME/EPP",
+			taxRegime:     TaxRegimeLucroReal,
+			at:            inForce,
+			expectedCount: 1,
+			checkFields:   []string{"provider.tax_regime"},
+		},
+		{
+			name:          "special regime granted",
+			taxRegime:     TaxRegimeMEEPP,
+			specialRegime: emission.SpecialRegimeProfessionalSociety,
+			at:            inForce,
+			expectedCount: 0,
+		},
+		{
+			name:          "special regime not granted",
+			taxRegime:     TaxRegimeMEEPP,
+			specialRegime: emission.SpecialRegimeEstimate,
+			at:            inForce,
+			expectedCount: 1,
+			checkFields:   []string{"provider.special_regime"},
+		},
+		{
+			name:          "benefit not granted",
+			taxRegime:     TaxRegimeMEEPP,
+			benefit:       "35503080400002",
+			at:            inForce,
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit.number"},
+		},
+		{
+			name:          "benefit no longer in force",
+			taxRegime:     TaxRegimeMEEPP,
+			benefit:       testBenefitNumber,
+			at:            time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit.number"},
+		},
+		{
+			name:      "contributor without parameters claiming a benefit",
+			taxRegime: TaxRegimeMEI,
+			benefit:   testBenefitNumber,
+			modify: func(params *sefin.ContributorParametersResult) {
+				*params = sefin.ContributorParametersResult{CodigoMunicipio: "3550308"}
+			},
+			at:            inForce,
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit.number"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &emission.EmissionRequest{
+				Provider: emission.ProviderRequest{CNPJ: "11222333000181", TaxRegime: tt.taxRegime, SpecialRegime: tt.specialRegime},
+			}
+			if tt.benefit != "" {
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{Number: tt.benefit}
+			}
+
+			params := testContributorParameters()
+			if tt.modify != nil {
+				tt.modify(params)
+			}
+
+			errors := validator.ValidateContributorParameters(req, params, tt.at)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field && err.Code == ValidationCodeNotOnRecord {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// Validation patterns for deduction document fields.
+var (
+	// nfseKeyPattern matches the 50-digit national NFS-e access key (chNFSe).
+	nfseKeyPattern = regexp.MustCompile(`^\d{50}$`)
+
+	// nfeKeyPattern matches the 44-digit NF-e access key (chNFe).
+	nfeKeyPattern = regexp.MustCompile(`^\d{44}$`)
+
+	// municipalNFSeNumberPattern matches the municipal NFS-e number (nNFSeMun): 1-15 digits.
+	municipalNFSeNumberPattern = regexp.MustCompile(`^\d{1,15}$`)
+
+	// verificationCodePattern matches the municipal NFS-e verification code (cVerifNFSeMun).
+	verificationCodePattern = regexp.MustCompile(`^[a-zA-Z0-9]{1,9}$`)
+
+	// paperInvoiceNumberPattern matches the NF/NFS number (nNFS): 1-7 digits.
+	paperInvoiceNumberPattern = regexp.MustCompile(`^\d{1,7}$`)
+
+	// paperInvoiceModelPattern matches the NF/NFS model (modNFS): 1-15 digits.
+	paperInvoiceModelPattern = regexp.MustCompile(`^\d{1,15}$`)
+
+	// paperInvoiceSeriesPattern matches the NF/NFS series (serieNFS).
+	paperInvoiceSeriesPattern = regexp.MustCompile(`^[a-zA-Z0-9]{1,15}$`)
+)
+
+// Deduction document validation constants.
+const (
+	// DeductionDocumentsMax is the maximum number of deduction documents (docDedRed).
+	DeductionDocumentsMax = 1000
+
+	// DeductionDocumentNumberMaxLength is the maximum length for fiscal and non-fiscal document numbers.
+	DeductionDocumentNumberMaxLength = 255
+
+	// DeductionTypeDescriptionMaxLength is the maximum length for the deduction description (xDescOutDed).
+	DeductionTypeDescriptionMaxLength = 150
+)
+
+// validDeductionTypes are the accepted deduction/reduction types (tpDedRed).
+var validDeductionTypes = map[int]bool{
+	emission.DeductionTypeFood:               true,
+	emission.DeductionTypeMaterials:          true,
+	emission.DeductionTypeExternalProduction: true,
+	emission.DeductionTypeExpenseRefund:      true,
+	emission.DeductionTypeConsortiumTransfer: true,
+	emission.DeductionTypeHealthPlanTransfer: true,
+	emission.DeductionTypeServices:           true,
+	emission.DeductionTypeSubcontracting:     true,
+	emission.DeductionTypeOther:              true,
+}
+
+// validateDeductionDocuments validates the documents supporting the deductions
+// (documentos/docDedRed) and checks that their deduction values add up to the
+// deductions total (vDR).
+func (v *EmissionValidator) validateDeductionDocuments(values *emission.ValuesRequest) []ValidationError {
+	var errors []ValidationError
+
+	if len(values.DeductionDocuments) > DeductionDocumentsMax {
+		return []ValidationError{NewValidationError(
+			"values.deduction_documents",
+			ValidationCodeOutOfRange,
+			fmt.Sprintf("At most %d deduction documents are allowed", DeductionDocumentsMax),
+		)}
+	}
+
+	var total float64
+	for i := range values.DeductionDocuments {
+		doc := &values.DeductionDocuments[i]
+		errors = append(errors, validateDeductionDocument(fmt.Sprintf("values.deduction_documents[%d]", i), doc)...)
+		total += doc.DeductionValue
+	}
+
+	// Compare in cents to avoid floating point rounding differences
+	if math.Round(total*100) != math.Round(values.Deductions*100) {
+		errors = append(errors, NewValidationError(
+			"values.deductions",
+			ValidationCodeInvalid,
+			fmt.Sprintf("Deductions (%.2f) must equal the sum of the deduction documents (%.2f)", values.Deductions, total),
+		))
+	}
+
+	return errors
+}
+
+// validateDeductionDocument validates a single deduction document.
+func validateDeductionDocument(field string, doc *emission.DeductionDocumentRequest) []ValidationError {
+	var errors []ValidationError
+
+	errors = append(errors, validateDeductionDocumentIdentification(field, doc)...)
+
+	// Validate type and its description
+	if !validDeductionTypes[doc.Type] {
+		errors = append(errors, NewValidationError(
+			field+".type",
+			ValidationCodeInvalid,
+			"Deduction type (tpDedRed) must be 1 to 8 or 99",
+		))
+	}
+	description := strings.TrimSpace(doc.TypeDescription)
+	if doc.Type == emission.DeductionTypeOther && description == "" {
+		errors = append(errors, NewValidationError(
+			field+".type_description",
+			ValidationCodeRequired,
+			"Deduction description (xDescOutDed) is required for other deductions (99)",
+		))
+	} else if len(description) > DeductionTypeDescriptionMaxLength {
+		errors = append(errors, NewValidationError(
+			field+".type_description",
+			ValidationCodeTooLong,
+			"Deduction description (xDescOutDed) must not exceed 150 characters",
+		))
+	}
+
+	// Validate issue date
+	if doc.IssueDate == "" {
+		errors = append(errors, NewValidationError(field+".issue_date", ValidationCodeRequired, "Document issue date is required"))
+	} else if _, err := time.Parse("2006-01-02", doc.IssueDate); err != nil {
+		errors = append(errors, NewValidationError(field+".issue_date", ValidationCodeInvalidFormat, "Document issue date must be in YYYY-MM-DD format"))
+	}
+
+	// Validate values
+	deductibleValid := doc.DeductibleValue > 0 && isValidMonetaryValue(doc.DeductibleValue)
+	if !deductibleValid {
+		errors = append(errors, NewValidationError(
+			field+".deductible_value",
+			ValidationCodeOutOfRange,
+			"Deductible value must be greater than zero with at most 2 decimal places",
+		))
+	}
+	if doc.DeductionValue <= 0 || !isValidMonetaryValue(doc.DeductionValue) {
+		errors = append(errors, NewValidationError(
+			field+".deduction_value",
+			ValidationCodeOutOfRange,
+			"Deduction value must be greater than zero with at most 2 decimal places",
+		))
+	} else if deductibleValid && doc.DeductionValue > doc.DeductibleValue {
+		errors = append(errors, NewValidationError(
+			field+".deduction_value",
+			ValidationCodeOutOfRange,
+			"Deduction value must not exceed the deductible value of the document",
+		))
+	}
+
+	return errors
+}
+
+// validateDeductionDocumentIdentification validates that the document is identified
+// by exactly one of its access keys, municipal NFS-e, paper invoice or document numbers.
+func validateDeductionDocumentIdentification(field string, doc *emission.DeductionDocumentRequest) []ValidationError {
+	identifierCount := 0
+	for _, set := range []bool{
+		doc.NFSeKey != "",
+		doc.NFeKey != "",
+		doc.MunicipalNFSe != nil,
+		doc.PaperInvoice != nil,
+		doc.FiscalDocumentNumber != "",
+		doc.DocumentNumber != "",
+	} {
+		if set {
+			identifierCount++
+		}
+	}
+
+	if identifierCount == 0 {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeRequired,
+			"Deduction document must have exactly one of nfse_key, nfe_key, municipal_nfse, paper_invoice, fiscal_document_number or document_number",
+		)}
+	}
+	if identifierCount > 1 {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeInvalid,
+			"Deduction document must have only one identification (they are mutually exclusive)",
+		)}
+	}
+
+	var errors []ValidationError
+	switch {
+	case doc.NFSeKey != "":
+		if !nfseKeyPattern.MatchString(doc.NFSeKey) {
+			errors = append(errors, NewValidationError(field+".nfse_key", ValidationCodeInvalidFormat, "NFS-e access key must be exactly 50 digits"))
+		}
+	case doc.NFeKey != "":
+		if !nfeKeyPattern.MatchString(doc.NFeKey) {
+			errors = append(errors, NewValidationError(field+".nfe_key", ValidationCodeInvalidFormat, "NF-e access key must be exactly 44 digits"))
+		}
+	case doc.MunicipalNFSe != nil:
+		nfse := doc.MunicipalNFSe
+		if !municipalityCodePattern.MatchString(nfse.MunicipalityCode) {
+			errors = append(errors, NewValidationError(field+".municipal_nfse.municipality_code", ValidationCodeInvalidFormat, "Municipality code must be exactly 7 digits (IBGE code)"))
+		}
+		if !municipalNFSeNumberPattern.MatchString(nfse.Number) {
+			errors = append(errors, NewValidationError(field+".municipal_nfse.number", ValidationCodeInvalidFormat, "Municipal NFS-e number must be 1-15 digits"))
+		}
+		if !verificationCodePattern.MatchString(nfse.VerificationCode) {
+			errors = append(errors, NewValidationError(field+".municipal_nfse.verification_code", ValidationCodeInvalidFormat, "Verification code must be 1-9 alphanumeric characters"))
+		}
+	case doc.PaperInvoice != nil:
+		invoice := doc.PaperInvoice
+		if !paperInvoiceNumberPattern.MatchString(invoice.Number) {
+			errors = append(errors, NewValidationError(field+".paper_invoice.number", ValidationCodeInvalidFormat, "Invoice number must be 1-7 digits"))
+		}
+		if !paperInvoiceModelPattern.MatchString(invoice.Model) {
+			errors = append(errors, NewValidationError(field+".paper_invoice.model", ValidationCodeInvalidFormat, "Invoice model must be 1-15 digits"))
+		}
+		if !paperInvoiceSeriesPattern.MatchString(invoice.Series) {
+			errors = append(errors, NewValidationError(field+".paper_invoice.series", ValidationCodeInvalidFormat, "Invoice series must be 1-15 alphanumeric characters"))
+		}
+	case doc.FiscalDocumentNumber != "":
+		if len(doc.FiscalDocumentNumber) > DeductionDocumentNumberMaxLength {
+			errors = append(errors, NewValidationError(field+".fiscal_document_number", ValidationCodeTooLong, "Fiscal document number must not exceed 255 characters"))
+		}
+	case doc.DocumentNumber != "":
+		if len(doc.DocumentNumber) > DeductionDocumentNumberMaxLength {
+			errors = append(errors, NewValidationError(field+".document_number", ValidationCodeTooLong, "Document number must not exceed 255 characters"))
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,220 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// testDeductionValues returns values with 300.00 of deductions supported by two documents.
+func testDeductionValues() *emission.ValuesRequest {
+	return &emission.ValuesRequest{
+		ServiceValue: 1000.00,
+		Deductions:   300.00,
+		DeductionDocuments: []emission.DeductionDocumentRequest{
+			{
+				NFeKey:          "35250111222333000181550010000012341000012345",
+				Type:            emission.DeductionTypeMaterials,
+				IssueDate:       "2025-01-10",
+				DeductibleValue: 250.00,
+				DeductionValue:  200.00,
+			},
+			{
+				MunicipalNFSe: &emission.MunicipalNFSeRequest{
+					MunicipalityCode: "3550308",
+					Number:           "1234",
+					VerificationCode: "AB12CD34",
+				},
+				Type:            emission.DeductionTypeSubcontracting,
+				IssueDate:       "2025-01-12",
+				DeductibleValue: 100.00,
+				DeductionValue:  100.00,
+			},
+		},
+	}
+}
+
+func TestEmissionValidator_ValidateDeductionDocuments(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(values *emission.ValuesRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid documents matching deductions",
+			expectedCount: 0,
+		},
+		{
+			name: "valid other deduction with description",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[1].Type = emission.DeductionTypeOther
+				values.DeductionDocuments[1].TypeDescription = "Equipment rental"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "sum does not match deductions",
+			modify: func(values *emission.ValuesRequest) {
+				values.Deductions = 300.01
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deductions"},
+		},
+		{
+			name: "missing identification",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].NFeKey = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents[0]"},
+		},
+		{
+			name: "more than one identification",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].DocumentNumber = "REC-001"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents[0]"},
+		},
+		{
+			name: "invalid access keys",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].NFeKey = "1234"
+				values.DeductionDocuments[1].MunicipalNFSe = nil
+				values.DeductionDocuments[1].NFSeKey = "1234"
+			},
+			expectedCount: 2,
+			checkFields:   []string{"values.deduction_documents[0].nfe_key", "values.deduction_documents[1].nfse_key"},
+		},
+		{
+			name: "invalid municipal NFS-e",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[1].MunicipalNFSe = &emission.MunicipalNFSeRequest{
+					MunicipalityCode: "355030",
+					Number:           "12A",
+					VerificationCode: "ABCDEFGHIJ",
+				}
+			},
+			expectedCount: 3,
+			checkFields: []string{
+				"values.deduction_documents[1].municipal_nfse.municipality_code",
+				"values.deduction_documents[1].municipal_nfse.number",
+				"values.deduction_documents[1].municipal_nfse.verification_code",
+			},
+		},
+		{
+			name: "invalid paper invoice",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[1].MunicipalNFSe = nil
+				values.DeductionDocuments[1].PaperInvoice = &emission.PaperInvoiceRequest{
+					Number: "12345678",
+					Model:  "A1",
+					Series: "",
+				}
+			},
+			expectedCount: 3,
+			checkFields: []string{
+				"values.deduction_documents[1].paper_invoice.number",
+				"values.deduction_documents[1].paper_invoice.model",
+				"values.deduction_documents[1].paper_invoice.series",
+			},
+		},
+		{
+			name: "document number too long",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].NFeKey = ""
+				values.DeductionDocuments[0].DocumentNumber = strings.Repeat("1", 256)
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents[0].document_number"},
+		},
+		{
+			name: "invalid type",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].Type = 9
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents[0].type"},
+		},
+		{
+			name: "other deduction without description",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].Type = emission.DeductionTypeOther
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents[0].type_description"},
+		},
+		{
+			name: "missing and invalid issue dates",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].IssueDate = ""
+				values.DeductionDocuments[1].IssueDate = "12/01/2025"
+			},
+			expectedCount: 2,
+			checkFields:   []string{"values.deduction_documents[0].issue_date", "values.deduction_documents[1].issue_date"},
+		},
+		{
+			name: "deduction exceeds deductible value",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].DeductibleValue = 150.00
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents[0].deduction_value"},
+		},
+		{
+			name: "invalid values",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments[0].DeductibleValue = 0
+				values.DeductionDocuments[1].DeductionValue = 100.001
+				values.Deductions = 300.001
+			},
+			expectedCount: 2,
+			checkFields: []string{
+				"values.deduction_documents[0].deductible_value",
+				"values.deduction_documents[1].deduction_value",
+			},
+		},
+		{
+			name: "too many documents",
+			modify: func(values *emission.ValuesRequest) {
+				values.DeductionDocuments = make([]emission.DeductionDocumentRequest, DeductionDocumentsMax+1)
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.deduction_documents"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := testDeductionValues()
+			if tt.modify != nil {
+				tt.modify(values)
+			}
+
+			errors := validator.validateDeductionDocuments(values)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
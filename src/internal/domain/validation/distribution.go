@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
+)
+
+// inboxManifestationStatuses are the statuses accepted by the inbox filter.
+var inboxManifestationStatuses = map[string]bool{
+	event.LifecycleActive:                  true,
+	event.LifecycleConfirmed:               true,
+	event.LifecycleRejected:                true,
+	event.LifecycleCancelled:               true,
+	event.LifecycleSubstituted:             true,
+	event.LifecycleCancellationUnderReview: true,
+	event.LifecycleBlocked:                 true,
+}
+
+// DistributionValidator validates DF-e distribution registration requests.
+type DistributionValidator struct {
+	// emissionValidator is reused for the certificate and webhook URL checks,
+	// which follow the same rules as emission requests.
+	emissionValidator *EmissionValidator
+}
+
+// NewDistributionValidator creates a new distribution validator.
+func NewDistributionValidator() *DistributionValidator {
+	return &DistributionValidator{
+		emissionValidator: NewEmissionValidator(),
+	}
+}
+
+// ValidateRegistration performs validation of a CNPJ registration for DF-e distribution.
+// Returns a slice of ValidationErrors if any validation fails.
+func (v *DistributionValidator) ValidateRegistration(req *distribution.RegistrationRequest) []ValidationError {
+	var errors []ValidationError
+
+	// Validate CNPJ
+	if req.CNPJ == "" {
+		errors = append(errors, NewValidationError("cnpj", ValidationCodeRequired, "CNPJ is required"))
+	} else if !cnpjcpf.ValidateCNPJ(cnpjcpf.CleanCNPJ(req.CNPJ)) {
+		errors = append(errors, NewValidationError(
+			"cnpj",
+			ValidationCodeInvalid,
+			"CNPJ is invalid (check digit mismatch or incorrect format)",
+		))
+	}
+
+	// Validate certificate (required for mTLS with the ADN)
+	if req.Certificate == nil {
+		errors = append(errors, NewValidationError(
+			"certificate",
+			ValidationCodeRequired,
+			"Certificate is required to download the CNPJ's documents",
+		))
+	} else {
+		errors = append(errors, v.emissionValidator.validateCertificate(req.Certificate)...)
+	}
+
+	// Validate webhook URL (if present)
+	if req.WebhookURL != "" {
+		errors = append(errors, v.emissionValidator.validateWebhookURL(req.WebhookURL)...)
+	}
+
+	return errors
+}
+
+// ParseInboxQuery validates the filters of the received NFS-e inbox and returns
+// them parsed. Returns a slice of ValidationErrors if any validation fails.
+func (v *DistributionValidator) ParseInboxQuery(q *distribution.InboxQuery) (*distribution.InboxFilter, []ValidationError) {
+	var errors []ValidationError
+	filter := &distribution.InboxFilter{
+		ManifestationStatus: q.ManifestationStatus,
+		Municipality:        q.Municipality,
+	}
+
+	// Validate CNPJs
+	if q.CNPJ != "" {
+		filter.CNPJ = cnpjcpf.CleanCNPJ(q.CNPJ)
+		if !cnpjcpf.ValidateCNPJ(filter.CNPJ) {
+			errors = append(errors, NewValidationError("cnpj", ValidationCodeInvalid, "CNPJ is invalid"))
+		}
+	}
+	if q.IssuerCNPJ != "" {
+		filter.IssuerCNPJ = cnpjcpf.CleanCNPJ(q.IssuerCNPJ)
+		if !cnpjcpf.ValidateCNPJ(filter.IssuerCNPJ) {
+			errors = append(errors, NewValidationError("issuer_cnpj", ValidationCodeInvalid, "Issuer CNPJ is invalid"))
+		}
+	}
+
+	// Validate competence period
+	if q.Competence != "" {
+		if q.CompetenceFrom != "" || q.CompetenceTo != "" {
+			errors = append(errors, NewValidationError(
+				"competence",
+				ValidationCodeInvalid,
+				"competence cannot be combined with competence_from or competence_to",
+			))
+		} else if month, err := time.Parse("2006-01", q.Competence); err != nil {
+			errors = append(errors, NewValidationError("competence", ValidationCodeInvalidFormat, "competence must be in YYYY-MM format"))
+		} else {
+			last := month.AddDate(0, 1, -1)
+			filter.CompetenceFrom = &month
+			filter.CompetenceTo = &last
+		}
+	}
+	if q.CompetenceFrom != "" {
+		if from, err := time.Parse("2006-01-02", q.CompetenceFrom); err != nil {
+			errors = append(errors, NewValidationError("competence_from", ValidationCodeInvalidFormat, "competence_from must be in YYYY-MM-DD format"))
+		} else {
+			filter.CompetenceFrom = &from
+		}
+	}
+	if q.CompetenceTo != "" {
+		if to, err := time.Parse("2006-01-02", q.CompetenceTo); err != nil {
+			errors = append(errors, NewValidationError("competence_to", ValidationCodeInvalidFormat, "competence_to must be in YYYY-MM-DD format"))
+		} else {
+			filter.CompetenceTo = &to
+		}
+	}
+	if filter.CompetenceFrom != nil && filter.CompetenceTo != nil && filter.CompetenceTo.Before(*filter.CompetenceFrom) {
+		errors = append(errors, NewValidationError("competence_to", ValidationCodeOutOfRange, "competence_to must not be before competence_from"))
+	}
+
+	// Validate value range
+	filter.MinValue, errors = parseInboxValue("min_value", q.MinValue, errors)
+	filter.MaxValue, errors = parseInboxValue("max_value", q.MaxValue, errors)
+	if filter.MinValue != nil && filter.MaxValue != nil && *filter.MaxValue < *filter.MinValue {
+		errors = append(errors, NewValidationError("max_value", ValidationCodeOutOfRange, "max_value must not be less than min_value"))
+	}
+
+	// Validate manifestation status
+	if q.ManifestationStatus != "" && !inboxManifestationStatuses[q.ManifestationStatus] {
+		errors = append(errors, NewValidationError(
+			"manifestation_status",
+			ValidationCodeInvalid,
+			"manifestation_status must be one of active, confirmed, rejected, cancelled, substituted, cancellation_under_review or blocked",
+		))
+	}
+
+	// Validate municipality
+	if q.Municipality != "" && !municipalityCodePattern.MatchString(q.Municipality) {
+		errors = append(errors, NewValidationError("municipality", ValidationCodeInvalidFormat, "municipality must be a 7-digit IBGE code"))
+	}
+
+	return filter, errors
+}
+
+// parseInboxValue parses a non-negative monetary filter, appending a
+// validation error for the field if it is invalid.
+func parseInboxValue(field, value string, errors []ValidationError) (*float64, []ValidationError) {
+	if value == "" {
+		return nil, errors
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, append(errors, NewValidationError(field, ValidationCodeInvalidFormat, field+" must be a number"))
+	}
+	if parsed < 0 {
+		return nil, append(errors, NewValidationError(field, ValidationCodeOutOfRange, field+" must not be negative"))
+	}
+
+	return &parsed, errors
+}
@@ -0,0 +1,238 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// validDistributionRegistration returns a registration request that passes validation.
+func validDistributionRegistration() *distribution.RegistrationRequest {
+	return &distribution.RegistrationRequest{
+		CNPJ: "11.222.333/0001-81",
+		Certificate: &emission.CertificateRequest{
+			PFXBase64: "dGVzdA==",
+			Password:  "secret",
+		},
+	}
+}
+
+func TestDistributionValidator_ValidateRegistration(t *testing.T) {
+	validator := NewDistributionValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(req *distribution.RegistrationRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid request",
+			modify:        func(req *distribution.RegistrationRequest) {},
+			expectedCount: 0,
+		},
+		{
+			name: "valid request with webhook",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.WebhookURL = "https://example.com/webhook"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing CNPJ",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.CNPJ = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"cnpj"},
+		},
+		{
+			name: "invalid CNPJ",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.CNPJ = "11111111111111"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"cnpj"},
+		},
+		{
+			name: "missing certificate",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.Certificate = nil
+			},
+			expectedCount: 1,
+			checkFields:   []string{"certificate"},
+		},
+		{
+			name: "missing certificate password",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.Certificate.Password = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"certificate.password"},
+		},
+		{
+			name: "insecure webhook URL",
+			modify: func(req *distribution.RegistrationRequest) {
+				req.WebhookURL = "http://example.com/webhook"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"webhook_url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validDistributionRegistration()
+			tt.modify(req)
+
+			errors := validator.ValidateRegistration(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+func TestDistributionValidator_ParseInboxQuery(t *testing.T) {
+	validator := NewDistributionValidator()
+
+	tests := []struct {
+		name          string
+		query         distribution.InboxQuery
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+		checkFilter   func(t *testing.T, filter *distribution.InboxFilter)
+	}{
+		{
+			name:          "no filters",
+			query:         distribution.InboxQuery{},
+			expectedCount: 0,
+		},
+		{
+			name: "all filters",
+			query: distribution.InboxQuery{
+				CNPJ:                "11222333000181",
+				IssuerCNPJ:          "11.444.777/0001-61",
+				CompetenceFrom:      "2026-01-01",
+				CompetenceTo:        "2026-03-31",
+				MinValue:            "100",
+				MaxValue:            "2500.50",
+				ManifestationStatus: "rejected",
+				Municipality:        "3304557",
+			},
+			expectedCount: 0,
+			checkFilter: func(t *testing.T, filter *distribution.InboxFilter) {
+				if filter.IssuerCNPJ != "11444777000161" {
+					t.Errorf("IssuerCNPJ = %q, want 11444777000161", filter.IssuerCNPJ)
+				}
+				if filter.MaxValue == nil || *filter.MaxValue != 2500.50 {
+					t.Errorf("MaxValue = %v, want 2500.50", filter.MaxValue)
+				}
+				if filter.CompetenceTo == nil || filter.CompetenceTo.Format("2006-01-02") != "2026-03-31" {
+					t.Errorf("CompetenceTo = %v, want 2026-03-31", filter.CompetenceTo)
+				}
+			},
+		},
+		{
+			name:          "competence month",
+			query:         distribution.InboxQuery{Competence: "2026-02"},
+			expectedCount: 0,
+			checkFilter: func(t *testing.T, filter *distribution.InboxFilter) {
+				if filter.CompetenceFrom == nil || filter.CompetenceFrom.Format("2006-01-02") != "2026-02-01" {
+					t.Errorf("CompetenceFrom = %v, want 2026-02-01", filter.CompetenceFrom)
+				}
+				if filter.CompetenceTo == nil || filter.CompetenceTo.Format("2006-01-02") != "2026-02-28" {
+					t.Errorf("CompetenceTo = %v, want 2026-02-28", filter.CompetenceTo)
+				}
+			},
+		},
+		{
+			name:          "invalid issuer CNPJ",
+			query:         distribution.InboxQuery{IssuerCNPJ: "11111111111111"},
+			expectedCount: 1,
+			checkFields:   []string{"issuer_cnpj"},
+		},
+		{
+			name:          "competence combined with period",
+			query:         distribution.InboxQuery{Competence: "2026-02", CompetenceFrom: "2026-02-01"},
+			expectedCount: 1,
+			checkFields:   []string{"competence"},
+		},
+		{
+			name:          "inverted competence period",
+			query:         distribution.InboxQuery{CompetenceFrom: "2026-03-01", CompetenceTo: "2026-02-01"},
+			expectedCount: 1,
+			checkFields:   []string{"competence_to"},
+		},
+		{
+			name:          "invalid values",
+			query:         distribution.InboxQuery{MinValue: "-1", MaxValue: "abc"},
+			expectedCount: 2,
+			checkFields:   []string{"min_value", "max_value"},
+		},
+		{
+			name:          "inverted value range",
+			query:         distribution.InboxQuery{MinValue: "500", MaxValue: "100"},
+			expectedCount: 1,
+			checkFields:   []string{"max_value"},
+		},
+		{
+			name:          "unknown manifestation status",
+			query:         distribution.InboxQuery{ManifestationStatus: "pending"},
+			expectedCount: 1,
+			checkFields:   []string{"manifestation_status"},
+		},
+		{
+			name:          "invalid municipality",
+			query:         distribution.InboxQuery{Municipality: "355030"},
+			expectedCount: 1,
+			checkFields:   []string{"municipality"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, errors := validator.ParseInboxQuery(&tt.query)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+
+			if tt.checkFilter != nil {
+				tt.checkFilter(t, filter)
+			}
+		})
+	}
+}
@@ -17,17 +17,26 @@ var (
 	// municipalityCodePattern matches exactly 7 digits for IBGE municipality code.
 	municipalityCodePattern = regexp.MustCompile(`^\d{7}$`)
 
+	// municipalTaxCodePattern matches exactly 3 digits for cTribMun.
+	municipalTaxCodePattern = regexp.MustCompile(`^\d{3}$`)
+
 	// dpsSeriesPattern matches exactly 5 digits for DPS series.
 	dpsSeriesPattern = regexp.MustCompile(`^\d{5}$`)
 
 	// dpsNumberPattern matches 1-15 digits for DPS number.
 	dpsNumberPattern = regexp.MustCompile(`^\d{1,15}$`)
+
+	// benefitNumberPattern matches exactly 14 digits for the municipal benefit number (nBM).
+	benefitNumberPattern = regexp.MustCompile(`^\d{14}$`)
 )
 
 // Valid tax regime values.
 const (
-	TaxRegimeMEI   = "mei"
-	TaxRegimeMEEPP = "me_epp"
+	TaxRegimeMEI            = "mei"
+	TaxRegimeMEEPP          = "me_epp"
+	TaxRegimeLucroPresumido = "lucro_presumido"
+	TaxRegimeLucroReal      = "lucro_real"
+	TaxRegimeAutonomo       = "autonomo"
 )
 
 // ValidationError represents a single field validation error.
@@ -60,6 +69,7 @@ const (
 	ValidationCodeOutOfRange    = "out_of_range"
 	ValidationCodeInvalidFormat = "invalid_format"
 	ValidationCodeDuplicate     = "duplicate"
+	ValidationCodeNotOnRecord   = "not_on_record"
 )
 
 // EmissionValidator validates emission requests.
@@ -87,12 +97,29 @@ func (v *EmissionValidator) Validate(req *emission.EmissionRequest) []Validation
 		errors = append(errors, v.validateTaker(req.Taker)...)
 	}
 
+	// Validate intermediary (if present)
+	if req.Intermediary != nil {
+		errors = append(errors, v.validateIntermediary(req.Intermediary)...)
+	}
+
+	// Validate the party emitting the DPS
+	errors = append(errors, v.validateEmitter(req)...)
+
 	// Validate service
 	errors = append(errors, v.validateService(&req.Service)...)
 
+	// Validate the NBS and municipal tax codes (NBS required for exports and imports)
+	errors = append(errors, v.validateServiceCodes(req)...)
+
 	// Validate values
 	errors = append(errors, v.validateValues(&req.Values)...)
 
+	// Validate ISS treatment and municipal benefit reduction
+	errors = append(errors, v.validateISS(req)...)
+
+	// Validate the rules of the provider's tax regime
+	errors = append(errors, v.validateTaxRegime(req)...)
+
 	// Validate DPS
 	errors = append(errors, v.validateDPS(&req.DPS)...)
 
@@ -113,14 +140,21 @@ func (v *EmissionValidator) Validate(req *emission.EmissionRequest) []Validation
 func (v *EmissionValidator) validateProvider(provider *emission.ProviderRequest) []ValidationError {
 	var errors []ValidationError
 
-	// Validate CNPJ is present
-	if provider.CNPJ == "" {
+	// Validate exactly one of CNPJ or CPF is present
+	switch {
+	case provider.CNPJ == "" && provider.CPF == "":
 		errors = append(errors, NewValidationError(
 			"provider.cnpj",
 			ValidationCodeRequired,
-			"Provider CNPJ is required",
+			"Provider CNPJ or CPF is required",
 		))
-	} else {
+	case provider.CNPJ != "" && provider.CPF != "":
+		errors = append(errors, NewValidationError(
+			"provider",
+			ValidationCodeInvalid,
+			"Provider must have only one of CNPJ or CPF (they are mutually exclusive)",
+		))
+	case provider.CNPJ != "":
 		// Clean and validate CNPJ format and check digit
 		cleanCNPJ := cnpjcpf.CleanCNPJ(provider.CNPJ)
 		if !cnpjcpf.ValidateCNPJ(cleanCNPJ) {
@@ -130,6 +164,16 @@ func (v *EmissionValidator) validateProvider(provider *emission.ProviderRequest)
 				"Provider CNPJ is invalid (check digit mismatch or incorrect format)",
 			))
 		}
+	default:
+		// Clean and validate CPF format and check digit
+		cleanCPF := cnpjcpf.CleanCPF(provider.CPF)
+		if !cnpjcpf.ValidateCPF(cleanCPF) {
+			errors = append(errors, NewValidationError(
+				"provider.cpf",
+				ValidationCodeInvalid,
+				"Provider CPF is invalid (check digit mismatch or incorrect format)",
+			))
+		}
 	}
 
 	// Validate tax regime
@@ -139,11 +183,11 @@ func (v *EmissionValidator) validateProvider(provider *emission.ProviderRequest)
 			ValidationCodeRequired,
 			"Provider tax regime is required",
 		))
-	} else if provider.TaxRegime != TaxRegimeMEI && provider.TaxRegime != TaxRegimeMEEPP {
+	} else if !isValidTaxRegime(provider.TaxRegime) {
 		errors = append(errors, NewValidationError(
 			"provider.tax_regime",
 			ValidationCodeInvalid,
-			"Provider tax regime must be 'mei' or 'me_epp'",
+			"Provider tax regime must be 'mei', 'me_epp', 'lucro_presumido', 'lucro_real' or 'autonomo'",
 		))
 	}
 
@@ -172,6 +216,12 @@ func (v *EmissionValidator) validateTaker(taker *emission.TakerRequest) []Valida
 	return v.takerValidator.ValidateTaker(taker)
 }
 
+// validateIntermediary validates the intermediary section of the request.
+// The intermediary shares the taker rules for identification, name, phone, email and address.
+func (v *EmissionValidator) validateIntermediary(intermediary *emission.IntermediaryRequest) []ValidationError {
+	return v.takerValidator.ValidateIntermediary(intermediary)
+}
+
 // validateService validates the service section of the request.
 func (v *EmissionValidator) validateService(service *emission.ServiceRequest) []ValidationError {
 	var errors []ValidationError
@@ -221,6 +271,17 @@ func (v *EmissionValidator) validateService(service *emission.ServiceRequest) []
 		))
 	}
 
+	// Validate the country of services performed abroad
+	errors = append(errors, v.validateServiceCountry(service.CountryCode)...)
+
+	// Validate foreign trade (if present)
+	if service.ForeignTrade != nil {
+		errors = append(errors, v.validateForeignTrade(service.ForeignTrade)...)
+	}
+
+	// Validate construction site and event (required for some service codes)
+	errors = append(errors, v.validateServiceSite(service)...)
+
 	return errors
 }
 
@@ -272,6 +333,25 @@ func (v *EmissionValidator) validateValues(values *emission.ValuesRequest) []Val
 		))
 	}
 
+	// Validate municipal benefit number format (nBM)
+	if values.MunicipalBenefit != nil && !benefitNumberPattern.MatchString(values.MunicipalBenefit.Number) {
+		errors = append(errors, NewValidationError(
+			"values.municipal_benefit.number",
+			ValidationCodeInvalidFormat,
+			"Municipal benefit number must be exactly 14 digits",
+		))
+	}
+
+	// Validate deduction documents (if present)
+	if len(values.DeductionDocuments) > 0 {
+		errors = append(errors, v.validateDeductionDocuments(values)...)
+	}
+
+	// Validate federal taxes (if present)
+	if values.FederalTaxes != nil {
+		errors = append(errors, v.validateFederalTaxes(values)...)
+	}
+
 	// Validate that discounts don't exceed service value
 	totalDeductions := values.UnconditionalDiscount + values.ConditionalDiscount + values.Deductions
 	if totalDeductions > values.ServiceValue {
@@ -372,6 +452,11 @@ func (v *EmissionValidator) validateWebhookURL(url string) []ValidationError {
 	return errors
 }
 
+// IsValidMunicipalityCode reports whether code is a 7-digit IBGE municipality code.
+func IsValidMunicipalityCode(code string) bool {
+	return municipalityCodePattern.MatchString(code)
+}
+
 // isValidMonetaryValue checks if a float64 value has at most 2 decimal places.
 // This is a simplified check; in production, decimal.Decimal should be used.
 func isValidMonetaryValue(value float64) bool {
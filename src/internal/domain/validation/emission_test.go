@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+func TestEmissionValidator_ValidateProvider(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		provider      emission.ProviderRequest
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "company provider",
+			provider:      emission.ProviderRequest{CNPJ: "11.222.333/0001-81", TaxRegime: TaxRegimeMEEPP, Name: "Empresa Ltda"},
+			expectedCount: 0,
+		},
+		{
+			name:          "individual provider",
+			provider:      emission.ProviderRequest{CPF: "529.982.247-25", TaxRegime: TaxRegimeAutonomo, Name: "Maria Silva"},
+			expectedCount: 0,
+		},
+		{
+			name:          "missing registration",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeMEI, Name: "Sem Documento"},
+			expectedCount: 1,
+			checkFields:   []string{"provider.cnpj"},
+		},
+		{
+			name:          "both CNPJ and CPF",
+			provider:      emission.ProviderRequest{CNPJ: "11222333000181", CPF: "52998224725", TaxRegime: TaxRegimeMEI, Name: "Ambos"},
+			expectedCount: 1,
+			checkFields:   []string{"provider"},
+		},
+		{
+			name:          "invalid CPF",
+			provider:      emission.ProviderRequest{CPF: "52998224700", TaxRegime: TaxRegimeAutonomo, Name: "Maria Silva"},
+			expectedCount: 1,
+			checkFields:   []string{"provider.cpf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.validateProvider(&tt.provider)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
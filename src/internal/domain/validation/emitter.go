@@ -0,0 +1,284 @@
+package validation
+
+import (
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// validateEmitter validates who emits the DPS (tpEmit) and the provider address,
+// which is only informed when the provider is not the emitter.
+func (v *EmissionValidator) validateEmitter(req *emission.EmissionRequest) []ValidationError {
+	if errors := validateEmitterParty(req); len(errors) > 0 {
+		return errors
+	}
+
+	errors := v.validateProviderAddress(req)
+	errors = append(errors, validateEmissionReason(req)...)
+	errors = append(errors, validateEmitterWithholding(req)...)
+
+	return errors
+}
+
+// validateEmitterParty validates the party emitting the DPS. A taker or intermediary
+// emitting the DPS must be present in the request and identified by CNPJ or CPF,
+// since its registration forms the DPS ID.
+func validateEmitterParty(req *emission.EmissionRequest) []ValidationError {
+	switch req.EmitterTypeOrDefault() {
+	case emission.EmitterProvider:
+		return nil
+	case emission.EmitterTaker:
+		if req.Taker == nil {
+			return []ValidationError{NewValidationError(
+				"taker",
+				ValidationCodeRequired,
+				"Taker is required when the taker emits the DPS",
+			)}
+		}
+		if req.EmitterRegistration() == "" {
+			return []ValidationError{NewValidationError(
+				"taker.cnpj",
+				ValidationCodeRequired,
+				"Taker emitting the DPS must be identified by CNPJ or CPF",
+			)}
+		}
+	case emission.EmitterIntermediary:
+		if req.Intermediary == nil {
+			return []ValidationError{NewValidationError(
+				"intermediary",
+				ValidationCodeRequired,
+				"Intermediary is required when the intermediary emits the DPS",
+			)}
+		}
+		if req.EmitterRegistration() == "" {
+			return []ValidationError{NewValidationError(
+				"intermediary.cnpj",
+				ValidationCodeRequired,
+				"Intermediary emitting the DPS must be identified by CNPJ or CPF",
+			)}
+		}
+	default:
+		return []ValidationError{NewValidationError(
+			"emitter_type",
+			ValidationCodeInvalid,
+			"Emitter type (tpEmit) must be 1 (provider), 2 (taker) or 3 (intermediary)",
+		)}
+	}
+
+	return nil
+}
+
+// validateProviderAddress validates the provider address (prest/end). SEFIN requires the
+// national address of a provider identified by CNPJ or CPF when the taker or intermediary
+// emits the DPS (E0129, E0125), and rejects it when the provider does (E0128).
+func (v *EmissionValidator) validateProviderAddress(req *emission.EmissionRequest) []ValidationError {
+	addr := req.Provider.Address
+
+	if req.EmitterTypeOrDefault() == emission.EmitterProvider {
+		if addr != nil {
+			return []ValidationError{NewValidationError(
+				"provider.address",
+				ValidationCodeInvalid,
+				"Provider address is only informed when the taker or intermediary emits the DPS",
+			)}
+		}
+		return nil
+	}
+
+	if addr == nil {
+		return []ValidationError{NewValidationError(
+			"provider.address",
+			ValidationCodeRequired,
+			"Provider address is required when the taker or intermediary emits the DPS",
+		)}
+	}
+
+	return v.takerValidator.validateNationalAddress("provider.address", addr)
+}
+
+// validateEmissionReason validates why the taker or intermediary emits the DPS
+// (cMotivoEmisTI), which is not informed when the provider emits it (E0029), and the
+// access key of the rejected NFS-e (chNFSeRej), only informed for a rejection (E0034).
+func validateEmissionReason(req *emission.EmissionRequest) []ValidationError {
+	var errors []ValidationError
+	reason := req.EmissionReason
+
+	switch {
+	case reason == 0:
+	case req.EmitterTypeOrDefault() == emission.EmitterProvider:
+		errors = append(errors, NewValidationError(
+			"emission_reason",
+			ValidationCodeInvalid,
+			"Emission reason (cMotivoEmisTI) is only informed when the taker or intermediary emits the DPS",
+		))
+	case reason < emission.EmissionReasonImport || reason > emission.EmissionReasonRejection:
+		errors = append(errors, NewValidationError(
+			"emission_reason",
+			ValidationCodeInvalid,
+			"Emission reason (cMotivoEmisTI) must be 1, 2, 3 or 4",
+		))
+	}
+
+	rejection := reason == emission.EmissionReasonRejection && req.EmitterTypeOrDefault() != emission.EmitterProvider
+	switch {
+	case req.RejectedAccessKey == "":
+		if rejection {
+			errors = append(errors, NewValidationError(
+				"rejected_access_key",
+				ValidationCodeRequired,
+				"Access key of the rejected NFS-e (chNFSeRej) is required for emission reason 4",
+			))
+		}
+	case !rejection:
+		errors = append(errors, NewValidationError(
+			"rejected_access_key",
+			ValidationCodeInvalid,
+			"Access key of the rejected NFS-e (chNFSeRej) is only informed by a taker or intermediary rejecting the provider's NFS-e (emission reason 4)",
+		))
+	case !nfseKeyPattern.MatchString(req.RejectedAccessKey):
+		errors = append(errors, NewValidationError(
+			"rejected_access_key",
+			ValidationCodeInvalidFormat,
+			"Access key of the rejected NFS-e must be exactly 50 digits",
+		))
+	}
+
+	return errors
+}
+
+// placeOfServiceIncidence are the national service codes (cTribNac) whose ISS is due
+// in the municipality where the service is provided (LC 116/03, art. 3), as listed in
+// the incidence table of ANEXO I. The ISS of the remaining codes is due where the
+// provider is established, except for subitem 17.05 (taker's establishment).
+var placeOfServiceIncidence = map[string]bool{
+	// Use of public roads and rights of way
+	"030401": true, "030402": true, "030403": true,
+	// Temporary structures for events
+	"030501": true,
+	// Civil construction works
+	"070201": true, "070202": true,
+	// Demolition
+	"070401": true,
+	// Repair and renovation of buildings and roads
+	"070501": true, "070502": true,
+	// Waste collection and disposal
+	"070901": true, "070902": true,
+	// Cleaning of public and private spaces
+	"071001": true, "071002": true,
+	// Decoration and gardening
+	"071101": true, "071102": true,
+	// Control of physical, chemical and biological agents
+	"071201": true,
+	// Forest planting and reforestation
+	"071601": true,
+	// Shoring and slope containment
+	"071701": true,
+	// Silting removal and dredging
+	"071801": true,
+	// Supervision of engineering works
+	"071901": true,
+	// Parking and vehicle storage
+	"110101": true, "110102": true,
+	// Surveillance and security
+	"110201": true,
+	// Storage, loading and unloading of goods
+	"110401": true, "110402": true,
+	// Entertainment, leisure and events
+	"120101": true, "120201": true, "120301": true, "120401": true, "120501": true, "120601": true,
+	"120701": true, "120801": true, "120901": true, "120902": true, "120903": true, "121001": true,
+	"121101": true, "121201": true, "121401": true, "121501": true, "121601": true, "121701": true,
+	// Municipal public transport
+	"160102": true, "160103": true, "160104": true,
+	// Other municipal transport
+	"160201": true,
+	// Organization of fairs, exhibitions and congresses
+	"171001": true, "171002": true,
+	// Port services
+	"200101": true,
+	// Airport services
+	"200201": true,
+	// Road and rail terminal services
+	"200301": true,
+	// Highway operation
+	"220101": true,
+}
+
+const (
+	// takerIncidenceCode is the national service code (cTribNac) of temporary labor
+	// (17.05), whose ISS is due where the taker is established.
+	takerIncidenceCode = "170501"
+
+	// nonIncidenceCode is the national service code (cTribNac) of services outside the
+	// scope of the ISS and ICMS (99.01), which have no incidence municipality.
+	nonIncidenceCode = "990101"
+
+	// offshoreMunicipalityCode is the place of service (cLocPrestacao) of services
+	// provided in maritime waters, whose ISS is due where the provider is established.
+	offshoreMunicipalityCode = "0000000"
+)
+
+// incidenceMunicipality returns the IBGE code of the municipality where the ISS of the
+// service is due, or an empty string when it cannot be determined from the request.
+// The ISS of a service import is due where the emitter is established, so a service
+// performed abroad has no incidence municipality to check.
+func incidenceMunicipality(req *emission.EmissionRequest) string {
+	service := req.Service
+	if service.CountryCode != "" || service.NationalCode == nonIncidenceCode {
+		return ""
+	}
+
+	switch {
+	case placeOfServiceIncidence[service.NationalCode] && service.MunicipalityCode != offshoreMunicipalityCode:
+		return service.MunicipalityCode
+	case service.NationalCode == takerIncidenceCode:
+		if req.Taker != nil && req.Taker.Address != nil {
+			return req.Taker.Address.MunicipalityCode
+		}
+	case req.Provider.Address != nil:
+		return req.Provider.Address.MunicipalityCode
+	}
+
+	return ""
+}
+
+// validateEmitterWithholding validates the ISS withholding of a DPS emitted by the taker
+// or intermediary. The emitter cannot withhold the ISS when it is established in a
+// municipality other than the incidence municipality (E0031, E0032), so its address
+// is required to check it.
+func validateEmitterWithholding(req *emission.EmissionRequest) []ValidationError {
+	var field string
+	var addr *emission.AddressRequest
+
+	switch req.EmitterTypeOrDefault() {
+	case emission.EmitterTaker:
+		field, addr = "taker.address", req.Taker.Address
+	case emission.EmitterIntermediary:
+		field, addr = "intermediary.address", req.Intermediary.Address
+	default:
+		return nil
+	}
+
+	if req.Values.ISS.WithholdingOrDefault() == emission.ISSWithholdingNone {
+		return nil
+	}
+
+	incidence := incidenceMunicipality(req)
+	if incidence == "" {
+		return nil
+	}
+
+	if addr == nil {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeRequired,
+			"Emitter address is required to check the ISS withholding against the incidence municipality",
+		)}
+	}
+	if addr.MunicipalityCode != incidence {
+		return []ValidationError{NewValidationError(
+			"values.iss.withholding",
+			ValidationCodeInvalid,
+			"ISS cannot be withheld by an emitter established outside the incidence municipality ("+incidence+")",
+		)}
+	}
+
+	return nil
+}
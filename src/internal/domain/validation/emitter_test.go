@@ -0,0 +1,463 @@
+package validation
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
+)
+
+// testProviderAddress returns the national address of the provider.
+func testProviderAddress() *emission.AddressRequest {
+	return &emission.AddressRequest{
+		Street:           "Rua do Prestador",
+		Number:           "200",
+		Neighborhood:     "Centro",
+		MunicipalityCode: "3550308",
+		State:            "SP",
+		PostalCode:       "01310100",
+	}
+}
+
+// withholdingTaker makes the taker, established in takerMunicipality, emit the DPS and
+// withhold the ISS of a service provided in the provider's municipality.
+func withholdingTaker(req *emission.EmissionRequest, nationalCode, takerMunicipality string) {
+	req.EmitterType = emission.EmitterTaker
+	req.Taker = &emission.TakerRequest{
+		CNPJ:    "11444777000161",
+		Name:    "Marketplace",
+		Address: &emission.AddressRequest{MunicipalityCode: takerMunicipality},
+	}
+	req.Provider.Address = testProviderAddress()
+	req.Service = emission.ServiceRequest{NationalCode: nationalCode, MunicipalityCode: "3550308"}
+	req.Values.ISS = &emission.ISSRequest{Withholding: emission.ISSWithholdingTaker}
+}
+
+func TestEmissionValidator_ValidateEmitter(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(req *emission.EmissionRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "provider by default",
+			expectedCount: 0,
+		},
+		{
+			name: "taker with CNPJ",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "intermediary with CPF",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterIntermediary
+				req.Intermediary = &emission.IntermediaryRequest{CPF: "52998224725", Name: "Corretor"}
+				req.Provider.Address = testProviderAddress()
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "taker emitter without provider address",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"provider.address"},
+		},
+		{
+			name: "intermediary emitter with foreign provider address",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterIntermediary
+				req.Intermediary = &emission.IntermediaryRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.Provider.Address.CountryCode = "US"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"provider.address.country_code"},
+		},
+		{
+			name:          "provider emitter with provider address",
+			modify:        func(req *emission.EmissionRequest) { req.Provider.Address = testProviderAddress() },
+			expectedCount: 1,
+			checkFields:   []string{"provider.address"},
+		},
+		{
+			name:          "invalid emitter type",
+			modify:        func(req *emission.EmissionRequest) { req.EmitterType = 4 },
+			expectedCount: 1,
+			checkFields:   []string{"emitter_type"},
+		},
+		{
+			name:          "taker emitter without taker",
+			modify:        func(req *emission.EmissionRequest) { req.EmitterType = emission.EmitterTaker },
+			expectedCount: 1,
+			checkFields:   []string{"taker"},
+		},
+		{
+			name: "foreign taker emitter",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{NIF: "123456789", Name: "Foreign Corp"}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"taker.cnpj"},
+		},
+		{
+			name:          "intermediary emitter without intermediary",
+			modify:        func(req *emission.EmissionRequest) { req.EmitterType = emission.EmitterIntermediary },
+			expectedCount: 1,
+			checkFields:   []string{"intermediary"},
+		},
+		{
+			name: "taker rejecting the provider's NFS-e",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.EmissionReason = emission.EmissionReasonRejection
+				req.RejectedAccessKey = "35503082211222333000181000000000000000000000000001"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "intermediary emitting by municipal law",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterIntermediary
+				req.Intermediary = &emission.IntermediaryRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.EmissionReason = emission.EmissionReasonMunicipalLaw
+			},
+			expectedCount: 0,
+		},
+		{
+			name:          "provider emitter with emission reason",
+			modify:        func(req *emission.EmissionRequest) { req.EmissionReason = emission.EmissionReasonImport },
+			expectedCount: 1,
+			checkFields:   []string{"emission_reason"},
+		},
+		{
+			name: "invalid emission reason",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.EmissionReason = 5
+			},
+			expectedCount: 1,
+			checkFields:   []string{"emission_reason"},
+		},
+		{
+			name: "rejection without rejected access key",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.EmissionReason = emission.EmissionReasonRejection
+			},
+			expectedCount: 1,
+			checkFields:   []string{"rejected_access_key"},
+		},
+		{
+			name: "rejected access key without rejection",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.EmissionReason = emission.EmissionReasonProviderRefusal
+				req.RejectedAccessKey = "35503082211222333000181000000000000000000000000001"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"rejected_access_key"},
+		},
+		{
+			name: "rejected access key with invalid format",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterTaker
+				req.Taker = &emission.TakerRequest{CNPJ: "11444777000161", Name: "Marketplace"}
+				req.Provider.Address = testProviderAddress()
+				req.EmissionReason = emission.EmissionReasonRejection
+				req.RejectedAccessKey = "12345"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"rejected_access_key"},
+		},
+		{
+			name: "taker withholding in the provider's municipality",
+			modify: func(req *emission.EmissionRequest) {
+				withholdingTaker(req, "010101", "3550308")
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "taker withholding outside the provider's municipality",
+			modify: func(req *emission.EmissionRequest) {
+				withholdingTaker(req, "010101", "3509502")
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.withholding"},
+		},
+		{
+			name: "taker withholding in the place of service",
+			modify: func(req *emission.EmissionRequest) {
+				withholdingTaker(req, "070201", "3509502")
+				req.Service.MunicipalityCode = "3509502"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "taker outside the municipality not withholding",
+			modify: func(req *emission.EmissionRequest) {
+				withholdingTaker(req, "010101", "3509502")
+				req.Values.ISS.Withholding = emission.ISSWithholdingNone
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "taker withholding on a service import",
+			modify: func(req *emission.EmissionRequest) {
+				withholdingTaker(req, "010101", "3509502")
+				req.Service.CountryCode = "US"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "taker withholding without taker address",
+			modify: func(req *emission.EmissionRequest) {
+				withholdingTaker(req, "010101", "3550308")
+				req.Taker.Address = nil
+			},
+			expectedCount: 1,
+			checkFields:   []string{"taker.address"},
+		},
+		{
+			name: "intermediary withholding outside the provider's municipality",
+			modify: func(req *emission.EmissionRequest) {
+				req.EmitterType = emission.EmitterIntermediary
+				req.Intermediary = &emission.IntermediaryRequest{
+					CNPJ:    "11444777000161",
+					Name:    "Marketplace",
+					Address: &emission.AddressRequest{MunicipalityCode: "3509502"},
+				}
+				req.Provider.Address = testProviderAddress()
+				req.Service = emission.ServiceRequest{NationalCode: "010101", MunicipalityCode: "3550308"}
+				req.Values.ISS = &emission.ISSRequest{Withholding: emission.ISSWithholdingIntermediary}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.withholding"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &emission.EmissionRequest{
+				Provider: emission.ProviderRequest{CNPJ: "11222333000181", TaxRegime: TaxRegimeMEEPP},
+			}
+			if tt.modify != nil {
+				tt.modify(req)
+			}
+
+			errors := validator.validateEmitter(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+// generateHolderCertificate creates a self-signed certificate whose common name
+// identifies its holder, as ICP-Brasil certificates do ("NAME:registration").
+func generateHolderCertificate(t *testing.T, commonName string) *xmlsigner.CertificateInfo {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	return &xmlsigner.CertificateInfo{PrivateKey: privateKey, Certificate: cert}
+}
+
+func TestValidateCertificateHolder(t *testing.T) {
+	marketplace := &emission.EmissionRequest{
+		EmitterType: emission.EmitterTaker,
+		Provider:    emission.ProviderRequest{CNPJ: "11222333000181"},
+		Taker:       &emission.TakerRequest{CNPJ: "11444777000161"},
+	}
+
+	tests := []struct {
+		name          string
+		req           *emission.EmissionRequest
+		commonName    string
+		expectedCount int
+	}{
+		{
+			name:          "provider certificate",
+			req:           &emission.EmissionRequest{Provider: emission.ProviderRequest{CNPJ: "11222333000181"}},
+			commonName:    "PRESTADOR LTDA:11222333000181",
+			expectedCount: 0,
+		},
+		{
+			name:          "headquarters certificate of a branch provider",
+			req:           &emission.EmissionRequest{Provider: emission.ProviderRequest{CNPJ: "11222333000262"}},
+			commonName:    "PRESTADOR LTDA:11222333000181",
+			expectedCount: 0,
+		},
+		{
+			name:          "e-CPF of an individual provider",
+			req:           &emission.EmissionRequest{Provider: emission.ProviderRequest{CPF: "529.982.247-25"}},
+			commonName:    "MARIA SILVA:52998224725",
+			expectedCount: 0,
+		},
+		{
+			name:          "another person's e-CPF",
+			req:           &emission.EmissionRequest{Provider: emission.ProviderRequest{CPF: "52998224725"}},
+			commonName:    "JOAO SOUZA:11144477735",
+			expectedCount: 1,
+		},
+		{
+			name:          "taker certificate for taker emission",
+			req:           marketplace,
+			commonName:    "MARKETPLACE SA:11444777000161",
+			expectedCount: 0,
+		},
+		{
+			name:          "provider certificate for taker emission",
+			req:           marketplace,
+			commonName:    "PRESTADOR LTDA:11222333000181",
+			expectedCount: 1,
+		},
+		{
+			name:          "certificate without holder registration",
+			req:           marketplace,
+			commonName:    "Test Certificate",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateCertificateHolder(tt.req, generateHolderCertificate(t, tt.commonName))
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+			for _, err := range errors {
+				if err.Field != "certificate.pfx_base64" || err.Code != CertificateCodeHolderMismatch {
+					t.Errorf("unexpected error: %+v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEventCertificateHolder(t *testing.T) {
+	tests := []struct {
+		name          string
+		author        event.AuthorRequest
+		commonName    string
+		expectedCount int
+	}{
+		{
+			name:          "author certificate",
+			author:        event.AuthorRequest{CNPJ: "11222333000181"},
+			commonName:    "PRESTADOR LTDA:11222333000181",
+			expectedCount: 0,
+		},
+		{
+			name:          "headquarters certificate of a branch author",
+			author:        event.AuthorRequest{CNPJ: "11222333000262"},
+			commonName:    "PRESTADOR LTDA:11222333000181",
+			expectedCount: 0,
+		},
+		{
+			name:          "e-CPF of an individual author",
+			author:        event.AuthorRequest{CPF: "52998224725"},
+			commonName:    "MARIA SILVA:52998224725",
+			expectedCount: 0,
+		},
+		{
+			name:          "another company's certificate",
+			author:        event.AuthorRequest{CNPJ: "11222333000181"},
+			commonName:    "MARKETPLACE SA:11444777000161",
+			expectedCount: 1,
+		},
+		{
+			name:          "e-CNPJ for an individual author",
+			author:        event.AuthorRequest{CPF: "52998224725"},
+			commonName:    "PRESTADOR LTDA:11222333000181",
+			expectedCount: 1,
+		},
+		{
+			name:          "certificate without holder registration",
+			author:        event.AuthorRequest{CNPJ: "11222333000181"},
+			commonName:    "Test Certificate",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateEventCertificateHolder(tt.author, generateHolderCertificate(t, tt.commonName))
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+			for _, err := range errors {
+				if err.Field != "certificate.pfx_base64" || err.Code != CertificateCodeHolderMismatch {
+					t.Errorf("unexpected error: %+v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/pkg/cnpjcpf"
+)
+
+// Event validation constants.
+const (
+	// EventReasonMinLength is the minimum length of an event reason (xMotivo).
+	EventReasonMinLength = 15
+
+	// EventReasonMaxLength is the maximum length of an event reason (xMotivo).
+	EventReasonMaxLength = 255
+)
+
+// rejectionEventIDPattern matches the Id of a rejection event (TSIdNumEvento): 59 digits.
+var rejectionEventIDPattern = regexp.MustCompile(`^[0-9]{59}$`)
+
+// EventValidator validates event registration requests (pedidos de registro de evento).
+type EventValidator struct {
+	// emissionValidator is reused for the certificate and webhook URL checks,
+	// which follow the same rules as emission requests.
+	emissionValidator *EmissionValidator
+}
+
+// NewEventValidator creates a new event validator.
+func NewEventValidator() *EventValidator {
+	return &EventValidator{
+		emissionValidator: NewEmissionValidator(),
+	}
+}
+
+// ValidateCancellation performs validation of an NFS-e cancellation request (e101101).
+// Returns a slice of ValidationErrors if any validation fails.
+func (v *EventValidator) ValidateCancellation(req *event.CancellationRequest) []ValidationError {
+	var errors []ValidationError
+
+	// Validate author
+	errors = append(errors, v.validateAuthor(&req.Author)...)
+
+	// Validate reason code (cMotivo)
+	switch req.ReasonCode {
+	case event.CancellationReasonEmissionError,
+		event.CancellationReasonServiceNotProvided,
+		event.CancellationReasonOther:
+	case 0:
+		errors = append(errors, NewValidationError(
+			"reason_code",
+			ValidationCodeRequired,
+			"Cancellation reason code is required",
+		))
+	default:
+		errors = append(errors, NewValidationError(
+			"reason_code",
+			ValidationCodeInvalid,
+			"Cancellation reason code must be 1 (emission error), 2 (service not provided) or 9 (other)",
+		))
+	}
+
+	// Validate reason text (xMotivo)
+	errors = append(errors, v.validateReason("reason", req.Reason)...)
+
+	// Validate certificate (required to sign the event)
+	if req.Certificate == nil {
+		errors = append(errors, NewValidationError(
+			"certificate",
+			ValidationCodeRequired,
+			"Certificate is required to sign the event",
+		))
+	} else {
+		errors = append(errors, v.emissionValidator.validateCertificate(req.Certificate)...)
+	}
+
+	// Validate webhook URL (if present)
+	if req.WebhookURL != "" {
+		errors = append(errors, v.emissionValidator.validateWebhookURL(req.WebhookURL)...)
+	}
+
+	return errors
+}
+
+// ValidateReplacement performs validation of an NFS-e replacement request.
+// The corrected emission payload follows the emission rules; in addition, a
+// certificate is required because the replaced NFS-e is cancelled by an
+// e105102 event signed with it.
+func (v *EventValidator) ValidateReplacement(req *event.ReplacementRequest) []ValidationError {
+	var errors []ValidationError
+
+	// Validate the corrected emission payload
+	errors = append(errors, v.emissionValidator.Validate(&req.EmissionRequest)...)
+
+	// Validate certificate presence (format is checked by the emission validator)
+	if req.Certificate == nil {
+		errors = append(errors, NewValidationError(
+			"certificate",
+			ValidationCodeRequired,
+			"Certificate is required to sign the substitute DPS and the cancellation event",
+		))
+	}
+
+	// Validate substitution reason code (cMotivo)
+	reasonCode := strings.TrimSpace(req.Substitution.ReasonCode)
+	if reasonCode == "" {
+		errors = append(errors, NewValidationError(
+			"substitution.reason_code",
+			ValidationCodeRequired,
+			"Substitution reason code is required",
+		))
+	} else if !isValidSubstitutionReasonCode(reasonCode) {
+		errors = append(errors, NewValidationError(
+			"substitution.reason_code",
+			ValidationCodeInvalid,
+			"Substitution reason code must be one of 01, 02, 03, 04, 05 or 99",
+		))
+	}
+
+	// Validate reason text (xMotivo) - required for "other", optional otherwise
+	if reasonCode == event.SubstitutionReasonOther || strings.TrimSpace(req.Substitution.Reason) != "" {
+		errors = append(errors, v.validateReason("substitution.reason", req.Substitution.Reason)...)
+	}
+
+	return errors
+}
+
+// ValidateManifestation performs validation of an NFS-e manifestation request.
+// The role and action must map to an event the role is allowed to register;
+// the remaining fields are checked according to the resulting event type.
+func (v *EventValidator) ValidateManifestation(req *event.ManifestationRequest) []ValidationError {
+	var errors []ValidationError
+
+	// Validate role and action
+	validRole := event.IsValidRole(req.Role)
+	validAction := event.IsValidAction(req.Action)
+	if req.Role == "" {
+		errors = append(errors, NewValidationError("role", ValidationCodeRequired, "Role is required"))
+	} else if !validRole {
+		errors = append(errors, NewValidationError(
+			"role",
+			ValidationCodeInvalid,
+			"Role must be one of: provider, taker, intermediary, municipality",
+		))
+	}
+	if req.Action == "" {
+		errors = append(errors, NewValidationError("action", ValidationCodeRequired, "Action is required"))
+	} else if !validAction {
+		errors = append(errors, NewValidationError(
+			"action",
+			ValidationCodeInvalid,
+			"Action must be one of: confirm, reject, annul_rejection",
+		))
+	}
+
+	eventType, allowed := event.ManifestationEventType(req.Role, req.Action)
+	if validRole && validAction && !allowed {
+		errors = append(errors, NewValidationError(
+			"action",
+			ValidationCodeInvalid,
+			fmt.Sprintf("Role %s may not register action %s", req.Role, req.Action),
+		))
+	}
+
+	// Validate author
+	errors = append(errors, v.validateAuthor(&req.Author)...)
+
+	// Validate event-specific fields
+	switch {
+	case event.IsRejectionType(eventType):
+		switch req.ReasonCode {
+		case event.RejectionReasonDuplicate,
+			event.RejectionReasonIssuedByTaker,
+			event.RejectionReasonNoTaxableEvent,
+			event.RejectionReasonTaxLiability,
+			event.RejectionReasonValues,
+			event.RejectionReasonOther:
+		case 0:
+			errors = append(errors, NewValidationError(
+				"reason_code",
+				ValidationCodeRequired,
+				"Rejection reason code is required",
+			))
+		default:
+			errors = append(errors, NewValidationError(
+				"reason_code",
+				ValidationCodeInvalid,
+				"Rejection reason code must be one of 1, 2, 3, 4, 5 or 9",
+			))
+		}
+
+		// Reason text (xMotivo) - required for "other", optional otherwise
+		if req.ReasonCode == event.RejectionReasonOther || strings.TrimSpace(req.Reason) != "" {
+			errors = append(errors, v.validateReason("reason", req.Reason)...)
+		}
+	case eventType == event.TypeRejectionAnnulment:
+		if req.TaxAgentCPF == "" {
+			errors = append(errors, NewValidationError(
+				"tax_agent_cpf",
+				ValidationCodeRequired,
+				"Tax agent CPF is required to annul a rejection",
+			))
+		} else if !cnpjcpf.ValidateCPF(cnpjcpf.CleanCPF(req.TaxAgentCPF)) {
+			errors = append(errors, NewValidationError(
+				"tax_agent_cpf",
+				ValidationCodeInvalid,
+				"Tax agent CPF is invalid (check digit mismatch or incorrect format)",
+			))
+		}
+
+		rejectionEventID := strings.TrimSpace(req.RejectionEventID)
+		if rejectionEventID == "" {
+			errors = append(errors, NewValidationError(
+				"rejection_event_id",
+				ValidationCodeRequired,
+				"Rejection event ID is required to annul a rejection",
+			))
+		} else if !rejectionEventIDPattern.MatchString(rejectionEventID) {
+			errors = append(errors, NewValidationError(
+				"rejection_event_id",
+				ValidationCodeInvalidFormat,
+				"Rejection event ID must be exactly 59 digits",
+			))
+		}
+
+		errors = append(errors, v.validateReason("reason", req.Reason)...)
+	}
+
+	// Validate certificate (required to sign the event)
+	if req.Certificate == nil {
+		errors = append(errors, NewValidationError(
+			"certificate",
+			ValidationCodeRequired,
+			"Certificate is required to sign the event",
+		))
+	} else {
+		errors = append(errors, v.emissionValidator.validateCertificate(req.Certificate)...)
+	}
+
+	// Validate webhook URL (if present)
+	if req.WebhookURL != "" {
+		errors = append(errors, v.emissionValidator.validateWebhookURL(req.WebhookURL)...)
+	}
+
+	return errors
+}
+
+// isValidSubstitutionReasonCode reports whether code is an accepted substitution cMotivo.
+func isValidSubstitutionReasonCode(code string) bool {
+	switch code {
+	case event.SubstitutionReasonSimplesExclusion,
+		event.SubstitutionReasonSimplesInclusion,
+		event.SubstitutionReasonExemptionInclusion,
+		event.SubstitutionReasonExemptionExclusion,
+		event.SubstitutionReasonTakerRejection,
+		event.SubstitutionReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateAuthor validates the event author identification.
+// Exactly one of CNPJ or CPF must be provided.
+func (v *EventValidator) validateAuthor(author *event.AuthorRequest) []ValidationError {
+	var errors []ValidationError
+
+	hasCNPJ := author.CNPJ != ""
+	hasCPF := author.CPF != ""
+
+	if !hasCNPJ && !hasCPF {
+		errors = append(errors, NewValidationError(
+			"author",
+			ValidationCodeRequired,
+			"Author CNPJ or CPF is required",
+		))
+		return errors
+	}
+
+	if hasCNPJ && hasCPF {
+		errors = append(errors, NewValidationError(
+			"author",
+			ValidationCodeInvalid,
+			"Only one of author CNPJ or CPF may be provided",
+		))
+		return errors
+	}
+
+	if hasCNPJ && !cnpjcpf.ValidateCNPJ(cnpjcpf.CleanCNPJ(author.CNPJ)) {
+		errors = append(errors, NewValidationError(
+			"author.cnpj",
+			ValidationCodeInvalid,
+			"Author CNPJ is invalid (check digit mismatch or incorrect format)",
+		))
+	}
+
+	if hasCPF && !cnpjcpf.ValidateCPF(cnpjcpf.CleanCPF(author.CPF)) {
+		errors = append(errors, NewValidationError(
+			"author.cpf",
+			ValidationCodeInvalid,
+			"Author CPF is invalid (check digit mismatch or incorrect format)",
+		))
+	}
+
+	return errors
+}
+
+// validateReason validates a free-text event reason (xMotivo): 15-255 characters.
+func (v *EventValidator) validateReason(field, reason string) []ValidationError {
+	var errors []ValidationError
+
+	trimmed := strings.TrimSpace(reason)
+	if trimmed == "" {
+		errors = append(errors, NewValidationError(
+			field,
+			ValidationCodeRequired,
+			"Reason is required",
+		))
+		return errors
+	}
+
+	length := utf8.RuneCountInString(trimmed)
+	if length < EventReasonMinLength {
+		errors = append(errors, NewValidationError(
+			field,
+			ValidationCodeTooShort,
+			"Reason must have at least 15 characters",
+		))
+	} else if length > EventReasonMaxLength {
+		errors = append(errors, NewValidationError(
+			field,
+			ValidationCodeTooLong,
+			"Reason must not exceed 255 characters",
+		))
+	}
+
+	for _, r := range trimmed {
+		if unicode.IsControl(r) {
+			errors = append(errors, NewValidationError(
+				field,
+				ValidationCodeInvalid,
+				"Reason contains invalid control characters",
+			))
+			break
+		}
+	}
+
+	return errors
+}
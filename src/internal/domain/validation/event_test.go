@@ -0,0 +1,480 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+)
+
+// validCancellationRequest returns a cancellation request that passes validation.
+func validCancellationRequest() *event.CancellationRequest {
+	return &event.CancellationRequest{
+		Author:     event.AuthorRequest{CNPJ: "11222333000181"},
+		ReasonCode: event.CancellationReasonEmissionError,
+		Reason:     "Erro no valor do servico informado",
+		Certificate: &emission.CertificateRequest{
+			PFXBase64: "dGVzdA==",
+			Password:  "secret",
+		},
+	}
+}
+
+func TestEventValidator_ValidateCancellation(t *testing.T) {
+	validator := NewEventValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(req *event.CancellationRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid request with CNPJ author",
+			modify:        func(req *event.CancellationRequest) {},
+			expectedCount: 0,
+		},
+		{
+			name: "valid request with CPF author",
+			modify: func(req *event.CancellationRequest) {
+				req.Author = event.AuthorRequest{CPF: "12345678909"}
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing author",
+			modify: func(req *event.CancellationRequest) {
+				req.Author = event.AuthorRequest{}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"author"},
+		},
+		{
+			name: "both CNPJ and CPF author",
+			modify: func(req *event.CancellationRequest) {
+				req.Author.CPF = "12345678909"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"author"},
+		},
+		{
+			name: "invalid author CNPJ",
+			modify: func(req *event.CancellationRequest) {
+				req.Author.CNPJ = "11111111111111"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"author.cnpj"},
+		},
+		{
+			name: "invalid author CPF",
+			modify: func(req *event.CancellationRequest) {
+				req.Author = event.AuthorRequest{CPF: "11111111111"}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"author.cpf"},
+		},
+		{
+			name: "missing reason code",
+			modify: func(req *event.CancellationRequest) {
+				req.ReasonCode = 0
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason_code"},
+		},
+		{
+			name: "invalid reason code",
+			modify: func(req *event.CancellationRequest) {
+				req.ReasonCode = 3
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason_code"},
+		},
+		{
+			name: "reason code other is accepted",
+			modify: func(req *event.CancellationRequest) {
+				req.ReasonCode = event.CancellationReasonOther
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing reason",
+			modify: func(req *event.CancellationRequest) {
+				req.Reason = "   "
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason"},
+		},
+		{
+			name: "reason too short",
+			modify: func(req *event.CancellationRequest) {
+				req.Reason = "Erro na nota"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason"},
+		},
+		{
+			name: "reason too long",
+			modify: func(req *event.CancellationRequest) {
+				req.Reason = strings.Repeat("a", 256)
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason"},
+		},
+		{
+			name: "reason length counts characters, not bytes",
+			modify: func(req *event.CancellationRequest) {
+				req.Reason = strings.Repeat("ç", 255)
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing certificate",
+			modify: func(req *event.CancellationRequest) {
+				req.Certificate = nil
+			},
+			expectedCount: 1,
+			checkFields:   []string{"certificate"},
+		},
+		{
+			name: "certificate without password",
+			modify: func(req *event.CancellationRequest) {
+				req.Certificate.Password = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"certificate.password"},
+		},
+		{
+			name: "webhook URL without HTTPS",
+			modify: func(req *event.CancellationRequest) {
+				req.WebhookURL = "http://example.com/hook"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"webhook_url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validCancellationRequest()
+			tt.modify(req)
+
+			errors := validator.ValidateCancellation(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+// validReplacementRequest returns a replacement request that passes validation.
+func validReplacementRequest() *event.ReplacementRequest {
+	return &event.ReplacementRequest{
+		EmissionRequest: emission.EmissionRequest{
+			Provider: emission.ProviderRequest{
+				CNPJ:      "11222333000181",
+				TaxRegime: "me_epp",
+				Name:      "Empresa Teste LTDA",
+			},
+			Service: emission.ServiceRequest{
+				NationalCode:     "010101",
+				Description:      "Servico de desenvolvimento de software",
+				MunicipalityCode: "3550308",
+			},
+			Values: emission.ValuesRequest{ServiceValue: 1000.00},
+			DPS:    emission.DPSRequest{Series: "00001", Number: "2"},
+			Certificate: &emission.CertificateRequest{
+				PFXBase64: "dGVzdA==",
+				Password:  "secret",
+			},
+		},
+		Substitution: event.SubstitutionRequest{
+			ReasonCode: event.SubstitutionReasonOther,
+			Reason:     "Correcao da descricao do servico",
+		},
+	}
+}
+
+func TestEventValidator_ValidateReplacement(t *testing.T) {
+	validator := NewEventValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(req *event.ReplacementRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid request",
+			modify:        func(req *event.ReplacementRequest) {},
+			expectedCount: 0,
+		},
+		{
+			name: "valid request without reason for coded motive",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution = event.SubstitutionRequest{ReasonCode: event.SubstitutionReasonTakerRejection}
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing certificate",
+			modify: func(req *event.ReplacementRequest) {
+				req.Certificate = nil
+			},
+			expectedCount: 1,
+			checkFields:   []string{"certificate"},
+		},
+		{
+			name: "missing reason code",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution.ReasonCode = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"substitution.reason_code"},
+		},
+		{
+			name: "invalid reason code",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution.ReasonCode = "06"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"substitution.reason_code"},
+		},
+		{
+			name: "missing reason for other motive",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution.Reason = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"substitution.reason"},
+		},
+		{
+			name: "reason too short",
+			modify: func(req *event.ReplacementRequest) {
+				req.Substitution = event.SubstitutionRequest{
+					ReasonCode: event.SubstitutionReasonSimplesExclusion,
+					Reason:     "curto",
+				}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"substitution.reason"},
+		},
+		{
+			name: "invalid emission payload",
+			modify: func(req *event.ReplacementRequest) {
+				req.Values.ServiceValue = 0
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.service_value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validReplacementRequest()
+			tt.modify(req)
+
+			errors := validator.ValidateReplacement(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+// validManifestationRequest returns a taker confirmation request that passes validation.
+func validManifestationRequest() *event.ManifestationRequest {
+	return &event.ManifestationRequest{
+		Role:   event.RoleTaker,
+		Action: event.ActionConfirm,
+		Author: event.AuthorRequest{CNPJ: "11222333000181"},
+		Certificate: &emission.CertificateRequest{
+			PFXBase64: "dGVzdA==",
+			Password:  "secret",
+		},
+	}
+}
+
+func TestEventValidator_ValidateManifestation(t *testing.T) {
+	validator := NewEventValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(req *event.ManifestationRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid taker confirmation",
+			modify:        func(req *event.ManifestationRequest) {},
+			expectedCount: 0,
+		},
+		{
+			name: "valid intermediary rejection",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = event.RoleIntermediary
+				req.Action = event.ActionReject
+				req.ReasonCode = event.RejectionReasonValues
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid rejection annulment",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = event.RoleMunicipality
+				req.Action = event.ActionAnnulRejection
+				req.Author = event.AuthorRequest{CPF: "52998224725"}
+				req.TaxAgentCPF = "52998224725"
+				req.RejectionEventID = strings.Repeat("1", 59)
+				req.Reason = "Rejeicao registrada indevidamente"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing role and action",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = ""
+				req.Action = ""
+			},
+			expectedCount: 2,
+			checkFields:   []string{"role", "action"},
+		},
+		{
+			name: "unknown role",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = "auditor"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"role"},
+		},
+		{
+			name: "taker may not annul a rejection",
+			modify: func(req *event.ManifestationRequest) {
+				req.Action = event.ActionAnnulRejection
+			},
+			expectedCount: 1,
+			checkFields:   []string{"action"},
+		},
+		{
+			name: "municipality may not confirm",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = event.RoleMunicipality
+			},
+			expectedCount: 1,
+			checkFields:   []string{"action"},
+		},
+		{
+			name: "rejection without reason code",
+			modify: func(req *event.ManifestationRequest) {
+				req.Action = event.ActionReject
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason_code"},
+		},
+		{
+			name: "rejection with invalid reason code",
+			modify: func(req *event.ManifestationRequest) {
+				req.Action = event.ActionReject
+				req.ReasonCode = 6
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason_code"},
+		},
+		{
+			name: "rejection for other reason without text",
+			modify: func(req *event.ManifestationRequest) {
+				req.Action = event.ActionReject
+				req.ReasonCode = event.RejectionReasonOther
+			},
+			expectedCount: 1,
+			checkFields:   []string{"reason"},
+		},
+		{
+			name: "annulment without agent, rejection ID and reason",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = event.RoleMunicipality
+				req.Action = event.ActionAnnulRejection
+			},
+			expectedCount: 3,
+			checkFields:   []string{"tax_agent_cpf", "rejection_event_id", "reason"},
+		},
+		{
+			name: "annulment with malformed rejection ID",
+			modify: func(req *event.ManifestationRequest) {
+				req.Role = event.RoleMunicipality
+				req.Action = event.ActionAnnulRejection
+				req.TaxAgentCPF = "52998224725"
+				req.RejectionEventID = "123"
+				req.Reason = "Rejeicao registrada indevidamente"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"rejection_event_id"},
+		},
+		{
+			name: "missing certificate",
+			modify: func(req *event.ManifestationRequest) {
+				req.Certificate = nil
+			},
+			expectedCount: 1,
+			checkFields:   []string{"certificate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validManifestationRequest()
+			tt.modify(req)
+
+			errors := validator.ValidateManifestation(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// pisCofinsCSTPattern matches the PIS/COFINS tax situation code (CST): "00" to "09".
+var pisCofinsCSTPattern = regexp.MustCompile(`^0\d$`)
+
+// FederalTaxRateMax is the maximum federal tax rate percentage (pAliqPis and pAliqCofins allow 2 integer digits).
+const FederalTaxRateMax = emission.MaxFederalTaxRate
+
+// validateFederalTaxes validates the federal taxes (tribFed) and checks that the
+// withheld taxes do not exceed the value paid by the taker.
+func (v *EmissionValidator) validateFederalTaxes(values *emission.ValuesRequest) []ValidationError {
+	var errors []ValidationError
+	taxes := values.FederalTaxes
+
+	if pc := taxes.PisCofins; pc != nil {
+		if pc.CST == "" {
+			errors = append(errors, NewValidationError(
+				"values.federal_taxes.pis_cofins.cst",
+				ValidationCodeRequired,
+				"PIS/COFINS tax situation code (CST) is required",
+			))
+		} else if !pisCofinsCSTPattern.MatchString(pc.CST) {
+			errors = append(errors, NewValidationError(
+				"values.federal_taxes.pis_cofins.cst",
+				ValidationCodeInvalid,
+				"PIS/COFINS tax situation code (CST) must be '00' to '09'",
+			))
+		}
+
+		if pc.TaxBase < 0 || !isValidMonetaryValue(pc.TaxBase) {
+			errors = append(errors, NewValidationError(
+				"values.federal_taxes.pis_cofins.tax_base",
+				ValidationCodeOutOfRange,
+				"PIS/COFINS tax base cannot be negative and must have at most 2 decimal places",
+			))
+		}
+
+		errors = append(errors, validateFederalTaxRate("values.federal_taxes.pis_cofins.pis_rate", "PIS", pc.PISRate)...)
+		errors = append(errors, validateFederalTaxRate("values.federal_taxes.pis_cofins.cofins_rate", "COFINS", pc.COFINSRate)...)
+	}
+
+	errors = append(errors, validateFederalTaxRate("values.federal_taxes.cp_rate", "CP", taxes.CPRate)...)
+	errors = append(errors, validateFederalTaxRate("values.federal_taxes.irrf_rate", "IRRF", taxes.IRRFRate)...)
+	errors = append(errors, validateFederalTaxRate("values.federal_taxes.csll_rate", "CSLL", taxes.CSLLRate)...)
+
+	// The taker cannot withhold more than it pays
+	if len(errors) == 0 {
+		payable := values.ServiceValue - values.UnconditionalDiscount - values.ConditionalDiscount
+		if withheld := values.CalculateTotalWithheld(); withheld > payable {
+			errors = append(errors, NewValidationError(
+				"values.federal_taxes",
+				ValidationCodeOutOfRange,
+				fmt.Sprintf("Withheld taxes (%.2f) cannot exceed the service value after discounts (%.2f)", withheld, payable),
+			))
+		}
+	}
+
+	return errors
+}
+
+// validateFederalTaxRate validates a federal tax rate percentage.
+func validateFederalTaxRate(field, label string, rate float64) []ValidationError {
+	if rate < 0 || rate > FederalTaxRateMax || !isValidMonetaryValue(rate) {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeOutOfRange,
+			fmt.Sprintf("%s rate must be between 0 and %.2f with at most 2 decimal places", label, FederalTaxRateMax),
+		)}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// testFederalTaxValues returns values of a service whose taker withholds federal taxes.
+func testFederalTaxValues() *emission.ValuesRequest {
+	return &emission.ValuesRequest{
+		ServiceValue: 10000.00,
+		FederalTaxes: &emission.FederalTaxesRequest{
+			PisCofins: &emission.PisCofinsRequest{
+				CST:        "01",
+				PISRate:    0.65,
+				COFINSRate: 3.00,
+				Withheld:   true,
+			},
+			IRRFRate: 1.50,
+			CSLLRate: 1.00,
+		},
+	}
+}
+
+func TestEmissionValidator_ValidateFederalTaxes(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(values *emission.ValuesRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid withholdings",
+			expectedCount: 0,
+		},
+		{
+			name: "valid withholdings without PIS/COFINS",
+			modify: func(values *emission.ValuesRequest) {
+				values.FederalTaxes.PisCofins = nil
+				values.FederalTaxes.CPRate = 11.00
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "missing CST",
+			modify: func(values *emission.ValuesRequest) {
+				values.FederalTaxes.PisCofins.CST = ""
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.federal_taxes.pis_cofins.cst"},
+		},
+		{
+			name: "invalid CST",
+			modify: func(values *emission.ValuesRequest) {
+				values.FederalTaxes.PisCofins.CST = "10"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.federal_taxes.pis_cofins.cst"},
+		},
+		{
+			name: "negative tax base",
+			modify: func(values *emission.ValuesRequest) {
+				values.FederalTaxes.PisCofins.TaxBase = -1
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.federal_taxes.pis_cofins.tax_base"},
+		},
+		{
+			name: "rates out of range",
+			modify: func(values *emission.ValuesRequest) {
+				values.FederalTaxes.PisCofins.PISRate = -0.65
+				values.FederalTaxes.PisCofins.COFINSRate = 100
+				values.FederalTaxes.CPRate = 11.005
+				values.FederalTaxes.IRRFRate = 150
+				values.FederalTaxes.CSLLRate = -1
+			},
+			expectedCount: 5,
+			checkFields: []string{
+				"values.federal_taxes.pis_cofins.pis_rate",
+				"values.federal_taxes.pis_cofins.cofins_rate",
+				"values.federal_taxes.cp_rate",
+				"values.federal_taxes.irrf_rate",
+				"values.federal_taxes.csll_rate",
+			},
+		},
+		{
+			name: "withholdings exceed value after discounts",
+			modify: func(values *emission.ValuesRequest) {
+				values.ConditionalDiscount = 5000.00
+				values.FederalTaxes.CPRate = 50.00
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.federal_taxes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := testFederalTaxValues()
+			if tt.modify != nil {
+				tt.modify(values)
+			}
+
+			errors := validator.validateFederalTaxes(values)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// currencyCodePattern matches the 3-digit BACEN currency code (tpMoeda).
+var currencyCodePattern = regexp.MustCompile(`^\d{3}$`)
+
+// Foreign trade validation constants.
+const (
+	// ServiceModeMax is the highest mode of supply (mdPrestacao).
+	ServiceModeMax = 4
+
+	// RelationshipMax is the highest relationship between the parties (vincPrest).
+	RelationshipMax = 6
+
+	// TemporaryGoodsMax is the highest temporary movement of goods option (movTempBens).
+	TemporaryGoodsMax = 3
+
+	// TemporaryGoodsImport links the operation to an import declaration (movTempBens 2).
+	TemporaryGoodsImport = 2
+
+	// TemporaryGoodsExport links the operation to an export declaration (movTempBens 3).
+	TemporaryGoodsExport = 3
+
+	// ForeignTradeDocumentMaxLength is the maximum length for the import declaration (nDI)
+	// and export registration (nRE) numbers.
+	ForeignTradeDocumentMaxLength = 12
+)
+
+// validProviderSupportMechanisms are the accepted provider support mechanisms (mecAFComexP).
+var validProviderSupportMechanisms = supportMechanisms(8)
+
+// validTakerSupportMechanisms are the accepted taker support mechanisms (mecAFComexT).
+var validTakerSupportMechanisms = supportMechanisms(26)
+
+// supportMechanisms returns the two-digit codes from "00" to max.
+func supportMechanisms(max int) map[string]bool {
+	codes := make(map[string]bool, max+1)
+	for i := 0; i <= max; i++ {
+		codes[fmt.Sprintf("%02d", i)] = true
+	}
+	return codes
+}
+
+// validateServiceCountry validates the country where the service was performed (cPaisPrestacao).
+func (v *EmissionValidator) validateServiceCountry(countryCode string) []ValidationError {
+	if countryCode == "" {
+		return nil
+	}
+
+	if !countryCodePattern.MatchString(countryCode) {
+		return []ValidationError{NewValidationError(
+			"service.country_code",
+			ValidationCodeInvalidFormat,
+			"Service country code must be exactly 2 uppercase letters (ISO 3166-1 alpha-2)",
+		)}
+	}
+	if countryCode == "BR" {
+		return []ValidationError{NewValidationError(
+			"service.country_code",
+			ValidationCodeInvalid,
+			"Service country code is only for services performed abroad; use municipality_code for Brazil",
+		)}
+	}
+	return nil
+}
+
+// validateForeignTrade validates the foreign trade group (comExt) of service exports and imports.
+func (v *EmissionValidator) validateForeignTrade(trade *emission.ForeignTradeRequest) []ValidationError {
+	var errors []ValidationError
+
+	if trade.ServiceMode < 0 || trade.ServiceMode > ServiceModeMax {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.service_mode",
+			ValidationCodeOutOfRange,
+			"Service mode (mdPrestacao) must be between 0 and 4",
+		))
+	}
+
+	if trade.Relationship < 0 || trade.Relationship > RelationshipMax {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.relationship",
+			ValidationCodeOutOfRange,
+			"Relationship (vincPrest) must be between 0 and 6",
+		))
+	}
+
+	// Validate currency and the value expressed in it
+	if trade.CurrencyCode == "" {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.currency_code",
+			ValidationCodeRequired,
+			"Currency code (tpMoeda) is required",
+		))
+	} else if !currencyCodePattern.MatchString(trade.CurrencyCode) {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.currency_code",
+			ValidationCodeInvalidFormat,
+			"Currency code (tpMoeda) must be the 3-digit BACEN code",
+		))
+	}
+
+	if trade.ForeignValue <= 0 {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.foreign_value",
+			ValidationCodeOutOfRange,
+			"Foreign currency value (vServMoeda) must be greater than zero",
+		))
+	} else if !isValidMonetaryValue(trade.ForeignValue) {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.foreign_value",
+			ValidationCodeInvalid,
+			"Foreign currency value (vServMoeda) must have at most 2 decimal places",
+		))
+	}
+
+	// Validate support mechanisms
+	errors = append(errors, validateSupportMechanism(
+		trade.ProviderSupportMechanism,
+		"service.foreign_trade.provider_support_mechanism",
+		"Provider support mechanism (mecAFComexP)",
+		validProviderSupportMechanisms,
+		"08",
+	)...)
+	errors = append(errors, validateSupportMechanism(
+		trade.TakerSupportMechanism,
+		"service.foreign_trade.taker_support_mechanism",
+		"Taker support mechanism (mecAFComexT)",
+		validTakerSupportMechanisms,
+		"26",
+	)...)
+
+	// Validate temporary movement of goods and its declarations
+	if trade.TemporaryGoods < 0 || trade.TemporaryGoods > TemporaryGoodsMax {
+		errors = append(errors, NewValidationError(
+			"service.foreign_trade.temporary_goods",
+			ValidationCodeOutOfRange,
+			"Temporary goods movement (movTempBens) must be between 0 and 3",
+		))
+	}
+	errors = append(errors, validateForeignTradeDocument(
+		trade.ImportDeclaration,
+		trade.TemporaryGoods == TemporaryGoodsImport,
+		"service.foreign_trade.import_declaration",
+		"Import declaration (nDI)",
+	)...)
+	errors = append(errors, validateForeignTradeDocument(
+		trade.ExportRegistration,
+		trade.TemporaryGoods == TemporaryGoodsExport,
+		"service.foreign_trade.export_registration",
+		"Export registration (nRE)",
+	)...)
+
+	return errors
+}
+
+// validateSupportMechanism validates a required foreign trade support mechanism code.
+func validateSupportMechanism(code, field, label string, valid map[string]bool, max string) []ValidationError {
+	if code == "" {
+		return []ValidationError{NewValidationError(field, ValidationCodeRequired, fmt.Sprintf("%s is required", label))}
+	}
+	if !valid[code] {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeInvalid,
+			fmt.Sprintf("%s must be a two-digit code from 00 to %s", label, max),
+		)}
+	}
+	return nil
+}
+
+// validateForeignTradeDocument validates an import declaration or export registration
+// number, required when the operation is linked to it.
+func validateForeignTradeDocument(number string, required bool, field, label string) []ValidationError {
+	if number == "" {
+		if required {
+			return []ValidationError{NewValidationError(
+				field,
+				ValidationCodeRequired,
+				fmt.Sprintf("%s is required for the informed temporary goods movement", label),
+			)}
+		}
+		return nil
+	}
+	if len(number) > ForeignTradeDocumentMaxLength {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeTooLong,
+			fmt.Sprintf("%s must not exceed %d characters", label, ForeignTradeDocumentMaxLength),
+		)}
+	}
+	return nil
+}
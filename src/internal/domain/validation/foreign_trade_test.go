@@ -0,0 +1,175 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// testForeignTrade returns the foreign trade group of a cross-border software export.
+func testForeignTrade() *emission.ForeignTradeRequest {
+	return &emission.ForeignTradeRequest{
+		ServiceMode:              1,
+		Relationship:             0,
+		CurrencyCode:             "220",
+		ForeignValue:             2500.00,
+		ProviderSupportMechanism: "01",
+		TakerSupportMechanism:    "01",
+		TemporaryGoods:           1,
+		ShareWithMDIC:            true,
+	}
+}
+
+func TestEmissionValidator_ValidateForeignTrade(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(trade *emission.ForeignTradeRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "valid service export",
+			expectedCount: 0,
+		},
+		{
+			name: "valid operation linked to an export declaration",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.TemporaryGoods = TemporaryGoodsExport
+				trade.ExportRegistration = "250012345678"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "codes out of range",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.ServiceMode = 5
+				trade.Relationship = 7
+				trade.TemporaryGoods = -1
+			},
+			expectedCount: 3,
+			checkFields: []string{
+				"service.foreign_trade.service_mode",
+				"service.foreign_trade.relationship",
+				"service.foreign_trade.temporary_goods",
+			},
+		},
+		{
+			name: "invalid currency and value",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.CurrencyCode = "USD"
+				trade.ForeignValue = 10.001
+			},
+			expectedCount: 2,
+			checkFields:   []string{"service.foreign_trade.currency_code", "service.foreign_trade.foreign_value"},
+		},
+		{
+			name: "missing currency and value",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.CurrencyCode = ""
+				trade.ForeignValue = 0
+			},
+			expectedCount: 2,
+			checkFields:   []string{"service.foreign_trade.currency_code", "service.foreign_trade.foreign_value"},
+		},
+		{
+			name: "invalid support mechanisms",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.ProviderSupportMechanism = "09"
+				trade.TakerSupportMechanism = "1"
+			},
+			expectedCount: 2,
+			checkFields: []string{
+				"service.foreign_trade.provider_support_mechanism",
+				"service.foreign_trade.taker_support_mechanism",
+			},
+		},
+		{
+			name: "missing support mechanisms",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.ProviderSupportMechanism = ""
+				trade.TakerSupportMechanism = ""
+			},
+			expectedCount: 2,
+			checkFields: []string{
+				"service.foreign_trade.provider_support_mechanism",
+				"service.foreign_trade.taker_support_mechanism",
+			},
+		},
+		{
+			name: "import declaration required",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.TemporaryGoods = TemporaryGoodsImport
+			},
+			expectedCount: 1,
+			checkFields:   []string{"service.foreign_trade.import_declaration"},
+		},
+		{
+			name: "export registration too long",
+			modify: func(trade *emission.ForeignTradeRequest) {
+				trade.ExportRegistration = "1234567890123"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"service.foreign_trade.export_registration"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade := testForeignTrade()
+			if tt.modify != nil {
+				tt.modify(trade)
+			}
+
+			errors := validator.validateForeignTrade(trade)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+func TestEmissionValidator_ValidateServiceCountry(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		countryCode  string
+		expectedCode string // empty when no error is expected
+	}{
+		{"", ""},
+		{"US", ""},
+		{"us", ValidationCodeInvalidFormat},
+		{"USA", ValidationCodeInvalidFormat},
+		{"BR", ValidationCodeInvalid},
+	}
+
+	for _, tt := range tests {
+		errors := validator.validateServiceCountry(tt.countryCode)
+		if tt.expectedCode == "" {
+			if len(errors) != 0 {
+				t.Errorf("validateServiceCountry(%q) returned errors: %+v", tt.countryCode, errors)
+			}
+			continue
+		}
+		if len(errors) != 1 || errors[0].Code != tt.expectedCode || errors[0].Field != "service.country_code" {
+			t.Errorf("validateServiceCountry(%q) = %+v, want one %s error", tt.countryCode, errors, tt.expectedCode)
+		}
+	}
+}
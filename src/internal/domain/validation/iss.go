@@ -0,0 +1,243 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// suspensionProcessPattern matches the 30-digit proceeding number of a suspended exigibility (nProcesso).
+var suspensionProcessPattern = regexp.MustCompile(`^\d{30}$`)
+
+// ISS validation constants.
+const (
+	// ImmunityTypeMin is the lowest immunity type accepted in emissions (tpImunidade).
+	// Type 0 (not informed) is reserved for notes imported from other systems.
+	ImmunityTypeMin = 1
+
+	// ImmunityTypeMax is the highest immunity type (tpImunidade).
+	ImmunityTypeMax = 5
+)
+
+// validateISS validates the municipal tax (ISSQN) treatment of the service and the
+// municipal benefit reduction. The withholding must name a party present in the request.
+func (v *EmissionValidator) validateISS(req *emission.EmissionRequest) []ValidationError {
+	var errors []ValidationError
+	iss := req.Values.ISS
+	taxation := iss.TaxationOrDefault()
+
+	if iss != nil {
+		if iss.Taxation < 0 || iss.Taxation > emission.ISSTaxationNonIncidence {
+			errors = append(errors, NewValidationError(
+				"values.iss.taxation",
+				ValidationCodeInvalid,
+				"ISS taxation (tribISSQN) must be 1 (taxable), 2 (immunity), 3 (export) or 4 (non-incidence)",
+			))
+		}
+
+		errors = append(errors, validateResultCountry(iss, taxation)...)
+		errors = append(errors, validateImmunityType(iss, taxation)...)
+
+		if iss.Suspension != nil {
+			errors = append(errors, validateISSSuspension(iss.Suspension, taxation)...)
+		}
+
+		errors = append(errors, validateISSWithholding(req, taxation)...)
+	}
+
+	if req.Values.MunicipalBenefit != nil {
+		errors = append(errors, validateBenefitReduction(&req.Values, taxation)...)
+	}
+
+	return errors
+}
+
+// validateResultCountry validates the country of the result of an exported service (cPaisResult).
+func validateResultCountry(iss *emission.ISSRequest, taxation int) []ValidationError {
+	if taxation != emission.ISSTaxationExport {
+		if iss.ResultCountryCode != "" {
+			return []ValidationError{NewValidationError(
+				"values.iss.result_country_code",
+				ValidationCodeInvalid,
+				"Result country (cPaisResult) is only allowed for service exports (taxation 3)",
+			)}
+		}
+		return nil
+	}
+
+	if iss.ResultCountryCode == "" {
+		return []ValidationError{NewValidationError(
+			"values.iss.result_country_code",
+			ValidationCodeRequired,
+			"Result country (cPaisResult) is required for service exports",
+		)}
+	}
+	if !countryCodePattern.MatchString(iss.ResultCountryCode) {
+		return []ValidationError{NewValidationError(
+			"values.iss.result_country_code",
+			ValidationCodeInvalidFormat,
+			"Result country must be a 2-letter ISO 3166-1 code in uppercase",
+		)}
+	}
+	if iss.ResultCountryCode == "BR" {
+		return []ValidationError{NewValidationError(
+			"values.iss.result_country_code",
+			ValidationCodeInvalid,
+			"Result country of a service export cannot be Brazil",
+		)}
+	}
+
+	return nil
+}
+
+// validateImmunityType validates the constitutional immunity (tpImunidade).
+func validateImmunityType(iss *emission.ISSRequest, taxation int) []ValidationError {
+	if taxation != emission.ISSTaxationImmunity {
+		if iss.ImmunityType != 0 {
+			return []ValidationError{NewValidationError(
+				"values.iss.immunity_type",
+				ValidationCodeInvalid,
+				"Immunity type (tpImunidade) is only allowed for immune operations (taxation 2)",
+			)}
+		}
+		return nil
+	}
+
+	if iss.ImmunityType == 0 {
+		return []ValidationError{NewValidationError(
+			"values.iss.immunity_type",
+			ValidationCodeRequired,
+			"Immunity type (tpImunidade) is required for immune operations",
+		)}
+	}
+	if iss.ImmunityType < ImmunityTypeMin || iss.ImmunityType > ImmunityTypeMax {
+		return []ValidationError{NewValidationError(
+			"values.iss.immunity_type",
+			ValidationCodeInvalid,
+			fmt.Sprintf("Immunity type (tpImunidade) must be %d to %d", ImmunityTypeMin, ImmunityTypeMax),
+		)}
+	}
+
+	return nil
+}
+
+// validateISSSuspension validates the suspended exigibility of the ISS (exigSusp).
+func validateISSSuspension(suspension *emission.ISSSuspensionRequest, taxation int) []ValidationError {
+	var errors []ValidationError
+
+	if taxation != emission.ISSTaxationTaxable {
+		errors = append(errors, NewValidationError(
+			"values.iss.suspension",
+			ValidationCodeInvalid,
+			"Suspended exigibility (exigSusp) is only allowed for taxable operations",
+		))
+	}
+
+	if suspension.Type != emission.ISSSuspensionJudicial && suspension.Type != emission.ISSSuspensionAdministrative {
+		errors = append(errors, NewValidationError(
+			"values.iss.suspension.type",
+			ValidationCodeInvalid,
+			"Suspension type (tpSusp) must be 1 (court decision) or 2 (administrative proceeding)",
+		))
+	}
+
+	if !suspensionProcessPattern.MatchString(suspension.ProcessNumber) {
+		errors = append(errors, NewValidationError(
+			"values.iss.suspension.process_number",
+			ValidationCodeInvalidFormat,
+			"Process number (nProcesso) must be exactly 30 digits",
+		))
+	}
+
+	return errors
+}
+
+// validateISSWithholding validates the ISS withholding (tpRetISSQN). Only taxable
+// operations are withheld, by a taker or intermediary present in the request.
+func validateISSWithholding(req *emission.EmissionRequest, taxation int) []ValidationError {
+	withholding := req.Values.ISS.Withholding
+
+	switch withholding {
+	case 0, emission.ISSWithholdingNone:
+		return nil
+	case emission.ISSWithholdingTaker, emission.ISSWithholdingIntermediary:
+	default:
+		return []ValidationError{NewValidationError(
+			"values.iss.withholding",
+			ValidationCodeInvalid,
+			"ISS withholding (tpRetISSQN) must be 1 (not withheld), 2 (taker) or 3 (intermediary)",
+		)}
+	}
+
+	if taxation != emission.ISSTaxationTaxable {
+		return []ValidationError{NewValidationError(
+			"values.iss.withholding",
+			ValidationCodeInvalid,
+			"ISS can only be withheld in taxable operations",
+		)}
+	}
+	if withholding == emission.ISSWithholdingTaker && req.Taker == nil {
+		return []ValidationError{NewValidationError(
+			"values.iss.withholding",
+			ValidationCodeInvalid,
+			"ISS withheld by the taker requires a taker",
+		)}
+	}
+	if withholding == emission.ISSWithholdingIntermediary && req.Intermediary == nil {
+		return []ValidationError{NewValidationError(
+			"values.iss.withholding",
+			ValidationCodeInvalid,
+			"ISS withheld by the intermediary requires an intermediary",
+		)}
+	}
+
+	return nil
+}
+
+// validateBenefitReduction validates the tax base reduction of the municipal benefit
+// (vRedBCBM or pRedBCBM). Benefits only apply to taxable operations.
+func validateBenefitReduction(values *emission.ValuesRequest, taxation int) []ValidationError {
+	var errors []ValidationError
+	benefit := values.MunicipalBenefit
+
+	if taxation != emission.ISSTaxationTaxable {
+		errors = append(errors, NewValidationError(
+			"values.municipal_benefit",
+			ValidationCodeInvalid,
+			"Municipal benefit (BM) is only allowed for taxable operations",
+		))
+	}
+
+	if benefit.ReductionPercentage != 0 && benefit.ReductionValue != 0 {
+		return append(errors, NewValidationError(
+			"values.municipal_benefit",
+			ValidationCodeInvalid,
+			"Reduction percentage and reduction value are mutually exclusive",
+		))
+	}
+
+	if benefit.ReductionPercentage < 0 || benefit.ReductionPercentage > 100 || !isValidMonetaryValue(benefit.ReductionPercentage) {
+		errors = append(errors, NewValidationError(
+			"values.municipal_benefit.reduction_percentage",
+			ValidationCodeOutOfRange,
+			"Reduction percentage must be between 0 and 100 with at most 2 decimal places",
+		))
+	}
+
+	if benefit.ReductionValue < 0 || !isValidMonetaryValue(benefit.ReductionValue) {
+		errors = append(errors, NewValidationError(
+			"values.municipal_benefit.reduction_value",
+			ValidationCodeOutOfRange,
+			"Reduction value cannot be negative and must have at most 2 decimal places",
+		))
+	} else if benefit.ReductionValue > values.CalculateTaxBase() {
+		errors = append(errors, NewValidationError(
+			"values.municipal_benefit.reduction_value",
+			ValidationCodeOutOfRange,
+			"Reduction value cannot exceed the tax base",
+		))
+	}
+
+	return errors
+}
@@ -0,0 +1,254 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// testISSProcessNumber is a valid 30-digit proceeding number.
+const testISSProcessNumber = "000123456789012345678901234567"
+
+func TestEmissionValidator_ValidateISS(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		modify        func(req *emission.EmissionRequest)
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "defaults without ISS group",
+			modify:        func(req *emission.EmissionRequest) { req.Values.ISS = nil },
+			expectedCount: 0,
+		},
+		{
+			name: "valid withholding by the taker",
+			modify: func(req *emission.EmissionRequest) {
+				req.Taker = &emission.TakerRequest{CNPJ: "11222333000181", Name: "Prefeitura"}
+				req.Values.ISS.Withholding = emission.ISSWithholdingTaker
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid immunity",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationImmunity
+				req.Values.ISS.ImmunityType = 3
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid export",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationExport
+				req.Values.ISS.ResultCountryCode = "US"
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid suspension",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Suspension = &emission.ISSSuspensionRequest{
+					Type:          emission.ISSSuspensionJudicial,
+					ProcessNumber: testISSProcessNumber,
+				}
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid benefit with reduction percentage",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{
+					Number:              testBenefitNumber,
+					ReductionPercentage: 40,
+				}
+			},
+			expectedCount: 0,
+		},
+		{
+			name:          "invalid taxation and withholding",
+			modify:        func(req *emission.EmissionRequest) { req.Values.ISS.Taxation = 5; req.Values.ISS.Withholding = 4 },
+			expectedCount: 2,
+			checkFields:   []string{"values.iss.taxation", "values.iss.withholding"},
+		},
+		{
+			name: "export without result country",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationExport
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.result_country_code"},
+		},
+		{
+			name: "export to Brazil",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationExport
+				req.Values.ISS.ResultCountryCode = "BR"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.result_country_code"},
+		},
+		{
+			name: "result country outside exports",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.ResultCountryCode = "US"
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.result_country_code"},
+		},
+		{
+			name: "immunity without type",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationImmunity
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.immunity_type"},
+		},
+		{
+			name: "immunity type out of range",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationImmunity
+				req.Values.ISS.ImmunityType = 6
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.immunity_type"},
+		},
+		{
+			name: "immunity type outside immunity",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.ImmunityType = 1
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.immunity_type"},
+		},
+		{
+			name: "invalid suspension",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Suspension = &emission.ISSSuspensionRequest{Type: 3, ProcessNumber: "123"}
+			},
+			expectedCount: 2,
+			checkFields:   []string{"values.iss.suspension.type", "values.iss.suspension.process_number"},
+		},
+		{
+			name: "suspension of non-incidence",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationNonIncidence
+				req.Values.ISS.Suspension = &emission.ISSSuspensionRequest{
+					Type:          emission.ISSSuspensionAdministrative,
+					ProcessNumber: testISSProcessNumber,
+				}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.suspension"},
+		},
+		{
+			name: "withholding by missing taker",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Withholding = emission.ISSWithholdingTaker
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.withholding"},
+		},
+		{
+			name: "withholding by missing intermediary",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Withholding = emission.ISSWithholdingIntermediary
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.withholding"},
+		},
+		{
+			name: "withholding of an export",
+			modify: func(req *emission.EmissionRequest) {
+				req.Taker = &emission.TakerRequest{NIF: "123456789", Name: "Foreign Inc"}
+				req.Values.ISS.Taxation = emission.ISSTaxationExport
+				req.Values.ISS.ResultCountryCode = "US"
+				req.Values.ISS.Withholding = emission.ISSWithholdingTaker
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.iss.withholding"},
+		},
+		{
+			name: "both benefit reductions",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{
+					Number:              testBenefitNumber,
+					ReductionPercentage: 10,
+					ReductionValue:      100,
+				}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit"},
+		},
+		{
+			name: "benefit reductions out of range",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.Deductions = 500
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{
+					Number:         testBenefitNumber,
+					ReductionValue: 600,
+				}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit.reduction_value"},
+		},
+		{
+			name: "benefit reduction percentage above 100",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{
+					Number:              testBenefitNumber,
+					ReductionPercentage: 100.01,
+				}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit.reduction_percentage"},
+		},
+		{
+			name: "benefit on immune operation",
+			modify: func(req *emission.EmissionRequest) {
+				req.Values.ISS.Taxation = emission.ISSTaxationImmunity
+				req.Values.ISS.ImmunityType = 3
+				req.Values.MunicipalBenefit = &emission.MunicipalBenefitRequest{Number: testBenefitNumber}
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.municipal_benefit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &emission.EmissionRequest{
+				Values: emission.ValuesRequest{
+					ServiceValue: 1000.00,
+					ISS:          &emission.ISSRequest{},
+				},
+			}
+			if tt.modify != nil {
+				tt.modify(req)
+			}
+
+			errors := validator.validateISS(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
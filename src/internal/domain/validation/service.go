@@ -0,0 +1,312 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/pkg/nbs"
+)
+
+// Service site validation constants.
+const (
+	// ConstructionCodeMaxLength is the maximum length for the construction code (cObra).
+	ConstructionCodeMaxLength = 30
+
+	// PropertyRegistrationMaxLength is the maximum length for the property registration (inscImobFisc).
+	PropertyRegistrationMaxLength = 30
+
+	// EventNameMaxLength is the maximum length for the event description (xNome).
+	EventNameMaxLength = 255
+
+	// EventIDMaxLength is the maximum length for the municipal event identification (idAtvEvt).
+	EventIDMaxLength = 30
+)
+
+// constructionServiceItems are the LC 116 subitems (first 4 digits of cTribNac)
+// of civil construction services, which must inform the construction site (obra).
+var constructionServiceItems = map[string]bool{
+	"0702": true, // Execution of civil construction works
+	"0704": true, // Demolition
+	"0705": true, // Repair, maintenance and renovation of buildings and roads
+	"0706": true, // Installation of carpets, floors and coverings
+	"0707": true, // Floor recovery and polishing
+	"0708": true, // Caulking
+	"0717": true, // Shoring and slope containment
+	"0719": true, // Supervision of engineering works
+}
+
+// eventServiceItems are the LC 116 items or subitems (prefix of cTribNac) of
+// event services, which must inform the event (atvEvento).
+var eventServiceItems = []string{
+	"12",   // Entertainment, leisure, amusement and similar events
+	"1710", // Organization of fairs, exhibitions and congresses
+}
+
+// RequiresConstruction reports whether services with the given national code
+// (cTribNac) must inform the construction site (obra group).
+func RequiresConstruction(nationalCode string) bool {
+	return len(nationalCode) >= 4 && constructionServiceItems[nationalCode[:4]]
+}
+
+// RequiresEvent reports whether services with the given national code
+// (cTribNac) must inform the event (atvEvento group).
+func RequiresEvent(nationalCode string) bool {
+	for _, prefix := range eventServiceItems {
+		if strings.HasPrefix(nationalCode, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateServiceSite validates the construction site and event groups of the service,
+// requiring them for the service codes that need them.
+func (v *EmissionValidator) validateServiceSite(service *emission.ServiceRequest) []ValidationError {
+	var errors []ValidationError
+
+	if service.Construction != nil {
+		errors = append(errors, v.validateConstruction(service.Construction)...)
+	} else if RequiresConstruction(service.NationalCode) {
+		errors = append(errors, NewValidationError(
+			"service.construction",
+			ValidationCodeRequired,
+			fmt.Sprintf("Construction site (obra) is required for civil construction service %s", service.NationalCode),
+		))
+	}
+
+	if service.Event != nil {
+		errors = append(errors, v.validateEvent(service.Event)...)
+	} else if RequiresEvent(service.NationalCode) {
+		errors = append(errors, NewValidationError(
+			"service.event",
+			ValidationCodeRequired,
+			fmt.Sprintf("Event (atvEvento) is required for event service %s", service.NationalCode),
+		))
+	}
+
+	return errors
+}
+
+// validateServiceCodes validates the NBS code (cNBS), which must be in the national
+// NBS list and is required for service exports and imports, and the municipal tax
+// code (cTribMun). The municipal codes are defined by each municipality, so only
+// their format is checked.
+func (v *EmissionValidator) validateServiceCodes(req *emission.EmissionRequest) []ValidationError {
+	var errors []ValidationError
+	service := &req.Service
+
+	if service.NBSCode != "" {
+		if code := nbs.Clean(service.NBSCode); len(code) != nbs.CodeLength {
+			errors = append(errors, NewValidationError(
+				"service.nbs_code",
+				ValidationCodeInvalidFormat,
+				"Service NBS code (cNBS) must have exactly 9 digits",
+			))
+		} else if !nbs.IsValid(code) {
+			errors = append(errors, NewValidationError(
+				"service.nbs_code",
+				ValidationCodeInvalid,
+				fmt.Sprintf("Service NBS code %s is not in the national NBS list", service.NBSCode),
+			))
+		}
+	} else if req.EmitterTypeOrDefault() == emission.EmitterProvider && isServiceExport(req) {
+		errors = append(errors, NewValidationError(
+			"service.nbs_code",
+			ValidationCodeRequired,
+			"Service NBS code (cNBS) is required for service exports, with a foreign taker, intermediary or country of service",
+		))
+	} else if req.EmitterTypeOrDefault() != emission.EmitterProvider && isServiceImport(req) {
+		errors = append(errors, NewValidationError(
+			"service.nbs_code",
+			ValidationCodeRequired,
+			"Service NBS code (cNBS) is required for service imports, with a service performed abroad",
+		))
+	}
+
+	if service.MunicipalTaxCode != "" && !municipalTaxCodePattern.MatchString(service.MunicipalTaxCode) {
+		errors = append(errors, NewValidationError(
+			"service.municipal_tax_code",
+			ValidationCodeInvalidFormat,
+			"Service municipal tax code (cTribMun) must be exactly 3 digits",
+		))
+	}
+
+	return errors
+}
+
+// isServiceExport reports whether a DPS emitted by the provider is treated by SEFIN as
+// a service export (E0318): the taker or intermediary is abroad, or the service was
+// performed abroad (cPaisPrestacao).
+func isServiceExport(req *emission.EmissionRequest) bool {
+	if req.Service.CountryCode != "" {
+		return true
+	}
+	if req.Taker != nil && req.Taker.Address.IsForeign() {
+		return true
+	}
+	return req.Intermediary != nil && req.Intermediary.Address.IsForeign()
+}
+
+// isServiceImport reports whether a DPS emitted by the taker or intermediary is
+// treated by SEFIN as a service import (E0320): the service was performed abroad
+// (cPaisPrestacao). Providers are identified by CNPJ or CPF and have a national
+// address, so the provider abroad case does not arise.
+func isServiceImport(req *emission.EmissionRequest) bool {
+	return req.Service.CountryCode != ""
+}
+
+// validateConstruction validates the construction site (obra group).
+// The site must be identified by exactly one of its code (cObra) or address.
+func (v *EmissionValidator) validateConstruction(construction *emission.ConstructionRequest) []ValidationError {
+	var errors []ValidationError
+
+	code := strings.TrimSpace(construction.Code)
+	switch {
+	case code == "" && construction.Address == nil:
+		errors = append(errors, NewValidationError(
+			"service.construction",
+			ValidationCodeRequired,
+			"Construction site must have a code (cObra) or an address",
+		))
+	case code != "" && construction.Address != nil:
+		errors = append(errors, NewValidationError(
+			"service.construction",
+			ValidationCodeInvalid,
+			"Construction site must have only one of code (cObra) or address (they are mutually exclusive)",
+		))
+	case len(code) > ConstructionCodeMaxLength:
+		errors = append(errors, NewValidationError(
+			"service.construction.code",
+			ValidationCodeTooLong,
+			"Construction code (cObra) must not exceed 30 characters",
+		))
+	case construction.Address != nil:
+		errors = append(errors, v.validateSiteAddress("service.construction.address", construction.Address)...)
+	}
+
+	if len(construction.PropertyRegistration) > PropertyRegistrationMaxLength {
+		errors = append(errors, NewValidationError(
+			"service.construction.property_registration",
+			ValidationCodeTooLong,
+			"Property registration (inscImobFisc) must not exceed 30 characters",
+		))
+	}
+
+	return errors
+}
+
+// validateEvent validates the event (atvEvento group).
+// The event must be identified by exactly one of its municipal ID (idAtvEvt) or address.
+func (v *EmissionValidator) validateEvent(event *emission.EventRequest) []ValidationError {
+	var errors []ValidationError
+
+	name := strings.TrimSpace(event.Name)
+	if name == "" {
+		errors = append(errors, NewValidationError(
+			"service.event.name",
+			ValidationCodeRequired,
+			"Event name is required",
+		))
+	} else if len(name) > EventNameMaxLength {
+		errors = append(errors, NewValidationError(
+			"service.event.name",
+			ValidationCodeTooLong,
+			"Event name must not exceed 255 characters",
+		))
+	}
+
+	start, startErrors := parseEventDate("service.event.start_date", event.StartDate)
+	errors = append(errors, startErrors...)
+	end, endErrors := parseEventDate("service.event.end_date", event.EndDate)
+	errors = append(errors, endErrors...)
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		errors = append(errors, NewValidationError(
+			"service.event.end_date",
+			ValidationCodeOutOfRange,
+			"Event end date must not be before its start date",
+		))
+	}
+
+	id := strings.TrimSpace(event.ID)
+	switch {
+	case id == "" && event.Address == nil:
+		errors = append(errors, NewValidationError(
+			"service.event",
+			ValidationCodeRequired,
+			"Event must have an identification (idAtvEvt) or an address",
+		))
+	case id != "" && event.Address != nil:
+		errors = append(errors, NewValidationError(
+			"service.event",
+			ValidationCodeInvalid,
+			"Event must have only one of identification (idAtvEvt) or address (they are mutually exclusive)",
+		))
+	case len(id) > EventIDMaxLength:
+		errors = append(errors, NewValidationError(
+			"service.event.id",
+			ValidationCodeTooLong,
+			"Event identification (idAtvEvt) must not exceed 30 characters",
+		))
+	case event.Address != nil:
+		errors = append(errors, v.validateSiteAddress("service.event.address", event.Address)...)
+	}
+
+	return errors
+}
+
+// parseEventDate parses a required event date in YYYY-MM-DD format.
+// Returns the zero time along with the validation errors when the date is missing or invalid.
+func parseEventDate(field, value string) (time.Time, []ValidationError) {
+	if value == "" {
+		return time.Time{}, []ValidationError{NewValidationError(field, ValidationCodeRequired, "Event date is required")}
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, []ValidationError{NewValidationError(field, ValidationCodeInvalidFormat, "Event date must be in YYYY-MM-DD format")}
+	}
+	return date, nil
+}
+
+// validateSiteAddress validates the address of a construction site or event
+// (TCEnderObraEvento). National sites are identified by the CEP only; foreign
+// sites by the postal code, city and region.
+func (v *EmissionValidator) validateSiteAddress(field string, addr *emission.AddressRequest) []ValidationError {
+	var errors []ValidationError
+
+	// Validate common fields
+	errors = append(errors, v.takerValidator.validateAddressCommonFields(field, addr)...)
+
+	if addr.CountryCode != "" && addr.CountryCode != "BR" {
+		if !countryCodePattern.MatchString(addr.CountryCode) {
+			errors = append(errors, NewValidationError(
+				field+".country_code",
+				ValidationCodeInvalidFormat,
+				"Country code must be exactly 2 uppercase letters (ISO 3166-1 alpha-2)",
+			))
+		}
+		errors = append(errors, validateForeignAddressField(addr.PostalCode, field+".postal_code", "Postal code", ForeignPostalCodeMaxLength)...)
+		errors = append(errors, validateForeignAddressField(addr.City, field+".city", "City", AddressCityMaxLength)...)
+		errors = append(errors, validateForeignAddressField(addr.Region, field+".region", "State, province or region", AddressRegionMaxLength)...)
+		return errors
+	}
+
+	// Postal code is required (8 digits CEP)
+	if addr.PostalCode == "" {
+		errors = append(errors, NewValidationError(
+			field+".postal_code",
+			ValidationCodeRequired,
+			"Postal code (CEP) is required for Brazilian addresses",
+		))
+	} else if !postalCodePattern.MatchString(strings.ReplaceAll(addr.PostalCode, "-", "")) {
+		errors = append(errors, NewValidationError(
+			field+".postal_code",
+			ValidationCodeInvalidFormat,
+			"Postal code (CEP) must be exactly 8 digits",
+		))
+	}
+
+	return errors
+}
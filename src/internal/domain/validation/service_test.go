@@ -0,0 +1,326 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// testSiteAddress returns a national construction site or event address.
+func testSiteAddress() *emission.AddressRequest {
+	return &emission.AddressRequest{
+		Street:       "Rua das Obras",
+		Number:       "100",
+		Neighborhood: "Centro",
+		PostalCode:   "01310-100",
+	}
+}
+
+func TestEmissionValidator_ValidateServiceSite(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name          string
+		service       emission.ServiceRequest
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "service without site requirements",
+			service:       emission.ServiceRequest{NationalCode: "010101"},
+			expectedCount: 0,
+		},
+		{
+			name:          "construction service without construction site",
+			service:       emission.ServiceRequest{NationalCode: "070201"},
+			expectedCount: 1,
+			checkFields:   []string{"service.construction"},
+		},
+		{
+			name: "construction service with construction code",
+			service: emission.ServiceRequest{
+				NationalCode: "070201",
+				Construction: &emission.ConstructionRequest{Code: "900012345678", PropertyRegistration: "0123456789"},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "construction service with national site address",
+			service: emission.ServiceRequest{
+				NationalCode: "070501",
+				Construction: &emission.ConstructionRequest{Address: testSiteAddress()},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "construction site with code and address",
+			service: emission.ServiceRequest{
+				NationalCode: "070201",
+				Construction: &emission.ConstructionRequest{Code: "900012345678", Address: testSiteAddress()},
+			},
+			expectedCount: 1,
+			checkFields:   []string{"service.construction"},
+		},
+		{
+			name: "construction site without code or address",
+			service: emission.ServiceRequest{
+				NationalCode: "070201",
+				Construction: &emission.ConstructionRequest{PropertyRegistration: "0123456789"},
+			},
+			expectedCount: 1,
+			checkFields:   []string{"service.construction"},
+		},
+		{
+			name: "construction site address with invalid CEP",
+			service: emission.ServiceRequest{
+				NationalCode: "070201",
+				Construction: &emission.ConstructionRequest{Address: &emission.AddressRequest{
+					Street: "Rua das Obras", Number: "100", Neighborhood: "Centro", PostalCode: "0131",
+				}},
+			},
+			expectedCount: 1,
+			checkFields:   []string{"service.construction.address.postal_code"},
+		},
+		{
+			name: "foreign construction site address missing city and region",
+			service: emission.ServiceRequest{
+				NationalCode: "070201",
+				Construction: &emission.ConstructionRequest{Address: &emission.AddressRequest{
+					Street: "Calle Mayor", Number: "10", Neighborhood: "Centro", PostalCode: "28013", CountryCode: "ES",
+				}},
+			},
+			expectedCount: 2,
+			checkFields:   []string{"service.construction.address.city", "service.construction.address.region"},
+		},
+		{
+			name:          "event service without event",
+			service:       emission.ServiceRequest{NationalCode: "120101"},
+			expectedCount: 1,
+			checkFields:   []string{"service.event"},
+		},
+		{
+			name: "event service with municipal event identification",
+			service: emission.ServiceRequest{
+				NationalCode: "171001",
+				Event:        &emission.EventRequest{Name: "Feira de Tecnologia", StartDate: "2025-03-10", EndDate: "2025-03-12", ID: "EVT-2025-001"},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "event service with event address",
+			service: emission.ServiceRequest{
+				NationalCode: "120101",
+				Event:        &emission.EventRequest{Name: "Show de Verao", StartDate: "2025-01-20", EndDate: "2025-01-20", Address: testSiteAddress()},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "event ending before it starts",
+			service: emission.ServiceRequest{
+				NationalCode: "120101",
+				Event:        &emission.EventRequest{Name: "Show de Verao", StartDate: "2025-01-20", EndDate: "2025-01-19", ID: "EVT-1"},
+			},
+			expectedCount: 1,
+			checkFields:   []string{"service.event.end_date"},
+		},
+		{
+			name: "event without name, with invalid date and without location",
+			service: emission.ServiceRequest{
+				NationalCode: "120101",
+				Event:        &emission.EventRequest{StartDate: "20/01/2025", EndDate: "2025-01-20"},
+			},
+			expectedCount: 3,
+			checkFields:   []string{"service.event.name", "service.event.start_date", "service.event"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.validateServiceSite(&tt.service)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiresConstructionAndEvent(t *testing.T) {
+	tests := []struct {
+		nationalCode string
+		construction bool
+		event        bool
+	}{
+		{"070201", true, false},
+		{"071901", true, false},
+		{"070101", false, false},
+		{"120101", false, true},
+		{"121701", false, true},
+		{"171001", false, true},
+		{"170101", false, false},
+		{"010101", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := RequiresConstruction(tt.nationalCode); got != tt.construction {
+			t.Errorf("RequiresConstruction(%q) = %v, want %v", tt.nationalCode, got, tt.construction)
+		}
+		if got := RequiresEvent(tt.nationalCode); got != tt.event {
+			t.Errorf("RequiresEvent(%q) = %v, want %v", tt.nationalCode, got, tt.event)
+		}
+	}
+}
+
+func TestEmissionValidator_ValidateServiceCodes(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	foreignAddress := &emission.AddressRequest{
+		Street:      "Main Street",
+		Number:      "100",
+		PostalCode:  "10001",
+		City:        "New York",
+		Region:      "NY",
+		CountryCode: "US",
+	}
+
+	tests := []struct {
+		name          string
+		emitterType   int
+		service       emission.ServiceRequest
+		taker         *emission.TakerRequest
+		intermediary  *emission.IntermediaryRequest
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "without service codes",
+			service:       emission.ServiceRequest{},
+			expectedCount: 0,
+		},
+		{
+			name:          "NBS code in the national list",
+			service:       emission.ServiceRequest{NBSCode: "101011100", MunicipalTaxCode: "001"},
+			expectedCount: 0,
+		},
+		{
+			name:          "formatted NBS code",
+			service:       emission.ServiceRequest{NBSCode: "1.0101.11.00"},
+			expectedCount: 0,
+		},
+		{
+			name:          "NBS code with wrong length",
+			service:       emission.ServiceRequest{NBSCode: "1010111"},
+			expectedCount: 1,
+			checkFields:   []string{"service.nbs_code"},
+		},
+		{
+			name:          "NBS code not in the national list",
+			service:       emission.ServiceRequest{NBSCode: "101011900"},
+			expectedCount: 1,
+			checkFields:   []string{"service.nbs_code"},
+		},
+		{
+			name:          "municipal tax code with wrong length",
+			service:       emission.ServiceRequest{MunicipalTaxCode: "0001"},
+			expectedCount: 1,
+			checkFields:   []string{"service.municipal_tax_code"},
+		},
+		{
+			name:          "municipal tax code with letters",
+			service:       emission.ServiceRequest{MunicipalTaxCode: "A01"},
+			expectedCount: 1,
+			checkFields:   []string{"service.municipal_tax_code"},
+		},
+		{
+			name:          "export with NBS code",
+			service:       emission.ServiceRequest{NBSCode: "101011100", CountryCode: "US"},
+			expectedCount: 0,
+		},
+		{
+			name:          "export without NBS code, service performed abroad",
+			service:       emission.ServiceRequest{CountryCode: "US"},
+			expectedCount: 1,
+			checkFields:   []string{"service.nbs_code"},
+		},
+		{
+			name:          "export without NBS code, foreign taker",
+			taker:         &emission.TakerRequest{NIF: "123456789", Address: foreignAddress},
+			expectedCount: 1,
+			checkFields:   []string{"service.nbs_code"},
+		},
+		{
+			name:          "export without NBS code, foreign intermediary",
+			intermediary:  &emission.IntermediaryRequest{NIF: "123456789", Address: foreignAddress},
+			expectedCount: 1,
+			checkFields:   []string{"service.nbs_code"},
+		},
+		{
+			name:          "national taker without NBS code",
+			taker:         &emission.TakerRequest{CNPJ: "11222333000181", Address: &emission.AddressRequest{PostalCode: "01310100"}},
+			expectedCount: 0,
+		},
+		{
+			name:          "import without NBS code, service performed abroad",
+			emitterType:   emission.EmitterTaker,
+			service:       emission.ServiceRequest{CountryCode: "US"},
+			taker:         &emission.TakerRequest{CNPJ: "11222333000181"},
+			expectedCount: 1,
+			checkFields:   []string{"service.nbs_code"},
+		},
+		{
+			name:          "import with NBS code",
+			emitterType:   emission.EmitterIntermediary,
+			service:       emission.ServiceRequest{NBSCode: "101011100", CountryCode: "US"},
+			intermediary:  &emission.IntermediaryRequest{CNPJ: "11222333000181"},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &emission.EmissionRequest{
+				EmitterType:  tt.emitterType,
+				Service:      tt.service,
+				Taker:        tt.taker,
+				Intermediary: tt.intermediary,
+			}
+
+			errors := validator.validateServiceCodes(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package validation
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
@@ -59,6 +60,15 @@ const (
 
 	// NIFMaxLength is the maximum length for NIF.
 	NIFMaxLength = 40
+
+	// ForeignPostalCodeMaxLength is the maximum length for a foreign postal code (cEndPost).
+	ForeignPostalCodeMaxLength = 11
+
+	// AddressCityMaxLength is the maximum length for a foreign city name (xCidade).
+	AddressCityMaxLength = 60
+
+	// AddressRegionMaxLength is the maximum length for a foreign state, province or region (xEstProvReg).
+	AddressRegionMaxLength = 60
 )
 
 // Valid Brazilian state codes.
@@ -92,89 +102,128 @@ func (v *TakerValidator) ValidateTaker(taker *emission.TakerRequest) []Validatio
 
 	var errors []ValidationError
 
+	// Validate identification, name and contact
+	errors = append(errors, v.validatePerson("taker", "Taker", taker.CNPJ, taker.CPF, taker.NIF, taker.Name, taker.Phone, taker.Email)...)
+
+	// Validate address based on taker type
+	errors = append(errors, v.validateTakerAddress(taker)...)
+
+	return errors
+}
+
+// ValidateIntermediary validates an intermediary and returns all validation errors found.
+// The intermediary follows the taker rules, except that its address is always optional;
+// when provided it must be foreign for NIF intermediaries and national otherwise.
+func (v *TakerValidator) ValidateIntermediary(intermediary *emission.IntermediaryRequest) []ValidationError {
+	if intermediary == nil {
+		return []ValidationError{
+			NewValidationError("intermediary", ValidationCodeRequired, "Intermediary is required"),
+		}
+	}
+
+	var errors []ValidationError
+
+	// Validate identification, name and contact
+	errors = append(errors, v.validatePerson("intermediary", "Intermediary", intermediary.CNPJ, intermediary.CPF, intermediary.NIF, intermediary.Name, intermediary.Phone, intermediary.Email)...)
+
+	// Validate address (if provided)
+	if intermediary.Address != nil {
+		if intermediary.NIF != "" {
+			errors = append(errors, v.validateForeignAddress("intermediary.address", intermediary.Address)...)
+		} else {
+			errors = append(errors, v.validateNationalAddress("intermediary.address", intermediary.Address)...)
+		}
+	}
+
+	return errors
+}
+
+// validatePerson validates the identification, name and contact shared by the
+// taker and the intermediary (TCInfoPessoa). Field is the request field of the
+// person ("taker" or "intermediary") and label is its name in error messages.
+func (v *TakerValidator) validatePerson(field, label, cnpj, cpf, nif, name, phone, email string) []ValidationError {
+	var errors []ValidationError
+
 	// Validate identification (CNPJ, CPF, or NIF)
-	errors = append(errors, v.validateIdentification(taker)...)
+	errors = append(errors, v.validateIdentification(field, label, cnpj, cpf, nif)...)
 
 	// Validate name
-	errors = append(errors, v.validateName(taker.Name)...)
+	errors = append(errors, v.validateName(field, label, name)...)
 
 	// Validate phone (if provided)
-	if taker.Phone != "" {
-		errors = append(errors, v.validatePhone(taker.Phone, taker.NIF != "")...)
+	if phone != "" {
+		errors = append(errors, v.validatePhone(field, phone, nif != "")...)
 	}
 
 	// Validate email (if provided)
-	if taker.Email != "" {
-		errors = append(errors, v.validateEmail(taker.Email)...)
+	if email != "" {
+		errors = append(errors, v.validateEmail(field, email)...)
 	}
 
-	// Validate address based on taker type
-	errors = append(errors, v.validateTakerAddress(taker)...)
-
 	return errors
 }
 
 // validateIdentification validates that exactly one identification type is provided
 // and that the provided identification is valid.
-func (v *TakerValidator) validateIdentification(taker *emission.TakerRequest) []ValidationError {
+func (v *TakerValidator) validateIdentification(field, label, cnpj, cpf, nif string) []ValidationError {
 	var errors []ValidationError
 
 	// Count provided identifiers
 	identifierCount := 0
-	if taker.CNPJ != "" {
+	if cnpj != "" {
 		identifierCount++
 	}
-	if taker.CPF != "" {
+	if cpf != "" {
 		identifierCount++
 	}
-	if taker.NIF != "" {
+	if nif != "" {
 		identifierCount++
 	}
 
 	// Check that exactly one identifier is provided
 	if identifierCount == 0 {
 		errors = append(errors, NewValidationError(
-			"taker",
+			field,
 			ValidationCodeRequired,
-			"Taker must have exactly one of CNPJ, CPF, or NIF",
+			label+" must have exactly one of CNPJ, CPF, or NIF",
 		))
 		return errors
 	}
 
 	if identifierCount > 1 {
 		errors = append(errors, NewValidationError(
-			"taker",
+			field,
 			ValidationCodeInvalid,
-			"Taker must have only one of CNPJ, CPF, or NIF (they are mutually exclusive)",
+			label+" must have only one of CNPJ, CPF, or NIF (they are mutually exclusive)",
 		))
 		return errors
 	}
 
 	// Validate the provided identifier
-	if taker.CNPJ != "" {
-		cleanCNPJ := cnpjcpf.CleanCNPJ(taker.CNPJ)
+	if cnpj != "" {
+		cleanCNPJ := cnpjcpf.CleanCNPJ(cnpj)
 		if !cnpjcpf.ValidateCNPJ(cleanCNPJ) {
 			errors = append(errors, NewValidationError(
-				"taker.cnpj",
+				field+".cnpj",
 				ValidationCodeInvalid,
-				"Taker CNPJ is invalid (check digit mismatch or incorrect format)",
+				label+" CNPJ is invalid (check digit mismatch or incorrect format)",
 			))
 		}
 	}
 
-	if taker.CPF != "" {
-		cleanCPF := cnpjcpf.CleanCPF(taker.CPF)
+	if cpf != "" {
+		cleanCPF := cnpjcpf.CleanCPF(cpf)
 		if !cnpjcpf.ValidateCPF(cleanCPF) {
 			errors = append(errors, NewValidationError(
-				"taker.cpf",
+				field+".cpf",
 				ValidationCodeInvalid,
-				"Taker CPF is invalid (check digit mismatch or incorrect format)",
+				label+" CPF is invalid (check digit mismatch or incorrect format)",
 			))
 		}
 	}
 
-	if taker.NIF != "" {
-		errors = append(errors, v.validateNIF(taker.NIF)...)
+	if nif != "" {
+		errors = append(errors, v.validateNIF(field, nif)...)
 	}
 
 	return errors
@@ -182,13 +231,13 @@ func (v *TakerValidator) validateIdentification(taker *emission.TakerRequest) []
 
 // validateNIF validates a foreign tax identification number.
 // NIF must be 1-40 alphanumeric characters.
-func (v *TakerValidator) validateNIF(nif string) []ValidationError {
+func (v *TakerValidator) validateNIF(field, nif string) []ValidationError {
 	var errors []ValidationError
 
 	// Check length
 	if len(nif) == 0 {
 		errors = append(errors, NewValidationError(
-			"taker.nif",
+			field+".nif",
 			ValidationCodeRequired,
 			"NIF cannot be empty",
 		))
@@ -197,7 +246,7 @@ func (v *TakerValidator) validateNIF(nif string) []ValidationError {
 
 	if len(nif) > NIFMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.nif",
+			field+".nif",
 			ValidationCodeTooLong,
 			"NIF must not exceed 40 characters",
 		))
@@ -207,7 +256,7 @@ func (v *TakerValidator) validateNIF(nif string) []ValidationError {
 	// Check format (alphanumeric only)
 	if !nifPattern.MatchString(nif) {
 		errors = append(errors, NewValidationError(
-			"taker.nif",
+			field+".nif",
 			ValidationCodeInvalidFormat,
 			"NIF must contain only alphanumeric characters (1-40 chars)",
 		))
@@ -216,17 +265,17 @@ func (v *TakerValidator) validateNIF(nif string) []ValidationError {
 	return errors
 }
 
-// validateName validates the taker name.
-func (v *TakerValidator) validateName(name string) []ValidationError {
+// validateName validates the taker or intermediary name.
+func (v *TakerValidator) validateName(field, label, name string) []ValidationError {
 	var errors []ValidationError
 
 	// Check required
 	trimmedName := strings.TrimSpace(name)
 	if trimmedName == "" {
 		errors = append(errors, NewValidationError(
-			"taker.name",
+			field+".name",
 			ValidationCodeRequired,
-			"Taker name is required",
+			label+" name is required",
 		))
 		return errors
 	}
@@ -234,9 +283,9 @@ func (v *TakerValidator) validateName(name string) []ValidationError {
 	// Check length
 	if len(trimmedName) > TakerNameMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.name",
+			field+".name",
 			ValidationCodeTooLong,
-			"Taker name must not exceed 300 characters",
+			label+" name must not exceed 300 characters",
 		))
 	}
 
@@ -244,9 +293,9 @@ func (v *TakerValidator) validateName(name string) []ValidationError {
 	for _, r := range trimmedName {
 		if unicode.IsControl(r) {
 			errors = append(errors, NewValidationError(
-				"taker.name",
+				field+".name",
 				ValidationCodeInvalid,
-				"Taker name contains invalid control characters",
+				label+" name contains invalid control characters",
 			))
 			break
 		}
@@ -255,10 +304,10 @@ func (v *TakerValidator) validateName(name string) []ValidationError {
 	return errors
 }
 
-// validatePhone validates the taker phone number.
-// For Brazilian takers (CNPJ/CPF), phone should be 10-11 digits.
-// For foreign takers (NIF), international format is accepted.
-func (v *TakerValidator) validatePhone(phone string, isForeign bool) []ValidationError {
+// validatePhone validates the taker or intermediary phone number.
+// For Brazilian persons (CNPJ/CPF), phone should be 10-11 digits.
+// For foreign persons (NIF), international format is accepted.
+func (v *TakerValidator) validatePhone(field, phone string, isForeign bool) []ValidationError {
 	var errors []ValidationError
 
 	// Clean phone number (remove common formatting)
@@ -273,7 +322,7 @@ func (v *TakerValidator) validatePhone(phone string, isForeign bool) []Validatio
 		// International phone: allow + prefix, 7-15 digits
 		if !internationalPhonePattern.MatchString(cleanPhone) {
 			errors = append(errors, NewValidationError(
-				"taker.phone",
+				field+".phone",
 				ValidationCodeInvalidFormat,
 				"Phone number must be 7-15 digits (international format)",
 			))
@@ -282,7 +331,7 @@ func (v *TakerValidator) validatePhone(phone string, isForeign bool) []Validatio
 		// Brazilian phone: 10-11 digits
 		if !brazilianPhonePattern.MatchString(cleanPhone) {
 			errors = append(errors, NewValidationError(
-				"taker.phone",
+				field+".phone",
 				ValidationCodeInvalidFormat,
 				"Phone number must be 10-11 digits (Brazilian format: DDD + number)",
 			))
@@ -292,8 +341,8 @@ func (v *TakerValidator) validatePhone(phone string, isForeign bool) []Validatio
 	return errors
 }
 
-// validateEmail validates the taker email address.
-func (v *TakerValidator) validateEmail(email string) []ValidationError {
+// validateEmail validates the taker or intermediary email address.
+func (v *TakerValidator) validateEmail(field, email string) []ValidationError {
 	var errors []ValidationError
 
 	trimmedEmail := strings.TrimSpace(email)
@@ -305,7 +354,7 @@ func (v *TakerValidator) validateEmail(email string) []ValidationError {
 	// Check length
 	if len(trimmedEmail) > EmailMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.email",
+			field+".email",
 			ValidationCodeTooLong,
 			"Email must not exceed 254 characters",
 		))
@@ -315,7 +364,7 @@ func (v *TakerValidator) validateEmail(email string) []ValidationError {
 	// Check format
 	if !emailPattern.MatchString(trimmedEmail) {
 		errors = append(errors, NewValidationError(
-			"taker.email",
+			field+".email",
 			ValidationCodeInvalidFormat,
 			"Email address is not in a valid format",
 		))
@@ -343,12 +392,12 @@ func (v *TakerValidator) validateTakerAddress(taker *emission.TakerRequest) []Va
 			return errors
 		}
 		// Validate as national address
-		errors = append(errors, v.validateNationalAddress(taker.Address)...)
+		errors = append(errors, v.validateNationalAddress("taker.address", taker.Address)...)
 	} else if taker.CPF != "" {
 		// B2C: address is optional for individual takers
 		if taker.Address != nil {
 			// If provided, validate as national address
-			errors = append(errors, v.validateNationalAddress(taker.Address)...)
+			errors = append(errors, v.validateNationalAddress("taker.address", taker.Address)...)
 		}
 	} else if taker.NIF != "" {
 		// Foreign: address is required for foreign takers
@@ -361,7 +410,7 @@ func (v *TakerValidator) validateTakerAddress(taker *emission.TakerRequest) []Va
 			return errors
 		}
 		// Validate as foreign address
-		errors = append(errors, v.validateForeignAddress(taker.Address)...)
+		errors = append(errors, v.validateForeignAddress("taker.address", taker.Address)...)
 	}
 
 	return errors
@@ -375,28 +424,29 @@ func (v *TakerValidator) ValidateAddress(addr *emission.AddressRequest, isForeig
 	}
 
 	if isForeign {
-		return v.validateForeignAddress(addr)
+		return v.validateForeignAddress("taker.address", addr)
 	}
-	return v.validateNationalAddress(addr)
+	return v.validateNationalAddress("taker.address", addr)
 }
 
 // validateNationalAddress validates a Brazilian (national) address.
-func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest) []ValidationError {
+// Field is the request field of the address, used in validation errors.
+func (v *TakerValidator) validateNationalAddress(field string, addr *emission.AddressRequest) []ValidationError {
 	var errors []ValidationError
 
 	// Validate common fields
-	errors = append(errors, v.validateAddressCommonFields(addr)...)
+	errors = append(errors, v.validateAddressCommonFields(field, addr)...)
 
 	// Municipality code is required (7 digits IBGE code)
 	if addr.MunicipalityCode == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.municipality_code",
+			field+".municipality_code",
 			ValidationCodeRequired,
 			"Municipality code (IBGE) is required for Brazilian addresses",
 		))
 	} else if !municipalityCodePattern.MatchString(addr.MunicipalityCode) {
 		errors = append(errors, NewValidationError(
-			"taker.address.municipality_code",
+			field+".municipality_code",
 			ValidationCodeInvalidFormat,
 			"Municipality code must be exactly 7 digits (IBGE code)",
 		))
@@ -405,7 +455,7 @@ func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest)
 	// State is required (2 chars)
 	if addr.State == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.state",
+			field+".state",
 			ValidationCodeRequired,
 			"State (UF) is required for Brazilian addresses",
 		))
@@ -413,13 +463,13 @@ func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest)
 		upperState := strings.ToUpper(addr.State)
 		if !statePattern.MatchString(upperState) {
 			errors = append(errors, NewValidationError(
-				"taker.address.state",
+				field+".state",
 				ValidationCodeInvalidFormat,
 				"State must be exactly 2 uppercase letters",
 			))
 		} else if !validBrazilianStates[upperState] {
 			errors = append(errors, NewValidationError(
-				"taker.address.state",
+				field+".state",
 				ValidationCodeInvalid,
 				"State is not a valid Brazilian state code",
 			))
@@ -429,7 +479,7 @@ func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest)
 	// Postal code is required (8 digits CEP)
 	if addr.PostalCode == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.postal_code",
+			field+".postal_code",
 			ValidationCodeRequired,
 			"Postal code (CEP) is required for Brazilian addresses",
 		))
@@ -437,7 +487,7 @@ func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest)
 		cleanPostal := strings.ReplaceAll(addr.PostalCode, "-", "")
 		if !postalCodePattern.MatchString(cleanPostal) {
 			errors = append(errors, NewValidationError(
-				"taker.address.postal_code",
+				field+".postal_code",
 				ValidationCodeInvalidFormat,
 				"Postal code (CEP) must be exactly 8 digits",
 			))
@@ -447,7 +497,7 @@ func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest)
 	// Country code should be BR or empty for national addresses
 	if addr.CountryCode != "" && addr.CountryCode != "BR" {
 		errors = append(errors, NewValidationError(
-			"taker.address.country_code",
+			field+".country_code",
 			ValidationCodeInvalid,
 			"Country code must be 'BR' or empty for Brazilian addresses",
 		))
@@ -457,28 +507,29 @@ func (v *TakerValidator) validateNationalAddress(addr *emission.AddressRequest)
 }
 
 // validateForeignAddress validates a foreign (non-Brazilian) address.
-func (v *TakerValidator) validateForeignAddress(addr *emission.AddressRequest) []ValidationError {
+// Field is the request field of the address, used in validation errors.
+func (v *TakerValidator) validateForeignAddress(field string, addr *emission.AddressRequest) []ValidationError {
 	var errors []ValidationError
 
 	// Validate common fields
-	errors = append(errors, v.validateAddressCommonFields(addr)...)
+	errors = append(errors, v.validateAddressCommonFields(field, addr)...)
 
 	// Country code is required and must NOT be BR
 	if addr.CountryCode == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.country_code",
+			field+".country_code",
 			ValidationCodeRequired,
 			"Country code is required for foreign addresses",
 		))
 	} else if !countryCodePattern.MatchString(addr.CountryCode) {
 		errors = append(errors, NewValidationError(
-			"taker.address.country_code",
+			field+".country_code",
 			ValidationCodeInvalidFormat,
 			"Country code must be exactly 2 uppercase letters (ISO 3166-1 alpha-2)",
 		))
 	} else if addr.CountryCode == "BR" {
 		errors = append(errors, NewValidationError(
-			"taker.address.country_code",
+			field+".country_code",
 			ValidationCodeInvalid,
 			"Foreign address cannot have 'BR' as country code",
 		))
@@ -487,7 +538,7 @@ func (v *TakerValidator) validateForeignAddress(addr *emission.AddressRequest) [
 	// Municipality code should NOT be provided for foreign addresses
 	if addr.MunicipalityCode != "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.municipality_code",
+			field+".municipality_code",
 			ValidationCodeInvalid,
 			"Municipality code (IBGE) should not be provided for foreign addresses",
 		))
@@ -496,32 +547,54 @@ func (v *TakerValidator) validateForeignAddress(addr *emission.AddressRequest) [
 	// State should NOT be provided for foreign addresses
 	if addr.State != "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.state",
+			field+".state",
 			ValidationCodeInvalid,
 			"State (UF) should not be provided for foreign addresses",
 		))
 	}
 
-	// Postal code is optional for foreign addresses
-	// (no validation on format since it varies by country)
+	// Postal code, city and region identify the foreign address (endExt).
+	// The postal code format varies by country, so only its length is checked
+	errors = append(errors, validateForeignAddressField(addr.PostalCode, field+".postal_code", "Postal code", ForeignPostalCodeMaxLength)...)
+	errors = append(errors, validateForeignAddressField(addr.City, field+".city", "City", AddressCityMaxLength)...)
+	errors = append(errors, validateForeignAddressField(addr.Region, field+".region", "State, province or region", AddressRegionMaxLength)...)
 
 	return errors
 }
 
+// validateForeignAddressField validates a required field of a foreign address.
+func validateForeignAddressField(value, field, label string, maxLength int) []ValidationError {
+	if strings.TrimSpace(value) == "" {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeRequired,
+			fmt.Sprintf("%s is required for foreign addresses", label),
+		)}
+	}
+	if len(value) > maxLength {
+		return []ValidationError{NewValidationError(
+			field,
+			ValidationCodeTooLong,
+			fmt.Sprintf("%s must not exceed %d characters", label, maxLength),
+		)}
+	}
+	return nil
+}
+
 // validateAddressCommonFields validates fields common to both national and foreign addresses.
-func (v *TakerValidator) validateAddressCommonFields(addr *emission.AddressRequest) []ValidationError {
+func (v *TakerValidator) validateAddressCommonFields(field string, addr *emission.AddressRequest) []ValidationError {
 	var errors []ValidationError
 
 	// Street is required
 	if strings.TrimSpace(addr.Street) == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.street",
+			field+".street",
 			ValidationCodeRequired,
 			"Street is required",
 		))
 	} else if len(addr.Street) > AddressStreetMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.address.street",
+			field+".street",
 			ValidationCodeTooLong,
 			"Street must not exceed 255 characters",
 		))
@@ -530,13 +603,13 @@ func (v *TakerValidator) validateAddressCommonFields(addr *emission.AddressReque
 	// Number is required
 	if strings.TrimSpace(addr.Number) == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.number",
+			field+".number",
 			ValidationCodeRequired,
 			"Number is required",
 		))
 	} else if len(addr.Number) > AddressNumberMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.address.number",
+			field+".number",
 			ValidationCodeTooLong,
 			"Number must not exceed 60 characters",
 		))
@@ -545,7 +618,7 @@ func (v *TakerValidator) validateAddressCommonFields(addr *emission.AddressReque
 	// Complement is optional, but validate length if provided
 	if addr.Complement != "" && len(addr.Complement) > AddressComplementMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.address.complement",
+			field+".complement",
 			ValidationCodeTooLong,
 			"Complement must not exceed 156 characters",
 		))
@@ -554,13 +627,13 @@ func (v *TakerValidator) validateAddressCommonFields(addr *emission.AddressReque
 	// Neighborhood is required
 	if strings.TrimSpace(addr.Neighborhood) == "" {
 		errors = append(errors, NewValidationError(
-			"taker.address.neighborhood",
+			field+".neighborhood",
 			ValidationCodeRequired,
 			"Neighborhood is required",
 		))
 	} else if len(addr.Neighborhood) > AddressNeighborhoodMaxLength {
 		errors = append(errors, NewValidationError(
-			"taker.address.neighborhood",
+			field+".neighborhood",
 			ValidationCodeTooLong,
 			"Neighborhood must not exceed 60 characters",
 		))
@@ -56,6 +56,9 @@ func TestTakerValidator_ValidateTaker(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "ES",
 				},
 			},
@@ -122,6 +125,9 @@ func TestTakerValidator_ValidateTaker(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "ES",
 				},
 			},
@@ -137,6 +143,9 @@ func TestTakerValidator_ValidateTaker(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "ES",
 				},
 			},
@@ -214,6 +223,133 @@ func TestTakerValidator_ValidateTaker(t *testing.T) {
 	}
 }
 
+func TestTakerValidator_ValidateIntermediary(t *testing.T) {
+	validator := NewTakerValidator()
+
+	tests := []struct {
+		name          string
+		intermediary  *emission.IntermediaryRequest
+		expectedCount int // number of expected validation errors
+		checkFields   []string
+	}{
+		{
+			name:          "nil intermediary returns error",
+			intermediary:  nil,
+			expectedCount: 1,
+			checkFields:   []string{"intermediary"},
+		},
+		{
+			name: "valid intermediary with CNPJ and address",
+			intermediary: &emission.IntermediaryRequest{
+				CNPJ:  "11222333000181",
+				Name:  "Marketplace S.A.",
+				Phone: "(11) 3333-4444",
+				Email: "nfse@marketplace.com.br",
+				Address: &emission.AddressRequest{
+					Street:           "Rua Funchal",
+					Number:           "418",
+					Neighborhood:     "Vila Olimpia",
+					MunicipalityCode: "3550308",
+					State:            "SP",
+					PostalCode:       "04551060",
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid intermediary with CNPJ without address",
+			intermediary: &emission.IntermediaryRequest{
+				CNPJ: "11222333000181",
+				Name: "Marketplace S.A.",
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "valid intermediary with NIF and foreign address",
+			intermediary: &emission.IntermediaryRequest{
+				NIF:  "IE1234567T",
+				Name: "Foreign Marketplace Ltd",
+				Address: &emission.AddressRequest{
+					Street:       "Grand Canal Street",
+					Number:       "1",
+					Neighborhood: "Docklands",
+					PostalCode:   "D02",
+					City:         "Dublin",
+					Region:       "Leinster",
+					CountryCode:  "IE",
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "intermediary without identification",
+			intermediary: &emission.IntermediaryRequest{
+				Name: "Marketplace S.A.",
+			},
+			expectedCount: 1,
+			checkFields:   []string{"intermediary"},
+		},
+		{
+			name: "intermediary with invalid CPF and email",
+			intermediary: &emission.IntermediaryRequest{
+				CPF:   "12345678900",
+				Name:  "Agent",
+				Email: "not-an-email",
+			},
+			expectedCount: 2,
+			checkFields:   []string{"intermediary.cpf", "intermediary.email"},
+		},
+		{
+			name: "NIF intermediary with national address",
+			intermediary: &emission.IntermediaryRequest{
+				NIF:  "IE1234567T",
+				Name: "Foreign Marketplace Ltd",
+				Address: &emission.AddressRequest{
+					Street:           "Rua Funchal",
+					Number:           "418",
+					Neighborhood:     "Vila Olimpia",
+					MunicipalityCode: "3550308",
+					State:            "SP",
+					PostalCode:       "04551060",
+				},
+			},
+			expectedCount: 5,
+			checkFields: []string{
+				"intermediary.address.country_code",
+				"intermediary.address.municipality_code",
+				"intermediary.address.state",
+				"intermediary.address.city",
+				"intermediary.address.region",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateIntermediary(tt.intermediary)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
+
 func TestTakerValidator_ValidateNationalAddress(t *testing.T) {
 	validator := NewTakerValidator()
 
@@ -451,6 +587,9 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "ES",
 				},
 			},
@@ -466,6 +605,9 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 					Number:       "100",
 					Complement:   "Suite 500",
 					Neighborhood: "Downtown",
+					PostalCode:   "10001",
+					City:         "New York",
+					Region:       "NY",
 					CountryCode:  "US",
 				},
 			},
@@ -480,6 +622,9 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 				},
 			},
 			expectedCount: 1,
@@ -494,6 +639,9 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "BR", // Invalid for foreign taker
 				},
 			},
@@ -509,6 +657,9 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 					Street:           "Foreign Street",
 					Number:           "456",
 					Neighborhood:     "Foreign District",
+					PostalCode:       "28001",
+					City:             "Madrid",
+					Region:           "Madrid",
 					MunicipalityCode: "3550308", // Should not be provided
 					CountryCode:      "ES",
 				},
@@ -525,6 +676,9 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					State:        "SP", // Should not be provided
 					CountryCode:  "ES",
 				},
@@ -532,6 +686,39 @@ func TestTakerValidator_ValidateForeignAddress(t *testing.T) {
 			expectedCount: 1,
 			checkFields:   []string{"taker.address.state"},
 		},
+		{
+			name: "missing foreign postal code, city and region",
+			taker: &emission.TakerRequest{
+				NIF:  "ES12345678A",
+				Name: "Foreign Company",
+				Address: &emission.AddressRequest{
+					Street:       "Foreign Street",
+					Number:       "456",
+					Neighborhood: "Foreign District",
+					CountryCode:  "ES",
+				},
+			},
+			expectedCount: 3,
+			checkFields:   []string{"taker.address.postal_code", "taker.address.city", "taker.address.region"},
+		},
+		{
+			name: "foreign postal code too long",
+			taker: &emission.TakerRequest{
+				NIF:  "ES12345678A",
+				Name: "Foreign Company",
+				Address: &emission.AddressRequest{
+					Street:       "Foreign Street",
+					Number:       "456",
+					Neighborhood: "Foreign District",
+					PostalCode:   "280010000000",
+					City:         "Madrid",
+					Region:       "Madrid",
+					CountryCode:  "ES",
+				},
+			},
+			expectedCount: 1,
+			checkFields:   []string{"taker.address.postal_code"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -606,6 +793,9 @@ func TestTakerValidator_ValidatePhoneAndEmail(t *testing.T) {
 					Street:       "Foreign Street",
 					Number:       "456",
 					Neighborhood: "Foreign District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "ES",
 				},
 			},
@@ -694,6 +884,9 @@ func TestTakerValidator_NIFValidation(t *testing.T) {
 					Street:       "Test Street",
 					Number:       "100",
 					Neighborhood: "Test District",
+					PostalCode:   "28001",
+					City:         "Madrid",
+					Region:       "Madrid",
 					CountryCode:  "ES",
 				},
 			}
@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+// Tax regime validation constants.
+const (
+	// SpecialRegimeMax is the highest special taxation regime (regEspTrib).
+	SpecialRegimeMax = emission.SpecialRegimeProfessionalSociety
+
+	// MaxSimplesTaxRate is the highest approximate Simples Nacional percentage (pTotTribSN).
+	MaxSimplesTaxRate = 99.99
+)
+
+// isValidTaxRegime reports whether the tax regime is supported.
+func isValidTaxRegime(regime string) bool {
+	switch regime {
+	case TaxRegimeMEI, TaxRegimeMEEPP, TaxRegimeLucroPresumido, TaxRegimeLucroReal, TaxRegimeAutonomo:
+		return true
+	default:
+		return false
+	}
+}
+
+// federalTaxesInSimples reports whether the provider pays its federal taxes within the
+// Simples Nacional, which dispenses the taker from withholding IRRF, CSLL and PIS/COFINS.
+func federalTaxesInSimples(provider *emission.ProviderRequest) bool {
+	switch provider.TaxRegime {
+	case TaxRegimeMEI:
+		return true
+	case TaxRegimeMEEPP:
+		return provider.SimplesCalculationRegime != emission.SimplesCalculationNone
+	default:
+		return false
+	}
+}
+
+// validateTaxRegime validates the regime matches the provider's registration (individuals
+// are identified by CPF), the Simples Nacional calculation regime (regApTribSN), the special
+// taxation regime (regEspTrib), and the withholdings and approximate Simples Nacional
+// percentage (pTotTribSN) allowed or required by the regime.
+func (v *EmissionValidator) validateTaxRegime(req *emission.EmissionRequest) []ValidationError {
+	var errors []ValidationError
+	provider := &req.Provider
+
+	if provider.CPF != "" && provider.CNPJ == "" && provider.TaxRegime != TaxRegimeAutonomo && isValidTaxRegime(provider.TaxRegime) {
+		errors = append(errors, NewValidationError(
+			"provider.tax_regime",
+			ValidationCodeInvalid,
+			"Providers identified by CPF must use the 'autonomo' tax regime",
+		))
+	} else if provider.TaxRegime == TaxRegimeAutonomo && provider.CNPJ != "" {
+		errors = append(errors, NewValidationError(
+			"provider.tax_regime",
+			ValidationCodeInvalid,
+			"The 'autonomo' tax regime is only allowed for providers identified by CPF",
+		))
+	}
+
+	if calc := provider.SimplesCalculationRegime; calc != 0 {
+		if provider.TaxRegime != TaxRegimeMEEPP {
+			errors = append(errors, NewValidationError(
+				"provider.simples_calculation_regime",
+				ValidationCodeInvalid,
+				"Simples Nacional calculation regime (regApTribSN) is only allowed for 'me_epp' providers",
+			))
+		} else if calc < emission.SimplesCalculationAll || calc > emission.SimplesCalculationNone {
+			errors = append(errors, NewValidationError(
+				"provider.simples_calculation_regime",
+				ValidationCodeInvalid,
+				"Simples Nacional calculation regime (regApTribSN) must be 1, 2 or 3",
+			))
+		}
+	}
+
+	if regime := provider.SpecialRegime; regime < emission.SpecialRegimeNone || regime > SpecialRegimeMax {
+		errors = append(errors, NewValidationError(
+			"provider.special_regime",
+			ValidationCodeInvalid,
+			fmt.Sprintf("Special taxation regime (regEspTrib) must be 0 to %d", SpecialRegimeMax),
+		))
+	} else if regime != emission.SpecialRegimeNone && provider.TaxRegime == TaxRegimeMEI {
+		errors = append(errors, NewValidationError(
+			"provider.special_regime",
+			ValidationCodeInvalid,
+			"MEI providers cannot use a special taxation regime",
+		))
+	}
+
+	// Simples Nacional optants only suffer the withholding of the social security contribution
+	if taxes := req.Values.FederalTaxes; taxes != nil && federalTaxesInSimples(provider) {
+		if taxes.IRRFRate != 0 || taxes.CSLLRate != 0 || (taxes.PisCofins != nil && taxes.PisCofins.Withheld) {
+			errors = append(errors, NewValidationError(
+				"values.federal_taxes",
+				ValidationCodeInvalid,
+				"IRRF, CSLL and PIS/COFINS are not withheld from providers paying federal taxes within the Simples Nacional",
+			))
+		}
+	}
+
+	// SEFIN rejects pTotTribSN from MEI providers (E0710), so only ME/EPP providers inform
+	// the rate. Without it, the tax burden is taken from the table
+	switch rate := req.Values.SimplesTaxRate; {
+	case rate == 0:
+	case provider.TaxRegime != TaxRegimeMEEPP:
+		errors = append(errors, NewValidationError(
+			"values.simples_tax_rate",
+			ValidationCodeInvalid,
+			"Simples Nacional tax rate (pTotTribSN) is only allowed for 'me_epp' providers",
+		))
+	case rate < 0 || rate > MaxSimplesTaxRate || !isValidMonetaryValue(rate):
+		errors = append(errors, NewValidationError(
+			"values.simples_tax_rate",
+			ValidationCodeOutOfRange,
+			fmt.Sprintf("Simples Nacional tax rate must be between 0 and %.2f with at most 2 decimal places", MaxSimplesTaxRate),
+		))
+	}
+
+	return errors
+}
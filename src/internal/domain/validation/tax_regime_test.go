@@ -0,0 +1,187 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+)
+
+func TestEmissionValidator_ValidateTaxRegime(t *testing.T) {
+	validator := NewEmissionValidator()
+
+	tests := []struct {
+		name           string
+		provider       emission.ProviderRequest
+		federalTaxes   *emission.FederalTaxesRequest
+		simplesTaxRate float64
+		expectedCount  int // number of expected validation errors
+		checkFields    []string
+	}{
+		{
+			name:          "MEI without special regime",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeMEI},
+			expectedCount: 0,
+		},
+		{
+			name:           "ME/EPP with ISS outside the Simples Nacional",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP, SimplesCalculationRegime: emission.SimplesCalculationISSOutside},
+			simplesTaxRate: 6.00,
+			expectedCount:  0,
+		},
+		{
+			name:          "lucro presumido professional society",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeLucroPresumido, SpecialRegime: emission.SpecialRegimeProfessionalSociety},
+			expectedCount: 0,
+		},
+		{
+			name:     "lucro real with federal withholdings",
+			provider: emission.ProviderRequest{TaxRegime: TaxRegimeLucroReal},
+			federalTaxes: &emission.FederalTaxesRequest{
+				PisCofins: &emission.PisCofinsRequest{CST: "01", PISRate: 0.65, COFINSRate: 3.00, Withheld: true},
+				IRRFRate:  1.50,
+				CSLLRate:  1.00,
+			},
+			expectedCount: 0,
+		},
+		{
+			name:           "ME/EPP outside the Simples Nacional with IRRF",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP, SimplesCalculationRegime: emission.SimplesCalculationNone},
+			federalTaxes:   &emission.FederalTaxesRequest{IRRFRate: 1.50},
+			simplesTaxRate: 6.00,
+			expectedCount:  0,
+		},
+		{
+			name:          "MEI with CP withheld",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeMEI},
+			federalTaxes:  &emission.FederalTaxesRequest{CPRate: 11.00},
+			expectedCount: 0,
+		},
+		{
+			name:           "ME/EPP with Simples Nacional tax rate",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP},
+			simplesTaxRate: 6.00,
+			expectedCount:  0,
+		},
+		{
+			name:          "ME/EPP without Simples Nacional tax rate",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP},
+			expectedCount: 0,
+		},
+		{
+			name:           "MEI with Simples Nacional tax rate",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEI},
+			simplesTaxRate: 6.00,
+			expectedCount:  1,
+			checkFields:    []string{"values.simples_tax_rate"},
+		},
+		{
+			name:           "Simples Nacional tax rate of a lucro real provider",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeLucroReal},
+			simplesTaxRate: 6.00,
+			expectedCount:  1,
+			checkFields:    []string{"values.simples_tax_rate"},
+		},
+		{
+			name:           "Simples Nacional tax rate out of range",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP},
+			simplesTaxRate: 100,
+			expectedCount:  1,
+			checkFields:    []string{"values.simples_tax_rate"},
+		},
+		{
+			name:          "autonomous professional identified by CPF",
+			provider:      emission.ProviderRequest{CPF: "52998224725", TaxRegime: TaxRegimeAutonomo, SpecialRegime: emission.SpecialRegimeAutonomousProfessional},
+			federalTaxes:  &emission.FederalTaxesRequest{IRRFRate: 1.50},
+			expectedCount: 0,
+		},
+		{
+			name:           "CPF provider as ME/EPP",
+			provider:       emission.ProviderRequest{CPF: "52998224725", TaxRegime: TaxRegimeMEEPP},
+			simplesTaxRate: 6.00,
+			expectedCount:  1,
+			checkFields:    []string{"provider.tax_regime"},
+		},
+		{
+			name:          "autonomo regime of a CNPJ provider",
+			provider:      emission.ProviderRequest{CNPJ: "11222333000181", TaxRegime: TaxRegimeAutonomo},
+			expectedCount: 1,
+			checkFields:   []string{"provider.tax_regime"},
+		},
+		{
+			name:           "calculation regime out of range",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP, SimplesCalculationRegime: 4},
+			simplesTaxRate: 6.00,
+			expectedCount:  1,
+			checkFields:    []string{"provider.simples_calculation_regime"},
+		},
+		{
+			name:          "calculation regime of a lucro presumido provider",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeLucroPresumido, SimplesCalculationRegime: emission.SimplesCalculationAll},
+			expectedCount: 1,
+			checkFields:   []string{"provider.simples_calculation_regime"},
+		},
+		{
+			name:          "special regime out of range",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeLucroReal, SpecialRegime: 7},
+			expectedCount: 1,
+			checkFields:   []string{"provider.special_regime"},
+		},
+		{
+			name:          "MEI with special regime",
+			provider:      emission.ProviderRequest{TaxRegime: TaxRegimeMEI, SpecialRegime: emission.SpecialRegimeCooperative},
+			expectedCount: 1,
+			checkFields:   []string{"provider.special_regime"},
+		},
+		{
+			name:           "ME/EPP within the Simples Nacional with CSLL",
+			provider:       emission.ProviderRequest{TaxRegime: TaxRegimeMEEPP},
+			federalTaxes:   &emission.FederalTaxesRequest{CSLLRate: 1.00},
+			simplesTaxRate: 6.00,
+			expectedCount:  1,
+			checkFields:    []string{"values.federal_taxes"},
+		},
+		{
+			name:     "MEI with PIS/COFINS withheld",
+			provider: emission.ProviderRequest{TaxRegime: TaxRegimeMEI},
+			federalTaxes: &emission.FederalTaxesRequest{
+				PisCofins: &emission.PisCofinsRequest{CST: "01", PISRate: 0.65, COFINSRate: 3.00, Withheld: true},
+			},
+			expectedCount: 1,
+			checkFields:   []string{"values.federal_taxes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &emission.EmissionRequest{
+				Provider: tt.provider,
+				Values: emission.ValuesRequest{
+					ServiceValue:   1000.00,
+					FederalTaxes:   tt.federalTaxes,
+					SimplesTaxRate: tt.simplesTaxRate,
+				},
+			}
+
+			errors := validator.validateTaxRegime(req)
+
+			if len(errors) != tt.expectedCount {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedCount, len(errors), errors)
+				return
+			}
+
+			// Check that expected fields are present in errors
+			for _, field := range tt.checkFields {
+				found := false
+				for _, err := range errors {
+					if err.Field == field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error for field %q, but not found in %+v", field, errors)
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/beevik/etree"
 )
@@ -214,7 +215,7 @@ func (v *XSDValidator) validateInfDPS(infDPS *etree.Element) []XSDValidationErro
 	// Validate cLocEmi (emission municipality code) - required
 	errors = append(errors, v.validateCLocEmi(infDPS)...)
 
-	// Validate subst (substitution) - required
+	// Validate subst (substitution) - optional
 	errors = append(errors, v.validateSubst(infDPS)...)
 
 	// Validate prest (provider) - required
@@ -422,31 +423,48 @@ func (v *XSDValidator) validateCLocEmi(infDPS *etree.Element) []XSDValidationErr
 	return errors
 }
 
-// validateSubst validates the subst (substitution) element.
+// validateSubst validates the optional subst (substitution) group.
+// It is only present when the DPS replaces an existing NFS-e.
 func (v *XSDValidator) validateSubst(infDPS *etree.Element) []XSDValidationError {
 	var errors []XSDValidationError
 
 	subst := infDPS.FindElement("subst")
 	if subst == nil {
-		errors = append(errors, XSDValidationError{
-			Code:    XSDErrorMissingElement,
-			Element: "infDPS/subst",
-			Message: "required element 'subst' (substitution) not found",
-		})
 		return errors
 	}
 
-	value := strings.TrimSpace(subst.Text())
-	// Valid substitution values: 1 (substitution), 2 (no substitution)
-	if value != "1" && value != "2" {
+	// Validate chSubstda (replaced NFS-e access key) - required
+	errors = append(errors, v.validateRequiredElement(subst, "chSubstda", "replaced NFS-e access key")...)
+
+	// Validate cMotivo (substitution reason code) - required
+	cMotivo := subst.FindElement("cMotivo")
+	if cMotivo == nil {
+		errors = append(errors, XSDValidationError{
+			Code:    XSDErrorMissingElement,
+			Element: "subst/cMotivo",
+			Message: "required element 'cMotivo' (substitution reason code) not found",
+		})
+	} else if value := strings.TrimSpace(cMotivo.Text()); !isValidSubstitutionReasonCode(value) {
 		errors = append(errors, XSDValidationError{
 			Code:    XSDErrorInvalidValue,
-			Element: "infDPS/subst",
-			Message: "subst must be '1' (substitution) or '2' (no substitution)",
+			Element: "subst/cMotivo",
+			Message: "cMotivo must be one of 01, 02, 03, 04, 05 or 99",
 			Value:   value,
 		})
 	}
 
+	// Validate xMotivo (substitution reason) - optional, 15-255 characters
+	if xMotivo := subst.FindElement("xMotivo"); xMotivo != nil {
+		length := utf8.RuneCountInString(strings.TrimSpace(xMotivo.Text()))
+		if length < EventReasonMinLength || length > EventReasonMaxLength {
+			errors = append(errors, XSDValidationError{
+				Code:    XSDErrorInvalidFormat,
+				Element: "subst/xMotivo",
+				Message: "xMotivo must have between 15 and 255 characters",
+			})
+		}
+	}
+
 	return errors
 }
 
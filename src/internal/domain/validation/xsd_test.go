@@ -19,7 +19,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000190</CNPJ>
       <xNome>Provider Company Ltd</xNome>
@@ -46,7 +45,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000190</CNPJ>
     </prest>
@@ -76,7 +74,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000190</CNPJ>
     </prest>
@@ -221,7 +218,6 @@ func TestXSDValidator_ValidateDPS_MissingNamespace(t *testing.T) {
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000190</CNPJ>
     </prest>
@@ -251,6 +247,64 @@ func TestXSDValidator_ValidateDPS_MissingNamespace(t *testing.T) {
 	}
 }
 
+func TestXSDValidator_ValidateDPS_Substitution(t *testing.T) {
+	validSubst := `<subst>
+      <chSubstda>NFSe3550308202601081123456789012300000000000012310</chSubstda>
+      <cMotivo>99</cMotivo>
+      <xMotivo>Correcao da descricao do servico</xMotivo>
+    </subst>`
+
+	tests := []struct {
+		name            string
+		subst           string
+		expectedElement string
+	}{
+		{name: "valid substitution group", subst: validSubst},
+		{
+			name:            "missing chSubstda",
+			subst:           `<subst><cMotivo>01</cMotivo></subst>`,
+			expectedElement: "subst/chSubstda",
+		},
+		{
+			name:            "invalid cMotivo",
+			subst:           `<subst><chSubstda>NFSe3550308202601081123456789012300000000000012310</chSubstda><cMotivo>2</cMotivo></subst>`,
+			expectedElement: "subst/cMotivo",
+		},
+		{
+			name:            "xMotivo too short",
+			subst:           `<subst><chSubstda>NFSe3550308202601081123456789012300000000000012310</chSubstda><cMotivo>99</cMotivo><xMotivo>curto</xMotivo></subst>`,
+			expectedElement: "subst/xMotivo",
+		},
+	}
+
+	validator, _ := NewXSDValidator("")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xml := strings.Replace(validDPSXML, "<prest>", tt.subst+"\n    <prest>", 1)
+			errors := validator.ValidateDPS(xml)
+
+			if tt.expectedElement == "" {
+				if len(errors) != 0 {
+					t.Errorf("Expected no errors, got: %v", errors)
+				}
+				return
+			}
+
+			found := false
+			for _, e := range errors {
+				if e.Element == tt.expectedElement {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("Expected error for %s, got: %v", tt.expectedElement, errors)
+			}
+		})
+	}
+}
+
 func TestXSDValidationError_Error(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,577 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// distributionCursorsCollection is the name of the DF-e distribution cursors collection.
+	distributionCursorsCollection = "distribution_cursors"
+
+	// receivedDocumentsCollection is the name of the collection of documents
+	// received through DF-e distribution.
+	receivedDocumentsCollection = "received_documents"
+
+	// receivedNFSeDocumentType is the document type of received NFS-e (as
+	// opposed to events).
+	receivedNFSeDocumentType = "NFSE"
+)
+
+var (
+	// ErrDistributionCursorNotFound is returned when a distribution cursor is not found.
+	ErrDistributionCursorNotFound = errors.New("distribution cursor not found")
+
+	// ErrDistributionCursorExists is returned when the CNPJ is already registered
+	// for distribution by another API key.
+	ErrDistributionCursorExists = errors.New("CNPJ already registered for distribution")
+
+	// ErrReceivedDocumentNotFound is returned when a received document is not found.
+	ErrReceivedDocumentNotFound = errors.New("received document not found")
+)
+
+// DistributionCursor represents a CNPJ registered for ADN DF-e distribution and
+// the NSU up to which its documents were downloaded.
+type DistributionCursor struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	CNPJ        string             `bson:"cnpj"`
+	APIKeyID    primitive.ObjectID `bson:"api_key_id"`
+	Environment string             `bson:"environment"`
+	Active      bool               `bson:"active"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+
+	// Certificate of the CNPJ, kept for mTLS with the ADN
+	Certificate *CertificateData `bson:"certificate,omitempty"`
+
+	// Webhook configuration
+	WebhookURL string `bson:"webhook_url,omitempty"`
+
+	// LastNSU is the greatest NSU already stored for the CNPJ (ultNSU).
+	LastNSU int64 `bson:"last_nsu"`
+
+	// Sync tracking
+	LastSyncAt *time.Time `bson:"last_sync_at,omitempty"`
+	LastError  string     `bson:"last_error,omitempty"`
+
+	// NextSyncAt is the earliest time the CNPJ may be synced again. It is set
+	// when the ADN has no more documents, which requires waiting before polling.
+	NextSyncAt *time.Time `bson:"next_sync_at,omitempty"`
+}
+
+// IsBackingOff reports whether the CNPJ must not be synced yet.
+func (c *DistributionCursor) IsBackingOff(now time.Time) bool {
+	return c.NextSyncAt != nil && c.NextSyncAt.After(now)
+}
+
+// ReceivedDocument represents an NFS-e or event downloaded through DF-e distribution.
+type ReceivedDocument struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	CNPJ       string             `bson:"cnpj"`
+	NSU        int64              `bson:"nsu"`
+	APIKeyID   primitive.ObjectID `bson:"api_key_id"`
+	ReceivedAt time.Time          `bson:"received_at"`
+
+	// AccessKey is the access key of the NFS-e the document refers to.
+	AccessKey string `bson:"access_key"`
+
+	// DocumentType is NFSE or EVENTO.
+	DocumentType string `bson:"document_type"`
+
+	// EventType is the event type code (only for events).
+	EventType string `bson:"event_type,omitempty"`
+
+	// XML is the decompressed document.
+	XML string `bson:"xml"`
+
+	// GeneratedAt is when the document was generated in the ADN.
+	GeneratedAt time.Time `bson:"generated_at,omitempty"`
+
+	// Signature verification result
+	Signature SignatureCheck `bson:"signature"`
+
+	// NFSe contains the fields of the NFS-e used by the inbox filters (only for NFS-e).
+	NFSe *ReceivedNFSe `bson:"nfse,omitempty"`
+}
+
+// ReceivedNFSe contains the data parsed from a received NFS-e and its current
+// status, derived from the events received for it.
+type ReceivedNFSe struct {
+	IssuerCNPJ       string    `bson:"issuer_cnpj"`
+	TakerDocument    string    `bson:"taker_document,omitempty"`
+	Competence       time.Time `bson:"competence"`
+	ServiceValue     float64   `bson:"service_value"`
+	MunicipalityCode string    `bson:"municipality_code,omitempty"`
+	Status           string    `bson:"status"`
+}
+
+// SignatureCheck contains the result of the signature verification of a received document.
+type SignatureCheck struct {
+	Valid     bool      `bson:"valid"`
+	SignerCN  string    `bson:"signer_cn,omitempty"`
+	Errors    []string  `bson:"errors,omitempty"`
+	CheckedAt time.Time `bson:"checked_at"`
+}
+
+// DistributionRepository provides access to DF-e distribution cursors and
+// received documents in MongoDB.
+type DistributionRepository struct {
+	cursors   *mongo.Collection
+	documents *mongo.Collection
+}
+
+// NewDistributionRepository creates a new distribution repository.
+func NewDistributionRepository(client *Client) *DistributionRepository {
+	return &DistributionRepository{
+		cursors:   client.GetCollection(distributionCursorsCollection),
+		documents: client.GetCollection(receivedDocumentsCollection),
+	}
+}
+
+// RegisterCursor registers a CNPJ for distribution, or updates the certificate,
+// webhook and active flag of an existing registration of the same API key.
+// The NSU of an existing registration is preserved, so the sync resumes where it stopped.
+func (r *DistributionRepository) RegisterCursor(ctx context.Context, cursor *DistributionCursor) error {
+	if cursor == nil {
+		return fmt.Errorf("distribution cursor cannot be nil")
+	}
+
+	if cursor.CNPJ == "" {
+		return fmt.Errorf("CNPJ is required")
+	}
+
+	if cursor.APIKeyID.IsZero() {
+		return fmt.Errorf("API key ID is required")
+	}
+
+	existing, err := r.FindCursorByCNPJ(ctx, cursor.CNPJ)
+	if err != nil && !errors.Is(err, ErrDistributionCursorNotFound) {
+		return err
+	}
+	if existing != nil && existing.APIKeyID != cursor.APIKeyID {
+		return ErrDistributionCursorExists
+	}
+
+	now := time.Now().UTC()
+	cursor.UpdatedAt = now
+
+	filter := bson.M{"cnpj": cursor.CNPJ}
+	update := bson.M{
+		"$set": bson.M{
+			"api_key_id":  cursor.APIKeyID,
+			"environment": cursor.Environment,
+			"active":      cursor.Active,
+			"certificate": cursor.Certificate,
+			"webhook_url": cursor.WebhookURL,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{
+			"last_nsu":   cursor.LastNSU,
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	if err := r.cursors.FindOneAndUpdate(ctx, filter, update, opts).Decode(cursor); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDistributionCursorExists
+		}
+		return fmt.Errorf("failed to register distribution cursor: %w", err)
+	}
+
+	return nil
+}
+
+// FindCursorByCNPJ retrieves the distribution cursor of a CNPJ.
+func (r *DistributionRepository) FindCursorByCNPJ(ctx context.Context, cnpj string) (*DistributionCursor, error) {
+	if cnpj == "" {
+		return nil, fmt.Errorf("CNPJ cannot be empty")
+	}
+
+	var cursor DistributionCursor
+	err := r.cursors.FindOne(ctx, bson.M{"cnpj": cnpj}).Decode(&cursor)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDistributionCursorNotFound
+		}
+		return nil, fmt.Errorf("failed to find distribution cursor: %w", err)
+	}
+
+	return &cursor, nil
+}
+
+// FindDueCursors retrieves the cursors of the active CNPJs that may be synced
+// at the given time, oldest sync first.
+func (r *DistributionRepository) FindDueCursors(ctx context.Context, now time.Time) ([]*DistributionCursor, error) {
+	filter := bson.M{
+		"active": true,
+		"$or": []bson.M{
+			{"next_sync_at": bson.M{"$exists": false}},
+			{"next_sync_at": bson.M{"$lte": now}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "last_sync_at", Value: 1}})
+
+	cur, err := r.cursors.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find distribution cursors: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var items []*DistributionCursor
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode distribution cursors: %w", err)
+	}
+
+	return items, nil
+}
+
+// UpdateCursorProgress records the sync of a CNPJ. The last NSU never moves
+// backwards, and lastError clears any previous error when empty.
+func (r *DistributionRepository) UpdateCursorProgress(ctx context.Context, cnpj string, lastNSU int64, lastError string) error {
+	if cnpj == "" {
+		return fmt.Errorf("CNPJ cannot be empty")
+	}
+
+	now := time.Now().UTC()
+	filter := bson.M{"cnpj": cnpj}
+	set := bson.M{
+		"last_sync_at": now,
+		"updated_at":   now,
+	}
+	update := bson.M{
+		"$set": set,
+		"$max": bson.M{
+			"last_nsu": lastNSU,
+		},
+	}
+	if lastError != "" {
+		set["last_error"] = lastError
+	} else {
+		update["$unset"] = bson.M{"last_error": ""}
+	}
+
+	result, err := r.cursors.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update distribution cursor: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrDistributionCursorNotFound
+	}
+
+	return nil
+}
+
+// UpdateNextSync sets the earliest time the CNPJ may be synced again.
+// A nil time allows the next poll to sync it.
+func (r *DistributionRepository) UpdateNextSync(ctx context.Context, cnpj string, nextSyncAt *time.Time) error {
+	if cnpj == "" {
+		return fmt.Errorf("CNPJ cannot be empty")
+	}
+
+	filter := bson.M{"cnpj": cnpj}
+	set := bson.M{"updated_at": time.Now().UTC()}
+	update := bson.M{"$set": set}
+	if nextSyncAt != nil {
+		set["next_sync_at"] = *nextSyncAt
+	} else {
+		update["$unset"] = bson.M{"next_sync_at": ""}
+	}
+
+	result, err := r.cursors.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update distribution cursor: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrDistributionCursorNotFound
+	}
+
+	return nil
+}
+
+// SaveDocument stores a received document. Saving the same NSU of a CNPJ again
+// replaces the stored document, so a batch can be safely reprocessed.
+func (r *DistributionRepository) SaveDocument(ctx context.Context, doc *ReceivedDocument) error {
+	if doc == nil {
+		return fmt.Errorf("received document cannot be nil")
+	}
+
+	if doc.CNPJ == "" {
+		return fmt.Errorf("CNPJ is required")
+	}
+
+	if doc.ReceivedAt.IsZero() {
+		doc.ReceivedAt = time.Now().UTC()
+	}
+
+	filter := bson.M{"cnpj": doc.CNPJ, "nsu": doc.NSU}
+	opts := options.Replace().SetUpsert(true)
+
+	if _, err := r.documents.ReplaceOne(ctx, filter, doc, opts); err != nil {
+		return fmt.Errorf("failed to save received document: %w", err)
+	}
+
+	return nil
+}
+
+// FindDocumentsByAccessKey retrieves the documents received by a CNPJ for an NFS-e,
+// in NSU order.
+func (r *DistributionRepository) FindDocumentsByAccessKey(ctx context.Context, cnpj, accessKey string) ([]*ReceivedDocument, error) {
+	if accessKey == "" {
+		return nil, fmt.Errorf("access key cannot be empty")
+	}
+
+	filter := bson.M{"cnpj": cnpj, "access_key": accessKey}
+	opts := options.Find().SetSort(bson.D{{Key: "nsu", Value: 1}})
+
+	cur, err := r.documents.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find received documents: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var items []*ReceivedDocument
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode received documents: %w", err)
+	}
+
+	if len(items) == 0 {
+		return nil, ErrReceivedDocumentNotFound
+	}
+
+	return items, nil
+}
+
+// UpdateNFSeStatus sets the status of an NFS-e received by a CNPJ.
+func (r *DistributionRepository) UpdateNFSeStatus(ctx context.Context, cnpj, accessKey, status string) error {
+	filter := bson.M{"cnpj": cnpj, "access_key": accessKey, "nfse": bson.M{"$exists": true}}
+	update := bson.M{"$set": bson.M{"nfse.status": status}}
+
+	result, err := r.documents.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update received NFS-e status: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrReceivedDocumentNotFound
+	}
+
+	return nil
+}
+
+// InboxFilter contains the filters for listing the NFS-e received by the CNPJs
+// of an API key. Empty fields are not filtered.
+type InboxFilter struct {
+	APIKeyID         primitive.ObjectID
+	CNPJ             string
+	IssuerCNPJ       string
+	CompetenceFrom   *time.Time
+	CompetenceTo     *time.Time
+	MinValue         *float64
+	MaxValue         *float64
+	Status           string
+	MunicipalityCode string
+}
+
+// InboxResult contains a page of received NFS-e.
+type InboxResult struct {
+	Items      []*ReceivedDocument
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+// FindInbox retrieves the NFS-e received by the CNPJs of an API key with
+// pagination, latest competence first.
+func (r *DistributionRepository) FindInbox(ctx context.Context, filter InboxFilter, params PaginationParams) (*InboxResult, error) {
+	if filter.APIKeyID.IsZero() {
+		return nil, fmt.Errorf("API key ID cannot be empty")
+	}
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	query := bson.M{
+		"api_key_id":    filter.APIKeyID,
+		"document_type": receivedNFSeDocumentType,
+	}
+	if filter.CNPJ != "" {
+		query["cnpj"] = filter.CNPJ
+	}
+	if filter.IssuerCNPJ != "" {
+		query["nfse.issuer_cnpj"] = filter.IssuerCNPJ
+	}
+	if filter.Status != "" {
+		query["nfse.status"] = filter.Status
+	}
+	if filter.MunicipalityCode != "" {
+		query["nfse.municipality_code"] = filter.MunicipalityCode
+	}
+
+	competence := bson.M{}
+	if filter.CompetenceFrom != nil {
+		competence["$gte"] = *filter.CompetenceFrom
+	}
+	if filter.CompetenceTo != nil {
+		competence["$lte"] = *filter.CompetenceTo
+	}
+	if len(competence) > 0 {
+		query["nfse.competence"] = competence
+	}
+
+	value := bson.M{}
+	if filter.MinValue != nil {
+		value["$gte"] = *filter.MinValue
+	}
+	if filter.MaxValue != nil {
+		value["$lte"] = *filter.MaxValue
+	}
+	if len(value) > 0 {
+		query["nfse.service_value"] = value
+	}
+
+	// Get total count
+	totalCount, err := r.documents.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count received documents: %w", err)
+	}
+
+	// Calculate pagination
+	skip := (params.Page - 1) * params.PageSize
+	totalPages := (totalCount + params.PageSize - 1) / params.PageSize
+
+	// Find with pagination
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(params.PageSize).
+		SetSort(bson.D{{Key: "nfse.competence", Value: -1}, {Key: "nsu", Value: -1}})
+
+	cur, err := r.documents.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find received documents: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var items []*ReceivedDocument
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode received documents: %w", err)
+	}
+
+	return &InboxResult{
+		Items:      items,
+		TotalCount: totalCount,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// FindInboxNFSe retrieves an NFS-e received by any CNPJ of an API key.
+func (r *DistributionRepository) FindInboxNFSe(ctx context.Context, apiKeyID primitive.ObjectID, accessKey string) (*ReceivedDocument, error) {
+	if accessKey == "" {
+		return nil, fmt.Errorf("access key cannot be empty")
+	}
+
+	filter := bson.M{
+		"api_key_id":    apiKeyID,
+		"access_key":    accessKey,
+		"document_type": receivedNFSeDocumentType,
+	}
+
+	var doc ReceivedDocument
+	err := r.documents.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrReceivedDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to find received document: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// EnsureIndexes creates the necessary indexes for the distribution collections.
+func (r *DistributionRepository) EnsureIndexes(ctx context.Context) error {
+	cursorIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "cnpj", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "api_key_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "active", Value: 1},
+				{Key: "next_sync_at", Value: 1},
+			},
+		},
+	}
+
+	if _, err := r.cursors.Indexes().CreateMany(ctx, cursorIndexes); err != nil {
+		return fmt.Errorf("failed to create distribution cursor indexes: %w", err)
+	}
+
+	documentIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "cnpj", Value: 1},
+				{Key: "nsu", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "cnpj", Value: 1},
+				{Key: "access_key", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "api_key_id", Value: 1},
+				{Key: "document_type", Value: 1},
+				{Key: "generated_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "api_key_id", Value: 1},
+				{Key: "document_type", Value: 1},
+				{Key: "nfse.competence", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "api_key_id", Value: 1},
+				{Key: "nfse.issuer_cnpj", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "access_key", Value: 1}},
+		},
+	}
+
+	if _, err := r.documents.Indexes().CreateMany(ctx, documentIndexes); err != nil {
+		return fmt.Errorf("failed to create received document indexes: %w", err)
+	}
+
+	return nil
+}
@@ -38,15 +38,35 @@ type EmissionRequest struct {
 	// Taker information (optional)
 	Taker *TakerData `bson:"taker,omitempty"`
 
+	// Intermediary information (optional)
+	Intermediary *IntermediaryData `bson:"intermediary,omitempty"`
+
 	// Service information
 	Service ServiceData `bson:"service"`
 
 	// Monetary values
 	Values ValuesData `bson:"values"`
 
+	// ISS rate used in the DPS and where it came from (set when the DPS is built)
+	ISSRate *ISSRateData `bson:"iss_rate,omitempty"`
+
+	// Approximate tax burden (totTrib) used in the DPS and the table it came from (set when the DPS is built)
+	TotalTaxes *TotalTaxesData `bson:"total_taxes,omitempty"`
+
 	// DPS information
 	DPS DPSData `bson:"dps"`
 
+	// EmitterType identifies who emits the DPS (tpEmit); 0 means the provider
+	EmitterType int `bson:"emitter_type,omitempty"`
+
+	// Reason for the taker or intermediary to emit the DPS (cMotivoEmisTI) and the
+	// access key of the NFS-e they rejected (chNFSeRej), if provided
+	EmissionReason    int    `bson:"emission_reason,omitempty"`
+	RejectedAccessKey string `bson:"rejected_access_key,omitempty"`
+
+	// Substitution information (only when replacing an existing NFS-e)
+	Substitution *SubstitutionData `bson:"substitution,omitempty"`
+
 	// Certificate information (optional, for signed emissions)
 	Certificate *CertificateData `bson:"certificate,omitempty"`
 
@@ -63,6 +83,9 @@ type EmissionRequest struct {
 	// Rejection (only on failure)
 	Rejection *RejectionInfo `bson:"rejection,omitempty"`
 
+	// Lifecycle tracks the fiscal state of the emitted NFS-e (only on success)
+	Lifecycle *LifecycleData `bson:"lifecycle,omitempty"`
+
 	// Pre-signed XML fields (Phase 5 - User Story 3)
 	// IsPreSigned indicates if this request was submitted with pre-signed XML.
 	IsPreSigned bool `bson:"is_presigned"`
@@ -75,25 +98,99 @@ type EmissionRequest struct {
 
 // ProviderData contains provider information for storage.
 type ProviderData struct {
-	CNPJ                  string `bson:"cnpj"`
+	CNPJ                  string `bson:"cnpj,omitempty"`
+	CPF                   string `bson:"cpf,omitempty"`
 	TaxRegime             string `bson:"tax_regime"`
 	Name                  string `bson:"name"`
 	MunicipalRegistration string `bson:"municipal_registration,omitempty"`
+
+	// Simples Nacional calculation regime (regApTribSN) and special regime (regEspTrib), if provided
+	SimplesCalculationRegime int `bson:"simples_calculation_regime,omitempty"`
+	SpecialRegime            int `bson:"special_regime,omitempty"`
+
+	// Address is only kept when the taker or intermediary emits the DPS
+	Address *AddressData `bson:"address,omitempty"`
 }
 
 // TakerData contains taker information for storage.
 type TakerData struct {
-	CNPJ string `bson:"cnpj,omitempty"`
-	CPF  string `bson:"cpf,omitempty"`
-	NIF  string `bson:"nif,omitempty"`
-	Name string `bson:"name"`
+	CNPJ    string       `bson:"cnpj,omitempty"`
+	CPF     string       `bson:"cpf,omitempty"`
+	NIF     string       `bson:"nif,omitempty"`
+	Name    string       `bson:"name"`
+	Phone   string       `bson:"phone,omitempty"`
+	Email   string       `bson:"email,omitempty"`
+	Address *AddressData `bson:"address,omitempty"`
+}
+
+// IntermediaryData contains intermediary information for storage.
+type IntermediaryData struct {
+	CNPJ    string       `bson:"cnpj,omitempty"`
+	CPF     string       `bson:"cpf,omitempty"`
+	NIF     string       `bson:"nif,omitempty"`
+	Name    string       `bson:"name"`
+	Phone   string       `bson:"phone,omitempty"`
+	Email   string       `bson:"email,omitempty"`
+	Address *AddressData `bson:"address,omitempty"`
+}
+
+// AddressData contains address information for storage.
+// National addresses use MunicipalityCode, State and PostalCode (CEP); foreign
+// addresses use CountryCode, PostalCode (cEndPost), City and Region.
+type AddressData struct {
+	Street           string `bson:"street"`
+	Number           string `bson:"number"`
+	Complement       string `bson:"complement,omitempty"`
+	Neighborhood     string `bson:"neighborhood"`
+	MunicipalityCode string `bson:"municipality_code,omitempty"`
+	State            string `bson:"state,omitempty"`
+	PostalCode       string `bson:"postal_code,omitempty"`
+	City             string `bson:"city,omitempty"`
+	Region           string `bson:"region,omitempty"`
+	CountryCode      string `bson:"country_code,omitempty"`
 }
 
 // ServiceData contains service information for storage.
 type ServiceData struct {
-	NationalCode     string `bson:"national_code"`
-	Description      string `bson:"description"`
-	MunicipalityCode string `bson:"municipality_code"`
+	NationalCode     string            `bson:"national_code"`
+	Description      string            `bson:"description"`
+	MunicipalityCode string            `bson:"municipality_code"`
+	NBSCode          string            `bson:"nbs_code,omitempty"`
+	MunicipalTaxCode string            `bson:"municipal_tax_code,omitempty"`
+	CountryCode      string            `bson:"country_code,omitempty"`
+	ForeignTrade     *ForeignTradeData `bson:"foreign_trade,omitempty"`
+	Construction     *ConstructionData `bson:"construction,omitempty"`
+	Event            *EventData        `bson:"event,omitempty"`
+}
+
+// ForeignTradeData contains the foreign trade information (comExt) of the service for storage.
+type ForeignTradeData struct {
+	ServiceMode              int     `bson:"service_mode"`
+	Relationship             int     `bson:"relationship"`
+	CurrencyCode             string  `bson:"currency_code"`
+	ForeignValue             float64 `bson:"foreign_value"`
+	ProviderSupportMechanism string  `bson:"provider_support_mechanism"`
+	TakerSupportMechanism    string  `bson:"taker_support_mechanism"`
+	TemporaryGoods           int     `bson:"temporary_goods"`
+	ImportDeclaration        string  `bson:"import_declaration,omitempty"`
+	ExportRegistration       string  `bson:"export_registration,omitempty"`
+	ShareWithMDIC            bool    `bson:"share_with_mdic"`
+}
+
+// ConstructionData contains the construction site (obra) of the service for storage.
+type ConstructionData struct {
+	Code                 string       `bson:"code,omitempty"`
+	PropertyRegistration string       `bson:"property_registration,omitempty"`
+	Address              *AddressData `bson:"address,omitempty"`
+}
+
+// EventData contains the event (atvEvento) of the service for storage.
+type EventData struct {
+	Name      string       `bson:"name"`
+	StartDate time.Time    `bson:"start_date"`
+	EndDate   time.Time    `bson:"end_date"`
+	ID        string       `bson:"id,omitempty"`
+	Address   *AddressData `bson:"address,omitempty"`
 }
 
 // ValuesData contains monetary values for storage.
@@ -102,6 +199,127 @@ type ValuesData struct {
 	UnconditionalDiscount float64 `bson:"unconditional_discount,omitempty"`
 	ConditionalDiscount   float64 `bson:"conditional_discount,omitempty"`
 	Deductions            float64 `bson:"deductions,omitempty"`
+
+	// Documents itemizing the deductions (docDedRed), if provided
+	DeductionDocuments []DeductionDocumentData `bson:"deduction_documents,omitempty"`
+
+	// Municipal benefit (nBM) claimed by the provider, checked against the municipal record
+	MunicipalBenefitNumber string `bson:"municipal_benefit_number,omitempty"`
+
+	// Tax base reduction granted by the municipal benefit (pRedBCBM or vRedBCBM), if provided
+	MunicipalBenefitReductionPercentage float64 `bson:"municipal_benefit_reduction_percentage,omitempty"`
+	MunicipalBenefitReductionValue      float64 `bson:"municipal_benefit_reduction_value,omitempty"`
+
+	// Municipal tax (tribMun) treatment, if provided; defaults to taxable and not withheld
+	ISS *ISSData `bson:"iss,omitempty"`
+
+	// Federal tax rates (tribFed), if provided; amounts are calculated when the DPS is built
+	FederalTaxes *FederalTaxesData `bson:"federal_taxes,omitempty"`
+
+	// Approximate Simples Nacional tax percentage (pTotTribSN), if provided
+	SimplesTaxRate float64 `bson:"simples_tax_rate,omitempty"`
+}
+
+// ISSData contains the municipal tax (tribMun) treatment for storage.
+type ISSData struct {
+	Taxation          int                `bson:"taxation,omitempty"`
+	ResultCountryCode string             `bson:"result_country_code,omitempty"`
+	ImmunityType      int                `bson:"immunity_type,omitempty"`
+	Suspension        *ISSSuspensionData `bson:"suspension,omitempty"`
+	Withholding       int                `bson:"withholding,omitempty"`
+}
+
+// ISSSuspensionData contains the suspended exigibility of the ISS (exigSusp) for storage.
+type ISSSuspensionData struct {
+	Type          int    `bson:"type"`
+	ProcessNumber string `bson:"process_number"`
+}
+
+// FederalTaxesData contains the federal tax rates (tribFed) for storage.
+type FederalTaxesData struct {
+	PisCofins *PisCofinsData `bson:"pis_cofins,omitempty"`
+	CPRate    float64        `bson:"cp_rate,omitempty"`
+	IRRFRate  float64        `bson:"irrf_rate,omitempty"`
+	CSLLRate  float64        `bson:"csll_rate,omitempty"`
+}
+
+// PisCofinsData contains the PIS/COFINS taxes (piscofins) for storage.
+type PisCofinsData struct {
+	CST        string  `bson:"cst"`
+	TaxBase    float64 `bson:"tax_base,omitempty"`
+	PISRate    float64 `bson:"pis_rate,omitempty"`
+	COFINSRate float64 `bson:"cofins_rate,omitempty"`
+	Withheld   bool    `bson:"withheld"`
+}
+
+// DeductionDocumentData contains a document supporting a deduction (docDedRed) for storage.
+type DeductionDocumentData struct {
+	NFSeKey              string             `bson:"nfse_key,omitempty"`
+	NFeKey               string             `bson:"nfe_key,omitempty"`
+	MunicipalNFSe        *MunicipalNFSeData `bson:"municipal_nfse,omitempty"`
+	PaperInvoice         *PaperInvoiceData  `bson:"paper_invoice,omitempty"`
+	FiscalDocumentNumber string             `bson:"fiscal_document_number,omitempty"`
+	DocumentNumber       string             `bson:"document_number,omitempty"`
+	Type                 int                `bson:"type"`
+	TypeDescription      string             `bson:"type_description,omitempty"`
+	IssueDate            time.Time          `bson:"issue_date"`
+	DeductibleValue      float64            `bson:"deductible_value"`
+	DeductionValue       float64            `bson:"deduction_value"`
+}
+
+// MunicipalNFSeData identifies an NFS-e issued in a municipal standard (NFSeMun) for storage.
+type MunicipalNFSeData struct {
+	MunicipalityCode string `bson:"municipality_code"`
+	Number           string `bson:"number"`
+	VerificationCode string `bson:"verification_code"`
+}
+
+// PaperInvoiceData identifies a non-electronic NF or NFS (NFNFS) for storage.
+type PaperInvoiceData struct {
+	Number string `bson:"number"`
+	Model  string `bson:"model"`
+	Series string `bson:"series"`
+}
+
+// ISSRateData records the ISS rate used in the DPS, for audit.
+type ISSRateData struct {
+	// Rate is the ISS rate percentage (pAliq).
+	Rate float64 `bson:"rate"`
+
+	// Source indicates where the rate came from (see emission.ISSRateSource* constants).
+	Source string `bson:"source"`
+
+	// MunicipalTaxCode is the municipal tax code of the service (cTribMun), if any.
+	MunicipalTaxCode string `bson:"municipal_tax_code,omitempty"`
+
+	// ConsultedAt is when the municipal parameters were retrieved, if they were.
+	ConsultedAt *time.Time `bson:"consulted_at,omitempty"`
+}
+
+// TotalTaxesData records the approximate tax burden (totTrib) used in the DPS, for audit.
+type TotalTaxesData struct {
+	// Source indicates where the tax burden came from (see emission.TotalTaxesSource* constants).
+	Source string `bson:"source"`
+
+	// State, CodeType and Code identify the table entry looked up.
+	State    string `bson:"state,omitempty"`
+	CodeType string `bson:"code_type,omitempty"`
+	Code     string `bson:"code,omitempty"`
+
+	// TableVersion and TableSource identify the table the rates came from.
+	TableVersion string `bson:"table_version,omitempty"`
+	TableSource  string `bson:"table_source,omitempty"`
+
+	// Approximate tax percentages and amounts by jurisdiction (vTotTrib)
+	FederalRate     float64 `bson:"federal_rate,omitempty"`
+	StateRate       float64 `bson:"state_rate,omitempty"`
+	MunicipalRate   float64 `bson:"municipal_rate,omitempty"`
+	FederalAmount   float64 `bson:"federal_amount,omitempty"`
+	StateAmount     float64 `bson:"state_amount,omitempty"`
+	MunicipalAmount float64 `bson:"municipal_amount,omitempty"`
+
+	// SimplesRate is the approximate Simples Nacional percentage (pTotTribSN).
+	SimplesRate float64 `bson:"simples_rate,omitempty"`
 }
 
 // DPSData contains DPS information for storage.
@@ -110,6 +328,28 @@ type DPSData struct {
 	Number string `bson:"number"`
 }
 
+// SubstitutionData links a substitute emission to the NFS-e it replaces.
+type SubstitutionData struct {
+	// ReplacedAccessKey is the access key of the NFS-e being replaced (chSubstda).
+	ReplacedAccessKey string `bson:"replaced_access_key"`
+
+	// ReasonCode is the substitution reason (cMotivo).
+	ReasonCode string `bson:"reason_code"`
+
+	// Reason is the free-text justification (xMotivo), if any.
+	Reason string `bson:"reason,omitempty"`
+
+	// CancellationSequence is the sequence number (nSeqEvento) of the cancellation by
+	// substitution (e105102) the government API registered for the replaced NFS-e,
+	// if it was found after the substitute NFS-e was emitted.
+	CancellationSequence int `bson:"cancellation_sequence,omitempty"`
+
+	// CancelledAt is when the replaced NFS-e was cancelled: the processing date of the
+	// e105102 event, or the emission of the substitute when the event was not found.
+	// It is set once the substitute NFS-e has been emitted.
+	CancelledAt time.Time `bson:"cancelled_at,omitempty"`
+}
+
 // CertificateData contains certificate information for storage.
 // Note: We store only metadata, not the actual certificate data for security.
 type CertificateData struct {
@@ -147,6 +387,52 @@ type EmissionResult struct {
 	NFSeXMLURL    string `bson:"nfse_xml_url,omitempty"`
 }
 
+// LifecycleData contains the current lifecycle of an emitted NFS-e, updated from
+// the events registered through the API or discovered in the government API.
+type LifecycleData struct {
+	// Status is the current lifecycle status (see event.Lifecycle* constants).
+	Status string `bson:"status"`
+
+	// PreviousStatus is the status to restore when the blocks and cancellation review end.
+	PreviousStatus string `bson:"previous_status,omitempty"`
+
+	// UnderReview reports whether a cancellation awaits fiscal review.
+	UnderReview bool `bson:"under_review,omitempty"`
+
+	// BlockedEvents lists the event types blocked ex officio and not unblocked yet.
+	BlockedEvents []string `bson:"blocked_events,omitempty"`
+
+	// LastEventType is the type of the last event that changed the lifecycle.
+	LastEventType string `bson:"last_event_type,omitempty"`
+
+	// ReplacedBy is the access key of the substitute NFS-e, once the NFS-e was
+	// cancelled by substitution.
+	ReplacedBy string `bson:"replaced_by,omitempty"`
+
+	// UpdatedAt is when the lifecycle was last updated.
+	UpdatedAt time.Time `bson:"updated_at"`
+
+	// History lists every event applied to the lifecycle, in the order applied.
+	History []LifecycleTransition `bson:"history,omitempty"`
+}
+
+// LifecycleTransition records an event applied to the lifecycle of an NFS-e.
+type LifecycleTransition struct {
+	EventType  string    `bson:"event_type"`
+	Sequence   int       `bson:"sequence"`
+	FromStatus string    `bson:"from_status"`
+	ToStatus   string    `bson:"to_status"`
+	OccurredAt time.Time `bson:"occurred_at,omitempty"`
+	RecordedAt time.Time `bson:"recorded_at"`
+	Source     string    `bson:"source"`
+
+	// EventID is the event identifier, kept for blocks so that their unblock can be matched.
+	EventID string `bson:"event_id,omitempty"`
+
+	// BlockedEventType is the event type blocked or unblocked ex officio (e305102/e305103).
+	BlockedEventType string `bson:"blocked_event_type,omitempty"`
+}
+
 // RejectionInfo contains information about a failed emission.
 type RejectionInfo struct {
 	Code           string `bson:"code"`
@@ -274,6 +560,7 @@ func (r *EmissionRepository) UpdateResult(ctx context.Context, requestID string,
 		"$set": bson.M{
 			"status":       "success",
 			"result":       result,
+			"lifecycle":    &LifecycleData{Status: "active", UpdatedAt: now},
 			"updated_at":   now,
 			"processed_at": now,
 		},
@@ -363,6 +650,205 @@ func (r *EmissionRepository) UpdateSigningStatus(ctx context.Context, requestID
 	return nil
 }
 
+// ClearCredentials removes the stored certificate credentials of an emission request
+// that reached a final state without its DPS being signed.
+func (r *EmissionRepository) ClearCredentials(ctx context.Context, requestID string) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now().UTC(),
+		},
+		"$unset": bson.M{
+			"certificate.pfx_base64": "",
+			"certificate.password":   "",
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to clear certificate credentials: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEmissionRequestNotFound
+	}
+
+	return nil
+}
+
+// FindByAccessKey retrieves the successful emission request that produced the
+// NFS-e with the given access key.
+// Returns ErrEmissionRequestNotFound if the NFS-e was not emitted through this API.
+func (r *EmissionRepository) FindByAccessKey(ctx context.Context, accessKey string) (*EmissionRequest, error) {
+	if accessKey == "" {
+		return nil, fmt.Errorf("access key cannot be empty")
+	}
+
+	filter := bson.M{"result.nfse_access_key": accessKey}
+
+	var req EmissionRequest
+	err := r.collection.FindOne(ctx, filter).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmissionRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to find emission request: %w", err)
+	}
+
+	return &req, nil
+}
+
+// UpdateLifecycle replaces the lifecycle of an emission request.
+func (r *EmissionRepository) UpdateLifecycle(ctx context.Context, requestID string, lifecycle *LifecycleData) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	if lifecycle == nil {
+		return fmt.Errorf("lifecycle cannot be nil")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"lifecycle":  lifecycle,
+			"updated_at": time.Now().UTC(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update lifecycle: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEmissionRequestNotFound
+	}
+
+	return nil
+}
+
+// UpdateISSRate records the ISS rate used in the DPS of an emission request.
+func (r *EmissionRepository) UpdateISSRate(ctx context.Context, requestID string, issRate *ISSRateData) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	if issRate == nil {
+		return fmt.Errorf("ISS rate cannot be nil")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"iss_rate":   issRate,
+			"updated_at": time.Now().UTC(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update ISS rate: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEmissionRequestNotFound
+	}
+
+	return nil
+}
+
+// UpdateTotalTaxes records the approximate tax burden used in the DPS of an emission request.
+func (r *EmissionRepository) UpdateTotalTaxes(ctx context.Context, requestID string, totalTaxes *TotalTaxesData) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	if totalTaxes == nil {
+		return fmt.Errorf("total taxes cannot be nil")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"total_taxes": totalTaxes,
+			"updated_at":  time.Now().UTC(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update total taxes: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEmissionRequestNotFound
+	}
+
+	return nil
+}
+
+// FindActiveReplacement retrieves the most recent substitute emission for the given
+// NFS-e that is still pending, processing or already succeeded.
+// Returns ErrEmissionRequestNotFound if there is none.
+func (r *EmissionRepository) FindActiveReplacement(ctx context.Context, replacedAccessKey string) (*EmissionRequest, error) {
+	if replacedAccessKey == "" {
+		return nil, fmt.Errorf("access key cannot be empty")
+	}
+
+	filter := bson.M{
+		"substitution.replaced_access_key": replacedAccessKey,
+		"status":                           bson.M{"$in": []string{"pending", "processing", "success"}},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var req EmissionRequest
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmissionRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to find emission request: %w", err)
+	}
+
+	return &req, nil
+}
+
+// SetSubstitutionCancellation records on a substitute emission the cancellation by
+// substitution of the NFS-e it replaces. The sequence is zero when the e105102 event
+// was not found.
+func (r *EmissionRepository) SetSubstitutionCancellation(ctx context.Context, requestID string, sequence int, cancelledAt time.Time) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	set := bson.M{
+		"substitution.cancelled_at": cancelledAt,
+		"updated_at":                time.Now().UTC(),
+	}
+	if sequence > 0 {
+		set["substitution.cancellation_sequence"] = sequence
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{"$set": set}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to record substitution cancellation: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEmissionRequestNotFound
+	}
+
+	return nil
+}
+
 // IncrementRetryCount increments the retry counter and updates the last error.
 func (r *EmissionRepository) IncrementRetryCount(ctx context.Context, requestID, lastError string) error {
 	if requestID == "" {
@@ -518,6 +1004,14 @@ func (r *EmissionRepository) EnsureIndexes(ctx context.Context) error {
 				{Key: "created_at", Value: 1},
 			},
 		},
+		{
+			Keys:    bson.D{{Key: "substitution.replaced_access_key", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "result.nfse_access_key", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
@@ -0,0 +1,434 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// eventRequestsCollection is the name of the event requests collection.
+	eventRequestsCollection = "event_requests"
+)
+
+// ErrEventRequestNotFound is returned when an event request is not found.
+var ErrEventRequestNotFound = errors.New("event request not found")
+
+// EventRequest represents an event registration request (pedido de registro de evento)
+// stored in MongoDB.
+type EventRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	RequestID   string             `bson:"request_id"`
+	APIKeyID    primitive.ObjectID `bson:"api_key_id"`
+	Status      string             `bson:"status"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	ProcessedAt *time.Time         `bson:"processed_at,omitempty"`
+	Environment string             `bson:"environment"`
+
+	// NFS-e the event refers to
+	ChaveAcesso string `bson:"chave_acesso"`
+
+	// EventType is the event code (e.g. "e101101")
+	EventType string `bson:"event_type"`
+
+	// Author of the event (CNPJAutor/CPFAutor)
+	Author EventAuthorData `bson:"author"`
+
+	// Cancellation data (only for e101101)
+	Cancellation *CancellationData `bson:"cancellation,omitempty"`
+
+	// Manifestation data (only for confirmation, rejection and annulment events)
+	Manifestation *ManifestationData `bson:"manifestation,omitempty"`
+
+	// Certificate information used to sign the event
+	Certificate *CertificateData `bson:"certificate,omitempty"`
+
+	// Webhook configuration
+	WebhookURL string `bson:"webhook_url,omitempty"`
+
+	// Processing tracking
+	RetryCount int    `bson:"retry_count"`
+	LastError  string `bson:"last_error,omitempty"`
+
+	// Result (only on success)
+	Result *EventResult `bson:"result,omitempty"`
+
+	// Rejection (only on failure)
+	Rejection *RejectionInfo `bson:"rejection,omitempty"`
+}
+
+// EventAuthorData contains the event author identification for storage.
+type EventAuthorData struct {
+	CNPJ string `bson:"cnpj,omitempty"`
+	CPF  string `bson:"cpf,omitempty"`
+}
+
+// CancellationData contains the cancellation event (e101101) data for storage.
+type CancellationData struct {
+	ReasonCode int    `bson:"reason_code"`
+	Reason     string `bson:"reason"`
+}
+
+// ManifestationData contains the manifestation event data for storage.
+type ManifestationData struct {
+	// Role is the author's role in the NFS-e (provider, taker, intermediary or municipality).
+	Role string `bson:"role"`
+
+	// Action is the manifestation (confirm, reject or annul_rejection).
+	Action string `bson:"action"`
+
+	// ReasonCode is the rejection reason (cMotivo), if any.
+	ReasonCode int `bson:"reason_code,omitempty"`
+
+	// Reason is the free-text justification (xMotivo), if any.
+	Reason string `bson:"reason,omitempty"`
+
+	// TaxAgentCPF is the CPF of the municipal tax agent (CPFAgTrib), for annulments.
+	TaxAgentCPF string `bson:"tax_agent_cpf,omitempty"`
+
+	// RejectionEventID is the Id of the annulled rejection event (idEvManifRej), for annulments.
+	RejectionEventID string `bson:"rejection_event_id,omitempty"`
+}
+
+// EventResult contains the event registered by the government API.
+type EventResult struct {
+	EventType    string    `bson:"event_type"`
+	Sequence     int       `bson:"sequence"`
+	RegisteredAt time.Time `bson:"registered_at,omitempty"`
+	EventXML     string    `bson:"event_xml,omitempty"`
+}
+
+// EventRepository provides access to event request data in MongoDB.
+type EventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewEventRepository creates a new event repository.
+func NewEventRepository(client *Client) *EventRepository {
+	return &EventRepository{
+		collection: client.GetCollection(eventRequestsCollection),
+	}
+}
+
+// Create inserts a new event request into the database.
+func (r *EventRepository) Create(ctx context.Context, req *EventRequest) error {
+	if req == nil {
+		return fmt.Errorf("event request cannot be nil")
+	}
+
+	if req.RequestID == "" {
+		return fmt.Errorf("request ID is required")
+	}
+
+	if req.APIKeyID.IsZero() {
+		return fmt.Errorf("API key ID is required")
+	}
+
+	// Set timestamps
+	now := time.Now().UTC()
+	req.CreatedAt = now
+	req.UpdatedAt = now
+
+	// Set default status if not provided
+	if req.Status == "" {
+		req.Status = "pending"
+	}
+
+	result, err := r.collection.InsertOne(ctx, req)
+	if err != nil {
+		// Check for duplicate key error (request_id should be unique)
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("event request with ID %s already exists", req.RequestID)
+		}
+		return fmt.Errorf("failed to create event request: %w", err)
+	}
+
+	// Set the generated ID
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		req.ID = oid
+	}
+
+	return nil
+}
+
+// FindByRequestID retrieves an event request by its request ID.
+func (r *EventRepository) FindByRequestID(ctx context.Context, requestID string) (*EventRequest, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request ID cannot be empty")
+	}
+
+	filter := bson.M{"request_id": requestID}
+
+	var req EventRequest
+	err := r.collection.FindOne(ctx, filter).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEventRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to find event request: %w", err)
+	}
+
+	return &req, nil
+}
+
+// FindActiveByAccessKey retrieves the most recent event request of the given type
+// for an NFS-e that is still pending, processing or already succeeded.
+// Returns ErrEventRequestNotFound if there is none, which allows a new request.
+func (r *EventRepository) FindActiveByAccessKey(ctx context.Context, chaveAcesso, eventType string) (*EventRequest, error) {
+	if chaveAcesso == "" {
+		return nil, fmt.Errorf("access key cannot be empty")
+	}
+
+	filter := bson.M{
+		"chave_acesso": chaveAcesso,
+		"event_type":   eventType,
+		"status":       bson.M{"$in": []string{"pending", "processing", "success"}},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var req EventRequest
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEventRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to find event request: %w", err)
+	}
+
+	return &req, nil
+}
+
+// UpdateStatus updates the status of an event request.
+func (r *EventRepository) UpdateStatus(ctx context.Context, requestID, status string) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	if status == "" {
+		return fmt.Errorf("status cannot be empty")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"updated_at": time.Now().UTC(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update event request status: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEventRequestNotFound
+	}
+
+	return nil
+}
+
+// UpdateResult updates an event request with the registered event.
+func (r *EventRepository) UpdateResult(ctx context.Context, requestID string, result *EventResult) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	if result == nil {
+		return fmt.Errorf("result cannot be nil")
+	}
+
+	now := time.Now().UTC()
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       "success",
+			"result":       result,
+			"updated_at":   now,
+			"processed_at": now,
+		},
+	}
+
+	updateResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update event request result: %w", err)
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return ErrEventRequestNotFound
+	}
+
+	return nil
+}
+
+// UpdateRejection updates an event request with a rejection/failure result.
+func (r *EventRepository) UpdateRejection(ctx context.Context, requestID string, rejection *RejectionInfo) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	if rejection == nil {
+		return fmt.Errorf("rejection cannot be nil")
+	}
+
+	now := time.Now().UTC()
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       "failed",
+			"rejection":    rejection,
+			"last_error":   rejection.Message,
+			"updated_at":   now,
+			"processed_at": now,
+		},
+		"$inc": bson.M{
+			"retry_count": 1,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update event request rejection: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEventRequestNotFound
+	}
+
+	return nil
+}
+
+// UpdateSigningStatus updates the certificate signing status and clears sensitive certificate data.
+// Unlike emissions, the certificate is also needed for mTLS when registering the event,
+// so this should only be called once the request reaches a final state.
+func (r *EventRepository) UpdateSigningStatus(ctx context.Context, requestID string, isSigned bool, subjectCN, issuerCN, serialNumber string) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"certificate.is_signed":     isSigned,
+			"certificate.subject_cn":    subjectCN,
+			"certificate.issuer_cn":     issuerCN,
+			"certificate.serial_number": serialNumber,
+			"updated_at":                time.Now().UTC(),
+		},
+		"$unset": bson.M{
+			// Clear sensitive data after signing
+			"certificate.pfx_base64": "",
+			"certificate.password":   "",
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update signing status: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEventRequestNotFound
+	}
+
+	return nil
+}
+
+// ClearCredentials removes the stored certificate credentials of an event request
+// that reached a final state without being registered by the government API.
+func (r *EventRepository) ClearCredentials(ctx context.Context, requestID string) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now().UTC(),
+		},
+		"$unset": bson.M{
+			"certificate.pfx_base64": "",
+			"certificate.password":   "",
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to clear certificate credentials: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEventRequestNotFound
+	}
+
+	return nil
+}
+
+// IncrementRetryCount increments the retry counter and updates the last error.
+func (r *EventRepository) IncrementRetryCount(ctx context.Context, requestID, lastError string) error {
+	if requestID == "" {
+		return fmt.Errorf("request ID cannot be empty")
+	}
+
+	filter := bson.M{"request_id": requestID}
+	update := bson.M{
+		"$set": bson.M{
+			"last_error": lastError,
+			"updated_at": time.Now().UTC(),
+		},
+		"$inc": bson.M{
+			"retry_count": 1,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to increment retry count: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrEventRequestNotFound
+	}
+
+	return nil
+}
+
+// EnsureIndexes creates the necessary indexes for the event requests collection.
+func (r *EventRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "request_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "api_key_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "chave_acesso", Value: 1},
+				{Key: "event_type", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+		},
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	return nil
+}
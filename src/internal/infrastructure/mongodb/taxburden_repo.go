@@ -0,0 +1,155 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// taxBurdenRatesCollection is the name of the approximate tax burden rates collection.
+	taxBurdenRatesCollection = "tax_burden_rates"
+)
+
+// ErrTaxBurdenRateNotFound is returned when the table has no rate for a service code.
+var ErrTaxBurdenRateNotFound = errors.New("tax burden rate not found")
+
+// TaxBurdenRate represents the approximate tax burden of a service code in a state,
+// loaded from an offline table (totTrib).
+type TaxBurdenRate struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	State       string             `bson:"state"`
+	CodeType    string             `bson:"code_type"`
+	Code        string             `bson:"code"`
+	Description string             `bson:"description,omitempty"`
+
+	// Approximate tax percentages by jurisdiction
+	FederalRate   float64 `bson:"federal_rate"`
+	StateRate     float64 `bson:"state_rate"`
+	MunicipalRate float64 `bson:"municipal_rate"`
+
+	// Validity of the rate, if informed by the table
+	ValidFrom  *time.Time `bson:"valid_from,omitempty"`
+	ValidUntil *time.Time `bson:"valid_until,omitempty"`
+
+	// Table identification
+	Version  string             `bson:"version"`
+	Source   string             `bson:"source,omitempty"`
+	LoadID   primitive.ObjectID `bson:"load_id"`
+	LoadedAt time.Time          `bson:"loaded_at"`
+}
+
+// TaxBurdenRepository provides access to the approximate tax burden rates in MongoDB.
+type TaxBurdenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTaxBurdenRepository creates a new tax burden repository.
+func NewTaxBurdenRepository(client *Client) *TaxBurdenRepository {
+	return &TaxBurdenRepository{
+		collection: client.GetCollection(taxBurdenRatesCollection),
+	}
+}
+
+// ReplaceTable replaces the rates of a state with the given table version.
+// The new rates are inserted before the previous ones are removed, so emissions
+// processed during the load always find a rate.
+func (r *TaxBurdenRepository) ReplaceTable(ctx context.Context, state, version, source string, rates []TaxBurdenRate) error {
+	if state == "" {
+		return fmt.Errorf("state cannot be empty")
+	}
+
+	if version == "" {
+		return fmt.Errorf("table version cannot be empty")
+	}
+
+	if len(rates) == 0 {
+		return fmt.Errorf("table has no rates")
+	}
+
+	loadID := primitive.NewObjectID()
+	loadedAt := time.Now().UTC()
+
+	docs := make([]interface{}, len(rates))
+	for i := range rates {
+		rate := rates[i]
+		rate.ID = primitive.NilObjectID
+		rate.State = state
+		rate.Version = version
+		rate.Source = source
+		rate.LoadID = loadID
+		rate.LoadedAt = loadedAt
+		docs[i] = rate
+	}
+
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to insert tax burden rates: %w", err)
+	}
+
+	filter := bson.M{
+		"state":   state,
+		"load_id": bson.M{"$ne": loadID},
+	}
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to remove previous tax burden rates: %w", err)
+	}
+
+	return nil
+}
+
+// FindRate retrieves the rate of a service code in a state.
+// Returns ErrTaxBurdenRateNotFound if the table has no rate for the code.
+func (r *TaxBurdenRepository) FindRate(ctx context.Context, state, codeType, code string) (*TaxBurdenRate, error) {
+	if state == "" || code == "" {
+		return nil, fmt.Errorf("state and code cannot be empty")
+	}
+
+	filter := bson.M{
+		"state":     state,
+		"code_type": codeType,
+		"code":      code,
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "loaded_at", Value: -1}})
+
+	var rate TaxBurdenRate
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&rate); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTaxBurdenRateNotFound
+		}
+		return nil, fmt.Errorf("failed to find tax burden rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+// EnsureIndexes creates the necessary indexes for the tax burden rates collection.
+func (r *TaxBurdenRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "state", Value: 1},
+				{Key: "code_type", Value: 1},
+				{Key: "code", Value: 1},
+				{Key: "loaded_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "state", Value: 1},
+				{Key: "load_id", Value: 1},
+			},
+		},
+	}
+
+	if _, err := r.collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create tax burden indexes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Package redis provides Redis connection management for the NFS-e API.
+// This file implements the cache store for government reference data.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultCacheKeyPrefix is the default prefix of the cache keys.
+	DefaultCacheKeyPrefix = "nfse:cache:"
+
+	// cacheScanCount is the number of keys requested per SCAN iteration.
+	cacheScanCount = 100
+)
+
+// CacheStore stores cache entries in Redis, so government reference data
+// retrieved by one API or worker instance is reused by all of them.
+// It implements sefin.CacheStore.
+type CacheStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// CacheStoreConfig configures the Redis cache store.
+type CacheStoreConfig struct {
+	// Client is the Redis client.
+	Client *Client
+
+	// Prefix is prepended to every key (default: "nfse:cache:").
+	Prefix string
+}
+
+// NewCacheStore creates a new Redis cache store.
+func NewCacheStore(config CacheStoreConfig) *CacheStore {
+	if config.Prefix == "" {
+		config.Prefix = DefaultCacheKeyPrefix
+	}
+
+	return &CacheStore{
+		client: config.Client.GetClient(),
+		prefix: config.Prefix,
+	}
+}
+
+// Get returns the entry stored under key; found is false when it does not exist.
+func (s *CacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis: cache get failed: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set stores an entry, removing it after expiration.
+func (s *CacheStore) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	if err := s.client.Set(ctx, s.prefix+key, value, expiration).Err(); err != nil {
+		return fmt.Errorf("redis: cache set failed: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every entry whose key starts with prefix and returns how many were removed.
+// Keys are found with SCAN, so the lookup does not block Redis on large caches,
+// and deleted once the scan completes.
+func (s *CacheStore) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	pattern := escapePattern(s.prefix+prefix) + "*"
+
+	var keys []string
+	iter := s.client.Scan(ctx, 0, pattern, cacheScanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("redis: cache scan failed: %w", err)
+	}
+
+	deleted := 0
+	for start := 0; start < len(keys); start += cacheScanCount {
+		end := start + cacheScanCount
+		if end > len(keys) {
+			end = len(keys)
+		}
+		n, err := s.client.Del(ctx, keys[start:end]...).Result()
+		deleted += int(n)
+		if err != nil {
+			return deleted, fmt.Errorf("redis: cache delete failed: %w", err)
+		}
+	}
+
+	return deleted, nil
+}
+
+// escapePattern escapes the glob characters of a key, so it matches literally in SCAN patterns.
+func escapePattern(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestCacheStore creates a cache store backed by miniredis.
+func newTestCacheStore(t *testing.T) (*miniredis.Miniredis, *CacheStore) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := &Client{client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+	return mr, NewCacheStore(CacheStoreConfig{Client: client})
+}
+
+func TestCacheStore_GetSet(t *testing.T) {
+	mr, store := newTestCacheStore(t)
+	ctx := context.Background()
+
+	if _, found, err := store.Get(ctx, "convenio:3550308"); err != nil || found {
+		t.Fatalf("expected miss, got found=%v err=%v", found, err)
+	}
+
+	if err := store.Set(ctx, "convenio:3550308", []byte(`{"conveniado":true}`), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if !mr.Exists(DefaultCacheKeyPrefix + "convenio:3550308") {
+		t.Error("expected key to be stored under the default prefix")
+	}
+
+	value, found, err := store.Get(ctx, "convenio:3550308")
+	if err != nil || !found {
+		t.Fatalf("expected hit, got found=%v err=%v", found, err)
+	}
+	if string(value) != `{"conveniado":true}` {
+		t.Errorf("unexpected value %s", value)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if _, found, _ := store.Get(ctx, "convenio:3550308"); found {
+		t.Error("expected entry to expire")
+	}
+}
+
+func TestCacheStore_DeletePrefix(t *testing.T) {
+	mr, store := newTestCacheStore(t)
+	ctx := context.Background()
+
+	for i := 0; i < 250; i++ {
+		key := fmt.Sprintf("servico:3550308/%06d", i)
+		if err := store.Set(ctx, key, []byte("{}"), time.Hour); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	_ = store.Set(ctx, "servico:4106902/010101", []byte("{}"), time.Hour)
+	_ = mr.Set("other:servico:3550308/010101", "{}")
+
+	deleted, err := store.DeletePrefix(ctx, "servico:3550308")
+	if err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if deleted != 250 {
+		t.Errorf("expected 250 deleted keys, got %d", deleted)
+	}
+	if _, found, _ := store.Get(ctx, "servico:4106902/010101"); !found {
+		t.Error("expected entries of other municipalities to be kept")
+	}
+	if !mr.Exists("other:servico:3550308/010101") {
+		t.Error("expected keys outside the prefix to be kept")
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	if got := escapePattern("nfse:cache:a*b?[c]"); got != `nfse:cache:a\*b\?\[c\]` {
+		t.Errorf("escapePattern() = %s", got)
+	}
+}
@@ -26,6 +26,12 @@ const (
 
 	// HomologationBaseURL is the homologation (testing) API endpoint.
 	HomologationBaseURL = "https://homolog.sefin.nfse.gov.br/nfse"
+
+	// ADNProductionBaseURL is the production endpoint of the ADN contributor API (DF-e distribution).
+	ADNProductionBaseURL = "https://adn.nfse.gov.br/contribuintes"
+
+	// ADNHomologationBaseURL is the restricted production (testing) endpoint of the ADN contributor API.
+	ADNHomologationBaseURL = "https://adn.producaorestrita.nfse.gov.br/contribuintes"
 )
 
 // SOAP action headers for different operations.
@@ -69,6 +75,34 @@ type SefinClient interface {
 	// Returns an empty event list (not an error) if the NFS-e has no events.
 	// Returns ErrNFSeNotFound if the NFS-e does not exist.
 	QueryEvents(ctx context.Context, chaveAcesso string, cert *tls.Certificate) (*EventsQueryResult, error)
+
+	// QueryEventsByType retrieves only the events of a given type (e.g. "e101101") for an NFS-e.
+	// Returns an empty event list (not an error) if the NFS-e has no events of that type.
+	// Returns ErrNFSeNotFound if the NFS-e does not exist.
+	QueryEventsByType(ctx context.Context, chaveAcesso, tipoEvento string, cert *tls.Certificate) (*EventsQueryResult, error)
+
+	// QueryEvent retrieves a single event identified by its type and sequence number (nSeqEvento).
+	// Returns ErrEventNotFound if the NFS-e has no such event.
+	QueryEvent(ctx context.Context, chaveAcesso, tipoEvento string, numSeqEvento int, cert *tls.Certificate) (*EventData, error)
+
+	// RegisterEvent submits a signed pedRegEvento XML (e.g. cancellation) for an NFS-e.
+	// The certificate parameter is used for mTLS authentication and must belong to the event author.
+	// Business rule rejections are returned as a result with Success=false.
+	// Returns ErrNFSeNotFound if the NFS-e does not exist.
+	RegisterEvent(ctx context.Context, chaveAcesso, pedRegEventoXML string, cert *tls.Certificate) (*EventRegistrationResult, error)
+
+	// QueryConvenio retrieves the agreement (convênio) parameters of a municipality
+	// with the Sistema Nacional NFS-e, including its adherence to the national emitter.
+	QueryConvenio(ctx context.Context, codigoMunicipio string, cert *tls.Certificate) (*ConvenioResult, error)
+
+	// QueryServiceParameters retrieves the parameters of a national service code in a
+	// municipality, including the ISS rate and the municipal tax code.
+	// Returns ErrServiceParametersNotFound if the municipality has no parameters for the service.
+	QueryServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string, cert *tls.Certificate) (*ServiceParametersResult, error)
+
+	// QueryContributorParameters retrieves the parameters a municipality has on record
+	// for a contributor (CPF/CNPJ): special regimes, municipal benefits and withholding rules.
+	QueryContributorParameters(ctx context.Context, codigoMunicipio, documento string, cert *tls.Certificate) (*ContributorParametersResult, error)
 }
 
 // SefinResponse represents the response from a SEFIN API call.
@@ -231,6 +265,24 @@ type EventData struct {
 	// Data is the event timestamp.
 	Data time.Time
 
+	// DataProcessamento is the event processing timestamp (dhProc), parsed from the XML.
+	DataProcessamento time.Time
+
+	// AutorCNPJ is the CNPJ of the event author (CNPJAutor), if the author is a company.
+	AutorCNPJ string
+
+	// AutorCPF is the CPF of the event author (CPFAutor), if the author is an individual.
+	AutorCPF string
+
+	// ID is the event identifier (infEvento Id, without the "EVT" prefix), parsed from the XML.
+	ID string
+
+	// CodEventoBloqueado is the event type blocked by an ex officio block (codEvento of e305102).
+	CodEventoBloqueado string
+
+	// IDBloqueio is the identifier of the block lifted by an ex officio unblock (idBloqOfic of e305103).
+	IDBloqueio string
+
 	// XML contains the complete signed event XML document.
 	XML string
 }
@@ -242,6 +294,9 @@ type EventData struct {
 // ErrNFSeNotFound is returned when the requested NFS-e does not exist.
 var ErrNFSeNotFound = fmt.Errorf("nfse not found")
 
+// ErrEventNotFound is returned when the requested event does not exist for the NFS-e.
+var ErrEventNotFound = fmt.Errorf("event not found")
+
 // ErrDPSNotFound is returned when the requested DPS does not exist.
 var ErrDPSNotFound = fmt.Errorf("dps not found")
 
@@ -259,6 +314,9 @@ type ClientConfig struct {
 	// BaseURL is the SEFIN API base URL.
 	BaseURL string
 
+	// ADNBaseURL is the ADN contributor API base URL, used for DF-e distribution.
+	ADNBaseURL string
+
 	// Environment is "producao" or "homologacao".
 	Environment string
 
@@ -291,6 +349,7 @@ type ClientConfig struct {
 type ProductionClient struct {
 	httpClient  *http.Client
 	baseURL     string
+	adnBaseURL  string
 	environment int // 1=production, 2=homologation
 	timeout     time.Duration
 	logger      *log.Logger
@@ -310,6 +369,14 @@ func NewProductionClient(config ClientConfig) (*ProductionClient, error) {
 		}
 	}
 
+	if config.ADNBaseURL == "" {
+		if config.Environment == EnvironmentProduction {
+			config.ADNBaseURL = ADNProductionBaseURL
+		} else {
+			config.ADNBaseURL = ADNHomologationBaseURL
+		}
+	}
+
 	if config.Timeout == 0 {
 		config.Timeout = 30 * time.Second
 	}
@@ -357,6 +424,7 @@ func NewProductionClient(config ClientConfig) (*ProductionClient, error) {
 	return &ProductionClient{
 		httpClient:  httpClient,
 		baseURL:     config.BaseURL,
+		adnBaseURL:  config.ADNBaseURL,
 		environment: envCode,
 		timeout:     config.Timeout,
 		logger:      config.Logger,
@@ -1047,13 +1115,16 @@ func (c *ProductionClient) parseEventsQueryResponse(chaveAcesso string, body []b
 			}
 		}
 
-		result.Events = append(result.Events, EventData{
+		eventData := EventData{
 			Tipo:      evt.Tipo,
 			Descricao: evt.Descricao,
 			Sequencia: evt.Sequencia,
 			Data:      eventDate,
 			XML:       evt.XML,
-		})
+		}
+		populateEventDetails(&eventData)
+
+		result.Events = append(result.Events, eventData)
 	}
 
 	return result, nil
@@ -1364,16 +1435,19 @@ func (c *MockClient) QueryEvents(ctx context.Context, chaveAcesso string, cert *
 			XML:       generateMockEventXML("EMISSAO", chaveAcesso, 1),
 		},
 	}
+	populateEventDetails(&events[0])
 
 	// Add cancellation event for keys starting with "CANCELLED"
 	if strings.HasPrefix(chaveAcesso, "CANCELLED") {
-		events = append(events, EventData{
+		cancellation := EventData{
 			Tipo:      "e101101",
 			Descricao: "Cancelamento de NFS-e",
 			Sequencia: 2,
 			Data:      time.Now().Add(-12 * time.Hour),
 			XML:       generateMockEventXML("e101101", chaveAcesso, 2),
-		})
+		}
+		populateEventDetails(&cancellation)
+		events = append(events, cancellation)
 	}
 
 	return &EventsQueryResult{
@@ -1393,6 +1467,14 @@ func generateMockEventXML(eventType, chaveAcesso string, sequencia int) string {
     <tpEvento>%s</tpEvento>
     <nSeqEvento>%d</nSeqEvento>
     <dhEvento>%s</dhEvento>
+    <dhProc>%s</dhProc>
+    <pedRegEvento versao="1.00">
+      <infPedReg>
+        <CNPJAutor>%s</CNPJAutor>
+        <chNFSe>%s</chNFSe>
+      </infPedReg>
+    </pedRegEvento>
   </infEvento>
-</evento>`, chaveAcesso, sequencia, chaveAcesso, eventType, sequencia, timestamp)
+</evento>`, chaveAcesso, sequencia, chaveAcesso, eventType, sequencia, timestamp, timestamp,
+		mockEventAuthorCNPJ, chaveAcesso)
 }
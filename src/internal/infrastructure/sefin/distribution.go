@@ -0,0 +1,439 @@
+package sefin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ================================================================================
+// DF-e Distribution Types
+// ================================================================================
+
+// MaxDistributionBatchSize is the maximum number of documents returned by the
+// ADN in a single distribution batch.
+const MaxDistributionBatchSize = 50
+
+// Distribution processing statuses returned by the ADN (StatusProcessamento).
+const (
+	// DistributionStatusDocumentsFound indicates the batch contains documents.
+	DistributionStatusDocumentsFound = "DOCUMENTOS_LOCALIZADOS"
+
+	// DistributionStatusNoDocuments indicates there are no documents after the informed NSU.
+	DistributionStatusNoDocuments = "NENHUM_DOCUMENTO_LOCALIZADO"
+
+	// DistributionStatusRejected indicates the request was rejected.
+	DistributionStatusRejected = "REJEICAO"
+)
+
+// Distributed document types (TipoDocumento).
+const (
+	// DistributedDocumentNFSe is an NFS-e document.
+	DistributedDocumentNFSe = "NFSE"
+
+	// DistributedDocumentEvent is an NFS-e event document.
+	DistributedDocumentEvent = "EVENTO"
+)
+
+// ErrDistributionRejected is returned when the ADN rejects a distribution request.
+var ErrDistributionRejected = errors.New("distribution request rejected")
+
+// DistributionClient defines the ADN DF-e distribution operations. The ADN
+// distributes, for each CPF/CNPJ, every NFS-e and event in which it is an actor
+// (provider, taker or intermediary), numbered by a sequential NSU.
+type DistributionClient interface {
+	// FetchDocuments retrieves up to MaxDistributionBatchSize documents with NSU
+	// greater than lastNSU (GET /DFe/{UltimoNSU}).
+	// The certificate parameter is used for mTLS and must belong to the CNPJ.
+	FetchDocuments(ctx context.Context, cnpj string, lastNSU int64, cert *tls.Certificate) (*DistributionResult, error)
+
+	// FetchDocument retrieves the single document with the given NSU (GET /DFe/{NSU}).
+	// It is used to recover an NSU missing from the local base.
+	FetchDocument(ctx context.Context, cnpj string, nsu int64, cert *tls.Certificate) (*DistributionResult, error)
+}
+
+// DistributionResult holds a batch of documents distributed by the ADN.
+type DistributionResult struct {
+	// Status is the processing status (see DistributionStatus* constants).
+	Status string
+
+	// Documents are the distributed documents, in ascending NSU order.
+	Documents []DistributedDocument
+
+	// LastNSU is the greatest NSU in the batch, or the informed NSU if the batch is empty.
+	LastNSU int64
+
+	// ProcessedAt is when the ADN processed the request.
+	ProcessedAt time.Time
+
+	// Alerts contains informational messages returned with the batch.
+	Alerts []string
+}
+
+// HasDocuments reports whether the batch contains documents.
+func (r *DistributionResult) HasDocuments() bool {
+	return len(r.Documents) > 0
+}
+
+// DistributedDocument is a single DF-e (NFS-e or event) distributed by the ADN.
+type DistributedDocument struct {
+	// NSU is the sequential number of the document for the requesting CNPJ.
+	NSU int64
+
+	// ChaveAcesso is the access key of the NFS-e the document refers to.
+	ChaveAcesso string
+
+	// TipoDocumento is the document type (see DistributedDocument* constants).
+	TipoDocumento string
+
+	// TipoEvento is the event type code (only for events).
+	TipoEvento string
+
+	// XML is the decompressed document XML.
+	XML string
+
+	// DataGeracao is when the document was generated.
+	DataGeracao time.Time
+}
+
+// IsNFSe reports whether the document is an NFS-e.
+func (d *DistributedDocument) IsNFSe() bool {
+	return d.TipoDocumento == DistributedDocumentNFSe
+}
+
+// IsEvent reports whether the document is an NFS-e event.
+func (d *DistributedDocument) IsEvent() bool {
+	return d.TipoDocumento == DistributedDocumentEvent
+}
+
+// EventData returns the event details parsed from the XML of an event document.
+func (d *DistributedDocument) EventData() EventData {
+	evt := EventData{
+		Tipo: d.TipoEvento,
+		Data: d.DataGeracao,
+		XML:  d.XML,
+	}
+	populateEventDetails(&evt)
+	return evt
+}
+
+// ================================================================================
+// DF-e Distribution Methods for ProductionClient
+// ================================================================================
+
+// distributionJSONResponse represents the JSON response of the ADN distribution API.
+type distributionJSONResponse struct {
+	StatusProcessamento   string                    `json:"StatusProcessamento"`
+	LoteDFe               []distributedDocumentJSON `json:"LoteDFe"`
+	Alertas               []distributionMessageJSON `json:"Alertas"`
+	Erros                 []distributionMessageJSON `json:"Erros"`
+	DataHoraProcessamento string                    `json:"DataHoraProcessamento"`
+}
+
+// distributedDocumentJSON represents a document in the distribution batch.
+type distributedDocumentJSON struct {
+	NSU             int64  `json:"NSU"`
+	ChaveAcesso     string `json:"ChaveAcesso"`
+	TipoDocumento   string `json:"TipoDocumento"`
+	TipoEvento      string `json:"TipoEvento"`
+	ArquivoXML      string `json:"ArquivoXml"`
+	DataHoraGeracao string `json:"DataHoraGeracao"`
+}
+
+// distributionMessageJSON represents an alert or error returned by the distribution API.
+type distributionMessageJSON struct {
+	Codigo      string `json:"Codigo"`
+	Descricao   string `json:"Descricao"`
+	Complemento string `json:"Complemento"`
+}
+
+// String formats the message as "code - description (complement)".
+func (m distributionMessageJSON) String() string {
+	msg := m.Descricao
+	if m.Codigo != "" {
+		msg = m.Codigo + " - " + msg
+	}
+	if m.Complemento != "" {
+		msg += " (" + m.Complemento + ")"
+	}
+	return msg
+}
+
+// FetchDocuments retrieves the next batch of documents after lastNSU from the ADN.
+func (c *ProductionClient) FetchDocuments(ctx context.Context, cnpj string, lastNSU int64, cert *tls.Certificate) (*DistributionResult, error) {
+	return c.fetchDistribution(ctx, "FetchDocuments", cnpj, lastNSU, true, cert)
+}
+
+// FetchDocument retrieves the document with the given NSU from the ADN.
+func (c *ProductionClient) FetchDocument(ctx context.Context, cnpj string, nsu int64, cert *tls.Certificate) (*DistributionResult, error) {
+	return c.fetchDistribution(ctx, "FetchDocument", cnpj, nsu, false, cert)
+}
+
+// fetchDistribution calls GET /DFe/{NSU}. With batch set, the ADN returns the
+// documents following the NSU; otherwise it returns the document with that NSU.
+func (c *ProductionClient) fetchDistribution(ctx context.Context, operation, cnpj string, nsu int64, batch bool, cert *tls.Certificate) (*DistributionResult, error) {
+	if cnpj == "" {
+		return nil, fmt.Errorf("cnpj is required")
+	}
+	if nsu < 0 {
+		return nil, fmt.Errorf("nsu must not be negative")
+	}
+
+	url := fmt.Sprintf("%s/DFe/%d?cnpjConsulta=%s&lote=%t", c.adnBaseURL, nsu, cnpj, batch)
+
+	statusCode, body, err := c.getEventsResource(ctx, operation, url, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	switch statusCode {
+	case http.StatusOK, http.StatusBadRequest, http.StatusNotFound:
+		// The ADN reports "no documents" and rejections in the JSON body
+		result, err := parseDistributionResponse(nsu, body)
+		if err != nil {
+			if statusCode != http.StatusOK && !errors.Is(err, ErrDistributionRejected) {
+				return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+			}
+			return nil, err
+		}
+		return result, nil
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+	}
+}
+
+// parseDistributionResponse parses the distribution JSON response and decompresses
+// the documents in the batch.
+func parseDistributionResponse(requestedNSU int64, body []byte) (*DistributionResult, error) {
+	var jsonResp distributionJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	if jsonResp.StatusProcessamento == DistributionStatusRejected {
+		messages := make([]string, 0, len(jsonResp.Erros))
+		for _, e := range jsonResp.Erros {
+			messages = append(messages, e.String())
+		}
+		return nil, fmt.Errorf("%w: %s", ErrDistributionRejected, strings.Join(messages, "; "))
+	}
+
+	result := &DistributionResult{
+		Status:    jsonResp.StatusProcessamento,
+		Documents: make([]DistributedDocument, 0, len(jsonResp.LoteDFe)),
+		LastNSU:   requestedNSU,
+	}
+	if processedAt, ok := parseEventTimestamp(jsonResp.DataHoraProcessamento); ok {
+		result.ProcessedAt = processedAt
+	}
+	for _, a := range jsonResp.Alertas {
+		result.Alerts = append(result.Alerts, a.String())
+	}
+
+	for _, item := range jsonResp.LoteDFe {
+		xmlContent, err := decodeGZipBase64(item.ArquivoXML)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document NSU %d: %w", item.NSU, err)
+		}
+
+		doc := DistributedDocument{
+			NSU:           item.NSU,
+			ChaveAcesso:   item.ChaveAcesso,
+			TipoDocumento: strings.ToUpper(item.TipoDocumento),
+			TipoEvento:    item.TipoEvento,
+			XML:           string(xmlContent),
+		}
+		if generatedAt, ok := parseEventTimestamp(item.DataHoraGeracao); ok {
+			doc.DataGeracao = generatedAt
+		}
+		if doc.IsEvent() && doc.TipoEvento == "" {
+			doc.TipoEvento = extractEventType(doc.XML)
+		}
+
+		result.Documents = append(result.Documents, doc)
+		if item.NSU > result.LastNSU {
+			result.LastNSU = item.NSU
+		}
+	}
+
+	if result.Status == "" {
+		if result.HasDocuments() {
+			result.Status = DistributionStatusDocumentsFound
+		} else {
+			result.Status = DistributionStatusNoDocuments
+		}
+	}
+
+	return result, nil
+}
+
+// ================================================================================
+// Mock DF-e Distribution Methods
+// ================================================================================
+
+// mockDistributionDocuments is the number of documents the mock distributes for each CNPJ.
+const mockDistributionDocuments = 3
+
+// FetchDocuments returns the mock documents after lastNSU. The mock distributes,
+// for any CNPJ, an NFS-e issued against it (NSU 1), its cancellation (NSU 2) and
+// a second NFS-e (NSU 3).
+func (c *MockClient) FetchDocuments(ctx context.Context, cnpj string, lastNSU int64, cert *tls.Certificate) (*DistributionResult, error) {
+	// Add simulated latency
+	if c.SimulatedLatency > 0 {
+		select {
+		case <-time.After(c.SimulatedLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Simulate failure if configured
+	if c.SimulateFailure || c.shouldFail() {
+		return nil, ErrServiceUnavailable
+	}
+
+	result := &DistributionResult{
+		Status:      DistributionStatusNoDocuments,
+		LastNSU:     lastNSU,
+		ProcessedAt: time.Now(),
+	}
+	for nsu := lastNSU + 1; nsu <= mockDistributionDocuments && len(result.Documents) < MaxDistributionBatchSize; nsu++ {
+		result.Documents = append(result.Documents, mockDistributedDocument(cnpj, nsu))
+		result.LastNSU = nsu
+	}
+	if result.HasDocuments() {
+		result.Status = DistributionStatusDocumentsFound
+	}
+
+	return result, nil
+}
+
+// FetchDocument returns the mock document with the given NSU.
+func (c *MockClient) FetchDocument(ctx context.Context, cnpj string, nsu int64, cert *tls.Certificate) (*DistributionResult, error) {
+	// Add simulated latency
+	if c.SimulatedLatency > 0 {
+		select {
+		case <-time.After(c.SimulatedLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Simulate failure if configured
+	if c.SimulateFailure || c.shouldFail() {
+		return nil, ErrServiceUnavailable
+	}
+
+	result := &DistributionResult{
+		Status:      DistributionStatusNoDocuments,
+		LastNSU:     nsu,
+		ProcessedAt: time.Now(),
+	}
+	if nsu >= 1 && nsu <= mockDistributionDocuments {
+		result.Status = DistributionStatusDocumentsFound
+		result.Documents = []DistributedDocument{mockDistributedDocument(cnpj, nsu)}
+	}
+
+	return result, nil
+}
+
+// mockDistributedDocument builds the mock document with the given NSU for the CNPJ.
+func mockDistributedDocument(cnpj string, nsu int64) DistributedDocument {
+	firstKey := mockDistributionAccessKey(1)
+	switch nsu {
+	case 1:
+		return DistributedDocument{
+			NSU:           1,
+			ChaveAcesso:   firstKey,
+			TipoDocumento: DistributedDocumentNFSe,
+			XML:           generateMockDistributedNFSeXML(firstKey, cnpj, "000000101", 1500.00),
+			DataGeracao:   time.Now().Add(-48 * time.Hour),
+		}
+	case 2:
+		return DistributedDocument{
+			NSU:           2,
+			ChaveAcesso:   firstKey,
+			TipoDocumento: DistributedDocumentEvent,
+			TipoEvento:    "e101101",
+			XML:           generateMockEventXML("e101101", firstKey, 1),
+			DataGeracao:   time.Now().Add(-24 * time.Hour),
+		}
+	default:
+		key := mockDistributionAccessKey(nsu)
+		return DistributedDocument{
+			NSU:           nsu,
+			ChaveAcesso:   key,
+			TipoDocumento: DistributedDocumentNFSe,
+			XML:           generateMockDistributedNFSeXML(key, cnpj, fmt.Sprintf("%09d", 100+nsu), 800.00),
+			DataGeracao:   time.Now(),
+		}
+	}
+}
+
+// mockDistributionAccessKey builds a deterministic 50-character access key for mock documents.
+func mockDistributionAccessKey(n int64) string {
+	return fmt.Sprintf("NFSe3550308%s%s%029d0", time.Now().Format("0601"), mockEventAuthorCNPJ[:5], n)
+}
+
+// generateMockDistributedNFSeXML generates a mock NFS-e issued by the mock provider
+// against the given taker CNPJ.
+func generateMockDistributedNFSeXML(chaveAcesso, takerCNPJ, nfseNumber string, value float64) string {
+	now := time.Now()
+	timestamp := now.Format("2006-01-02T15:04:05-03:00")
+	competence := now.Format("2006-01-02")
+	iss := value * 0.05
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<NFSe xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00">
+  <infNFSe Id="NFS%s">
+    <nNFSe>%s</nNFSe>
+    <dhEmi>%s</dhEmi>
+    <chNFSe>%s</chNFSe>
+    <sit>1</sit>
+    <emit>
+      <CNPJ>%s</CNPJ>
+      <xNome>Mock Service Provider LTDA</xNome>
+      <ender>
+        <cMun>3550308</cMun>
+        <xMun>Sao Paulo</xMun>
+        <UF>SP</UF>
+      </ender>
+    </emit>
+    <toma>
+      <CNPJ>%s</CNPJ>
+      <xNome>Mock Service Taker</xNome>
+    </toma>
+    <serv>
+      <cTribNac>010101</cTribNac>
+      <xDescServ>Mock distributed service</xDescServ>
+      <localPrest>
+        <cMun>3550308</cMun>
+        <xMun>Sao Paulo</xMun>
+        <UF>SP</UF>
+      </localPrest>
+    </serv>
+    <valores>
+      <vServico>%.2f</vServico>
+      <vBC>%.2f</vBC>
+      <pAliq>5.00</pAliq>
+      <vISS>%.2f</vISS>
+      <vLiq>%.2f</vLiq>
+    </valores>
+    <DPS>
+      <infDPS>
+        <dCompet>%s</dCompet>
+      </infDPS>
+    </DPS>
+  </infNFSe>
+</NFSe>`, chaveAcesso[4:], nfseNumber, timestamp, chaveAcesso, mockEventAuthorCNPJ, takerCNPJ,
+		value, value, iss, value, competence)
+}
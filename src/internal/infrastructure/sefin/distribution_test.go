@@ -0,0 +1,320 @@
+package sefin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newDistributionTestClient creates a ProductionClient whose ADN endpoint points at the given test server.
+func newDistributionTestClient(t *testing.T, serverURL string) *ProductionClient {
+	t.Helper()
+
+	client, err := NewProductionClient(ClientConfig{
+		BaseURL:     serverURL,
+		ADNBaseURL:  serverURL,
+		Environment: EnvironmentHomologation,
+		Timeout:     10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+// ================================================================================
+// ProductionClient DF-e Distribution Tests
+// ================================================================================
+
+func TestFetchDocuments_Success(t *testing.T) {
+	nfseXML := `<NFSe><infNFSe Id="NFS123"><nNFSe>1</nNFSe></infNFSe></NFSe>`
+	encodedNFSe, err := encodeGZipBase64([]byte(nfseXML))
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	encodedEvent, err := encodeGZipBase64([]byte(testRegisteredEventXML))
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path != "/DFe/10" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("cnpjConsulta"); got != "11222333000181" {
+			t.Errorf("expected cnpjConsulta 11222333000181, got %q", got)
+		}
+		if got := r.URL.Query().Get("lote"); got != "true" {
+			t.Errorf("expected lote=true, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"StatusProcessamento":   DistributionStatusDocumentsFound,
+			"DataHoraProcessamento": "2024-01-16T14:00:05-03:00",
+			"LoteDFe": []map[string]interface{}{
+				{
+					"NSU":             11,
+					"ChaveAcesso":     "NFSe12345",
+					"TipoDocumento":   "NFSE",
+					"ArquivoXml":      encodedNFSe,
+					"DataHoraGeracao": "2024-01-15T10:00:00-03:00",
+				},
+				{
+					"NSU":           12,
+					"ChaveAcesso":   "NFSe12345",
+					"TipoDocumento": "EVENTO",
+					"ArquivoXml":    encodedEvent,
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	result, err := client.FetchDocuments(context.Background(), "11222333000181", 10, nil)
+	if err != nil {
+		t.Fatalf("FetchDocuments failed: %v", err)
+	}
+
+	if result.Status != DistributionStatusDocumentsFound {
+		t.Errorf("expected status %s, got %s", DistributionStatusDocumentsFound, result.Status)
+	}
+	if result.LastNSU != 12 {
+		t.Errorf("expected last NSU 12, got %d", result.LastNSU)
+	}
+	if len(result.Documents) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(result.Documents))
+	}
+
+	nfse := result.Documents[0]
+	if !nfse.IsNFSe() || nfse.XML != nfseXML {
+		t.Errorf("expected decompressed NFS-e, got %+v", nfse)
+	}
+	if nfse.DataGeracao.IsZero() {
+		t.Error("expected generation date to be parsed")
+	}
+
+	evt := result.Documents[1]
+	if !evt.IsEvent() {
+		t.Errorf("expected event document, got %s", evt.TipoDocumento)
+	}
+	if evt.TipoEvento != "e101101" {
+		t.Errorf("expected event type extracted from XML, got %q", evt.TipoEvento)
+	}
+}
+
+func TestFetchDocuments_NoDocuments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"StatusProcessamento": DistributionStatusNoDocuments,
+		})
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	result, err := client.FetchDocuments(context.Background(), "11222333000181", 42, nil)
+	if err != nil {
+		t.Fatalf("FetchDocuments failed: %v", err)
+	}
+
+	if result.HasDocuments() {
+		t.Errorf("expected no documents, got %d", len(result.Documents))
+	}
+	if result.LastNSU != 42 {
+		t.Errorf("expected last NSU to stay at 42, got %d", result.LastNSU)
+	}
+}
+
+func TestFetchDocuments_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"StatusProcessamento": DistributionStatusRejected,
+			"Erros": []map[string]string{
+				{"Codigo": "E2001", "Descricao": "CNPJ nao autorizado"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	_, err := client.FetchDocuments(context.Background(), "11222333000181", 0, nil)
+	if !errors.Is(err, ErrDistributionRejected) {
+		t.Fatalf("expected ErrDistributionRejected, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "E2001") {
+		t.Errorf("expected error code in message, got %v", err)
+	}
+}
+
+func TestFetchDocument_SingleNSU(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/DFe/7" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("lote"); got != "false" {
+			t.Errorf("expected lote=false, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"StatusProcessamento": DistributionStatusNoDocuments,
+		})
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	if _, err := client.FetchDocument(context.Background(), "11222333000181", 7, nil); err != nil {
+		t.Fatalf("FetchDocument failed: %v", err)
+	}
+}
+
+func TestFetchDocuments_StatusErrors(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+		{http.StatusTooManyRequests, ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		client := newDistributionTestClient(t, server.URL)
+		_, err := client.FetchDocuments(context.Background(), "11222333000181", 0, nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.wantErr, err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestFetchDocuments_EmptyParameters(t *testing.T) {
+	client := newDistributionTestClient(t, "http://localhost")
+
+	if _, err := client.FetchDocuments(context.Background(), "", 0, nil); err == nil {
+		t.Error("expected error for empty cnpj")
+	}
+	if _, err := client.FetchDocuments(context.Background(), "11222333000181", -1, nil); err == nil {
+		t.Error("expected error for negative NSU")
+	}
+}
+
+func TestNewProductionClient_ADNBaseURL(t *testing.T) {
+	client, err := NewProductionClient(ClientConfig{Environment: EnvironmentProduction})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if client.adnBaseURL != ADNProductionBaseURL {
+		t.Errorf("expected production ADN URL, got %s", client.adnBaseURL)
+	}
+
+	client, err = NewProductionClient(ClientConfig{Environment: EnvironmentHomologation})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if client.adnBaseURL != ADNHomologationBaseURL {
+		t.Errorf("expected homologation ADN URL, got %s", client.adnBaseURL)
+	}
+}
+
+// ================================================================================
+// MockClient DF-e Distribution Tests
+// ================================================================================
+
+func TestMockClient_FetchDocuments(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	result, err := client.FetchDocuments(context.Background(), "11222333000181", 0, nil)
+	if err != nil {
+		t.Fatalf("MockClient.FetchDocuments failed: %v", err)
+	}
+	if len(result.Documents) != mockDistributionDocuments {
+		t.Fatalf("expected %d documents, got %d", mockDistributionDocuments, len(result.Documents))
+	}
+	if result.LastNSU != mockDistributionDocuments {
+		t.Errorf("expected last NSU %d, got %d", mockDistributionDocuments, result.LastNSU)
+	}
+	if !strings.Contains(result.Documents[0].XML, "<CNPJ>11222333000181</CNPJ>") {
+		t.Error("expected the requesting CNPJ as taker of the mock NFS-e")
+	}
+	if len(result.Documents[0].ChaveAcesso) != 50 {
+		t.Errorf("expected 50-character access key, got %q", result.Documents[0].ChaveAcesso)
+	}
+	if !result.Documents[1].IsEvent() || result.Documents[1].TipoEvento != "e101101" {
+		t.Errorf("expected cancellation event at NSU 2, got %+v", result.Documents[1])
+	}
+
+	result, err = client.FetchDocuments(context.Background(), "11222333000181", result.LastNSU, nil)
+	if err != nil {
+		t.Fatalf("MockClient.FetchDocuments failed: %v", err)
+	}
+	if result.HasDocuments() || result.Status != DistributionStatusNoDocuments {
+		t.Errorf("expected no documents after last NSU, got %d", len(result.Documents))
+	}
+}
+
+func TestMockClient_FetchDocument(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	result, err := client.FetchDocument(context.Background(), "11222333000181", 2, nil)
+	if err != nil {
+		t.Fatalf("MockClient.FetchDocument failed: %v", err)
+	}
+	if len(result.Documents) != 1 || result.Documents[0].NSU != 2 {
+		t.Errorf("expected document NSU 2, got %+v", result.Documents)
+	}
+}
+
+func TestDistributedDocument_EventData(t *testing.T) {
+	blockID := "3550308" + strings.Repeat("1", 43) + "305102" + "001"
+	block := &DistributedDocument{
+		TipoDocumento: DistributedDocumentEvent,
+		TipoEvento:    "e305102",
+		XML:           `<evento versao="1.00"><infEvento Id="EVT` + blockID + `"><nSeqEvento>3</nSeqEvento><pedRegEvento><infPedReg><e305102><xDesc>Bloqueio de NFS-e por Ofício</xDesc><CPFAgTrib>12345678909</CPFAgTrib><codEvento>e101101</codEvento><xMotivo>Fiscalização</xMotivo></e305102></infPedReg></pedRegEvento></infEvento></evento>`,
+	}
+
+	evt := block.EventData()
+	if evt.Tipo != "e305102" || evt.Sequencia != 3 {
+		t.Errorf("unexpected event type %q and sequence %d", evt.Tipo, evt.Sequencia)
+	}
+	if evt.ID != blockID {
+		t.Errorf("expected ID %s, got %q", blockID, evt.ID)
+	}
+	if evt.CodEventoBloqueado != "e101101" {
+		t.Errorf("expected blocked event e101101, got %q", evt.CodEventoBloqueado)
+	}
+
+	unblock := &DistributedDocument{
+		TipoDocumento: DistributedDocumentEvent,
+		TipoEvento:    "e305103",
+		XML:           `<evento versao="1.00"><infEvento Id="EVT1"><pedRegEvento><infPedReg><e305103><xDesc>Desbloqueio de NFS-e por Ofício</xDesc><CPFAgTrib>12345678909</CPFAgTrib><idBloqOfic>` + blockID + `</idBloqOfic></e305103></infPedReg></pedRegEvento></infEvento></evento>`,
+	}
+
+	evt = unblock.EventData()
+	if evt.IDBloqueio != blockID {
+		t.Errorf("expected block ID %s, got %q", blockID, evt.IDBloqueio)
+	}
+	if evt.CodEventoBloqueado != "" {
+		t.Errorf("expected no blocked event for an unblock, got %q", evt.CodEventoBloqueado)
+	}
+}
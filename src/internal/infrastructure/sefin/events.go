@@ -0,0 +1,605 @@
+package sefin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ================================================================================
+// Event Registration Types
+// ================================================================================
+
+// EventRegistrationResult holds the result of registering an event for an NFS-e.
+type EventRegistrationResult struct {
+	// Success indicates whether the event was registered.
+	Success bool
+
+	// ChaveAcesso is the 50-character access key of the NFS-e.
+	ChaveAcesso string
+
+	// TipoEvento is the event type code (e.g., "e101101" for cancellation).
+	TipoEvento string
+
+	// Sequencia is the sequential event number assigned by SEFIN (nSeqEvento).
+	Sequencia int
+
+	// DataProcessamento is the event processing timestamp (dhProc).
+	DataProcessamento time.Time
+
+	// EventXML is the registered event XML document signed by SEFIN (only on success).
+	EventXML string
+
+	// ErrorCode is the error code returned by SEFIN (only on failure).
+	ErrorCode string
+
+	// ErrorMessage is the error message returned by SEFIN (only on failure).
+	ErrorMessage string
+
+	// ErrorCodes contains multiple error codes if the request had multiple issues.
+	ErrorCodes []string
+
+	// ProcessingTime is how long SEFIN took to process the request.
+	ProcessingTime time.Duration
+}
+
+// ================================================================================
+// Event Registration Methods for ProductionClient
+// ================================================================================
+
+// RegisterEvent submits a signed pedRegEvento XML for an NFS-e to the government API.
+// The XML is sent GZip-compressed and base64-encoded, as required by the API.
+// Business rule rejections are returned as a result with Success=false, not as an error.
+func (c *ProductionClient) RegisterEvent(ctx context.Context, chaveAcesso, pedRegEventoXML string, cert *tls.Certificate) (*EventRegistrationResult, error) {
+	if chaveAcesso == "" {
+		return nil, fmt.Errorf("chaveAcesso is required")
+	}
+	if pedRegEventoXML == "" {
+		return nil, fmt.Errorf("pedRegEventoXML is required")
+	}
+
+	start := time.Now()
+
+	// Build request URL
+	url := fmt.Sprintf("%s/nfse/%s/eventos", c.baseURL, chaveAcesso)
+
+	c.logDebug("RegisterEvent: requesting %s", url)
+
+	// Compress and encode the signed event
+	encoded, err := encodeGZipBase64([]byte(pedRegEventoXML))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event XML: %w", err)
+	}
+
+	payload, err := json.Marshal(eventRegistrationJSONRequest{PedidoRegistroEventoXMLGZipB64: encoded})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP client with provided certificate
+	httpClient := c.createQueryHTTPClient(cert)
+
+	// Create request with context
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute request
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout") {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	c.logDebug("RegisterEvent: response status=%d body=%s", resp.StatusCode, string(bodyBytes))
+
+	// Handle response status codes
+	var result *EventRegistrationResult
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		result, err = c.parseEventRegistrationResponse(chaveAcesso, bodyBytes)
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		result, err = c.parseEventRegistrationRejection(chaveAcesso, bodyBytes)
+	case http.StatusNotFound:
+		return nil, ErrNFSeNotFound
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", resp.StatusCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.ProcessingTime = time.Since(start)
+	return result, nil
+}
+
+// eventRegistrationJSONRequest represents the JSON request body of the events API.
+type eventRegistrationJSONRequest struct {
+	PedidoRegistroEventoXMLGZipB64 string `json:"pedidoRegistroEventoXmlGZipB64"`
+}
+
+// eventRegistrationJSONResponse represents the JSON response of the events API.
+type eventRegistrationJSONResponse struct {
+	DataHoraProcessamento string `json:"dataHoraProcessamento"`
+	EventoXMLGZipB64      string `json:"eventoXmlGZipB64"`
+	Erros                 []struct {
+		Codigo    string `json:"codigo"`
+		Descricao string `json:"descricao"`
+	} `json:"erros"`
+}
+
+// parseEventRegistrationResponse parses a successful JSON response from the events API.
+func (c *ProductionClient) parseEventRegistrationResponse(chaveAcesso string, body []byte) (*EventRegistrationResult, error) {
+	var jsonResp eventRegistrationJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	eventXML, err := decodeGZipBase64(jsonResp.EventoXMLGZipB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event XML: %w", err)
+	}
+
+	result := &EventRegistrationResult{
+		Success:     true,
+		ChaveAcesso: chaveAcesso,
+		EventXML:    string(eventXML),
+		TipoEvento:  extractEventType(string(eventXML)),
+		Sequencia:   extractEventSequence(string(eventXML)),
+	}
+
+	// Parse processing date
+	processedAt, err := time.Parse(time.RFC3339, jsonResp.DataHoraProcessamento)
+	if err != nil {
+		// Try alternative format
+		processedAt, err = time.Parse("2006-01-02T15:04:05-07:00", jsonResp.DataHoraProcessamento)
+		if err != nil {
+			c.logDebug("RegisterEvent: failed to parse date %s: %v", jsonResp.DataHoraProcessamento, err)
+			processedAt = time.Time{}
+		}
+	}
+	result.DataProcessamento = processedAt
+
+	return result, nil
+}
+
+// parseEventRegistrationRejection parses a rejection JSON response from the events API.
+func (c *ProductionClient) parseEventRegistrationRejection(chaveAcesso string, body []byte) (*EventRegistrationResult, error) {
+	var jsonResp eventRegistrationJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	result := &EventRegistrationResult{
+		Success:     false,
+		ChaveAcesso: chaveAcesso,
+	}
+
+	for i, erro := range jsonResp.Erros {
+		if i == 0 {
+			result.ErrorCode = erro.Codigo
+			result.ErrorMessage = erro.Descricao
+		}
+		result.ErrorCodes = append(result.ErrorCodes, erro.Codigo)
+	}
+
+	if result.ErrorCode == "" {
+		result.ErrorMessage = "event request rejected without error details"
+	}
+
+	return result, nil
+}
+
+// eventTypePattern matches the event group element (e.g. <e101101>) of an event XML.
+var eventTypePattern = regexp.MustCompile(`<(e\d{6})[\s/>]`)
+
+// eventSequencePattern matches the nSeqEvento element of a registered event XML.
+var eventSequencePattern = regexp.MustCompile(`<nSeqEvento>(\d+)</nSeqEvento>`)
+
+// extractEventType returns the event type code found in an event XML, if any.
+func extractEventType(eventXML string) string {
+	if m := eventTypePattern.FindStringSubmatch(eventXML); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// extractEventSequence returns the nSeqEvento found in a registered event XML, if any.
+func extractEventSequence(eventXML string) int {
+	m := eventSequencePattern.FindStringSubmatch(eventXML)
+	if m == nil {
+		return 0
+	}
+	seq, _ := strconv.Atoi(m[1])
+	return seq
+}
+
+// encodeGZipBase64 compresses data with GZip and encodes it in base64.
+func encodeGZipBase64(data []byte) (string, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeGZipBase64 decodes base64 data and decompresses it with GZip.
+func decodeGZipBase64(encoded string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip: %w", err)
+	}
+	defer gz.Close()
+
+	return io.ReadAll(gz)
+}
+
+// ================================================================================
+// Mock Event Registration Methods
+// ================================================================================
+
+// RegisterEvent simulates registering an event for an NFS-e.
+// The certificate parameter is ignored in the mock implementation.
+func (c *MockClient) RegisterEvent(ctx context.Context, chaveAcesso, pedRegEventoXML string, cert *tls.Certificate) (*EventRegistrationResult, error) {
+	start := time.Now()
+
+	// Add simulated latency
+	if c.SimulatedLatency > 0 {
+		select {
+		case <-time.After(c.SimulatedLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Simulate failure if configured
+	if c.SimulateFailure || c.shouldFail() {
+		return nil, ErrServiceUnavailable
+	}
+
+	// Return not found for specific test keys
+	if strings.HasPrefix(chaveAcesso, "NOTFOUND") {
+		return nil, ErrNFSeNotFound
+	}
+
+	// Reject events for keys starting with "CANCELLED" (NFS-e already cancelled)
+	if strings.HasPrefix(chaveAcesso, "CANCELLED") {
+		return &EventRegistrationResult{
+			Success:        false,
+			ChaveAcesso:    chaveAcesso,
+			ErrorCode:      "E002",
+			ErrorMessage:   "Mock rejection: NFS-e already cancelled",
+			ErrorCodes:     []string{"E002"},
+			ProcessingTime: time.Since(start),
+		}, nil
+	}
+
+	eventType := extractEventType(pedRegEventoXML)
+	sequence := 1
+
+	return &EventRegistrationResult{
+		Success:           true,
+		ChaveAcesso:       chaveAcesso,
+		TipoEvento:        eventType,
+		Sequencia:         sequence,
+		DataProcessamento: time.Now(),
+		EventXML:          generateMockEventXML(eventType, chaveAcesso, sequence),
+		ProcessingTime:    time.Since(start),
+	}, nil
+}
+
+// ================================================================================
+// Event Query Methods for ProductionClient
+// ================================================================================
+
+// eventProcessingDatePattern matches the dhProc element of a registered event XML.
+var eventProcessingDatePattern = regexp.MustCompile(`<dhProc>([^<]+)</dhProc>`)
+
+// eventAuthorCNPJPattern matches the CNPJAutor element of the embedded pedRegEvento.
+var eventAuthorCNPJPattern = regexp.MustCompile(`<CNPJAutor>(\d{14})</CNPJAutor>`)
+
+// eventAuthorCPFPattern matches the CPFAutor element of the embedded pedRegEvento.
+var eventAuthorCPFPattern = regexp.MustCompile(`<CPFAutor>(\d{11})</CPFAutor>`)
+
+// eventIDPattern matches the Id attribute of the infEvento element of a registered event XML.
+var eventIDPattern = regexp.MustCompile(`<infEvento[^>]*\sId="EVT(\d+)"`)
+
+// eventBlockedTypePattern matches the codEvento element of an ex officio block (e305102).
+var eventBlockedTypePattern = regexp.MustCompile(`<codEvento>(e\d{6})</codEvento>`)
+
+// eventBlockIDPattern matches the idBloqOfic element of an ex officio unblock (e305103).
+var eventBlockIDPattern = regexp.MustCompile(`<idBloqOfic>(\d+)</idBloqOfic>`)
+
+// QueryEventsByType retrieves the events of a single type for an NFS-e by its access key.
+// Returns an empty event list (not an error) if the NFS-e has no events of that type.
+func (c *ProductionClient) QueryEventsByType(ctx context.Context, chaveAcesso, tipoEvento string, cert *tls.Certificate) (*EventsQueryResult, error) {
+	if chaveAcesso == "" {
+		return nil, fmt.Errorf("chaveAcesso is required")
+	}
+	if tipoEvento == "" {
+		return nil, fmt.Errorf("tipoEvento is required")
+	}
+
+	// Build request URL
+	url := fmt.Sprintf("%s/nfse/%s/eventos/%s", c.baseURL, chaveAcesso, tipoEvento)
+
+	statusCode, body, err := c.getEventsResource(ctx, "QueryEventsByType", url, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle response status codes
+	switch statusCode {
+	case http.StatusOK:
+		return c.parseEventsQueryResponse(chaveAcesso, body)
+	case http.StatusNotFound:
+		return nil, ErrNFSeNotFound
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+	}
+}
+
+// QueryEvent retrieves a single event of an NFS-e identified by its type and sequence number.
+// Returns ErrEventNotFound if the government API has no such event.
+func (c *ProductionClient) QueryEvent(ctx context.Context, chaveAcesso, tipoEvento string, numSeqEvento int, cert *tls.Certificate) (*EventData, error) {
+	if chaveAcesso == "" {
+		return nil, fmt.Errorf("chaveAcesso is required")
+	}
+	if tipoEvento == "" {
+		return nil, fmt.Errorf("tipoEvento is required")
+	}
+	if numSeqEvento <= 0 {
+		return nil, fmt.Errorf("numSeqEvento must be positive")
+	}
+
+	// Build request URL
+	url := fmt.Sprintf("%s/nfse/%s/eventos/%s/%d", c.baseURL, chaveAcesso, tipoEvento, numSeqEvento)
+
+	statusCode, body, err := c.getEventsResource(ctx, "QueryEvent", url, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle response status codes
+	switch statusCode {
+	case http.StatusOK:
+		return c.parseEventQueryResponse(chaveAcesso, tipoEvento, numSeqEvento, body)
+	case http.StatusNotFound:
+		return nil, ErrEventNotFound
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+	}
+}
+
+// getEventsResource performs a GET request against an events endpoint and returns
+// the HTTP status code and raw response body.
+func (c *ProductionClient) getEventsResource(ctx context.Context, operation, url string, cert *tls.Certificate) (int, []byte, error) {
+	c.logDebug("%s: requesting %s", operation, url)
+
+	// Create HTTP client with provided certificate
+	httpClient := c.createQueryHTTPClient(cert)
+
+	// Create request with context
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute request
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout") {
+			return 0, nil, ErrTimeout
+		}
+		return 0, nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	c.logDebug("%s: response status=%d body=%s", operation, resp.StatusCode, string(bodyBytes))
+
+	return resp.StatusCode, bodyBytes, nil
+}
+
+// eventJSONResponse represents the JSON response of the single event query API.
+// The API may return either the compressed event document or the events list envelope.
+type eventJSONResponse struct {
+	eventsJSONResponse
+	DataHoraProcessamento string `json:"dataHoraProcessamento"`
+	EventoXMLGZipB64      string `json:"eventoXmlGZipB64"`
+}
+
+// parseEventQueryResponse parses the JSON response from the single event query API.
+func (c *ProductionClient) parseEventQueryResponse(chaveAcesso, tipoEvento string, numSeqEvento int, body []byte) (*EventData, error) {
+	var jsonResp eventJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	// Compressed event document
+	if jsonResp.EventoXMLGZipB64 != "" {
+		eventXML, err := decodeGZipBase64(jsonResp.EventoXMLGZipB64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode event XML: %w", err)
+		}
+
+		event := &EventData{
+			Tipo:      tipoEvento,
+			Sequencia: numSeqEvento,
+			XML:       string(eventXML),
+		}
+		if processedAt, ok := parseEventTimestamp(jsonResp.DataHoraProcessamento); ok {
+			event.DataProcessamento = processedAt
+			event.Data = processedAt
+		}
+		populateEventDetails(event)
+		return event, nil
+	}
+
+	// Events list envelope: pick the requested event
+	result, err := c.parseEventsQueryResponse(chaveAcesso, body)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Events {
+		evt := result.Events[i]
+		if evt.Tipo == tipoEvento && evt.Sequencia == numSeqEvento {
+			return &evt, nil
+		}
+	}
+
+	return nil, ErrEventNotFound
+}
+
+// parseEventTimestamp parses an event timestamp in RFC 3339 format.
+func parseEventTimestamp(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// populateEventDetails fills the event fields that can be extracted from the event XML
+// (type, sequence number, processing date and author) when they are not already set.
+func populateEventDetails(evt *EventData) {
+	if evt.XML == "" {
+		return
+	}
+
+	if evt.Tipo == "" {
+		evt.Tipo = extractEventType(evt.XML)
+	}
+	if evt.Sequencia == 0 {
+		evt.Sequencia = extractEventSequence(evt.XML)
+	}
+	if evt.DataProcessamento.IsZero() {
+		if m := eventProcessingDatePattern.FindStringSubmatch(evt.XML); m != nil {
+			if processedAt, ok := parseEventTimestamp(strings.TrimSpace(m[1])); ok {
+				evt.DataProcessamento = processedAt
+			}
+		}
+	}
+	if evt.AutorCNPJ == "" && evt.AutorCPF == "" {
+		if m := eventAuthorCNPJPattern.FindStringSubmatch(evt.XML); m != nil {
+			evt.AutorCNPJ = m[1]
+		} else if m := eventAuthorCPFPattern.FindStringSubmatch(evt.XML); m != nil {
+			evt.AutorCPF = m[1]
+		}
+	}
+	if evt.ID == "" {
+		if m := eventIDPattern.FindStringSubmatch(evt.XML); m != nil {
+			evt.ID = m[1]
+		}
+	}
+	if evt.CodEventoBloqueado == "" && evt.IDBloqueio == "" {
+		if m := eventBlockedTypePattern.FindStringSubmatch(evt.XML); m != nil {
+			evt.CodEventoBloqueado = m[1]
+		}
+		if m := eventBlockIDPattern.FindStringSubmatch(evt.XML); m != nil {
+			evt.IDBloqueio = m[1]
+		}
+	}
+}
+
+// ================================================================================
+// Mock Event Query Methods
+// ================================================================================
+
+// mockEventAuthorCNPJ is the author CNPJ used in mock event documents.
+const mockEventAuthorCNPJ = "12345678000199"
+
+// QueryEventsByType returns the mock events of a single type for an NFS-e.
+// The certificate parameter is ignored in the mock implementation.
+func (c *MockClient) QueryEventsByType(ctx context.Context, chaveAcesso, tipoEvento string, cert *tls.Certificate) (*EventsQueryResult, error) {
+	all, err := c.QueryEvents(ctx, chaveAcesso, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]EventData, 0, len(all.Events))
+	for _, evt := range all.Events {
+		if evt.Tipo == tipoEvento {
+			events = append(events, evt)
+		}
+	}
+
+	return &EventsQueryResult{
+		ChaveAcesso: chaveAcesso,
+		Events:      events,
+	}, nil
+}
+
+// QueryEvent returns a single mock event identified by its type and sequence number.
+// The certificate parameter is ignored in the mock implementation.
+func (c *MockClient) QueryEvent(ctx context.Context, chaveAcesso, tipoEvento string, numSeqEvento int, cert *tls.Certificate) (*EventData, error) {
+	result, err := c.QueryEventsByType(ctx, chaveAcesso, tipoEvento, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Events {
+		if result.Events[i].Sequencia == numSeqEvento {
+			evt := result.Events[i]
+			return &evt, nil
+		}
+	}
+
+	return nil, ErrEventNotFound
+}
@@ -0,0 +1,453 @@
+package sefin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPedRegEventoXML = `<?xml version="1.0" encoding="UTF-8"?>
+<pedRegEvento xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00">
+  <infPedReg Id="PRENFSe12345101101">
+    <chNFSe>NFSe12345</chNFSe>
+    <e101101>
+      <xDesc>Cancelamento de NFS-e</xDesc>
+      <cMotivo>1</cMotivo>
+      <xMotivo>Erro na emissao da nota fiscal</xMotivo>
+    </e101101>
+  </infPedReg>
+</pedRegEvento>`
+
+// newEventTestClient creates a ProductionClient pointing at the given test server.
+func newEventTestClient(t *testing.T, serverURL string) *ProductionClient {
+	t.Helper()
+
+	client, err := NewProductionClient(ClientConfig{
+		BaseURL:     serverURL,
+		Environment: EnvironmentHomologation,
+		Timeout:     10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+// ================================================================================
+// ProductionClient RegisterEvent Tests
+// ================================================================================
+
+func TestRegisterEvent_Success(t *testing.T) {
+	registeredXML := `<evento><infEvento Id="EVT1"><nSeqEvento>3</nSeqEvento><pedRegEvento><infPedReg><e101101/></infPedReg></pedRegEvento></infEvento></evento>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		if r.URL.Path != "/nfse/NFSe12345/eventos" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		sent, err := decodeGZipBase64(body["pedidoRegistroEventoXmlGZipB64"])
+		if err != nil {
+			t.Fatalf("failed to decode event XML: %v", err)
+		}
+		if string(sent) != testPedRegEventoXML {
+			t.Error("sent event XML does not match the original")
+		}
+
+		encoded, err := encodeGZipBase64([]byte(registeredXML))
+		if err != nil {
+			t.Fatalf("failed to encode response: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		response := map[string]interface{}{
+			"dataHoraProcessamento": "2026-01-08T10:30:00-03:00",
+			"eventoXmlGZipB64":      encoded,
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := newEventTestClient(t, server.URL)
+
+	result, err := client.RegisterEvent(context.Background(), "NFSe12345", testPedRegEventoXML, nil)
+	if err != nil {
+		t.Fatalf("RegisterEvent failed: %v", err)
+	}
+
+	if !result.Success {
+		t.Error("expected success")
+	}
+	if result.TipoEvento != "e101101" {
+		t.Errorf("expected TipoEvento e101101, got %s", result.TipoEvento)
+	}
+	if result.Sequencia != 3 {
+		t.Errorf("expected Sequencia 3, got %d", result.Sequencia)
+	}
+	if result.EventXML != registeredXML {
+		t.Error("expected decoded event XML")
+	}
+	if result.DataProcessamento.IsZero() {
+		t.Error("expected DataProcessamento to be parsed")
+	}
+}
+
+func TestRegisterEvent_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]interface{}{
+			"erros": []map[string]string{
+				{"codigo": "E0840", "descricao": "Prazo de cancelamento expirado"},
+				{"codigo": "E0841", "descricao": "Outro erro"},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := newEventTestClient(t, server.URL)
+
+	result, err := client.RegisterEvent(context.Background(), "NFSe12345", testPedRegEventoXML, nil)
+	if err != nil {
+		t.Fatalf("RegisterEvent failed: %v", err)
+	}
+
+	if result.Success {
+		t.Error("expected rejection")
+	}
+	if result.ErrorCode != "E0840" {
+		t.Errorf("expected ErrorCode E0840, got %s", result.ErrorCode)
+	}
+	if len(result.ErrorCodes) != 2 {
+		t.Errorf("expected 2 error codes, got %d", len(result.ErrorCodes))
+	}
+}
+
+func TestRegisterEvent_StatusErrors(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusNotFound, ErrNFSeNotFound},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		client := newEventTestClient(t, server.URL)
+		_, err := client.RegisterEvent(context.Background(), "NFSe12345", testPedRegEventoXML, nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.wantErr, err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestRegisterEvent_EmptyParameters(t *testing.T) {
+	client := newEventTestClient(t, "http://localhost")
+
+	if _, err := client.RegisterEvent(context.Background(), "", testPedRegEventoXML, nil); err == nil {
+		t.Error("expected error for empty chaveAcesso")
+	}
+	if _, err := client.RegisterEvent(context.Background(), "NFSe12345", "", nil); err == nil {
+		t.Error("expected error for empty event XML")
+	}
+}
+
+func TestGZipBase64_RoundTrip(t *testing.T) {
+	encoded, err := encodeGZipBase64([]byte(testPedRegEventoXML))
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	decoded, err := decodeGZipBase64(encoded)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	if string(decoded) != testPedRegEventoXML {
+		t.Error("round trip did not preserve content")
+	}
+
+	if _, err := decodeGZipBase64("not-base64!"); err == nil {
+		t.Error("expected error for invalid base64")
+	}
+}
+
+// ================================================================================
+// MockClient RegisterEvent Tests
+// ================================================================================
+
+func TestMockClient_RegisterEvent_Success(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	result, err := client.RegisterEvent(context.Background(), "NFSe12345", testPedRegEventoXML, nil)
+	if err != nil {
+		t.Fatalf("MockClient.RegisterEvent failed: %v", err)
+	}
+
+	if !result.Success {
+		t.Error("expected success")
+	}
+	if result.TipoEvento != "e101101" {
+		t.Errorf("expected TipoEvento e101101, got %s", result.TipoEvento)
+	}
+	if !strings.Contains(result.EventXML, "NFSe12345") {
+		t.Error("expected event XML to reference the access key")
+	}
+}
+
+func TestMockClient_RegisterEvent_AlreadyCancelled(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	result, err := client.RegisterEvent(context.Background(), "CANCELLED12345", testPedRegEventoXML, nil)
+	if err != nil {
+		t.Fatalf("MockClient.RegisterEvent failed: %v", err)
+	}
+
+	if result.Success {
+		t.Error("expected rejection for CANCELLED prefix")
+	}
+}
+
+func TestMockClient_RegisterEvent_NotFound(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	_, err := client.RegisterEvent(context.Background(), "NOTFOUND12345", testPedRegEventoXML, nil)
+	if !errors.Is(err, ErrNFSeNotFound) {
+		t.Errorf("expected ErrNFSeNotFound, got %v", err)
+	}
+}
+
+func TestMockClient_RegisterEvent_SimulateFailure(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+	client.SimulateFailure = true
+
+	_, err := client.RegisterEvent(context.Background(), "NFSe12345", testPedRegEventoXML, nil)
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("expected ErrServiceUnavailable, got %v", err)
+	}
+}
+
+// ================================================================================
+// ProductionClient Event Query Tests
+// ================================================================================
+
+const testRegisteredEventXML = `<evento versao="1.00"><infEvento Id="EVT1"><nSeqEvento>2</nSeqEvento><dhProc>2024-01-16T14:00:05-03:00</dhProc><pedRegEvento><infPedReg><CPFAutor>12345678909</CPFAutor><chNFSe>NFSe12345</chNFSe><e101101/></infPedReg></pedRegEvento></infEvento></evento>`
+
+func TestQueryEventsByType_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nfse/NFSe12345/eventos/e101101" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		response := map[string]interface{}{
+			"eventos": []map[string]interface{}{
+				{
+					"tipo": "e101101",
+					"data": "2024-01-16T14:00:00-03:00",
+					"xml":  testRegisteredEventXML,
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := newEventTestClient(t, server.URL)
+	result, err := client.QueryEventsByType(context.Background(), "NFSe12345", "e101101", nil)
+	if err != nil {
+		t.Fatalf("QueryEventsByType failed: %v", err)
+	}
+
+	if len(result.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(result.Events))
+	}
+
+	evt := result.Events[0]
+	if evt.Sequencia != 2 {
+		t.Errorf("expected sequence parsed from XML to be 2, got %d", evt.Sequencia)
+	}
+	if evt.AutorCPF != "12345678909" {
+		t.Errorf("expected author CPF 12345678909, got %q", evt.AutorCPF)
+	}
+	if evt.DataProcessamento.IsZero() {
+		t.Error("expected processing date to be parsed from dhProc")
+	}
+}
+
+func TestQueryEvent_CompressedDocument(t *testing.T) {
+	encoded, err := encodeGZipBase64([]byte(testRegisteredEventXML))
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nfse/NFSe12345/eventos/e101101/2" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"dataHoraProcessamento": "2024-01-16T14:00:05-03:00",
+			"eventoXmlGZipB64":      encoded,
+		})
+	}))
+	defer server.Close()
+
+	client := newEventTestClient(t, server.URL)
+	evt, err := client.QueryEvent(context.Background(), "NFSe12345", "e101101", 2, nil)
+	if err != nil {
+		t.Fatalf("QueryEvent failed: %v", err)
+	}
+
+	if evt.Tipo != "e101101" || evt.Sequencia != 2 {
+		t.Errorf("unexpected event %s/%d", evt.Tipo, evt.Sequencia)
+	}
+	if evt.XML != testRegisteredEventXML {
+		t.Error("expected decompressed event XML")
+	}
+	if evt.AutorCPF != "12345678909" {
+		t.Errorf("expected author CPF 12345678909, got %q", evt.AutorCPF)
+	}
+	if evt.DataProcessamento.IsZero() {
+		t.Error("expected processing date")
+	}
+}
+
+func TestQueryEvent_EventsEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"eventos": []map[string]interface{}{
+				{"tipo": "e101101", "sequencia": 1, "xml": "<evento/>"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newEventTestClient(t, server.URL)
+
+	if _, err := client.QueryEvent(context.Background(), "NFSe12345", "e101101", 1, nil); err != nil {
+		t.Errorf("expected event 1 to be found, got %v", err)
+	}
+	if _, err := client.QueryEvent(context.Background(), "NFSe12345", "e101101", 2, nil); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound for missing sequence, got %v", err)
+	}
+}
+
+func TestQueryEvent_StatusErrors(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusNotFound, ErrEventNotFound},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		client := newEventTestClient(t, server.URL)
+		_, err := client.QueryEvent(context.Background(), "NFSe12345", "e101101", 1, nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.wantErr, err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestQueryEvent_EmptyParameters(t *testing.T) {
+	client := newEventTestClient(t, "http://localhost")
+
+	if _, err := client.QueryEvent(context.Background(), "", "e101101", 1, nil); err == nil {
+		t.Error("expected error for empty chaveAcesso")
+	}
+	if _, err := client.QueryEvent(context.Background(), "NFSe12345", "", 1, nil); err == nil {
+		t.Error("expected error for empty tipoEvento")
+	}
+	if _, err := client.QueryEvent(context.Background(), "NFSe12345", "e101101", 0, nil); err == nil {
+		t.Error("expected error for non-positive numSeqEvento")
+	}
+}
+
+// ================================================================================
+// MockClient Event Query Tests
+// ================================================================================
+
+func TestMockClient_QueryEventsByType(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	result, err := client.QueryEventsByType(context.Background(), "CANCELLED12345", "e101101", nil)
+	if err != nil {
+		t.Fatalf("MockClient.QueryEventsByType failed: %v", err)
+	}
+	if len(result.Events) != 1 || result.Events[0].Tipo != "e101101" {
+		t.Fatalf("expected only the cancellation event, got %+v", result.Events)
+	}
+	if result.Events[0].AutorCNPJ != mockEventAuthorCNPJ {
+		t.Errorf("expected author CNPJ %s, got %q", mockEventAuthorCNPJ, result.Events[0].AutorCNPJ)
+	}
+
+	result, err = client.QueryEventsByType(context.Background(), "NFSe12345", "e101101", nil)
+	if err != nil {
+		t.Fatalf("MockClient.QueryEventsByType failed: %v", err)
+	}
+	if len(result.Events) != 0 {
+		t.Errorf("expected no cancellation events, got %d", len(result.Events))
+	}
+}
+
+func TestMockClient_QueryEvent(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	evt, err := client.QueryEvent(context.Background(), "CANCELLED12345", "e101101", 2, nil)
+	if err != nil {
+		t.Fatalf("MockClient.QueryEvent failed: %v", err)
+	}
+	if evt.Sequencia != 2 || evt.DataProcessamento.IsZero() {
+		t.Errorf("unexpected event %+v", evt)
+	}
+
+	if _, err := client.QueryEvent(context.Background(), "CANCELLED12345", "e101101", 3, nil); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound, got %v", err)
+	}
+	if _, err := client.QueryEvent(context.Background(), "NOTFOUND12345", "e101101", 1, nil); !errors.Is(err, ErrNFSeNotFound) {
+		t.Errorf("expected ErrNFSeNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,571 @@
+package sefin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ================================================================================
+// Municipal Parameters Types
+// ================================================================================
+
+// ConvenioResult holds the agreement (convênio) parameters of a municipality
+// with the Sistema Nacional NFS-e.
+type ConvenioResult struct {
+	// CodigoMunicipio is the 7-digit IBGE code of the municipality.
+	CodigoMunicipio string
+
+	// Conveniado indicates the municipality has an agreement with the Sistema Nacional NFS-e.
+	Conveniado bool
+
+	// AderenteAmbienteNacional indicates the municipality adhered to the national
+	// data environment (ADN).
+	AderenteAmbienteNacional bool
+
+	// AderenteEmissorNacional indicates the municipality adhered to the national
+	// emitter, so NFS-e can be issued through the Sefin Nacional API.
+	AderenteEmissorNacional bool
+
+	// AderenteMAN indicates the municipality adhered to the national tax
+	// assessment module (MAN).
+	AderenteMAN bool
+
+	// PermiteAproveitamentoCreditos indicates the municipality allows the use of tax credits.
+	PermiteAproveitamentoCreditos bool
+
+	// ConsultedAt is when the parameters were retrieved from the government API.
+	ConsultedAt time.Time
+}
+
+// CanEmit reports whether NFS-e issued in the municipality can be emitted
+// through the national emitter.
+func (r *ConvenioResult) CanEmit() bool {
+	return r.Conveniado && r.AderenteEmissorNacional
+}
+
+// ServiceParametersResult holds the parameters a municipality defines for a
+// national service code (cTribNac).
+type ServiceParametersResult struct {
+	// CodigoMunicipio is the 7-digit IBGE code of the municipality.
+	CodigoMunicipio string
+
+	// CodigoServico is the national service code (cTribNac).
+	CodigoServico string
+
+	// Descricao is the municipal description of the service.
+	Descricao string
+
+	// Aliquota is the ISS rate percentage the municipality applies to the service (pAliq).
+	Aliquota float64
+
+	// CodigoTributacaoMunicipal is the municipal tax code of the service (cTribMun), if any.
+	CodigoTributacaoMunicipal string
+
+	// ConsultedAt is when the parameters were retrieved from the government API.
+	ConsultedAt time.Time
+}
+
+// Simples Nacional options (opSimpNac) recorded for a contributor.
+const (
+	// OpcaoSimplesNacionalNaoOptante indicates the contributor is not a Simples Nacional optant.
+	OpcaoSimplesNacionalNaoOptante = 1
+
+	// OpcaoSimplesNacionalMEI indicates the contributor is a Microempreendedor Individual.
+	OpcaoSimplesNacionalMEI = 2
+
+	// OpcaoSimplesNacionalMEEPP indicates the contributor is a Microempresa or Empresa de Pequeno Porte.
+	OpcaoSimplesNacionalMEEPP = 3
+)
+
+// ContributorParametersResult holds the parameters a municipality has on record
+// for a contributor (CPF/CNPJ).
+type ContributorParametersResult struct {
+	// CodigoMunicipio is the 7-digit IBGE code of the municipality.
+	CodigoMunicipio string
+
+	// Documento is the CPF or CNPJ of the contributor.
+	Documento string
+
+	// Cadastrado indicates the municipality has parameters on record for the contributor.
+	Cadastrado bool
+
+	// OpcaoSimplesNacional is the Simples Nacional option on record (see OpcaoSimplesNacional*
+	// constants), or 0 if the municipality does not inform it.
+	OpcaoSimplesNacional int
+
+	// RegimesEspeciais lists the special taxation regimes granted to the contributor.
+	RegimesEspeciais []RegimeEspecial
+
+	// Beneficios lists the municipal benefits (BM) granted to the contributor.
+	Beneficios []BeneficioMunicipal
+
+	// Retencoes lists the ISS withholding rules applying to the contributor.
+	Retencoes []RetencaoMunicipal
+
+	// ConsultedAt is when the parameters were retrieved from the government API.
+	ConsultedAt time.Time
+}
+
+// RegimeEspecial is a special taxation regime (regEspTrib) granted to a contributor.
+type RegimeEspecial struct {
+	// Codigo is the special regime code (regEspTrib).
+	Codigo int
+
+	// Descricao is the description of the regime.
+	Descricao string
+}
+
+// BeneficioMunicipal is a municipal benefit (BM) granted to a contributor.
+type BeneficioMunicipal struct {
+	// Numero is the 14-digit benefit identifier (nBM).
+	Numero string
+
+	// Descricao is the description of the benefit.
+	Descricao string
+
+	// InicioVigencia is when the benefit starts to apply.
+	InicioVigencia *time.Time
+
+	// FimVigencia is when the benefit stops applying, if it has an end.
+	FimVigencia *time.Time
+}
+
+// IsActive reports whether the benefit applies on the given date.
+func (b *BeneficioMunicipal) IsActive(at time.Time) bool {
+	if b.InicioVigencia != nil && at.Before(*b.InicioVigencia) {
+		return false
+	}
+	if b.FimVigencia != nil && at.After(*b.FimVigencia) {
+		return false
+	}
+	return true
+}
+
+// RetencaoMunicipal is an ISS withholding rule applying to a contributor.
+type RetencaoMunicipal struct {
+	// TipoRetencao is the withholding type (tpRetISSQN): 2 = by the taker, 3 = by the intermediary.
+	TipoRetencao int
+
+	// Descricao is the description of the rule.
+	Descricao string
+}
+
+// FindBeneficio returns the benefit with the given number, or nil if the
+// contributor has no such benefit.
+func (r *ContributorParametersResult) FindBeneficio(numero string) *BeneficioMunicipal {
+	for i := range r.Beneficios {
+		if r.Beneficios[i].Numero == numero {
+			return &r.Beneficios[i]
+		}
+	}
+	return nil
+}
+
+// HasRegimeEspecial reports whether the special taxation regime is granted to the contributor.
+func (r *ContributorParametersResult) HasRegimeEspecial(codigo int) bool {
+	for _, regime := range r.RegimesEspeciais {
+		if regime.Codigo == codigo {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrServiceParametersNotFound is returned when a municipality has no parameters
+// for a service code.
+var ErrServiceParametersNotFound = errors.New("service parameters not found")
+
+// ================================================================================
+// Municipal Parameters Methods for ProductionClient
+// ================================================================================
+
+// QueryConvenio retrieves the agreement parameters of a municipality.
+// A municipality without parameters in the Sistema Nacional NFS-e is returned
+// as a result with Conveniado=false rather than an error.
+func (c *ProductionClient) QueryConvenio(ctx context.Context, codigoMunicipio string, cert *tls.Certificate) (*ConvenioResult, error) {
+	if codigoMunicipio == "" {
+		return nil, fmt.Errorf("codigoMunicipio is required")
+	}
+
+	url := fmt.Sprintf("%s/parametros_municipais/%s/convenio", c.baseURL, codigoMunicipio)
+
+	statusCode, body, err := c.getEventsResource(ctx, "QueryConvenio", url, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+		return parseConvenioResponse(codigoMunicipio, body)
+	case http.StatusNotFound:
+		return &ConvenioResult{
+			CodigoMunicipio: codigoMunicipio,
+			ConsultedAt:     time.Now(),
+		}, nil
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+	}
+}
+
+// convenioJSONResponse represents the JSON response of the municipal agreement API.
+// Adherence flags are returned as 1 (adhered) or 0 (not adhered).
+type convenioJSONResponse struct {
+	ParametrosConvenio *struct {
+		AderenteAmbienteNacional       int  `json:"aderenteAmbienteNacional"`
+		AderenteEmissorNacional        int  `json:"aderenteEmissorNacional"`
+		AderenteMAN                    int  `json:"aderenteMAN"`
+		PermiteAproveitametoDeCreditos bool `json:"permiteAproveitametoDeCreditos"`
+	} `json:"parametrosConvenio"`
+}
+
+// parseConvenioResponse parses the JSON response of the municipal agreement API.
+func parseConvenioResponse(codigoMunicipio string, body []byte) (*ConvenioResult, error) {
+	var jsonResp convenioJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse convenio response: %w", err)
+	}
+
+	result := &ConvenioResult{
+		CodigoMunicipio: codigoMunicipio,
+		ConsultedAt:     time.Now(),
+	}
+
+	// No parameters means the municipality has no agreement
+	params := jsonResp.ParametrosConvenio
+	if params == nil {
+		return result, nil
+	}
+
+	result.Conveniado = true
+	result.AderenteAmbienteNacional = params.AderenteAmbienteNacional == 1
+	result.AderenteEmissorNacional = params.AderenteEmissorNacional == 1
+	result.AderenteMAN = params.AderenteMAN == 1
+	result.PermiteAproveitamentoCreditos = params.PermiteAproveitametoDeCreditos
+
+	return result, nil
+}
+
+// QueryServiceParameters retrieves the parameters of a national service code in a
+// municipality, including the ISS rate.
+// Returns ErrServiceParametersNotFound if the municipality has no parameters for the service.
+func (c *ProductionClient) QueryServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string, cert *tls.Certificate) (*ServiceParametersResult, error) {
+	if codigoMunicipio == "" {
+		return nil, fmt.Errorf("codigoMunicipio is required")
+	}
+	if codigoServico == "" {
+		return nil, fmt.Errorf("codigoServico is required")
+	}
+
+	url := fmt.Sprintf("%s/parametros_municipais/%s/%s", c.baseURL, codigoMunicipio, codigoServico)
+
+	statusCode, body, err := c.getEventsResource(ctx, "QueryServiceParameters", url, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+		return parseServiceParametersResponse(codigoMunicipio, codigoServico, body)
+	case http.StatusNotFound:
+		return nil, ErrServiceParametersNotFound
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+	}
+}
+
+// serviceParametersJSONResponse represents the JSON response of the municipal
+// service parameters API.
+type serviceParametersJSONResponse struct {
+	ParametrosServico *struct {
+		Descricao                 string  `json:"descricao"`
+		Aliquota                  float64 `json:"aliquota"`
+		CodigoTributacaoMunicipal string  `json:"codigoTributacaoMunicipal"`
+	} `json:"parametrosServico"`
+}
+
+// parseServiceParametersResponse parses the JSON response of the municipal service parameters API.
+func parseServiceParametersResponse(codigoMunicipio, codigoServico string, body []byte) (*ServiceParametersResult, error) {
+	var jsonResp serviceParametersJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse service parameters response: %w", err)
+	}
+
+	params := jsonResp.ParametrosServico
+	if params == nil {
+		return nil, ErrServiceParametersNotFound
+	}
+
+	return &ServiceParametersResult{
+		CodigoMunicipio:           codigoMunicipio,
+		CodigoServico:             codigoServico,
+		Descricao:                 params.Descricao,
+		Aliquota:                  params.Aliquota,
+		CodigoTributacaoMunicipal: params.CodigoTributacaoMunicipal,
+		ConsultedAt:               time.Now(),
+	}, nil
+}
+
+// QueryContributorParameters retrieves the parameters a municipality has on record
+// for a contributor: special regimes, municipal benefits and withholding rules.
+// A contributor without parameters is returned as a result with Cadastrado=false
+// rather than an error.
+func (c *ProductionClient) QueryContributorParameters(ctx context.Context, codigoMunicipio, documento string, cert *tls.Certificate) (*ContributorParametersResult, error) {
+	if codigoMunicipio == "" {
+		return nil, fmt.Errorf("codigoMunicipio is required")
+	}
+	if documento == "" {
+		return nil, fmt.Errorf("documento is required")
+	}
+
+	url := fmt.Sprintf("%s/parametros_municipais/%s/%s", c.baseURL, codigoMunicipio, documento)
+
+	statusCode, body, err := c.getEventsResource(ctx, "QueryContributorParameters", url, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+		return parseContributorParametersResponse(codigoMunicipio, documento, body)
+	case http.StatusNotFound:
+		return &ContributorParametersResult{
+			CodigoMunicipio: codigoMunicipio,
+			Documento:       documento,
+			ConsultedAt:     time.Now(),
+		}, nil
+	case http.StatusForbidden:
+		return nil, ErrForbidden
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return nil, ErrServiceUnavailable
+	default:
+		return nil, fmt.Errorf("unexpected HTTP status: %d", statusCode)
+	}
+}
+
+// contributorParametersJSONResponse represents the JSON response of the municipal
+// contributor parameters API. Dates are returned as YYYY-MM-DD.
+type contributorParametersJSONResponse struct {
+	ParametrosContribuinte *struct {
+		OpcaoSimplesNacional int `json:"opcaoSimplesNacional"`
+		RegimesEspeciais     []struct {
+			Codigo    int    `json:"codigo"`
+			Descricao string `json:"descricao"`
+		} `json:"regimesEspeciais"`
+		Beneficios []struct {
+			NumeroBeneficio    string `json:"numeroBeneficio"`
+			Descricao          string `json:"descricao"`
+			DataInicioVigencia string `json:"dataInicioVigencia"`
+			DataFimVigencia    string `json:"dataFimVigencia"`
+		} `json:"beneficios"`
+		Retencoes []struct {
+			TipoRetencao int    `json:"tipoRetencao"`
+			Descricao    string `json:"descricao"`
+		} `json:"retencoes"`
+	} `json:"parametrosContribuinte"`
+}
+
+// parseContributorParametersResponse parses the JSON response of the municipal
+// contributor parameters API.
+func parseContributorParametersResponse(codigoMunicipio, documento string, body []byte) (*ContributorParametersResult, error) {
+	var jsonResp contributorParametersJSONResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to parse contributor parameters response: %w", err)
+	}
+
+	result := &ContributorParametersResult{
+		CodigoMunicipio: codigoMunicipio,
+		Documento:       documento,
+		ConsultedAt:     time.Now(),
+	}
+
+	params := jsonResp.ParametrosContribuinte
+	if params == nil {
+		return result, nil
+	}
+
+	result.Cadastrado = true
+	result.OpcaoSimplesNacional = params.OpcaoSimplesNacional
+
+	for _, r := range params.RegimesEspeciais {
+		result.RegimesEspeciais = append(result.RegimesEspeciais, RegimeEspecial{
+			Codigo:    r.Codigo,
+			Descricao: r.Descricao,
+		})
+	}
+
+	for _, b := range params.Beneficios {
+		beneficio := BeneficioMunicipal{
+			Numero:    b.NumeroBeneficio,
+			Descricao: b.Descricao,
+		}
+		if t, err := time.Parse("2006-01-02", b.DataInicioVigencia); err == nil {
+			beneficio.InicioVigencia = &t
+		}
+		if t, err := time.Parse("2006-01-02", b.DataFimVigencia); err == nil {
+			// The benefit applies during the whole last day
+			end := t.Add(24*time.Hour - time.Nanosecond)
+			beneficio.FimVigencia = &end
+		}
+		result.Beneficios = append(result.Beneficios, beneficio)
+	}
+
+	for _, r := range params.Retencoes {
+		result.Retencoes = append(result.Retencoes, RetencaoMunicipal{
+			TipoRetencao: r.TipoRetencao,
+			Descricao:    r.Descricao,
+		})
+	}
+
+	return result, nil
+}
+
+// ================================================================================
+// Mock Municipal Parameters Methods
+// ================================================================================
+
+const (
+	// MockMunicipalityWithoutConvenio is a municipality code for which the mock
+	// client reports no agreement with the Sistema Nacional NFS-e.
+	MockMunicipalityWithoutConvenio = "9999999"
+
+	// MockMunicipalityOwnEmitter is a municipality code for which the mock client
+	// reports an agreement without adherence to the national emitter.
+	MockMunicipalityOwnEmitter = "9999998"
+
+	// MockServiceWithoutParameters is a national service code for which the mock
+	// client reports no municipal parameters.
+	MockServiceWithoutParameters = "999999"
+
+	// MockISSRate is the ISS rate percentage the mock client returns for every service.
+	MockISSRate = 2.0
+
+	// MockContributorWithoutParameters is a CNPJ for which the mock client reports
+	// no contributor parameters.
+	MockContributorWithoutParameters = "99999999000191"
+
+	// MockBenefitSequence is the sequence of the municipal benefit the mock client
+	// grants to every contributor. The benefit number is the municipality code
+	// followed by "04" (other benefits) and this sequence.
+	MockBenefitSequence = "00001"
+)
+
+// QueryConvenio simulates retrieving the agreement parameters of a municipality.
+// Every municipality is adhered except the Mock* municipality codes.
+func (c *MockClient) QueryConvenio(ctx context.Context, codigoMunicipio string, cert *tls.Certificate) (*ConvenioResult, error) {
+	// Add simulated latency
+	if c.SimulatedLatency > 0 {
+		select {
+		case <-time.After(c.SimulatedLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Simulate failure if configured
+	if c.SimulateFailure || c.shouldFail() {
+		return nil, ErrServiceUnavailable
+	}
+
+	result := &ConvenioResult{
+		CodigoMunicipio: codigoMunicipio,
+		ConsultedAt:     time.Now(),
+	}
+
+	switch codigoMunicipio {
+	case MockMunicipalityWithoutConvenio:
+		return result, nil
+	case MockMunicipalityOwnEmitter:
+		result.Conveniado = true
+		result.AderenteAmbienteNacional = true
+		return result, nil
+	}
+
+	result.Conveniado = true
+	result.AderenteAmbienteNacional = true
+	result.AderenteEmissorNacional = true
+	result.AderenteMAN = true
+	return result, nil
+}
+
+// QueryServiceParameters simulates retrieving the parameters of a service in a municipality.
+// Every service has the MockISSRate rate except MockServiceWithoutParameters.
+func (c *MockClient) QueryServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string, cert *tls.Certificate) (*ServiceParametersResult, error) {
+	// Add simulated latency
+	if c.SimulatedLatency > 0 {
+		select {
+		case <-time.After(c.SimulatedLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Simulate failure if configured
+	if c.SimulateFailure || c.shouldFail() {
+		return nil, ErrServiceUnavailable
+	}
+
+	if codigoServico == MockServiceWithoutParameters {
+		return nil, ErrServiceParametersNotFound
+	}
+
+	return &ServiceParametersResult{
+		CodigoMunicipio:           codigoMunicipio,
+		CodigoServico:             codigoServico,
+		Descricao:                 "Servico simulado",
+		Aliquota:                  MockISSRate,
+		CodigoTributacaoMunicipal: "001",
+		ConsultedAt:               time.Now(),
+	}, nil
+}
+
+// QueryContributorParameters simulates retrieving the parameters of a contributor.
+// Every contributor except MockContributorWithoutParameters is a ME/EPP with one
+// active municipal benefit.
+func (c *MockClient) QueryContributorParameters(ctx context.Context, codigoMunicipio, documento string, cert *tls.Certificate) (*ContributorParametersResult, error) {
+	// Add simulated latency
+	if c.SimulatedLatency > 0 {
+		select {
+		case <-time.After(c.SimulatedLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Simulate failure if configured
+	if c.SimulateFailure || c.shouldFail() {
+		return nil, ErrServiceUnavailable
+	}
+
+	result := &ContributorParametersResult{
+		CodigoMunicipio: codigoMunicipio,
+		Documento:       documento,
+		ConsultedAt:     time.Now(),
+	}
+
+	if documento == MockContributorWithoutParameters {
+		return result, nil
+	}
+
+	result.Cadastrado = true
+	result.OpcaoSimplesNacional = OpcaoSimplesNacionalMEEPP
+	result.Beneficios = []BeneficioMunicipal{
+		{
+			Numero:    codigoMunicipio + "04" + MockBenefitSequence,
+			Descricao: "Beneficio simulado",
+		},
+	}
+	return result, nil
+}
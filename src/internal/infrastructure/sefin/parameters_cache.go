@@ -0,0 +1,458 @@
+package sefin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache resources group the cached municipal parameters by lookup, each with its own TTL.
+const (
+	// CacheResourceConvenio is the agreement of a municipality (GET /parametros_municipais/{codigoMunicipio}/convenio).
+	CacheResourceConvenio = "convenio"
+
+	// CacheResourceServiceParameters is the parameters of a service in a municipality.
+	CacheResourceServiceParameters = "servico"
+
+	// CacheResourceContributorParameters is the parameters of a contributor in a municipality.
+	CacheResourceContributorParameters = "contribuinte"
+)
+
+// CacheResources lists every cached resource.
+var CacheResources = []string{
+	CacheResourceConvenio,
+	CacheResourceServiceParameters,
+	CacheResourceContributorParameters,
+}
+
+const (
+	// DefaultParametersCacheTTL is the default time municipal parameters are cached.
+	// Municipal agreements change rarely, so a day avoids querying the government
+	// API on every emission.
+	DefaultParametersCacheTTL = 24 * time.Hour
+
+	// DefaultContributorParametersCacheTTL is the default time contributor parameters
+	// are cached. Benefits and regimes are granted during the day, so they expire sooner.
+	DefaultContributorParametersCacheTTL = 6 * time.Hour
+
+	// DefaultParametersCacheStaleTTL is the default time an expired result is still
+	// served while it is refreshed in the background.
+	DefaultParametersCacheStaleTTL = time.Hour
+
+	// DefaultParametersCacheNegativeTTL is the default time a negative answer
+	// (no agreement, no parameters on record) is cached.
+	DefaultParametersCacheNegativeTTL = 30 * time.Minute
+
+	// parametersRefreshTimeout bounds a background refresh of a stale result.
+	parametersRefreshTimeout = 30 * time.Second
+)
+
+// ErrUnknownCacheResource is returned when invalidating a resource that is not cached.
+var ErrUnknownCacheResource = errors.New("unknown cache resource")
+
+// CacheStore stores serialized cache entries.
+// Implementations include the in-memory store used by default and the Redis
+// store shared by the API and worker instances.
+type CacheStore interface {
+	// Get returns the entry stored under key; found is false when it does not exist.
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+
+	// Set stores an entry, removing it after expiration.
+	Set(ctx context.Context, key string, value []byte, expiration time.Duration) error
+
+	// DeletePrefix removes every entry whose key starts with prefix and returns how many were removed.
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// ParametersCache caches the municipal parameters retrieved from the government API.
+//
+// Results are fresh for the TTL of their resource. After that they are served
+// for StaleTTL more while a background refresh queries the API again
+// (stale-while-revalidate). Negative answers are cached for NegativeTTL.
+// Lookup errors are not cached, so a failed lookup is retried on the next call.
+// Store failures are logged and treated as misses, so the cache never blocks a lookup.
+type ParametersCache struct {
+	client SefinClient
+	store  CacheStore
+
+	ttls        map[string]time.Duration
+	staleTTL    time.Duration
+	negativeTTL time.Duration
+
+	// now returns the current time (overridden in tests).
+	now func() time.Time
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+	refreshes  sync.WaitGroup
+}
+
+// ParametersCacheConfig configures the municipal parameters cache.
+type ParametersCacheConfig struct {
+	// Client is the SEFIN client used on cache misses.
+	Client SefinClient
+
+	// Store keeps the cached entries (default: in process memory).
+	Store CacheStore
+
+	// ConvenioTTL is how long municipal agreements are cached (default: 24 hours).
+	ConvenioTTL time.Duration
+
+	// ServiceParametersTTL is how long service parameters are cached (default: 24 hours).
+	ServiceParametersTTL time.Duration
+
+	// ContributorParametersTTL is how long contributor parameters are cached (default: 6 hours).
+	ContributorParametersTTL time.Duration
+
+	// StaleTTL is how long an expired result is served while refreshed (default: 1 hour).
+	// Set to a negative value to disable stale-while-revalidate.
+	StaleTTL time.Duration
+
+	// NegativeTTL is how long negative answers are cached (default: 30 minutes).
+	// Set to a negative value to disable negative caching.
+	NegativeTTL time.Duration
+}
+
+// NewParametersCache creates a new municipal parameters cache.
+func NewParametersCache(config ParametersCacheConfig) *ParametersCache {
+	if config.Store == nil {
+		config.Store = newMemoryCacheStore()
+	}
+	if config.ConvenioTTL <= 0 {
+		config.ConvenioTTL = DefaultParametersCacheTTL
+	}
+	if config.ServiceParametersTTL <= 0 {
+		config.ServiceParametersTTL = DefaultParametersCacheTTL
+	}
+	if config.ContributorParametersTTL <= 0 {
+		config.ContributorParametersTTL = DefaultContributorParametersCacheTTL
+	}
+	if config.StaleTTL == 0 {
+		config.StaleTTL = DefaultParametersCacheStaleTTL
+	} else if config.StaleTTL < 0 {
+		config.StaleTTL = 0
+	}
+	if config.NegativeTTL == 0 {
+		config.NegativeTTL = DefaultParametersCacheNegativeTTL
+	} else if config.NegativeTTL < 0 {
+		config.NegativeTTL = 0
+	}
+
+	return &ParametersCache{
+		client: config.Client,
+		store:  config.Store,
+		ttls: map[string]time.Duration{
+			CacheResourceConvenio:              config.ConvenioTTL,
+			CacheResourceServiceParameters:     config.ServiceParametersTTL,
+			CacheResourceContributorParameters: config.ContributorParametersTTL,
+		},
+		staleTTL:    config.StaleTTL,
+		negativeTTL: config.NegativeTTL,
+		now:         time.Now,
+		refreshing:  make(map[string]bool),
+	}
+}
+
+// GetConvenio returns the agreement parameters of a municipality, querying the
+// government API when they are not cached or have expired.
+// Municipalities without an agreement are cached as negative answers.
+func (c *ParametersCache) GetConvenio(ctx context.Context, codigoMunicipio string) (*ConvenioResult, error) {
+	var result ConvenioResult
+	err := c.fetch(ctx, CacheResourceConvenio, codigoMunicipio, &result, nil, func(ctx context.Context) (interface{}, bool, error) {
+		convenio, err := c.client.QueryConvenio(ctx, codigoMunicipio, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		return convenio, !convenio.Conveniado, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetServiceParameters returns the parameters of a service in a municipality,
+// querying the government API when they are not cached or have expired.
+// Services without parameters are cached as negative answers and keep
+// returning ErrServiceParametersNotFound.
+func (c *ParametersCache) GetServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string) (*ServiceParametersResult, error) {
+	var result ServiceParametersResult
+	key := codigoMunicipio + "/" + codigoServico
+	err := c.fetch(ctx, CacheResourceServiceParameters, key, &result, ErrServiceParametersNotFound, func(ctx context.Context) (interface{}, bool, error) {
+		params, err := c.client.QueryServiceParameters(ctx, codigoMunicipio, codigoServico, nil)
+		return params, false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetContributorParameters returns the parameters of a contributor in a municipality,
+// querying the government API when they are not cached or have expired.
+// Contributors without parameters on record are cached as negative answers.
+func (c *ParametersCache) GetContributorParameters(ctx context.Context, codigoMunicipio, documento string) (*ContributorParametersResult, error) {
+	var result ContributorParametersResult
+	key := codigoMunicipio + "/" + documento
+	err := c.fetch(ctx, CacheResourceContributorParameters, key, &result, nil, func(ctx context.Context) (interface{}, bool, error) {
+		params, err := c.client.QueryContributorParameters(ctx, codigoMunicipio, documento, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		return params, !params.Cadastrado, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Invalidate removes cached parameters so the next lookup queries the government API.
+// An empty resource removes every resource, and an empty codigoMunicipio removes
+// the entries of every municipality. Returns the number of removed entries.
+func (c *ParametersCache) Invalidate(ctx context.Context, resource, codigoMunicipio string) (int, error) {
+	resources := CacheResources
+	if resource != "" {
+		if _, ok := c.ttls[resource]; !ok {
+			return 0, fmt.Errorf("%w: %s", ErrUnknownCacheResource, resource)
+		}
+		resources = []string{resource}
+	}
+
+	deleted := 0
+	for _, r := range resources {
+		n, err := c.store.DeletePrefix(ctx, cacheKey(r, codigoMunicipio))
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to invalidate %s cache: %w", r, err)
+		}
+	}
+
+	return deleted, nil
+}
+
+// cacheEntry is the serialized form of a cached lookup.
+type cacheEntry struct {
+	// StoredAt is when the lookup was made.
+	StoredAt time.Time `json:"stored_at"`
+
+	// Negative marks answers cached for the negative TTL.
+	Negative bool `json:"negative,omitempty"`
+
+	// NotFound marks lookups that returned the resource's not found error.
+	NotFound bool `json:"not_found,omitempty"`
+
+	// Value is the JSON encoded lookup result.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// parametersLoader queries the government API, reporting whether the result is a negative answer.
+type parametersLoader func(ctx context.Context) (value interface{}, negative bool, err error)
+
+// fetch decodes the cached result of a lookup into dst, loading it when it is
+// missing or expired. notFound is the error a missing result is reported with,
+// which is cached as a negative answer; it is nil for resources without one.
+func (c *ParametersCache) fetch(ctx context.Context, resource, key string, dst interface{}, notFound error, load parametersLoader) error {
+	storeKey := cacheKey(resource, key)
+
+	if entry, ok := c.read(ctx, storeKey); ok {
+		age := c.now().Sub(entry.StoredAt)
+		ttl := c.entryTTL(resource, entry)
+
+		if age < ttl {
+			return entry.decode(dst, notFound)
+		}
+		// Serve the stale result and refresh it in the background
+		if !entry.Negative && age < ttl+c.staleTTL {
+			c.revalidate(resource, storeKey, notFound, load)
+			return entry.decode(dst, notFound)
+		}
+	}
+
+	entry, err := c.load(ctx, resource, storeKey, notFound, load)
+	if err != nil {
+		return err
+	}
+	return entry.decode(dst, notFound)
+}
+
+// load queries the government API and stores the result.
+func (c *ParametersCache) load(ctx context.Context, resource, storeKey string, notFound error, load parametersLoader) (*cacheEntry, error) {
+	value, negative, err := load(ctx)
+	if err != nil {
+		if notFound == nil || !errors.Is(err, notFound) {
+			return nil, err
+		}
+		entry := &cacheEntry{StoredAt: c.now(), Negative: true, NotFound: true}
+		c.write(ctx, resource, storeKey, entry)
+		return entry, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s parameters: %w", resource, err)
+	}
+
+	entry := &cacheEntry{StoredAt: c.now(), Negative: negative, Value: data}
+	c.write(ctx, resource, storeKey, entry)
+	return entry, nil
+}
+
+// revalidate refreshes a stale entry in the background, once per key at a time.
+func (c *ParametersCache) revalidate(resource, storeKey string, notFound error, load parametersLoader) {
+	c.mu.Lock()
+	if c.refreshing[storeKey] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[storeKey] = true
+	c.mu.Unlock()
+
+	c.refreshes.Add(1)
+	go func() {
+		defer c.refreshes.Done()
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, storeKey)
+			c.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), parametersRefreshTimeout)
+		defer cancel()
+
+		if _, err := c.load(ctx, resource, storeKey, notFound, load); err != nil {
+			log.Printf("WARN: Failed to refresh cached %s parameters %s: %v", resource, storeKey, err)
+		}
+	}()
+}
+
+// read returns the stored entry for a key, treating store failures as misses.
+func (c *ParametersCache) read(ctx context.Context, storeKey string) (*cacheEntry, bool) {
+	data, found, err := c.store.Get(ctx, storeKey)
+	if err != nil {
+		log.Printf("WARN: Failed to read cached parameters %s: %v", storeKey, err)
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Printf("WARN: Discarding malformed cached parameters %s: %v", storeKey, err)
+		return nil, false
+	}
+	return &entry, true
+}
+
+// write stores an entry until it can no longer be served.
+// Negative answers are not stored when negative caching is disabled.
+func (c *ParametersCache) write(ctx context.Context, resource, storeKey string, entry *cacheEntry) {
+	expiration := c.entryTTL(resource, entry)
+	if !entry.Negative {
+		expiration += c.staleTTL
+	}
+	if expiration <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("WARN: Failed to encode cached parameters %s: %v", storeKey, err)
+		return
+	}
+	if err := c.store.Set(ctx, storeKey, data, expiration); err != nil {
+		log.Printf("WARN: Failed to cache parameters %s: %v", storeKey, err)
+	}
+}
+
+// entryTTL returns how long an entry of the resource is fresh.
+func (c *ParametersCache) entryTTL(resource string, entry *cacheEntry) time.Duration {
+	if entry.Negative {
+		return c.negativeTTL
+	}
+	return c.ttls[resource]
+}
+
+// decode decodes the cached result into dst, or returns the not found error it records.
+func (e *cacheEntry) decode(dst interface{}, notFound error) error {
+	if e.NotFound {
+		return notFound
+	}
+	if err := json.Unmarshal(e.Value, dst); err != nil {
+		return fmt.Errorf("failed to decode cached parameters: %w", err)
+	}
+	return nil
+}
+
+// cacheKey builds the store key of a resource lookup.
+func cacheKey(resource, key string) string {
+	return resource + ":" + key
+}
+
+// memoryCacheStore is a CacheStore kept in process memory.
+type memoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+// memoryCacheEntry is an entry of the in-memory store.
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// newMemoryCacheStore creates an empty in-memory cache store.
+func newMemoryCacheStore() *memoryCacheStore {
+	return &memoryCacheStore{
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns the entry stored under key, if it has not expired.
+func (s *memoryCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores an entry until expiration.
+func (s *memoryCacheStore) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryCacheEntry{
+		value:     value,
+		expiresAt: time.Now().Add(expiration),
+	}
+	return nil
+}
+
+// DeletePrefix removes every entry whose key starts with prefix.
+func (s *memoryCacheStore) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
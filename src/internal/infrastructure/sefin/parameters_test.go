@@ -0,0 +1,522 @@
+package sefin
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ================================================================================
+// ProductionClient Municipal Parameters Tests
+// ================================================================================
+
+func TestQueryConvenio_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path != "/parametros_municipais/3550308/convenio" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"mensagem": "Parâmetros do convênio recuperados com sucesso.",
+			"parametrosConvenio": {
+				"aderenteAmbienteNacional": 1,
+				"aderenteEmissorNacional": 1,
+				"aderenteMAN": 0,
+				"permiteAproveitametoDeCreditos": true
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	result, err := client.QueryConvenio(context.Background(), "3550308", nil)
+	if err != nil {
+		t.Fatalf("QueryConvenio failed: %v", err)
+	}
+
+	if result.CodigoMunicipio != "3550308" {
+		t.Errorf("expected municipality 3550308, got %s", result.CodigoMunicipio)
+	}
+	if !result.Conveniado || !result.AderenteAmbienteNacional || !result.AderenteEmissorNacional {
+		t.Errorf("expected adhered municipality, got %+v", result)
+	}
+	if result.AderenteMAN {
+		t.Error("expected municipality not adhered to MAN")
+	}
+	if !result.PermiteAproveitamentoCreditos {
+		t.Error("expected municipality to allow tax credits")
+	}
+	if !result.CanEmit() {
+		t.Error("expected CanEmit to be true")
+	}
+	if result.ConsultedAt.IsZero() {
+		t.Error("expected ConsultedAt to be set")
+	}
+}
+
+func TestQueryConvenio_NotConveniado(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "not found", status: http.StatusNotFound},
+		{name: "no parameters", status: http.StatusOK, body: `{"parametrosConvenio": null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newDistributionTestClient(t, server.URL)
+			result, err := client.QueryConvenio(context.Background(), "3550308", nil)
+			if err != nil {
+				t.Fatalf("QueryConvenio failed: %v", err)
+			}
+			if result.Conveniado || result.CanEmit() {
+				t.Errorf("expected municipality without agreement, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestQueryConvenio_StatusErrors(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+		{http.StatusTooManyRequests, ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		client := newDistributionTestClient(t, server.URL)
+		_, err := client.QueryConvenio(context.Background(), "3550308", nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.wantErr, err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestQueryServiceParameters_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/parametros_municipais/3550308/010101" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"parametrosServico": {
+				"descricao": "Análise e desenvolvimento de sistemas",
+				"aliquota": 2.9,
+				"codigoTributacaoMunicipal": "002"
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	result, err := client.QueryServiceParameters(context.Background(), "3550308", "010101", nil)
+	if err != nil {
+		t.Fatalf("QueryServiceParameters failed: %v", err)
+	}
+
+	if result.Aliquota != 2.9 {
+		t.Errorf("expected rate 2.9, got %v", result.Aliquota)
+	}
+	if result.CodigoTributacaoMunicipal != "002" {
+		t.Errorf("expected municipal tax code 002, got %s", result.CodigoTributacaoMunicipal)
+	}
+	if result.CodigoMunicipio != "3550308" || result.CodigoServico != "010101" {
+		t.Errorf("unexpected identification %s/%s", result.CodigoMunicipio, result.CodigoServico)
+	}
+}
+
+func TestQueryServiceParameters_Errors(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		wantErr error
+	}{
+		{http.StatusNotFound, "", ErrServiceParametersNotFound},
+		{http.StatusOK, `{"parametrosServico": null}`, ErrServiceParametersNotFound},
+		{http.StatusForbidden, "", ErrForbidden},
+		{http.StatusServiceUnavailable, "", ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		client := newDistributionTestClient(t, server.URL)
+		_, err := client.QueryServiceParameters(context.Background(), "3550308", "010101", nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.wantErr, err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestQueryContributorParameters_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/parametros_municipais/3550308/11222333000181" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"parametrosContribuinte": {
+				"opcaoSimplesNacional": 3,
+				"regimesEspeciais": [{"codigo": 1, "descricao": "Ato Cooperado"}],
+				"beneficios": [{
+					"numeroBeneficio": "35503080400001",
+					"descricao": "Redução de base de cálculo",
+					"dataInicioVigencia": "2025-01-01",
+					"dataFimVigencia": "2025-12-31"
+				}],
+				"retencoes": [{"tipoRetencao": 2, "descricao": "Retido pelo tomador"}]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	result, err := client.QueryContributorParameters(context.Background(), "3550308", "11222333000181", nil)
+	if err != nil {
+		t.Fatalf("QueryContributorParameters failed: %v", err)
+	}
+
+	if !result.Cadastrado {
+		t.Error("expected contributor to be on record")
+	}
+	if result.OpcaoSimplesNacional != OpcaoSimplesNacionalMEEPP {
+		t.Errorf("expected Simples Nacional option %d, got %d", OpcaoSimplesNacionalMEEPP, result.OpcaoSimplesNacional)
+	}
+	if len(result.RegimesEspeciais) != 1 || len(result.Retencoes) != 1 {
+		t.Errorf("expected 1 special regime and 1 withholding rule, got %d and %d", len(result.RegimesEspeciais), len(result.Retencoes))
+	}
+
+	beneficio := result.FindBeneficio("35503080400001")
+	if beneficio == nil {
+		t.Fatal("expected benefit 35503080400001 to be on record")
+	}
+	if !beneficio.IsActive(time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)) {
+		t.Error("expected benefit to be in force during its last day")
+	}
+	if beneficio.IsActive(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected benefit to be expired after its last day")
+	}
+}
+
+func TestQueryContributorParameters_Errors(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		client := newDistributionTestClient(t, server.URL)
+		_, err := client.QueryContributorParameters(context.Background(), "3550308", "11222333000181", nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.wantErr, err)
+		}
+
+		server.Close()
+	}
+
+	// A contributor without parameters is not an error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newDistributionTestClient(t, server.URL)
+	result, err := client.QueryContributorParameters(context.Background(), "3550308", "11222333000181", nil)
+	if err != nil {
+		t.Fatalf("QueryContributorParameters failed: %v", err)
+	}
+	if result.Cadastrado {
+		t.Error("expected contributor not to be on record")
+	}
+}
+
+// ================================================================================
+// MockClient Municipal Parameters Tests
+// ================================================================================
+
+func TestMockClient_QueryConvenio(t *testing.T) {
+	client := NewMockClient()
+	client.SimulatedLatency = 0
+
+	tests := []struct {
+		codigo  string
+		canEmit bool
+	}{
+		{"3550308", true},
+		{MockMunicipalityWithoutConvenio, false},
+		{MockMunicipalityOwnEmitter, false},
+	}
+
+	for _, tt := range tests {
+		result, err := client.QueryConvenio(context.Background(), tt.codigo, nil)
+		if err != nil {
+			t.Fatalf("MockClient.QueryConvenio failed: %v", err)
+		}
+		if result.CanEmit() != tt.canEmit {
+			t.Errorf("municipality %s: expected CanEmit %v, got %v", tt.codigo, tt.canEmit, result.CanEmit())
+		}
+	}
+}
+
+// ================================================================================
+// ParametersCache Tests
+// ================================================================================
+
+// countingParametersClient counts the parameter queries reaching the mock client.
+type countingParametersClient struct {
+	*MockClient
+	calls int
+}
+
+func (c *countingParametersClient) QueryServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string, cert *tls.Certificate) (*ServiceParametersResult, error) {
+	c.calls++
+	return c.MockClient.QueryServiceParameters(ctx, codigoMunicipio, codigoServico, cert)
+}
+
+func (c *countingParametersClient) QueryConvenio(ctx context.Context, codigoMunicipio string, cert *tls.Certificate) (*ConvenioResult, error) {
+	c.calls++
+	return c.MockClient.QueryConvenio(ctx, codigoMunicipio, cert)
+}
+
+func (c *countingParametersClient) QueryContributorParameters(ctx context.Context, codigoMunicipio, documento string, cert *tls.Certificate) (*ContributorParametersResult, error) {
+	c.calls++
+	return c.MockClient.QueryContributorParameters(ctx, codigoMunicipio, documento, cert)
+}
+
+func TestParametersCache_GetConvenio(t *testing.T) {
+	client := &countingParametersClient{MockClient: NewMockClient()}
+	client.SimulatedLatency = 0
+
+	cache := NewParametersCache(ParametersCacheConfig{Client: client})
+
+	for i := 0; i < 3; i++ {
+		result, err := cache.GetConvenio(context.Background(), "3550308")
+		if err != nil {
+			t.Fatalf("GetConvenio failed: %v", err)
+		}
+		if !result.CanEmit() {
+			t.Error("expected CanEmit to be true")
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("expected 1 query to the client, got %d", client.calls)
+	}
+
+	// Failures are not cached
+	client.SimulateFailure = true
+	if _, err := cache.GetConvenio(context.Background(), "4106902"); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("expected ErrServiceUnavailable, got %v", err)
+	}
+	client.SimulateFailure = false
+	if _, err := cache.GetConvenio(context.Background(), "4106902"); err != nil {
+		t.Fatalf("GetConvenio failed: %v", err)
+	}
+	if client.calls != 3 {
+		t.Errorf("expected 3 queries to the client, got %d", client.calls)
+	}
+}
+
+func TestParametersCache_GetServiceParameters(t *testing.T) {
+	client := &countingParametersClient{MockClient: NewMockClient()}
+	client.SimulatedLatency = 0
+
+	cache := NewParametersCache(ParametersCacheConfig{Client: client})
+
+	for _, codigoServico := range []string{"010101", "010101", "010201"} {
+		result, err := cache.GetServiceParameters(context.Background(), "3550308", codigoServico)
+		if err != nil {
+			t.Fatalf("GetServiceParameters failed: %v", err)
+		}
+		if result.Aliquota != MockISSRate {
+			t.Errorf("expected rate %v, got %v", MockISSRate, result.Aliquota)
+		}
+	}
+	if client.calls != 2 {
+		t.Errorf("expected 2 queries to the client, got %d", client.calls)
+	}
+
+	if _, err := cache.GetServiceParameters(context.Background(), "3550308", MockServiceWithoutParameters); !errors.Is(err, ErrServiceParametersNotFound) {
+		t.Errorf("expected ErrServiceParametersNotFound, got %v", err)
+	}
+}
+
+func TestParametersCache_GetContributorParameters(t *testing.T) {
+	client := &countingParametersClient{MockClient: NewMockClient()}
+	client.SimulatedLatency = 0
+
+	cache := NewParametersCache(ParametersCacheConfig{Client: client})
+
+	for _, documento := range []string{"11222333000181", "11222333000181", MockContributorWithoutParameters} {
+		result, err := cache.GetContributorParameters(context.Background(), "3550308", documento)
+		if err != nil {
+			t.Fatalf("GetContributorParameters failed: %v", err)
+		}
+		if result.Documento != documento {
+			t.Errorf("expected document %s, got %s", documento, result.Documento)
+		}
+		if result.Cadastrado != (documento != MockContributorWithoutParameters) {
+			t.Errorf("unexpected record status %v for %s", result.Cadastrado, documento)
+		}
+	}
+	if client.calls != 2 {
+		t.Errorf("expected 2 queries to the client, got %d", client.calls)
+	}
+}
+
+func TestParametersCache_StaleWhileRevalidate(t *testing.T) {
+	client := &countingParametersClient{MockClient: NewMockClient()}
+	client.SimulatedLatency = 0
+
+	cache := NewParametersCache(ParametersCacheConfig{Client: client, ConvenioTTL: time.Hour, StaleTTL: time.Hour})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if _, err := cache.GetConvenio(context.Background(), "3550308"); err != nil {
+		t.Fatalf("GetConvenio failed: %v", err)
+	}
+
+	// Expired but within the stale window: served from the cache and refreshed in the background
+	now = now.Add(90 * time.Minute)
+	if _, err := cache.GetConvenio(context.Background(), "3550308"); err != nil {
+		t.Fatalf("GetConvenio failed: %v", err)
+	}
+	cache.refreshes.Wait()
+	if client.calls != 2 {
+		t.Errorf("expected 2 queries to the client, got %d", client.calls)
+	}
+
+	// The refreshed entry is fresh again
+	if _, err := cache.GetConvenio(context.Background(), "3550308"); err != nil {
+		t.Fatalf("GetConvenio failed: %v", err)
+	}
+	cache.refreshes.Wait()
+	if client.calls != 2 {
+		t.Errorf("expected 2 queries to the client, got %d", client.calls)
+	}
+
+	// Beyond the stale window the lookup waits for the client, and failures are returned
+	now = now.Add(3 * time.Hour)
+	client.SimulateFailure = true
+	if _, err := cache.GetConvenio(context.Background(), "3550308"); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("expected ErrServiceUnavailable, got %v", err)
+	}
+}
+
+func TestParametersCache_NegativeCaching(t *testing.T) {
+	client := &countingParametersClient{MockClient: NewMockClient()}
+	client.SimulatedLatency = 0
+
+	cache := NewParametersCache(ParametersCacheConfig{Client: client, NegativeTTL: 10 * time.Minute})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetServiceParameters(context.Background(), "3550308", MockServiceWithoutParameters)
+		if !errors.Is(err, ErrServiceParametersNotFound) {
+			t.Fatalf("expected ErrServiceParametersNotFound, got %v", err)
+		}
+
+		result, err := cache.GetConvenio(context.Background(), MockMunicipalityWithoutConvenio)
+		if err != nil {
+			t.Fatalf("GetConvenio failed: %v", err)
+		}
+		if result.Conveniado {
+			t.Error("expected municipality without agreement")
+		}
+	}
+	if client.calls != 2 {
+		t.Errorf("expected 2 queries to the client, got %d", client.calls)
+	}
+
+	// Negative answers expire after the negative TTL, without a stale window
+	now = now.Add(15 * time.Minute)
+	if _, err := cache.GetServiceParameters(context.Background(), "3550308", MockServiceWithoutParameters); !errors.Is(err, ErrServiceParametersNotFound) {
+		t.Fatalf("expected ErrServiceParametersNotFound, got %v", err)
+	}
+	if client.calls != 3 {
+		t.Errorf("expected 3 queries to the client, got %d", client.calls)
+	}
+}
+
+func TestParametersCache_Invalidate(t *testing.T) {
+	client := &countingParametersClient{MockClient: NewMockClient()}
+	client.SimulatedLatency = 0
+
+	cache := NewParametersCache(ParametersCacheConfig{Client: client})
+	ctx := context.Background()
+
+	for _, codigo := range []string{"3550308", "4106902"} {
+		if _, err := cache.GetConvenio(ctx, codigo); err != nil {
+			t.Fatalf("GetConvenio failed: %v", err)
+		}
+		if _, err := cache.GetServiceParameters(ctx, codigo, "010101"); err != nil {
+			t.Fatalf("GetServiceParameters failed: %v", err)
+		}
+	}
+
+	deleted, err := cache.Invalidate(ctx, CacheResourceConvenio, "3550308")
+	if err != nil || deleted != 1 {
+		t.Errorf("expected 1 deleted entry, got %d (err %v)", deleted, err)
+	}
+
+	deleted, err = cache.Invalidate(ctx, "", "4106902")
+	if err != nil || deleted != 2 {
+		t.Errorf("expected 2 deleted entries, got %d (err %v)", deleted, err)
+	}
+
+	deleted, err = cache.Invalidate(ctx, "", "")
+	if err != nil || deleted != 1 {
+		t.Errorf("expected 1 deleted entry, got %d (err %v)", deleted, err)
+	}
+
+	if _, err := cache.Invalidate(ctx, "aliquotas", ""); !errors.Is(err, ErrUnknownCacheResource) {
+		t.Errorf("expected ErrUnknownCacheResource, got %v", err)
+	}
+
+	calls := client.calls
+	if _, err := cache.GetConvenio(ctx, "3550308"); err != nil {
+		t.Fatalf("GetConvenio failed: %v", err)
+	}
+	if client.calls != calls+1 {
+		t.Error("expected invalidated entry to be queried again")
+	}
+}
@@ -5,6 +5,7 @@ package xmlsigner
 
 import (
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
@@ -186,3 +187,24 @@ func (c *CertificateInfo) GetSerialNumber() string {
 	}
 	return c.Certificate.SerialNumber.String()
 }
+
+// TLSCertificate returns the certificate and private key as a tls.Certificate,
+// suitable for mutual TLS authentication against the SEFIN/ADN APIs.
+// Chain certificates, when present, are appended after the leaf certificate.
+func (c *CertificateInfo) TLSCertificate() *tls.Certificate {
+	if c.Certificate == nil || c.PrivateKey == nil {
+		return nil
+	}
+
+	chain := make([][]byte, 0, len(c.Chain)+1)
+	chain = append(chain, c.Certificate.Raw)
+	for _, cert := range c.Chain {
+		chain = append(chain, cert.Raw)
+	}
+
+	return &tls.Certificate{
+		Certificate: chain,
+		PrivateKey:  c.PrivateKey,
+		Leaf:        c.Certificate,
+	}
+}
@@ -0,0 +1,109 @@
+package xmlsigner
+
+import (
+	"encoding/asn1"
+	"strings"
+)
+
+// ICP-Brasil object identifiers of the holder's data, carried as otherName
+// entries of the certificate's subject alternative name.
+var (
+	// oidSubjectAltName identifies the subject alternative name extension.
+	oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+	// oidICPBrasilPersonData identifies the data of an e-CPF holder:
+	// birth date (8) + CPF (11) + NIS (11) + RG (15) + issuer and state (6).
+	oidICPBrasilPersonData = asn1.ObjectIdentifier{2, 16, 76, 1, 3, 1}
+
+	// oidICPBrasilCNPJ identifies the CNPJ (14) of an e-CNPJ holder.
+	oidICPBrasilCNPJ = asn1.ObjectIdentifier{2, 16, 76, 1, 3, 3}
+)
+
+// otherName is a GeneralName of the otherName type (RFC 5280).
+// Value holds the [0] EXPLICIT wrapper, whose content is the encoded value.
+type otherName struct {
+	TypeID asn1.ObjectIdentifier
+	Value  asn1.RawValue `asn1:"tag:0"`
+}
+
+// GetFederalRegistration returns the CNPJ (14 digits) or CPF (11 digits) of the
+// certificate holder, as recorded by ICP-Brasil certificates. It is read from the
+// subject alternative name, falling back to the "NAME:registration" common name.
+// Returns an empty string when the certificate does not identify its holder.
+func (c *CertificateInfo) GetFederalRegistration() string {
+	if c.Certificate == nil {
+		return ""
+	}
+
+	var cnpj, cpf string
+	for _, ext := range c.Certificate.Extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+
+		var names []asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+			break
+		}
+
+		for _, name := range names {
+			// otherName is the [0] choice of GeneralName
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 0 {
+				continue
+			}
+
+			var other otherName
+			if _, err := asn1.UnmarshalWithParams(name.FullBytes, &other, "tag:0"); err != nil {
+				continue
+			}
+
+			var encoded asn1.RawValue
+			if _, err := asn1.Unmarshal(other.Value.Bytes, &encoded); err != nil {
+				continue
+			}
+
+			value := strings.TrimSpace(string(encoded.Bytes))
+			switch {
+			case other.TypeID.Equal(oidICPBrasilCNPJ):
+				if len(value) == 14 && isDigits(value) {
+					cnpj = value
+				}
+			case other.TypeID.Equal(oidICPBrasilPersonData):
+				if len(value) >= 19 && isDigits(value[8:19]) {
+					cpf = value[8:19]
+				}
+			}
+		}
+	}
+
+	// e-CNPJ certificates also carry the data of the person responsible for the company
+	if cnpj != "" {
+		return cnpj
+	}
+	if cpf != "" {
+		return cpf
+	}
+
+	cn := c.Certificate.Subject.CommonName
+	if i := strings.LastIndex(cn, ":"); i >= 0 {
+		registration := strings.TrimSpace(cn[i+1:])
+		if (len(registration) == 14 || len(registration) == 11) && isDigits(registration) {
+			return registration
+		}
+	}
+
+	return ""
+}
+
+// isDigits reports whether a non-empty string contains only digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
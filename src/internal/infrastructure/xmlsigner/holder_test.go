@@ -0,0 +1,124 @@
+package xmlsigner
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// icpBrasilOtherName encodes an ICP-Brasil otherName entry of the subject alternative name.
+func icpBrasilOtherName(t *testing.T, oid asn1.ObjectIdentifier, value string) asn1.RawValue {
+	t.Helper()
+
+	oidBytes, err := asn1.Marshal(oid)
+	if err != nil {
+		t.Fatalf("Failed to marshal OID: %v", err)
+	}
+	inner, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagOctetString, Bytes: []byte(value)})
+	if err != nil {
+		t.Fatalf("Failed to marshal value: %v", err)
+	}
+	explicit, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner})
+	if err != nil {
+		t.Fatalf("Failed to marshal explicit value: %v", err)
+	}
+
+	return asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        0,
+		IsCompound: true,
+		Bytes:      append(oidBytes, explicit...),
+	}
+}
+
+// generateHolderCertificate creates a self-signed certificate with the given common name and otherName entries.
+func generateHolderCertificate(t *testing.T, commonName string, names ...asn1.RawValue) *CertificateInfo {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if len(names) > 0 {
+		san, err := asn1.Marshal(names)
+		if err != nil {
+			t.Fatalf("Failed to marshal subject alternative name: %v", err)
+		}
+		template.ExtraExtensions = []pkix.Extension{{Id: oidSubjectAltName, Value: san}}
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	return &CertificateInfo{PrivateKey: privateKey, Certificate: cert}
+}
+
+func TestCertificateInfo_GetFederalRegistration(t *testing.T) {
+	// Birth date, CPF, NIS, RG and issuer of the person responsible for the company
+	personData := "01011980" + "52998224725" + "00000000000" + "000000000000000" + "SSPSP"
+
+	tests := []struct {
+		name     string
+		cert     func(t *testing.T) *CertificateInfo
+		expected string
+	}{
+		{
+			name: "e-CNPJ",
+			cert: func(t *testing.T) *CertificateInfo {
+				return generateHolderCertificate(t, "EMPRESA LTDA:11222333000181",
+					icpBrasilOtherName(t, oidICPBrasilPersonData, personData),
+					icpBrasilOtherName(t, oidICPBrasilCNPJ, "11222333000181"),
+				)
+			},
+			expected: "11222333000181",
+		},
+		{
+			name: "e-CPF",
+			cert: func(t *testing.T) *CertificateInfo {
+				return generateHolderCertificate(t, "FULANO DE TAL:52998224725",
+					icpBrasilOtherName(t, oidICPBrasilPersonData, personData),
+				)
+			},
+			expected: "52998224725",
+		},
+		{
+			name: "common name only",
+			cert: func(t *testing.T) *CertificateInfo {
+				return generateHolderCertificate(t, "EMPRESA LTDA:11222333000181")
+			},
+			expected: "11222333000181",
+		},
+		{
+			name:     "certificate without holder registration",
+			cert:     generateTestCertificate,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cert(t).GetFederalRegistration(); got != tt.expected {
+				t.Errorf("GetFederalRegistration() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
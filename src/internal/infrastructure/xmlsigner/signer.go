@@ -204,6 +204,120 @@ func (s *XMLSigner) SignDPSCompact(dpsXML string) (string, error) {
 	return signedXML, nil
 }
 
+// SignEvent signs a pedRegEvento (Pedido de Registro de Evento) XML document.
+// The infPedReg element is signed and the Signature is appended to pedRegEvento,
+// following the same enveloped XMLDSig scheme used for DPS documents.
+//
+// Parameters:
+//   - eventXML: The unsigned pedRegEvento XML document as a string
+//
+// Returns:
+//   - string: The signed pedRegEvento XML document
+//   - error: Any error encountered during signing
+func (s *XMLSigner) SignEvent(eventXML string) (string, error) {
+	doc, err := s.signEventDocument(eventXML)
+	if err != nil {
+		return "", err
+	}
+
+	// Serialize the signed document
+	doc.Indent(2)
+	signedXML, err := doc.WriteToString()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize signed XML: %w", err)
+	}
+
+	return signedXML, nil
+}
+
+// SignEventCompact signs a pedRegEvento XML document and returns a compact (non-indented) result.
+// This is the form sent to the government API, where whitespace must not change after signing.
+func (s *XMLSigner) SignEventCompact(eventXML string) (string, error) {
+	doc, err := s.signEventDocument(eventXML)
+	if err != nil {
+		return "", err
+	}
+
+	// Serialize without indentation
+	signedXML, err := doc.WriteToString()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize signed XML: %w", err)
+	}
+
+	return signedXML, nil
+}
+
+// SignEventWithResult signs a pedRegEvento document and returns detailed results.
+// This is useful for debugging and audit logging.
+func (s *XMLSigner) SignEventWithResult(eventXML string) (*SigningResult, error) {
+	doc, err := s.signEventDocument(eventXML)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := doc.FindElement("//pedRegEvento/Signature")
+	reference := signature.FindElement("./SignedInfo/Reference")
+
+	// Serialize
+	doc.Indent(2)
+	signedXML, err := doc.WriteToString()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize signed XML: %w", err)
+	}
+
+	return &SigningResult{
+		SignedXML:      signedXML,
+		DigestValue:    reference.FindElement("./DigestValue").Text(),
+		SignatureValue: signature.FindElement("./SignatureValue").Text(),
+		ReferenceURI:   reference.SelectAttrValue("URI", ""),
+	}, nil
+}
+
+// signEventDocument parses a pedRegEvento XML document, signs its infPedReg element
+// and appends the Signature to pedRegEvento. The document is returned unserialized.
+func (s *XMLSigner) signEventDocument(eventXML string) (*etree.Document, error) {
+	// Validate certificate before signing
+	if err := s.validateCertificate(); err != nil {
+		return nil, err
+	}
+
+	// Parse the XML document
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(eventXML); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSigningInvalidXML, err)
+	}
+
+	// Find the pedRegEvento element
+	pedRegEvento := doc.FindElement("//pedRegEvento")
+	if pedRegEvento == nil {
+		return nil, fmt.Errorf("%w: pedRegEvento element", ErrSigningMissingElement)
+	}
+
+	// Find the infPedReg element
+	infPedReg := pedRegEvento.FindElement("infPedReg")
+	if infPedReg == nil {
+		return nil, fmt.Errorf("%w: infPedReg element", ErrSigningMissingElement)
+	}
+
+	// Get the Id attribute from infPedReg
+	idAttr := infPedReg.SelectAttr("Id")
+	if idAttr == nil {
+		return nil, ErrSigningMissingID
+	}
+	referenceURI := "#" + idAttr.Value
+
+	// Create and append the signature
+	signature, err := s.createSignature(infPedReg, referenceURI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Append the signature element after infPedReg
+	pedRegEvento.AddChild(signature)
+
+	return doc, nil
+}
+
 // validateCertificate checks that the signer has a valid certificate.
 func (s *XMLSigner) validateCertificate() error {
 	if s.certInfo == nil {
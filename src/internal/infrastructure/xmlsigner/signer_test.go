@@ -104,7 +104,6 @@ const sampleDPSXML = `<?xml version="1.0" encoding="UTF-8"?>
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000199</CNPJ>
       <xNome>Test Provider</xNome>
@@ -335,3 +334,124 @@ func TestXMLSigner_SignatureConsistency(t *testing.T) {
 		t.Error("Signature values should not be empty")
 	}
 }
+
+// samplePedRegEventoXML is a sample cancellation event request for testing.
+const samplePedRegEventoXML = `<?xml version="1.0" encoding="UTF-8"?>
+<pedRegEvento xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00">
+  <infPedReg Id="PRENFSe3550308202601081123456789012300000000000012310101101">
+    <tpAmb>2</tpAmb>
+    <verAplic>1.0.0</verAplic>
+    <dhEvento>2026-01-08T10:30:00-03:00</dhEvento>
+    <CNPJAutor>12345678000199</CNPJAutor>
+    <chNFSe>NFSe3550308202601081123456789012300000000000012310</chNFSe>
+    <e101101>
+      <xDesc>Cancelamento de NFS-e</xDesc>
+      <cMotivo>1</cMotivo>
+      <xMotivo>Erro na emissao da nota fiscal</xMotivo>
+    </e101101>
+  </infPedReg>
+</pedRegEvento>`
+
+func TestXMLSigner_SignEvent(t *testing.T) {
+	certInfo := generateTestCertificate(t)
+	signer := NewXMLSigner(certInfo)
+
+	signedXML, err := signer.SignEvent(samplePedRegEventoXML)
+	if err != nil {
+		t.Fatalf("Failed to sign event: %v", err)
+	}
+
+	if !strings.Contains(signedXML, "<Signature") {
+		t.Error("Signed XML does not contain Signature element")
+	}
+
+	if !strings.Contains(signedXML, `URI="#PRENFSe3550308202601081123456789012300000000000012310101101"`) {
+		t.Error("Signed XML does not contain correct reference URI")
+	}
+
+	// The signature must be a child of pedRegEvento, not of infPedReg
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(signedXML); err != nil {
+		t.Fatalf("Signed XML is not valid: %v", err)
+	}
+	if doc.FindElement("//pedRegEvento/Signature") == nil {
+		t.Error("Signature should be appended to pedRegEvento")
+	}
+	if doc.FindElement("//infPedReg/Signature") != nil {
+		t.Error("Signature should not be inside infPedReg")
+	}
+}
+
+func TestXMLSigner_SignEvent_MissingInfPedReg(t *testing.T) {
+	certInfo := generateTestCertificate(t)
+	signer := NewXMLSigner(certInfo)
+
+	xmlWithoutInf := `<?xml version="1.0"?><pedRegEvento xmlns="http://www.sped.fazenda.gov.br/nfse"><data>test</data></pedRegEvento>`
+	_, err := signer.SignEvent(xmlWithoutInf)
+	if err == nil {
+		t.Error("Expected error for missing infPedReg element")
+	}
+}
+
+func TestXMLSigner_SignEvent_NilCertificate(t *testing.T) {
+	signer := NewXMLSigner(nil)
+
+	_, err := signer.SignEvent(samplePedRegEventoXML)
+	if err != ErrSigningNilCertificate {
+		t.Errorf("Expected ErrSigningNilCertificate, got: %v", err)
+	}
+}
+
+func TestXMLSigner_SignEventCompact(t *testing.T) {
+	certInfo := generateTestCertificate(t)
+	signer := NewXMLSigner(certInfo)
+
+	signedXML, err := signer.SignEventCompact(samplePedRegEventoXML)
+	if err != nil {
+		t.Fatalf("Failed to sign event: %v", err)
+	}
+
+	if !strings.Contains(signedXML, "<SignedInfo><CanonicalizationMethod") {
+		t.Error("Compact signed XML should not indent the Signature element")
+	}
+}
+
+func TestXMLSigner_SignEventWithResult(t *testing.T) {
+	certInfo := generateTestCertificate(t)
+	signer := NewXMLSigner(certInfo)
+
+	result, err := signer.SignEventWithResult(samplePedRegEventoXML)
+	if err != nil {
+		t.Fatalf("Failed to sign event: %v", err)
+	}
+
+	if result.ReferenceURI != "#PRENFSe3550308202601081123456789012300000000000012310101101" {
+		t.Errorf("Unexpected reference URI: %s", result.ReferenceURI)
+	}
+	if result.DigestValue == "" || !strings.Contains(result.SignedXML, result.DigestValue) {
+		t.Error("DigestValue should be set and present in the signed XML")
+	}
+	if result.SignatureValue == "" {
+		t.Error("SignatureValue should be set")
+	}
+}
+
+func TestCertificateInfo_TLSCertificate(t *testing.T) {
+	certInfo := generateTestCertificate(t)
+
+	tlsCert := certInfo.TLSCertificate()
+	if tlsCert == nil {
+		t.Fatal("Expected TLS certificate, got nil")
+	}
+	if len(tlsCert.Certificate) != 1 {
+		t.Errorf("Expected 1 certificate in chain, got %d", len(tlsCert.Certificate))
+	}
+	if tlsCert.Leaf != certInfo.Certificate {
+		t.Error("Leaf should be the parsed certificate")
+	}
+
+	certInfo.PrivateKey = nil
+	if certInfo.TLSCertificate() != nil {
+		t.Error("Expected nil TLS certificate when private key is missing")
+	}
+}
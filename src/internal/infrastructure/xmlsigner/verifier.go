@@ -143,18 +143,25 @@ func (v *XMLVerifier) VerifySignature(signedXML string) (*VerificationResult, er
 		return result, nil
 	}
 
+	v.verifySignatureElement(doc, signature, result)
+	return result, nil
+}
+
+// verifySignatureElement verifies the given Signature element of a parsed document
+// and records the outcome in result.
+func (v *XMLVerifier) verifySignatureElement(doc *etree.Document, signature *etree.Element, result *VerificationResult) {
 	// Extract SignedInfo
 	signedInfo := signature.FindElement("SignedInfo")
 	if signedInfo == nil {
 		result.AddError(ErrVerificationNoSignedInfo.Error())
-		return result, nil
+		return
 	}
 
 	// Extract SignatureValue
 	signatureValueElem := signature.FindElement("SignatureValue")
 	if signatureValueElem == nil {
 		result.AddError(ErrVerificationNoSignatureValue.Error())
-		return result, nil
+		return
 	}
 	signatureValue := cleanBase64(signatureValueElem.Text())
 
@@ -162,7 +169,7 @@ func (v *XMLVerifier) VerifySignature(signedXML string) (*VerificationResult, er
 	cert, err := v.extractCertificate(signature)
 	if err != nil {
 		result.AddError(err.Error())
-		return result, nil
+		return
 	}
 	result.Certificate = cert
 	result.SignerCN = cert.Subject.CommonName
@@ -180,14 +187,14 @@ func (v *XMLVerifier) VerifySignature(signedXML string) (*VerificationResult, er
 	reference := signedInfo.FindElement("Reference")
 	if reference == nil {
 		result.AddError(ErrVerificationNoReference.Error())
-		return result, nil
+		return
 	}
 
 	// Get the URI attribute to find the referenced element
 	uriAttr := reference.SelectAttr("URI")
 	if uriAttr == nil || uriAttr.Value == "" {
 		result.AddError("Reference URI attribute is missing or empty")
-		return result, nil
+		return
 	}
 	referenceURI := uriAttr.Value
 	result.SignedElementID = strings.TrimPrefix(referenceURI, "#")
@@ -196,7 +203,7 @@ func (v *XMLVerifier) VerifySignature(signedXML string) (*VerificationResult, er
 	digestValueElem := reference.FindElement("DigestValue")
 	if digestValueElem == nil {
 		result.AddError(ErrVerificationNoDigestValue.Error())
-		return result, nil
+		return
 	}
 	expectedDigest := cleanBase64(digestValueElem.Text())
 
@@ -204,11 +211,11 @@ func (v *XMLVerifier) VerifySignature(signedXML string) (*VerificationResult, er
 	referencedElement := v.findElementByID(doc, result.SignedElementID)
 	if referencedElement == nil {
 		result.AddError(fmt.Sprintf("%s: %s", ErrVerificationReferencedElementNotFound.Error(), result.SignedElementID))
-		return result, nil
+		return
 	}
 
 	// Verify the digest
-	if !v.verifyDigest(referencedElement, expectedDigest) {
+	if !v.verifyDigest(referencedElement, expectedDigest, isDescendant(signature, referencedElement)) {
 		result.AddError(ErrVerificationDigestMismatch.Error())
 	}
 
@@ -216,8 +223,6 @@ func (v *XMLVerifier) VerifySignature(signedXML string) (*VerificationResult, er
 	if err := v.verifySignatureValue(signedInfo, signatureValue, cert); err != nil {
 		result.AddError(fmt.Sprintf("%s: %v", ErrVerificationSignatureMismatch.Error(), err))
 	}
-
-	return result, nil
 }
 
 // findSignatureElement finds the Signature element in the document.
@@ -244,6 +249,16 @@ func (v *XMLVerifier) findSignatureElement(doc *etree.Document) *etree.Element {
 	return nil
 }
 
+// isDescendant reports whether element is nested inside ancestor.
+func isDescendant(element, ancestor *etree.Element) bool {
+	for parent := element.Parent(); parent != nil; parent = parent.Parent() {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
 // findSignatureRecursive recursively searches for a Signature element.
 func findSignatureRecursive(element *etree.Element) *etree.Element {
 	if element.Tag == "Signature" {
@@ -313,9 +328,20 @@ func (v *XMLVerifier) findElementByID(doc *etree.Document, id string) *etree.Ele
 }
 
 // verifyDigest verifies the digest of the referenced element.
-func (v *XMLVerifier) verifyDigest(element *etree.Element, expectedDigest string) bool {
-	// Canonicalize the element (applying enveloped-signature transform which removes Signature)
-	canonicalContent, err := CanonicalizeSigned(element)
+// When the signature is enveloped (inside the signed element) it is removed before
+// canonicalization. Otherwise the element is canonicalized as is, so that nested
+// signatures (e.g. the DPS signature inside infNFSe) remain part of the signed content.
+func (v *XMLVerifier) verifyDigest(element *etree.Element, expectedDigest string, enveloped bool) bool {
+	var canonicalContent []byte
+	var err error
+	if enveloped {
+		// Apply the enveloped-signature transform which removes Signature
+		canonicalContent, err = CanonicalizeSigned(element)
+	} else {
+		doc := etree.NewDocument()
+		doc.SetRoot(element.Copy())
+		canonicalContent, err = Canonicalize(doc.Root())
+	}
 	if err != nil {
 		return false
 	}
@@ -415,3 +441,80 @@ func (v *XMLVerifier) VerifyDPSSignature(signedDPSXML string) (*VerificationResu
 
 	return result, nil
 }
+
+// VerifyNFSeSignature verifies the signature of an NFS-e document, as distributed
+// by the government API. The NFS-e also carries the signature of its DPS inside
+// infNFSe; only the signature of infNFSe, placed directly under NFSe, is verified.
+//
+// Parameters:
+//   - signedNFSeXML: The signed NFS-e XML document
+//
+// Returns:
+//   - *VerificationResult: The verification result
+//   - error: Only returns error for fatal parsing errors
+func (v *XMLVerifier) VerifyNFSeSignature(signedNFSeXML string) (*VerificationResult, error) {
+	return v.verifyDocumentSignature(signedNFSeXML, "NFSe", "infNFSe")
+}
+
+// VerifyEventDocumentSignature verifies the signature of an event document (evento),
+// as distributed by the government API. The event also carries the signed pedRegEvento
+// inside infEvento; only the signature of infEvento, placed directly under evento,
+// is verified.
+//
+// Parameters:
+//   - signedEventXML: The signed evento XML document
+//
+// Returns:
+//   - *VerificationResult: The verification result
+//   - error: Only returns error for fatal parsing errors
+func (v *XMLVerifier) VerifyEventDocumentSignature(signedEventXML string) (*VerificationResult, error) {
+	return v.verifyDocumentSignature(signedEventXML, "evento", "infEvento")
+}
+
+// verifyDocumentSignature verifies the Signature placed directly under the root
+// element and checks that it references the given info element.
+func (v *XMLVerifier) verifyDocumentSignature(signedXML, rootTag, infTag string) (*VerificationResult, error) {
+	result := &VerificationResult{
+		Valid:  true,
+		Errors: make([]string, 0),
+	}
+
+	if signedXML == "" {
+		return nil, fmt.Errorf("XML document is empty")
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(signedXML); err != nil {
+		return nil, fmt.Errorf("failed to parse XML document: %w", err)
+	}
+
+	root := doc.Root()
+	if root == nil || root.Tag != rootTag {
+		result.AddError(fmt.Sprintf("not a valid %s document: %s element not found", rootTag, rootTag))
+		return result, nil
+	}
+
+	info := root.SelectElement(infTag)
+	if info == nil {
+		result.AddError(fmt.Sprintf("not a valid %s document: %s element not found", rootTag, infTag))
+		return result, nil
+	}
+
+	signature := root.SelectElement("Signature")
+	if signature == nil {
+		result.AddError(ErrVerificationNoSignature.Error())
+		return result, nil
+	}
+
+	v.verifySignatureElement(doc, signature, result)
+
+	// Verify the signed element is the info element
+	if idAttr := info.SelectAttr("Id"); idAttr == nil {
+		result.AddError(fmt.Sprintf("%s element is missing Id attribute", infTag))
+	} else if result.SignedElementID != "" && result.SignedElementID != idAttr.Value {
+		result.AddError(fmt.Sprintf("signature does not reference %s element: expected %s, got %s",
+			infTag, idAttr.Value, result.SignedElementID))
+	}
+
+	return result, nil
+}
@@ -2,8 +2,12 @@
 package xmlsigner
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"strings"
 	"testing"
+
+	"github.com/beevik/etree"
 )
 
 // Test XML documents for verification tests.
@@ -20,7 +24,6 @@ const (
     <dCompet>2024-01-15</dCompet>
     <tpEmit>1</tpEmit>
     <cLocEmi>3550308</cLocEmi>
-    <subst>2</subst>
     <prest>
       <CNPJ>12345678000190</CNPJ>
       <xNome>Provider Company Ltd</xNome>
@@ -262,3 +265,117 @@ func TestXMLVerifier_VerifySignature_MissingKeyInfo(t *testing.T) {
 		t.Error("Expected Valid to be false for signature without KeyInfo")
 	}
 }
+
+// buildSignedNFSe wraps a signed DPS in an NFS-e document and signs its infNFSe element.
+func buildSignedNFSe(t *testing.T, signer *XMLSigner, signedDPS string) string {
+	t.Helper()
+
+	dpsDoc := etree.NewDocument()
+	if err := dpsDoc.ReadFromString(signedDPS); err != nil {
+		t.Fatalf("Failed to parse signed DPS: %v", err)
+	}
+
+	doc := etree.NewDocument()
+	nfse := doc.CreateElement("NFSe")
+	nfse.CreateAttr("xmlns", "http://www.sped.fazenda.gov.br/nfse")
+	nfse.CreateAttr("versao", "1.00")
+	infNFSe := nfse.CreateElement("infNFSe")
+	infNFSe.CreateAttr("Id", "NFS35503082024011512345678000190000000000000001")
+	infNFSe.CreateElement("nNFSe").SetText("1")
+	infNFSe.AddChild(dpsDoc.Root().Copy())
+
+	// The DPS signature nested in infNFSe is part of the signed content
+	copyDoc := etree.NewDocument()
+	copyDoc.SetRoot(infNFSe.Copy())
+	canonical, err := Canonicalize(copyDoc.Root())
+	if err != nil {
+		t.Fatalf("Failed to canonicalize infNFSe: %v", err)
+	}
+	digest := sha256.Sum256(canonical)
+	signedInfo := signer.buildSignedInfo("#NFS35503082024011512345678000190000000000000001", base64.StdEncoding.EncodeToString(digest[:]))
+	canonicalSignedInfo, err := Canonicalize(signedInfo)
+	if err != nil {
+		t.Fatalf("Failed to canonicalize SignedInfo: %v", err)
+	}
+	signatureValue, err := signer.signData(canonicalSignedInfo)
+	if err != nil {
+		t.Fatalf("Failed to sign infNFSe: %v", err)
+	}
+	certBase64, err := signer.certInfo.GetCertificateBase64()
+	if err != nil {
+		t.Fatalf("Failed to encode certificate: %v", err)
+	}
+	nfse.AddChild(signer.buildSignatureElement(signedInfo, base64.StdEncoding.EncodeToString(signatureValue), certBase64))
+
+	out, err := doc.WriteToString()
+	if err != nil {
+		t.Fatalf("Failed to serialize NFS-e: %v", err)
+	}
+	return out
+}
+
+func TestXMLVerifier_VerifyNFSeSignature(t *testing.T) {
+	signer := NewXMLSigner(generateTestCertificate(t))
+	signedDPS, err := signer.SignDPSCompact(testUnsignedDPS)
+	if err != nil {
+		t.Fatalf("Failed to sign DPS: %v", err)
+	}
+	signedNFSe := buildSignedNFSe(t, signer, signedDPS)
+
+	verifier := NewXMLVerifier()
+	verifier.ValidateCertificate = false
+
+	result, err := verifier.VerifyNFSeSignature(signedNFSe)
+	if err != nil {
+		t.Fatalf("VerifyNFSeSignature returned error: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("Expected valid NFS-e signature, got errors: %v", result.Errors)
+	}
+	if result.SignedElementID != "NFS35503082024011512345678000190000000000000001" {
+		t.Errorf("Expected signature to reference infNFSe, got %q", result.SignedElementID)
+	}
+}
+
+func TestXMLVerifier_VerifyNFSeSignature_Tampered(t *testing.T) {
+	signer := NewXMLSigner(generateTestCertificate(t))
+	signedDPS, err := signer.SignDPSCompact(testUnsignedDPS)
+	if err != nil {
+		t.Fatalf("Failed to sign DPS: %v", err)
+	}
+	signedNFSe := buildSignedNFSe(t, signer, signedDPS)
+	tampered := strings.Replace(signedNFSe, "<vServPrest>1000.00</vServPrest>", "<vServPrest>10.00</vServPrest>", 1)
+
+	verifier := NewXMLVerifier()
+	verifier.ValidateCertificate = false
+
+	result, err := verifier.VerifyNFSeSignature(tampered)
+	if err != nil {
+		t.Fatalf("VerifyNFSeSignature returned error: %v", err)
+	}
+	if result.Valid {
+		t.Error("Expected tampered NFS-e to fail verification")
+	}
+}
+
+func TestXMLVerifier_VerifyNFSeSignature_NotNFSe(t *testing.T) {
+	result, err := NewXMLVerifier().VerifyNFSeSignature(testDPSNoSignature)
+	if err != nil {
+		t.Fatalf("VerifyNFSeSignature should not return error for non-NFS-e, got: %v", err)
+	}
+	if result.Valid {
+		t.Error("Expected Valid to be false for non-NFS-e document")
+	}
+}
+
+func TestXMLVerifier_VerifyEventDocumentSignature_Unsigned(t *testing.T) {
+	eventXML := `<evento xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00"><infEvento Id="EVT1"><nSeqEvento>1</nSeqEvento></infEvento></evento>`
+
+	result, err := NewXMLVerifier().VerifyEventDocumentSignature(eventXML)
+	if err != nil {
+		t.Fatalf("VerifyEventDocumentSignature returned error: %v", err)
+	}
+	if result.Valid {
+		t.Error("Expected Valid to be false for unsigned event document")
+	}
+}
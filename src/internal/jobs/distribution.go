@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// TypeDistributionSync is the task type for downloading the documents distributed
+	// by the ADN for a registered CNPJ.
+	TypeDistributionSync = "distribution:sync"
+
+	// TypeDistributionPoll is the recurring task type that queues the sync of
+	// every registered CNPJ that is due.
+	TypeDistributionPoll = "distribution:poll"
+)
+
+// DistributionSyncTaskPayload contains the data needed to sync a CNPJ.
+type DistributionSyncTaskPayload struct {
+	// CNPJ is the registered CNPJ to sync.
+	CNPJ string `json:"cnpj"`
+}
+
+// NewDistributionSyncTask creates a new distribution sync task.
+func NewDistributionSyncTask(cnpj string) (*asynq.Task, error) {
+	if cnpj == "" {
+		return nil, fmt.Errorf("CNPJ is required")
+	}
+
+	payload := DistributionSyncTaskPayload{
+		CNPJ: cnpj,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal distribution sync task payload: %w", err)
+	}
+
+	return asynq.NewTask(TypeDistributionSync, data), nil
+}
+
+// DistributionSyncTaskID returns the task ID of the sync of a CNPJ, so that
+// a CNPJ has at most one sync queued at a time.
+func DistributionSyncTaskID(cnpj string) string {
+	return fmt.Sprintf("%s:%s", TypeDistributionSync, cnpj)
+}
+
+// ParseDistributionSyncTask parses a distribution sync task and returns its payload.
+func ParseDistributionSyncTask(task *asynq.Task) (*DistributionSyncTaskPayload, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+
+	if task.Type() != TypeDistributionSync {
+		return nil, fmt.Errorf("unexpected task type: %s (expected %s)", task.Type(), TypeDistributionSync)
+	}
+
+	var payload DistributionSyncTaskPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal distribution sync task payload: %w", err)
+	}
+
+	if payload.CNPJ == "" {
+		return nil, fmt.Errorf("task payload is missing cnpj")
+	}
+
+	return &payload, nil
+}
+
+// NewDistributionPollTask creates a new distribution poll task.
+func NewDistributionPollTask() *asynq.Task {
+	return asynq.NewTask(TypeDistributionPoll, nil)
+}
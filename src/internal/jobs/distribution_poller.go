@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	infraredis "github.com/eduardo/nfse-nacional/internal/infrastructure/redis"
+)
+
+// DistributionPoller queues the distribution sync of the registered CNPJs on
+// each run of the recurring distribution:poll task. CNPJs backing off after
+// the ADN reported no more documents are skipped until their next sync time.
+type DistributionPoller struct {
+	repo      *mongodb.DistributionRepository
+	jobClient *infraredis.JobClient
+}
+
+// DistributionPollerConfig configures the distribution poller.
+type DistributionPollerConfig struct {
+	// Repo is the repository for distribution cursors.
+	Repo *mongodb.DistributionRepository
+
+	// JobClient is the Asynq job client for enqueueing sync tasks.
+	JobClient *infraredis.JobClient
+}
+
+// NewDistributionPoller creates a new distribution poller.
+func NewDistributionPoller(config DistributionPollerConfig) *DistributionPoller {
+	return &DistributionPoller{
+		repo:      config.Repo,
+		jobClient: config.JobClient,
+	}
+}
+
+// ProcessPoll handles the distribution:poll task.
+func (p *DistributionPoller) ProcessPoll(ctx context.Context, task *asynq.Task) error {
+	if p.jobClient == nil {
+		log.Printf("ERROR: Job client not configured, distribution poll skipped")
+		return nil
+	}
+
+	cursors, err := p.repo.FindDueCursors(ctx, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to list distribution cursors: %w", err)
+	}
+
+	queued := 0
+	for _, cursor := range cursors {
+		syncTask, err := NewDistributionSyncTask(cursor.CNPJ)
+		if err != nil {
+			log.Printf("ERROR: Failed to create distribution sync task: cnpj=%s error=%v", cursor.CNPJ, err)
+			continue
+		}
+
+		_, err = p.jobClient.Enqueue(ctx, syncTask, &infraredis.EnqueueOptions{
+			Queue:    infraredis.QueueLow,
+			MaxRetry: 3,
+			TaskID:   DistributionSyncTaskID(cursor.CNPJ),
+		})
+		if err != nil {
+			// A sync already queued for the CNPJ covers this poll
+			if errors.Is(err, asynq.ErrTaskIDConflict) {
+				continue
+			}
+			log.Printf("ERROR: Failed to enqueue distribution sync task: cnpj=%s error=%v", cursor.CNPJ, err)
+			continue
+		}
+		queued++
+	}
+
+	log.Printf("Distribution poll: %d CNPJs due, %d syncs queued", len(cursors), queued)
+	return nil
+}
@@ -0,0 +1,482 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/distribution"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/domain/query"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/webhook"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
+)
+
+const (
+	// DefaultDistributionMaxBatches is the default number of batches downloaded per sync.
+	DefaultDistributionMaxBatches = 20
+
+	// MinDistributionIdleBackoff is the minimum wait before querying the ADN
+	// again once it reports no more documents, as required by the ADN manual.
+	MinDistributionIdleBackoff = time.Hour
+)
+
+// ErrDistributionBackoff is returned when a CNPJ is synced before the wait
+// required after the ADN reported no more documents.
+var ErrDistributionBackoff = errors.New("distribution sync is backing off")
+
+// DistributionSyncer downloads the NFS-e and events distributed by the ADN for
+// the registered CNPJs, verifies their signatures and stores them, advancing the
+// NSU cursor of each CNPJ.
+type DistributionSyncer struct {
+	repo          *mongodb.DistributionRepository
+	client        sefin.DistributionClient
+	lifecycle     *LifecycleTracker
+	webhookRepo   *mongodb.WebhookRepository
+	webhookSender *webhook.Sender
+	verifier      *xmlsigner.XMLVerifier
+	maxBatches    int
+	idleBackoff   time.Duration
+}
+
+// DistributionSyncerConfig configures the distribution syncer.
+type DistributionSyncerConfig struct {
+	// Repo is the repository for distribution cursors and received documents.
+	Repo *mongodb.DistributionRepository
+
+	// Client is the ADN distribution client.
+	Client sefin.DistributionClient
+
+	// EmissionRepo is the repository for emission requests. When set, received
+	// events update the lifecycle of NFS-e emitted through this API.
+	EmissionRepo *mongodb.EmissionRepository
+
+	// WebhookRepo is the repository for webhook deliveries.
+	WebhookRepo *mongodb.WebhookRepository
+
+	// WebhookSender is the webhook sender. Received documents are only
+	// notified when both the sender and the repository are set.
+	WebhookSender *webhook.Sender
+
+	// MaxBatches limits the number of batches downloaded per sync (default: 20).
+	// The next sync resumes from the stored cursor.
+	MaxBatches int
+
+	// IdleBackoff is the wait before syncing a CNPJ again once the ADN has no
+	// more documents (default and minimum: 1 hour).
+	IdleBackoff time.Duration
+}
+
+// NewDistributionSyncer creates a new distribution syncer.
+func NewDistributionSyncer(config DistributionSyncerConfig) *DistributionSyncer {
+	if config.MaxBatches <= 0 {
+		config.MaxBatches = DefaultDistributionMaxBatches
+	}
+	if config.IdleBackoff < MinDistributionIdleBackoff {
+		config.IdleBackoff = MinDistributionIdleBackoff
+	}
+
+	// Distributed documents may have been signed with certificates that have
+	// since expired, so only the signature itself is checked.
+	verifier := xmlsigner.NewXMLVerifier()
+	verifier.ValidateCertificate = false
+
+	s := &DistributionSyncer{
+		repo:          config.Repo,
+		client:        config.Client,
+		webhookRepo:   config.WebhookRepo,
+		webhookSender: config.WebhookSender,
+		verifier:      verifier,
+		maxBatches:    config.MaxBatches,
+		idleBackoff:   config.IdleBackoff,
+	}
+	if config.EmissionRepo != nil {
+		s.lifecycle = NewLifecycleTracker(config.EmissionRepo)
+	}
+	return s
+}
+
+// DistributionSyncResult contains the outcome of the sync of a CNPJ.
+type DistributionSyncResult struct {
+	// CNPJ is the synced CNPJ.
+	CNPJ string
+
+	// Documents are the documents stored during the sync, in NSU order.
+	Documents []*mongodb.ReceivedDocument
+
+	// LastNSU is the cursor after the sync.
+	LastNSU int64
+
+	// UpToDate indicates the ADN has no documents after LastNSU.
+	UpToDate bool
+
+	// NextSyncAt is the earliest time of the next sync, set when UpToDate.
+	NextSyncAt *time.Time
+}
+
+// ProcessSync handles the distribution:sync task.
+func (s *DistributionSyncer) ProcessSync(ctx context.Context, task *asynq.Task) error {
+	payload, err := ParseDistributionSyncTask(task)
+	if err != nil {
+		// Return nil to prevent retries for invalid payloads
+		log.Printf("Error parsing distribution sync task: %v", err)
+		return nil
+	}
+
+	if _, err := s.Sync(ctx, payload.CNPJ); err != nil {
+		if errors.Is(err, mongodb.ErrDistributionCursorNotFound) {
+			log.Printf("Distribution cursor not found: %s", payload.CNPJ)
+			return nil // Don't retry if not registered
+		}
+		if errors.Is(err, ErrDistributionBackoff) {
+			log.Printf("Distribution sync for CNPJ %s skipped: %v", payload.CNPJ, err)
+			return nil // The poller queues it again when due
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Sync downloads the documents of the CNPJ after its stored NSU cursor.
+// The cursor is persisted after each batch, so an interrupted sync resumes
+// from the last stored document. Once the ADN reports no documents after the
+// cursor, the CNPJ is not synced again before the idle backoff.
+func (s *DistributionSyncer) Sync(ctx context.Context, cnpj string) (*DistributionSyncResult, error) {
+	cursor, err := s.repo.FindCursorByCNPJ(ctx, cnpj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load distribution cursor: %w", err)
+	}
+
+	if cursor.IsBackingOff(time.Now()) {
+		return nil, fmt.Errorf("%w until %s", ErrDistributionBackoff, cursor.NextSyncAt.UTC().Format(time.RFC3339))
+	}
+
+	if cursor.Certificate == nil || cursor.Certificate.PFXBase64 == "" {
+		return nil, s.fail(ctx, cnpj, cursor.LastNSU, errors.New("certificate is not available for this CNPJ"))
+	}
+
+	certInfo, err := xmlsigner.ParsePFXBase64(cursor.Certificate.PFXBase64, cursor.Certificate.Password)
+	if err != nil {
+		return nil, s.fail(ctx, cnpj, cursor.LastNSU, fmt.Errorf("invalid certificate: %w", err))
+	}
+	tlsCert := certInfo.TLSCertificate()
+
+	result := &DistributionSyncResult{
+		CNPJ:    cnpj,
+		LastNSU: cursor.LastNSU,
+	}
+
+	for batch := 0; batch < s.maxBatches; batch++ {
+		dist, err := s.client.FetchDocuments(ctx, cnpj, result.LastNSU, tlsCert)
+		if err != nil {
+			return result, s.fail(ctx, cnpj, result.LastNSU, fmt.Errorf("failed to fetch documents: %w", err))
+		}
+
+		for i := range dist.Documents {
+			doc := s.receive(cursor, &dist.Documents[i])
+			if err := s.repo.SaveDocument(ctx, doc); err != nil {
+				// Keep the cursor at the last stored document
+				return result, s.fail(ctx, cnpj, result.LastNSU, err)
+			}
+			s.updateNFSeStatus(ctx, cnpj, doc.AccessKey)
+			s.trackLifecycle(ctx, doc)
+			result.Documents = append(result.Documents, doc)
+			if doc.NSU > result.LastNSU {
+				result.LastNSU = doc.NSU
+			}
+			s.sendWebhook(ctx, cursor, doc)
+		}
+
+		if err := s.repo.UpdateCursorProgress(ctx, cnpj, result.LastNSU, ""); err != nil {
+			return result, fmt.Errorf("failed to update distribution cursor: %w", err)
+		}
+
+		// Only "no documents" means the CNPJ is up to date: the ADN does not
+		// promise full batches, so a short batch may still have documents after it
+		if dist.Status == sefin.DistributionStatusNoDocuments || !dist.HasDocuments() {
+			result.UpToDate = true
+			break
+		}
+	}
+
+	// Wait before querying again when up to date, and let the next poll
+	// continue right away when the batch limit was reached
+	if result.UpToDate {
+		nextSyncAt := time.Now().UTC().Add(s.idleBackoff)
+		result.NextSyncAt = &nextSyncAt
+	}
+	if err := s.repo.UpdateNextSync(ctx, cnpj, result.NextSyncAt); err != nil {
+		return result, fmt.Errorf("failed to update distribution cursor: %w", err)
+	}
+
+	log.Printf("Distribution sync for CNPJ %s: %d documents received, last NSU %d", cnpj, len(result.Documents), result.LastNSU)
+	return result, nil
+}
+
+// SyncNSU downloads and stores a single document by its NSU, recovering an NSU
+// missing from the local base. The cursor is not changed.
+func (s *DistributionSyncer) SyncNSU(ctx context.Context, cnpj string, nsu int64) (*mongodb.ReceivedDocument, error) {
+	cursor, err := s.repo.FindCursorByCNPJ(ctx, cnpj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load distribution cursor: %w", err)
+	}
+
+	if cursor.Certificate == nil || cursor.Certificate.PFXBase64 == "" {
+		return nil, errors.New("certificate is not available for this CNPJ")
+	}
+
+	certInfo, err := xmlsigner.ParsePFXBase64(cursor.Certificate.PFXBase64, cursor.Certificate.Password)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+
+	dist, err := s.client.FetchDocument(ctx, cnpj, nsu, certInfo.TLSCertificate())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch document: %w", err)
+	}
+
+	for i := range dist.Documents {
+		if dist.Documents[i].NSU != nsu {
+			continue
+		}
+		doc := s.receive(cursor, &dist.Documents[i])
+		if err := s.repo.SaveDocument(ctx, doc); err != nil {
+			return nil, err
+		}
+		s.updateNFSeStatus(ctx, cnpj, doc.AccessKey)
+		s.trackLifecycle(ctx, doc)
+		return doc, nil
+	}
+
+	return nil, mongodb.ErrReceivedDocumentNotFound
+}
+
+// receive converts a distributed document into a received document, verifying its signature.
+func (s *DistributionSyncer) receive(cursor *mongodb.DistributionCursor, dist *sefin.DistributedDocument) *mongodb.ReceivedDocument {
+	now := time.Now().UTC()
+	doc := &mongodb.ReceivedDocument{
+		CNPJ:         cursor.CNPJ,
+		NSU:          dist.NSU,
+		APIKeyID:     cursor.APIKeyID,
+		ReceivedAt:   now,
+		AccessKey:    dist.ChaveAcesso,
+		DocumentType: dist.TipoDocumento,
+		EventType:    dist.TipoEvento,
+		XML:          dist.XML,
+		GeneratedAt:  dist.DataGeracao,
+	}
+
+	if dist.IsNFSe() {
+		doc.NFSe = parseReceivedNFSe(dist)
+	}
+
+	var verification *xmlsigner.VerificationResult
+	var err error
+	if dist.IsEvent() {
+		verification, err = s.verifier.VerifyEventDocumentSignature(dist.XML)
+	} else {
+		verification, err = s.verifier.VerifyNFSeSignature(dist.XML)
+	}
+
+	doc.Signature.CheckedAt = now
+	if err != nil {
+		doc.Signature.Errors = []string{err.Error()}
+		return doc
+	}
+
+	doc.Signature.Valid = verification.Valid
+	doc.Signature.SignerCN = verification.SignerCN
+	doc.Signature.Errors = verification.Errors
+	if !verification.Valid {
+		log.Printf("Invalid signature on NSU %d for CNPJ %s: %v", dist.NSU, cursor.CNPJ, verification.Errors)
+	}
+
+	return doc
+}
+
+// parseReceivedNFSe extracts the fields used by the inbox filters from a
+// distributed NFS-e. It returns nil if the XML cannot be parsed.
+func parseReceivedNFSe(dist *sefin.DistributedDocument) *mongodb.ReceivedNFSe {
+	data, err := query.ParseNFSeXML(dist.XML)
+	if err != nil {
+		log.Printf("Failed to parse NFS-e received on NSU %d: %v", dist.NSU, err)
+		return nil
+	}
+
+	nfse := &mongodb.ReceivedNFSe{
+		IssuerCNPJ:       data.Prestador.Documento,
+		Competence:       data.DataCompetencia,
+		ServiceValue:     data.Valores.ValorServico,
+		MunicipalityCode: data.Servico.MunicipioCodigo,
+		Status:           event.LifecycleActive,
+	}
+	if data.Tomador != nil {
+		nfse.TakerDocument = data.Tomador.Documento
+	}
+
+	return nfse
+}
+
+// updateNFSeStatus derives the status of an NFS-e received by the CNPJ from the
+// events received for it, so the inbox can be filtered by manifestation.
+func (s *DistributionSyncer) updateNFSeStatus(ctx context.Context, cnpj, accessKey string) {
+	if accessKey == "" {
+		return
+	}
+
+	docs, err := s.repo.FindDocumentsByAccessKey(ctx, cnpj, accessKey)
+	if err != nil {
+		log.Printf("Failed to load documents of NFS-e %s for CNPJ %s: %v", accessKey, cnpj, err)
+		return
+	}
+
+	hasNFSe := false
+	state := event.LifecycleState{Status: event.LifecycleActive}
+	blocks := make(map[string]string)
+	for _, doc := range docs {
+		if doc.NFSe != nil {
+			hasNFSe = true
+		}
+		if doc.DocumentType != sefin.DistributedDocumentEvent {
+			continue
+		}
+
+		// An unblock only names the block it lifts
+		evt := receivedEventData(doc)
+		target := evt.CodEventoBloqueado
+		switch doc.EventType {
+		case event.TypeOfficialBlock:
+			blocks[evt.ID] = target
+		case event.TypeOfficialUnblock:
+			target = blocks[evt.IDBloqueio]
+		}
+		state, _ = state.Apply(doc.EventType, target)
+	}
+
+	// Events may be received before the NFS-e they refer to
+	if !hasNFSe {
+		return
+	}
+
+	if err := s.repo.UpdateNFSeStatus(ctx, cnpj, accessKey, state.Status); err != nil {
+		log.Printf("Failed to update status of NFS-e %s for CNPJ %s: %v", accessKey, cnpj, err)
+	}
+}
+
+// trackLifecycle applies a received event to the lifecycle of the NFS-e it refers
+// to, when the NFS-e was emitted through this API. Events whose signature could not
+// be verified are not trusted. Failures are only logged.
+func (s *DistributionSyncer) trackLifecycle(ctx context.Context, doc *mongodb.ReceivedDocument) {
+	if s.lifecycle == nil || doc.DocumentType != sefin.DistributedDocumentEvent || doc.AccessKey == "" {
+		return
+	}
+	if !doc.Signature.Valid {
+		log.Printf("Skipping lifecycle update from NSU %d for CNPJ %s: invalid signature", doc.NSU, doc.CNPJ)
+		return
+	}
+
+	if _, err := s.lifecycle.Apply(ctx, doc.AccessKey, NewDiscoveredLifecycleEvent(receivedEventData(doc))); err != nil {
+		log.Printf("Failed to update lifecycle of NFS-e %s from NSU %d: %v", doc.AccessKey, doc.NSU, err)
+	}
+}
+
+// receivedEventData parses the details of a received event document.
+func receivedEventData(doc *mongodb.ReceivedDocument) sefin.EventData {
+	dist := &sefin.DistributedDocument{
+		ChaveAcesso:   doc.AccessKey,
+		TipoDocumento: doc.DocumentType,
+		TipoEvento:    doc.EventType,
+		XML:           doc.XML,
+		DataGeracao:   doc.GeneratedAt,
+	}
+	return dist.EventData()
+}
+
+// sendWebhook notifies the webhook of the CNPJ about a received document.
+func (s *DistributionSyncer) sendWebhook(ctx context.Context, cursor *mongodb.DistributionCursor, doc *mongodb.ReceivedDocument) {
+	// Skip if no webhook URL or sender
+	if cursor.WebhookURL == "" || s.webhookSender == nil || s.webhookRepo == nil {
+		return
+	}
+
+	webhookEvent := distribution.WebhookEventNFSeReceived
+	if doc.DocumentType == sefin.DistributedDocumentEvent {
+		webhookEvent = distribution.WebhookEventEventReceived
+	}
+
+	// Build payload
+	payload := distribution.WebhookPayload{
+		Event:          webhookEvent,
+		Timestamp:      time.Now().UTC(),
+		CNPJ:           doc.CNPJ,
+		NSU:            doc.NSU,
+		ChaveAcesso:    doc.AccessKey,
+		EventType:      doc.EventType,
+		SignatureValid: doc.Signature.Valid,
+		XML:            doc.XML,
+	}
+	if !doc.GeneratedAt.IsZero() {
+		generatedAt := doc.GeneratedAt
+		payload.GeneratedAt = &generatedAt
+	}
+
+	// Create webhook delivery record
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error marshaling webhook payload: %v", err)
+		return
+	}
+
+	// Received documents have no request, so the delivery is identified by CNPJ and NSU
+	requestID := fmt.Sprintf("dist-%s-%d", doc.CNPJ, doc.NSU)
+	delivery := &mongodb.WebhookDelivery{
+		RequestID: requestID,
+		APIKeyID:  cursor.APIKeyID,
+		URL:       cursor.WebhookURL,
+		Status:    mongodb.WebhookStatusPending,
+		Payload:   string(payloadBytes),
+	}
+
+	if err := s.webhookRepo.Create(ctx, delivery); err != nil {
+		log.Printf("Error creating webhook delivery record: %v", err)
+		return
+	}
+
+	// Send webhook (TODO: Get secret from API key)
+	webhookSecret := "" // In production, get this from the API key
+	sendResult, err := s.webhookSender.Send(ctx, cursor.WebhookURL, payload, webhookSecret, requestID)
+	if err != nil {
+		log.Printf("Webhook delivery failed for %s: %v", requestID, err)
+		if markErr := s.webhookRepo.MarkFailed(ctx, delivery.ID, err.Error(), sendResult.Duration.Milliseconds()); markErr != nil {
+			log.Printf("Error marking webhook as failed: %v", markErr)
+		}
+		return
+	}
+
+	if sendResult.Success {
+		if markErr := s.webhookRepo.MarkSuccess(ctx, delivery.ID, sendResult.StatusCode, sendResult.ResponseBody, sendResult.Duration.Milliseconds()); markErr != nil {
+			log.Printf("Error marking webhook as success: %v", markErr)
+		}
+	} else {
+		log.Printf("Webhook delivery failed for %s: %s", requestID, sendResult.Error)
+		if markErr := s.webhookRepo.MarkFailed(ctx, delivery.ID, sendResult.Error, sendResult.Duration.Milliseconds()); markErr != nil {
+			log.Printf("Error marking webhook as failed: %v", markErr)
+		}
+	}
+}
+
+// fail records a sync error on the cursor and returns it.
+func (s *DistributionSyncer) fail(ctx context.Context, cnpj string, lastNSU int64, syncErr error) error {
+	if err := s.repo.UpdateCursorProgress(ctx, cnpj, lastNSU, syncErr.Error()); err != nil {
+		log.Printf("Failed to record distribution error for CNPJ %s: %v", cnpj, err)
+	}
+	return syncErr
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// TypeEventRegister is the task type for registering NFS-e events (pedidos de registro de evento).
+const TypeEventRegister = "event:register"
+
+// EventTaskPayload contains the data needed to register an event.
+type EventTaskPayload struct {
+	// RequestID is the unique identifier of the event request.
+	RequestID string `json:"request_id"`
+}
+
+// NewEventTask creates a new event registration task.
+func NewEventTask(requestID string) (*asynq.Task, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request ID is required")
+	}
+
+	payload := EventTaskPayload{
+		RequestID: requestID,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event task payload: %w", err)
+	}
+
+	return asynq.NewTask(TypeEventRegister, data), nil
+}
+
+// ParseEventTask parses an event registration task and returns its payload.
+func ParseEventTask(task *asynq.Task) (*EventTaskPayload, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+
+	if task.Type() != TypeEventRegister {
+		return nil, fmt.Errorf("unexpected task type: %s (expected %s)", task.Type(), TypeEventRegister)
+	}
+
+	var payload EventTaskPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event task payload: %w", err)
+	}
+
+	if payload.RequestID == "" {
+		return nil, fmt.Errorf("task payload is missing request_id")
+	}
+
+	return &payload, nil
+}
@@ -0,0 +1,422 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/webhook"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/xmlsigner"
+	"github.com/eduardo/nfse-nacional/pkg/xmlbuilder"
+)
+
+// EventProcessor handles event registration job processing.
+type EventProcessor struct {
+	eventRepo     *mongodb.EventRepository
+	lifecycle     *LifecycleTracker
+	webhookRepo   *mongodb.WebhookRepository
+	sefinClient   sefin.SefinClient
+	webhookSender *webhook.Sender
+}
+
+// EventProcessorConfig configures the event processor.
+type EventProcessorConfig struct {
+	// EventRepo is the repository for event requests.
+	EventRepo *mongodb.EventRepository
+
+	// EmissionRepo is the repository for emission requests. When set, registered
+	// events update the lifecycle of NFS-e emitted through this API.
+	EmissionRepo *mongodb.EmissionRepository
+
+	// WebhookRepo is the repository for webhook deliveries.
+	WebhookRepo *mongodb.WebhookRepository
+
+	// SefinClient is the SEFIN API client.
+	SefinClient sefin.SefinClient
+
+	// WebhookSender is the webhook sender.
+	WebhookSender *webhook.Sender
+}
+
+// NewEventProcessor creates a new event processor.
+func NewEventProcessor(config EventProcessorConfig) *EventProcessor {
+	p := &EventProcessor{
+		eventRepo:     config.EventRepo,
+		webhookRepo:   config.WebhookRepo,
+		sefinClient:   config.SefinClient,
+		webhookSender: config.WebhookSender,
+	}
+	if config.EmissionRepo != nil {
+		p.lifecycle = NewLifecycleTracker(config.EmissionRepo)
+	}
+	return p
+}
+
+// ProcessEvent handles the event:register task.
+// The pedRegEvento is built and signed on every attempt because the author's
+// certificate is also required for mTLS when submitting to the government API.
+func (p *EventProcessor) ProcessEvent(ctx context.Context, task *asynq.Task) error {
+	// Parse task payload
+	payload, err := ParseEventTask(task)
+	if err != nil {
+		// Return nil to prevent retries for invalid payloads
+		log.Printf("Error parsing event task: %v", err)
+		return nil
+	}
+
+	requestID := payload.RequestID
+	log.Printf("Processing event request: %s", requestID)
+
+	// Load event request from database
+	eventReq, err := p.eventRepo.FindByRequestID(ctx, requestID)
+	if err != nil {
+		if err == mongodb.ErrEventRequestNotFound {
+			log.Printf("Event request not found: %s", requestID)
+			return nil // Don't retry if not found
+		}
+		return fmt.Errorf("failed to load event request: %w", err)
+	}
+
+	// Skip if already processed
+	if eventReq.Status == emission.StatusSuccess || eventReq.Status == emission.StatusFailed {
+		log.Printf("Event request %s already processed with status: %s", requestID, eventReq.Status)
+		return nil
+	}
+
+	// Update status to processing
+	if err := p.eventRepo.UpdateStatus(ctx, requestID, emission.StatusProcessing); err != nil {
+		return fmt.Errorf("failed to update status to processing: %w", err)
+	}
+
+	// Load the author's certificate
+	if eventReq.Certificate == nil || eventReq.Certificate.PFXBase64 == "" {
+		p.reject(ctx, eventReq, emission.ErrorCodeCertificateError, "Certificate is not available for this event request", "")
+		return nil
+	}
+
+	certInfo, err := xmlsigner.ParsePFXBase64(eventReq.Certificate.PFXBase64, eventReq.Certificate.Password)
+	if err == nil {
+		err = xmlsigner.NewCertificateValidator().ValidateForSigning(certInfo)
+	}
+	if err != nil {
+		p.reject(ctx, eventReq, emission.ErrorCodeCertificateError, fmt.Sprintf("Invalid certificate: %v", err), "")
+		return nil // Don't retry certificate errors
+	}
+
+	// Build the pedRegEvento XML
+	buildResult, err := p.buildEventXML(eventReq)
+	if err != nil {
+		p.reject(ctx, eventReq, emission.ErrorCodeXMLBuildError, fmt.Sprintf("Failed to build event XML: %v", err), "")
+		return nil // Don't retry XML build errors
+	}
+
+	log.Printf("Built event XML for request %s, ID: %s", requestID, buildResult.ID)
+
+	// Sign the pedRegEvento XML, keeping it compact so the signed infPedReg is sent unchanged
+	signedXML, err := xmlsigner.NewXMLSigner(certInfo).SignEventCompact(buildResult.XML)
+	if err != nil {
+		p.reject(ctx, eventReq, emission.ErrorCodeCertificateError, fmt.Sprintf("Failed to sign event XML: %v", err), "")
+		return nil // Don't retry signing errors
+	}
+
+	// Submit to SEFIN
+	sefinResult, err := p.sefinClient.RegisterEvent(ctx, eventReq.ChaveAcesso, signedXML, certInfo.TLSCertificate())
+	if err != nil {
+		// The NFS-e or the author's permission will not change on retry
+		if errors.Is(err, sefin.ErrNFSeNotFound) || errors.Is(err, sefin.ErrForbidden) {
+			p.reject(ctx, eventReq, emission.ErrorCodeGovernmentRejection, err.Error(), "")
+			return nil
+		}
+
+		// Network/system error - retry
+		if updateErr := p.eventRepo.IncrementRetryCount(ctx, requestID, err.Error()); updateErr != nil {
+			log.Printf("Error incrementing retry count: %v", updateErr)
+		}
+		p.releaseOnFinalAttempt(ctx, eventReq)
+		return fmt.Errorf("SEFIN event registration failed: %w", err)
+	}
+
+	// The request reached a final state; clear the stored certificate credentials
+	p.clearCertificate(ctx, eventReq, certInfo)
+
+	if !sefinResult.Success {
+		// Rejection from SEFIN - don't retry
+		log.Printf("Event request %s rejected by SEFIN: %s - %s", requestID, sefinResult.ErrorCode, sefinResult.ErrorMessage)
+		p.reject(ctx, eventReq, emission.ErrorCodeGovernmentRejection, sefinResult.ErrorMessage, sefinResult.ErrorCode)
+		return nil
+	}
+
+	eventType := sefinResult.TipoEvento
+	if eventType == "" {
+		eventType = eventReq.EventType
+	}
+
+	result := &mongodb.EventResult{
+		EventType:    eventType,
+		Sequence:     sefinResult.Sequencia,
+		RegisteredAt: sefinResult.DataProcessamento,
+		EventXML:     sefinResult.EventXML,
+	}
+
+	if err := p.eventRepo.UpdateResult(ctx, requestID, result); err != nil {
+		log.Printf("Error updating result: %v", err)
+		return fmt.Errorf("failed to update result: %w", err)
+	}
+
+	log.Printf("Event request %s registered successfully: %s seq %d", requestID, eventType, sefinResult.Sequencia)
+	lifecycle := p.updateLifecycle(ctx, eventReq, result)
+	p.sendWebhook(ctx, eventReq, result, nil, lifecycle)
+	return nil
+}
+
+// updateLifecycle applies the registered event to the lifecycle of the NFS-e.
+// Failures are only logged: the event is already registered by the government API.
+func (p *EventProcessor) updateLifecycle(ctx context.Context, req *mongodb.EventRequest, result *mongodb.EventResult) *mongodb.LifecycleData {
+	if p.lifecycle == nil {
+		return nil
+	}
+
+	lifecycle, err := p.lifecycle.Apply(ctx, req.ChaveAcesso, LifecycleEvent{
+		EventType:  result.EventType,
+		Sequence:   result.Sequence,
+		OccurredAt: result.RegisteredAt,
+		Source:     event.LifecycleSourceRegistered,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to update lifecycle for request %s: %v", req.RequestID, err)
+		return nil
+	}
+
+	return lifecycle
+}
+
+// buildEventXML creates the pedRegEvento XML document from the event request.
+func (p *EventProcessor) buildEventXML(req *mongodb.EventRequest) (*xmlbuilder.PedRegEventoBuildResult, error) {
+	// Determine environment code (1=production, 2=homologation)
+	envCode := 2 // Default to homologation
+	if req.Environment == "producao" || req.Environment == "production" {
+		envCode = 1
+	}
+
+	config := xmlbuilder.PedRegEventoConfig{
+		Environment:        envCode,
+		EventDateTime:      time.Now(),
+		ApplicationVersion: "1.0.0",
+		AuthorCNPJ:         req.Author.CNPJ,
+		AuthorCPF:          req.Author.CPF,
+		AccessKey:          req.ChaveAcesso,
+	}
+
+	switch req.EventType {
+	case event.TypeCancellation:
+		if req.Cancellation == nil {
+			return nil, fmt.Errorf("cancellation data is missing")
+		}
+		config.Cancellation = &xmlbuilder.CancellationEvent{
+			ReasonCode:        req.Cancellation.ReasonCode,
+			ReasonDescription: req.Cancellation.Reason,
+		}
+	case event.TypeProviderConfirmation, event.TypeTakerConfirmation, event.TypeIntermediaryConfirmation,
+		event.TypeProviderRejection, event.TypeTakerRejection, event.TypeIntermediaryRejection,
+		event.TypeRejectionAnnulment:
+		if req.Manifestation == nil {
+			return nil, fmt.Errorf("manifestation data is missing")
+		}
+		config.Manifestation = &xmlbuilder.ManifestationEvent{
+			EventType:         req.EventType,
+			ReasonCode:        req.Manifestation.ReasonCode,
+			ReasonDescription: req.Manifestation.Reason,
+			TaxAgentCPF:       req.Manifestation.TaxAgentCPF,
+			RejectionEventID:  req.Manifestation.RejectionEventID,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event type: %s", req.EventType)
+	}
+
+	return xmlbuilder.NewPedRegEventoBuilder(config).Build()
+}
+
+// reject records a final failure for the event request, clears the stored
+// certificate credentials and notifies the webhook.
+func (p *EventProcessor) reject(ctx context.Context, req *mongodb.EventRequest, code, message, governmentCode string) {
+	rejection := &mongodb.RejectionInfo{
+		Code:           code,
+		Message:        message,
+		GovernmentCode: governmentCode,
+	}
+
+	if err := p.eventRepo.UpdateRejection(ctx, req.RequestID, rejection); err != nil {
+		log.Printf("Error updating rejection: %v", err)
+	}
+
+	p.releaseCertificate(ctx, req)
+	p.sendWebhook(ctx, req, nil, rejection, nil)
+}
+
+// clearCertificate records the signing certificate metadata and removes the stored credentials.
+func (p *EventProcessor) clearCertificate(ctx context.Context, req *mongodb.EventRequest, certInfo *xmlsigner.CertificateInfo) {
+	if err := p.eventRepo.UpdateSigningStatus(
+		ctx,
+		req.RequestID,
+		true,
+		certInfo.GetSubjectCN(),
+		certInfo.GetIssuerCN(),
+		certInfo.GetSerialNumber(),
+	); err != nil {
+		log.Printf("Warning: failed to update signing status: %v", err)
+		return
+	}
+
+	req.Certificate.PFXBase64 = ""
+	req.Certificate.Password = ""
+}
+
+// releaseCertificate removes the stored credentials of a request that ends without
+// an answer from the government API. Answered requests are cleared by
+// clearCertificate, which also records the certificate metadata.
+func (p *EventProcessor) releaseCertificate(ctx context.Context, req *mongodb.EventRequest) {
+	if req.Certificate == nil || req.Certificate.PFXBase64 == "" {
+		return
+	}
+
+	if err := p.eventRepo.ClearCredentials(ctx, req.RequestID); err != nil {
+		log.Printf("Warning: failed to clear certificate credentials: %v", err)
+	}
+}
+
+// releaseOnFinalAttempt clears the stored credentials when the task fails on its
+// last attempt: asynq archives it, so no later attempt will sign the event.
+func (p *EventProcessor) releaseOnFinalAttempt(ctx context.Context, req *mongodb.EventRequest) {
+	if !isFinalAttempt(ctx) {
+		return
+	}
+
+	log.Printf("Event request %s failed on its last attempt, releasing the certificate", req.RequestID)
+	p.releaseCertificate(ctx, req)
+}
+
+// sendWebhook sends a webhook notification for the event result.
+func (p *EventProcessor) sendWebhook(ctx context.Context, req *mongodb.EventRequest, result *mongodb.EventResult, rejection *mongodb.RejectionInfo, lifecycle *mongodb.LifecycleData) {
+	// Skip if no webhook URL
+	if req.WebhookURL == "" {
+		log.Printf("No webhook URL configured for request %s", req.RequestID)
+		return
+	}
+
+	// Determine webhook event and status
+	var webhookEvent, status string
+	if result != nil {
+		webhookEvent = event.WebhookEventRegistered
+		status = emission.StatusSuccess
+	} else {
+		webhookEvent = event.WebhookEventFailed
+		status = emission.StatusFailed
+	}
+
+	// Build payload
+	payload := event.WebhookPayload{
+		Event:       webhookEvent,
+		RequestID:   req.RequestID,
+		Timestamp:   time.Now().UTC(),
+		Status:      status,
+		ChaveAcesso: req.ChaveAcesso,
+		EventType:   req.EventType,
+	}
+
+	if req.Manifestation != nil {
+		payload.Manifestation = NewManifestationDTO(req.Manifestation)
+	}
+
+	if result != nil {
+		payload.Result = NewEventResultDTO(result)
+		payload.Lifecycle = NewLifecycleDTO(lifecycle)
+	}
+
+	if rejection != nil {
+		payload.Error = &emission.EmissionErrorDTO{
+			Code:           rejection.Code,
+			Message:        rejection.Message,
+			GovernmentCode: rejection.GovernmentCode,
+			Details:        rejection.Details,
+		}
+	}
+
+	// Create webhook delivery record
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error marshaling webhook payload: %v", err)
+		return
+	}
+
+	delivery := &mongodb.WebhookDelivery{
+		RequestID: req.RequestID,
+		APIKeyID:  req.APIKeyID,
+		URL:       req.WebhookURL,
+		Status:    mongodb.WebhookStatusPending,
+		Payload:   string(payloadBytes),
+	}
+
+	if err := p.webhookRepo.Create(ctx, delivery); err != nil {
+		log.Printf("Error creating webhook delivery record: %v", err)
+		return
+	}
+
+	// Send webhook (TODO: Get secret from API key)
+	webhookSecret := "" // In production, get this from the API key
+	sendResult, err := p.webhookSender.Send(ctx, req.WebhookURL, payload, webhookSecret, req.RequestID)
+	if err != nil {
+		log.Printf("Webhook delivery failed for request %s: %v", req.RequestID, err)
+		if markErr := p.webhookRepo.MarkFailed(ctx, delivery.ID, err.Error(), sendResult.Duration.Milliseconds()); markErr != nil {
+			log.Printf("Error marking webhook as failed: %v", markErr)
+		}
+		return
+	}
+
+	if sendResult.Success {
+		log.Printf("Webhook delivered successfully for request %s", req.RequestID)
+		if markErr := p.webhookRepo.MarkSuccess(ctx, delivery.ID, sendResult.StatusCode, sendResult.ResponseBody, sendResult.Duration.Milliseconds()); markErr != nil {
+			log.Printf("Error marking webhook as success: %v", markErr)
+		}
+	} else {
+		log.Printf("Webhook delivery failed for request %s: %s", req.RequestID, sendResult.Error)
+		if markErr := p.webhookRepo.MarkFailed(ctx, delivery.ID, sendResult.Error, sendResult.Duration.Milliseconds()); markErr != nil {
+			log.Printf("Error marking webhook as failed: %v", markErr)
+		}
+	}
+}
+
+// NewEventResultDTO converts a stored event result into its API representation.
+func NewEventResultDTO(result *mongodb.EventResult) *event.ResultDTO {
+	dto := &event.ResultDTO{
+		EventType:   result.EventType,
+		Description: event.TypeDescriptions[result.EventType],
+		Sequence:    result.Sequence,
+		XML:         result.EventXML,
+	}
+	if !result.RegisteredAt.IsZero() {
+		registeredAt := result.RegisteredAt
+		dto.RegisteredAt = &registeredAt
+	}
+	return dto
+}
+
+// NewManifestationDTO converts stored manifestation data into its API representation.
+func NewManifestationDTO(m *mongodb.ManifestationData) *event.ManifestationDTO {
+	return &event.ManifestationDTO{
+		Role:             m.Role,
+		Action:           m.Action,
+		ReasonCode:       m.ReasonCode,
+		Reason:           m.Reason,
+		TaxAgentCPF:      m.TaxAgentCPF,
+		RejectionEventID: m.RejectionEventID,
+	}
+}
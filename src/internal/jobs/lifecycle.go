@@ -0,0 +1,237 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
+	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
+)
+
+// LifecycleRepository defines the emission operations needed to track the
+// lifecycle of the NFS-e emitted through this API.
+type LifecycleRepository interface {
+	FindByAccessKey(ctx context.Context, accessKey string) (*mongodb.EmissionRequest, error)
+	UpdateLifecycle(ctx context.Context, requestID string, lifecycle *mongodb.LifecycleData) error
+}
+
+// LifecycleEvent is an event linked to an NFS-e that may change its lifecycle.
+type LifecycleEvent struct {
+	// EventType is the event type code (e.g., "e101101").
+	EventType string
+
+	// Sequence is the event sequence number (nSeqEvento), if known.
+	Sequence int
+
+	// OccurredAt is when the event was registered by the government API, if known.
+	OccurredAt time.Time
+
+	// Source indicates how the event became known (event.LifecycleSource*).
+	Source string
+
+	// ReplacedBy is the access key of the substitute NFS-e of a cancellation by
+	// substitution (e105102), if known.
+	ReplacedBy string
+
+	// EventID is the event identifier (infEvento Id without the "EVT" prefix), if known.
+	EventID string
+
+	// BlockedEventType is the event type blocked by an ex officio block (e305102), if known.
+	BlockedEventType string
+
+	// BlockID is the identifier of the block lifted by an ex officio unblock (e305103), if known.
+	BlockID string
+}
+
+// NewDiscoveredLifecycleEvent converts an event found in the government API into a
+// lifecycle event.
+func NewDiscoveredLifecycleEvent(evt sefin.EventData) LifecycleEvent {
+	occurredAt := evt.DataProcessamento
+	if occurredAt.IsZero() {
+		occurredAt = evt.Data
+	}
+	return LifecycleEvent{
+		EventType:        evt.Tipo,
+		Sequence:         evt.Sequencia,
+		OccurredAt:       occurredAt,
+		Source:           event.LifecycleSourceDiscovered,
+		EventID:          evt.ID,
+		BlockedEventType: evt.CodEventoBloqueado,
+		BlockID:          evt.IDBloqueio,
+	}
+}
+
+// LifecycleTracker keeps the local lifecycle of emitted NFS-e up to date.
+type LifecycleTracker struct {
+	emissionRepo LifecycleRepository
+}
+
+// NewLifecycleTracker creates a new lifecycle tracker.
+func NewLifecycleTracker(emissionRepo LifecycleRepository) *LifecycleTracker {
+	return &LifecycleTracker{
+		emissionRepo: emissionRepo,
+	}
+}
+
+// Apply applies the given events to the lifecycle of the NFS-e and persists the
+// result. Events already present in the lifecycle history (same type and
+// sequence) are skipped, so the same events can be applied more than once; an
+// event recorded before its sequence was known is completed instead.
+// It returns nil, nil if the NFS-e was not emitted through this API.
+func (t *LifecycleTracker) Apply(ctx context.Context, accessKey string, events ...LifecycleEvent) (*mongodb.LifecycleData, error) {
+	req, err := t.emissionRepo.FindByAccessKey(ctx, accessKey)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrEmissionRequestNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load emission request: %w", err)
+	}
+
+	lifecycle := req.Lifecycle
+	if lifecycle == nil {
+		lifecycle = &mongodb.LifecycleData{Status: event.LifecycleActive}
+	}
+
+	now := time.Now().UTC()
+	changed := false
+	for _, ev := range events {
+		if ev.ReplacedBy != "" && lifecycle.ReplacedBy == "" {
+			lifecycle.ReplacedBy = ev.ReplacedBy
+			lifecycle.UpdatedAt = now
+			changed = true
+		}
+
+		if tr := findLifecycleTransition(lifecycle, ev); tr != nil {
+			if tr.Sequence == 0 && ev.Sequence > 0 {
+				tr.Sequence = ev.Sequence
+				tr.OccurredAt = ev.OccurredAt
+				changed = true
+			}
+			continue
+		}
+
+		state := event.LifecycleState{
+			Status:         lifecycle.Status,
+			PreviousStatus: lifecycle.PreviousStatus,
+			UnderReview:    lifecycle.UnderReview,
+			BlockedEvents:  lifecycle.BlockedEvents,
+		}
+		target := lifecycleBlockTarget(lifecycle.History, ev)
+		next, ok := state.Apply(ev.EventType, target)
+		if !ok && !isLifecycleEvent(ev.EventType) {
+			continue
+		}
+
+		lifecycle.History = append(lifecycle.History, mongodb.LifecycleTransition{
+			EventType:        ev.EventType,
+			Sequence:         ev.Sequence,
+			FromStatus:       state.Status,
+			ToStatus:         next.Status,
+			OccurredAt:       ev.OccurredAt,
+			RecordedAt:       now,
+			Source:           ev.Source,
+			EventID:          ev.EventID,
+			BlockedEventType: target,
+		})
+		lifecycle.Status = next.Status
+		lifecycle.PreviousStatus = next.PreviousStatus
+		lifecycle.UnderReview = next.UnderReview
+		lifecycle.BlockedEvents = next.BlockedEvents
+		lifecycle.LastEventType = ev.EventType
+		lifecycle.UpdatedAt = now
+		changed = true
+	}
+
+	if !changed {
+		return lifecycle, nil
+	}
+
+	if err := t.emissionRepo.UpdateLifecycle(ctx, req.RequestID, lifecycle); err != nil {
+		return nil, fmt.Errorf("failed to update lifecycle: %w", err)
+	}
+
+	return lifecycle, nil
+}
+
+// findLifecycleTransition returns the transition of the lifecycle history that
+// already applied the event, or nil. A transition or event without sequence (zero)
+// matches any sequence of the same event type.
+func findLifecycleTransition(lifecycle *mongodb.LifecycleData, ev LifecycleEvent) *mongodb.LifecycleTransition {
+	for i := range lifecycle.History {
+		tr := &lifecycle.History[i]
+		if tr.EventType != ev.EventType {
+			continue
+		}
+		if tr.Sequence == ev.Sequence || tr.Sequence == 0 || ev.Sequence == 0 {
+			return tr
+		}
+	}
+	return nil
+}
+
+// lifecycleBlockTarget returns the event type a block or unblock refers to. An unblock
+// only names the block it lifts, so its event type is taken from the history.
+func lifecycleBlockTarget(history []mongodb.LifecycleTransition, ev LifecycleEvent) string {
+	switch ev.EventType {
+	case event.TypeOfficialBlock:
+		return ev.BlockedEventType
+	case event.TypeOfficialUnblock:
+		if ev.BlockedEventType != "" || ev.BlockID == "" {
+			return ev.BlockedEventType
+		}
+		for _, tr := range history {
+			if tr.EventType == event.TypeOfficialBlock && tr.EventID == ev.BlockID {
+				return tr.BlockedEventType
+			}
+		}
+	}
+	return ""
+}
+
+// isLifecycleEvent reports whether the event type is a known NFS-e event, so
+// that it is recorded in the history even when it does not change the status.
+func isLifecycleEvent(eventType string) bool {
+	_, ok := event.TypeDescriptions[eventType]
+	return ok
+}
+
+// NewLifecycleDTO converts a stored lifecycle into its API representation.
+func NewLifecycleDTO(lifecycle *mongodb.LifecycleData) *emission.LifecycleDTO {
+	if lifecycle == nil {
+		return nil
+	}
+
+	dto := &emission.LifecycleDTO{
+		Status:                  lifecycle.Status,
+		LastEventType:           lifecycle.LastEventType,
+		ReplacedBy:              lifecycle.ReplacedBy,
+		CancellationUnderReview: lifecycle.UnderReview,
+		BlockedEvents:           lifecycle.BlockedEvents,
+	}
+	if !lifecycle.UpdatedAt.IsZero() {
+		updatedAt := lifecycle.UpdatedAt
+		dto.UpdatedAt = &updatedAt
+	}
+
+	for _, tr := range lifecycle.History {
+		entry := emission.LifecycleTransitionDTO{
+			EventType:   tr.EventType,
+			Description: event.TypeDescriptions[tr.EventType],
+			Sequence:    tr.Sequence,
+			FromStatus:  tr.FromStatus,
+			ToStatus:    tr.ToStatus,
+			Source:      tr.Source,
+		}
+		if !tr.OccurredAt.IsZero() {
+			occurredAt := tr.OccurredAt
+			entry.OccurredAt = &occurredAt
+		}
+		dto.History = append(dto.History, entry)
+	}
+
+	return dto
+}
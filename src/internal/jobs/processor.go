@@ -4,6 +4,7 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -11,6 +12,9 @@ import (
 	"github.com/hibiken/asynq"
 
 	"github.com/eduardo/nfse-nacional/internal/domain/emission"
+	"github.com/eduardo/nfse-nacional/internal/domain/event"
+	"github.com/eduardo/nfse-nacional/internal/domain/taxburden"
+	"github.com/eduardo/nfse-nacional/internal/domain/validation"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/mongodb"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/sefin"
 	"github.com/eduardo/nfse-nacional/internal/infrastructure/webhook"
@@ -18,17 +22,31 @@ import (
 	"github.com/eduardo/nfse-nacional/pkg/xmlbuilder"
 )
 
+// ServiceParametersLookup retrieves the parameters a municipality defines for a service.
+type ServiceParametersLookup interface {
+	GetServiceParameters(ctx context.Context, codigoMunicipio, codigoServico string) (*sefin.ServiceParametersResult, error)
+}
+
+// TaxBurdenLookup retrieves the approximate tax burden rates of the offline table.
+type TaxBurdenLookup interface {
+	FindRate(ctx context.Context, state, codeType, code string) (*mongodb.TaxBurdenRate, error)
+}
+
 // EmissionProcessor handles emission job processing.
 type EmissionProcessor struct {
 	emissionRepo  *mongodb.EmissionRepository
+	lifecycle     *LifecycleTracker
 	webhookRepo   *mongodb.WebhookRepository
 	sefinClient   sefin.SefinClient
+	parameters    ServiceParametersLookup
+	taxBurden     TaxBurdenLookup
 	webhookSender *webhook.Sender
 }
 
 // EmissionProcessorConfig configures the emission processor.
 type EmissionProcessorConfig struct {
-	// EmissionRepo is the repository for emission requests.
+	// EmissionRepo is the repository for emission requests. It also tracks the
+	// lifecycle of the NFS-e replaced by a substitute emitted through this API.
 	EmissionRepo *mongodb.EmissionRepository
 
 	// WebhookRepo is the repository for webhook deliveries.
@@ -37,16 +55,34 @@ type EmissionProcessorConfig struct {
 	// SefinClient is the SEFIN API client.
 	SefinClient sefin.SefinClient
 
+	// Parameters retrieves the municipal service parameters used to fill the ISS
+	// rate of ME/EPP providers (default: a sefin.ParametersCache over SefinClient).
+	Parameters ServiceParametersLookup
+
+	// TaxBurden retrieves the approximate tax burden rates used to fill totTrib.
+	// Optional; without it the tax burden is declared as not informed.
+	TaxBurden TaxBurdenLookup
+
 	// WebhookSender is the webhook sender.
 	WebhookSender *webhook.Sender
 }
 
 // NewEmissionProcessor creates a new emission processor.
 func NewEmissionProcessor(config EmissionProcessorConfig) *EmissionProcessor {
+	parameters := config.Parameters
+	if parameters == nil && config.SefinClient != nil {
+		parameters = sefin.NewParametersCache(sefin.ParametersCacheConfig{
+			Client: config.SefinClient,
+		})
+	}
+
 	return &EmissionProcessor{
 		emissionRepo:  config.EmissionRepo,
+		lifecycle:     NewLifecycleTracker(config.EmissionRepo),
 		webhookRepo:   config.WebhookRepo,
 		sefinClient:   config.SefinClient,
+		parameters:    parameters,
+		taxBurden:     config.TaxBurden,
 		webhookSender: config.WebhookSender,
 	}
 }
@@ -94,7 +130,54 @@ func (p *EmissionProcessor) ProcessEmission(ctx context.Context, task *asynq.Tas
 		log.Printf("Processing pre-signed XML for request %s", requestID)
 		dpsXML = emissionReq.PreSignedXML
 	} else {
-		// Standard flow: Build and optionally sign the DPS XML
+		// Standard flow: Resolve the ISS rate and the tax burden, then build and optionally sign the DPS XML
+		if emissionReq.ISSRate == nil {
+			issRate, err := p.resolveISSRate(ctx, emissionReq)
+			if err != nil {
+				// Government API unavailable - retry
+				if updateErr := p.emissionRepo.IncrementRetryCount(ctx, requestID, err.Error()); updateErr != nil {
+					log.Printf("Error incrementing retry count: %v", updateErr)
+				}
+				p.releaseOnFinalAttempt(ctx, emissionReq)
+				return fmt.Errorf("ISS rate lookup failed: %w", err)
+			}
+			if updateErr := p.emissionRepo.UpdateISSRate(ctx, requestID, issRate); updateErr != nil {
+				log.Printf("Warning: failed to record ISS rate: %v", updateErr)
+			}
+			emissionReq.ISSRate = issRate
+		}
+
+		if emissionReq.TotalTaxes == nil {
+			totalTaxes, err := p.resolveTotalTaxes(ctx, emissionReq)
+			if err != nil {
+				// Tax burden table unavailable - retry
+				if updateErr := p.emissionRepo.IncrementRetryCount(ctx, requestID, err.Error()); updateErr != nil {
+					log.Printf("Error incrementing retry count: %v", updateErr)
+				}
+				p.releaseOnFinalAttempt(ctx, emissionReq)
+				return fmt.Errorf("tax burden lookup failed: %w", err)
+			}
+			if updateErr := p.emissionRepo.UpdateTotalTaxes(ctx, requestID, totalTaxes); updateErr != nil {
+				log.Printf("Warning: failed to record total taxes: %v", updateErr)
+			}
+			emissionReq.TotalTaxes = totalTaxes
+		}
+
+		// SEFIN rejects an ME/EPP DPS with the tax burden not informed (E0712), so a
+		// service missing from the table needs the Simples Nacional rate in the request
+		if emissionReq.Provider.TaxRegime == validation.TaxRegimeMEEPP && emissionReq.TotalTaxes.Source == emission.TotalTaxesSourceNotFound {
+			rejectionInfo := &mongodb.RejectionInfo{
+				Code:    emission.ErrorCodeValidation,
+				Message: "The service is missing from the tax burden table: 'me_epp' providers must inform values.simples_tax_rate (pTotTribSN)",
+			}
+			if updateErr := p.emissionRepo.UpdateRejection(ctx, requestID, rejectionInfo); updateErr != nil {
+				log.Printf("Error updating rejection: %v", updateErr)
+			}
+			p.releaseCertificate(ctx, emissionReq)
+			p.sendWebhook(ctx, emissionReq, nil, rejectionInfo)
+			return nil // The request must be sent again with the rate
+		}
+
 		dpsResult, err := p.buildDPSXML(emissionReq)
 		if err != nil {
 			// This is a configuration/validation error, don't retry
@@ -105,6 +188,7 @@ func (p *EmissionProcessor) ProcessEmission(ctx context.Context, task *asynq.Tas
 			if updateErr := p.emissionRepo.UpdateRejection(ctx, requestID, rejectionInfo); updateErr != nil {
 				log.Printf("Error updating rejection: %v", updateErr)
 			}
+			p.releaseCertificate(ctx, emissionReq)
 			p.sendWebhook(ctx, emissionReq, nil, rejectionInfo)
 			return nil // Don't retry XML build errors
 		}
@@ -124,6 +208,7 @@ func (p *EmissionProcessor) ProcessEmission(ctx context.Context, task *asynq.Tas
 				if updateErr := p.emissionRepo.UpdateRejection(ctx, requestID, rejectionInfo); updateErr != nil {
 					log.Printf("Error updating rejection: %v", updateErr)
 				}
+				p.releaseCertificate(ctx, emissionReq)
 				p.sendWebhook(ctx, emissionReq, nil, rejectionInfo)
 				return nil // Don't retry signing errors
 			}
@@ -148,6 +233,7 @@ func (p *EmissionProcessor) ProcessEmission(ctx context.Context, task *asynq.Tas
 		if updateErr := p.emissionRepo.IncrementRetryCount(ctx, requestID, err.Error()); updateErr != nil {
 			log.Printf("Error incrementing retry count: %v", updateErr)
 		}
+		p.releaseOnFinalAttempt(ctx, emissionReq)
 		return fmt.Errorf("SEFIN submission failed: %w", err)
 	}
 
@@ -166,6 +252,12 @@ func (p *EmissionProcessor) ProcessEmission(ctx context.Context, task *asynq.Tas
 		}
 
 		log.Printf("Emission request %s completed successfully, NFS-e: %s", requestID, sefinResponse.NFSeNumber)
+
+		// The government API cancelled the replaced NFS-e when it accepted the substitute
+		if emissionReq.Substitution != nil {
+			p.recordSubstitution(ctx, emissionReq, result)
+		}
+
 		p.sendWebhook(ctx, emissionReq, result, nil)
 		return nil
 	}
@@ -189,6 +281,7 @@ func (p *EmissionProcessor) ProcessEmission(ctx context.Context, task *asynq.Tas
 }
 
 // signDPSXML signs the DPS XML using the provided certificate.
+// The stored certificate credentials are cleared after signing.
 func (p *EmissionProcessor) signDPSXML(ctx context.Context, requestID string, certData *mongodb.CertificateData, dpsXML string) (string, error) {
 	if certData == nil || !certData.HasCertificate {
 		return dpsXML, nil // Return unsigned if no certificate
@@ -229,6 +322,131 @@ func (p *EmissionProcessor) signDPSXML(ctx context.Context, requestID string, ce
 	return signedXML, nil
 }
 
+// resolveISSRate determines the ISS rate of the DPS. When the DPS must inform the
+// rate (see issRateApplies), it gets the rate and municipal tax code the issuing
+// municipality defines for the service; every other DPS is sent without a rate and
+// SEFIN applies the municipal one. A service without municipal parameters is also
+// sent without a rate.
+func (p *EmissionProcessor) resolveISSRate(ctx context.Context, req *mongodb.EmissionRequest) (*mongodb.ISSRateData, error) {
+	if !issRateApplies(req) || p.parameters == nil {
+		return &mongodb.ISSRateData{Source: emission.ISSRateSourceNotApplicable}, nil
+	}
+
+	params, err := p.parameters.GetServiceParameters(ctx, req.Service.MunicipalityCode, req.Service.NationalCode)
+	if err != nil {
+		if errors.Is(err, sefin.ErrServiceParametersNotFound) {
+			log.Printf("Warning: no parameters for service %s in municipality %s, request %s sent without ISS rate",
+				req.Service.NationalCode, req.Service.MunicipalityCode, req.RequestID)
+			return &mongodb.ISSRateData{Source: emission.ISSRateSourceNotFound}, nil
+		}
+		return nil, err
+	}
+
+	consultedAt := params.ConsultedAt
+	return &mongodb.ISSRateData{
+		Rate:             params.Aliquota,
+		Source:           emission.ISSRateSourceMunicipalParameters,
+		MunicipalTaxCode: params.CodigoTributacaoMunicipal,
+		ConsultedAt:      &consultedAt,
+	}, nil
+}
+
+// issRateApplies reports whether the DPS informs the ISS rate (pAliq). In a municipality
+// with an active agreement, which the API checks before queuing, only an ME/EPP provider
+// calculating the ISS within the Simples Nacional (regApTribSN = 1) informs the rate, and
+// only when the taker or intermediary withholds the ISS (E0621). The rate is forbidden
+// otherwise (E0617, E0625, E0635), for MEI providers (E0600), special regimes (E0604)
+// and immune, exported or non-incident operations (E0602).
+func issRateApplies(req *mongodb.EmissionRequest) bool {
+	provider := req.Provider
+	if provider.TaxRegime != validation.TaxRegimeMEEPP || provider.SpecialRegime != emission.SpecialRegimeNone {
+		return false
+	}
+	if calc := provider.SimplesCalculationRegime; calc != 0 && calc != emission.SimplesCalculationAll {
+		return false
+	}
+
+	iss := req.Values.ISS
+	if iss == nil || (iss.Taxation != 0 && iss.Taxation != emission.ISSTaxationTaxable) {
+		return false
+	}
+	return iss.Withholding == emission.ISSWithholdingTaker || iss.Withholding == emission.ISSWithholdingIntermediary
+}
+
+// resolveTotalTaxes determines the approximate tax burden of the DPS. The Simples
+// Nacional percentage sent in the request takes precedence; otherwise the amounts are
+// calculated from the tax burden table of the issuing state for the service item, on
+// the service value minus the unconditional discount. A service missing from the table
+// is sent with the tax burden declared as not informed, which ME/EPP providers cannot do.
+func (p *EmissionProcessor) resolveTotalTaxes(ctx context.Context, req *mongodb.EmissionRequest) (*mongodb.TotalTaxesData, error) {
+	if req.Values.SimplesTaxRate > 0 {
+		return &mongodb.TotalTaxesData{
+			Source:      emission.TotalTaxesSourceSimplesRate,
+			SimplesRate: req.Values.SimplesTaxRate,
+		}, nil
+	}
+
+	state := taxburden.StateFromMunicipality(req.Service.MunicipalityCode)
+	item, err := taxburden.ServiceItemFromNationalCode(req.Service.NationalCode)
+	if p.taxBurden == nil || state == "" || err != nil {
+		return &mongodb.TotalTaxesData{Source: emission.TotalTaxesSourceNotFound}, nil
+	}
+
+	notFound := &mongodb.TotalTaxesData{
+		Source:   emission.TotalTaxesSourceNotFound,
+		State:    state,
+		CodeType: taxburden.CodeTypeServiceItem,
+		Code:     item,
+	}
+
+	// The NBS code classifies the service more precisely than its LC 116 item
+	var stored *mongodb.TaxBurdenRate
+	if req.Service.NBSCode != "" {
+		stored, err = p.taxBurden.FindRate(ctx, state, taxburden.CodeTypeNBS, req.Service.NBSCode)
+		if err != nil && !errors.Is(err, mongodb.ErrTaxBurdenRateNotFound) {
+			return nil, err
+		}
+	}
+	if stored == nil {
+		stored, err = p.taxBurden.FindRate(ctx, state, taxburden.CodeTypeServiceItem, item)
+	}
+	if err != nil {
+		if errors.Is(err, mongodb.ErrTaxBurdenRateNotFound) {
+			log.Printf("Warning: no tax burden rate for service item %s in %s, request %s sent without tax burden",
+				item, state, req.RequestID)
+			return notFound, nil
+		}
+		return nil, err
+	}
+
+	rate := taxburden.Rate{
+		FederalRate:   stored.FederalRate,
+		StateRate:     stored.StateRate,
+		MunicipalRate: stored.MunicipalRate,
+		ValidFrom:     stored.ValidFrom,
+		ValidUntil:    stored.ValidUntil,
+	}
+	if !rate.IsValid(time.Now()) {
+		log.Printf("Warning: tax burden table %s of %s is out of its validity, load a new version", stored.Version, state)
+	}
+
+	amounts := rate.Approximate(req.Values.ServiceValue - req.Values.UnconditionalDiscount)
+	return &mongodb.TotalTaxesData{
+		Source:          emission.TotalTaxesSourceTable,
+		State:           state,
+		CodeType:        stored.CodeType,
+		Code:            stored.Code,
+		TableVersion:    stored.Version,
+		TableSource:     stored.Source,
+		FederalRate:     stored.FederalRate,
+		StateRate:       stored.StateRate,
+		MunicipalRate:   stored.MunicipalRate,
+		FederalAmount:   amounts.Federal,
+		StateAmount:     amounts.State,
+		MunicipalAmount: amounts.Municipal,
+	}, nil
+}
+
 // buildDPSXML creates the DPS XML document from the emission request.
 func (p *EmissionProcessor) buildDPSXML(req *mongodb.EmissionRequest) (*xmlbuilder.DPSBuildResult, error) {
 	// Determine environment code (1=production, 2=homologation)
@@ -244,19 +462,28 @@ func (p *EmissionProcessor) buildDPSXML(req *mongodb.EmissionRequest) (*xmlbuild
 		Series:             req.DPS.Series,
 		Number:             req.DPS.Number,
 		CompetenceDate:     time.Now(),
-		EmitterType:        1, // Service provider
+		EmitterType:        req.EmitterType, // Defaults to the service provider
+		EmissionReason:     req.EmissionReason,
+		RejectedAccessKey:  req.RejectedAccessKey,
 		MunicipalityCode:   req.Service.MunicipalityCode,
-		Substitution:       2, // No substitution
 		Provider: xmlbuilder.DPSProvider{
 			CNPJ:                  req.Provider.CNPJ,
+			CPF:                   req.Provider.CPF,
 			Name:                  req.Provider.Name,
 			TaxRegime:             req.Provider.TaxRegime,
 			MunicipalRegistration: req.Provider.MunicipalRegistration,
+
+			SimplesCalculationRegime: req.Provider.SimplesCalculationRegime,
+			SpecialRegime:            req.Provider.SpecialRegime,
+			Address:                  newAddressConfig(req.Provider.Address),
 		},
 		Service: xmlbuilder.DPSService{
 			NationalCode:     req.Service.NationalCode,
 			Description:      req.Service.Description,
 			MunicipalityCode: req.Service.MunicipalityCode,
+			NBSCode:          req.Service.NBSCode,
+			MunicipalTaxCode: req.Service.MunicipalTaxCode,
+			CountryCode:      req.Service.CountryCode,
 		},
 		Values: xmlbuilder.DPSValues{
 			ServiceValue:          req.Values.ServiceValue,
@@ -266,13 +493,161 @@ func (p *EmissionProcessor) buildDPSXML(req *mongodb.EmissionRequest) (*xmlbuild
 		},
 	}
 
+	// Fill the ISS rate, the federal taxes and the resulting tax amounts
+	if req.ISSRate != nil || req.Values.FederalTaxes != nil {
+		input := &emission.CalculationInput{
+			ServiceValue:          req.Values.ServiceValue,
+			UnconditionalDiscount: req.Values.UnconditionalDiscount,
+			ConditionalDiscount:   req.Values.ConditionalDiscount,
+			Deductions:            req.Values.Deductions,
+			FederalTaxes:          newFederalTaxInput(req.Values.FederalTaxes),
+
+			BenefitReductionPercentage: req.Values.MunicipalBenefitReductionPercentage,
+			BenefitReductionValue:      req.Values.MunicipalBenefitReductionValue,
+		}
+		if iss := req.Values.ISS; iss != nil {
+			input.ISSWithheld = iss.Withholding == emission.ISSWithholdingTaker ||
+				iss.Withholding == emission.ISSWithholdingIntermediary
+		}
+		if req.ISSRate != nil {
+			if config.Service.MunicipalTaxCode == "" {
+				config.Service.MunicipalTaxCode = req.ISSRate.MunicipalTaxCode
+			}
+			input.ISSRate = req.ISSRate.Rate
+		}
+
+		calculation, err := emission.NewValueCalculator().Calculate(input)
+		if err != nil {
+			return nil, err
+		}
+		if req.ISSRate != nil {
+			config.Values.ISSRate = calculation.ISSRate
+		}
+		config.Values.FederalTaxes = newDPSFederalTaxes(req.Values.FederalTaxes, calculation)
+	}
+
+	// Add the approximate tax burden resolved for the DPS
+	config.Values.TotalTaxes = newDPSTotalTaxes(req.TotalTaxes)
+
+	// Add the ISS treatment and the municipal benefit if present
+	if iss := req.Values.ISS; iss != nil {
+		config.Values.ISSTaxation = iss.Taxation
+		config.Values.ResultCountryCode = iss.ResultCountryCode
+		config.Values.ImmunityType = iss.ImmunityType
+		config.Values.ISSWithholding = iss.Withholding
+		if susp := iss.Suspension; susp != nil {
+			config.Values.ISSSuspension = &xmlbuilder.DPSISSSuspension{
+				Type:          susp.Type,
+				ProcessNumber: susp.ProcessNumber,
+			}
+		}
+	}
+	if req.Values.MunicipalBenefitNumber != "" {
+		config.Values.MunicipalBenefit = &xmlbuilder.DPSMunicipalBenefit{
+			Number:              req.Values.MunicipalBenefitNumber,
+			ReductionValue:      req.Values.MunicipalBenefitReductionValue,
+			ReductionPercentage: req.Values.MunicipalBenefitReductionPercentage,
+		}
+	}
+
+	// Add deduction documents if present
+	for _, doc := range req.Values.DeductionDocuments {
+		dpsDoc := xmlbuilder.DPSDeductionDocument{
+			NFSeKey:              doc.NFSeKey,
+			NFeKey:               doc.NFeKey,
+			FiscalDocumentNumber: doc.FiscalDocumentNumber,
+			DocumentNumber:       doc.DocumentNumber,
+			Type:                 doc.Type,
+			TypeDescription:      doc.TypeDescription,
+			IssueDate:            doc.IssueDate,
+			DeductibleValue:      doc.DeductibleValue,
+			DeductionValue:       doc.DeductionValue,
+		}
+		if nfse := doc.MunicipalNFSe; nfse != nil {
+			dpsDoc.MunicipalNFSe = &xmlbuilder.DPSMunicipalNFSe{
+				MunicipalityCode: nfse.MunicipalityCode,
+				Number:           nfse.Number,
+				VerificationCode: nfse.VerificationCode,
+			}
+		}
+		if invoice := doc.PaperInvoice; invoice != nil {
+			dpsDoc.PaperInvoice = &xmlbuilder.DPSPaperInvoice{
+				Number: invoice.Number,
+				Model:  invoice.Model,
+				Series: invoice.Series,
+			}
+		}
+		config.Values.DeductionDocuments = append(config.Values.DeductionDocuments, dpsDoc)
+	}
+
+	// Add substitution group if replacing an existing NFS-e
+	if req.Substitution != nil {
+		config.Substitution = &xmlbuilder.DPSSubstitution{
+			ReplacedAccessKey: req.Substitution.ReplacedAccessKey,
+			ReasonCode:        req.Substitution.ReasonCode,
+			ReasonDescription: req.Substitution.Reason,
+		}
+	}
+
+	// Add foreign trade information if present
+	if trade := req.Service.ForeignTrade; trade != nil {
+		config.Service.ForeignTrade = &xmlbuilder.DPSForeignTrade{
+			ServiceMode:              trade.ServiceMode,
+			Relationship:             trade.Relationship,
+			CurrencyCode:             trade.CurrencyCode,
+			ForeignValue:             trade.ForeignValue,
+			ProviderSupportMechanism: trade.ProviderSupportMechanism,
+			TakerSupportMechanism:    trade.TakerSupportMechanism,
+			TemporaryGoods:           trade.TemporaryGoods,
+			ImportDeclaration:        trade.ImportDeclaration,
+			ExportRegistration:       trade.ExportRegistration,
+			ShareWithMDIC:            trade.ShareWithMDIC,
+		}
+	}
+
+	// Add construction site if present
+	if construction := req.Service.Construction; construction != nil {
+		config.Service.Construction = &xmlbuilder.DPSConstruction{
+			Code:                 construction.Code,
+			PropertyRegistration: construction.PropertyRegistration,
+			Address:              newAddressConfig(construction.Address),
+		}
+	}
+
+	// Add event if present
+	if event := req.Service.Event; event != nil {
+		config.Service.Event = &xmlbuilder.DPSEvent{
+			Name:      event.Name,
+			StartDate: event.StartDate,
+			EndDate:   event.EndDate,
+			ID:        event.ID,
+			Address:   newAddressConfig(event.Address),
+		}
+	}
+
 	// Add taker if present
 	if req.Taker != nil {
 		config.Taker = &xmlbuilder.DPSTaker{
-			CNPJ: req.Taker.CNPJ,
-			CPF:  req.Taker.CPF,
-			NIF:  req.Taker.NIF,
-			Name: req.Taker.Name,
+			CNPJ:    req.Taker.CNPJ,
+			CPF:     req.Taker.CPF,
+			NIF:     req.Taker.NIF,
+			Name:    req.Taker.Name,
+			Phone:   req.Taker.Phone,
+			Email:   req.Taker.Email,
+			Address: newAddressConfig(req.Taker.Address),
+		}
+	}
+
+	// Add intermediary if present
+	if req.Intermediary != nil {
+		config.Intermediary = &xmlbuilder.DPSIntermediary{
+			CNPJ:    req.Intermediary.CNPJ,
+			CPF:     req.Intermediary.CPF,
+			NIF:     req.Intermediary.NIF,
+			Name:    req.Intermediary.Name,
+			Phone:   req.Intermediary.Phone,
+			Email:   req.Intermediary.Email,
+			Address: newAddressConfig(req.Intermediary.Address),
 		}
 	}
 
@@ -280,6 +655,185 @@ func (p *EmissionProcessor) buildDPSXML(req *mongodb.EmissionRequest) (*xmlbuild
 	return builder.Build()
 }
 
+// newFederalTaxInput converts the stored federal tax rates into calculator input.
+// Returns nil when no federal taxes were stored.
+func newFederalTaxInput(taxes *mongodb.FederalTaxesData) *emission.FederalTaxInput {
+	if taxes == nil {
+		return nil
+	}
+
+	input := &emission.FederalTaxInput{
+		CPRate:   taxes.CPRate,
+		IRRFRate: taxes.IRRFRate,
+		CSLLRate: taxes.CSLLRate,
+	}
+	if pc := taxes.PisCofins; pc != nil {
+		input.PisCofinsTaxBase = pc.TaxBase
+		input.PISRate = pc.PISRate
+		input.COFINSRate = pc.COFINSRate
+		input.PisCofinsWithheld = pc.Withheld
+	}
+
+	return input
+}
+
+// newDPSTotalTaxes converts the recorded approximate tax burden for the DPS builder.
+// Returns nil, declaring the tax burden as not informed, when none was found.
+func newDPSTotalTaxes(totalTaxes *mongodb.TotalTaxesData) *xmlbuilder.DPSTotalTaxes {
+	if totalTaxes == nil {
+		return nil
+	}
+
+	switch totalTaxes.Source {
+	case emission.TotalTaxesSourceSimplesRate:
+		return &xmlbuilder.DPSTotalTaxes{SimplesRate: totalTaxes.SimplesRate}
+	case emission.TotalTaxesSourceTable:
+		return &xmlbuilder.DPSTotalTaxes{
+			Amounts: &xmlbuilder.DPSTaxBurden{
+				Federal:   totalTaxes.FederalAmount,
+				State:     totalTaxes.StateAmount,
+				Municipal: totalTaxes.MunicipalAmount,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// newDPSFederalTaxes combines the stored federal taxes with the calculated amounts for the DPS builder.
+// Returns nil when no federal taxes were stored.
+func newDPSFederalTaxes(taxes *mongodb.FederalTaxesData, calculation *emission.CalculationResult) *xmlbuilder.DPSFederalTaxes {
+	if taxes == nil {
+		return nil
+	}
+
+	federal := &xmlbuilder.DPSFederalTaxes{
+		CPWithheld:   calculation.CPWithheld,
+		IRRFWithheld: calculation.IRRFWithheld,
+		CSLLWithheld: calculation.CSLLWithheld,
+	}
+	if pc := taxes.PisCofins; pc != nil {
+		federal.PisCofins = &xmlbuilder.DPSPisCofins{
+			CST:          pc.CST,
+			PISRate:      pc.PISRate,
+			COFINSRate:   pc.COFINSRate,
+			PISAmount:    calculation.PISAmount,
+			COFINSAmount: calculation.COFINSAmount,
+			Withheld:     pc.Withheld,
+		}
+		// Only inform the base when the taxes are charged or it was given explicitly
+		if pc.TaxBase > 0 || pc.PISRate > 0 || pc.COFINSRate > 0 {
+			federal.PisCofins.TaxBase = calculation.PisCofinsTaxBase
+		}
+	}
+
+	return federal
+}
+
+// newAddressConfig converts a stored provider, taker, intermediary, construction site
+// or event address for the DPS builder.
+// Returns nil when no address was stored.
+func newAddressConfig(addr *mongodb.AddressData) *xmlbuilder.AddressConfig {
+	if addr == nil {
+		return nil
+	}
+
+	return &xmlbuilder.AddressConfig{
+		Street:           addr.Street,
+		Number:           addr.Number,
+		Complement:       addr.Complement,
+		Neighborhood:     addr.Neighborhood,
+		MunicipalityCode: addr.MunicipalityCode,
+		State:            addr.State,
+		PostalCode:       addr.PostalCode,
+		City:             addr.City,
+		Region:           addr.Region,
+		CountryCode:      addr.CountryCode,
+	}
+}
+
+// recordSubstitution records the cancellation by substitution (e105102) of the NFS-e
+// replaced by a successfully emitted substitute. The government API registers that
+// event itself when it accepts a DPS carrying subst, so it is only looked up here to
+// link it to the substitute emission and to the lifecycle of the replaced NFS-e.
+// Failures are only logged: the substitute NFS-e is already emitted.
+func (p *EmissionProcessor) recordSubstitution(ctx context.Context, req *mongodb.EmissionRequest, result *mongodb.EmissionResult) {
+	replacedKey := req.Substitution.ReplacedAccessKey
+
+	cancellation := LifecycleEvent{
+		EventType:  event.TypeCancellationBySubstitution,
+		Source:     event.LifecycleSourceDiscovered,
+		ReplacedBy: result.NFSeAccessKey,
+	}
+
+	events, err := p.sefinClient.QueryEventsByType(ctx, replacedKey, event.TypeCancellationBySubstitution, nil)
+	switch {
+	case err != nil:
+		log.Printf("Warning: failed to query the cancellation by substitution of NFS-e %s: %v", replacedKey, err)
+	case len(events.Events) == 0:
+		log.Printf("Warning: cancellation by substitution of NFS-e %s not found yet", replacedKey)
+	default:
+		found := NewDiscoveredLifecycleEvent(events.Events[len(events.Events)-1])
+		cancellation.Sequence = found.Sequence
+		cancellation.OccurredAt = found.OccurredAt
+	}
+
+	// Without the event, the substitute's emission dates the cancellation
+	cancelledAt := cancellation.OccurredAt
+	if cancelledAt.IsZero() {
+		cancelledAt = time.Now().UTC()
+	}
+
+	if err := p.emissionRepo.SetSubstitutionCancellation(ctx, req.RequestID, cancellation.Sequence, cancelledAt); err != nil {
+		log.Printf("Error recording substitution cancellation: %v", err)
+	}
+	req.Substitution.CancellationSequence = cancellation.Sequence
+	req.Substitution.CancelledAt = cancelledAt
+
+	// Only tracked when the replaced NFS-e was also emitted through this API
+	if _, err := p.lifecycle.Apply(ctx, replacedKey, cancellation); err != nil {
+		log.Printf("Warning: failed to update lifecycle of replaced NFS-e %s: %v", replacedKey, err)
+	}
+
+	log.Printf("NFS-e %s cancelled by substitution (replaced by %s)", replacedKey, result.NFSeAccessKey)
+}
+
+// releaseCertificate clears the stored credentials of a request whose DPS will not
+// be signed, since the emission failed before signing.
+func (p *EmissionProcessor) releaseCertificate(ctx context.Context, req *mongodb.EmissionRequest) {
+	if req.Certificate == nil || req.Certificate.PFXBase64 == "" {
+		return
+	}
+
+	if err := p.emissionRepo.ClearCredentials(ctx, req.RequestID); err != nil {
+		log.Printf("Warning: failed to clear certificate credentials: %v", err)
+	}
+}
+
+// releaseOnFinalAttempt clears the stored credentials when the task fails on its
+// last attempt: asynq archives it, so no later attempt will sign the DPS.
+func (p *EmissionProcessor) releaseOnFinalAttempt(ctx context.Context, req *mongodb.EmissionRequest) {
+	if !isFinalAttempt(ctx) {
+		return
+	}
+
+	log.Printf("Emission request %s failed on its last attempt, releasing the certificate", req.RequestID)
+	p.releaseCertificate(ctx, req)
+}
+
+// isFinalAttempt reports whether the running task will not be retried if it fails.
+func isFinalAttempt(ctx context.Context) bool {
+	retried, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return false
+	}
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	if !ok {
+		return false
+	}
+	return retried >= maxRetry
+}
+
 // sendWebhook sends a webhook notification for the emission result.
 func (p *EmissionProcessor) sendWebhook(ctx context.Context, req *mongodb.EmissionRequest, result *mongodb.EmissionResult, rejection *mongodb.RejectionInfo) {
 	// Skip if no webhook URL
@@ -289,29 +843,34 @@ func (p *EmissionProcessor) sendWebhook(ctx context.Context, req *mongodb.Emissi
 	}
 
 	// Determine event type and status
-	var event, status string
+	var webhookEvent, status string
 	if result != nil {
-		event = emission.WebhookEventEmissionCompleted
+		webhookEvent = emission.WebhookEventEmissionCompleted
 		status = emission.StatusSuccess
 	} else {
-		event = emission.WebhookEventEmissionFailed
+		webhookEvent = emission.WebhookEventEmissionFailed
 		status = emission.StatusFailed
 	}
 
 	// Build payload
 	payload := emission.WebhookPayload{
-		Event:     event,
+		Event:     webhookEvent,
 		RequestID: req.RequestID,
 		Timestamp: time.Now().UTC(),
 		Status:    status,
 	}
 
+	if req.Substitution != nil {
+		payload.Substitution = NewSubstitutionDTO(req.Substitution)
+	}
+
 	if result != nil {
 		payload.Result = &emission.EmissionResultDTO{
 			NFSeAccessKey: result.NFSeAccessKey,
 			NFSeNumber:    result.NFSeNumber,
 			NFSeXMLURL:    result.NFSeXMLURL,
 		}
+		payload.Lifecycle = &emission.LifecycleDTO{Status: event.LifecycleActive}
 	}
 
 	if rejection != nil {
@@ -367,6 +926,24 @@ func (p *EmissionProcessor) sendWebhook(ctx context.Context, req *mongodb.Emissi
 	}
 }
 
+// NewSubstitutionDTO converts stored substitution data into its API representation.
+// The cancellation is reported as successful once the substitute NFS-e has been
+// emitted, since the government API cancels the replaced NFS-e at the same time.
+func NewSubstitutionDTO(sub *mongodb.SubstitutionData) *emission.SubstitutionDTO {
+	dto := &emission.SubstitutionDTO{
+		ReplacedAccessKey:    sub.ReplacedAccessKey,
+		ReasonCode:           sub.ReasonCode,
+		Reason:               sub.Reason,
+		CancellationSequence: sub.CancellationSequence,
+	}
+	if !sub.CancelledAt.IsZero() {
+		cancelledAt := sub.CancelledAt
+		dto.CancellationStatus = emission.StatusSuccess
+		dto.CancelledAt = &cancelledAt
+	}
+	return dto
+}
+
 // WebhookProcessor handles webhook delivery tasks.
 type WebhookProcessor struct {
 	webhookRepo   *mongodb.WebhookRepository
@@ -0,0 +1,122 @@
+package nbs
+
+// codes are the 9-digit codes of the NBS 2.0 list (LISTA.NBS_v2.0 sheet of
+// ANEXO_B-NBS2-LISTA_SERVICO_NACIONAL-SNNFSe-v1.00-20251210.xlsx in docs/anexos).
+// Only the most detailed level (subitems) is accepted in the DPS (cNBS).
+var codes = map[string]struct{}{
+	"101011100": {}, "101011200": {}, "101012100": {}, "101012200": {}, "101012900": {}, "101013000": {}, "101021100": {}, "101021200": {},
+	"101021300": {}, "101022000": {}, "101023100": {}, "101023200": {}, "101023300": {}, "101023400": {}, "101023510": {}, "101023520": {},
+	"101023530": {}, "101024110": {}, "101024120": {}, "101024190": {}, "101024210": {}, "101024220": {}, "101025100": {}, "101025210": {},
+	"101025220": {}, "101025310": {}, "101025320": {}, "101026100": {}, "101026900": {}, "101027000": {}, "101028000": {}, "101029000": {},
+	"101031000": {}, "101032000": {}, "101033000": {}, "101034100": {}, "101034200": {}, "101040000": {}, "101051100": {}, "101051200": {},
+	"101052100": {}, "101052200": {}, "101053000": {}, "101054000": {}, "101055000": {}, "101056000": {}, "101057000": {}, "101059000": {},
+	"101061100": {}, "101061200": {}, "101061300": {}, "101061400": {}, "101061900": {}, "101062100": {}, "101062200": {}, "101063100": {},
+	"101063200": {}, "101064000": {}, "101065000": {}, "101066000": {}, "101069000": {}, "101071000": {}, "101072000": {}, "101073000": {},
+	"101074000": {}, "101075000": {}, "101076000": {}, "101079000": {}, "102010000": {}, "102020000": {}, "102030000": {}, "102040000": {},
+	"102050000": {}, "103011000": {}, "103012100": {}, "103012200": {}, "103012900": {}, "103013100": {}, "103013200": {}, "103013900": {},
+	"103019000": {}, "103020000": {}, "103031100": {}, "103031200": {}, "103031300": {}, "103031400": {}, "103032000": {}, "103039000": {},
+	"103041000": {}, "103042000": {}, "103049000": {}, "104011111": {}, "104011119": {}, "104011120": {}, "104011210": {}, "104011220": {},
+	"104011290": {}, "104011300": {}, "104011400": {}, "104011510": {}, "104011520": {}, "104011610": {}, "104011620": {}, "104011690": {},
+	"104011710": {}, "104011720": {}, "104011790": {}, "104011900": {}, "104012110": {}, "104012120": {}, "104012190": {}, "104012200": {},
+	"104012300": {}, "104012900": {}, "104013000": {}, "104014100": {}, "104014200": {}, "104014300": {}, "104014900": {}, "104019000": {},
+	"104021110": {}, "104021190": {}, "104021200": {}, "104021310": {}, "104021320": {}, "104021400": {}, "104021900": {}, "104022110": {},
+	"104022120": {}, "104022190": {}, "104022200": {}, "104022300": {}, "104023100": {}, "104023200": {}, "104023300": {}, "104023900": {},
+	"104029000": {}, "104031110": {}, "104031190": {}, "104031200": {}, "104031310": {}, "104031390": {}, "104031900": {}, "104032110": {},
+	"104032120": {}, "104032190": {}, "104032200": {}, "104032300": {}, "104032400": {}, "104033100": {}, "104033200": {}, "104033300": {},
+	"104033900": {}, "104039000": {}, "104041000": {}, "104042000": {}, "104043000": {}, "104050000": {}, "105011110": {}, "105011120": {},
+	"105011130": {}, "105011210": {}, "105011220": {}, "105011230": {}, "105011310": {}, "105011320": {}, "105011410": {}, "105011420": {},
+	"105011430": {}, "105011440": {}, "105011451": {}, "105011452": {}, "105011459": {}, "105011500": {}, "105011900": {}, "105012110": {},
+	"105012120": {}, "105012130": {}, "105012210": {}, "105012220": {}, "105012230": {}, "105012310": {}, "105012320": {}, "105012410": {},
+	"105012421": {}, "105012422": {}, "105012429": {}, "105012500": {}, "105012900": {}, "105013100": {}, "105013200": {}, "105013900": {},
+	"105021110": {}, "105021120": {}, "105021130": {}, "105021210": {}, "105021220": {}, "105021230": {}, "105021310": {}, "105021320": {},
+	"105021410": {}, "105021420": {}, "105021430": {}, "105021440": {}, "105021451": {}, "105021452": {}, "105021459": {}, "105021490": {},
+	"105021900": {}, "105022110": {}, "105022120": {}, "105022130": {}, "105022210": {}, "105022220": {}, "105022230": {}, "105022310": {},
+	"105022320": {}, "105022410": {}, "105022420": {}, "105022430": {}, "105022440": {}, "105022451": {}, "105022452": {}, "105022459": {},
+	"105022900": {}, "105023110": {}, "105023120": {}, "105023130": {}, "105023210": {}, "105023220": {}, "105023230": {}, "105023310": {},
+	"105023320": {}, "105023410": {}, "105023420": {}, "105023430": {}, "105023440": {}, "105023451": {}, "105023452": {}, "105023459": {},
+	"105023900": {}, "105031100": {}, "105031200": {}, "105032100": {}, "105032200": {}, "105032300": {}, "105032400": {}, "105032500": {},
+	"105032600": {}, "105032700": {}, "105032800": {}, "105032900": {}, "105039000": {}, "105041100": {}, "105041200": {}, "105041300": {},
+	"105042100": {}, "105042200": {}, "105042300": {}, "105043100": {}, "105043200": {}, "105044100": {}, "105044200": {}, "105044300": {},
+	"105044400": {}, "105044510": {}, "105044520": {}, "105044590": {}, "105044900": {}, "105049000": {}, "105051000": {}, "105052000": {},
+	"105053000": {}, "105060000": {}, "106011000": {}, "106019000": {}, "106021000": {}, "106022100": {}, "106022200": {}, "106022300": {},
+	"106022900": {}, "106023100": {}, "106023200": {}, "106023300": {}, "106029000": {}, "106030000": {}, "106041000": {}, "106042100": {},
+	"106042200": {}, "106043000": {}, "106044000": {}, "106049000": {}, "106051000": {}, "106052000": {}, "106053000": {}, "106054000": {},
+	"106059000": {}, "106061100": {}, "106061200": {}, "106061900": {}, "106062000": {}, "106070000": {}, "106081000": {}, "106082000": {},
+	"106083000": {}, "106084000": {}, "106089000": {}, "106090000": {}, "107010000": {}, "107020000": {}, "107030000": {}, "108011000": {},
+	"108012000": {}, "108021000": {}, "108022000": {}, "108023000": {}, "108030000": {}, "109011000": {}, "109012100": {}, "109012200": {},
+	"109012900": {}, "109013100": {}, "109013200": {}, "109013300": {}, "109013400": {}, "109013500": {}, "109013600": {}, "109013900": {},
+	"109014000": {}, "109015111": {}, "109015112": {}, "109015113": {}, "109015114": {}, "109015115": {}, "109015116": {}, "109015117": {},
+	"109015121": {}, "109015122": {}, "109015123": {}, "109015124": {}, "109015125": {}, "109015129": {}, "109015210": {}, "109015220": {},
+	"109015230": {}, "109015240": {}, "109015250": {}, "109015290": {}, "109019000": {}, "109021000": {}, "109022000": {}, "109023000": {},
+	"109024000": {}, "109029000": {}, "109031100": {}, "109031200": {}, "109031300": {}, "109032100": {}, "109032200": {}, "109033100": {},
+	"109033200": {}, "109033300": {}, "109033400": {}, "109033500": {}, "109033600": {}, "109033700": {}, "109033800": {}, "109033900": {},
+	"109041000": {}, "109042100": {}, "109042200": {}, "109043100": {}, "109043200": {}, "109043300": {}, "109043400": {}, "109043500": {},
+	"109043600": {}, "109043700": {}, "109043900": {}, "109051100": {}, "109051200": {}, "109051300": {}, "109052100": {}, "109052200": {},
+	"109052300": {}, "109053000": {}, "109054000": {}, "109055000": {}, "109056000": {}, "109057000": {}, "109058000": {}, "109059000": {},
+	"109061100": {}, "109061200": {}, "109062000": {}, "109063000": {}, "109064000": {}, "109069000": {}, "109070000": {}, "109080000": {},
+	"109091000": {}, "109092000": {}, "109101000": {}, "109102000": {}, "109109000": {}, "109110000": {}, "110011100": {}, "110011210": {},
+	"110011290": {}, "110012100": {}, "110012200": {}, "110013000": {}, "110014000": {}, "110015000": {}, "110019000": {}, "110021000": {},
+	"110022000": {}, "111011100": {}, "111011200": {}, "111011300": {}, "111011400": {}, "111011500": {}, "111011600": {}, "111011700": {},
+	"111012000": {}, "111013000": {}, "111014000": {}, "111015000": {}, "111016000": {}, "111019000": {}, "111021000": {}, "111022000": {},
+	"111023000": {}, "111024000": {}, "111025000": {}, "111026000": {}, "111029000": {}, "111031000": {}, "111032100": {}, "111032200": {},
+	"111032300": {}, "111032900": {}, "111033100": {}, "111033200": {}, "111033300": {}, "111033400": {}, "111033500": {}, "111033610": {},
+	"111033620": {}, "111033690": {}, "111033900": {}, "111034100": {}, "111034200": {}, "111034300": {}, "111035000": {}, "111039000": {},
+	"111041000": {}, "111042000": {}, "111043000": {}, "111049000": {}, "111051000": {}, "111052000": {}, "111053000": {}, "111054100": {},
+	"111054200": {}, "111055100": {}, "111055900": {}, "111056000": {}, "111057000": {}, "111059000": {}, "111061000": {}, "111062000": {},
+	"111063100": {}, "111063200": {}, "111063300": {}, "111063400": {}, "111063500": {}, "111063610": {}, "111063620": {}, "111063690": {},
+	"111063900": {}, "111064100": {}, "111064200": {}, "111064300": {}, "111065000": {}, "111069000": {}, "111071000": {}, "111072000": {},
+	"111073100": {}, "111073200": {}, "111073300": {}, "111073900": {}, "111074000": {}, "111075000": {}, "111079000": {}, "111081000": {},
+	"111082000": {}, "111083000": {}, "111089000": {}, "111091000": {}, "111092000": {}, "111093000": {}, "111099000": {}, "111100000": {},
+	"112011100": {}, "112011200": {}, "112011900": {}, "112012000": {}, "112013100": {}, "112013200": {}, "112013300": {}, "112013400": {},
+	"112013900": {}, "112014000": {}, "112015000": {}, "112019000": {}, "112021000": {}, "112022000": {}, "112023000": {}, "112024000": {},
+	"112029000": {}, "112030000": {}, "113011000": {}, "113012000": {}, "113013000": {}, "113014000": {}, "113019000": {}, "113021100": {},
+	"113021900": {}, "113022100": {}, "113022200": {}, "113022300": {}, "113031000": {}, "113032000": {}, "113040000": {}, "114011100": {},
+	"114011200": {}, "114011300": {}, "114011400": {}, "114011500": {}, "114011600": {}, "114011700": {}, "114011800": {}, "114011900": {},
+	"114012100": {}, "114012200": {}, "114012900": {}, "114013100": {}, "114013200": {}, "114013900": {}, "114021100": {}, "114021200": {},
+	"114021300": {}, "114021400": {}, "114021500": {}, "114022100": {}, "114022200": {}, "114023100": {}, "114023200": {}, "114029000": {},
+	"114031000": {}, "114032110": {}, "114032120": {}, "114032211": {}, "114032212": {}, "114032213": {}, "114032214": {}, "114032221": {},
+	"114032222": {}, "114032223": {}, "114032290": {}, "114032300": {}, "114032400": {}, "114032500": {}, "114032600": {}, "114032700": {},
+	"114032900": {}, "114033000": {}, "114039000": {}, "114041100": {}, "114041200": {}, "114041300": {}, "114041400": {}, "114041900": {},
+	"114042100": {}, "114042200": {}, "114043000": {}, "114044100": {}, "114044200": {}, "114044300": {}, "114044400": {}, "114044900": {},
+	"114051100": {}, "114051200": {}, "114052100": {}, "114052200": {}, "114053000": {}, "114054000": {}, "114055000": {}, "114056000": {},
+	"114059000": {}, "114061100": {}, "114061200": {}, "114061900": {}, "114062000": {}, "114063100": {}, "114063200": {}, "114063300": {},
+	"114063400": {}, "114063900": {}, "114070000": {}, "114081100": {}, "114081200": {}, "114081300": {}, "114081400": {}, "114081500": {},
+	"114081900": {}, "114082000": {}, "114091100": {}, "114091200": {}, "114092100": {}, "114092200": {}, "114092300": {}, "114092400": {},
+	"114092500": {}, "114092900": {}, "114093000": {}, "114099000": {}, "114101000": {}, "114109000": {}, "114110000": {}, "114120000": {},
+	"114130000": {}, "114140000": {}, "114150000": {}, "115011000": {}, "115012000": {}, "115013000": {}, "115021000": {}, "115022000": {},
+	"115023000": {}, "115024000": {}, "115025000": {}, "115029000": {}, "115030000": {}, "115040000": {}, "115050000": {}, "115061000": {},
+	"115062100": {}, "115062200": {}, "115062300": {}, "115062900": {}, "115069000": {}, "115071000": {}, "115072000": {}, "115079000": {},
+	"115080000": {}, "115090000": {}, "115100000": {}, "117011100": {}, "117011200": {}, "117011900": {}, "117012100": {}, "117012900": {},
+	"117013100": {}, "117013200": {}, "117013300": {}, "117013400": {}, "117014000": {}, "117015100": {}, "117015200": {}, "117019000": {},
+	"117021000": {}, "117022100": {}, "117022200": {}, "117029000": {}, "117031000": {}, "117032100": {}, "117032200": {}, "117033100": {},
+	"117033200": {}, "117039100": {}, "117039200": {}, "117039900": {}, "117041000": {}, "117042000": {}, "117051000": {}, "117052000": {},
+	"117061100": {}, "117061200": {}, "117062100": {}, "117062200": {}, "117062300": {}, "117062400": {}, "117069000": {}, "118011100": {},
+	"118011200": {}, "118012100": {}, "118012200": {}, "118012900": {}, "118021000": {}, "118022000": {}, "118023000": {}, "118024000": {},
+	"118025000": {}, "118029000": {}, "118031000": {}, "118032100": {}, "118032200": {}, "118032900": {}, "118040000": {}, "118051100": {},
+	"118051200": {}, "118051300": {}, "118051400": {}, "118051900": {}, "118052100": {}, "118052200": {}, "118052300": {}, "118052400": {},
+	"118053100": {}, "118053200": {}, "118053900": {}, "118054000": {}, "118055000": {}, "118056100": {}, "118056200": {}, "118061000": {},
+	"118062000": {}, "118063100": {}, "118063900": {}, "118064000": {}, "118065100": {}, "118065200": {}, "118065300": {}, "118065900": {},
+	"118066100": {}, "118066200": {}, "118066300": {}, "118067000": {}, "118068100": {}, "118068200": {}, "118068300": {}, "118069000": {},
+	"119011000": {}, "119012000": {}, "119013000": {}, "119014000": {}, "119015000": {}, "119021000": {}, "119029000": {}, "119031100": {},
+	"119031200": {}, "119032000": {}, "119033000": {}, "119034000": {}, "119035000": {}, "120011000": {}, "120012000": {}, "120013110": {},
+	"120013120": {}, "120013200": {}, "120013300": {}, "120013410": {}, "120013420": {}, "120013430": {}, "120013500": {}, "120013900": {},
+	"120014000": {}, "120015000": {}, "120016000": {}, "120017000": {}, "120018100": {}, "120018200": {}, "120018300": {}, "120018900": {},
+	"120021000": {}, "120022000": {}, "120023000": {}, "120024000": {}, "120029000": {}, "120031000": {}, "120032110": {}, "120032190": {},
+	"120032200": {}, "120032300": {}, "120032400": {}, "120032510": {}, "120032520": {}, "120032610": {}, "120032690": {}, "120032900": {},
+	"121011000": {}, "121012100": {}, "121012200": {}, "121012300": {}, "121013000": {}, "122011100": {}, "122011200": {}, "122011900": {},
+	"122012000": {}, "122013000": {}, "122020000": {}, "122031000": {}, "122032000": {}, "122041000": {}, "122042000": {}, "122043000": {},
+	"122044000": {}, "122051100": {}, "122051200": {}, "122051300": {}, "122051400": {}, "122051900": {}, "122052000": {}, "123011100": {},
+	"123011200": {}, "123011300": {}, "123011400": {}, "123011500": {}, "123011900": {}, "123012100": {}, "123012200": {}, "123012300": {},
+	"123019100": {}, "123019200": {}, "123019300": {}, "123019400": {}, "123019500": {}, "123019600": {}, "123019700": {}, "123019800": {},
+	"123019900": {}, "123021000": {}, "123022100": {}, "123022200": {}, "123022300": {}, "123030000": {}, "123041100": {}, "123041200": {},
+	"123041900": {}, "123042000": {}, "123049000": {}, "124010000": {}, "124021000": {}, "124022000": {}, "124031100": {}, "124031200": {},
+	"124031900": {}, "124032100": {}, "124032200": {}, "124033100": {}, "124033200": {}, "124041100": {}, "124041200": {}, "124041300": {},
+	"124041900": {}, "124042100": {}, "124042200": {}, "124043100": {}, "124043200": {}, "124043300": {}, "124043900": {}, "124051100": {},
+	"124051200": {}, "124051300": {}, "124051400": {}, "124052000": {}, "124059000": {}, "124061000": {}, "124069000": {}, "124070000": {},
+	"125011100": {}, "125011200": {}, "125012100": {}, "125012200": {}, "125013100": {}, "125013200": {}, "125013300": {}, "125013400": {},
+	"125013500": {}, "125013600": {}, "125013700": {}, "125013900": {}, "125014000": {}, "125015000": {}, "125019000": {}, "125021000": {},
+	"125022000": {}, "125023000": {}, "125029000": {}, "125031000": {}, "125032000": {}, "125041100": {}, "125041200": {}, "125042100": {},
+	"125042200": {}, "125051000": {}, "125052000": {}, "125059000": {}, "125060000": {}, "125071000": {}, "125079000": {}, "125080000": {},
+	"126011000": {}, "126012000": {}, "126013000": {}, "126014000": {}, "126019000": {}, "126021000": {}, "126022000": {}, "126023000": {},
+	"126029000": {}, "126030000": {}, "126040000": {}, "126050000": {}, "126060000": {}, "999999999": {},
+}
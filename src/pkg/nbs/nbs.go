@@ -0,0 +1,34 @@
+// Package nbs provides validation of NBS codes (Nomenclatura Brasileira de
+// Serviços, Intangíveis e Outras Operações que Produzam Variações no Patrimônio),
+// informed in the DPS service block (cNBS) according to the national list.
+package nbs
+
+import (
+	"regexp"
+)
+
+// NBS validation constants.
+const (
+	// CodeLength is the length of an NBS code without formatting.
+	CodeLength = 9
+)
+
+// nonDigitRegex matches the formatting characters of an NBS code.
+var nonDigitRegex = regexp.MustCompile(`\D`)
+
+// Clean removes all non-digit characters from an NBS code,
+// so the formatted "1.0101.11.00" becomes "101011100".
+func Clean(code string) string {
+	return nonDigitRegex.ReplaceAllString(code, "")
+}
+
+// IsValid reports whether the code is in the national NBS list.
+// It accepts both formatted (X.XXXX.XX.XX) and unformatted (XXXXXXXXX) inputs.
+func IsValid(code string) bool {
+	code = Clean(code)
+	if len(code) != CodeLength {
+		return false
+	}
+	_, ok := codes[code]
+	return ok
+}
@@ -0,0 +1,23 @@
+package nbs
+
+import "testing"
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"101011100", true},    // residential buildings of one and two floors
+		{"1.0101.11.00", true}, // formatted
+		{"1.2606.00.00", true}, // personal services not elsewhere classified
+		{"1.0101.1", false},    // a position, not a subitem
+		{"101011199", false},   // not in the list
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValid(tt.code); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -32,10 +32,18 @@ type AddressConfig struct {
 	// Required for national addresses.
 	State string
 
-	// PostalCode is the 8-digit CEP without formatting.
-	// Required for national addresses.
+	// PostalCode is the 8-digit CEP without formatting for national addresses,
+	// or the foreign postal code (cEndPost) for foreign addresses.
 	PostalCode string
 
+	// City is the city name (xCidade).
+	// Required for foreign addresses.
+	City string
+
+	// Region is the state, province or region (xEstProvReg).
+	// Required for foreign addresses.
+	Region string
+
 	// CountryCode is the ISO 3166-1 alpha-2 country code (cPais).
 	// Defaults to "BR" for national addresses.
 	CountryCode string
@@ -62,19 +70,20 @@ func BuildAddressXML(config *AddressConfig) (*etree.Element, error) {
 
 // BuildNationalAddressXML generates the <end> element for a Brazilian address.
 // National addresses require: street, number, neighborhood, municipality code,
-// state (UF), and postal code (CEP).
+// state (UF), and postal code (CEP). The state is implied by the municipality
+// code and is not part of the XML.
 //
 // XML structure:
 //
 //	<end>
+//	  <endNac>
+//	    <cMun>3550308</cMun>
+//	    <CEP>01310100</CEP>
+//	  </endNac>
 //	  <xLgr>Rua Example</xLgr>
 //	  <nro>123</nro>
 //	  <xCpl>Sala 101</xCpl>
 //	  <xBairro>Centro</xBairro>
-//	  <cMun>3550308</cMun>
-//	  <UF>SP</UF>
-//	  <CEP>01310100</CEP>
-//	  <cPais>BR</cPais>
 //	</end>
 func BuildNationalAddressXML(config *AddressConfig) (*etree.Element, error) {
 	if config == nil {
@@ -88,50 +97,36 @@ func BuildNationalAddressXML(config *AddressConfig) (*etree.Element, error) {
 
 	end := etree.NewElement("end")
 
-	// xLgr - Street (required)
-	end.CreateElement("xLgr").SetText(sanitizeXMLText(config.Street))
-
-	// nro - Number (required)
-	end.CreateElement("nro").SetText(sanitizeXMLText(config.Number))
-
-	// xCpl - Complement (optional)
-	if config.Complement != "" {
-		end.CreateElement("xCpl").SetText(sanitizeXMLText(config.Complement))
-	}
-
-	// xBairro - Neighborhood (required)
-	end.CreateElement("xBairro").SetText(sanitizeXMLText(config.Neighborhood))
+	// endNac - National address identification
+	endNac := end.CreateElement("endNac")
 
 	// cMun - Municipality IBGE code (required for national)
-	end.CreateElement("cMun").SetText(config.MunicipalityCode)
-
-	// UF - State (required for national)
-	end.CreateElement("UF").SetText(strings.ToUpper(config.State))
+	endNac.CreateElement("cMun").SetText(config.MunicipalityCode)
 
 	// CEP - Postal code (required for national)
-	end.CreateElement("CEP").SetText(cleanPostalCode(config.PostalCode))
+	endNac.CreateElement("CEP").SetText(cleanPostalCode(config.PostalCode))
 
-	// cPais - Country code (defaults to BR)
-	countryCode := config.CountryCode
-	if countryCode == "" {
-		countryCode = "BR"
-	}
-	end.CreateElement("cPais").SetText(strings.ToUpper(countryCode))
+	addAddressStreetElements(end, config)
 
 	return end, nil
 }
 
 // BuildForeignAddressXML generates the <end> element for a foreign (non-Brazilian) address.
-// Foreign addresses have minimal required fields: street, number, neighborhood, and country code.
-// Municipality, state, and CEP are omitted.
+// Foreign addresses require: street, number, neighborhood, country code, postal code,
+// city, and state/province/region. Municipality and UF are omitted.
 //
 // XML structure:
 //
 //	<end>
+//	  <endExt>
+//	    <cPais>ES</cPais>
+//	    <cEndPost>28001</cEndPost>
+//	    <xCidade>Madrid</xCidade>
+//	    <xEstProvReg>Madrid</xEstProvReg>
+//	  </endExt>
 //	  <xLgr>Foreign Street</xLgr>
 //	  <nro>456</nro>
 //	  <xBairro>Foreign District</xBairro>
-//	  <cPais>ES</cPais>
 //	</end>
 func BuildForeignAddressXML(config *AddressConfig) (*etree.Element, error) {
 	if config == nil {
@@ -145,6 +140,29 @@ func BuildForeignAddressXML(config *AddressConfig) (*etree.Element, error) {
 
 	end := etree.NewElement("end")
 
+	// endExt - Foreign address identification
+	endExt := end.CreateElement("endExt")
+
+	// cPais - Country code (required, must NOT be BR)
+	endExt.CreateElement("cPais").SetText(strings.ToUpper(config.CountryCode))
+
+	// cEndPost - Foreign postal code (required)
+	endExt.CreateElement("cEndPost").SetText(sanitizeXMLText(config.PostalCode))
+
+	// xCidade - City (required)
+	endExt.CreateElement("xCidade").SetText(sanitizeXMLText(config.City))
+
+	// xEstProvReg - State, province or region (required)
+	endExt.CreateElement("xEstProvReg").SetText(sanitizeXMLText(config.Region))
+
+	addAddressStreetElements(end, config)
+
+	return end, nil
+}
+
+// addAddressStreetElements adds the elements common to national and foreign
+// addresses, which follow the endNac/endExt identification.
+func addAddressStreetElements(end *etree.Element, config *AddressConfig) {
 	// xLgr - Street (required)
 	end.CreateElement("xLgr").SetText(sanitizeXMLText(config.Street))
 
@@ -158,11 +176,6 @@ func BuildForeignAddressXML(config *AddressConfig) (*etree.Element, error) {
 
 	// xBairro - Neighborhood (required)
 	end.CreateElement("xBairro").SetText(sanitizeXMLText(config.Neighborhood))
-
-	// cPais - Country code (required, must NOT be BR)
-	end.CreateElement("cPais").SetText(strings.ToUpper(config.CountryCode))
-
-	return end, nil
 }
 
 // AddressFromDomain converts a domain Address to AddressConfig.
@@ -178,6 +191,8 @@ func AddressFromDomain(addr *domain.Address) *AddressConfig {
 		MunicipalityCode: addr.MunicipalityCode,
 		State:            addr.State,
 		PostalCode:       addr.PostalCode,
+		City:             addr.City,
+		Region:           addr.Region,
 		CountryCode:      addr.CountryCode,
 	}
 }
@@ -222,6 +237,15 @@ func validateForeignAddressConfig(config *AddressConfig) error {
 	if config.CountryCode == "BR" {
 		return fmt.Errorf("country code cannot be 'BR' for foreign address")
 	}
+	if config.PostalCode == "" {
+		return fmt.Errorf("postal code (cEndPost) is required for foreign address")
+	}
+	if config.City == "" {
+		return fmt.Errorf("city (xCidade) is required for foreign address")
+	}
+	if config.Region == "" {
+		return fmt.Errorf("state, province or region (xEstProvReg) is required for foreign address")
+	}
 	return nil
 }
 
@@ -0,0 +1,108 @@
+package xmlbuilder
+
+import "time"
+
+// DPSDeductionDocument contains a document supporting a deduction (docDedRed).
+// The document is identified by exactly one of NFSeKey, NFeKey, MunicipalNFSe,
+// PaperInvoice, FiscalDocumentNumber or DocumentNumber.
+type DPSDeductionDocument struct {
+	NFSeKey              string            // chNFSe - 50 digits
+	NFeKey               string            // chNFe - 44 digits
+	MunicipalNFSe        *DPSMunicipalNFSe // NFSeMun
+	PaperInvoice         *DPSPaperInvoice  // NFNFS
+	FiscalDocumentNumber string            // nDocFisc
+	DocumentNumber       string            // nDoc
+	Type                 int               // tpDedRed - 1 to 8 or 99
+	TypeDescription      string            // xDescOutDed (required for type 99)
+	IssueDate            time.Time         // dtEmiDoc
+	DeductibleValue      float64           // vDedutivelRedutivel
+	DeductionValue       float64           // vDeducaoReducao
+}
+
+// DPSMunicipalNFSe identifies an NFS-e issued in a municipal standard (NFSeMun).
+type DPSMunicipalNFSe struct {
+	MunicipalityCode string // cMunNFSeMun - 7-digit IBGE code
+	Number           string // nNFSeMun
+	VerificationCode string // cVerifNFSeMun
+}
+
+// DPSPaperInvoice identifies a non-electronic NF or NFS (NFNFS).
+type DPSPaperInvoice struct {
+	Number string // nNFS
+	Model  string // modNFS
+	Series string // serieNFS
+}
+
+// buildDeductionDocuments creates the deduction documents (documentos) XML element.
+// Returns nil when no documents are given.
+func buildDeductionDocuments(docs []DPSDeductionDocument) *documentosXML {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	documentos := &documentosXML{DocDedRed: make([]docDedRedXML, 0, len(docs))}
+	for _, doc := range docs {
+		item := docDedRedXML{
+			ChNFSe:              doc.NFSeKey,
+			ChNFe:               doc.NFeKey,
+			NDocFisc:            doc.FiscalDocumentNumber,
+			NDoc:                doc.DocumentNumber,
+			TpDedRed:            doc.Type,
+			XDescOutDed:         doc.TypeDescription,
+			DtEmiDoc:            formatDate(doc.IssueDate),
+			VDedutivelRedutivel: formatMoney(doc.DeductibleValue),
+			VDeducaoReducao:     formatMoney(doc.DeductionValue),
+		}
+		if doc.MunicipalNFSe != nil {
+			item.NFSeMun = &nfseMunXML{
+				CMunNFSeMun:   doc.MunicipalNFSe.MunicipalityCode,
+				NNFSeMun:      doc.MunicipalNFSe.Number,
+				CVerifNFSeMun: doc.MunicipalNFSe.VerificationCode,
+			}
+		}
+		if doc.PaperInvoice != nil {
+			item.NFNFS = &nfnfsXML{
+				NNFS:     doc.PaperInvoice.Number,
+				ModNFS:   doc.PaperInvoice.Model,
+				SerieNFS: doc.PaperInvoice.Series,
+			}
+		}
+		documentos.DocDedRed = append(documentos.DocDedRed, item)
+	}
+
+	return documentos
+}
+
+// documentosXML represents the list of deduction documents (TCListaDocDedRed).
+type documentosXML struct {
+	DocDedRed []docDedRedXML `xml:"docDedRed"`
+}
+
+// docDedRedXML represents a deduction document (TCDocDedRed).
+type docDedRedXML struct {
+	ChNFSe              string      `xml:"chNFSe,omitempty"`
+	ChNFe               string      `xml:"chNFe,omitempty"`
+	NFSeMun             *nfseMunXML `xml:"NFSeMun,omitempty"`
+	NFNFS               *nfnfsXML   `xml:"NFNFS,omitempty"`
+	NDocFisc            string      `xml:"nDocFisc,omitempty"`
+	NDoc                string      `xml:"nDoc,omitempty"`
+	TpDedRed            int         `xml:"tpDedRed"`
+	XDescOutDed         string      `xml:"xDescOutDed,omitempty"`
+	DtEmiDoc            string      `xml:"dtEmiDoc"`
+	VDedutivelRedutivel string      `xml:"vDedutivelRedutivel"`
+	VDeducaoReducao     string      `xml:"vDeducaoReducao"`
+}
+
+// nfseMunXML represents an NFS-e issued in a municipal standard (TCDocOutNFSe).
+type nfseMunXML struct {
+	CMunNFSeMun   string `xml:"cMunNFSeMun"`
+	NNFSeMun      string `xml:"nNFSeMun"`
+	CVerifNFSeMun string `xml:"cVerifNFSeMun"`
+}
+
+// nfnfsXML represents a non-electronic NF or NFS (TCDocNFNFS).
+type nfnfsXML struct {
+	NNFS     string `xml:"nNFS"`
+	ModNFS   string `xml:"modNFS"`
+	SerieNFS string `xml:"serieNFS"`
+}
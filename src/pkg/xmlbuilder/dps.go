@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/eduardo/nfse-nacional/pkg/nbs"
 )
 
 // DPSConfig contains all parameters needed to build a DPS XML document.
@@ -29,14 +32,21 @@ type DPSConfig struct {
 	// CompetenceDate is the date of service competence
 	CompetenceDate time.Time
 
-	// EmitterType: 1 = service provider, 2 = service taker
+	// EmitterType: 1 = service provider, 2 = service taker, 3 = intermediary.
+	// The DPS ID is formed with the emitter's CNPJ or CPF.
 	EmitterType int
 
+	// EmissionReason is why the taker or intermediary emits the DPS (cMotivoEmisTI), 1 to 4 (optional)
+	EmissionReason int
+
+	// RejectedAccessKey is the access key of the NFS-e rejected by the emitter (chNFSeRej, optional)
+	RejectedAccessKey string
+
 	// MunicipalityCode is the 7-digit IBGE code where the DPS is emitted
 	MunicipalityCode string
 
-	// Substitution: 1 = yes, 2 = no
-	Substitution int
+	// Substitution identifies the NFS-e replaced by this DPS (optional)
+	Substitution *DPSSubstitution
 
 	// Provider information
 	Provider DPSProvider
@@ -44,6 +54,9 @@ type DPSConfig struct {
 	// Taker information (optional)
 	Taker *DPSTaker
 
+	// Intermediary information (optional)
+	Intermediary *DPSIntermediary
+
 	// Service information
 	Service DPSService
 
@@ -51,12 +64,39 @@ type DPSConfig struct {
 	Values DPSValues
 }
 
+// DPSSubstitution contains the data of the NFS-e being replaced (subst group).
+type DPSSubstitution struct {
+	// ReplacedAccessKey is the access key of the NFS-e being replaced (chSubstda)
+	ReplacedAccessKey string
+
+	// ReasonCode is the substitution reason (cMotivo): "01"-"05" or "99"
+	ReasonCode string
+
+	// ReasonDescription is the optional free-text justification (xMotivo), 15-255 characters
+	ReasonDescription string
+}
+
 // DPSProvider contains provider information for the DPS.
 type DPSProvider struct {
-	CNPJ                  string
+	// Identification (mutually exclusive): CNPJ for companies, CPF for individuals
+	CNPJ string
+	CPF  string
+
 	Name                  string
-	TaxRegime             string // "mei" or "me_epp"
+	TaxRegime             string // "mei", "me_epp", "lucro_presumido", "lucro_real" or "autonomo"
 	MunicipalRegistration string
+
+	// SimplesCalculationRegime is how an ME/EPP provider calculates its taxes (regApTribSN):
+	// 1 = all within the Simples Nacional, 2 = ISS outside, 3 = federal taxes and ISS outside.
+	// Optional; ignored for other regimes.
+	SimplesCalculationRegime int
+
+	// SpecialRegime is the special taxation regime (regEspTrib), 0 (none) to 6.
+	SpecialRegime int
+
+	// Address is only emitted when the taker or intermediary emits the DPS;
+	// SEFIN rejects it when the provider is the emitter (E0128).
+	Address *AddressConfig
 }
 
 // DPSTaker contains taker information for the DPS.
@@ -75,16 +115,76 @@ type DPSTaker struct {
 	Address *AddressConfig
 }
 
+// DPSIntermediary contains intermediary information for the DPS.
+type DPSIntermediary struct {
+	// Identification (mutually exclusive)
+	CNPJ string
+	CPF  string
+	NIF  string
+
+	// Basic info
+	Name  string
+	Phone string
+	Email string
+
+	// Address (optional)
+	Address *AddressConfig
+}
+
 // DPSService contains service information for the DPS.
 type DPSService struct {
 	NationalCode     string // cTribNac - 6 digits
+	MunicipalTaxCode string // cTribMun - 3 digits (optional)
 	Description      string
+	NBSCode          string // cNBS - 9 digits of the NBS 2.0 list (optional)
 	MunicipalityCode string // IBGE code where service was provided
+	CountryCode      string // cPaisPrestacao - ISO code of the country where a service abroad was provided
+
+	// Foreign trade information of service exports and imports (optional)
+	ForeignTrade *DPSForeignTrade
+
+	// Construction site of civil construction services (optional)
+	Construction *DPSConstruction
+
+	// Event of event services (optional)
+	Event *DPSEvent
+}
+
+// DPSForeignTrade contains the foreign trade information of the service (comExt group).
+type DPSForeignTrade struct {
+	ServiceMode              int     // mdPrestacao - 0 to 4
+	Relationship             int     // vincPrest - 0 to 6
+	CurrencyCode             string  // tpMoeda - 3-digit BACEN code
+	ForeignValue             float64 // vServMoeda
+	ProviderSupportMechanism string  // mecAFComexP - "00" to "08"
+	TakerSupportMechanism    string  // mecAFComexT - "00" to "26"
+	TemporaryGoods           int     // movTempBens - 0 to 3
+	ImportDeclaration        string  // nDI (optional)
+	ExportRegistration       string  // nRE (optional)
+	ShareWithMDIC            bool    // mdic
+}
+
+// DPSConstruction contains the construction site of the service (obra group).
+// The site is identified by Code or, when empty, by Address.
+type DPSConstruction struct {
+	Code                 string // cObra - CNO or CEI number
+	PropertyRegistration string // inscImobFisc (optional)
+	Address              *AddressConfig
+}
+
+// DPSEvent contains the event of the service (atvEvento group).
+// The event is identified by ID or, when empty, by Address.
+type DPSEvent struct {
+	Name      string    // xNome
+	StartDate time.Time // dtIni
+	EndDate   time.Time // dtFim
+	ID        string    // idAtvEvt - assigned by the municipality
+	Address   *AddressConfig
 }
 
-// DPSValues contains monetary values for the DPS.
-// These values are used to calculate the tax base according to Brazilian NFS-e rules:
-// Tax Base (vBCCalc) = ServiceValue - UnconditionalDiscount - Deductions
+// DPSValues contains monetary values and the tax treatment for the DPS.
+// The Sistema Nacional calculates the ISS tax base and amount from these values:
+// Tax Base (vBC) = ServiceValue - UnconditionalDiscount - Deductions - benefit reduction
 // Note: ConditionalDiscount does NOT affect the tax base.
 type DPSValues struct {
 	// ServiceValue is the gross value of the service (vServ).
@@ -107,17 +207,40 @@ type DPSValues struct {
 	// If set to 0 and Deductions > 0, it will be calculated automatically.
 	DeductionPercentage float64
 
-	// TaxBase is the calculated tax base for ISS (vBCCalc).
-	// If set to 0, it will be calculated automatically.
-	TaxBase float64
+	// DeductionDocuments itemize the deductions (documentos/docDedRed).
+	// When present, the DPS lists them instead of the deduction value and percentage.
+	DeductionDocuments []DPSDeductionDocument
+
+	// ISSTaxation is the ISS taxation (tribISSQN). Defaults to ISSTaxationTaxable.
+	ISSTaxation int
+
+	// ResultCountryCode is the ISO country of the result of an exported service (cPaisResult).
+	// Only emitted for ISSTaxationExport.
+	ResultCountryCode string
+
+	// ImmunityType is the constitutional immunity (tpImunidade).
+	// Only emitted for ISSTaxationImmunity.
+	ImmunityType int
+
+	// ISSSuspension is the suspended exigibility of the ISS (exigSusp). Optional.
+	ISSSuspension *DPSISSSuspension
 
-	// ISSRate is the ISS tax rate percentage (pAliq).
-	// Can be 0 for SIMPLES NACIONAL MEI providers.
+	// MunicipalBenefit is the municipal benefit with its tax base reduction (BM). Optional.
+	MunicipalBenefit *DPSMunicipalBenefit
+
+	// ISSWithholding is the ISS withholding (tpRetISSQN). Defaults to ISSNotWithheld.
+	ISSWithholding int
+
+	// ISSRate is the ISS tax rate percentage (pAliq), emitted for taxable operations.
+	// Zero omits the rate; it is never emitted for MEI providers or special regimes.
 	ISSRate float64
 
-	// ISSAmount is the calculated ISS tax amount (vISS).
-	// If set to 0 and ISSRate > 0, it will be calculated automatically.
-	ISSAmount float64
+	// FederalTaxes are the federal taxes of the service (tribFed). Optional.
+	FederalTaxes *DPSFederalTaxes
+
+	// TotalTaxes is the approximate tax burden of the service (totTrib).
+	// Optional; without it the tax burden is declared as not informed.
+	TotalTaxes *DPSTotalTaxes
 }
 
 // DPSBuildResult contains the result of building a DPS XML.
@@ -157,15 +280,34 @@ func (b *DPSBuilder) Build() (*DPSBuildResult, error) {
 	if b.config.EmitterType == 0 {
 		b.config.EmitterType = 1 // Default to provider
 	}
-	if b.config.Substitution == 0 {
-		b.config.Substitution = 2 // Default to no substitution
+
+	// Build substitution group (only when replacing an existing NFS-e)
+	subst, err := b.buildSubstitution()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build service, including the construction site and event groups
+	serv, err := b.buildService()
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate DPS ID
+	// Build values, including the tax section
+	valores, err := b.buildValues()
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate DPS ID from the emitter's registration
+	regType, registration, err := b.emitterRegistration()
+	if err != nil {
+		return nil, err
+	}
 	dpsID, err := GenerateDPSID(DPSIDConfig{
 		MunicipalityCode:    b.config.MunicipalityCode,
-		RegistrationType:    RegistrationTypeCNPJ,
-		FederalRegistration: b.config.Provider.CNPJ,
+		RegistrationType:    regType,
+		FederalRegistration: registration,
 		Series:              b.config.Series,
 		Number:              b.config.Number,
 	})
@@ -178,20 +320,23 @@ func (b *DPSBuilder) Build() (*DPSBuildResult, error) {
 		XMLNs:  "http://www.sped.fazenda.gov.br/nfse",
 		Versao: "1.00",
 		InfDPS: infDPSXML{
-			ID:        dpsID,
-			TpAmb:     b.config.Environment,
-			DhEmi:     formatDateTime(b.config.EmissionDateTime),
-			VerAplic:  b.config.ApplicationVersion,
-			Serie:     b.config.Series,
-			NDPS:      b.config.Number,
-			DCompet:   formatDate(b.config.CompetenceDate),
-			TpEmit:    b.config.EmitterType,
-			CLocEmi:   b.config.MunicipalityCode,
-			Subst:     b.config.Substitution,
-			Prest:     b.buildProvider(),
-			Toma:      b.buildTaker(),
-			Serv:      b.buildService(),
-			Valores:   b.buildValues(),
+			ID:            dpsID,
+			TpAmb:         b.config.Environment,
+			DhEmi:         formatDateTime(b.config.EmissionDateTime),
+			VerAplic:      b.config.ApplicationVersion,
+			Serie:         b.config.Series,
+			NDPS:          b.config.Number,
+			DCompet:       formatDate(b.config.CompetenceDate),
+			TpEmit:        b.config.EmitterType,
+			CMotivoEmisTI: b.config.EmissionReason,
+			ChNFSeRej:     b.config.RejectedAccessKey,
+			CLocEmi:       b.config.MunicipalityCode,
+			Subst:         subst,
+			Prest:         b.buildProvider(),
+			Toma:          b.buildTaker(),
+			Interm:        b.buildIntermediary(),
+			Serv:          serv,
+			Valores:       valores,
 		},
 	}
 
@@ -211,68 +356,168 @@ func (b *DPSBuilder) Build() (*DPSBuildResult, error) {
 	}, nil
 }
 
-// buildProvider creates the provider (prestador) XML element.
-func (b *DPSBuilder) buildProvider() prestXML {
-	// Convert tax regime to opSimpNac value
-	opSimpNac := 2 // MEI
-	if b.config.Provider.TaxRegime == "me_epp" {
-		opSimpNac = 3 // ME/EPP
+// emitterRegistration returns the registration type and the CNPJ or CPF of the party
+// emitting the DPS (tpEmit). A taker or intermediary emitter must be identified by CNPJ or CPF.
+func (b *DPSBuilder) emitterRegistration() (int, string, error) {
+	var cnpj, cpf string
+	switch b.config.EmitterType {
+	case 1:
+		cnpj, cpf = b.config.Provider.CNPJ, b.config.Provider.CPF
+	case 2:
+		if b.config.Taker == nil {
+			return 0, "", fmt.Errorf("emitter type 2 requires the taker")
+		}
+		cnpj, cpf = b.config.Taker.CNPJ, b.config.Taker.CPF
+	case 3:
+		if b.config.Intermediary == nil {
+			return 0, "", fmt.Errorf("emitter type 3 requires the intermediary")
+		}
+		cnpj, cpf = b.config.Intermediary.CNPJ, b.config.Intermediary.CPF
+	default:
+		return 0, "", fmt.Errorf("emitter type must be 1, 2 or 3, got %d", b.config.EmitterType)
+	}
+
+	switch {
+	case cnpj != "":
+		return RegistrationTypeCNPJ, cnpj, nil
+	case cpf != "":
+		return RegistrationTypeCPF, cpf, nil
+	default:
+		return 0, "", fmt.Errorf("emitter type %d requires a CNPJ or CPF", b.config.EmitterType)
+	}
+}
+
+// buildSubstitution creates the substitution (subst) XML element.
+// Returns nil when the DPS does not replace another NFS-e.
+func (b *DPSBuilder) buildSubstitution() (*substXML, error) {
+	sub := b.config.Substitution
+	if sub == nil {
+		return nil, nil
+	}
+
+	accessKey := strings.TrimSpace(sub.ReplacedAccessKey)
+	if accessKey == "" {
+		return nil, ErrSubstitutionMissingAccessKey
+	}
+
+	if !IsValidSubstitutionReasonCode(sub.ReasonCode) {
+		return nil, fmt.Errorf("%w: %q", ErrSubstitutionInvalidReasonCode, sub.ReasonCode)
+	}
+
+	subst := &substXML{
+		ChSubstda: accessKey,
+		CMotivo:   sub.ReasonCode,
+	}
+
+	reason := sanitizeXMLText(strings.TrimSpace(sub.ReasonDescription))
+	if reason != "" {
+		if n := utf8.RuneCountInString(reason); n < EventReasonMinLength || n > EventReasonMaxLength {
+			return nil, ErrSubstitutionInvalidReason
+		}
+		subst.XMotivo = reason
 	}
 
+	return subst, nil
+}
+
+// buildProvider creates the provider (prestador) XML element.
+func (b *DPSBuilder) buildProvider() prestXML {
 	prest := prestXML{
-		CNPJ:  cleanTaxID(b.config.Provider.CNPJ),
-		XNome: b.config.Provider.Name,
-		RegTrib: regTribXML{
-			OpSimpNac: opSimpNac,
-		},
+		CNPJ:    cleanTaxID(b.config.Provider.CNPJ),
+		CPF:     cleanTaxID(b.config.Provider.CPF),
+		XNome:   b.config.Provider.Name,
+		RegTrib: buildTaxRegime(b.config.Provider),
 	}
 
 	if b.config.Provider.MunicipalRegistration != "" {
 		prest.IM = b.config.Provider.MunicipalRegistration
 	}
 
+	if b.config.EmitterType != 1 {
+		prest.End = b.buildAddress(b.config.Provider.Address)
+	}
+
 	return prest
 }
 
 // buildTaker creates the taker (tomador) XML element.
-func (b *DPSBuilder) buildTaker() *tomaXML {
-	if b.config.Taker == nil {
+func (b *DPSBuilder) buildTaker() *infoPessoaXML {
+	taker := b.config.Taker
+	if taker == nil {
+		return nil
+	}
+
+	addr := taker.Address
+	if b.config.EmitterType == 2 {
+		addr = emitterAddress(addr)
+	}
+
+	return b.buildPerson(taker.CNPJ, taker.CPF, taker.NIF, taker.Name, taker.Phone, taker.Email, addr)
+}
+
+// buildIntermediary creates the intermediary (interm) XML element.
+func (b *DPSBuilder) buildIntermediary() *infoPessoaXML {
+	interm := b.config.Intermediary
+	if interm == nil {
+		return nil
+	}
+
+	addr := interm.Address
+	if b.config.EmitterType == 3 {
+		addr = emitterAddress(addr)
+	}
+
+	return b.buildPerson(interm.CNPJ, interm.CPF, interm.NIF, interm.Name, interm.Phone, interm.Email, addr)
+}
+
+// emitterAddress returns the address to emit for the taker or intermediary emitting
+// the DPS. SEFIN takes the emitter's national address from its own registry and
+// rejects it in the DPS (E0236, E0291), so only a foreign address is kept.
+func emitterAddress(addr *AddressConfig) *AddressConfig {
+	if addr == nil || !addr.IsForeign() {
 		return nil
 	}
+	return addr
+}
 
-	toma := &tomaXML{
-		XNome: b.config.Taker.Name,
+// buildPerson creates the XML element of a person (TCInfoPessoa), shared by
+// the taker and the intermediary.
+func (b *DPSBuilder) buildPerson(cnpj, cpf, nif, name, phone, email string, addr *AddressConfig) *infoPessoaXML {
+	person := &infoPessoaXML{
+		XNome: name,
 	}
 
 	// Set identification (only one should be set)
-	if b.config.Taker.CNPJ != "" {
-		toma.CNPJ = cleanTaxID(b.config.Taker.CNPJ)
-	} else if b.config.Taker.CPF != "" {
-		toma.CPF = cleanTaxID(b.config.Taker.CPF)
-	} else if b.config.Taker.NIF != "" {
-		toma.NIF = b.config.Taker.NIF
+	if cnpj != "" {
+		person.CNPJ = cleanTaxID(cnpj)
+	} else if cpf != "" {
+		person.CPF = cleanTaxID(cpf)
+	} else if nif != "" {
+		person.NIF = nif
 	}
 
 	// Set address if provided
-	if b.config.Taker.Address != nil {
-		toma.End = b.buildTakerAddress(b.config.Taker.Address)
+	if addr != nil {
+		person.End = b.buildAddress(addr)
 	}
 
 	// Set phone if provided
-	if b.config.Taker.Phone != "" {
-		toma.Fone = cleanPhoneNumber(b.config.Taker.Phone)
+	if phone != "" {
+		person.Fone = cleanPhoneNumber(phone)
 	}
 
 	// Set email if provided
-	if b.config.Taker.Email != "" {
-		toma.Email = b.config.Taker.Email
+	if email != "" {
+		person.Email = email
 	}
 
-	return toma
+	return person
 }
 
-// buildTakerAddress creates the address (end) XML element for the taker.
-func (b *DPSBuilder) buildTakerAddress(addr *AddressConfig) *endXML {
+// buildAddress creates the address (end) XML element of a person.
+// National addresses are identified by endNac (cMun, CEP) and foreign
+// addresses by endExt (cPais, cEndPost, xCidade, xEstProvReg).
+func (b *DPSBuilder) buildAddress(addr *AddressConfig) *endXML {
 	if addr == nil {
 		return nil
 	}
@@ -290,25 +535,17 @@ func (b *DPSBuilder) buildTakerAddress(addr *AddressConfig) *endXML {
 
 	// Set fields based on whether this is a national or foreign address
 	if addr.IsForeign() {
-		// Foreign address: only country code required
-		end.CPais = strings.ToUpper(addr.CountryCode)
-	} else {
-		// National address: include all fields
-		if addr.MunicipalityCode != "" {
-			end.CMun = addr.MunicipalityCode
+		end.EndExt = &endExtXML{
+			CPais:       strings.ToUpper(addr.CountryCode),
+			CEndPost:    addr.PostalCode,
+			XCidade:     addr.City,
+			XEstProvReg: addr.Region,
 		}
-		if addr.State != "" {
-			end.UF = strings.ToUpper(addr.State)
-		}
-		if addr.PostalCode != "" {
-			end.CEP = cleanPostalCode(addr.PostalCode)
-		}
-		// Default to BR for national addresses
-		countryCode := addr.CountryCode
-		if countryCode == "" {
-			countryCode = "BR"
+	} else {
+		end.EndNac = &endNacXML{
+			CMun: addr.MunicipalityCode,
+			CEP:  cleanPostalCode(addr.PostalCode),
 		}
-		end.CPais = strings.ToUpper(countryCode)
 	}
 
 	return end
@@ -333,25 +570,50 @@ func cleanPostalCode(postal string) string {
 }
 
 // buildService creates the service (serv) XML element.
-func (b *DPSBuilder) buildService() servXML {
-	return servXML{
-		LocPrest: locPrestXML{
-			CLocPrestacao: b.config.Service.MunicipalityCode,
-		},
+func (b *DPSBuilder) buildService() (servXML, error) {
+	serv := servXML{
 		CServ: cServXML{
-			CTribNac: b.config.Service.NationalCode,
+			CTribNac:  b.config.Service.NationalCode,
+			CTribMun:  b.config.Service.MunicipalTaxCode,
+			XDescServ: b.config.Service.Description,
+			CNBS:      nbs.Clean(b.config.Service.NBSCode),
 		},
-		XDescServ: b.config.Service.Description,
+		ComExt: buildForeignTrade(b.config.Service.ForeignTrade),
+	}
+
+	// Services performed abroad are identified by the country instead of the municipality
+	if countryCode := strings.ToUpper(b.config.Service.CountryCode); countryCode != "" && countryCode != "BR" {
+		serv.LocPrest.CPaisPrestacao = countryCode
+	} else {
+		serv.LocPrest.CLocPrestacao = b.config.Service.MunicipalityCode
+	}
+
+	obra, err := buildConstruction(b.config.Service.Construction)
+	if err != nil {
+		return servXML{}, err
+	}
+	serv.Obra = obra
+
+	atvEvento, err := buildEvent(b.config.Service.Event)
+	if err != nil {
+		return servXML{}, err
 	}
+	serv.AtvEvento = atvEvento
+
+	return serv, nil
 }
 
 // buildValues creates the values (valores) XML element with complete discount,
 // deduction, and tax calculation sections according to Brazilian NFS-e rules.
-func (b *DPSBuilder) buildValues() valoresXML {
+func (b *DPSBuilder) buildValues() (valoresXML, error) {
+	trib, err := b.buildTaxSection()
+	if err != nil {
+		return valoresXML{}, err
+	}
+
 	valores := valoresXML{
 		VServPrest: b.buildServiceValues(),
-		Trib:       b.buildTaxSection(),
-		TotTrib:    b.buildTotalTaxSection(),
+		Trib:       trib,
 	}
 
 	// Add deduction section if deductions are present
@@ -359,7 +621,7 @@ func (b *DPSBuilder) buildValues() valoresXML {
 		valores.VDedRed = b.buildDeductionSection()
 	}
 
-	return valores
+	return valores, nil
 }
 
 // buildServiceValues creates the service values (vServPrest) section.
@@ -387,6 +649,11 @@ func (b *DPSBuilder) buildDeductionSection() *vDedRedXML {
 		return nil
 	}
 
+	// Itemized deductions replace the deduction value and percentage
+	if documentos := buildDeductionDocuments(b.config.Values.DeductionDocuments); documentos != nil {
+		return &vDedRedXML{Documentos: documentos}
+	}
+
 	// Calculate deduction percentage if not provided
 	deductionPercentage := b.config.Values.DeductionPercentage
 	if deductionPercentage == 0 && b.config.Values.ServiceValue > 0 {
@@ -399,56 +666,18 @@ func (b *DPSBuilder) buildDeductionSection() *vDedRedXML {
 	}
 }
 
-// buildTaxSection creates the tax (trib) section with municipal tax (ISSQN) details.
-func (b *DPSBuilder) buildTaxSection() tribXML {
-	// Calculate tax base if not provided
-	taxBase := b.config.Values.TaxBase
-	if taxBase == 0 {
-		taxBase = b.config.Values.ServiceValue -
-			b.config.Values.UnconditionalDiscount -
-			b.config.Values.Deductions
-	}
-
-	// Ensure tax base is not negative
-	if taxBase < 0 {
-		taxBase = 0
-	}
-
-	// Calculate ISS amount if not provided
-	issAmount := b.config.Values.ISSAmount
-	if issAmount == 0 && b.config.Values.ISSRate > 0 {
-		issAmount = taxBase * b.config.Values.ISSRate / 100
+// buildTaxSection creates the tax (trib) section with municipal (ISSQN) and federal taxes.
+func (b *DPSBuilder) buildTaxSection() (tribXML, error) {
+	totTrib, err := buildTotalTaxes(b.config.Values.TotalTaxes, b.config.Provider.TaxRegime)
+	if err != nil {
+		return tribXML{}, err
 	}
 
-	// Determine tribISSQN value based on tax regime
-	// tribISSQN: 1 = Operação tributável
-	// For SIMPLES NACIONAL MEI, ISS is typically not charged (use tribISSQN = 1 anyway)
-	tribISSQN := 1
-
 	return tribXML{
-		TribMun: tribMunXML{
-			TribISSQN:   tribISSQN,
-			CPaisResult: "BR", // Service result country code
-			BM: bmXML{
-				VBCCalc: formatMoney(taxBase),
-				PAliq:   formatMoney(b.config.Values.ISSRate),
-				VISS:    formatMoney(issAmount),
-			},
-		},
-	}
-}
-
-// buildTotalTaxSection creates the total tax (totTrib) section.
-// For SIMPLES NACIONAL providers (MEI/ME/EPP), total taxes are typically 0.
-func (b *DPSBuilder) buildTotalTaxSection() totTribXML {
-	return totTribXML{
-		IndTotTrib: 0, // 0 = Not informed
-		PTotTrib: pTotTribXML{
-			PTotTribFed: formatMoney(0),
-			PTotTribEst: formatMoney(0),
-			PTotTribMun: formatMoney(0),
-		},
-	}
+		TribMun: buildMunicipalTax(b.config.Values, b.config.Provider),
+		TribFed: buildFederalTaxes(b.config.Values.FederalTaxes),
+		TotTrib: totTrib,
+	}, nil
 }
 
 // XML structure types for marshaling
@@ -461,34 +690,43 @@ type dpsXML struct {
 }
 
 type infDPSXML struct {
-	ID       string     `xml:"Id,attr"`
-	TpAmb    int        `xml:"tpAmb"`
-	DhEmi    string     `xml:"dhEmi"`
-	VerAplic string     `xml:"verAplic"`
-	Serie    string     `xml:"serie"`
-	NDPS     string     `xml:"nDPS"`
-	DCompet  string     `xml:"dCompet"`
-	TpEmit   int        `xml:"tpEmit"`
-	CLocEmi  string     `xml:"cLocEmi"`
-	Subst    int        `xml:"subst"`
-	Prest    prestXML   `xml:"prest"`
-	Toma     *tomaXML   `xml:"toma,omitempty"`
-	Serv     servXML    `xml:"serv"`
-	Valores  valoresXML `xml:"valores"`
+	ID            string         `xml:"Id,attr"`
+	TpAmb         int            `xml:"tpAmb"`
+	DhEmi         string         `xml:"dhEmi"`
+	VerAplic      string         `xml:"verAplic"`
+	Serie         string         `xml:"serie"`
+	NDPS          string         `xml:"nDPS"`
+	DCompet       string         `xml:"dCompet"`
+	TpEmit        int            `xml:"tpEmit"`
+	CMotivoEmisTI int            `xml:"cMotivoEmisTI,omitempty"`
+	ChNFSeRej     string         `xml:"chNFSeRej,omitempty"`
+	CLocEmi       string         `xml:"cLocEmi"`
+	Subst         *substXML      `xml:"subst,omitempty"`
+	Prest         prestXML       `xml:"prest"`
+	Toma          *infoPessoaXML `xml:"toma,omitempty"`
+	Interm        *infoPessoaXML `xml:"interm,omitempty"`
+	Serv          servXML        `xml:"serv"`
+	Valores       valoresXML     `xml:"valores"`
+}
+
+// substXML represents the substitution group (TCSubstituicao).
+type substXML struct {
+	ChSubstda string `xml:"chSubstda"`
+	CMotivo   string `xml:"cMotivo"`
+	XMotivo   string `xml:"xMotivo,omitempty"`
 }
 
 type prestXML struct {
-	CNPJ    string     `xml:"CNPJ"`
+	CNPJ    string     `xml:"CNPJ,omitempty"`
+	CPF     string     `xml:"CPF,omitempty"`
 	IM      string     `xml:"IM,omitempty"`
 	XNome   string     `xml:"xNome"`
+	End     *endXML    `xml:"end,omitempty"`
 	RegTrib regTribXML `xml:"regTrib"`
 }
 
-type regTribXML struct {
-	OpSimpNac int `xml:"opSimpNac"`
-}
-
-type tomaXML struct {
+// infoPessoaXML represents a person (TCInfoPessoa), used for the taker and the intermediary.
+type infoPessoaXML struct {
 	CNPJ  string  `xml:"CNPJ,omitempty"`
 	CPF   string  `xml:"CPF,omitempty"`
 	NIF   string  `xml:"NIF,omitempty"`
@@ -499,35 +737,52 @@ type tomaXML struct {
 }
 
 type endXML struct {
-	XLgr    string `xml:"xLgr"`
-	Nro     string `xml:"nro"`
-	XCpl    string `xml:"xCpl,omitempty"`
-	XBairro string `xml:"xBairro"`
-	CMun    string `xml:"cMun,omitempty"`
-	UF      string `xml:"UF,omitempty"`
-	CEP     string `xml:"CEP,omitempty"`
-	CPais   string `xml:"cPais"`
+	EndNac  *endNacXML `xml:"endNac,omitempty"`
+	EndExt  *endExtXML `xml:"endExt,omitempty"`
+	XLgr    string     `xml:"xLgr"`
+	Nro     string     `xml:"nro"`
+	XCpl    string     `xml:"xCpl,omitempty"`
+	XBairro string     `xml:"xBairro"`
+}
+
+// endNacXML identifies a national address.
+type endNacXML struct {
+	CMun string `xml:"cMun"`
+	CEP  string `xml:"CEP"`
+}
+
+// endExtXML identifies a foreign address.
+type endExtXML struct {
+	CPais       string `xml:"cPais"`
+	CEndPost    string `xml:"cEndPost"`
+	XCidade     string `xml:"xCidade"`
+	XEstProvReg string `xml:"xEstProvReg"`
 }
 
 type servXML struct {
-	LocPrest  locPrestXML `xml:"locPrest"`
-	CServ     cServXML    `xml:"cServ"`
-	XDescServ string      `xml:"xDescServ"`
+	LocPrest  locPrestXML   `xml:"locPrest"`
+	CServ     cServXML      `xml:"cServ"`
+	ComExt    *comExtXML    `xml:"comExt,omitempty"`
+	Obra      *obraXML      `xml:"obra,omitempty"`
+	AtvEvento *atvEventoXML `xml:"atvEvento,omitempty"`
 }
 
 type locPrestXML struct {
-	CLocPrestacao string `xml:"cLocPrestacao"`
+	CLocPrestacao  string `xml:"cLocPrestacao,omitempty"`
+	CPaisPrestacao string `xml:"cPaisPrestacao,omitempty"`
 }
 
 type cServXML struct {
-	CTribNac string `xml:"cTribNac"`
+	CTribNac  string `xml:"cTribNac"`
+	CTribMun  string `xml:"cTribMun,omitempty"`
+	XDescServ string `xml:"xDescServ"`
+	CNBS      string `xml:"cNBS,omitempty"`
 }
 
 type valoresXML struct {
 	VServPrest vServPrestXML `xml:"vServPrest"`
 	VDedRed    *vDedRedXML   `xml:"vDedRed,omitempty"`
 	Trib       tribXML       `xml:"trib"`
-	TotTrib    totTribXML    `xml:"totTrib"`
 }
 
 type vServPrestXML struct {
@@ -538,40 +793,16 @@ type vServPrestXML struct {
 
 // vDedRedXML represents the deduction section in the valores element.
 type vDedRedXML struct {
-	VDR string `xml:"vDR"`
-	PDR string `xml:"pDR"`
+	VDR        string         `xml:"vDR,omitempty"`
+	PDR        string         `xml:"pDR,omitempty"`
+	Documentos *documentosXML `xml:"documentos,omitempty"`
 }
 
 // tribXML represents the tax section in the valores element.
 type tribXML struct {
-	TribMun tribMunXML `xml:"tribMun"`
-}
-
-// tribMunXML represents the municipal tax (ISSQN) details.
-type tribMunXML struct {
-	TribISSQN   int    `xml:"tribISSQN"`
-	CPaisResult string `xml:"cPaisResult"`
-	BM          bmXML  `xml:"BM"`
-}
-
-// bmXML represents the tax base calculation details.
-type bmXML struct {
-	VBCCalc string `xml:"vBCCalc"`
-	PAliq   string `xml:"pAliq"`
-	VISS    string `xml:"vISS"`
-}
-
-// totTribXML represents the total tax information section.
-type totTribXML struct {
-	IndTotTrib int         `xml:"indTotTrib"`
-	PTotTrib   pTotTribXML `xml:"pTotTrib"`
-}
-
-// pTotTribXML represents the total tax percentages by jurisdiction.
-type pTotTribXML struct {
-	PTotTribFed string `xml:"pTotTribFed"`
-	PTotTribEst string `xml:"pTotTribEst"`
-	PTotTribMun string `xml:"pTotTribMun"`
+	TribMun tribMunXML  `xml:"tribMun"`
+	TribFed *tribFedXML `xml:"tribFed,omitempty"`
+	TotTrib totTribXML  `xml:"totTrib"`
 }
 
 // Helper functions
@@ -597,6 +828,15 @@ func formatMoney(value float64) string {
 	return fmt.Sprintf("%.2f", value)
 }
 
+// formatOptionalMoney formats a monetary value, returning an empty string for zero
+// so that optional elements are omitted.
+func formatOptionalMoney(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return formatMoney(value)
+}
+
 // cleanTaxID removes formatting characters from a tax ID (CNPJ/CPF).
 func cleanTaxID(taxID string) string {
 	taxID = strings.ReplaceAll(taxID, ".", "")
@@ -0,0 +1,425 @@
+package xmlbuilder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// interElementSpace matches the whitespace between XML elements.
+var interElementSpace = regexp.MustCompile(`>\s+<`)
+
+// TestDPSBuilder_BuildTaker tests the taker (toma) element with national and foreign addresses.
+func TestDPSBuilder_BuildTaker(t *testing.T) {
+	tests := []struct {
+		name        string
+		taker       *DPSTaker
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "company taker with national address",
+			taker: &DPSTaker{
+				CNPJ:  "11.222.333/0001-81",
+				Name:  "Tomador Ltda",
+				Phone: "(11) 98765-4321",
+				Email: "financeiro@tomador.com.br",
+				Address: &AddressConfig{
+					Street:           "Avenida Paulista",
+					Number:           "1000",
+					Complement:       "Conjunto 101",
+					Neighborhood:     "Bela Vista",
+					MunicipalityCode: "3550308",
+					State:            "sp",
+					PostalCode:       "01310-100",
+				},
+			},
+			contains: []string{
+				"<CNPJ>11222333000181</CNPJ>",
+				"<end><endNac><cMun>3550308</cMun><CEP>01310100</CEP></endNac><xLgr>Avenida Paulista</xLgr>",
+				"<xCpl>Conjunto 101</xCpl>",
+				"<fone>11987654321</fone>",
+				"<email>financeiro@tomador.com.br</email>",
+			},
+			notContains: []string{"<UF>", "<cPais>", "<endExt>"},
+		},
+		{
+			name: "foreign taker with foreign address",
+			taker: &DPSTaker{
+				NIF:  "ES12345678A",
+				Name: "Foreign Company",
+				Address: &AddressConfig{
+					Street:       "Calle Mayor",
+					Number:       "10",
+					Neighborhood: "Centro",
+					PostalCode:   "28013",
+					City:         "Madrid",
+					Region:       "Comunidad de Madrid",
+					CountryCode:  "es",
+				},
+			},
+			contains: []string{
+				"<NIF>ES12345678A</NIF>",
+				"<endExt><cPais>ES</cPais><cEndPost>28013</cEndPost><xCidade>Madrid</xCidade><xEstProvReg>Comunidad de Madrid</xEstProvReg></endExt>",
+			},
+			notContains: []string{"<endNac>", "<fone>", "<email>"},
+		},
+		{
+			name:        "individual taker without address",
+			taker:       &DPSTaker{CPF: "529.982.247-25", Name: "Maria Silva"},
+			contains:    []string{"<CPF>52998224725</CPF>", "<xNome>Maria Silva</xNome>"},
+			notContains: []string{"<end>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Taker = tt.taker
+			config.Values = DPSValues{ServiceValue: 1000.00}
+
+			result, err := NewDPSBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Compare without the indentation between elements
+			compact := interElementSpace.ReplaceAllString(result.XML, "><")
+			for _, expected := range tt.contains {
+				if !strings.Contains(compact, expected) {
+					t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+				}
+			}
+			for _, unexpected := range tt.notContains {
+				if strings.Contains(result.XML, unexpected) {
+					t.Errorf("expected XML not to contain %q, got:\n%s", unexpected, result.XML)
+				}
+			}
+		})
+	}
+}
+
+// TestDPSBuilder_BuildIntermediary tests the intermediary (interm) element follows the taker.
+func TestDPSBuilder_BuildIntermediary(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 1000.00}
+	config.Taker = &DPSTaker{CPF: "529.982.247-25", Name: "Maria Silva"}
+	config.Intermediary = &DPSIntermediary{
+		CNPJ:  "11.444.777/0001-61",
+		Name:  "Marketplace S.A.",
+		Email: "nfse@marketplace.com.br",
+		Address: &AddressConfig{
+			Street:           "Rua Funchal",
+			Number:           "418",
+			Neighborhood:     "Vila Olimpia",
+			MunicipalityCode: "3550308",
+			State:            "SP",
+			PostalCode:       "04551-060",
+		},
+	}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "</toma><interm><CNPJ>11444777000161</CNPJ><xNome>Marketplace S.A.</xNome>" +
+		"<end><endNac><cMun>3550308</cMun><CEP>04551060</CEP></endNac><xLgr>Rua Funchal</xLgr><nro>418</nro><xBairro>Vila Olimpia</xBairro></end>" +
+		"<email>nfse@marketplace.com.br</email></interm><serv>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+	}
+
+	// Without an intermediary the element is omitted
+	config.Intermediary = nil
+	result, err = NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(result.XML, "<interm>") {
+		t.Errorf("expected XML not to contain <interm>, got:\n%s", result.XML)
+	}
+}
+
+// TestDPSBuilder_EmitterType tests the DPS ID is formed with the emitter's registration.
+func TestDPSBuilder_EmitterType(t *testing.T) {
+	tests := []struct {
+		name         string
+		emitterType  int
+		intermediary *DPSIntermediary
+		expectedID   string
+		expectError  bool
+	}{
+		{
+			name:        "provider",
+			emitterType: 1,
+			expectedID:  "DPS3550308112345678000190" + "00001" + "000000000000123",
+		},
+		{
+			name:        "taker with CPF",
+			emitterType: 2,
+			expectedID:  "DPS3550308200052998224725" + "00001" + "000000000000123",
+		},
+		{
+			name:         "intermediary with CNPJ",
+			emitterType:  3,
+			intermediary: &DPSIntermediary{CNPJ: "11.444.777/0001-61", Name: "Marketplace S.A."},
+			expectedID:   "DPS3550308111444777000161" + "00001" + "000000000000123",
+		},
+		{
+			name:        "intermediary missing",
+			emitterType: 3,
+			expectError: true,
+		},
+		{
+			name:         "foreign intermediary",
+			emitterType:  3,
+			intermediary: &DPSIntermediary{NIF: "ES12345678A", Name: "Foreign Marketplace"},
+			expectError:  true,
+		},
+		{
+			name:        "invalid emitter type",
+			emitterType: 4,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Values = DPSValues{ServiceValue: 1000.00}
+			config.EmitterType = tt.emitterType
+			config.Taker = &DPSTaker{CPF: "529.982.247-25", Name: "Maria Silva"}
+			config.Intermediary = tt.intermediary
+
+			result, err := NewDPSBuilder(config).Build()
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.DPSID != tt.expectedID {
+				t.Errorf("expected DPS ID %s, got %s", tt.expectedID, result.DPSID)
+			}
+			expected := fmt.Sprintf("<tpEmit>%d</tpEmit>", tt.emitterType)
+			if !strings.Contains(result.XML, expected) {
+				t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+			}
+		})
+	}
+}
+
+// TestDPSBuilder_EmitterAddresses tests the provider address is only emitted when the
+// provider is not the emitter, and the emitter's own national address is omitted.
+func TestDPSBuilder_EmitterAddresses(t *testing.T) {
+	address := func(street, municipality, postalCode string) *AddressConfig {
+		return &AddressConfig{
+			Street:           street,
+			Number:           "100",
+			Neighborhood:     "Centro",
+			MunicipalityCode: municipality,
+			State:            "SP",
+			PostalCode:       postalCode,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		emitterType int
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "provider",
+			emitterType: 1,
+			contains: []string{
+				"<xNome>Test Provider Ltda</xNome><regTrib>",
+				"<toma><CNPJ>11222333000181</CNPJ><xNome>Tomador Ltda</xNome><end><endNac><cMun>3550308</cMun>",
+				"<interm><CNPJ>11444777000161</CNPJ><xNome>Marketplace S.A.</xNome><end><endNac><cMun>3509502</cMun>",
+			},
+			notContains: []string{"Rua do Prestador"},
+		},
+		{
+			name:        "taker",
+			emitterType: 2,
+			contains: []string{
+				"<xNome>Test Provider Ltda</xNome><end><endNac><cMun>3548708</cMun><CEP>09750000</CEP></endNac>" +
+					"<xLgr>Rua do Prestador</xLgr>",
+				"<toma><CNPJ>11222333000181</CNPJ><xNome>Tomador Ltda</xNome></toma>",
+				"<interm><CNPJ>11444777000161</CNPJ><xNome>Marketplace S.A.</xNome><end><endNac><cMun>3509502</cMun>",
+			},
+		},
+		{
+			name:        "intermediary",
+			emitterType: 3,
+			contains: []string{
+				"<xNome>Test Provider Ltda</xNome><end><endNac><cMun>3548708</cMun>",
+				"<toma><CNPJ>11222333000181</CNPJ><xNome>Tomador Ltda</xNome><end><endNac><cMun>3550308</cMun>",
+				"<interm><CNPJ>11444777000161</CNPJ><xNome>Marketplace S.A.</xNome></interm>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Values = DPSValues{ServiceValue: 1000.00}
+			config.EmitterType = tt.emitterType
+			config.Provider.Address = address("Rua do Prestador", "3548708", "09750000")
+			config.Taker = &DPSTaker{
+				CNPJ:    "11222333000181",
+				Name:    "Tomador Ltda",
+				Address: address("Avenida Paulista", "3550308", "01310100"),
+			}
+			config.Intermediary = &DPSIntermediary{
+				CNPJ:    "11444777000161",
+				Name:    "Marketplace S.A.",
+				Address: address("Avenida Brasil", "3509502", "13010000"),
+			}
+
+			result, err := NewDPSBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			compact := interElementSpace.ReplaceAllString(result.XML, "><")
+			for _, expected := range tt.contains {
+				if !strings.Contains(compact, expected) {
+					t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+				}
+			}
+			for _, unexpected := range tt.notContains {
+				if strings.Contains(compact, unexpected) {
+					t.Errorf("expected XML not to contain %q, got:\n%s", unexpected, result.XML)
+				}
+			}
+		})
+	}
+}
+
+// TestDPSBuilder_EmissionReason tests the reason of a taker emission and the rejected
+// NFS-e access key are emitted between tpEmit and cLocEmi.
+func TestDPSBuilder_EmissionReason(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 1000.00}
+	config.EmitterType = 2
+	config.EmissionReason = 4
+	config.RejectedAccessKey = "35503082211222333000181000000000000000000000000001"
+	config.Taker = &DPSTaker{CNPJ: "11444777000161", Name: "Marketplace S.A."}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "<tpEmit>2</tpEmit><cMotivoEmisTI>4</cMotivoEmisTI>" +
+		"<chNFSeRej>35503082211222333000181000000000000000000000000001</chNFSeRej><cLocEmi>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+	}
+
+	config.EmitterType = 1
+	config.EmissionReason = 0
+	config.RejectedAccessKey = ""
+	result, err = NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(result.XML, "cMotivoEmisTI") || strings.Contains(result.XML, "chNFSeRej") {
+		t.Errorf("expected no emission reason for the provider, got:\n%s", result.XML)
+	}
+}
+
+// TestDPSBuilder_BuildProviderCPF tests an individual provider is emitted as prest/CPF
+// and identifies the DPS with registration type 2.
+func TestDPSBuilder_BuildProviderCPF(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 1000.00}
+	config.Provider = DPSProvider{
+		CPF:           "529.982.247-25",
+		Name:          "Maria Silva",
+		TaxRegime:     TaxRegimeAutonomo,
+		SpecialRegime: SpecialRegimeAutonomousProfessional,
+	}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedID := "DPS3550308200052998224725" + "00001" + "000000000000123"
+	if result.DPSID != expectedID {
+		t.Errorf("expected DPS ID %s, got %s", expectedID, result.DPSID)
+	}
+
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "<prest><CPF>52998224725</CPF><xNome>Maria Silva</xNome>" +
+		"<regTrib><opSimpNac>1</opSimpNac><regEspTrib>5</regEspTrib></regTrib></prest>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+	}
+}
+
+// TestBuildAddressXML tests the standalone address element follows the endNac/endExt layout.
+func TestBuildAddressXML(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *AddressConfig
+		wantErr   bool
+		wantPaths []string
+	}{
+		{
+			name: "national address",
+			config: &AddressConfig{
+				Street: "Rua A", Number: "1", Neighborhood: "Centro",
+				MunicipalityCode: "3550308", State: "SP", PostalCode: "01310100",
+			},
+			wantPaths: []string{"endNac/cMun", "endNac/CEP", "xLgr", "nro", "xBairro"},
+		},
+		{
+			name: "foreign address",
+			config: &AddressConfig{
+				Street: "Calle Mayor", Number: "10", Neighborhood: "Centro",
+				PostalCode: "28013", City: "Madrid", Region: "Madrid", CountryCode: "ES",
+			},
+			wantPaths: []string{"endExt/cPais", "endExt/cEndPost", "endExt/xCidade", "endExt/xEstProvReg", "xLgr"},
+		},
+		{
+			name: "foreign address without city",
+			config: &AddressConfig{
+				Street: "Calle Mayor", Number: "10", Neighborhood: "Centro",
+				PostalCode: "28013", Region: "Madrid", CountryCode: "ES",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, err := BuildAddressXML(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, path := range tt.wantPaths {
+				if end.FindElement(path) == nil {
+					t.Errorf("expected element %s", path)
+				}
+			}
+		})
+	}
+}
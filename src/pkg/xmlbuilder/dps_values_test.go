@@ -9,9 +9,11 @@ import (
 // TestDPSBuilder_BuildValues_NoDiscounts tests XML generation without discounts.
 func TestDPSBuilder_BuildValues_NoDiscounts(t *testing.T) {
 	config := createBasicDPSConfig()
+	config.Provider.TaxRegime = TaxRegimeMEEPP
 	config.Values = DPSValues{
 		ServiceValue: 1000.00,
 		ISSRate:      2.00,
+		TotalTaxes:   &DPSTotalTaxes{SimplesRate: 6.00},
 	}
 
 	builder := NewDPSBuilder(config)
@@ -40,14 +42,10 @@ func TestDPSBuilder_BuildValues_NoDiscounts(t *testing.T) {
 	if !strings.Contains(result.XML, "<trib>") {
 		t.Error("expected trib element")
 	}
-	if !strings.Contains(result.XML, "<vBCCalc>1000.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with value 1000.00")
-	}
-	if !strings.Contains(result.XML, "<pAliq>2.00</pAliq>") {
-		t.Error("expected pAliq element with value 2.00")
-	}
-	if !strings.Contains(result.XML, "<vISS>20.00</vISS>") {
-		t.Error("expected vISS element with value 20.00")
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "<tribMun><tribISSQN>1</tribISSQN><tpRetISSQN>1</tpRetISSQN><pAliq>2.00</pAliq></tribMun>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
 	}
 }
 
@@ -75,17 +73,6 @@ func TestDPSBuilder_BuildValues_WithUnconditionalDiscount(t *testing.T) {
 	if !strings.Contains(result.XML, "<vDescIncond>100.00</vDescIncond>") {
 		t.Error("expected vDescIncond element with value 100.00")
 	}
-
-	// Check tax base is reduced by unconditional discount
-	// Tax base = 1500 - 100 = 1400
-	if !strings.Contains(result.XML, "<vBCCalc>1400.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with value 1400.00")
-	}
-
-	// Check ISS = 1400 * 2% = 28
-	if !strings.Contains(result.XML, "<vISS>28.00</vISS>") {
-		t.Error("expected vISS element with value 28.00")
-	}
 }
 
 // TestDPSBuilder_BuildValues_WithConditionalDiscount tests that conditional discount
@@ -108,17 +95,6 @@ func TestDPSBuilder_BuildValues_WithConditionalDiscount(t *testing.T) {
 	if !strings.Contains(result.XML, "<vDescCond>100.00</vDescCond>") {
 		t.Error("expected vDescCond element with value 100.00")
 	}
-
-	// IMPORTANT: Tax base should NOT be reduced by conditional discount
-	// Tax base = 1500 (NOT 1400)
-	if !strings.Contains(result.XML, "<vBCCalc>1500.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with value 1500.00 (conditional discount should NOT reduce tax base)")
-	}
-
-	// Check ISS = 1500 * 2% = 30
-	if !strings.Contains(result.XML, "<vISS>30.00</vISS>") {
-		t.Error("expected vISS element with value 30.00")
-	}
 }
 
 // TestDPSBuilder_BuildValues_WithDeductions tests XML generation with deductions.
@@ -148,28 +124,32 @@ func TestDPSBuilder_BuildValues_WithDeductions(t *testing.T) {
 	if !strings.Contains(result.XML, "<pDR>13.33</pDR>") {
 		t.Error("expected pDR element with value 13.33")
 	}
-
-	// Check tax base is reduced by deductions
-	// Tax base = 1500 - 200 = 1300
-	if !strings.Contains(result.XML, "<vBCCalc>1300.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with value 1300.00")
-	}
-
-	// Check ISS = 1300 * 2% = 26
-	if !strings.Contains(result.XML, "<vISS>26.00</vISS>") {
-		t.Error("expected vISS element with value 26.00")
-	}
 }
 
-// TestDPSBuilder_BuildValues_Complete tests XML generation with all discounts and deductions.
-func TestDPSBuilder_BuildValues_Complete(t *testing.T) {
+// TestDPSBuilder_BuildValues_WithDeductionDocuments tests XML generation with itemized deductions.
+func TestDPSBuilder_BuildValues_WithDeductionDocuments(t *testing.T) {
 	config := createBasicDPSConfig()
 	config.Values = DPSValues{
-		ServiceValue:          1500.00,
-		UnconditionalDiscount: 100.00,
-		ConditionalDiscount:   50.00,
-		Deductions:            200.00,
-		ISSRate:               2.00,
+		ServiceValue: 1500.00,
+		Deductions:   200.00,
+		ISSRate:      2.00,
+		DeductionDocuments: []DPSDeductionDocument{
+			{
+				NFeKey:          "35250111222333000181550010000012341000012345",
+				Type:            2,
+				IssueDate:       time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+				DeductibleValue: 250.00,
+				DeductionValue:  150.00,
+			},
+			{
+				PaperInvoice:    &DPSPaperInvoice{Number: "123", Model: "1", Series: "A"},
+				Type:            99,
+				TypeDescription: "Equipment rental",
+				IssueDate:       time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+				DeductibleValue: 50.00,
+				DeductionValue:  50.00,
+			},
+		},
 	}
 
 	builder := NewDPSBuilder(config)
@@ -178,39 +158,44 @@ func TestDPSBuilder_BuildValues_Complete(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Check all values are present
-	if !strings.Contains(result.XML, "<vServ>1500.00</vServ>") {
-		t.Error("expected vServ element with value 1500.00")
-	}
-	if !strings.Contains(result.XML, "<vDescIncond>100.00</vDescIncond>") {
-		t.Error("expected vDescIncond element with value 100.00")
-	}
-	if !strings.Contains(result.XML, "<vDescCond>50.00</vDescCond>") {
-		t.Error("expected vDescCond element with value 50.00")
-	}
-	if !strings.Contains(result.XML, "<vDR>200.00</vDR>") {
-		t.Error("expected vDR element with value 200.00")
-	}
-
-	// Check tax base calculation: 1500 - 100 - 200 = 1200
-	// (conditional discount of 50 does NOT reduce tax base)
-	if !strings.Contains(result.XML, "<vBCCalc>1200.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with value 1200.00")
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "<vDedRed><documentos>" +
+		"<docDedRed><chNFe>35250111222333000181550010000012341000012345</chNFe><tpDedRed>2</tpDedRed>" +
+		"<dtEmiDoc>2025-01-10</dtEmiDoc><vDedutivelRedutivel>250.00</vDedutivelRedutivel>" +
+		"<vDeducaoReducao>150.00</vDeducaoReducao></docDedRed>" +
+		"<docDedRed><NFNFS><nNFS>123</nNFS><modNFS>1</modNFS><serieNFS>A</serieNFS></NFNFS><tpDedRed>99</tpDedRed>" +
+		"<xDescOutDed>Equipment rental</xDescOutDed><dtEmiDoc>2025-01-12</dtEmiDoc>" +
+		"<vDedutivelRedutivel>50.00</vDedutivelRedutivel><vDeducaoReducao>50.00</vDeducaoReducao></docDedRed>" +
+		"</documentos></vDedRed>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
 	}
 
-	// Check ISS = 1200 * 2% = 24
-	if !strings.Contains(result.XML, "<vISS>24.00</vISS>") {
-		t.Error("expected vISS element with value 24.00")
+	// The documents replace the deduction value and percentage
+	if strings.Contains(result.XML, "<vDR>") || strings.Contains(result.XML, "<pDR>") {
+		t.Errorf("expected no vDR or pDR elements, got:\n%s", result.XML)
 	}
 }
 
-// TestDPSBuilder_BuildValues_MEI_NoISS tests XML generation for MEI (0% ISS rate).
-func TestDPSBuilder_BuildValues_MEI_NoISS(t *testing.T) {
+// TestDPSBuilder_BuildValues_WithFederalTaxes tests XML generation with federal taxes (tribFed).
+func TestDPSBuilder_BuildValues_WithFederalTaxes(t *testing.T) {
 	config := createBasicDPSConfig()
 	config.Values = DPSValues{
-		ServiceValue:          1000.00,
-		UnconditionalDiscount: 50.00,
-		ISSRate:               0, // MEI
+		ServiceValue: 10000.00,
+		ISSRate:      2.00,
+		FederalTaxes: &DPSFederalTaxes{
+			PisCofins: &DPSPisCofins{
+				CST:          "01",
+				TaxBase:      10000.00,
+				PISRate:      0.65,
+				COFINSRate:   3.00,
+				PISAmount:    65.00,
+				COFINSAmount: 300.00,
+				Withheld:     true,
+			},
+			IRRFWithheld: 150.00,
+			CSLLWithheld: 100.00,
+		},
 	}
 
 	builder := NewDPSBuilder(config)
@@ -219,33 +204,40 @@ func TestDPSBuilder_BuildValues_MEI_NoISS(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Check tax base is still calculated correctly
-	if !strings.Contains(result.XML, "<vBCCalc>950.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with value 950.00")
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "</tribMun><tribFed><piscofins><CST>01</CST><vBCPisCofins>10000.00</vBCPisCofins>" +
+		"<pAliqPis>0.65</pAliqPis><pAliqCofins>3.00</pAliqCofins><vPis>65.00</vPis><vCofins>300.00</vCofins>" +
+		"<tpRetPisCofins>1</tpRetPisCofins></piscofins>" +
+		"<vRetIRRF>150.00</vRetIRRF><vRetCSLL>100.00</vRetCSLL></tribFed><totTrib>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
 	}
 
-	// Check ISS rate is 0
-	if !strings.Contains(result.XML, "<pAliq>0.00</pAliq>") {
-		t.Error("expected pAliq element with value 0.00")
+	// CP was not withheld
+	if strings.Contains(result.XML, "<vRetCP>") {
+		t.Errorf("expected no vRetCP element, got:\n%s", result.XML)
 	}
 
-	// Check ISS amount is 0
-	if !strings.Contains(result.XML, "<vISS>0.00</vISS>") {
-		t.Error("expected vISS element with value 0.00")
+	// Without federal taxes there is no tribFed group
+	config.Values.FederalTaxes = nil
+	result, err = NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(result.XML, "<tribFed>") {
+		t.Errorf("expected no tribFed element, got:\n%s", result.XML)
 	}
 }
 
-// TestDPSBuilder_BuildValues_PreCalculatedValues tests using pre-calculated values.
-func TestDPSBuilder_BuildValues_PreCalculatedValues(t *testing.T) {
+// TestDPSBuilder_BuildValues_Complete tests XML generation with all discounts and deductions.
+func TestDPSBuilder_BuildValues_Complete(t *testing.T) {
 	config := createBasicDPSConfig()
 	config.Values = DPSValues{
 		ServiceValue:          1500.00,
 		UnconditionalDiscount: 100.00,
+		ConditionalDiscount:   50.00,
 		Deductions:            200.00,
-		DeductionPercentage:   13.33,
-		TaxBase:               1200.00,
 		ISSRate:               2.00,
-		ISSAmount:             24.00,
 	}
 
 	builder := NewDPSBuilder(config)
@@ -254,23 +246,71 @@ func TestDPSBuilder_BuildValues_PreCalculatedValues(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Check pre-calculated values are used
-	if !strings.Contains(result.XML, "<pDR>13.33</pDR>") {
-		t.Error("expected pDR element with pre-calculated value 13.33")
+	// Check all values are present
+	if !strings.Contains(result.XML, "<vServ>1500.00</vServ>") {
+		t.Error("expected vServ element with value 1500.00")
+	}
+	if !strings.Contains(result.XML, "<vDescIncond>100.00</vDescIncond>") {
+		t.Error("expected vDescIncond element with value 100.00")
 	}
-	if !strings.Contains(result.XML, "<vBCCalc>1200.00</vBCCalc>") {
-		t.Error("expected vBCCalc element with pre-calculated value 1200.00")
+	if !strings.Contains(result.XML, "<vDescCond>50.00</vDescCond>") {
+		t.Error("expected vDescCond element with value 50.00")
 	}
-	if !strings.Contains(result.XML, "<vISS>24.00</vISS>") {
-		t.Error("expected vISS element with pre-calculated value 24.00")
+	if !strings.Contains(result.XML, "<vDR>200.00</vDR>") {
+		t.Error("expected vDR element with value 200.00")
 	}
 }
 
-// TestDPSBuilder_BuildValues_TotalTribSection tests the totTrib section generation.
-func TestDPSBuilder_BuildValues_TotalTribSection(t *testing.T) {
+// TestDPSBuilder_BuildValues_ISSRateByProvider tests that pAliq is only emitted when a rate applies to the provider.
+func TestDPSBuilder_BuildValues_ISSRateByProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		taxRegime     string
+		specialRegime int
+		issRate       float64
+		expectRate    bool
+	}{
+		{name: "ME/EPP with rate", taxRegime: TaxRegimeMEEPP, issRate: 2.00, expectRate: true},
+		{name: "ME/EPP without rate", taxRegime: TaxRegimeMEEPP},
+		{name: "MEI", taxRegime: TaxRegimeMEI, issRate: 2.00},
+		{name: "MEI without rate", taxRegime: TaxRegimeMEI},
+		{name: "autonomous professional", taxRegime: TaxRegimeAutonomo, specialRegime: SpecialRegimeAutonomousProfessional, issRate: 2.00},
+		{name: "professional society", taxRegime: TaxRegimeLucroPresumido, specialRegime: SpecialRegimeProfessionalSociety, issRate: 2.00},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Provider.TaxRegime = tt.taxRegime
+			config.Provider.SpecialRegime = tt.specialRegime
+			config.Values = DPSValues{
+				ServiceValue:          1000.00,
+				UnconditionalDiscount: 50.00,
+				ISSRate:               tt.issRate,
+				TotalTaxes:            &DPSTotalTaxes{SimplesRate: 6.00},
+			}
+
+			result, err := NewDPSBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			hasRate := strings.Contains(result.XML, "<pAliq>")
+			if hasRate != tt.expectRate {
+				t.Errorf("expected pAliq present = %v, got:\n%s", tt.expectRate, result.XML)
+			}
+		})
+	}
+}
+
+// TestDPSBuilder_BuildValues_PreCalculatedDeductionPercentage tests using a pre-calculated pDR.
+func TestDPSBuilder_BuildValues_PreCalculatedDeductionPercentage(t *testing.T) {
 	config := createBasicDPSConfig()
 	config.Values = DPSValues{
-		ServiceValue: 1000.00,
+		ServiceValue:        1500.00,
+		Deductions:          200.00,
+		DeductionPercentage: 13.34,
+		ISSRate:             2.00,
 	}
 
 	builder := NewDPSBuilder(config)
@@ -279,21 +319,184 @@ func TestDPSBuilder_BuildValues_TotalTribSection(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Check totTrib section is present
-	if !strings.Contains(result.XML, "<totTrib>") {
-		t.Error("expected totTrib element")
+	if !strings.Contains(result.XML, "<pDR>13.34</pDR>") {
+		t.Error("expected pDR element with pre-calculated value 13.34")
 	}
-	if !strings.Contains(result.XML, "<indTotTrib>0</indTotTrib>") {
-		t.Error("expected indTotTrib element with value 0")
+}
+
+// TestDPSBuilder_BuildValues_MunicipalTax tests the municipal tax (tribMun) element.
+func TestDPSBuilder_BuildValues_MunicipalTax(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(values *DPSValues)
+		expected string
+	}{
+		{
+			name: "withheld by the taker",
+			modify: func(values *DPSValues) {
+				values.ISSWithholding = ISSWithheldByTaker
+			},
+			expected: "<tribMun><tribISSQN>1</tribISSQN><tpRetISSQN>2</tpRetISSQN><pAliq>2.00</pAliq></tribMun>",
+		},
+		{
+			name: "export",
+			modify: func(values *DPSValues) {
+				values.ISSTaxation = ISSTaxationExport
+				values.ResultCountryCode = "US"
+			},
+			expected: "<tribMun><tribISSQN>3</tribISSQN><cPaisResult>US</cPaisResult><tpRetISSQN>1</tpRetISSQN></tribMun>",
+		},
+		{
+			name: "immunity",
+			modify: func(values *DPSValues) {
+				values.ISSTaxation = ISSTaxationImmunity
+				values.ImmunityType = 3
+				values.ResultCountryCode = "US"
+			},
+			expected: "<tribMun><tribISSQN>2</tribISSQN><tpImunidade>3</tpImunidade><tpRetISSQN>1</tpRetISSQN></tribMun>",
+		},
+		{
+			name: "suspended exigibility",
+			modify: func(values *DPSValues) {
+				values.ISSSuspension = &DPSISSSuspension{Type: 1, ProcessNumber: "000123456789012345678901234567"}
+			},
+			expected: "<tribMun><tribISSQN>1</tribISSQN>" +
+				"<exigSusp><tpSusp>1</tpSusp><nProcesso>000123456789012345678901234567</nProcesso></exigSusp>" +
+				"<tpRetISSQN>1</tpRetISSQN><pAliq>2.00</pAliq></tribMun>",
+		},
+		{
+			name: "benefit with reduction percentage",
+			modify: func(values *DPSValues) {
+				values.MunicipalBenefit = &DPSMunicipalBenefit{Number: "35503080400001", ReductionPercentage: 40}
+			},
+			expected: "<tribMun><tribISSQN>1</tribISSQN><BM><nBM>35503080400001</nBM><pRedBCBM>40.00</pRedBCBM></BM>" +
+				"<tpRetISSQN>1</tpRetISSQN><pAliq>2.00</pAliq></tribMun>",
+		},
+		{
+			name: "benefit with reduction value",
+			modify: func(values *DPSValues) {
+				values.MunicipalBenefit = &DPSMunicipalBenefit{Number: "35503080400001", ReductionValue: 250}
+			},
+			expected: "<BM><nBM>35503080400001</nBM><vRedBCBM>250.00</vRedBCBM></BM>",
+		},
+		{
+			name: "benefit without reduction",
+			modify: func(values *DPSValues) {
+				values.MunicipalBenefit = &DPSMunicipalBenefit{Number: "35503080400001"}
+			},
+			expected: "<BM><nBM>35503080400001</nBM></BM>",
+		},
 	}
-	if !strings.Contains(result.XML, "<pTotTribFed>0.00</pTotTribFed>") {
-		t.Error("expected pTotTribFed element with value 0.00")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Provider.TaxRegime = TaxRegimeMEEPP
+			config.Values = DPSValues{ServiceValue: 1000.00, ISSRate: 2.00, TotalTaxes: &DPSTotalTaxes{SimplesRate: 6.00}}
+			tt.modify(&config.Values)
+
+			result, err := NewDPSBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			compact := interElementSpace.ReplaceAllString(result.XML, "><")
+			if !strings.Contains(compact, tt.expected) {
+				t.Errorf("expected XML to contain %q, got:\n%s", tt.expected, result.XML)
+			}
+		})
 	}
-	if !strings.Contains(result.XML, "<pTotTribEst>0.00</pTotTribEst>") {
-		t.Error("expected pTotTribEst element with value 0.00")
+}
+
+// TestDPSBuilder_BuildValues_TotalTribSection tests the approximate tax burden (totTrib) element.
+func TestDPSBuilder_BuildValues_TotalTribSection(t *testing.T) {
+	tests := []struct {
+		name       string
+		taxRegime  string
+		totalTaxes *DPSTotalTaxes
+		expected   string
+		expectErr  bool
+	}{
+		{
+			name:       "not informed",
+			taxRegime:  TaxRegimeMEI,
+			totalTaxes: nil,
+			expected:   "</tribMun><totTrib><indTotTrib>0</indTotTrib></totTrib></trib>",
+		},
+		{
+			name:       "amounts",
+			taxRegime:  TaxRegimeLucroPresumido,
+			totalTaxes: &DPSTotalTaxes{Amounts: &DPSTaxBurden{Federal: 134.50, Municipal: 29.00}},
+			expected: "<totTrib><vTotTrib><vTotTribFed>134.50</vTotTribFed><vTotTribEst>0.00</vTotTribEst>" +
+				"<vTotTribMun>29.00</vTotTribMun></vTotTrib></totTrib>",
+		},
+		{
+			name:       "percentages",
+			taxRegime:  TaxRegimeLucroReal,
+			totalTaxes: &DPSTotalTaxes{Percentages: &DPSTaxBurden{Federal: 13.45, Municipal: 2.90}},
+			expected: "<totTrib><pTotTrib><pTotTribFed>13.45</pTotTribFed><pTotTribEst>0.00</pTotTribEst>" +
+				"<pTotTribMun>2.90</pTotTribMun></pTotTrib></totTrib>",
+		},
+		{
+			name:       "empty",
+			taxRegime:  TaxRegimeLucroPresumido,
+			totalTaxes: &DPSTotalTaxes{},
+			expected:   "<totTrib><indTotTrib>0</indTotTrib></totTrib>",
+		},
+		{
+			name:      "ME/EPP Simples Nacional rate takes precedence",
+			taxRegime: TaxRegimeMEEPP,
+			totalTaxes: &DPSTotalTaxes{
+				Amounts:     &DPSTaxBurden{Federal: 134.50},
+				SimplesRate: 6.00,
+			},
+			expected: "<totTrib><pTotTribSN>6.00</pTotTribSN></totTrib>",
+		},
+		{
+			name:       "ME/EPP without tax burden",
+			taxRegime:  TaxRegimeMEEPP,
+			totalTaxes: nil,
+			expectErr:  true,
+		},
+		{
+			name:       "MEI Simples Nacional rate is not informed",
+			taxRegime:  TaxRegimeMEI,
+			totalTaxes: &DPSTotalTaxes{SimplesRate: 6.00},
+			expected:   "<totTrib><indTotTrib>0</indTotTrib></totTrib>",
+		},
+		{
+			name:      "MEI Simples Nacional rate falls back to amounts",
+			taxRegime: TaxRegimeMEI,
+			totalTaxes: &DPSTotalTaxes{
+				Amounts:     &DPSTaxBurden{Federal: 134.50},
+				SimplesRate: 6.00,
+			},
+			expected: "<totTrib><vTotTrib><vTotTribFed>134.50</vTotTribFed>",
+		},
 	}
-	if !strings.Contains(result.XML, "<pTotTribMun>0.00</pTotTribMun>") {
-		t.Error("expected pTotTribMun element with value 0.00")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Provider.TaxRegime = tt.taxRegime
+			config.Values = DPSValues{ServiceValue: 1000.00, TotalTaxes: tt.totalTaxes}
+
+			result, err := NewDPSBuilder(config).Build()
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			compact := interElementSpace.ReplaceAllString(result.XML, "><")
+			if !strings.Contains(compact, tt.expected) {
+				t.Errorf("expected XML to contain %q, got:\n%s", tt.expected, result.XML)
+			}
+		})
 	}
 }
 
@@ -315,7 +518,7 @@ func TestDPSBuilder_BuildValues_XMLStructure(t *testing.T) {
 	}
 
 	// Check XML structure elements are in correct order
-	// valores should contain: vServPrest, vDedRed (optional), trib, totTrib
+	// valores should contain: vServPrest, vDedRed (optional), trib (with totTrib)
 	valoresIdx := strings.Index(result.XML, "<valores>")
 	vServPrestIdx := strings.Index(result.XML, "<vServPrest>")
 	vDedRedIdx := strings.Index(result.XML, "<vDedRed>")
@@ -339,6 +542,35 @@ func TestDPSBuilder_BuildValues_XMLStructure(t *testing.T) {
 	}
 }
 
+// TestDPSBuilder_BuildService_MunicipalTaxCode tests the optional cTribMun element.
+func TestDPSBuilder_BuildService_MunicipalTaxCode(t *testing.T) {
+	tests := []struct {
+		name             string
+		municipalTaxCode string
+		expected         string
+	}{
+		{name: "with municipal tax code", municipalTaxCode: "001", expected: "<cTribNac>123456</cTribNac>\n        <cTribMun>001</cTribMun>"},
+		{name: "without municipal tax code", municipalTaxCode: "", expected: "<cTribNac>123456</cTribNac>\n        <xDescServ>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Service.MunicipalTaxCode = tt.municipalTaxCode
+			config.Values = DPSValues{ServiceValue: 1000.00}
+
+			result, err := NewDPSBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.Contains(result.XML, tt.expected) {
+				t.Errorf("expected cServ to contain %q, got:\n%s", tt.expected, result.XML)
+			}
+		})
+	}
+}
+
 // createBasicDPSConfig creates a basic DPS configuration for testing.
 func createBasicDPSConfig() DPSConfig {
 	return DPSConfig{
@@ -350,7 +582,6 @@ func createBasicDPSConfig() DPSConfig {
 		CompetenceDate:     time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		EmitterType:        1,
 		MunicipalityCode:   "3550308", // Sao Paulo
-		Substitution:       2,
 		Provider: DPSProvider{
 			CNPJ:      "12345678000190",
 			Name:      "Test Provider Ltda",
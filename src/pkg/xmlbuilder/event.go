@@ -0,0 +1,528 @@
+package xmlbuilder
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event type codes (tipo de evento) supported by the pedRegEvento builder.
+const (
+	// EventTypeCancellation is the NFS-e cancellation event (e101101).
+	EventTypeCancellation = "e101101"
+
+	// EventTypeCancellationBySubstitution is the cancellation by substitution event (e105102).
+	EventTypeCancellationBySubstitution = "e105102"
+
+	// EventTypeProviderConfirmation is the provider confirmation manifestation (e202201).
+	EventTypeProviderConfirmation = "e202201"
+
+	// EventTypeTakerConfirmation is the taker confirmation manifestation (e203202).
+	EventTypeTakerConfirmation = "e203202"
+
+	// EventTypeIntermediaryConfirmation is the intermediary confirmation manifestation (e204203).
+	EventTypeIntermediaryConfirmation = "e204203"
+
+	// EventTypeProviderRejection is the provider rejection manifestation (e202205).
+	EventTypeProviderRejection = "e202205"
+
+	// EventTypeTakerRejection is the taker rejection manifestation (e203206).
+	EventTypeTakerRejection = "e203206"
+
+	// EventTypeIntermediaryRejection is the intermediary rejection manifestation (e204207).
+	EventTypeIntermediaryRejection = "e204207"
+
+	// EventTypeRejectionAnnulment is the annulment of a rejection manifestation (e205208).
+	EventTypeRejectionAnnulment = "e205208"
+)
+
+// Cancellation reason codes (cMotivo - TSCodJustCanc).
+const (
+	// CancellationReasonEmissionError indicates an error in the emission (1).
+	CancellationReasonEmissionError = 1
+
+	// CancellationReasonServiceNotProvided indicates the service was not provided (2).
+	CancellationReasonServiceNotProvided = 2
+
+	// CancellationReasonOther indicates any other reason (9).
+	CancellationReasonOther = 9
+)
+
+// Rejection reason codes (cMotivo - TSCodMotivoRejeicao).
+const (
+	// RejectionReasonDuplicate indicates a duplicated NFS-e (1).
+	RejectionReasonDuplicate = 1
+
+	// RejectionReasonIssuedByTaker indicates the NFS-e was already issued by the taker (2).
+	RejectionReasonIssuedByTaker = 2
+
+	// RejectionReasonNoTaxableEvent indicates the taxable event did not occur (3).
+	RejectionReasonNoTaxableEvent = 3
+
+	// RejectionReasonTaxLiability indicates an error in the tax liability (4).
+	RejectionReasonTaxLiability = 4
+
+	// RejectionReasonValues indicates an error in values, service or taxable event date (5).
+	RejectionReasonValues = 5
+
+	// RejectionReasonOther indicates any other reason (9).
+	RejectionReasonOther = 9
+)
+
+// Reason text (xMotivo - TSMotivo) length limits.
+const (
+	// EventReasonMinLength is the minimum length of the reason text.
+	EventReasonMinLength = 15
+
+	// EventReasonMaxLength is the maximum length of the reason text.
+	EventReasonMaxLength = 255
+)
+
+// eventDescriptions maps each event type to its fixed xDesc value.
+var eventDescriptions = map[string]string{
+	EventTypeCancellation:               "Cancelamento de NFS-e",
+	EventTypeCancellationBySubstitution: "Cancelamento de NFS-e por Substituição",
+	EventTypeProviderConfirmation:       "Manifestação de NFS-e - Confirmação do Prestador",
+	EventTypeTakerConfirmation:          "Manifestação de NFS-e - Confirmação do Tomador",
+	EventTypeIntermediaryConfirmation:   "Manifestação de NFS-e - Confirmação do Intermediário",
+	EventTypeProviderRejection:          "Manifestação de NFS-e - Rejeição do Prestador",
+	EventTypeTakerRejection:             "Manifestação de NFS-e - Rejeição do Tomador",
+	EventTypeIntermediaryRejection:      "Manifestação de NFS-e - Rejeição do Intermediário",
+	EventTypeRejectionAnnulment:         "Manifestação de NFS-e - Anulação da Rejeição",
+}
+
+// Event build error types for specific error handling.
+var (
+	// ErrEventInvalidEnvironment indicates that tpAmb is neither production (1) nor homologation (2).
+	ErrEventInvalidEnvironment = errors.New("environment (tpAmb) must be 1 or 2")
+
+	// ErrEventMissingAccessKey indicates that the NFS-e access key was not provided.
+	ErrEventMissingAccessKey = errors.New("access key (chNFSe) is required")
+
+	// ErrEventInvalidAuthor indicates that exactly one of CNPJAutor/CPFAutor was not provided.
+	ErrEventInvalidAuthor = errors.New("exactly one of author CNPJ or CPF is required")
+
+	// ErrEventMissingReplacementKey indicates that the substitute NFS-e access key was not provided.
+	ErrEventMissingReplacementKey = errors.New("substitute NFS-e access key (chSubstituta) is required")
+
+	// ErrEventMissingGroup indicates that no event group was configured.
+	ErrEventMissingGroup = errors.New("event group is required")
+
+	// ErrEventInvalidReasonCode indicates an unsupported cMotivo value.
+	ErrEventInvalidReasonCode = errors.New("invalid event reason code")
+
+	// ErrEventInvalidReason indicates that xMotivo is outside the allowed length.
+	ErrEventInvalidReason = errors.New("event reason must be between 15 and 255 characters")
+
+	// ErrEventUnsupportedType indicates an event type that is not a manifestation event.
+	ErrEventUnsupportedType = errors.New("unsupported manifestation event type")
+
+	// ErrEventInvalidTaxAgentCPF indicates that CPFAgTrib is missing or malformed.
+	ErrEventInvalidTaxAgentCPF = errors.New("tax agent CPF (CPFAgTrib) must have 11 digits")
+
+	// ErrEventInvalidRejectionEventID indicates that idEvManifRej is missing or malformed.
+	ErrEventInvalidRejectionEventID = errors.New("rejection event ID (idEvManifRej) must have 59 digits")
+)
+
+// PedRegEventoConfig contains all parameters needed to build a pedRegEvento XML document.
+//
+// Layout v1.00 has no nPedRegEvento element: the request number is assigned by the
+// Sistema Nacional and only appears in the Id of the registered evento (EVT...).
+type PedRegEventoConfig struct {
+	// Environment: 1 = production, 2 = homologation
+	Environment int
+
+	// EventDateTime is the date/time of the event request (defaults to now if zero)
+	EventDateTime time.Time
+
+	// ApplicationVersion identifies the requesting application
+	ApplicationVersion string
+
+	// Author identification (mutually exclusive)
+	AuthorCNPJ string
+	AuthorCPF  string
+
+	// AccessKey is the NFS-e access key (chNFSe) the event refers to
+	AccessKey string
+
+	// Cancellation contains the e101101 event data
+	Cancellation *CancellationEvent
+
+	// SubstitutionCancellation contains the e105102 event data
+	SubstitutionCancellation *SubstitutionCancellationEvent
+
+	// Manifestation contains the data of a confirmation, rejection or
+	// rejection annulment event (e202201 through e205208)
+	Manifestation *ManifestationEvent
+}
+
+// CancellationEvent contains the data for an NFS-e cancellation event (e101101).
+type CancellationEvent struct {
+	// ReasonCode is the cancellation reason (cMotivo): 1, 2 or 9
+	ReasonCode int
+
+	// ReasonDescription is the free-text justification (xMotivo), 15-255 characters
+	ReasonDescription string
+}
+
+// SubstitutionCancellationEvent contains the data for a cancellation by substitution event (e105102).
+type SubstitutionCancellationEvent struct {
+	// ReasonCode is the substitution reason (cMotivo), as informed in the substitute DPS
+	ReasonCode string
+
+	// ReasonDescription is the optional free-text justification (xMotivo), 15-255 characters
+	ReasonDescription string
+
+	// ReplacementAccessKey is the access key of the substitute NFS-e (chSubstituta)
+	ReplacementAccessKey string
+}
+
+// ManifestationEvent contains the data for an NFS-e manifestation event.
+// Confirmations only carry xDesc; rejections carry cMotivo and an optional xMotivo;
+// the rejection annulment (e205208) carries CPFAgTrib, idEvManifRej and xMotivo.
+type ManifestationEvent struct {
+	// EventType is the manifestation event code (e.g. "e203202")
+	EventType string
+
+	// ReasonCode is the rejection reason (cMotivo): 1-5 or 9 (rejections only)
+	ReasonCode int
+
+	// ReasonDescription is the free-text justification (xMotivo), 15-255 characters.
+	// Optional for rejections, required for the rejection annulment.
+	ReasonDescription string
+
+	// TaxAgentCPF is the CPF of the municipal tax agent (CPFAgTrib, annulment only)
+	TaxAgentCPF string
+
+	// RejectionEventID is the Id of the rejection event being annulled (idEvManifRej, annulment only)
+	RejectionEventID string
+}
+
+// PedRegEventoBuildResult contains the result of building a pedRegEvento XML.
+type PedRegEventoBuildResult struct {
+	// ID is the generated infPedReg Id attribute
+	ID string
+
+	// EventType is the event code (e.g. "e101101")
+	EventType string
+
+	// XML is the complete pedRegEvento XML document as a string
+	XML string
+
+	// XMLBytes is the raw XML bytes
+	XMLBytes []byte
+}
+
+// PedRegEventoBuilder builds pedRegEvento XML documents according to the
+// Sistema Nacional NFS-e event specification.
+type PedRegEventoBuilder struct {
+	config PedRegEventoConfig
+}
+
+// NewPedRegEventoBuilder creates a new pedRegEvento builder with the given configuration.
+func NewPedRegEventoBuilder(config PedRegEventoConfig) *PedRegEventoBuilder {
+	return &PedRegEventoBuilder{config: config}
+}
+
+// Build generates the complete pedRegEvento XML document.
+func (b *PedRegEventoBuilder) Build() (*PedRegEventoBuildResult, error) {
+	// Set defaults
+	if b.config.EventDateTime.IsZero() {
+		b.config.EventDateTime = time.Now()
+	}
+	if b.config.ApplicationVersion == "" {
+		b.config.ApplicationVersion = "1.0.0"
+	}
+
+	if b.config.Environment != 1 && b.config.Environment != 2 {
+		return nil, ErrEventInvalidEnvironment
+	}
+
+	accessKey := strings.TrimSpace(b.config.AccessKey)
+	if accessKey == "" {
+		return nil, ErrEventMissingAccessKey
+	}
+
+	authorCNPJ := cleanTaxID(b.config.AuthorCNPJ)
+	authorCPF := cleanTaxID(b.config.AuthorCPF)
+	if (authorCNPJ == "") == (authorCPF == "") {
+		return nil, ErrEventInvalidAuthor
+	}
+
+	inf := infPedRegXML{
+		TpAmb:     b.config.Environment,
+		VerAplic:  b.config.ApplicationVersion,
+		DhEvento:  formatDateTime(b.config.EventDateTime),
+		CNPJAutor: authorCNPJ,
+		CPFAutor:  authorCPF,
+		ChNFSe:    accessKey,
+	}
+
+	var eventType string
+	switch {
+	case b.config.Cancellation != nil:
+		group, err := buildCancellationGroup(b.config.Cancellation)
+		if err != nil {
+			return nil, err
+		}
+		inf.E101101 = group
+		eventType = EventTypeCancellation
+	case b.config.SubstitutionCancellation != nil:
+		group, err := buildSubstitutionCancellationGroup(b.config.SubstitutionCancellation)
+		if err != nil {
+			return nil, err
+		}
+		inf.E105102 = group
+		eventType = EventTypeCancellationBySubstitution
+	case b.config.Manifestation != nil:
+		if err := applyManifestationGroup(&inf, b.config.Manifestation); err != nil {
+			return nil, err
+		}
+		eventType = b.config.Manifestation.EventType
+	default:
+		return nil, ErrEventMissingGroup
+	}
+
+	inf.ID = GeneratePedRegEventoID(accessKey, eventType)
+
+	ped := &pedRegEventoXML{
+		XMLNs:     "http://www.sped.fazenda.gov.br/nfse",
+		Versao:    "1.00",
+		InfPedReg: inf,
+	}
+
+	// Marshal to XML
+	xmlBytes, err := xml.MarshalIndent(ped, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pedRegEvento XML: %w", err)
+	}
+
+	// Add XML declaration
+	xmlStr := xml.Header + string(xmlBytes)
+
+	return &PedRegEventoBuildResult{
+		ID:        inf.ID,
+		EventType: eventType,
+		XML:       xmlStr,
+		XMLBytes:  []byte(xmlStr),
+	}, nil
+}
+
+// GeneratePedRegEventoID builds the infPedReg Id attribute.
+// Format: "PRE" + access key + event code digits (the "e" prefix is dropped).
+func GeneratePedRegEventoID(accessKey, eventType string) string {
+	return "PRE" + accessKey + strings.TrimPrefix(eventType, "e")
+}
+
+// buildCancellationGroup creates the e101101 event group.
+func buildCancellationGroup(event *CancellationEvent) (*e101101XML, error) {
+	switch event.ReasonCode {
+	case CancellationReasonEmissionError, CancellationReasonServiceNotProvided, CancellationReasonOther:
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrEventInvalidReasonCode, event.ReasonCode)
+	}
+
+	reason := sanitizeXMLText(strings.TrimSpace(event.ReasonDescription))
+	if n := utf8.RuneCountInString(reason); n < EventReasonMinLength || n > EventReasonMaxLength {
+		return nil, ErrEventInvalidReason
+	}
+
+	return &e101101XML{
+		XDesc:   eventDescriptions[EventTypeCancellation],
+		CMotivo: event.ReasonCode,
+		XMotivo: reason,
+	}, nil
+}
+
+// buildSubstitutionCancellationGroup creates the e105102 event group.
+func buildSubstitutionCancellationGroup(event *SubstitutionCancellationEvent) (*e105102XML, error) {
+	replacementKey := strings.TrimSpace(event.ReplacementAccessKey)
+	if replacementKey == "" {
+		return nil, ErrEventMissingReplacementKey
+	}
+
+	if !IsValidSubstitutionReasonCode(event.ReasonCode) {
+		return nil, fmt.Errorf("%w: %q", ErrEventInvalidReasonCode, event.ReasonCode)
+	}
+
+	group := &e105102XML{
+		XDesc:        eventDescriptions[EventTypeCancellationBySubstitution],
+		CMotivo:      event.ReasonCode,
+		ChSubstituta: replacementKey,
+	}
+
+	reason := sanitizeXMLText(strings.TrimSpace(event.ReasonDescription))
+	if reason != "" {
+		if n := utf8.RuneCountInString(reason); n < EventReasonMinLength || n > EventReasonMaxLength {
+			return nil, ErrEventInvalidReason
+		}
+		group.XMotivo = reason
+	}
+
+	return group, nil
+}
+
+// applyManifestationGroup creates the manifestation event group and sets it on infPedReg.
+func applyManifestationGroup(inf *infPedRegXML, event *ManifestationEvent) error {
+	desc := eventDescriptions[event.EventType]
+
+	switch event.EventType {
+	case EventTypeProviderConfirmation:
+		inf.E202201 = &confirmationXML{XDesc: desc}
+	case EventTypeTakerConfirmation:
+		inf.E203202 = &confirmationXML{XDesc: desc}
+	case EventTypeIntermediaryConfirmation:
+		inf.E204203 = &confirmationXML{XDesc: desc}
+	case EventTypeProviderRejection, EventTypeTakerRejection, EventTypeIntermediaryRejection:
+		group, err := buildRejectionGroup(desc, event)
+		if err != nil {
+			return err
+		}
+		switch event.EventType {
+		case EventTypeProviderRejection:
+			inf.E202205 = group
+		case EventTypeTakerRejection:
+			inf.E203206 = group
+		default:
+			inf.E204207 = group
+		}
+	case EventTypeRejectionAnnulment:
+		group, err := buildRejectionAnnulmentGroup(desc, event)
+		if err != nil {
+			return err
+		}
+		inf.E205208 = group
+	default:
+		return fmt.Errorf("%w: %q", ErrEventUnsupportedType, event.EventType)
+	}
+
+	return nil
+}
+
+// buildRejectionGroup creates a rejection manifestation event group (e202205, e203206 or e204207).
+func buildRejectionGroup(desc string, event *ManifestationEvent) (*rejectionXML, error) {
+	switch event.ReasonCode {
+	case RejectionReasonDuplicate, RejectionReasonIssuedByTaker, RejectionReasonNoTaxableEvent,
+		RejectionReasonTaxLiability, RejectionReasonValues, RejectionReasonOther:
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrEventInvalidReasonCode, event.ReasonCode)
+	}
+
+	group := &rejectionXML{
+		XDesc:   desc,
+		CMotivo: event.ReasonCode,
+	}
+
+	reason := sanitizeXMLText(strings.TrimSpace(event.ReasonDescription))
+	if reason != "" {
+		if n := utf8.RuneCountInString(reason); n < EventReasonMinLength || n > EventReasonMaxLength {
+			return nil, ErrEventInvalidReason
+		}
+		group.XMotivo = reason
+	}
+
+	return group, nil
+}
+
+// buildRejectionAnnulmentGroup creates the e205208 event group.
+func buildRejectionAnnulmentGroup(desc string, event *ManifestationEvent) (*rejectionAnnulmentXML, error) {
+	cpf := cleanTaxID(event.TaxAgentCPF)
+	if len(cpf) != 11 || !isDigits(cpf) {
+		return nil, ErrEventInvalidTaxAgentCPF
+	}
+
+	rejectionID := strings.TrimSpace(event.RejectionEventID)
+	if len(rejectionID) != 59 || !isDigits(rejectionID) {
+		return nil, ErrEventInvalidRejectionEventID
+	}
+
+	reason := sanitizeXMLText(strings.TrimSpace(event.ReasonDescription))
+	if n := utf8.RuneCountInString(reason); n < EventReasonMinLength || n > EventReasonMaxLength {
+		return nil, ErrEventInvalidReason
+	}
+
+	return &rejectionAnnulmentXML{
+		XDesc:        desc,
+		CPFAgTrib:    cpf,
+		IDEvManifRej: rejectionID,
+		XMotivo:      reason,
+	}, nil
+}
+
+// isDigits reports whether s consists only of ASCII digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// XML structure types for pedRegEvento marshaling
+
+type pedRegEventoXML struct {
+	XMLName   xml.Name     `xml:"pedRegEvento"`
+	XMLNs     string       `xml:"xmlns,attr"`
+	Versao    string       `xml:"versao,attr"`
+	InfPedReg infPedRegXML `xml:"infPedReg"`
+}
+
+type infPedRegXML struct {
+	ID        string      `xml:"Id,attr"`
+	TpAmb     int         `xml:"tpAmb"`
+	VerAplic  string      `xml:"verAplic"`
+	DhEvento  string      `xml:"dhEvento"`
+	CNPJAutor string      `xml:"CNPJAutor,omitempty"`
+	CPFAutor  string      `xml:"CPFAutor,omitempty"`
+	ChNFSe    string      `xml:"chNFSe"`
+	E101101   *e101101XML `xml:"e101101,omitempty"`
+	E105102   *e105102XML `xml:"e105102,omitempty"`
+
+	// Manifestation events
+	E202201 *confirmationXML       `xml:"e202201,omitempty"`
+	E203202 *confirmationXML       `xml:"e203202,omitempty"`
+	E204203 *confirmationXML       `xml:"e204203,omitempty"`
+	E202205 *rejectionXML          `xml:"e202205,omitempty"`
+	E203206 *rejectionXML          `xml:"e203206,omitempty"`
+	E204207 *rejectionXML          `xml:"e204207,omitempty"`
+	E205208 *rejectionAnnulmentXML `xml:"e205208,omitempty"`
+}
+
+// e101101XML represents the cancellation event group.
+type e101101XML struct {
+	XDesc   string `xml:"xDesc"`
+	CMotivo int    `xml:"cMotivo"`
+	XMotivo string `xml:"xMotivo"`
+}
+
+// e105102XML represents the cancellation by substitution event group.
+type e105102XML struct {
+	XDesc        string `xml:"xDesc"`
+	CMotivo      string `xml:"cMotivo"`
+	XMotivo      string `xml:"xMotivo,omitempty"`
+	ChSubstituta string `xml:"chSubstituta"`
+}
+
+// confirmationXML represents a confirmation manifestation event group (e202201, e203202, e204203).
+type confirmationXML struct {
+	XDesc string `xml:"xDesc"`
+}
+
+// rejectionXML represents a rejection manifestation event group (e202205, e203206, e204207).
+type rejectionXML struct {
+	XDesc   string `xml:"xDesc"`
+	CMotivo int    `xml:"cMotivo"`
+	XMotivo string `xml:"xMotivo,omitempty"`
+}
+
+// rejectionAnnulmentXML represents the rejection annulment event group (e205208).
+type rejectionAnnulmentXML struct {
+	XDesc        string `xml:"xDesc"`
+	CPFAgTrib    string `xml:"CPFAgTrib"`
+	IDEvManifRej string `xml:"idEvManifRej"`
+	XMotivo      string `xml:"xMotivo"`
+}
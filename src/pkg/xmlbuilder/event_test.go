@@ -0,0 +1,356 @@
+package xmlbuilder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPedRegEventoBuilder_Cancellation tests XML generation for the e101101 event.
+func TestPedRegEventoBuilder_Cancellation(t *testing.T) {
+	config := createBasicCancellationConfig()
+
+	result, err := NewPedRegEventoBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedID := "PRENFSe3550308202601081123456789012300000000000012310101101"
+	if result.ID != expectedID {
+		t.Errorf("expected ID %s, got %s", expectedID, result.ID)
+	}
+	if result.EventType != EventTypeCancellation {
+		t.Errorf("expected event type %s, got %s", EventTypeCancellation, result.EventType)
+	}
+
+	expected := []string{
+		`<pedRegEvento xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00">`,
+		`<infPedReg Id="` + expectedID + `">`,
+		"<tpAmb>2</tpAmb>",
+		"<verAplic>1.0.0</verAplic>",
+		"<dhEvento>2026-01-08T07:30:00-03:00</dhEvento>",
+		"<CNPJAutor>12345678000195</CNPJAutor>",
+		"<chNFSe>NFSe3550308202601081123456789012300000000000012310</chNFSe>",
+		"<xDesc>Cancelamento de NFS-e</xDesc>",
+		"<cMotivo>1</cMotivo>",
+		"<xMotivo>Erro no valor do servico informado</xMotivo>",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(result.XML, fragment) {
+			t.Errorf("expected XML to contain %q", fragment)
+		}
+	}
+
+	if strings.Contains(result.XML, "<CPFAutor>") {
+		t.Error("unexpected CPFAutor element")
+	}
+}
+
+// TestPedRegEventoBuilder_CPFAuthor tests that a CPF author is emitted as CPFAutor.
+func TestPedRegEventoBuilder_CPFAuthor(t *testing.T) {
+	config := createBasicCancellationConfig()
+	config.AuthorCNPJ = ""
+	config.AuthorCPF = "529.982.247-25"
+
+	result, err := NewPedRegEventoBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.XML, "<CPFAutor>52998224725</CPFAutor>") {
+		t.Error("expected CPFAutor element with cleaned CPF")
+	}
+}
+
+// TestPedRegEventoBuilder_SubstitutionCancellation tests XML generation for the e105102 event.
+func TestPedRegEventoBuilder_SubstitutionCancellation(t *testing.T) {
+	config := createBasicCancellationConfig()
+	config.Cancellation = nil
+	config.SubstitutionCancellation = &SubstitutionCancellationEvent{
+		ReasonCode:           SubstitutionReasonOther,
+		ReasonDescription:    "Correcao da descricao do servico",
+		ReplacementAccessKey: "NFSe3550308202601091123456789012300000000000012399",
+	}
+
+	result, err := NewPedRegEventoBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedID := "PRENFSe3550308202601081123456789012300000000000012310105102"
+	if result.ID != expectedID {
+		t.Errorf("expected ID %s, got %s", expectedID, result.ID)
+	}
+	if result.EventType != EventTypeCancellationBySubstitution {
+		t.Errorf("expected event type %s, got %s", EventTypeCancellationBySubstitution, result.EventType)
+	}
+
+	expected := []string{
+		"<e105102>",
+		"<xDesc>Cancelamento de NFS-e por Substituição</xDesc>",
+		"<cMotivo>99</cMotivo>",
+		"<xMotivo>Correcao da descricao do servico</xMotivo>",
+		"<chSubstituta>NFSe3550308202601091123456789012300000000000012399</chSubstituta>",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(result.XML, fragment) {
+			t.Errorf("expected XML to contain %q", fragment)
+		}
+	}
+
+	if strings.Contains(result.XML, "<e101101>") {
+		t.Error("unexpected e101101 element")
+	}
+
+	// xMotivo is optional for e105102
+	config.SubstitutionCancellation.ReasonDescription = ""
+	result, err = NewPedRegEventoBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error without reason: %v", err)
+	}
+	if strings.Contains(result.XML, "<xMotivo>") {
+		t.Error("expected xMotivo to be omitted when no reason is given")
+	}
+}
+
+// TestPedRegEventoBuilder_Manifestation tests XML generation for the manifestation events.
+func TestPedRegEventoBuilder_Manifestation(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    ManifestationEvent
+		expected []string
+		absent   []string
+	}{
+		{
+			name:  "taker confirmation",
+			event: ManifestationEvent{EventType: EventTypeTakerConfirmation},
+			expected: []string{
+				"<e203202>",
+				"<xDesc>Manifestação de NFS-e - Confirmação do Tomador</xDesc>",
+			},
+			absent: []string{"<cMotivo>", "<xMotivo>"},
+		},
+		{
+			name: "intermediary rejection",
+			event: ManifestationEvent{
+				EventType:         EventTypeIntermediaryRejection,
+				ReasonCode:        RejectionReasonValues,
+				ReasonDescription: "Valor do servico divergente do contratado",
+			},
+			expected: []string{
+				"<e204207>",
+				"<xDesc>Manifestação de NFS-e - Rejeição do Intermediário</xDesc>",
+				"<cMotivo>5</cMotivo>",
+				"<xMotivo>Valor do servico divergente do contratado</xMotivo>",
+			},
+		},
+		{
+			name: "provider rejection without reason",
+			event: ManifestationEvent{
+				EventType:  EventTypeProviderRejection,
+				ReasonCode: RejectionReasonDuplicate,
+			},
+			expected: []string{"<e202205>", "<cMotivo>1</cMotivo>"},
+			absent:   []string{"<xMotivo>"},
+		},
+		{
+			name: "rejection annulment",
+			event: ManifestationEvent{
+				EventType:         EventTypeRejectionAnnulment,
+				TaxAgentCPF:       "529.982.247-25",
+				RejectionEventID:  strings.Repeat("1", 59),
+				ReasonDescription: "Rejeicao registrada indevidamente",
+			},
+			expected: []string{
+				"<e205208>",
+				"<xDesc>Manifestação de NFS-e - Anulação da Rejeição</xDesc>",
+				"<CPFAgTrib>52998224725</CPFAgTrib>",
+				"<idEvManifRej>" + strings.Repeat("1", 59) + "</idEvManifRej>",
+				"<xMotivo>Rejeicao registrada indevidamente</xMotivo>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicCancellationConfig()
+			config.Cancellation = nil
+			event := tt.event
+			config.Manifestation = &event
+
+			result, err := NewPedRegEventoBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.EventType != tt.event.EventType {
+				t.Errorf("expected event type %s, got %s", tt.event.EventType, result.EventType)
+			}
+			expectedID := GeneratePedRegEventoID(config.AccessKey, tt.event.EventType)
+			if result.ID != expectedID {
+				t.Errorf("expected ID %s, got %s", expectedID, result.ID)
+			}
+
+			for _, fragment := range tt.expected {
+				if !strings.Contains(result.XML, fragment) {
+					t.Errorf("expected XML to contain %q", fragment)
+				}
+			}
+			for _, fragment := range tt.absent {
+				if strings.Contains(result.XML, fragment) {
+					t.Errorf("expected XML not to contain %q", fragment)
+				}
+			}
+		})
+	}
+}
+
+// TestPedRegEventoBuilder_Errors tests configuration errors.
+func TestPedRegEventoBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *PedRegEventoConfig)
+		wantErr error
+	}{
+		{
+			name:    "invalid environment",
+			modify:  func(c *PedRegEventoConfig) { c.Environment = 0 },
+			wantErr: ErrEventInvalidEnvironment,
+		},
+		{
+			name:    "missing access key",
+			modify:  func(c *PedRegEventoConfig) { c.AccessKey = "" },
+			wantErr: ErrEventMissingAccessKey,
+		},
+		{
+			name:    "missing author",
+			modify:  func(c *PedRegEventoConfig) { c.AuthorCNPJ = "" },
+			wantErr: ErrEventInvalidAuthor,
+		},
+		{
+			name:    "both authors",
+			modify:  func(c *PedRegEventoConfig) { c.AuthorCPF = "52998224725" },
+			wantErr: ErrEventInvalidAuthor,
+		},
+		{
+			name:    "missing event group",
+			modify:  func(c *PedRegEventoConfig) { c.Cancellation = nil },
+			wantErr: ErrEventMissingGroup,
+		},
+		{
+			name:    "invalid reason code",
+			modify:  func(c *PedRegEventoConfig) { c.Cancellation.ReasonCode = 3 },
+			wantErr: ErrEventInvalidReasonCode,
+		},
+		{
+			name:    "reason too short",
+			modify:  func(c *PedRegEventoConfig) { c.Cancellation.ReasonDescription = "curto" },
+			wantErr: ErrEventInvalidReason,
+		},
+		{
+			name:    "reason too long",
+			modify:  func(c *PedRegEventoConfig) { c.Cancellation.ReasonDescription = strings.Repeat("a", 256) },
+			wantErr: ErrEventInvalidReason,
+		},
+		{
+			name: "substitution without replacement key",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.SubstitutionCancellation = &SubstitutionCancellationEvent{ReasonCode: SubstitutionReasonOther}
+			},
+			wantErr: ErrEventMissingReplacementKey,
+		},
+		{
+			name: "substitution with invalid reason code",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.SubstitutionCancellation = &SubstitutionCancellationEvent{
+					ReasonCode:           "06",
+					ReplacementAccessKey: "NFSe3550308202601091123456789012300000000000012399",
+				}
+			},
+			wantErr: ErrEventInvalidReasonCode,
+		},
+		{
+			name: "unsupported manifestation type",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.Manifestation = &ManifestationEvent{EventType: EventTypeCancellation}
+			},
+			wantErr: ErrEventUnsupportedType,
+		},
+		{
+			name: "rejection with invalid reason code",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.Manifestation = &ManifestationEvent{EventType: EventTypeTakerRejection, ReasonCode: 6}
+			},
+			wantErr: ErrEventInvalidReasonCode,
+		},
+		{
+			name: "annulment without tax agent CPF",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.Manifestation = &ManifestationEvent{
+					EventType:         EventTypeRejectionAnnulment,
+					RejectionEventID:  strings.Repeat("1", 59),
+					ReasonDescription: "Rejeicao registrada indevidamente",
+				}
+			},
+			wantErr: ErrEventInvalidTaxAgentCPF,
+		},
+		{
+			name: "annulment with malformed rejection event ID",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.Manifestation = &ManifestationEvent{
+					EventType:         EventTypeRejectionAnnulment,
+					TaxAgentCPF:       "52998224725",
+					RejectionEventID:  "EVT123",
+					ReasonDescription: "Rejeicao registrada indevidamente",
+				}
+			},
+			wantErr: ErrEventInvalidRejectionEventID,
+		},
+		{
+			name: "annulment without reason",
+			modify: func(c *PedRegEventoConfig) {
+				c.Cancellation = nil
+				c.Manifestation = &ManifestationEvent{
+					EventType:        EventTypeRejectionAnnulment,
+					TaxAgentCPF:      "52998224725",
+					RejectionEventID: strings.Repeat("1", 59),
+				}
+			},
+			wantErr: ErrEventInvalidReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicCancellationConfig()
+			tt.modify(&config)
+
+			_, err := NewPedRegEventoBuilder(config).Build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// createBasicCancellationConfig creates a basic cancellation event configuration for testing.
+func createBasicCancellationConfig() PedRegEventoConfig {
+	return PedRegEventoConfig{
+		Environment:        2, // Homologation
+		EventDateTime:      time.Date(2026, 1, 8, 10, 30, 0, 0, time.UTC),
+		ApplicationVersion: "1.0.0",
+		AuthorCNPJ:         "12.345.678/0001-95",
+		AccessKey:          "NFSe3550308202601081123456789012300000000000012310",
+		Cancellation: &CancellationEvent{
+			ReasonCode:        CancellationReasonEmissionError,
+			ReasonDescription: "Erro no valor do servico informado",
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package xmlbuilder
+
+// PIS/COFINS withholding types (tpRetPisCofins).
+const (
+	// PisCofinsWithheld indicates that the taker withholds PIS/COFINS.
+	PisCofinsWithheld = 1
+
+	// PisCofinsNotWithheld indicates that PIS/COFINS are not withheld.
+	PisCofinsNotWithheld = 2
+)
+
+// DPSFederalTaxes contains the federal taxes of the service (tribFed group).
+// Amounts are emitted as given; use emission.ValueCalculator to calculate them.
+type DPSFederalTaxes struct {
+	PisCofins    *DPSPisCofins // piscofins (optional)
+	CPWithheld   float64       // vRetCP
+	IRRFWithheld float64       // vRetIRRF
+	CSLLWithheld float64       // vRetCSLL
+}
+
+// DPSPisCofins contains the PIS/COFINS taxes of the service (piscofins group).
+type DPSPisCofins struct {
+	CST          string  // CST - "00" to "09"
+	TaxBase      float64 // vBCPisCofins
+	PISRate      float64 // pAliqPis
+	COFINSRate   float64 // pAliqCofins
+	PISAmount    float64 // vPis
+	COFINSAmount float64 // vCofins
+	Withheld     bool    // tpRetPisCofins - 1 (withheld) or 2 (not withheld)
+}
+
+// buildFederalTaxes creates the federal taxes (tribFed) XML element.
+// Returns nil when the service has no federal taxes.
+func buildFederalTaxes(taxes *DPSFederalTaxes) *tribFedXML {
+	if taxes == nil {
+		return nil
+	}
+
+	tribFed := &tribFedXML{
+		VRetCP:   formatOptionalMoney(taxes.CPWithheld),
+		VRetIRRF: formatOptionalMoney(taxes.IRRFWithheld),
+		VRetCSLL: formatOptionalMoney(taxes.CSLLWithheld),
+	}
+
+	if pc := taxes.PisCofins; pc != nil {
+		retention := PisCofinsNotWithheld
+		if pc.Withheld {
+			retention = PisCofinsWithheld
+		}
+		tribFed.PisCofins = &pisCofinsXML{
+			CST:            pc.CST,
+			VBCPisCofins:   formatOptionalMoney(pc.TaxBase),
+			PAliqPis:       formatOptionalMoney(pc.PISRate),
+			PAliqCofins:    formatOptionalMoney(pc.COFINSRate),
+			VPis:           formatOptionalMoney(pc.PISAmount),
+			VCofins:        formatOptionalMoney(pc.COFINSAmount),
+			TpRetPisCofins: retention,
+		}
+	}
+
+	return tribFed
+}
+
+// tribFedXML represents the federal taxes (TCTribFederal).
+type tribFedXML struct {
+	PisCofins *pisCofinsXML `xml:"piscofins,omitempty"`
+	VRetCP    string        `xml:"vRetCP,omitempty"`
+	VRetIRRF  string        `xml:"vRetIRRF,omitempty"`
+	VRetCSLL  string        `xml:"vRetCSLL,omitempty"`
+}
+
+// pisCofinsXML represents the PIS/COFINS taxes (TCTribOutrosPisCofins).
+type pisCofinsXML struct {
+	CST            string `xml:"CST"`
+	VBCPisCofins   string `xml:"vBCPisCofins,omitempty"`
+	PAliqPis       string `xml:"pAliqPis,omitempty"`
+	PAliqCofins    string `xml:"pAliqCofins,omitempty"`
+	VPis           string `xml:"vPis,omitempty"`
+	VCofins        string `xml:"vCofins,omitempty"`
+	TpRetPisCofins int    `xml:"tpRetPisCofins"`
+}
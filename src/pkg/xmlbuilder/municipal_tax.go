@@ -0,0 +1,124 @@
+package xmlbuilder
+
+// ISS taxation types (tribISSQN).
+const (
+	// ISSTaxationTaxable is a taxable operation.
+	ISSTaxationTaxable = 1
+
+	// ISSTaxationImmunity is an operation with constitutional immunity.
+	ISSTaxationImmunity = 2
+
+	// ISSTaxationExport is a service export.
+	ISSTaxationExport = 3
+
+	// ISSTaxationNonIncidence is an operation outside the scope of the ISS.
+	ISSTaxationNonIncidence = 4
+)
+
+// ISS withholding types (tpRetISSQN).
+const (
+	// ISSNotWithheld indicates that the provider collects the ISS.
+	ISSNotWithheld = 1
+
+	// ISSWithheldByTaker indicates that the taker withholds the ISS.
+	ISSWithheldByTaker = 2
+
+	// ISSWithheldByIntermediary indicates that the intermediary withholds the ISS.
+	ISSWithheldByIntermediary = 3
+)
+
+// DPSISSSuspension contains the suspended exigibility of the ISS (exigSusp group).
+type DPSISSSuspension struct {
+	Type          int    // tpSusp - 1 (court decision) or 2 (administrative proceeding)
+	ProcessNumber string // nProcesso - 30 digits
+}
+
+// DPSMunicipalBenefit contains the municipal benefit of the service (BM group).
+// At most one of ReductionValue and ReductionPercentage is emitted.
+type DPSMunicipalBenefit struct {
+	Number              string  // nBM - 14 digits
+	ReductionValue      float64 // vRedBCBM
+	ReductionPercentage float64 // pRedBCBM
+}
+
+// buildMunicipalTax creates the municipal tax (tribMun) XML element.
+// The taxation and withholding default to a taxable operation not withheld.
+func buildMunicipalTax(values DPSValues, provider DPSProvider) tribMunXML {
+	taxation := values.ISSTaxation
+	if taxation == 0 {
+		taxation = ISSTaxationTaxable
+	}
+
+	withholding := values.ISSWithholding
+	if withholding == 0 {
+		withholding = ISSNotWithheld
+	}
+
+	tribMun := tribMunXML{
+		TribISSQN:  taxation,
+		TpRetISSQN: withholding,
+	}
+
+	switch taxation {
+	case ISSTaxationExport:
+		tribMun.CPaisResult = values.ResultCountryCode
+	case ISSTaxationImmunity:
+		tribMun.TpImunidade = &values.ImmunityType
+	}
+
+	if susp := values.ISSSuspension; susp != nil {
+		tribMun.ExigSusp = &exigSuspXML{
+			TpSusp:    susp.Type,
+			NProcesso: susp.ProcessNumber,
+		}
+	}
+
+	if bm := values.MunicipalBenefit; bm != nil {
+		tribMun.BM = &bmXML{
+			NBM:      bm.Number,
+			VRedBCBM: formatOptionalMoney(bm.ReductionValue),
+		}
+		if tribMun.BM.VRedBCBM == "" {
+			tribMun.BM.PRedBCBM = formatOptionalMoney(bm.ReductionPercentage)
+		}
+	}
+
+	if issRateApplies(taxation, provider) {
+		tribMun.PAliq = formatOptionalMoney(values.ISSRate)
+	}
+
+	return tribMun
+}
+
+// issRateApplies reports whether the DPS may inform the ISS rate (pAliq).
+// The rate only applies to taxable operations, and SEFIN rejects it for MEI
+// providers (E0600) and for providers under a special regime (E0604).
+func issRateApplies(taxation int, provider DPSProvider) bool {
+	return taxation == ISSTaxationTaxable &&
+		simplesNacionalOption(provider.TaxRegime) != SimplesNacionalMEI &&
+		provider.SpecialRegime == SpecialRegimeNone
+}
+
+// tribMunXML represents the municipal tax (TCTribMunicipal).
+type tribMunXML struct {
+	TribISSQN   int          `xml:"tribISSQN"`
+	CPaisResult string       `xml:"cPaisResult,omitempty"`
+	TpImunidade *int         `xml:"tpImunidade,omitempty"`
+	ExigSusp    *exigSuspXML `xml:"exigSusp,omitempty"`
+	BM          *bmXML       `xml:"BM,omitempty"`
+	TpRetISSQN  int          `xml:"tpRetISSQN"`
+	PAliq       string       `xml:"pAliq,omitempty"`
+}
+
+// exigSuspXML represents the suspended exigibility of the ISS (TCExigSuspensa).
+type exigSuspXML struct {
+	TpSusp    int    `xml:"tpSusp"`
+	NProcesso string `xml:"nProcesso"`
+}
+
+// bmXML represents the municipal benefit (TCBeneficioMunicipal).
+type bmXML struct {
+	NBM      string `xml:"nBM"`
+	VRedBCBM string `xml:"vRedBCBM,omitempty"`
+	PRedBCBM string `xml:"pRedBCBM,omitempty"`
+}
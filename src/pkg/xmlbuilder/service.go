@@ -0,0 +1,174 @@
+package xmlbuilder
+
+import (
+	"errors"
+	"strings"
+)
+
+// Service site error types for specific error handling.
+var (
+	// ErrConstructionMissingSite indicates that the construction site has neither a code (cObra) nor an address.
+	ErrConstructionMissingSite = errors.New("construction code (cObra) or address is required")
+
+	// ErrActivityEventMissingName indicates that the event description (xNome) was not provided.
+	ErrActivityEventMissingName = errors.New("event name (xNome) is required")
+
+	// ErrActivityEventMissingDates indicates that the event start (dtIni) or end (dtFim) date was not provided.
+	ErrActivityEventMissingDates = errors.New("event start and end dates (dtIni, dtFim) are required")
+
+	// ErrActivityEventMissingSite indicates that the event has neither an identification (idAtvEvt) nor an address.
+	ErrActivityEventMissingSite = errors.New("event identification (idAtvEvt) or address is required")
+)
+
+// buildForeignTrade creates the foreign trade (comExt) XML element.
+// Returns nil when the service is not a foreign trade operation.
+func buildForeignTrade(trade *DPSForeignTrade) *comExtXML {
+	if trade == nil {
+		return nil
+	}
+
+	mdic := 0
+	if trade.ShareWithMDIC {
+		mdic = 1
+	}
+
+	return &comExtXML{
+		MdPrestacao: trade.ServiceMode,
+		VincPrest:   trade.Relationship,
+		TpMoeda:     trade.CurrencyCode,
+		VServMoeda:  formatMoney(trade.ForeignValue),
+		MecAFComexP: trade.ProviderSupportMechanism,
+		MecAFComexT: trade.TakerSupportMechanism,
+		MovTempBens: trade.TemporaryGoods,
+		NDI:         strings.TrimSpace(trade.ImportDeclaration),
+		NRE:         strings.TrimSpace(trade.ExportRegistration),
+		Mdic:        mdic,
+	}
+}
+
+// buildConstruction creates the construction site (obra) XML element.
+// The code takes precedence over the address, since the schema accepts only one of them.
+// Returns nil when the service has no construction site.
+func buildConstruction(construction *DPSConstruction) (*obraXML, error) {
+	if construction == nil {
+		return nil, nil
+	}
+
+	obra := &obraXML{
+		InscImobFisc: strings.TrimSpace(construction.PropertyRegistration),
+	}
+
+	if code := strings.TrimSpace(construction.Code); code != "" {
+		obra.CObra = code
+	} else if construction.Address != nil {
+		obra.End = buildSiteAddress(construction.Address)
+	} else {
+		return nil, ErrConstructionMissingSite
+	}
+
+	return obra, nil
+}
+
+// buildEvent creates the event (atvEvento) XML element.
+// The identification takes precedence over the address, since the schema accepts only one of them.
+// Returns nil when the service has no event.
+func buildEvent(event *DPSEvent) (*atvEventoXML, error) {
+	if event == nil {
+		return nil, nil
+	}
+
+	name := sanitizeXMLText(strings.TrimSpace(event.Name))
+	if name == "" {
+		return nil, ErrActivityEventMissingName
+	}
+	if event.StartDate.IsZero() || event.EndDate.IsZero() {
+		return nil, ErrActivityEventMissingDates
+	}
+
+	atvEvento := &atvEventoXML{
+		XNome: name,
+		DtIni: formatDate(event.StartDate),
+		DtFim: formatDate(event.EndDate),
+	}
+
+	if id := strings.TrimSpace(event.ID); id != "" {
+		atvEvento.IdAtvEvt = id
+	} else if event.Address != nil {
+		atvEvento.End = buildSiteAddress(event.Address)
+	} else {
+		return nil, ErrActivityEventMissingSite
+	}
+
+	return atvEvento, nil
+}
+
+// buildSiteAddress creates the address (end) XML element of a construction site or
+// event (TCEnderObraEvento). National sites are identified by the CEP only and
+// foreign sites by endExt (cEndPost, xCidade, xEstProvReg), without the country.
+func buildSiteAddress(addr *AddressConfig) *endSiteXML {
+	end := &endSiteXML{
+		XLgr:    addr.Street,
+		Nro:     addr.Number,
+		XCpl:    addr.Complement,
+		XBairro: addr.Neighborhood,
+	}
+
+	if addr.IsForeign() {
+		end.EndExt = &endExtSimplesXML{
+			CEndPost:    addr.PostalCode,
+			XCidade:     addr.City,
+			XEstProvReg: addr.Region,
+		}
+	} else {
+		end.CEP = cleanPostalCode(addr.PostalCode)
+	}
+
+	return end
+}
+
+// comExtXML represents the foreign trade group (TCComExterior).
+type comExtXML struct {
+	MdPrestacao int    `xml:"mdPrestacao"`
+	VincPrest   int    `xml:"vincPrest"`
+	TpMoeda     string `xml:"tpMoeda"`
+	VServMoeda  string `xml:"vServMoeda"`
+	MecAFComexP string `xml:"mecAFComexP"`
+	MecAFComexT string `xml:"mecAFComexT"`
+	MovTempBens int    `xml:"movTempBens"`
+	NDI         string `xml:"nDI,omitempty"`
+	NRE         string `xml:"nRE,omitempty"`
+	Mdic        int    `xml:"mdic"`
+}
+
+// obraXML represents the construction site group (TCInfoObra).
+type obraXML struct {
+	InscImobFisc string      `xml:"inscImobFisc,omitempty"`
+	CObra        string      `xml:"cObra,omitempty"`
+	End          *endSiteXML `xml:"end,omitempty"`
+}
+
+// atvEventoXML represents the event group (TCAtvEvento).
+type atvEventoXML struct {
+	XNome    string      `xml:"xNome"`
+	DtIni    string      `xml:"dtIni"`
+	DtFim    string      `xml:"dtFim"`
+	IdAtvEvt string      `xml:"idAtvEvt,omitempty"`
+	End      *endSiteXML `xml:"end,omitempty"`
+}
+
+// endSiteXML represents the address of a construction site or event (TCEnderObraEvento).
+type endSiteXML struct {
+	CEP     string            `xml:"CEP,omitempty"`
+	EndExt  *endExtSimplesXML `xml:"endExt,omitempty"`
+	XLgr    string            `xml:"xLgr"`
+	Nro     string            `xml:"nro"`
+	XCpl    string            `xml:"xCpl,omitempty"`
+	XBairro string            `xml:"xBairro"`
+}
+
+// endExtSimplesXML identifies a foreign site address (TCEnderExtSimples).
+type endExtSimplesXML struct {
+	CEndPost    string `xml:"cEndPost"`
+	XCidade     string `xml:"xCidade"`
+	XEstProvReg string `xml:"xEstProvReg"`
+}
@@ -0,0 +1,208 @@
+package xmlbuilder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDPSBuilder_BuildServiceSite tests the construction site (obra) and event (atvEvento) groups.
+func TestDPSBuilder_BuildServiceSite(t *testing.T) {
+	siteAddress := &AddressConfig{
+		Street:       "Rua das Obras",
+		Number:       "100",
+		Neighborhood: "Centro",
+		PostalCode:   "01310-100",
+	}
+
+	tests := []struct {
+		name         string
+		construction *DPSConstruction
+		event        *DPSEvent
+		wantErr      error
+		contains     []string
+		notContains  []string
+	}{
+		{
+			name:         "construction identified by code",
+			construction: &DPSConstruction{Code: "900012345678", PropertyRegistration: "0123456789"},
+			contains: []string{
+				"</xDescServ></cServ><obra><inscImobFisc>0123456789</inscImobFisc><cObra>900012345678</cObra></obra></serv>",
+			},
+			notContains: []string{"<atvEvento>", "<end>"},
+		},
+		{
+			name:         "construction identified by national address",
+			construction: &DPSConstruction{Address: siteAddress},
+			contains: []string{
+				"<obra><end><CEP>01310100</CEP><xLgr>Rua das Obras</xLgr><nro>100</nro><xBairro>Centro</xBairro></end></obra>",
+			},
+			notContains: []string{"<cObra>", "<inscImobFisc>", "<endNac>"},
+		},
+		{
+			name: "construction identified by foreign address",
+			construction: &DPSConstruction{Address: &AddressConfig{
+				Street: "Calle Mayor", Number: "10", Neighborhood: "Centro",
+				PostalCode: "28013", City: "Madrid", Region: "Madrid", CountryCode: "ES",
+			}},
+			contains: []string{
+				"<obra><end><endExt><cEndPost>28013</cEndPost><xCidade>Madrid</xCidade><xEstProvReg>Madrid</xEstProvReg></endExt><xLgr>Calle Mayor</xLgr>",
+			},
+			notContains: []string{"<CEP>", "<cPais>"},
+		},
+		{
+			name: "event identified by municipal identification",
+			event: &DPSEvent{
+				Name:      "Feira de Tecnologia",
+				StartDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+				ID:        "EVT-2025-001",
+			},
+			contains: []string{
+				"</xDescServ></cServ><atvEvento><xNome>Feira de Tecnologia</xNome><dtIni>2025-03-10</dtIni><dtFim>2025-03-12</dtFim><idAtvEvt>EVT-2025-001</idAtvEvt></atvEvento></serv>",
+			},
+			notContains: []string{"<obra>"},
+		},
+		{
+			name: "event identified by address",
+			event: &DPSEvent{
+				Name:      "Show de Verao",
+				StartDate: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+				Address:   siteAddress,
+			},
+			contains: []string{
+				"<dtFim>2025-01-20</dtFim><end><CEP>01310100</CEP>",
+			},
+			notContains: []string{"<idAtvEvt>"},
+		},
+		{
+			name:         "construction without code or address",
+			construction: &DPSConstruction{PropertyRegistration: "0123456789"},
+			wantErr:      ErrConstructionMissingSite,
+		},
+		{
+			name: "event without dates",
+			event: &DPSEvent{
+				Name: "Show de Verao",
+				ID:   "EVT-1",
+			},
+			wantErr: ErrActivityEventMissingDates,
+		},
+		{
+			name: "event without identification or address",
+			event: &DPSEvent{
+				Name:      "Show de Verao",
+				StartDate: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: ErrActivityEventMissingSite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Service.Construction = tt.construction
+			config.Service.Event = tt.event
+			config.Values = DPSValues{ServiceValue: 1000.00}
+
+			result, err := NewDPSBuilder(config).Build()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Compare without the indentation between elements
+			compact := interElementSpace.ReplaceAllString(result.XML, "><")
+			for _, expected := range tt.contains {
+				if !strings.Contains(compact, expected) {
+					t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+				}
+			}
+			for _, unexpected := range tt.notContains {
+				if strings.Contains(result.XML, unexpected) {
+					t.Errorf("expected XML not to contain %q, got:\n%s", unexpected, result.XML)
+				}
+			}
+		})
+	}
+}
+
+// TestDPSBuilder_BuildForeignTrade tests the foreign trade (comExt) group and services performed abroad.
+func TestDPSBuilder_BuildForeignTrade(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 12500.00}
+	config.Service.CountryCode = "us"
+	config.Service.ForeignTrade = &DPSForeignTrade{
+		ServiceMode:              1,
+		Relationship:             0,
+		CurrencyCode:             "220",
+		ForeignValue:             2500,
+		ProviderSupportMechanism: "01",
+		TakerSupportMechanism:    "01",
+		TemporaryGoods:           3,
+		ExportRegistration:       "250012345678",
+		ShareWithMDIC:            true,
+	}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := []string{
+		"<locPrest><cPaisPrestacao>US</cPaisPrestacao></locPrest>",
+		"<comExt><mdPrestacao>1</mdPrestacao><vincPrest>0</vincPrest><tpMoeda>220</tpMoeda>" +
+			"<vServMoeda>2500.00</vServMoeda><mecAFComexP>01</mecAFComexP><mecAFComexT>01</mecAFComexT>" +
+			"<movTempBens>3</movTempBens><nRE>250012345678</nRE><mdic>1</mdic></comExt>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(compact, e) {
+			t.Errorf("expected XML to contain %q, got:\n%s", e, result.XML)
+		}
+	}
+	for _, unexpected := range []string{"<cLocPrestacao>", "<nDI>"} {
+		if strings.Contains(result.XML, unexpected) {
+			t.Errorf("expected XML not to contain %q, got:\n%s", unexpected, result.XML)
+		}
+	}
+
+	// Services performed in Brazil keep the municipality
+	config.Service.CountryCode = ""
+	config.Service.ForeignTrade = nil
+	result, err = NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.XML, "<cLocPrestacao>") || strings.Contains(result.XML, "<comExt>") {
+		t.Errorf("expected cLocPrestacao without comExt, got:\n%s", result.XML)
+	}
+}
+
+// TestDPSBuilder_BuildServiceCodes tests the NBS (cNBS) and municipal tax (cTribMun) codes of the service.
+func TestDPSBuilder_BuildServiceCodes(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Service.NBSCode = "1.0101.11.00"
+	config.Service.MunicipalTaxCode = "001"
+	config.Values = DPSValues{ServiceValue: 1000.00}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The schema orders cServ as cTribNac, cTribMun, xDescServ and cNBS
+	compact := interElementSpace.ReplaceAllString(result.XML, "><")
+	expected := "<cServ><cTribNac>123456</cTribNac><cTribMun>001</cTribMun><xDescServ>Test service</xDescServ><cNBS>101011100</cNBS></cServ>"
+	if !strings.Contains(compact, expected) {
+		t.Errorf("expected XML to contain %q, got:\n%s", expected, result.XML)
+	}
+}
@@ -0,0 +1,52 @@
+package xmlbuilder
+
+import "errors"
+
+// Substitution reason codes (cMotivo - TSCodJustSubst), shared by the DPS subst
+// group and the cancellation by substitution event (e105102).
+const (
+	// SubstitutionReasonSimplesExclusion indicates the NFS-e left the Simples Nacional (01).
+	SubstitutionReasonSimplesExclusion = "01"
+
+	// SubstitutionReasonSimplesInclusion indicates the NFS-e entered the Simples Nacional (02).
+	SubstitutionReasonSimplesInclusion = "02"
+
+	// SubstitutionReasonExemptionInclusion indicates a retroactive immunity/exemption inclusion (03).
+	SubstitutionReasonExemptionInclusion = "03"
+
+	// SubstitutionReasonExemptionExclusion indicates a retroactive immunity/exemption exclusion (04).
+	SubstitutionReasonExemptionExclusion = "04"
+
+	// SubstitutionReasonTakerRejection indicates the NFS-e was rejected by the taker or intermediary (05).
+	SubstitutionReasonTakerRejection = "05"
+
+	// SubstitutionReasonOther indicates any other reason (99).
+	SubstitutionReasonOther = "99"
+)
+
+// Substitution error types for specific error handling.
+var (
+	// ErrSubstitutionMissingAccessKey indicates that the replaced NFS-e access key was not provided.
+	ErrSubstitutionMissingAccessKey = errors.New("replaced NFS-e access key (chSubstda) is required")
+
+	// ErrSubstitutionInvalidReasonCode indicates an unsupported substitution cMotivo value.
+	ErrSubstitutionInvalidReasonCode = errors.New("invalid substitution reason code")
+
+	// ErrSubstitutionInvalidReason indicates that the substitution xMotivo is outside the allowed length.
+	ErrSubstitutionInvalidReason = errors.New("substitution reason must be between 15 and 255 characters")
+)
+
+// IsValidSubstitutionReasonCode reports whether code is an accepted substitution cMotivo.
+func IsValidSubstitutionReasonCode(code string) bool {
+	switch code {
+	case SubstitutionReasonSimplesExclusion,
+		SubstitutionReasonSimplesInclusion,
+		SubstitutionReasonExemptionInclusion,
+		SubstitutionReasonExemptionExclusion,
+		SubstitutionReasonTakerRejection,
+		SubstitutionReasonOther:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,125 @@
+package xmlbuilder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestDPSBuilder_NoSubstitution tests that the subst group is omitted for regular emissions.
+func TestDPSBuilder_NoSubstitution(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 1000.00}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(result.XML, "<subst>") {
+		t.Error("expected subst element to be omitted when not replacing an NFS-e")
+	}
+}
+
+// TestDPSBuilder_Substitution tests generation of the subst group and its position in infDPS.
+func TestDPSBuilder_Substitution(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 1000.00}
+	config.Substitution = &DPSSubstitution{
+		ReplacedAccessKey: "NFSe3550308202601081123456789012300000000000012310",
+		ReasonCode:        SubstitutionReasonOther,
+		ReasonDescription: "Correcao da descricao do servico",
+	}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"<chSubstda>NFSe3550308202601081123456789012300000000000012310</chSubstda>",
+		"<cMotivo>99</cMotivo>",
+		"<xMotivo>Correcao da descricao do servico</xMotivo>",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(result.XML, fragment) {
+			t.Errorf("expected XML to contain %q", fragment)
+		}
+	}
+
+	// subst must come after cLocEmi and before prest
+	cLocEmiIdx := strings.Index(result.XML, "<cLocEmi>")
+	substIdx := strings.Index(result.XML, "<subst>")
+	prestIdx := strings.Index(result.XML, "<prest>")
+	if substIdx == -1 || substIdx < cLocEmiIdx || substIdx > prestIdx {
+		t.Error("expected subst element between cLocEmi and prest")
+	}
+}
+
+// TestDPSBuilder_SubstitutionWithoutReason tests that xMotivo is optional.
+func TestDPSBuilder_SubstitutionWithoutReason(t *testing.T) {
+	config := createBasicDPSConfig()
+	config.Values = DPSValues{ServiceValue: 1000.00}
+	config.Substitution = &DPSSubstitution{
+		ReplacedAccessKey: "NFSe3550308202601081123456789012300000000000012310",
+		ReasonCode:        SubstitutionReasonSimplesInclusion,
+	}
+
+	result, err := NewDPSBuilder(config).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.XML, "<cMotivo>02</cMotivo>") {
+		t.Error("expected cMotivo 02")
+	}
+	if strings.Contains(result.XML, "<xMotivo>") {
+		t.Error("expected xMotivo to be omitted")
+	}
+}
+
+// TestDPSBuilder_SubstitutionErrors tests substitution configuration errors.
+func TestDPSBuilder_SubstitutionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		subst   DPSSubstitution
+		wantErr error
+	}{
+		{
+			name:    "missing access key",
+			subst:   DPSSubstitution{ReasonCode: SubstitutionReasonOther},
+			wantErr: ErrSubstitutionMissingAccessKey,
+		},
+		{
+			name: "invalid reason code",
+			subst: DPSSubstitution{
+				ReplacedAccessKey: "NFSe3550308202601081123456789012300000000000012310",
+				ReasonCode:        "9",
+			},
+			wantErr: ErrSubstitutionInvalidReasonCode,
+		},
+		{
+			name: "reason too short",
+			subst: DPSSubstitution{
+				ReplacedAccessKey: "NFSe3550308202601081123456789012300000000000012310",
+				ReasonCode:        SubstitutionReasonOther,
+				ReasonDescription: "curto",
+			},
+			wantErr: ErrSubstitutionInvalidReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Values = DPSValues{ServiceValue: 1000.00}
+			subst := tt.subst
+			config.Substitution = &subst
+
+			_, err := NewDPSBuilder(config).Build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
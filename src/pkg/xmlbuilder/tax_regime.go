@@ -0,0 +1,77 @@
+package xmlbuilder
+
+// Provider tax regimes accepted in DPSProvider.TaxRegime.
+const (
+	// TaxRegimeMEI is a Simples Nacional Microempreendedor Individual.
+	TaxRegimeMEI = "mei"
+
+	// TaxRegimeMEEPP is a Simples Nacional Microempresa or Empresa de Pequeno Porte.
+	TaxRegimeMEEPP = "me_epp"
+
+	// TaxRegimeLucroPresumido is a company taxed on presumed profit, outside the Simples Nacional.
+	TaxRegimeLucroPresumido = "lucro_presumido"
+
+	// TaxRegimeLucroReal is a company taxed on actual profit, outside the Simples Nacional.
+	TaxRegimeLucroReal = "lucro_real"
+
+	// TaxRegimeAutonomo is an individual (CPF) provider, outside the Simples Nacional.
+	TaxRegimeAutonomo = "autonomo"
+)
+
+// Simples Nacional options (opSimpNac).
+const (
+	// SimplesNacionalNotOptant indicates the provider is not a Simples Nacional optant.
+	SimplesNacionalNotOptant = 1
+
+	// SimplesNacionalMEI indicates the provider is a Microempreendedor Individual.
+	SimplesNacionalMEI = 2
+
+	// SimplesNacionalMEEPP indicates the provider is a Microempresa or Empresa de Pequeno Porte.
+	SimplesNacionalMEEPP = 3
+)
+
+// Special taxation regimes (regEspTrib).
+const (
+	SpecialRegimeNone                     = 0 // Nenhum
+	SpecialRegimeCooperative              = 1 // Ato Cooperado (Cooperativa)
+	SpecialRegimeEstimate                 = 2 // Estimativa
+	SpecialRegimeMunicipalMicroenterprise = 3 // Microempresa Municipal
+	SpecialRegimeNotary                   = 4 // Notário ou Registrador
+	SpecialRegimeAutonomousProfessional   = 5 // Profissional Autônomo
+	SpecialRegimeProfessionalSociety      = 6 // Sociedade de Profissionais
+)
+
+// simplesNacionalOption returns the Simples Nacional option (opSimpNac) of a tax regime.
+// Unknown regimes are treated as MEI, the most restrictive option.
+func simplesNacionalOption(taxRegime string) int {
+	switch taxRegime {
+	case TaxRegimeMEEPP:
+		return SimplesNacionalMEEPP
+	case TaxRegimeLucroPresumido, TaxRegimeLucroReal, TaxRegimeAutonomo:
+		return SimplesNacionalNotOptant
+	default:
+		return SimplesNacionalMEI
+	}
+}
+
+// buildTaxRegime creates the provider tax regime (regTrib) XML element.
+// The Simples Nacional calculation regime (regApTribSN) is only emitted for ME/EPP providers.
+func buildTaxRegime(provider DPSProvider) regTribXML {
+	regTrib := regTribXML{
+		OpSimpNac:  simplesNacionalOption(provider.TaxRegime),
+		RegEspTrib: provider.SpecialRegime,
+	}
+
+	if regTrib.OpSimpNac == SimplesNacionalMEEPP {
+		regTrib.RegApTribSN = provider.SimplesCalculationRegime
+	}
+
+	return regTrib
+}
+
+// regTribXML represents the provider tax regime (TCRegTrib).
+type regTribXML struct {
+	OpSimpNac   int `xml:"opSimpNac"`
+	RegApTribSN int `xml:"regApTribSN,omitempty"`
+	RegEspTrib  int `xml:"regEspTrib"`
+}
@@ -0,0 +1,56 @@
+package xmlbuilder
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDPSBuilder_BuildProvider_TaxRegime tests the provider tax regime (regTrib) element.
+func TestDPSBuilder_BuildProvider_TaxRegime(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider DPSProvider
+		expected string
+	}{
+		{
+			name:     "MEI",
+			provider: DPSProvider{TaxRegime: TaxRegimeMEI},
+			expected: "<regTrib><opSimpNac>2</opSimpNac><regEspTrib>0</regEspTrib></regTrib>",
+		},
+		{
+			name:     "ME/EPP with ISS outside the Simples Nacional",
+			provider: DPSProvider{TaxRegime: TaxRegimeMEEPP, SimplesCalculationRegime: 2},
+			expected: "<regTrib><opSimpNac>3</opSimpNac><regApTribSN>2</regApTribSN><regEspTrib>0</regEspTrib></regTrib>",
+		},
+		{
+			name:     "lucro presumido professional society",
+			provider: DPSProvider{TaxRegime: TaxRegimeLucroPresumido, SpecialRegime: SpecialRegimeProfessionalSociety},
+			expected: "<regTrib><opSimpNac>1</opSimpNac><regEspTrib>6</regEspTrib></regTrib>",
+		},
+		{
+			name:     "lucro real ignores the calculation regime",
+			provider: DPSProvider{TaxRegime: TaxRegimeLucroReal, SimplesCalculationRegime: 3},
+			expected: "<regTrib><opSimpNac>1</opSimpNac><regEspTrib>0</regEspTrib></regTrib>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createBasicDPSConfig()
+			config.Provider.TaxRegime = tt.provider.TaxRegime
+			config.Provider.SimplesCalculationRegime = tt.provider.SimplesCalculationRegime
+			config.Provider.SpecialRegime = tt.provider.SpecialRegime
+			config.Values = DPSValues{ServiceValue: 1000.00, TotalTaxes: &DPSTotalTaxes{SimplesRate: 6.00}}
+
+			result, err := NewDPSBuilder(config).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			compact := interElementSpace.ReplaceAllString(result.XML, "><")
+			if !strings.Contains(compact, tt.expected) {
+				t.Errorf("expected XML to contain %q, got:\n%s", tt.expected, result.XML)
+			}
+		})
+	}
+}
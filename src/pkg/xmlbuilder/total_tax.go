@@ -0,0 +1,75 @@
+package xmlbuilder
+
+import "fmt"
+
+// DPSTotalTaxes contains the approximate tax burden of the service (totTrib group),
+// informed to the taker as required by Lei 12.741/2012. Only one form is emitted:
+// SimplesRate for ME/EPP providers, then Amounts, then Percentages. Without any of
+// them the DPS declares that the tax burden is not informed (indTotTrib = 0).
+type DPSTotalTaxes struct {
+	Amounts     *DPSTaxBurden // vTotTrib
+	Percentages *DPSTaxBurden // pTotTrib
+	SimplesRate float64       // pTotTribSN - ME/EPP providers only
+}
+
+// DPSTaxBurden contains the approximate taxes by jurisdiction, as amounts or percentages.
+type DPSTaxBurden struct {
+	Federal   float64 // vTotTribFed or pTotTribFed
+	State     float64 // vTotTribEst or pTotTribEst
+	Municipal float64 // vTotTribMun or pTotTribMun
+}
+
+// buildTotalTaxes creates the approximate tax burden (totTrib) XML element for the
+// provider's tax regime. SEFIN rejects pTotTribSN from MEI providers (E0710), so their
+// Simples Nacional rate is ignored, and rejects indTotTrib from ME/EPP providers (E0712),
+// so they must inform the tax burden.
+func buildTotalTaxes(taxes *DPSTotalTaxes, taxRegime string) (totTribXML, error) {
+	option := simplesNacionalOption(taxRegime)
+
+	switch {
+	case taxes == nil:
+	case taxes.SimplesRate > 0 && option == SimplesNacionalMEEPP:
+		return totTribXML{PTotTribSN: formatMoney(taxes.SimplesRate)}, nil
+	case taxes.Amounts != nil:
+		return totTribXML{VTotTrib: &vTotTribXML{
+			VTotTribFed: formatMoney(taxes.Amounts.Federal),
+			VTotTribEst: formatMoney(taxes.Amounts.State),
+			VTotTribMun: formatMoney(taxes.Amounts.Municipal),
+		}}, nil
+	case taxes.Percentages != nil:
+		return totTribXML{PTotTrib: &pTotTribXML{
+			PTotTribFed: formatMoney(taxes.Percentages.Federal),
+			PTotTribEst: formatMoney(taxes.Percentages.State),
+			PTotTribMun: formatMoney(taxes.Percentages.Municipal),
+		}}, nil
+	}
+
+	if option == SimplesNacionalMEEPP {
+		return totTribXML{}, fmt.Errorf("ME/EPP providers must inform the approximate tax burden")
+	}
+
+	notInformed := 0
+	return totTribXML{IndTotTrib: &notInformed}, nil
+}
+
+// totTribXML represents the approximate tax burden (TCTribTotal), a choice of one element.
+type totTribXML struct {
+	VTotTrib   *vTotTribXML `xml:"vTotTrib,omitempty"`
+	PTotTrib   *pTotTribXML `xml:"pTotTrib,omitempty"`
+	IndTotTrib *int         `xml:"indTotTrib,omitempty"`
+	PTotTribSN string       `xml:"pTotTribSN,omitempty"`
+}
+
+// vTotTribXML represents the approximate tax amounts by jurisdiction (TCTribTotalMonet).
+type vTotTribXML struct {
+	VTotTribFed string `xml:"vTotTribFed"`
+	VTotTribEst string `xml:"vTotTribEst"`
+	VTotTribMun string `xml:"vTotTribMun"`
+}
+
+// pTotTribXML represents the approximate tax percentages by jurisdiction (TCTribTotalPercent).
+type pTotTribXML struct {
+	PTotTribFed string `xml:"pTotTribFed"`
+	PTotTribEst string `xml:"pTotTribEst"`
+	PTotTribMun string `xml:"pTotTribMun"`
+}